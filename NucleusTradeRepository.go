@@ -1,2929 +1,5489 @@
-package power
-
-import (
-	"context"
-	"database/sql"
-	"encoding/json"
-	"fmt"
-	"regexp"
-	"strings"
-	"time"
-
-	mssql "github.com/denisenkom/go-mssqldb"
-
-	"github.com/sede-x/RogerRogerAnomalyDetector/db/oracle"
-	"github.com/sede-x/RogerRogerAnomalyDetector/logger"
-	"github.com/sede-x/RogerRogerAnomalyDetector/models/common"
-	"github.com/sede-x/RogerRogerAnomalyDetector/models/nucleus"
-)
-
-const (
-	formulaPattern = ("\\[.+\\|.+\\|.+\\]")
-)
-
-func parseExecutionDateTime(executionDate string, executionTime string) (time.Time, error) {
-	executionDateString := strings.Split(executionDate, " ")[0]
-	executionTimeString := executionDateString + " " + executionTime
-	execTime, err := time.Parse("2006-01-02 03:04:05 PM", executionTimeString)
-	if err != nil {
-		execTime, err = time.Parse("01/02/2006 03:04:05 PM", executionTimeString)
-		if err != nil {
-			return time.Time{}, err
-		}
-	}
-	return execTime, err
-}
-
-func parseExecutionDate(executionDate string) (time.Time, error) {
-	execTime, err := time.Parse("2006-01-02", executionDate)
-	if err != nil {
-		execTime, err = time.Parse("01/02/2006", executionDate)
-		if err != nil {
-			return time.Time{}, err
-		}
-	}
-	return execTime, err
-}
-
-type NucleusTradeRepository struct {
-	nucleusDb         *sql.DB
-	machineLearningDb *sql.DB
-	logger            logger.Logger
-}
-
-func NewNucleusTradeRepository(nucleusDb *sql.DB, machineLearningDb *sql.DB, logger logger.Logger) *NucleusTradeRepository {
-	return &NucleusTradeRepository{
-		nucleusDb,
-		machineLearningDb,
-		logger,
-	}
-}
-
-func (repo *NucleusTradeRepository) GetNucPowerDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "GetNucPowerDealList")
-
-	rows, err := repo.nucleusDb.QueryContext(ctx, getNucPowerDealListQuery, sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime))
-	if err != nil {
-		logger.Debugln("error got when executing getNucPowerDealListQuery: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	var headerModels []*nucleus.NucleusTradeHeaderModel
-	headerModelsMap := make(map[int]*nucleus.NucleusTradeHeaderModel)
-	var dealKeys []int
-
-	for rows.Next() {
-		var headerModel nucleus.NucleusTradeHeaderModel
-
-		var contract, confirmFormat, hsHedgeKey, hasBroker,
-			broker, executionDate, executionTime, exoticFlag sql.NullString
-		var optionKey sql.NullInt32
-
-		if err := rows.Scan(&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection,
-			&headerModel.TransactionDate, &headerModel.CyCompanyKey, &headerModel.Company,
-			&headerModel.CompanyLongName, &headerModel.CompanyCode, &headerModel.LegalEntity,
-			&headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey, &contract,
-			&confirmFormat, &headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio,
-			&headerModel.Portfolio, &headerModel.UrTrader, &headerModel.TzTimeZone,
-			&hasBroker, &broker, &optionKey, &headerModel.CreatedBy,
-			&headerModel.CreatedAt, &headerModel.ModifiedBy, &headerModel.ModifiedAt,
-			&executionDate, &executionTime, &exoticFlag,
-		); err != nil {
-			logger.Debugln("error scanning rows: ", err)
-			return nil, err
-		}
-
-		if contract.Valid {
-			headerModel.Contract = contract.String
-		}
-
-		if confirmFormat.Valid {
-			headerModel.ConfirmFormat = confirmFormat.String
-		}
-
-		if hsHedgeKey.Valid {
-			headerModel.HsHedgeKey = hsHedgeKey.String
-		}
-
-		if hasBroker.Valid {
-			headerModel.HasBroker = hasBroker.String
-		}
-
-		if broker.Valid {
-			headerModel.Broker = broker.String
-		}
-
-		if optionKey.Valid {
-			headerModel.ExercisedOptionKey = int(optionKey.Int32)
-		}
-
-		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
-			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
-			if err != nil {
-				logger.Debugln("error parsing executionDate and executionTime: ", err)
-				return nil, err
-			}
-			headerModel.ExecutionTime = execTime
-		}
-
-		if exoticFlag.Valid {
-			headerModel.ExoticFlag = exoticFlag.String
-		}
-
-		headerModel.InteraffiliateFlag = "N"
-
-		dealKeys = append(dealKeys, headerModel.DealKey)
-		headerModelsMap[headerModel.DealKey] = &headerModel
-		headerModels = append(headerModels, &headerModel)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	if len(dealKeys) > 0 {
-		termModelsMap, err := repo.getNucPowerDealTradeTermModel(ctx, dealKeys)
-		if err != nil {
-			logger.Debugln("error getting getNucPowerDealTradeTermModel: ", err)
-			return nil, err
-		}
-
-		for index := range headerModelsMap {
-			headerModelsMap[index].Terms = termModelsMap[index]
-		}
-	}
-
-	return headerModels, nil
-}
-
-func (repo *NucleusTradeRepository) getNucPowerDealTradeTermModel(ctx context.Context, dealKeys []int) (map[int][]*nucleus.NucleusTradeTermModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "getNucPowerDealTradeTermModel")
-
-	if len(dealKeys) == 0 {
-		return nil, nil
-	}
-
-	termModels := make(map[int][]*nucleus.NucleusTradeTermModel)
-
-	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeys, []interface{}{}, "pv.pd_power_key")
-	if err != nil {
-		logger.Debugln("error in CreateInQueryInt: ", err)
-		return nil, err
-	}
-
-	query := getNucPowerTradeTermModelQuery(dealKeysQuery)
-	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
-	if err != nil {
-		logger.Debugln("error got when executing getNucPowerTradeTermModelQuery: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	formulaMap := make(map[int]int)
-	for rows.Next() {
-		var termModel nucleus.NucleusTradeTermModel
-
-		var powerKey int
-		var formula sql.NullString
-
-		if err := rows.Scan(
-			&powerKey, &termModel.VolSeq, &termModel.BegDate, &termModel.EndDate, &termModel.PriceType,
-			&termModel.FixedPrice, &termModel.Volume, &termModel.Pool1, &termModel.Product1,
-			&termModel.PointCode1, &formula, &termModel.HolidaySchedule,
-		); err != nil {
-			logger.Debugln("error when scanning rows: ", err)
-			return nil, err
-		}
-
-		if formula.Valid && formula.String != "" {
-			termModel.Formula1 = formula.String
-			formulaMap[powerKey] = termModel.VolSeq
-		}
-
-		termModels[powerKey] = append(termModels[powerKey], &termModel)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	indexModelsMap, err := repo.getNucPowerTradeIndexModel(ctx, formulaMap)
-	if err != nil {
-		logger.Debugln("error getting getNucPowerTradeIndexModel: ", err)
-		return nil, err
-	}
-
-	for dealKey, indexModels := range indexModelsMap {
-		for _, indexModel := range indexModels {
-			if termModels, ok := termModels[dealKey]; ok {
-				for _, termModel := range termModels {
-					if termModel.VolSeq == indexModel.VolSeq {
-						termModel.Indexes1 = append(termModel.Indexes1, indexModel)
-					}
-				}
-			}
-		}
-	}
-
-	return termModels, nil
-}
-
-func (repo *NucleusTradeRepository) getNucPowerTradeIndexModel(ctx context.Context, formulaMap map[int]int) (map[int][]*nucleus.NucleusTradeIndexModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "getNucPowerTradeIndexModel")
-
-	if len(formulaMap) == 0 {
-		return nil, nil
-	}
-
-	insNumResult := make(map[int][]*nucleus.NucleusTradeIndexModel)
-
-	insNumQuery, params, err := oracle.CreateMapIntWhereQuery(formulaMap, []interface{}{}, "pv_pd_power_key", "pv_volume_seq")
-	if err != nil {
-		logger.Debugln("error in CreateInQueryInt: ", err)
-		return nil, err
-	}
-
-	query := getNucPowerTradeIndexModelQuery(insNumQuery)
-
-	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
-	if err != nil {
-		logger.Debugln("error got when executing getNucPowerTradeIndexModelQuery: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var termIndex nucleus.NucleusTradeIndexModel
-		var dealKey int
-		if err := rows.Scan(
-			&dealKey, &termIndex.VolSeq, &termIndex.Publication,
-			&termIndex.PubIndex, &termIndex.Frequency,
-		); err != nil {
-			logger.Debugln("error when scanning rows: ", err)
-			return nil, err
-		}
-
-		insNumResult[dealKey] = append(insNumResult[dealKey], &termIndex)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	return insNumResult, nil
-}
-
-func (repo *NucleusTradeRepository) GetNucPowerSwapDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "GetNucPowerSwapDealList")
-
-	rows, err := repo.nucleusDb.QueryContext(ctx, getNucPowerSwapDealListQuery, sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime))
-	if err != nil {
-		logger.Debugln("error got when executing getNucPowerSwapDealListQuery: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	var headerModels []*nucleus.NucleusTradeHeaderModel
-	headerModelsMap := make(map[int]*nucleus.NucleusTradeHeaderModel)
-	initialTermModelsMap := make(map[int]*nucleus.NucleusTradeTermModel)
-
-	var dealKeys []int
-
-	for rows.Next() {
-		var headerModel nucleus.NucleusTradeHeaderModel
-		var pvModel nucleus.NucleusTradeTermModel
-		var idxModel, idxModel2 nucleus.NucleusTradeIndexModel
-
-		var interaffiliateFlag, contract, confirmFormat,
-			hsHedgeKey, ibPortfolio, ibUrTrader, hasBroker,
-			broker, executionDate, executionTime, exoticFlag sql.NullString
-		var totalQuantity, fixedPrice sql.NullFloat64
-		var ibPrtPortfolio, optionKey sql.NullInt32
-
-		var nonstdFlag string
-
-		var fixPiPbPublication, fixPiPubIndex, fixFrqFrequency sql.NullString
-
-		if err := rows.Scan(
-			&headerModel.DealKey, &headerModel.DealType, &totalQuantity, &headerModel.DnDirection,
-			&headerModel.TransactionDate, &headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName,
-			&headerModel.CompanyCode, &headerModel.LegalEntity, &headerModel.LegalEntityLongName,
-			&headerModel.CyLegalEntityKey, &interaffiliateFlag, &contract,
-			&confirmFormat, &headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio,
-			&headerModel.Portfolio, &headerModel.UrTrader, &ibPrtPortfolio,
-			&ibPortfolio, &ibUrTrader, &headerModel.TzTimeZone, &hasBroker,
-			&broker, &optionKey, &pvModel.Pool1, &pvModel.Product1,
-			&pvModel.Volume, &fixedPrice, &nonstdFlag, &idxModel.Publication,
-			&idxModel.PubIndex, &idxModel.Frequency, &fixPiPbPublication,
-			&fixPiPubIndex, &fixFrqFrequency, &headerModel.StartDate, &headerModel.EndDate,
-			&pvModel.HolidaySchedule, &headerModel.CreatedBy, &headerModel.CreatedAt, &headerModel.ModifiedBy,
-			&headerModel.ModifiedAt, &executionDate, &executionTime, &exoticFlag,
-		); err != nil {
-			logger.Debugln("error scanning rows: ", err)
-			return nil, err
-		}
-
-		if totalQuantity.Valid {
-			headerModel.TotalQuantity = totalQuantity.Float64
-		}
-
-		if interaffiliateFlag.Valid {
-			headerModel.InteraffiliateFlag = interaffiliateFlag.String
-		}
-
-		if contract.Valid {
-			headerModel.Contract = contract.String
-		}
-
-		if confirmFormat.Valid {
-			headerModel.ConfirmFormat = confirmFormat.String
-		}
-
-		if hsHedgeKey.Valid {
-			headerModel.HsHedgeKey = hsHedgeKey.String
-		}
-
-		if ibPrtPortfolio.Valid {
-			headerModel.IbPrtPortfolio = int(ibPrtPortfolio.Int32)
-		}
-
-		if ibPortfolio.Valid {
-			headerModel.IbPortfolio = ibPortfolio.String
-		}
-
-		if ibUrTrader.Valid {
-			headerModel.IbUrTrader = ibUrTrader.String
-		}
-
-		if hasBroker.Valid {
-			headerModel.HasBroker = hasBroker.String
-		}
-
-		if broker.Valid {
-			headerModel.Broker = broker.String
-		}
-
-		if optionKey.Valid {
-			headerModel.ExercisedOptionKey = int(optionKey.Int32)
-		}
-
-		if fixedPrice.Valid {
-			pvModel.FixedPrice = fixedPrice.Float64
-		}
-
-		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
-			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
-			if err != nil {
-				logger.Debugln("error parsing executionDate and executionTime: ", err)
-				return nil, err
-			}
-			headerModel.ExecutionTime = execTime
-		}
-
-		if exoticFlag.Valid {
-			headerModel.ExoticFlag = exoticFlag.String
-		}
-
-		if fixPiPbPublication.Valid && fixPiPubIndex.Valid && fixFrqFrequency.Valid {
-			idxModel2.Publication = fixPiPbPublication.String
-			idxModel2.PubIndex = fixPiPubIndex.String
-			idxModel2.Frequency = fixFrqFrequency.String
-			pvModel.Indexes2 = append(pvModel.Indexes2, &idxModel2)
-		}
-
-		pvModel.BegDate = headerModel.StartDate
-		pvModel.EndDate = headerModel.EndDate
-		pvModel.Indexes1 = append(pvModel.Indexes1, &idxModel)
-
-		if nonstdFlag == "N" {
-			pvModel.VolSeq = 0
-			headerModel.Terms = append(headerModel.Terms, &pvModel)
-		} else {
-			initialTermModelsMap[headerModel.DealKey] = &pvModel
-			headerModelsMap[headerModel.DealKey] = &headerModel
-			dealKeys = append(dealKeys, headerModel.DealKey)
-		}
-
-		headerModels = append(headerModels, &headerModel)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	if len(dealKeys) > 0 {
-		termModelsMap, err := repo.getNucPowerSwapDealTermModel(ctx, dealKeys)
-		if err != nil {
-			logger.Debugln("error getting getNucPowerSwapDealTermModel: ", err)
-			return nil, err
-		}
-
-		for dealKey, termModels := range termModelsMap {
-			for _, termModel := range termModels {
-				termModel.Pool1 = initialTermModelsMap[dealKey].Pool1
-				termModel.Product1 = initialTermModelsMap[dealKey].Product1
-				termModel.Indexes1 = initialTermModelsMap[dealKey].Indexes1
-				termModel.Indexes2 = initialTermModelsMap[dealKey].Indexes2
-				termModel.HolidaySchedule = initialTermModelsMap[dealKey].HolidaySchedule
-
-				headerModelsMap[dealKey].Terms = append(headerModelsMap[dealKey].Terms, termModel)
-			}
-		}
-	}
-
-	return headerModels, nil
-}
-
-func (repo *NucleusTradeRepository) getNucPowerSwapDealTermModel(ctx context.Context, dealKeys []int) (map[int][]*nucleus.NucleusTradeTermModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "getNucPowerSwapDealTermModel")
-
-	if len(dealKeys) == 0 {
-		return nil, nil
-	}
-
-	termModelMap := make(map[int][]*nucleus.NucleusTradeTermModel)
-
-	insNumQuery, params, err := oracle.CreateInQueryInt(dealKeys, []interface{}{}, "pswp_pswap_key")
-	if err != nil {
-		logger.Debugln("error in CreateInQueryInt: ", err)
-		return nil, err
-	}
-
-	query := getNucPowerSwapDealTermModelQuery(insNumQuery)
-
-	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
-	if err != nil {
-		logger.Debugln("error got when executing getNucPowerSwapDealTermModelQuery: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var termModel nucleus.NucleusTradeTermModel
-
-		var dealKey int
-
-		if err := rows.Scan(
-			&dealKey, &termModel.VolSeq, &termModel.BegDate, &termModel.EndDate,
-		); err != nil {
-			logger.Debugln("error when scanning rows: ", err)
-			return nil, err
-		}
-
-		termModelMap[dealKey] = append(termModelMap[dealKey], &termModel)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	return termModelMap, nil
-}
-
-func (repo *NucleusTradeRepository) GetNucPowerOptionsDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "GetNucPowerOptionsDealList")
-
-	rows, err := repo.nucleusDb.QueryContext(ctx, getNucPowerOptionsDealListQuery, sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime))
-	if err != nil {
-		logger.Debugln("error got when executing getNucPowerOptionsDealListQuery: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	var headerModels []*nucleus.NucleusTradeHeaderModel
-
-	formulaRegex, err := regexp.Compile(formulaPattern)
-	if err != nil {
-		logger.Debugln("error when compiling regex: ", err)
-		return nil, err
-	}
-
-	for rows.Next() {
-		var headerModel nucleus.NucleusTradeHeaderModel
-		var pvModel nucleus.NucleusTradeTermModel
-
-		var contract, confirmFormat, hsHedgeKey, hasBroker,
-			broker, executionDate, executionTime, exoticFlag,
-			interaffiliateFlag, ibPortfolio, ibUrTrader,
-			settleFormula, strikeFormula sql.NullString
-		var ibPrtPortfolio sql.NullInt32
-		var totalQuantity, fixedPrice sql.NullFloat64
-
-		if err := rows.Scan(
-			&headerModel.DealKey, &headerModel.DealType, &totalQuantity, &headerModel.DnDirection,
-			&headerModel.TransactionDate, &headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName,
-			&headerModel.CompanyCode, &headerModel.LegalEntity, &headerModel.LegalEntityLongName,
-			&headerModel.CyLegalEntityKey, &interaffiliateFlag, &contract,
-			&confirmFormat, &headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio,
-			&headerModel.Portfolio, &headerModel.UrTrader, &ibPrtPortfolio, &ibPortfolio,
-			&ibUrTrader, &headerModel.TzTimeZone, &headerModel.TzExerciseZone, &hasBroker,
-			&broker, &pvModel.Pool1, &pvModel.Product1, &pvModel.PointCode1,
-			&settleFormula, &headerModel.StartDate, &headerModel.EndDate, &pvModel.HolidaySchedule,
-			&pvModel.Volume, &fixedPrice, &pvModel.PriceType, &strikeFormula,
-			&headerModel.CreatedBy, &headerModel.ModifiedBy, &headerModel.CreatedAt, &headerModel.ModifiedAt,
-			&executionDate, &executionTime, &exoticFlag,
-		); err != nil {
-			logger.Debugln("error scanning rows: ", err)
-			return nil, err
-		}
-
-		if totalQuantity.Valid {
-			headerModel.TotalQuantity = totalQuantity.Float64
-		}
-
-		if interaffiliateFlag.Valid {
-			headerModel.InteraffiliateFlag = interaffiliateFlag.String
-		}
-
-		if contract.Valid {
-			headerModel.Contract = contract.String
-		}
-
-		if confirmFormat.Valid {
-			headerModel.ConfirmFormat = confirmFormat.String
-		}
-
-		if hsHedgeKey.Valid {
-			headerModel.HsHedgeKey = hsHedgeKey.String
-		}
-
-		if ibPrtPortfolio.Valid {
-			headerModel.IbPrtPortfolio = int(ibPrtPortfolio.Int32)
-		}
-
-		if ibPortfolio.Valid {
-			headerModel.IbPortfolio = ibPortfolio.String
-		}
-
-		if ibUrTrader.Valid {
-			headerModel.IbUrTrader = ibUrTrader.String
-		}
-
-		if hasBroker.Valid {
-			headerModel.HasBroker = hasBroker.String
-		}
-
-		if broker.Valid {
-			headerModel.Broker = broker.String
-		}
-
-		pvModel.BegDate = headerModel.StartDate
-		pvModel.EndDate = headerModel.EndDate
-		pvModel.VolSeq = 0
-
-		if fixedPrice.Valid {
-			pvModel.FixedPrice = fixedPrice.Float64
-		}
-
-		if exoticFlag.Valid {
-			headerModel.ExoticFlag = exoticFlag.String
-		}
-
-		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
-			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
-			if err != nil {
-				logger.Debugln("error parsing executionDate and executionTime: ", err)
-				return nil, err
-			}
-			headerModel.ExecutionTime = execTime
-		}
-
-		if settleFormula.Valid && settleFormula.String != "" {
-			formula := formulaRegex.FindString(settleFormula.String)
-			formula = strings.ReplaceAll(formula, "[", "")
-			formula = strings.ReplaceAll(formula, "]", "")
-			formulaSections := strings.Split(formula, "|")
-
-			if len(formulaSections) == 3 {
-				var idxModel nucleus.NucleusTradeIndexModel
-				idxModel.Publication = formulaSections[0]
-				idxModel.PubIndex = formulaSections[1]
-				idxModel.Frequency = formulaSections[2]
-				pvModel.Indexes1 = append(pvModel.Indexes1, &idxModel)
-			}
-		}
-
-		if strikeFormula.Valid && strikeFormula.String != "" {
-			formula := formulaRegex.FindString(strikeFormula.String)
-			formula = strings.ReplaceAll(formula, "[", "")
-			formula = strings.ReplaceAll(formula, "]", "")
-			formulaSections := strings.Split(formula, "|")
-
-			if len(formulaSections) == 3 {
-				var idxModel nucleus.NucleusTradeIndexModel
-				idxModel.Publication = formulaSections[0]
-				idxModel.PubIndex = formulaSections[1]
-				idxModel.Frequency = formulaSections[2]
-				pvModel.Indexes2 = append(pvModel.Indexes2, &idxModel)
-			}
-		}
-
-		headerModel.Terms = append(headerModel.Terms, &pvModel)
-		headerModels = append(headerModels, &headerModel)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	return headerModels, nil
-}
-
-func (repo *NucleusTradeRepository) GetNucCapacityDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "GetNucCapacityDealList")
-
-	rows, err := repo.nucleusDb.QueryContext(ctx, getNucCapacityDealListQuery, sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime))
-	if err != nil {
-		logger.Debugln("error got when executing getNucCapacityDealListQuery: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	var headerModels []*nucleus.NucleusTradeHeaderModel
-	headerModelsMap := make(map[int]*nucleus.NucleusTradeHeaderModel)
-	initialTermModelsMap := make(map[int]*nucleus.NucleusTradeTermModel)
-
-	var dealKeys []int
-	var formulaDealKeys []int
-
-	for rows.Next() {
-		var headerModel nucleus.NucleusTradeHeaderModel
-		var pvModel nucleus.NucleusTradeTermModel
-
-		var interaffiliateFlag, contract, confirmFormat,
-			hsHedgeKey, hasBroker, broker, executionDate,
-			executionTime, energyFormula sql.NullString
-		var totalQuantity, charge sql.NullFloat64
-
-		var nonstdFlag string
-
-		if err := rows.Scan(
-			&headerModel.DealKey, &headerModel.DealType, &totalQuantity, &headerModel.DnDirection,
-			&headerModel.TransactionDate, &headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName,
-			&headerModel.CompanyCode, &headerModel.LegalEntity, &headerModel.LegalEntityLongName,
-			&headerModel.CyLegalEntityKey, &interaffiliateFlag, &contract,
-			&confirmFormat, &headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio,
-			&headerModel.Portfolio, &headerModel.UrTrader, &headerModel.TzTimeZone,
-			&hasBroker, &broker, &nonstdFlag, &pvModel.PriceType,
-			&charge, &pvModel.Volume, &energyFormula, &pvModel.Pool1,
-			&pvModel.Product1, &pvModel.PointCode1, &headerModel.StartDate, &headerModel.EndDate, &pvModel.HolidaySchedule,
-			&headerModel.CreatedBy, &headerModel.ModifiedBy, &headerModel.CreatedAt, &headerModel.ModifiedAt,
-			&executionDate, &executionTime,
-		); err != nil {
-			logger.Debugln("error scanning rows: ", err)
-			return nil, err
-		}
-
-		if totalQuantity.Valid {
-			headerModel.TotalQuantity = totalQuantity.Float64
-		}
-
-		if interaffiliateFlag.Valid {
-			headerModel.InteraffiliateFlag = interaffiliateFlag.String
-		}
-
-		if contract.Valid {
-			headerModel.Contract = contract.String
-		}
-
-		if confirmFormat.Valid {
-			headerModel.ConfirmFormat = confirmFormat.String
-		}
-
-		if hsHedgeKey.Valid {
-			headerModel.HsHedgeKey = hsHedgeKey.String
-		}
-
-		if hasBroker.Valid {
-			headerModel.HasBroker = hasBroker.String
-		}
-
-		if broker.Valid {
-			headerModel.Broker = broker.String
-		}
-
-		if charge.Valid {
-			pvModel.FixedPrice = charge.Float64
-		}
-
-		pvModel.BegDate = headerModel.StartDate
-		pvModel.EndDate = headerModel.EndDate
-
-		headerModel.ExoticFlag = "NA"
-
-		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
-			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
-			if err != nil {
-				logger.Debugln("error parsing executionDate and executionTime: ", err)
-				return nil, err
-			}
-			headerModel.ExecutionTime = execTime
-		}
-
-		if energyFormula.Valid {
-			pvModel.Formula1 = energyFormula.String
-			formulaDealKeys = append(formulaDealKeys, headerModel.DealKey)
-		}
-
-		if nonstdFlag == "N" {
-			pvModel.VolSeq = 0
-			headerModel.Terms = append(headerModel.Terms, &pvModel)
-		} else {
-			initialTermModelsMap[headerModel.DealKey] = &pvModel
-			headerModelsMap[headerModel.DealKey] = &headerModel
-			dealKeys = append(dealKeys, headerModel.DealKey)
-		}
-
-		headerModels = append(headerModels, &headerModel)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	if len(dealKeys) > 0 {
-		termModelsMap, err := repo.getNucCapacityDealTermModel(ctx, dealKeys)
-		if err != nil {
-			logger.Debugln("error getting getNucCapacityDealTermModel: ", err)
-			return nil, err
-		}
-
-		for dealKey, termModels := range termModelsMap {
-			for _, termModel := range termModels {
-				termModel.PriceType = initialTermModelsMap[dealKey].PriceType
-				termModel.Pool1 = initialTermModelsMap[dealKey].Pool1
-				termModel.Product1 = initialTermModelsMap[dealKey].Product1
-				termModel.PointCode1 = initialTermModelsMap[dealKey].PointCode1
-				termModel.HolidaySchedule = initialTermModelsMap[dealKey].HolidaySchedule
-
-				headerModelsMap[dealKey].Terms = append(headerModelsMap[dealKey].Terms, termModel)
-			}
-		}
-	}
-
-	if len(formulaDealKeys) > 0 {
-		indexModelsMap, err := repo.getNucCapacityDealIndexModel(ctx, formulaDealKeys)
-		if err != nil {
-			logger.Debugln("error getting getNucCapacityDealIndexModel: ", err)
-			return nil, err
-		}
-
-		for dealKey, indexModels := range indexModelsMap {
-			headerModelsMap[dealKey].Terms[0].Indexes1 = indexModels
-		}
-	}
-
-	return headerModels, nil
-}
-
-func (repo *NucleusTradeRepository) getNucCapacityDealTermModel(ctx context.Context, dealKeys []int) (map[int][]*nucleus.NucleusTradeTermModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "getNucCapacityDealTermModel")
-
-	if len(dealKeys) == 0 {
-		return nil, nil
-	}
-
-	termModelMap := make(map[int][]*nucleus.NucleusTradeTermModel)
-
-	insNumQuery, params, err := oracle.CreateInQueryInt(dealKeys, []interface{}{}, "cpd_capacity_key")
-	if err != nil {
-		logger.Debugln("error in CreateInQueryInt: ", err)
-		return nil, err
-	}
-
-	query := getNucCapacityDealTermModelQuery(insNumQuery)
-
-	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
-	if err != nil {
-		logger.Debugln("error got when executing getNucCapacityDealTermModelQuery: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var termModel nucleus.NucleusTradeTermModel
-
-		var dealKey int
-
-		if err := rows.Scan(
-			&dealKey, &termModel.BegDate, &termModel.EndDate,
-		); err != nil {
-			logger.Debugln("error when scanning rows: ", err)
-			return nil, err
-		}
-
-		termModel.VolSeq = len(termModelMap[dealKey])
-		termModelMap[dealKey] = append(termModelMap[dealKey], &termModel)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	return termModelMap, nil
-}
-
-func (repo *NucleusTradeRepository) getNucCapacityDealIndexModel(ctx context.Context, dealKeys []int) (map[int][]*nucleus.NucleusTradeIndexModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "getNucCapacityDealIndexModel")
-
-	if len(dealKeys) == 0 {
-		return nil, nil
-	}
-
-	indexModelMap := make(map[int][]*nucleus.NucleusTradeIndexModel)
-
-	insNumQuery, params, err := oracle.CreateInQueryInt(dealKeys, []interface{}{}, "cpd_capacity_key")
-	if err != nil {
-		logger.Debugln("error in CreateInQueryInt: ", err)
-		return nil, err
-	}
-
-	query := getNucCapacityDealIndexModelQuery(insNumQuery)
-	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
-	if err != nil {
-		logger.Debugln("error got when executing getNucCapacityDealIndexModelQuery: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var indexModel nucleus.NucleusTradeIndexModel
-
-		var dealKey int
-
-		if err := rows.Scan(
-			&dealKey, &indexModel.Publication, &indexModel.PubIndex, &indexModel.Frequency,
-		); err != nil {
-			logger.Debugln("error when scanning rows: ", err)
-			return nil, err
-		}
-
-		indexModel.VolSeq = len(indexModelMap[dealKey])
-		indexModelMap[dealKey] = append(indexModelMap[dealKey], &indexModel)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	return indexModelMap, nil
-}
-
-func (repo *NucleusTradeRepository) GetNucPTPDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "GetNucPTPDealList")
-
-	rows, err := repo.nucleusDb.QueryContext(ctx, getNucPTPDealListQuery, sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime))
-	if err != nil {
-		logger.Debugln("error got when executing getNucPTPDealListQuery: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	var headerModels []*nucleus.NucleusTradeHeaderModel
-
-	for rows.Next() {
-		var headerModel nucleus.NucleusTradeHeaderModel
-		var pvModel nucleus.NucleusTradeTermModel
-		var idxModel, idxModel2 nucleus.NucleusTradeIndexModel
-
-		var contract, confirmFormat, hsHedgeKey, hasBroker,
-			broker sql.NullString
-
-		if err := rows.Scan(
-			&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection, &headerModel.TransactionDate,
-			&headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName, &headerModel.CompanyCode,
-			&headerModel.LegalEntity, &headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey,
-			&contract, &confirmFormat, &headerModel.Region, &hsHedgeKey,
-			&headerModel.PrtPortfolio, &headerModel.Portfolio, &headerModel.UrTrader, &headerModel.TzTimeZone,
-			&hasBroker, &broker, &pvModel.BegDate, &pvModel.Pool1, &pvModel.Product1,
-			&idxModel.Publication, &idxModel.PubIndex, &idxModel2.Publication, &idxModel2.PubIndex,
-			&headerModel.CreatedBy, &headerModel.ModifiedBy, &headerModel.CreatedAt, &headerModel.ModifiedAt,
-		); err != nil {
-			logger.Debugln("error scanning rows: ", err)
-			return nil, err
-		}
-
-		if contract.Valid {
-			headerModel.Contract = contract.String
-		}
-
-		if confirmFormat.Valid {
-			headerModel.ConfirmFormat = confirmFormat.String
-		}
-
-		if hsHedgeKey.Valid {
-			headerModel.HsHedgeKey = hsHedgeKey.String
-		}
-
-		if hasBroker.Valid {
-			headerModel.HasBroker = hasBroker.String
-		}
-
-		if broker.Valid {
-			headerModel.Broker = broker.String
-		}
-
-		pvModel.EndDate = pvModel.BegDate
-
-		idxModel.Frequency = "HOURLY"
-		idxModel2.Frequency = "HOURLY"
-
-		headerModel.InteraffiliateFlag = "N"
-
-		pvModel.VolSeq = 0
-		pvModel.Indexes1 = append(pvModel.Indexes1, &idxModel)
-		pvModel.Indexes2 = append(pvModel.Indexes2, &idxModel2)
-
-		headerModel.Terms = append(headerModel.Terms, &pvModel)
-
-		headerModels = append(headerModels, &headerModel)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	return headerModels, nil
-}
-
-func (repo *NucleusTradeRepository) GetNucEmissionDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "GetNucEmissionDealList")
-
-	rows, err := repo.nucleusDb.QueryContext(ctx, getNucEmissionDealListQuery, sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime))
-	if err != nil {
-		logger.Debugln("error got when executing getNucEmissionDealListQuery: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	var headerModels []*nucleus.NucleusTradeHeaderModel
-	headerModelsMap := make(map[int]*nucleus.NucleusTradeHeaderModel)
-
-	var dealKeys []int
-
-	for rows.Next() {
-		var headerModel nucleus.NucleusTradeHeaderModel
-
-		var contract, confirmFormat, hsHedgeKey, hasBroker,
-			broker, executionDate, executionTime, tzTimeZone sql.NullString
-
-		if err := rows.Scan(
-			&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection, &headerModel.TransactionDate,
-			&headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName, &headerModel.CompanyCode,
-			&headerModel.LegalEntity, &headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey,
-			&contract, &confirmFormat, &headerModel.Region, &hsHedgeKey,
-			&headerModel.PrtPortfolio, &headerModel.Portfolio, &headerModel.UrTrader, &tzTimeZone,
-			&hasBroker, &broker, &headerModel.CreatedBy, &headerModel.ModifiedBy,
-			&headerModel.CreatedAt, &headerModel.ModifiedAt, &executionDate, &executionTime,
-		); err != nil {
-			logger.Debugln("error scanning rows: ", err)
-			return nil, err
-		}
-
-		if contract.Valid {
-			headerModel.Contract = contract.String
-		}
-
-		if confirmFormat.Valid {
-			headerModel.ConfirmFormat = confirmFormat.String
-		}
-
-		if hsHedgeKey.Valid {
-			headerModel.HsHedgeKey = hsHedgeKey.String
-		}
-
-		if tzTimeZone.Valid {
-			headerModel.TzTimeZone = tzTimeZone.String
-		}
-
-		if hasBroker.Valid {
-			headerModel.HasBroker = hasBroker.String
-		}
-
-		if broker.Valid {
-			headerModel.Broker = broker.String
-		}
-
-		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
-			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
-			if err != nil {
-				logger.Debugln("error parsing executionDate and executionTime: ", err)
-				return nil, err
-			}
-			headerModel.ExecutionTime = execTime
-		}
-
-		headerModel.ExoticFlag = "NA"
-		headerModel.InteraffiliateFlag = "N"
-
-		dealKeys = append(dealKeys, headerModel.DealKey)
-		headerModels = append(headerModels, &headerModel)
-		headerModelsMap[headerModel.DealKey] = &headerModel
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	if len(dealKeys) > 0 {
-		termModelsMap, err := repo.getNucEmissionDealListTermModel(ctx, dealKeys)
-		if err != nil {
-			logger.Debugln("error getting getNucEmissionDealListTermModel: ", err)
-			return nil, err
-		}
-
-		for dealKey, termModels := range termModelsMap {
-			headerModelsMap[dealKey].Terms = termModels
-		}
-	}
-
-	return headerModels, nil
-}
-
-func (repo *NucleusTradeRepository) getNucEmissionDealListTermModel(ctx context.Context, dealKeys []int) (map[int][]*nucleus.NucleusTradeTermModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "getNucEmissionDealListTermModel")
-
-	if len(dealKeys) == 0 {
-		return nil, nil
-	}
-
-	termModelMap := make(map[int][]*nucleus.NucleusTradeTermModel)
-
-	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeys, []interface{}{}, "pv.ed_emission_key")
-	if err != nil {
-		logger.Debugln("error in CreateInQueryInt: ", err)
-		return nil, err
-	}
-
-	query := getNucEmissionDealListTermModelQuery(dealKeysQuery)
-	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
-	if err != nil {
-		logger.Debugln("error got when executing getNucEmissionDealListTermModelQuery: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	formulaRegex, err := regexp.Compile(formulaPattern)
-	if err != nil {
-		logger.Debugln("error when compiling regex: ", err)
-		return nil, err
-	}
-
-	for rows.Next() {
-		var termModel nucleus.NucleusTradeTermModel
-		var dealKey int
-
-		var ctpPointCode, formula sql.NullString
-
-		if err := rows.Scan(
-			&dealKey, &termModel.VolSeq, &termModel.BegDate, &termModel.EndDate,
-			&termModel.PriceType, &termModel.FixedPrice, &termModel.Volume, &termModel.Product1,
-			&ctpPointCode, &formula,
-		); err != nil {
-			logger.Debugln("error when scanning rows: ", err)
-			return nil, err
-		}
-
-		if ctpPointCode.Valid {
-			termModel.PointCode1 = ctpPointCode.String
-		}
-
-		if formula.Valid && formula.String != "" {
-			termModel.Formula1 = formula.String
-
-			formula := formulaRegex.FindString(formula.String)
-			formula = strings.ReplaceAll(formula, "[", "")
-			formula = strings.ReplaceAll(formula, "]", "")
-			formulaSections := strings.Split(formula, "|")
-
-			if len(formulaSections) == 3 {
-				var idxModel nucleus.NucleusTradeIndexModel
-				idxModel.Publication = formulaSections[0]
-				idxModel.PubIndex = formulaSections[1]
-				idxModel.Frequency = formulaSections[2]
-				termModel.Indexes1 = append(termModel.Indexes1, &idxModel)
-			}
-		}
-
-		termModelMap[dealKey] = append(termModelMap[dealKey], &termModel)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	return termModelMap, nil
-}
-
-func (repo *NucleusTradeRepository) GetNucEmissionOptionDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "GetNucEmissionOptionDealList")
-
-	rows, err := repo.nucleusDb.QueryContext(ctx, getNucEmissionOptionDealListQuery, sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime))
-	if err != nil {
-		logger.Debugln("error got when executing getNucEmissionOptionDealListQuery: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	var headerModels []*nucleus.NucleusTradeHeaderModel
-
-	var emissionKeys []int
-
-	for rows.Next() {
-		var headerModel nucleus.NucleusTradeHeaderModel
-		var pvModel nucleus.NucleusTradeTermModel
-
-		var emissionKey int
-
-		var contract, confirmFormat, hsHedgeKey, hasBroker,
-			broker, executionDate, executionTime, tzTimeZone sql.NullString
-
-		if err := rows.Scan(
-			&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection, &headerModel.TransactionDate,
-			&headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName, &headerModel.CompanyCode,
-			&headerModel.LegalEntity, &headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey,
-			&contract, &confirmFormat, &headerModel.Region, &hsHedgeKey,
-			&headerModel.PrtPortfolio, &headerModel.Portfolio, &headerModel.UrTrader, &tzTimeZone,
-			&hasBroker, &broker, &emissionKey, &pvModel.FixedPrice,
-			&pvModel.Volume, &headerModel.CreatedBy, &headerModel.ModifiedBy, &headerModel.CreatedAt,
-			&headerModel.ModifiedAt, &executionDate, &executionTime,
-		); err != nil {
-			logger.Debugln("error scanning rows: ", err)
-			return nil, err
-		}
-
-		if contract.Valid {
-			headerModel.Contract = contract.String
-		}
-
-		if confirmFormat.Valid {
-			headerModel.ConfirmFormat = confirmFormat.String
-		}
-
-		if hsHedgeKey.Valid {
-			headerModel.HsHedgeKey = hsHedgeKey.String
-		}
-
-		if tzTimeZone.Valid {
-			headerModel.TzTimeZone = tzTimeZone.String
-		}
-
-		if hasBroker.Valid {
-			headerModel.HasBroker = hasBroker.String
-		}
-
-		if broker.Valid {
-			headerModel.Broker = broker.String
-		}
-
-		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
-			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
-			if err != nil {
-				logger.Debugln("error parsing executionDate and executionTime: ", err)
-				return nil, err
-			}
-			headerModel.ExecutionTime = execTime
-		}
-
-		headerModel.ExoticFlag = "NA"
-		headerModel.InteraffiliateFlag = "N"
-
-		pvModel.VolSeq = emissionKey
-
-		headerModel.Terms = append(headerModel.Terms, &pvModel)
-		emissionKeys = append(emissionKeys, emissionKey)
-		headerModels = append(headerModels, &headerModel)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	if len(emissionKeys) > 0 {
-		termModelsMap, err := repo.getNucEmissionOptionDealTermList(ctx, emissionKeys)
-		if err != nil {
-			logger.Debugln("error getting getNucEmissionOptionDealTermList: ", err)
-			return nil, err
-		}
-
-		for _, headerModel := range headerModels {
-			emissionKey := headerModel.Terms[0].VolSeq
-
-			var modelTerms []*nucleus.NucleusTradeTermModel
-			for _, termModel := range termModelsMap[emissionKey] {
-				termModel.FixedPrice = headerModel.Terms[0].FixedPrice
-				termModel.Volume = headerModel.Terms[0].Volume
-				modelTerms = append(modelTerms, termModel)
-			}
-			headerModel.Terms = modelTerms
-		}
-	}
-
-	return headerModels, nil
-}
-
-func (repo *NucleusTradeRepository) getNucEmissionOptionDealTermList(ctx context.Context, emissionKeys []int) (map[int][]*nucleus.NucleusTradeTermModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "getNucEmissionOptionDealTermLists")
-
-	if len(emissionKeys) == 0 {
-		return nil, nil
-	}
-
-	termModelMap := make(map[int][]*nucleus.NucleusTradeTermModel)
-
-	emissionKeysQuery, params, err := oracle.CreateInQueryInt(emissionKeys, []interface{}{}, "pv.ed_emission_key")
-	if err != nil {
-		logger.Debugln("error in CreateInQueryInt: ", err)
-		return nil, err
-	}
-
-	query := getNucEmissionOptionDealTermListQuery(emissionKeysQuery)
-	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
-	if err != nil {
-		logger.Debugln("error got when executing getNucEmissionOptionDealTermListQuery: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var termModel nucleus.NucleusTradeTermModel
-		var emissionKey int
-
-		var ctpPointCode sql.NullString
-
-		if err := rows.Scan(
-			&emissionKey, &termModel.VolSeq, &termModel.BegDate, &termModel.EndDate,
-			&ctpPointCode, &termModel.Product1,
-		); err != nil {
-			logger.Debugln("error when scanning rows: ", err)
-			return nil, err
-		}
-
-		if ctpPointCode.Valid {
-			termModel.PointCode1 = ctpPointCode.String
-		}
-
-		termModelMap[emissionKey] = append(termModelMap[emissionKey], &termModel)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	return termModelMap, nil
-}
-
-func (repo *NucleusTradeRepository) GetNucSpreadOptionsDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "GetNucSpreadOptionsDealList")
-
-	rows, err := repo.nucleusDb.QueryContext(ctx, getNucSpreadOptionsDealListQuery, sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime))
-	if err != nil {
-		logger.Debugln("error got when executing getNucSpreadOptionsDealListQuery: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	var headerModels []*nucleus.NucleusTradeHeaderModel
-
-	formulaRegex, err := regexp.Compile(formulaPattern)
-	if err != nil {
-		logger.Debugln("error when compiling regex: ", err)
-		return nil, err
-	}
-
-	for rows.Next() {
-		var headerModel nucleus.NucleusTradeHeaderModel
-		var pvModel nucleus.NucleusTradeTermModel
-
-		var contract, confirmFormat, hsHedgeKey, hasBroker, broker, executionDate,
-			executionTime, tzTimeZone, ibPortfolio, ibUrTrader, formula1, formula2,
-			pool1, pool2, product1, product2, exoticFlag, pointCode1 sql.NullString
-		var ibPrtPortfolio sql.NullInt32
-		var fixedPrice sql.NullFloat64
-
-		if err := rows.Scan(
-			&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection, &headerModel.TransactionDate,
-			&headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName, &headerModel.CompanyCode,
-			&headerModel.LegalEntity, &headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey,
-			&contract, &confirmFormat, &headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio,
-			&headerModel.Portfolio, &headerModel.UrTrader, &ibPrtPortfolio, &ibPortfolio,
-			&ibUrTrader, &tzTimeZone, &hasBroker, &broker, &headerModel.StartDate, &headerModel.EndDate,
-			&pvModel.HolidaySchedule, &formula1, &formula2, &pool1, &pool2, &product1, &product2,
-			&pvModel.Volume, &fixedPrice, &headerModel.CreatedBy, &headerModel.ModifiedBy, &headerModel.CreatedAt,
-			&headerModel.ModifiedAt, &executionDate, &executionTime, &exoticFlag, &pointCode1,
-		); err != nil {
-			logger.Debugln("error scanning rows: ", err)
-			return nil, err
-		}
-
-		if contract.Valid {
-			headerModel.Contract = contract.String
-		}
-
-		if confirmFormat.Valid {
-			headerModel.ConfirmFormat = confirmFormat.String
-		}
-
-		if hsHedgeKey.Valid {
-			headerModel.HsHedgeKey = hsHedgeKey.String
-		}
-
-		if ibPrtPortfolio.Valid {
-			headerModel.IbPrtPortfolio = int(ibPrtPortfolio.Int32)
-		}
-
-		if ibPortfolio.Valid {
-			headerModel.IbPortfolio = ibPortfolio.String
-		}
-
-		if ibUrTrader.Valid {
-			headerModel.IbUrTrader = ibUrTrader.String
-		}
-
-		if tzTimeZone.Valid {
-			headerModel.TzTimeZone = tzTimeZone.String
-		}
-
-		if hasBroker.Valid {
-			headerModel.HasBroker = hasBroker.String
-		}
-
-		if broker.Valid {
-			headerModel.Broker = broker.String
-		}
-
-		if pool1.Valid {
-			pvModel.Pool1 = pool1.String
-		}
-
-		if pool2.Valid {
-			pvModel.Pool2 = pool2.String
-		}
-
-		if product1.Valid {
-			pvModel.Product1 = product1.String
-		}
-
-		if product2.Valid {
-			pvModel.Product2 = product2.String
-		}
-
-		if fixedPrice.Valid {
-			pvModel.FixedPrice = fixedPrice.Float64
-		}
-
-		if exoticFlag.Valid {
-			headerModel.ExoticFlag = exoticFlag.String
-		}
-
-		if pointCode1.Valid {
-			pvModel.PointCode1 = pointCode1.String
-		}
-
-		headerModel.InteraffiliateFlag = "N"
-
-		pvModel.VolSeq = 0
-		pvModel.BegDate = headerModel.StartDate
-		pvModel.EndDate = headerModel.EndDate
-
-		if formula1.Valid && formula1.String != "" {
-			formula := formulaRegex.FindString(formula1.String)
-			formula = strings.ReplaceAll(formula, "[", "")
-			formula = strings.ReplaceAll(formula, "]", "")
-			formulaSections := strings.Split(formula, "|")
-
-			if len(formulaSections) == 3 {
-				var idxModel nucleus.NucleusTradeIndexModel
-				idxModel.Publication = formulaSections[0]
-				idxModel.PubIndex = formulaSections[1]
-				idxModel.Frequency = formulaSections[2]
-				pvModel.Indexes1 = append(pvModel.Indexes1, &idxModel)
-			}
-		}
-
-		if formula2.Valid && formula2.String != "" {
-			formula := formulaRegex.FindString(formula2.String)
-			formula = strings.ReplaceAll(formula, "[", "")
-			formula = strings.ReplaceAll(formula, "]", "")
-			formulaSections := strings.Split(formula, "|")
-
-			if len(formulaSections) == 3 {
-				var idxModel nucleus.NucleusTradeIndexModel
-				idxModel.Publication = formulaSections[0]
-				idxModel.PubIndex = formulaSections[1]
-				idxModel.Frequency = formulaSections[2]
-				pvModel.Indexes2 = append(pvModel.Indexes2, &idxModel)
-			}
-		}
-
-		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
-			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
-			if err != nil {
-				logger.Debugln("error parsing executionDate and executionTime: ", err)
-				return nil, err
-			}
-			headerModel.ExecutionTime = execTime
-		}
-
-		headerModel.Terms = append(headerModel.Terms, &pvModel)
-		headerModels = append(headerModels, &headerModel)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	return headerModels, nil
-}
-
-func (repo *NucleusTradeRepository) GetNucHeatRateSwapsDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "GetNucHeatRateSwapsDealList")
-
-	rows, err := repo.nucleusDb.QueryContext(ctx, getNucHeatRateSwapsDealListQuery, sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime))
-	if err != nil {
-		logger.Debugln("error got when executing getNucHeatRateSwapsDealListQuery: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	var headerModels []*nucleus.NucleusTradeHeaderModel
-
-	for rows.Next() {
-		var headerModel nucleus.NucleusTradeHeaderModel
-		var pvModel nucleus.NucleusTradeTermModel
-		var idxModel, idxModel2 nucleus.NucleusTradeIndexModel
-
-		var contract, confirmFormat, hsHedgeKey, hasBroker, broker, executionDate,
-			executionTime, tzTimeZone, interaffiliateFlag, publication1, pubIndex1,
-			publication2, pubIndex2, frequency2 sql.NullString
-		var exercisedOptionKey sql.NullInt32
-		var totalQuantity, volume sql.NullFloat64
-
-		if err := rows.Scan(
-			&headerModel.DealKey, &headerModel.DealType, &totalQuantity, &headerModel.DnDirection,
-			&headerModel.TransactionDate, &headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName,
-			&headerModel.CompanyCode, &headerModel.LegalEntity, &headerModel.LegalEntityLongName,
-			&headerModel.CyLegalEntityKey, &interaffiliateFlag, &contract, &confirmFormat,
-			&headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio, &headerModel.Portfolio,
-			&headerModel.UrTrader, &tzTimeZone, &hasBroker, &broker, &exercisedOptionKey,
-			&pvModel.Pool1, &pvModel.Product1, &publication1, &pubIndex1, &publication2,
-			&pubIndex2, &frequency2, &headerModel.StartDate, &headerModel.EndDate,
-			&pvModel.HolidaySchedule, &volume, &headerModel.CreatedBy, &headerModel.ModifiedBy,
-			&headerModel.CreatedAt, &headerModel.ModifiedAt, &executionDate, &executionTime,
-		); err != nil {
-			logger.Debugln("error scanning rows: ", err)
-			return nil, err
-		}
-
-		if totalQuantity.Valid {
-			headerModel.TotalQuantity = totalQuantity.Float64
-		}
-
-		if interaffiliateFlag.Valid {
-			headerModel.InteraffiliateFlag = interaffiliateFlag.String
-		}
-
-		if contract.Valid {
-			headerModel.Contract = contract.String
-		}
-
-		if confirmFormat.Valid {
-			headerModel.ConfirmFormat = confirmFormat.String
-		}
-
-		if hsHedgeKey.Valid {
-			headerModel.HsHedgeKey = hsHedgeKey.String
-		}
-
-		if tzTimeZone.Valid {
-			headerModel.TzTimeZone = tzTimeZone.String
-		}
-
-		if hasBroker.Valid {
-			headerModel.HasBroker = hasBroker.String
-		}
-
-		if broker.Valid {
-			headerModel.Broker = broker.String
-		}
-
-		if exercisedOptionKey.Valid {
-			headerModel.ExercisedOptionKey = int(exercisedOptionKey.Int32)
-		}
-
-		if publication1.Valid {
-			idxModel.Publication = publication1.String
-		}
-
-		if pubIndex1.Valid {
-			idxModel.PubIndex = pubIndex1.String
-		}
-
-		if publication2.Valid {
-			idxModel2.Publication = publication2.String
-		}
-
-		if pubIndex2.Valid {
-			idxModel2.PubIndex = pubIndex2.String
-		}
-
-		if frequency2.Valid {
-			idxModel2.Frequency = frequency2.String
-		}
-
-		pvModel.Indexes1 = append(pvModel.Indexes1, &idxModel)
-		pvModel.Indexes2 = append(pvModel.Indexes2, &idxModel2)
-		pvModel.Indexes2 = append(pvModel.Indexes2, &idxModel2)
-
-		if volume.Valid {
-			pvModel.Volume = volume.Float64
-		}
-
-		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
-			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
-			if err != nil {
-				logger.Debugln("error parsing executionDate and executionTime: ", err)
-				return nil, err
-			}
-			headerModel.ExecutionTime = execTime
-		}
-
-		headerModel.ExoticFlag = "NA"
-		pvModel.VolSeq = 0
-
-		pvModel.BegDate = headerModel.StartDate
-		pvModel.EndDate = headerModel.EndDate
-
-		headerModel.Terms = append(headerModel.Terms, &pvModel)
-		headerModels = append(headerModels, &headerModel)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	return headerModels, nil
-}
-
-func (repo *NucleusTradeRepository) GetNucTCCFTRSDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time, strDealType string) ([]*nucleus.NucleusTradeHeaderModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "GetNucTCCFTRSDealList")
-
-	rows, err := repo.nucleusDb.QueryContext(ctx, getNucTCCFTRSDealListQuery, sql.Named("tradeDate", tradeDate), sql.Named("strDealType", strDealType), sql.Named("lastRunTime", lastRunTime))
-	if err != nil {
-		logger.Debugln("error got when executing getNucTCCFTRSDealListQuery: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	var headerModels []*nucleus.NucleusTradeHeaderModel
-
-	for rows.Next() {
-		var headerModel nucleus.NucleusTradeHeaderModel
-		var pvModel nucleus.NucleusTradeTermModel
-		var idxModel, idxModel2 nucleus.NucleusTradeIndexModel
-
-		var contract, confirmFormat, hsHedgeKey, hasBroker, broker sql.NullString
-		var volume, fixedPrice sql.NullFloat64
-
-		if err := rows.Scan(
-			&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection, &headerModel.TransactionDate,
-			&headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName, &headerModel.CompanyCode,
-			&headerModel.LegalEntity, &headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey,
-			&contract, &confirmFormat, &headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio,
-			&headerModel.Portfolio, &headerModel.UrTrader, &headerModel.TzTimeZone, &hasBroker, &broker,
-			&pvModel.BegDate, &pvModel.EndDate, &pvModel.HolidaySchedule, &volume,
-			&fixedPrice, &pvModel.Pool1, &pvModel.Product1, &idxModel.Publication,
-			&idxModel.Frequency, &idxModel.PubIndex, &idxModel2.PubIndex, &headerModel.CreatedBy,
-			&headerModel.ModifiedBy, &headerModel.CreatedAt, &headerModel.ModifiedAt,
-		); err != nil {
-			logger.Debugln("error scanning rows: ", err)
-			return nil, err
-		}
-
-		if contract.Valid {
-			headerModel.Contract = contract.String
-		}
-
-		if confirmFormat.Valid {
-			headerModel.ConfirmFormat = confirmFormat.String
-		}
-
-		if hsHedgeKey.Valid {
-			headerModel.HsHedgeKey = hsHedgeKey.String
-		}
-
-		if hasBroker.Valid {
-			headerModel.HasBroker = hasBroker.String
-		}
-
-		if broker.Valid {
-			headerModel.Broker = broker.String
-		}
-
-		if volume.Valid {
-			pvModel.Volume = volume.Float64
-		}
-
-		if fixedPrice.Valid {
-			pvModel.FixedPrice = fixedPrice.Float64
-		}
-
-		idxModel2.Publication = idxModel.Publication
-		idxModel2.Frequency = idxModel.Frequency
-
-		headerModel.ExoticFlag = "NA"
-		headerModel.InteraffiliateFlag = "N"
-
-		pvModel.Indexes1 = append(pvModel.Indexes1, &idxModel)
-		pvModel.Indexes2 = append(pvModel.Indexes2, &idxModel2)
-		pvModel.VolSeq = 0
-
-		headerModel.Terms = append(headerModel.Terms, &pvModel)
-		headerModels = append(headerModels, &headerModel)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	return headerModels, nil
-}
-
-func (repo *NucleusTradeRepository) GetNucTransmissionDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "GetNucTransmissionDealList")
-
-	rows, err := repo.nucleusDb.QueryContext(ctx, getNucTransmissionDealListQuery, sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime))
-	if err != nil {
-		logger.Debugln("error got when executing getNucTransmissionDealListQuery: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	var headerModels []*nucleus.NucleusTradeHeaderModel
-	headerModelsMap := make(map[int]*nucleus.NucleusTradeHeaderModel)
-
-	var dealKeys []int
-
-	for rows.Next() {
-		var headerModel nucleus.NucleusTradeHeaderModel
-		var contract, confirmFormat, hsHedgeKey, hasBroker, broker, executionTime sql.NullString
-
-		if err := rows.Scan(
-			&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection, &headerModel.TransactionDate,
-			&headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName, &headerModel.CompanyCode,
-			&headerModel.LegalEntity, &headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey,
-			&contract, &confirmFormat, &headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio,
-			&headerModel.Portfolio, &headerModel.UrTrader, &headerModel.TzTimeZone, &hasBroker,
-			&broker, &headerModel.CreatedBy, &headerModel.ModifiedBy, &headerModel.CreatedAt,
-			&headerModel.ModifiedAt, &executionTime,
-		); err != nil {
-			logger.Debugln("error scanning rows: ", err)
-			return nil, err
-		}
-
-		if contract.Valid {
-			headerModel.Contract = contract.String
-		}
-
-		if confirmFormat.Valid {
-			headerModel.ConfirmFormat = confirmFormat.String
-		}
-
-		if hsHedgeKey.Valid {
-			headerModel.HsHedgeKey = hsHedgeKey.String
-		}
-
-		if hasBroker.Valid {
-			headerModel.HasBroker = hasBroker.String
-		}
-
-		if broker.Valid {
-			headerModel.Broker = broker.String
-		}
-
-		if executionTime.Valid {
-			time, err := parseExecutionDate(executionTime.String)
-			if err != nil {
-				logger.Debugln("error parsing executionDate and executionTime: ", err)
-				return nil, err
-			}
-
-			headerModel.ExecutionTime = time
-		}
-
-		headerModel.ExoticFlag = "NA"
-		headerModel.InteraffiliateFlag = "N"
-
-		dealKeys = append(dealKeys, headerModel.DealKey)
-		headerModelsMap[headerModel.DealKey] = &headerModel
-		headerModels = append(headerModels, &headerModel)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	if len(dealKeys) > 0 {
-		termModelsMap, err := repo.getNucTransmissionDealTermModel(ctx, dealKeys)
-		if err != nil {
-			logger.Debugln("error getting getNucTransmissionDealTermModel: ", err)
-			return nil, err
-		}
-
-		for dealKey, termModels := range termModelsMap {
-			headerModelsMap[dealKey].Terms = termModels
-		}
-	}
-
-	return headerModels, nil
-}
-
-func (repo *NucleusTradeRepository) getNucTransmissionDealTermModel(ctx context.Context, dealKeys []int) (map[int][]*nucleus.NucleusTradeTermModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "getNucTransmissionDealTermModel")
-
-	if len(dealKeys) == 0 {
-		return nil, nil
-	}
-
-	termModels := make(map[int][]*nucleus.NucleusTradeTermModel)
-
-	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeys, []interface{}{}, "pv.td_trans_key")
-	if err != nil {
-		logger.Debugln("error in CreateInQueryInt: ", err)
-		return nil, err
-	}
-
-	query := getNucTransmissionDealTermListQuery(dealKeysQuery)
-	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
-	if err != nil {
-		logger.Debugln("error got when executing getNucTransmissionDealTermListQuery: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var termModel nucleus.NucleusTradeTermModel
-		var dealKey int
-
-		if err := rows.Scan(
-			&dealKey, &termModel.VolSeq, &termModel.BegDate, &termModel.EndDate,
-			&termModel.Volume, &termModel.Product1, &termModel.Pool1, &termModel.PointCode1,
-			&termModel.Pool2, &termModel.PointCode2, &termModel.HolidaySchedule,
-		); err != nil {
-			logger.Debugln("error when scanning rows: ", err)
-			return nil, err
-		}
-
-		termModel.PriceType = "F"
-
-		termModels[dealKey] = append(termModels[dealKey], &termModel)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	return termModels, nil
-}
-
-func (repo *NucleusTradeRepository) GetNucMiscChargeDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "GetNucMiscChargeDealList")
-
-	rows, err := repo.nucleusDb.QueryContext(ctx, getNucMiscChargeDealListQuery, sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime))
-	if err != nil {
-		logger.Debugln("error got when executing getNucMiscChargeDealListQuery: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	var headerModels []*nucleus.NucleusTradeHeaderModel
-	headerModelsMap := make(map[int]*nucleus.NucleusTradeHeaderModel)
-
-	var dealKeys []int
-
-	for rows.Next() {
-		var headerModel nucleus.NucleusTradeHeaderModel
-		var contract, confirmFormat, hsHedgeKey, hasBroker, broker, region, tzTimeZone sql.NullString
-
-		if err := rows.Scan(
-			&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection, &headerModel.TransactionDate,
-			&headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName, &headerModel.CompanyCode,
-			&headerModel.LegalEntity, &headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey,
-			&contract, &confirmFormat, &region, &hsHedgeKey, &headerModel.PrtPortfolio,
-			&headerModel.Portfolio, &headerModel.UrTrader, &tzTimeZone, &hasBroker, &broker,
-			&headerModel.CreatedBy, &headerModel.ModifiedBy, &headerModel.CreatedAt, &headerModel.ModifiedAt,
-		); err != nil {
-			logger.Debugln("error scanning rows: ", err)
-			return nil, err
-		}
-
-		if contract.Valid {
-			headerModel.Contract = contract.String
-		}
-
-		if confirmFormat.Valid {
-			headerModel.ConfirmFormat = confirmFormat.String
-		}
-
-		if region.Valid {
-			headerModel.Region = region.String
-		}
-
-		if hsHedgeKey.Valid {
-			headerModel.HsHedgeKey = hsHedgeKey.String
-		}
-
-		if tzTimeZone.Valid {
-			headerModel.TzTimeZone = tzTimeZone.String
-		}
-
-		if hasBroker.Valid {
-			headerModel.HasBroker = hasBroker.String
-		}
-
-		if broker.Valid {
-			headerModel.Broker = broker.String
-		}
-
-		headerModel.InteraffiliateFlag = "N"
-
-		dealKeys = append(dealKeys, headerModel.DealKey)
-		headerModelsMap[headerModel.DealKey] = &headerModel
-		headerModels = append(headerModels, &headerModel)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	if len(dealKeys) > 0 {
-		termModelsMap, err := repo.getNucMiscChargeDealTermList(ctx, dealKeys)
-		if err != nil {
-			logger.Debugln("error getting getNucMiscChargeDealTermList: ", err)
-			return nil, err
-		}
-
-		for dealKey, termModels := range termModelsMap {
-			headerModelsMap[dealKey].Terms = termModels
-		}
-	}
-
-	return headerModels, nil
-}
-
-func (repo *NucleusTradeRepository) getNucMiscChargeDealTermList(ctx context.Context, dealKeys []int) (map[int][]*nucleus.NucleusTradeTermModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "getNucMiscChargeDealTermList")
-
-	if len(dealKeys) == 0 {
-		return nil, nil
-	}
-
-	termModels := make(map[int][]*nucleus.NucleusTradeTermModel)
-
-	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeys, []interface{}{}, "pv.mc_misc_charge_key")
-	if err != nil {
-		logger.Debugln("error in CreateInQueryInt: ", err)
-		return nil, err
-	}
-
-	query := getNucMiscChargeDealTermListQuery(dealKeysQuery)
-	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
-	if err != nil {
-		logger.Debugln("error got when executing getNucMiscChargeDealTermListQuery: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var termModel nucleus.NucleusTradeTermModel
-		var dealKey int
-
-		if err := rows.Scan(
-			&dealKey, &termModel.VolSeq, &termModel.BegDate, &termModel.EndDate,
-			&termModel.Volume,
-		); err != nil {
-			logger.Debugln("error when scanning rows: ", err)
-			return nil, err
-		}
-
-		termModels[dealKey] = append(termModels[dealKey], &termModel)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	return termModels, nil
-}
-
-func (repo *NucleusTradeRepository) GetNucPowerDealByKeys(ctx context.Context, lstPowerkeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "GetNucPowerDealByKeys")
-
-	dealKeysQuery, params, err := oracle.CreateInQueryFloat64(lstPowerkeys, []interface{}{}, "pd.power_key")
-	if err != nil {
-		logger.Debugln("error in CreateInQueryFloat64: ", err)
-		return nil, err
-	}
-
-	query := getNucPowerDealByKeysQuery(dealKeysQuery)
-
-	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
-	if err != nil {
-		logger.Debugln("error got when executing getNucPowerDealByKeys: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	var headerModels []*nucleus.NucleusTradeHeaderModel
-	headerModelsMap := make(map[int]*nucleus.NucleusTradeHeaderModel)
-	var dealKeys []int
-
-	for rows.Next() {
-		var headerModel nucleus.NucleusTradeHeaderModel
-
-		var contract, confirmFormat, hsHedgeKey, hasBroker,
-			broker, executionDate, executionTime, exoticFlag sql.NullString
-		var optionKey sql.NullInt32
-
-		if err := rows.Scan(&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection,
-			&headerModel.TransactionDate, &headerModel.CyCompanyKey, &headerModel.Company,
-			&headerModel.CompanyLongName, &headerModel.CompanyCode, &headerModel.LegalEntity,
-			&headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey, &contract,
-			&confirmFormat, &headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio,
-			&headerModel.Portfolio, &headerModel.UrTrader, &headerModel.TzTimeZone,
-			&hasBroker, &broker, &optionKey, &headerModel.CreatedBy,
-			&headerModel.CreatedAt, &headerModel.ModifiedBy, &headerModel.ModifiedAt,
-			&executionDate, &executionTime, &exoticFlag,
-		); err != nil {
-			logger.Debugln("error scanning rows: ", err)
-			return nil, err
-		}
-
-		if contract.Valid {
-			headerModel.Contract = contract.String
-		}
-
-		if confirmFormat.Valid {
-			headerModel.ConfirmFormat = confirmFormat.String
-		}
-
-		if hsHedgeKey.Valid {
-			headerModel.HsHedgeKey = hsHedgeKey.String
-		}
-
-		if hasBroker.Valid {
-			headerModel.HasBroker = hasBroker.String
-		}
-
-		if broker.Valid {
-			headerModel.Broker = broker.String
-		}
-
-		if optionKey.Valid {
-			headerModel.ExercisedOptionKey = int(optionKey.Int32)
-		}
-
-		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
-			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
-			if err != nil {
-				logger.Debugln("error parsing executionDate and executionTime: ", err)
-				return nil, err
-			}
-			headerModel.ExecutionTime = execTime
-		}
-
-		if exoticFlag.Valid {
-			headerModel.ExoticFlag = exoticFlag.String
-		}
-
-		headerModel.InteraffiliateFlag = "N"
-
-		dealKeys = append(dealKeys, headerModel.DealKey)
-		headerModelsMap[headerModel.DealKey] = &headerModel
-		headerModels = append(headerModels, &headerModel)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	if len(dealKeys) > 0 {
-		termModelsMap, err := repo.getNucPowerDealTradeTermModel(ctx, dealKeys)
-		if err != nil {
-			logger.Debugln("error getting getNucPowerDealTradeTermModel: ", err)
-			return nil, err
-		}
-
-		for index := range headerModelsMap {
-			headerModelsMap[index].Terms = termModelsMap[index]
-		}
-	}
-
-	return headerModels, nil
-}
-
-func (repo *NucleusTradeRepository) GetNucPowerSwapDealByKeys(ctx context.Context, lstPowerSwapkeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "GetNucPowerSwapDealByKeys")
-
-	dealKeysQuery, params, err := oracle.CreateInQueryFloat64(lstPowerSwapkeys, []interface{}{}, "pd.pswap_key")
-	if err != nil {
-		logger.Debugln("error in CreateInQueryFloat64: ", err)
-		return nil, err
-	}
-
-	query := getNucPowerSwapDealByKeysQuery(dealKeysQuery)
-
-	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
-	if err != nil {
-		logger.Debugln("error got when executing getNucPowerSwapDealByKeys: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	var headerModels []*nucleus.NucleusTradeHeaderModel
-	headerModelsMap := make(map[int]*nucleus.NucleusTradeHeaderModel)
-	initialTermModelsMap := make(map[int]*nucleus.NucleusTradeTermModel)
-
-	var dealKeys []int
-
-	for rows.Next() {
-		var headerModel nucleus.NucleusTradeHeaderModel
-		var pvModel nucleus.NucleusTradeTermModel
-		var idxModel, idxModel2 nucleus.NucleusTradeIndexModel
-
-		var interaffiliateFlag, contract, confirmFormat,
-			hsHedgeKey, ibPortfolio, ibUrTrader, hasBroker,
-			broker, executionDate, executionTime, exoticFlag sql.NullString
-		var totalQuantity, fixedPrice sql.NullFloat64
-		var ibPrtPortfolio, optionKey sql.NullInt32
-
-		var nonstdFlag string
-
-		var fixPiPbPublication, fixPiPubIndex, fixFrqFrequency sql.NullString
-
-		if err := rows.Scan(
-			&headerModel.DealKey, &headerModel.DealType, &totalQuantity, &headerModel.DnDirection,
-			&headerModel.TransactionDate, &headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName,
-			&headerModel.CompanyCode, &headerModel.LegalEntity, &headerModel.LegalEntityLongName,
-			&headerModel.CyLegalEntityKey, &interaffiliateFlag, &contract,
-			&confirmFormat, &headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio,
-			&headerModel.Portfolio, &headerModel.UrTrader, &ibPrtPortfolio,
-			&ibPortfolio, &ibUrTrader, &headerModel.TzTimeZone, &hasBroker,
-			&broker, &optionKey, &pvModel.Pool1, &pvModel.Product1,
-			&pvModel.Volume, &fixedPrice, &nonstdFlag, &idxModel.Publication,
-			&idxModel.PubIndex, &idxModel.Frequency, &fixPiPbPublication,
-			&fixPiPubIndex, &fixFrqFrequency, &headerModel.StartDate, &headerModel.EndDate,
-			&pvModel.HolidaySchedule, &headerModel.CreatedBy, &headerModel.CreatedAt, &headerModel.ModifiedBy,
-			&headerModel.ModifiedAt, &executionDate, &executionTime, &exoticFlag,
-		); err != nil {
-			logger.Debugln("error scanning rows: ", err)
-			return nil, err
-		}
-
-		if totalQuantity.Valid {
-			headerModel.TotalQuantity = totalQuantity.Float64
-		}
-
-		if interaffiliateFlag.Valid {
-			headerModel.InteraffiliateFlag = interaffiliateFlag.String
-		}
-
-		if contract.Valid {
-			headerModel.Contract = contract.String
-		}
-
-		if confirmFormat.Valid {
-			headerModel.ConfirmFormat = confirmFormat.String
-		}
-
-		if hsHedgeKey.Valid {
-			headerModel.HsHedgeKey = hsHedgeKey.String
-		}
-
-		if ibPrtPortfolio.Valid {
-			headerModel.IbPrtPortfolio = int(ibPrtPortfolio.Int32)
-		}
-
-		if ibPortfolio.Valid {
-			headerModel.IbPortfolio = ibPortfolio.String
-		}
-
-		if ibUrTrader.Valid {
-			headerModel.IbUrTrader = ibUrTrader.String
-		}
-
-		if hasBroker.Valid {
-			headerModel.HasBroker = hasBroker.String
-		}
-
-		if broker.Valid {
-			headerModel.Broker = broker.String
-		}
-
-		if optionKey.Valid {
-			headerModel.ExercisedOptionKey = int(optionKey.Int32)
-		}
-
-		if fixedPrice.Valid {
-			pvModel.FixedPrice = fixedPrice.Float64
-		}
-
-		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
-			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
-			if err != nil {
-				logger.Debugln("error parsing executionDate and executionTime: ", err)
-				return nil, err
-			}
-			headerModel.ExecutionTime = execTime
-		}
-
-		if exoticFlag.Valid {
-			headerModel.ExoticFlag = exoticFlag.String
-		}
-
-		if fixPiPbPublication.Valid && fixPiPubIndex.Valid && fixFrqFrequency.Valid {
-			idxModel2.Publication = fixPiPbPublication.String
-			idxModel2.PubIndex = fixPiPubIndex.String
-			idxModel2.Frequency = fixFrqFrequency.String
-			pvModel.Indexes2 = append(pvModel.Indexes2, &idxModel2)
-		}
-
-		pvModel.BegDate = headerModel.StartDate
-		pvModel.EndDate = headerModel.EndDate
-		pvModel.Indexes1 = append(pvModel.Indexes1, &idxModel)
-
-		if nonstdFlag == "N" {
-			pvModel.VolSeq = 0
-			headerModel.Terms = append(headerModel.Terms, &pvModel)
-		} else {
-			initialTermModelsMap[headerModel.DealKey] = &pvModel
-			headerModelsMap[headerModel.DealKey] = &headerModel
-			dealKeys = append(dealKeys, headerModel.DealKey)
-		}
-
-		headerModels = append(headerModels, &headerModel)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	if len(dealKeys) > 0 {
-		termModelsMap, err := repo.getNucPowerSwapDealTermModel(ctx, dealKeys)
-		if err != nil {
-			logger.Debugln("error getting getNucPowerSwapDealTermModel: ", err)
-			return nil, err
-		}
-
-		for dealKey, termModels := range termModelsMap {
-			for _, termModel := range termModels {
-				termModel.Pool1 = initialTermModelsMap[dealKey].Pool1
-				termModel.Product1 = initialTermModelsMap[dealKey].Product1
-				termModel.Indexes1 = initialTermModelsMap[dealKey].Indexes1
-				termModel.Indexes2 = initialTermModelsMap[dealKey].Indexes2
-				termModel.HolidaySchedule = initialTermModelsMap[dealKey].HolidaySchedule
-
-				headerModelsMap[dealKey].Terms = append(headerModelsMap[dealKey].Terms, termModel)
-			}
-		}
-	}
-
-	return headerModels, nil
-}
-
-func (repo *NucleusTradeRepository) GetNucPowerOptionsDealByKeys(ctx context.Context, lstPowerOptionkeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "GetNucPowerOptionsDealByKeys")
-
-	dealKeysQuery, params, err := oracle.CreateInQueryFloat64(lstPowerOptionkeys, []interface{}{}, "pd.poption_key")
-	if err != nil {
-		logger.Debugln("error in CreateInQueryFloat64: ", err)
-		return nil, err
-	}
-
-	query := getNucPowerOptionsDealByKeysQuery(dealKeysQuery)
-	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
-	if err != nil {
-		logger.Debugln("error got when executing getNucPowerOptionsDealByKeysQuery: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	var headerModels []*nucleus.NucleusTradeHeaderModel
-
-	formulaRegex, err := regexp.Compile(formulaPattern)
-	if err != nil {
-		logger.Debugln("error when compiling regex: ", err)
-		return nil, err
-	}
-
-	for rows.Next() {
-		var headerModel nucleus.NucleusTradeHeaderModel
-		var pvModel nucleus.NucleusTradeTermModel
-
-		var company, companyLongName, companyCode, legalEntity, legalEntityLongName,
-			contract, confirmFormat, hsHedgeKey, hasBroker, broker, executionDate,
-			executionTime, exoticFlag, interaffiliateFlag, region, portfolio, ibPortfolio,
-			ibUrTrader, settleFormula, strikeFormula sql.NullString
-		var ibPrtPortfolio sql.NullInt32
-		var totalQuantity, fixedPrice sql.NullFloat64
-
-		if err := rows.Scan(
-			&headerModel.DealKey, &headerModel.DealType, &totalQuantity, &headerModel.DnDirection,
-			&headerModel.TransactionDate, &headerModel.CyCompanyKey, &company, &companyLongName,
-			&companyCode, &legalEntity, &legalEntityLongName,
-			&headerModel.CyLegalEntityKey, &interaffiliateFlag, &contract,
-			&confirmFormat, &region, &hsHedgeKey, &headerModel.PrtPortfolio,
-			&portfolio, &headerModel.UrTrader, &ibPrtPortfolio, &ibPortfolio,
-			&ibUrTrader, &headerModel.TzTimeZone, &headerModel.TzExerciseZone, &hasBroker,
-			&broker, &pvModel.Pool1, &pvModel.Product1, &pvModel.PointCode1,
-			&settleFormula, &headerModel.StartDate, &headerModel.EndDate, &pvModel.HolidaySchedule,
-			&pvModel.Volume, &fixedPrice, &pvModel.PriceType, &strikeFormula,
-			&headerModel.CreatedBy, &headerModel.ModifiedBy, &headerModel.CreatedAt, &headerModel.ModifiedAt,
-			&executionDate, &executionTime, &exoticFlag,
-		); err != nil {
-			logger.Debugln("error scanning rows: ", err)
-			return nil, err
-		}
-
-		if totalQuantity.Valid {
-			headerModel.TotalQuantity = totalQuantity.Float64
-		}
-
-		if company.Valid {
-			headerModel.Company = company.String
-		}
-
-		if companyLongName.Valid {
-			headerModel.CompanyLongName = companyLongName.String
-		}
-
-		if companyCode.Valid {
-			headerModel.CompanyCode = companyCode.String
-		}
-
-		if legalEntity.Valid {
-			headerModel.LegalEntity = legalEntity.String
-		}
-
-		if legalEntityLongName.Valid {
-			headerModel.LegalEntityLongName = legalEntityLongName.String
-		}
-
-		if interaffiliateFlag.Valid {
-			headerModel.InteraffiliateFlag = interaffiliateFlag.String
-		}
-
-		if contract.Valid {
-			headerModel.Contract = contract.String
-		}
-
-		if confirmFormat.Valid {
-			headerModel.ConfirmFormat = confirmFormat.String
-		}
-
-		if region.Valid {
-			headerModel.Region = region.String
-		}
-
-		if hsHedgeKey.Valid {
-			headerModel.HsHedgeKey = hsHedgeKey.String
-		}
-
-		if portfolio.Valid {
-			headerModel.Portfolio = portfolio.String
-		}
-
-		if ibPrtPortfolio.Valid {
-			headerModel.IbPrtPortfolio = int(ibPrtPortfolio.Int32)
-		}
-
-		if ibPortfolio.Valid {
-			headerModel.IbPortfolio = ibPortfolio.String
-		}
-
-		if ibUrTrader.Valid {
-			headerModel.IbUrTrader = ibUrTrader.String
-		}
-
-		if hasBroker.Valid {
-			headerModel.HasBroker = hasBroker.String
-		}
-
-		if broker.Valid {
-			headerModel.Broker = broker.String
-		}
-
-		pvModel.BegDate = headerModel.StartDate
-		pvModel.EndDate = headerModel.EndDate
-		pvModel.VolSeq = 0
-
-		if fixedPrice.Valid {
-			pvModel.FixedPrice = fixedPrice.Float64
-		}
-
-		if exoticFlag.Valid {
-			headerModel.ExoticFlag = exoticFlag.String
-		}
-
-		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
-			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
-			if err != nil {
-				logger.Debugln("error parsing executionDate and executionTime: ", err)
-				return nil, err
-			}
-			headerModel.ExecutionTime = execTime
-		}
-
-		if settleFormula.Valid && settleFormula.String != "" {
-			formula := formulaRegex.FindString(settleFormula.String)
-			formula = strings.ReplaceAll(formula, "[", "")
-			formula = strings.ReplaceAll(formula, "]", "")
-			formulaSections := strings.Split(formula, "|")
-
-			if len(formulaSections) == 3 {
-				var idxModel nucleus.NucleusTradeIndexModel
-				idxModel.Publication = formulaSections[0]
-				idxModel.PubIndex = formulaSections[1]
-				idxModel.Frequency = formulaSections[2]
-				pvModel.Indexes1 = append(pvModel.Indexes1, &idxModel)
-			}
-		}
-
-		if strikeFormula.Valid && strikeFormula.String != "" {
-			formula := formulaRegex.FindString(strikeFormula.String)
-			formula = strings.ReplaceAll(formula, "[", "")
-			formula = strings.ReplaceAll(formula, "]", "")
-			formulaSections := strings.Split(formula, "|")
-
-			if len(formulaSections) == 3 {
-				var idxModel nucleus.NucleusTradeIndexModel
-				idxModel.Publication = formulaSections[0]
-				idxModel.PubIndex = formulaSections[1]
-				idxModel.Frequency = formulaSections[2]
-				pvModel.Indexes2 = append(pvModel.Indexes2, &idxModel)
-			}
-		}
-
-		headerModel.Terms = append(headerModel.Terms, &pvModel)
-		headerModels = append(headerModels, &headerModel)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	return headerModels, nil
-}
-
-func (repo *NucleusTradeRepository) GetNucCapacityDealByKeys(ctx context.Context, lstCapacitykeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
-	return nil, fmt.Errorf("method not implemented")
-}
-
-func (repo *NucleusTradeRepository) GetNucPTPDealByKeys(ctx context.Context, lstPTPkeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
-	return nil, fmt.Errorf("method not implemented")
-}
-
-func (repo *NucleusTradeRepository) GetNucEmissionDealByKeys(ctx context.Context, lstEmissionkeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
-	return nil, fmt.Errorf("method not implemented")
-}
-
-func (repo *NucleusTradeRepository) GetNucEmissionOptionDealByKeys(ctx context.Context, lstEmissionkeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
-	return nil, fmt.Errorf("method not implemented")
-}
-
-func (repo *NucleusTradeRepository) GetNucSpreadOptionsDealByKeys(ctx context.Context, lstSpreadOptionkeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
-	return nil, fmt.Errorf("method not implemented")
-}
-
-func (repo *NucleusTradeRepository) GetNucHeatRateSwapsDealByKeys(ctx context.Context, lstHeatRateSwapskeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
-	return nil, fmt.Errorf("method not implemented")
-}
-
-func (repo *NucleusTradeRepository) GetNucTransmissionDealByKeys(ctx context.Context, lstTranskeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
-	return nil, fmt.Errorf("method not implemented")
-}
-
-func (repo *NucleusTradeRepository) GetNucTCCFTRSDealByKeys(ctx context.Context, lstTccFtrskeys []float64, strDealType string) ([]*nucleus.NucleusTradeHeaderModel, error) {
-	return nil, fmt.Errorf("method not implemented")
-}
-
-func (repo *NucleusTradeRepository) GetNucMiscChargeDealByKeys(ctx context.Context, lstTranskeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
-	return nil, fmt.Errorf("method not implemented")
-}
-
-type nucleusProcessedTradeType struct {
-	TradeId             int64
-	DealType            string
-	PortfolioId         int64
-	TransactionDate     time.Time
-	TradeDetail         string
-	AnomalyDetectedFlag bool
-	AnomalyTestResult   sql.NullString
-	ModelParameters     sql.NullString
-}
-
-func (repo *NucleusTradeRepository) ProcessTrades(ctx context.Context, trades []*nucleus.NucleusTradeHeaderModel, anomalyMessages map[int][]common.IModelBasePayload) error {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "ProcessTrades")
-
-	if len(trades) == 0 {
-		return nil
-	}
-
-	var nucleusProcessedTradeTypeData []nucleusProcessedTradeType
-
-	for _, trade := range trades {
-		var anomalyMsg, modelParams string
-
-		if resultList, ok := anomalyMessages[trade.DealKey]; ok {
-			for _, result := range resultList {
-				if result.GetScoredLabel() == "NO" {
-					if anomalyMsg == "" {
-						anomalyMsg = result.GetModelName() + " " + result.GetMessage()
-					} else {
-						anomalyMsg = anomalyMsg + ";" + result.GetModelName() + " " + result.GetMessage()
-					}
-
-					vParams, err := json.Marshal(result)
-					if err != nil {
-						logger.Debugln("error marshalling ModelParams: ", err)
-						return err
-					}
-
-					if modelParams == "" {
-						modelParams = string(vParams)
-					} else {
-						modelParams = modelParams + ";" + string(vParams)
-					}
-				}
-			}
-		}
-
-		marshalledTrade, err := json.Marshal(trade)
-		if err != nil {
-			logger.Debugln("error marshalling trade: ", err)
-			return err
-		}
-
-		nucleusProcessedTrade := nucleusProcessedTradeType{
-			TradeId:             int64(trade.DealKey),
-			DealType:            trade.DealType,
-			PortfolioId:         int64(trade.PrtPortfolio),
-			TransactionDate:     trade.TransactionDate,
-			TradeDetail:         string(marshalledTrade),
-			AnomalyDetectedFlag: false,
-			AnomalyTestResult: sql.NullString{
-				Valid: false,
-			},
-			ModelParameters: sql.NullString{
-				Valid: false,
-			},
-		}
-
-		if anomalyMsg != "" {
-			nucleusProcessedTrade.AnomalyDetectedFlag = true
-			nucleusProcessedTrade.AnomalyTestResult = sql.NullString{
-				String: anomalyMsg,
-				Valid:  true,
-			}
-		}
-
-		if modelParams != "" {
-			nucleusProcessedTrade.ModelParameters = sql.NullString{
-				String: modelParams,
-				Valid:  true,
-			}
-		}
-
-		nucleusProcessedTradeTypeData = append(nucleusProcessedTradeTypeData, nucleusProcessedTrade)
-	}
-
-	tvpType := mssql.TVP{
-		TypeName: "NucleusProcessedTradeType",
-		Value:    nucleusProcessedTradeTypeData,
-	}
-
-	if _, err := repo.machineLearningDb.Exec(execProcessTradesQuery, sql.Named("TVP", tvpType)); err != nil {
-		logger.Debugln("error in execProcessTradesQuery: ", err)
-		return err
-	}
-
-	return nil
-}
-
-func (repo *NucleusTradeRepository) GetLastExtractionRun(ctx context.Context, tradeDate time.Time, dealType string) (*nucleus.NucleusTradeExtractionRunModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "GetLastExtractionRun")
-
-	var extractionRun nucleus.NucleusTradeExtractionRunModel
-	if err := repo.machineLearningDb.QueryRowContext(ctx, getLastExtractionRunQuery, sql.Named("transactionDate", tradeDate), sql.Named("dealType", dealType)).
-		Scan(
-			&extractionRun.ExtractionRunId, &extractionRun.TransactionDate, &extractionRun.DealType,
-			&extractionRun.TimeParameter, &extractionRun.CreatedAt,
-		); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
-		logger.Debugln("error got when executing getLastExtractionRunQuery: ", err)
-		return nil, err
-	}
-
-	return &extractionRun, nil
-}
-
-func (repo *NucleusTradeRepository) InsertExtractionRun(ctx context.Context, tradeDate time.Time, lastRun time.Time, dealType string) error {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "InsertExtractionRun")
-
-	if _, err := repo.machineLearningDb.ExecContext(ctx, insertExtractionRunQuery,
-		sql.Named("transactionDate", tradeDate),
-		sql.Named("dealType", dealType),
-		sql.Named("timeParameter", lastRun),
-	); err != nil {
-		logger.Debugln("error got when executing insertExtractionRun: ", err)
-		return err
-	}
-
-	return nil
-}
-
-func (repo *NucleusTradeRepository) GetPortfolioRiskMappingList(ctx context.Context) ([]*nucleus.PortfolioRiskMappingModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "GetPortfolioRiskMappingList")
-
-	rows, err := repo.machineLearningDb.QueryContext(ctx, getPortfolioRiskMappingListQuery)
-	if err != nil {
-		logger.Debugln("error got when executing getPortfolioRiskMappingListQuery: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	var portfolioRiskModels []*nucleus.PortfolioRiskMappingModel
-
-	for rows.Next() {
-		var portfolioRiskModel nucleus.PortfolioRiskMappingModel
-
-		var legalEntity sql.NullString
-
-		if err := rows.Scan(
-			&portfolioRiskModel.SourceSystem, &portfolioRiskModel.Portfolio, &legalEntity,
-		); err != nil {
-			logger.Debugln("error scanning rows: ", err)
-			return nil, err
-		}
-
-		if legalEntity.Valid {
-			portfolioRiskModel.LegalEntity = legalEntity.String
-		}
-
-		portfolioRiskModels = append(portfolioRiskModels, &portfolioRiskModel)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	return portfolioRiskModels, nil
-}
-
-func (repo *NucleusTradeRepository) GetLarBaselist(ctx context.Context) ([]*common.LarBaseModel, error) {
-	logger := repo.logger.GetLogger()
-	logger = logger.WithField("method", "GetLarBaselist")
-
-	rows, err := repo.machineLearningDb.QueryContext(ctx, getLarBaselistQuery)
-	if err != nil {
-		logger.Debugln("error got when executing getLarBaselistQuery: ", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	var larBaseModels []*common.LarBaseModel
-
-	for rows.Next() {
-		var larBaseModel common.LarBaseModel
-
-		var shortName, counterpartyLongName, parentCompany, product, sourceSystem, dealType,
-			nettingAgreement, agreementTypePerCSA, buyTenor, sellTenor, limitAvailability,
-			sPRating, moodyRating, finalInternalRating, finalRating, equifax, amendedBy,
-			doddFrankClassification, tradingEntity, legalEntity sql.NullString
-		var grossExposure, collateral, netPosition sql.NullFloat64
-		var effectiveDate, reviewDate, reportCreatedDate, reportingDate, createdAt sql.NullTime
-
-		if err := rows.Scan(
-			&shortName, &counterpartyLongName, &parentCompany, &product, &sourceSystem,
-			&dealType, &nettingAgreement, &agreementTypePerCSA, &larBaseModel.OurThreshold,
-			&larBaseModel.CounterpartyThreshold, &buyTenor, &sellTenor, &grossExposure,
-			&collateral, &netPosition, &larBaseModel.LimitValue, &larBaseModel.LimitCurrency,
-			&limitAvailability, &larBaseModel.ExposureLimit, &larBaseModel.ExpirationDate,
-			&larBaseModel.MarketType, &larBaseModel.IndustryCode, &sPRating, &moodyRating,
-			&finalInternalRating, &finalRating, &equifax, &amendedBy, &effectiveDate,
-			&reviewDate, &doddFrankClassification, &reportCreatedDate, &larBaseModel.Boost,
-			&tradingEntity, &legalEntity, &larBaseModel.Agmt, &larBaseModel.CSA,
-			&larBaseModel.Tenor, &larBaseModel.Limit, &reportingDate, &createdAt,
-		); err != nil {
-			logger.Debugln("error scanning rows: ", err)
-			return nil, err
-		}
-
-		if shortName.Valid {
-			larBaseModel.ShortName = shortName.String
-		}
-
-		if counterpartyLongName.Valid {
-			larBaseModel.CounterpartyLongName = counterpartyLongName.String
-		}
-
-		if parentCompany.Valid {
-			larBaseModel.ParentCompany = parentCompany.String
-		}
-
-		if product.Valid {
-			larBaseModel.Product = product.String
-		}
-
-		if sourceSystem.Valid {
-			larBaseModel.SourceSystem = sourceSystem.String
-		}
-
-		if dealType.Valid {
-			larBaseModel.DealType = dealType.String
-		}
-
-		if nettingAgreement.Valid {
-			larBaseModel.NettingAgreement = nettingAgreement.String
-		}
-
-		if agreementTypePerCSA.Valid {
-			larBaseModel.AgreementTypePerCSA = agreementTypePerCSA.String
-		}
-
-		if buyTenor.Valid {
-			larBaseModel.BuyTenor = buyTenor.String
-		}
-
-		if sellTenor.Valid {
-			larBaseModel.SellTenor = sellTenor.String
-		}
-
-		if grossExposure.Valid {
-			larBaseModel.GrossExposure = grossExposure.Float64
-		}
-
-		if collateral.Valid {
-			larBaseModel.Collateral = collateral.Float64
-		}
-
-		if netPosition.Valid {
-			larBaseModel.NetPosition = netPosition.Float64
-		}
-
-		if limitAvailability.Valid {
-			larBaseModel.LimitAvailability = limitAvailability.String
-		}
-
-		if sPRating.Valid {
-			larBaseModel.SPRating = sPRating.String
-		}
-
-		if moodyRating.Valid {
-			larBaseModel.MoodyRating = moodyRating.String
-		}
-
-		if finalInternalRating.Valid {
-			larBaseModel.FinalInternalRating = finalInternalRating.String
-		}
-
-		if finalRating.Valid {
-			larBaseModel.FinalRating = finalRating.String
-		}
-
-		if equifax.Valid {
-			larBaseModel.Equifax = equifax.String
-		}
-
-		if amendedBy.Valid {
-			larBaseModel.AmendedBy = amendedBy.String
-		}
-
-		if effectiveDate.Valid {
-			larBaseModel.EffectiveDate = effectiveDate.Time
-		}
-
-		if reviewDate.Valid {
-			larBaseModel.ReviewDate = reviewDate.Time
-		}
-
-		if doddFrankClassification.Valid {
-			larBaseModel.DoddFrankClassification = doddFrankClassification.String
-		}
-
-		if reportCreatedDate.Valid {
-			larBaseModel.ReportCreatedDate = reportCreatedDate.Time
-		}
-
-		if tradingEntity.Valid {
-			larBaseModel.TradingEntity = tradingEntity.String
-		}
-
-		if legalEntity.Valid {
-			larBaseModel.LegalEntity = legalEntity.String
-		}
-
-		if reportingDate.Valid {
-			larBaseModel.ReportingDate = reportingDate.Time
-		}
-
-		if createdAt.Valid {
-			larBaseModel.CreatedAt = createdAt.Time
-		}
-
-		larBaseModels = append(larBaseModels, &larBaseModel)
-	}
-
-	if closeErr := rows.Close(); closeErr != nil {
-		logger.Debugln("error closing the rows: ", err)
-		return nil, err
-	}
-
-	if err := rows.Err(); err != nil {
-		logger.Debugln("error in rows: ", err)
-		return nil, err
-	}
-
-	return larBaseModels, nil
-}
+package power
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	mssql "github.com/denisenkom/go-mssqldb"
+
+	"github.com/sede-x/RogerRogerAnomalyDetector/db/oracle"
+	"github.com/sede-x/RogerRogerAnomalyDetector/logger"
+	"github.com/sede-x/RogerRogerAnomalyDetector/models/common"
+	"github.com/sede-x/RogerRogerAnomalyDetector/models/nucleus"
+)
+
+const (
+	formulaPattern = ("\\[.+\\|.+\\|.+\\]")
+)
+
+func parseExecutionDateTime(executionDate string, executionTime string) (time.Time, error) {
+	executionDateString := strings.Split(executionDate, " ")[0]
+	executionTimeString := executionDateString + " " + executionTime
+	execTime, err := time.Parse("2006-01-02 03:04:05 PM", executionTimeString)
+	if err != nil {
+		execTime, err = time.Parse("01/02/2006 03:04:05 PM", executionTimeString)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+	return execTime, err
+}
+
+func parseExecutionDate(executionDate string) (time.Time, error) {
+	execTime, err := time.Parse("2006-01-02", executionDate)
+	if err != nil {
+		execTime, err = time.Parse("01/02/2006", executionDate)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+	return execTime, err
+}
+
+type NucleusTradeRepository struct {
+	nucleusDb         *sql.DB
+	machineLearningDb *sql.DB
+	logger            logger.Logger
+
+	processTradesChunkSize int
+	extractionLeaseTTL     time.Duration
+	watermarkOverlap       time.Duration
+	ruleEngine             *NucleusRuleEngine
+
+	dealListStreamsOnce sync.Once
+	dealListStreams     chan struct{}
+}
+
+// NucleusTradeRepositoryOption changes the default settings of the repository
+type NucleusTradeRepositoryOption func(repo *NucleusTradeRepository)
+
+// WithProcessTradesChunkSize sets the number of trades sent in a single TVP
+// by ProcessTrades. Values lower than 1 keep defaultProcessTradesChunkSize
+func WithProcessTradesChunkSize(chunkSize int) NucleusTradeRepositoryOption {
+	return func(repo *NucleusTradeRepository) {
+		repo.processTradesChunkSize = chunkSize
+	}
+}
+
+// WithExtractionLeaseTTL sets how long the extraction lease taken by
+// ExtractAndProcessTrades lasts without being renewed. Values lower than a
+// second keep defaultExtractionLeaseTTL
+func WithExtractionLeaseTTL(ttl time.Duration) NucleusTradeRepositoryOption {
+	return func(repo *NucleusTradeRepository) {
+		repo.extractionLeaseTTL = ttl
+	}
+}
+
+// WithExtractionWatermarkOverlap sets how long before the SYSDATE of NUCLEUS
+// the watermark stored by ExtractAndProcessTrades is. Values lower than a
+// second keep defaultExtractionWatermarkOverlap
+func WithExtractionWatermarkOverlap(overlap time.Duration) NucleusTradeRepositoryOption {
+	return func(repo *NucleusTradeRepository) {
+		repo.watermarkOverlap = overlap
+	}
+}
+
+// WithRuleEngine sets the rule engine ExtractAndProcessTrades evaluates the
+// extracted trades with
+func WithRuleEngine(ruleEngine *NucleusRuleEngine) NucleusTradeRepositoryOption {
+	return func(repo *NucleusTradeRepository) {
+		repo.ruleEngine = ruleEngine
+	}
+}
+
+// NewNucleusTradeRepository builds the repository. The trades it extracts are
+// evaluated by the built-in rules enabled in NUCLEUS_ANOMALY_RULES, unless
+// WithRuleEngine sets another engine
+func NewNucleusTradeRepository(nucleusDb *sql.DB, machineLearningDb *sql.DB, logger logger.Logger, options ...NucleusTradeRepositoryOption) *NucleusTradeRepository {
+	repo := &NucleusTradeRepository{
+		nucleusDb:         nucleusDb,
+		machineLearningDb: machineLearningDb,
+		logger:            logger,
+		ruleEngine:        NewDefaultNucleusRuleEngine(LoadNucleusAnomalyRuleNames()),
+	}
+
+	for _, option := range options {
+		option(repo)
+	}
+
+	return repo
+}
+
+func (repo *NucleusTradeRepository) GetNucPowerDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return repo.getNucPowerDealList(ctx, "GetNucPowerDealList", getNucPowerDealListQuery, sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime))
+}
+
+func (repo *NucleusTradeRepository) StreamNucPowerDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	return repo.streamNucPowerDealList(ctx, "StreamNucPowerDealList", getNucPowerDealListQuery, []interface{}{sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime)}, yield)
+}
+
+func (repo *NucleusTradeRepository) GetNucPowerDealListByTradeDateRange(ctx context.Context, lastRunTime time.Time, fromTradeDate time.Time, toTradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return repo.getNucPowerDealList(ctx, "GetNucPowerDealListByTradeDateRange", getNucDealListByTradeDateRangeQuery(getNucPowerDealListQuery), sql.Named("fromTradeDate", fromTradeDate), sql.Named("toTradeDate", toTradeDate), sql.Named("lastRunTime", lastRunTime))
+}
+
+func (repo *NucleusTradeRepository) getNucPowerDealList(ctx context.Context, method string, query string, args ...interface{}) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return collectHeaderModels(func(yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+		return repo.streamNucPowerDealList(ctx, method, query, args, yield)
+	})
+}
+
+func (repo *NucleusTradeRepository) streamNucPowerDealList(ctx context.Context, method string, query string, args []interface{}, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", method)
+
+	release, err := repo.acquireDealListStream(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucPowerDealListQuery: ", err)
+		return err
+	}
+	defer rows.Close()
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+	headerModelsMap := make(map[int]*nucleus.NucleusTradeHeaderModel)
+	var dealKeys []int
+
+	flush := func() error {
+		if len(dealKeys) > 0 {
+			termModelsMap, err := repo.getNucPowerDealTradeTermModel(ctx, dealKeys)
+			if err != nil {
+				logger.Debugln("error getting getNucPowerDealTradeTermModel: ", err)
+				return err
+			}
+
+			for index := range headerModelsMap {
+				headerModelsMap[index].Terms = termModelsMap[index]
+			}
+		}
+
+		for _, headerModel := range headerModels {
+			if err := yield(headerModel); err != nil {
+				return err
+			}
+		}
+
+		headerModels = nil
+		headerModelsMap = make(map[int]*nucleus.NucleusTradeHeaderModel)
+		dealKeys = nil
+
+		return nil
+	}
+
+	for rows.Next() {
+		var headerModel nucleus.NucleusTradeHeaderModel
+
+		var contract, confirmFormat, hsHedgeKey, hasBroker,
+			broker, executionDate, executionTime, exoticFlag sql.NullString
+		var optionKey sql.NullInt32
+
+		if err := rows.Scan(&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection,
+			&headerModel.TransactionDate, &headerModel.CyCompanyKey, &headerModel.Company,
+			&headerModel.CompanyLongName, &headerModel.CompanyCode, &headerModel.LegalEntity,
+			&headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey, &contract,
+			&confirmFormat, &headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio,
+			&headerModel.Portfolio, &headerModel.UrTrader, &headerModel.TzTimeZone,
+			&hasBroker, &broker, &optionKey, &headerModel.CreatedBy,
+			&headerModel.CreatedAt, &headerModel.ModifiedBy, &headerModel.ModifiedAt,
+			&executionDate, &executionTime, &exoticFlag,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return err
+		}
+
+		if contract.Valid {
+			headerModel.Contract = contract.String
+		}
+
+		if confirmFormat.Valid {
+			headerModel.ConfirmFormat = confirmFormat.String
+		}
+
+		if hsHedgeKey.Valid {
+			headerModel.HsHedgeKey = hsHedgeKey.String
+		}
+
+		if hasBroker.Valid {
+			headerModel.HasBroker = hasBroker.String
+		}
+
+		if broker.Valid {
+			headerModel.Broker = broker.String
+		}
+
+		if optionKey.Valid {
+			headerModel.ExercisedOptionKey = int(optionKey.Int32)
+		}
+
+		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
+			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
+			if err != nil {
+				logger.Debugln("error parsing executionDate and executionTime: ", err)
+				return err
+			}
+			headerModel.ExecutionTime = execTime
+		}
+
+		if exoticFlag.Valid {
+			headerModel.ExoticFlag = exoticFlag.String
+		}
+
+		headerModel.InteraffiliateFlag = "N"
+
+		dealKeys = append(dealKeys, headerModel.DealKey)
+		headerModelsMap[headerModel.DealKey] = &headerModel
+		headerModels = append(headerModels, &headerModel)
+
+		if len(headerModels) == dealListStreamBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return err
+	}
+
+	return flush()
+}
+
+func (repo *NucleusTradeRepository) getNucPowerDealTradeTermModel(ctx context.Context, dealKeys []int) (map[int][]*nucleus.NucleusTradeTermModel, error) {
+	return getTermModelsInChunks(ctx, dealKeys, repo.getNucPowerDealTradeTermModelChunk)
+}
+
+func (repo *NucleusTradeRepository) getNucPowerDealTradeTermModelChunk(ctx context.Context, dealKeys []int) (map[int][]*nucleus.NucleusTradeTermModel, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "getNucPowerDealTradeTermModel")
+
+	if len(dealKeys) == 0 {
+		return nil, nil
+	}
+
+	termModels := make(map[int][]*nucleus.NucleusTradeTermModel)
+
+	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeys, []interface{}{}, "pv.pd_power_key")
+	if err != nil {
+		logger.Debugln("error in CreateInQueryInt: ", err)
+		return nil, err
+	}
+
+	query := getNucPowerTradeTermModelQuery(dealKeysQuery)
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucPowerTradeTermModelQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	formulaMap := make(map[int]int)
+	for rows.Next() {
+		var termModel nucleus.NucleusTradeTermModel
+
+		var powerKey int
+		var formula sql.NullString
+
+		if err := rows.Scan(
+			&powerKey, &termModel.VolSeq, &termModel.BegDate, &termModel.EndDate, &termModel.PriceType,
+			&termModel.FixedPrice, &termModel.Volume, &termModel.Pool1, &termModel.Product1,
+			&termModel.PointCode1, &formula, &termModel.HolidaySchedule,
+		); err != nil {
+			logger.Debugln("error when scanning rows: ", err)
+			return nil, err
+		}
+
+		if formula.Valid && formula.String != "" {
+			termModel.Formula1 = formula.String
+			formulaMap[powerKey] = termModel.VolSeq
+		}
+
+		termModels[powerKey] = append(termModels[powerKey], &termModel)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	indexModelsMap, err := repo.getNucPowerTradeIndexModel(ctx, formulaMap)
+	if err != nil {
+		logger.Debugln("error getting getNucPowerTradeIndexModel: ", err)
+		return nil, err
+	}
+
+	for dealKey, indexModels := range indexModelsMap {
+		for _, indexModel := range indexModels {
+			if termModels, ok := termModels[dealKey]; ok {
+				for _, termModel := range termModels {
+					if termModel.VolSeq == indexModel.VolSeq {
+						termModel.Indexes1 = append(termModel.Indexes1, indexModel)
+					}
+				}
+			}
+		}
+	}
+
+	return termModels, nil
+}
+
+func (repo *NucleusTradeRepository) getNucPowerTradeIndexModel(ctx context.Context, formulaMap map[int]int) (map[int][]*nucleus.NucleusTradeIndexModel, error) {
+	return getIndexModelsByFormulaMapInChunks(ctx, formulaMap, repo.getNucPowerTradeIndexModelChunk)
+}
+
+func (repo *NucleusTradeRepository) getNucPowerTradeIndexModelChunk(ctx context.Context, formulaMap map[int]int) (map[int][]*nucleus.NucleusTradeIndexModel, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "getNucPowerTradeIndexModel")
+
+	if len(formulaMap) == 0 {
+		return nil, nil
+	}
+
+	insNumResult := make(map[int][]*nucleus.NucleusTradeIndexModel)
+
+	insNumQuery, params, err := oracle.CreateMapIntWhereQuery(formulaMap, []interface{}{}, "pv_pd_power_key", "pv_volume_seq")
+	if err != nil {
+		logger.Debugln("error in CreateInQueryInt: ", err)
+		return nil, err
+	}
+
+	query := getNucPowerTradeIndexModelQuery(insNumQuery)
+
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucPowerTradeIndexModelQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var termIndex nucleus.NucleusTradeIndexModel
+		var dealKey int
+		if err := rows.Scan(
+			&dealKey, &termIndex.VolSeq, &termIndex.Publication,
+			&termIndex.PubIndex, &termIndex.Frequency,
+		); err != nil {
+			logger.Debugln("error when scanning rows: ", err)
+			return nil, err
+		}
+
+		insNumResult[dealKey] = append(insNumResult[dealKey], &termIndex)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	return insNumResult, nil
+}
+
+func (repo *NucleusTradeRepository) GetNucPowerSwapDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return repo.getNucPowerSwapDealList(ctx, "GetNucPowerSwapDealList", getNucPowerSwapDealListQuery, sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime))
+}
+
+func (repo *NucleusTradeRepository) StreamNucPowerSwapDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	return repo.streamNucPowerSwapDealList(ctx, "StreamNucPowerSwapDealList", getNucPowerSwapDealListQuery, []interface{}{sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime)}, yield)
+}
+
+func (repo *NucleusTradeRepository) GetNucPowerSwapDealListByTradeDateRange(ctx context.Context, lastRunTime time.Time, fromTradeDate time.Time, toTradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return repo.getNucPowerSwapDealList(ctx, "GetNucPowerSwapDealListByTradeDateRange", getNucDealListByTradeDateRangeQuery(getNucPowerSwapDealListQuery), sql.Named("fromTradeDate", fromTradeDate), sql.Named("toTradeDate", toTradeDate), sql.Named("lastRunTime", lastRunTime))
+}
+
+func (repo *NucleusTradeRepository) getNucPowerSwapDealList(ctx context.Context, method string, query string, args ...interface{}) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return collectHeaderModels(func(yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+		return repo.streamNucPowerSwapDealList(ctx, method, query, args, yield)
+	})
+}
+
+func (repo *NucleusTradeRepository) streamNucPowerSwapDealList(ctx context.Context, method string, query string, args []interface{}, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", method)
+
+	release, err := repo.acquireDealListStream(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucPowerSwapDealListQuery: ", err)
+		return err
+	}
+	defer rows.Close()
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+	headerModelsMap := make(map[int]*nucleus.NucleusTradeHeaderModel)
+	initialTermModelsMap := make(map[int]*nucleus.NucleusTradeTermModel)
+
+	var dealKeys []int
+
+	flush := func() error {
+		if len(dealKeys) > 0 {
+			termModelsMap, err := repo.getNucPowerSwapDealTermModel(ctx, dealKeys)
+			if err != nil {
+				logger.Debugln("error getting getNucPowerSwapDealTermModel: ", err)
+				return err
+			}
+
+			for dealKey, termModels := range termModelsMap {
+				for _, termModel := range termModels {
+					termModel.Pool1 = initialTermModelsMap[dealKey].Pool1
+					termModel.Product1 = initialTermModelsMap[dealKey].Product1
+					termModel.Indexes1 = initialTermModelsMap[dealKey].Indexes1
+					termModel.Indexes2 = initialTermModelsMap[dealKey].Indexes2
+					termModel.HolidaySchedule = initialTermModelsMap[dealKey].HolidaySchedule
+
+					headerModelsMap[dealKey].Terms = append(headerModelsMap[dealKey].Terms, termModel)
+				}
+			}
+		}
+
+		for _, headerModel := range headerModels {
+			if err := yield(headerModel); err != nil {
+				return err
+			}
+		}
+
+		headerModels = nil
+		headerModelsMap = make(map[int]*nucleus.NucleusTradeHeaderModel)
+		initialTermModelsMap = make(map[int]*nucleus.NucleusTradeTermModel)
+		dealKeys = nil
+
+		return nil
+	}
+
+	for rows.Next() {
+		var headerModel nucleus.NucleusTradeHeaderModel
+		var pvModel nucleus.NucleusTradeTermModel
+		var idxModel, idxModel2 nucleus.NucleusTradeIndexModel
+
+		var interaffiliateFlag, contract, confirmFormat,
+			hsHedgeKey, ibPortfolio, ibUrTrader, hasBroker,
+			broker, executionDate, executionTime, exoticFlag sql.NullString
+		var totalQuantity, fixedPrice sql.NullFloat64
+		var ibPrtPortfolio, optionKey sql.NullInt32
+
+		var nonstdFlag string
+
+		var fixPiPbPublication, fixPiPubIndex, fixFrqFrequency sql.NullString
+
+		if err := rows.Scan(
+			&headerModel.DealKey, &headerModel.DealType, &totalQuantity, &headerModel.DnDirection,
+			&headerModel.TransactionDate, &headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName,
+			&headerModel.CompanyCode, &headerModel.LegalEntity, &headerModel.LegalEntityLongName,
+			&headerModel.CyLegalEntityKey, &interaffiliateFlag, &contract,
+			&confirmFormat, &headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio,
+			&headerModel.Portfolio, &headerModel.UrTrader, &ibPrtPortfolio,
+			&ibPortfolio, &ibUrTrader, &headerModel.TzTimeZone, &hasBroker,
+			&broker, &optionKey, &pvModel.Pool1, &pvModel.Product1,
+			&pvModel.Volume, &fixedPrice, &nonstdFlag, &idxModel.Publication,
+			&idxModel.PubIndex, &idxModel.Frequency, &fixPiPbPublication,
+			&fixPiPubIndex, &fixFrqFrequency, &headerModel.StartDate, &headerModel.EndDate,
+			&pvModel.HolidaySchedule, &headerModel.CreatedBy, &headerModel.CreatedAt, &headerModel.ModifiedBy,
+			&headerModel.ModifiedAt, &executionDate, &executionTime, &exoticFlag,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return err
+		}
+
+		if totalQuantity.Valid {
+			headerModel.TotalQuantity = totalQuantity.Float64
+		}
+
+		if interaffiliateFlag.Valid {
+			headerModel.InteraffiliateFlag = interaffiliateFlag.String
+		}
+
+		if contract.Valid {
+			headerModel.Contract = contract.String
+		}
+
+		if confirmFormat.Valid {
+			headerModel.ConfirmFormat = confirmFormat.String
+		}
+
+		if hsHedgeKey.Valid {
+			headerModel.HsHedgeKey = hsHedgeKey.String
+		}
+
+		if ibPrtPortfolio.Valid {
+			headerModel.IbPrtPortfolio = int(ibPrtPortfolio.Int32)
+		}
+
+		if ibPortfolio.Valid {
+			headerModel.IbPortfolio = ibPortfolio.String
+		}
+
+		if ibUrTrader.Valid {
+			headerModel.IbUrTrader = ibUrTrader.String
+		}
+
+		if hasBroker.Valid {
+			headerModel.HasBroker = hasBroker.String
+		}
+
+		if broker.Valid {
+			headerModel.Broker = broker.String
+		}
+
+		if optionKey.Valid {
+			headerModel.ExercisedOptionKey = int(optionKey.Int32)
+		}
+
+		if fixedPrice.Valid {
+			pvModel.FixedPrice = fixedPrice.Float64
+		}
+
+		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
+			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
+			if err != nil {
+				logger.Debugln("error parsing executionDate and executionTime: ", err)
+				return err
+			}
+			headerModel.ExecutionTime = execTime
+		}
+
+		if exoticFlag.Valid {
+			headerModel.ExoticFlag = exoticFlag.String
+		}
+
+		if fixPiPbPublication.Valid && fixPiPubIndex.Valid && fixFrqFrequency.Valid {
+			idxModel2.Publication = fixPiPbPublication.String
+			idxModel2.PubIndex = fixPiPubIndex.String
+			idxModel2.Frequency = fixFrqFrequency.String
+			pvModel.Indexes2 = append(pvModel.Indexes2, &idxModel2)
+		}
+
+		pvModel.BegDate = headerModel.StartDate
+		pvModel.EndDate = headerModel.EndDate
+		pvModel.Indexes1 = append(pvModel.Indexes1, &idxModel)
+
+		if nonstdFlag == "N" {
+			pvModel.VolSeq = 0
+			headerModel.Terms = append(headerModel.Terms, &pvModel)
+		} else {
+			initialTermModelsMap[headerModel.DealKey] = &pvModel
+			headerModelsMap[headerModel.DealKey] = &headerModel
+			dealKeys = append(dealKeys, headerModel.DealKey)
+		}
+
+		headerModels = append(headerModels, &headerModel)
+
+		if len(headerModels) == dealListStreamBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return err
+	}
+
+	return flush()
+}
+
+func (repo *NucleusTradeRepository) getNucPowerSwapDealTermModel(ctx context.Context, dealKeys []int) (map[int][]*nucleus.NucleusTradeTermModel, error) {
+	return getTermModelsInChunks(ctx, dealKeys, repo.getNucPowerSwapDealTermModelChunk)
+}
+
+func (repo *NucleusTradeRepository) getNucPowerSwapDealTermModelChunk(ctx context.Context, dealKeys []int) (map[int][]*nucleus.NucleusTradeTermModel, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "getNucPowerSwapDealTermModel")
+
+	if len(dealKeys) == 0 {
+		return nil, nil
+	}
+
+	termModelMap := make(map[int][]*nucleus.NucleusTradeTermModel)
+
+	insNumQuery, params, err := oracle.CreateInQueryInt(dealKeys, []interface{}{}, "pswp_pswap_key")
+	if err != nil {
+		logger.Debugln("error in CreateInQueryInt: ", err)
+		return nil, err
+	}
+
+	query := getNucPowerSwapDealTermModelQuery(insNumQuery)
+
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucPowerSwapDealTermModelQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var termModel nucleus.NucleusTradeTermModel
+
+		var dealKey int
+
+		if err := rows.Scan(
+			&dealKey, &termModel.VolSeq, &termModel.BegDate, &termModel.EndDate,
+		); err != nil {
+			logger.Debugln("error when scanning rows: ", err)
+			return nil, err
+		}
+
+		termModelMap[dealKey] = append(termModelMap[dealKey], &termModel)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	return termModelMap, nil
+}
+
+func (repo *NucleusTradeRepository) GetNucPowerOptionsDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return repo.getNucPowerOptionsDealList(ctx, "GetNucPowerOptionsDealList", getNucPowerOptionsDealListQuery, sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime))
+}
+
+func (repo *NucleusTradeRepository) StreamNucPowerOptionsDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	return repo.streamNucPowerOptionsDealList(ctx, "StreamNucPowerOptionsDealList", getNucPowerOptionsDealListQuery, []interface{}{sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime)}, yield)
+}
+
+func (repo *NucleusTradeRepository) GetNucPowerOptionsDealListByTradeDateRange(ctx context.Context, lastRunTime time.Time, fromTradeDate time.Time, toTradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return repo.getNucPowerOptionsDealList(ctx, "GetNucPowerOptionsDealListByTradeDateRange", getNucDealListByTradeDateRangeQuery(getNucPowerOptionsDealListQuery), sql.Named("fromTradeDate", fromTradeDate), sql.Named("toTradeDate", toTradeDate), sql.Named("lastRunTime", lastRunTime))
+}
+
+func (repo *NucleusTradeRepository) getNucPowerOptionsDealList(ctx context.Context, method string, query string, args ...interface{}) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return collectHeaderModels(func(yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+		return repo.streamNucPowerOptionsDealList(ctx, method, query, args, yield)
+	})
+}
+
+func (repo *NucleusTradeRepository) streamNucPowerOptionsDealList(ctx context.Context, method string, query string, args []interface{}, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", method)
+
+	release, err := repo.acquireDealListStream(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucPowerOptionsDealListQuery: ", err)
+		return err
+	}
+	defer rows.Close()
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+
+	formulaRegex, err := regexp.Compile(formulaPattern)
+	if err != nil {
+		logger.Debugln("error when compiling regex: ", err)
+		return err
+	}
+
+	flush := func() error {
+		for _, headerModel := range headerModels {
+			if err := yield(headerModel); err != nil {
+				return err
+			}
+		}
+
+		headerModels = nil
+
+		return nil
+	}
+
+	for rows.Next() {
+		var headerModel nucleus.NucleusTradeHeaderModel
+		var pvModel nucleus.NucleusTradeTermModel
+
+		var contract, confirmFormat, hsHedgeKey, hasBroker,
+			broker, executionDate, executionTime, exoticFlag,
+			interaffiliateFlag, ibPortfolio, ibUrTrader,
+			settleFormula, strikeFormula sql.NullString
+		var ibPrtPortfolio sql.NullInt32
+		var totalQuantity, fixedPrice sql.NullFloat64
+
+		if err := rows.Scan(
+			&headerModel.DealKey, &headerModel.DealType, &totalQuantity, &headerModel.DnDirection,
+			&headerModel.TransactionDate, &headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName,
+			&headerModel.CompanyCode, &headerModel.LegalEntity, &headerModel.LegalEntityLongName,
+			&headerModel.CyLegalEntityKey, &interaffiliateFlag, &contract,
+			&confirmFormat, &headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio,
+			&headerModel.Portfolio, &headerModel.UrTrader, &ibPrtPortfolio, &ibPortfolio,
+			&ibUrTrader, &headerModel.TzTimeZone, &headerModel.TzExerciseZone, &hasBroker,
+			&broker, &pvModel.Pool1, &pvModel.Product1, &pvModel.PointCode1,
+			&settleFormula, &headerModel.StartDate, &headerModel.EndDate, &pvModel.HolidaySchedule,
+			&pvModel.Volume, &fixedPrice, &pvModel.PriceType, &strikeFormula,
+			&headerModel.CreatedBy, &headerModel.ModifiedBy, &headerModel.CreatedAt, &headerModel.ModifiedAt,
+			&executionDate, &executionTime, &exoticFlag,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return err
+		}
+
+		if totalQuantity.Valid {
+			headerModel.TotalQuantity = totalQuantity.Float64
+		}
+
+		if interaffiliateFlag.Valid {
+			headerModel.InteraffiliateFlag = interaffiliateFlag.String
+		}
+
+		if contract.Valid {
+			headerModel.Contract = contract.String
+		}
+
+		if confirmFormat.Valid {
+			headerModel.ConfirmFormat = confirmFormat.String
+		}
+
+		if hsHedgeKey.Valid {
+			headerModel.HsHedgeKey = hsHedgeKey.String
+		}
+
+		if ibPrtPortfolio.Valid {
+			headerModel.IbPrtPortfolio = int(ibPrtPortfolio.Int32)
+		}
+
+		if ibPortfolio.Valid {
+			headerModel.IbPortfolio = ibPortfolio.String
+		}
+
+		if ibUrTrader.Valid {
+			headerModel.IbUrTrader = ibUrTrader.String
+		}
+
+		if hasBroker.Valid {
+			headerModel.HasBroker = hasBroker.String
+		}
+
+		if broker.Valid {
+			headerModel.Broker = broker.String
+		}
+
+		pvModel.BegDate = headerModel.StartDate
+		pvModel.EndDate = headerModel.EndDate
+		pvModel.VolSeq = 0
+
+		if fixedPrice.Valid {
+			pvModel.FixedPrice = fixedPrice.Float64
+		}
+
+		if exoticFlag.Valid {
+			headerModel.ExoticFlag = exoticFlag.String
+		}
+
+		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
+			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
+			if err != nil {
+				logger.Debugln("error parsing executionDate and executionTime: ", err)
+				return err
+			}
+			headerModel.ExecutionTime = execTime
+		}
+
+		if settleFormula.Valid && settleFormula.String != "" {
+			formula := formulaRegex.FindString(settleFormula.String)
+			formula = strings.ReplaceAll(formula, "[", "")
+			formula = strings.ReplaceAll(formula, "]", "")
+			formulaSections := strings.Split(formula, "|")
+
+			if len(formulaSections) == 3 {
+				var idxModel nucleus.NucleusTradeIndexModel
+				idxModel.Publication = formulaSections[0]
+				idxModel.PubIndex = formulaSections[1]
+				idxModel.Frequency = formulaSections[2]
+				pvModel.Indexes1 = append(pvModel.Indexes1, &idxModel)
+			}
+		}
+
+		if strikeFormula.Valid && strikeFormula.String != "" {
+			formula := formulaRegex.FindString(strikeFormula.String)
+			formula = strings.ReplaceAll(formula, "[", "")
+			formula = strings.ReplaceAll(formula, "]", "")
+			formulaSections := strings.Split(formula, "|")
+
+			if len(formulaSections) == 3 {
+				var idxModel nucleus.NucleusTradeIndexModel
+				idxModel.Publication = formulaSections[0]
+				idxModel.PubIndex = formulaSections[1]
+				idxModel.Frequency = formulaSections[2]
+				pvModel.Indexes2 = append(pvModel.Indexes2, &idxModel)
+			}
+		}
+
+		headerModel.Terms = append(headerModel.Terms, &pvModel)
+		headerModels = append(headerModels, &headerModel)
+
+		if len(headerModels) == dealListStreamBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return err
+	}
+
+	return flush()
+}
+
+func (repo *NucleusTradeRepository) GetNucCapacityDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return repo.getNucCapacityDealList(ctx, "GetNucCapacityDealList", getNucCapacityDealListQuery, sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime))
+}
+
+func (repo *NucleusTradeRepository) StreamNucCapacityDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	return repo.streamNucCapacityDealList(ctx, "StreamNucCapacityDealList", getNucCapacityDealListQuery, []interface{}{sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime)}, yield)
+}
+
+func (repo *NucleusTradeRepository) GetNucCapacityDealListByTradeDateRange(ctx context.Context, lastRunTime time.Time, fromTradeDate time.Time, toTradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return repo.getNucCapacityDealList(ctx, "GetNucCapacityDealListByTradeDateRange", getNucDealListByTradeDateRangeQuery(getNucCapacityDealListQuery), sql.Named("fromTradeDate", fromTradeDate), sql.Named("toTradeDate", toTradeDate), sql.Named("lastRunTime", lastRunTime))
+}
+
+func (repo *NucleusTradeRepository) getNucCapacityDealList(ctx context.Context, method string, query string, args ...interface{}) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return collectHeaderModels(func(yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+		return repo.streamNucCapacityDealList(ctx, method, query, args, yield)
+	})
+}
+
+func (repo *NucleusTradeRepository) streamNucCapacityDealList(ctx context.Context, method string, query string, args []interface{}, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", method)
+
+	release, err := repo.acquireDealListStream(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucCapacityDealListQuery: ", err)
+		return err
+	}
+	defer rows.Close()
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+	headerModelsMap := make(map[int]*nucleus.NucleusTradeHeaderModel)
+	initialTermModelsMap := make(map[int]*nucleus.NucleusTradeTermModel)
+
+	var dealKeys []int
+	var formulaDealKeys []int
+
+	flush := func() error {
+		if len(dealKeys) > 0 {
+			termModelsMap, err := repo.getNucCapacityDealTermModel(ctx, dealKeys)
+			if err != nil {
+				logger.Debugln("error getting getNucCapacityDealTermModel: ", err)
+				return err
+			}
+
+			for dealKey, termModels := range termModelsMap {
+				for _, termModel := range termModels {
+					termModel.PriceType = initialTermModelsMap[dealKey].PriceType
+					termModel.Pool1 = initialTermModelsMap[dealKey].Pool1
+					termModel.Product1 = initialTermModelsMap[dealKey].Product1
+					termModel.PointCode1 = initialTermModelsMap[dealKey].PointCode1
+					termModel.HolidaySchedule = initialTermModelsMap[dealKey].HolidaySchedule
+
+					headerModelsMap[dealKey].Terms = append(headerModelsMap[dealKey].Terms, termModel)
+				}
+			}
+		}
+
+		if len(formulaDealKeys) > 0 {
+			indexModelsMap, err := repo.getNucCapacityDealIndexModel(ctx, formulaDealKeys)
+			if err != nil {
+				logger.Debugln("error getting getNucCapacityDealIndexModel: ", err)
+				return err
+			}
+
+			for dealKey, indexModels := range indexModelsMap {
+				if headerModel, ok := headerModelsMap[dealKey]; ok && len(headerModel.Terms) > 0 {
+					headerModel.Terms[0].Indexes1 = indexModels
+				}
+			}
+		}
+
+		for _, headerModel := range headerModels {
+			if err := yield(headerModel); err != nil {
+				return err
+			}
+		}
+
+		headerModels = nil
+		headerModelsMap = make(map[int]*nucleus.NucleusTradeHeaderModel)
+		initialTermModelsMap = make(map[int]*nucleus.NucleusTradeTermModel)
+		dealKeys = nil
+		formulaDealKeys = nil
+
+		return nil
+	}
+
+	for rows.Next() {
+		var headerModel nucleus.NucleusTradeHeaderModel
+		var pvModel nucleus.NucleusTradeTermModel
+
+		var interaffiliateFlag, contract, confirmFormat,
+			hsHedgeKey, hasBroker, broker, executionDate,
+			executionTime, energyFormula sql.NullString
+		var totalQuantity, charge sql.NullFloat64
+
+		var nonstdFlag string
+
+		if err := rows.Scan(
+			&headerModel.DealKey, &headerModel.DealType, &totalQuantity, &headerModel.DnDirection,
+			&headerModel.TransactionDate, &headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName,
+			&headerModel.CompanyCode, &headerModel.LegalEntity, &headerModel.LegalEntityLongName,
+			&headerModel.CyLegalEntityKey, &interaffiliateFlag, &contract,
+			&confirmFormat, &headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio,
+			&headerModel.Portfolio, &headerModel.UrTrader, &headerModel.TzTimeZone,
+			&hasBroker, &broker, &nonstdFlag, &pvModel.PriceType,
+			&charge, &pvModel.Volume, &energyFormula, &pvModel.Pool1,
+			&pvModel.Product1, &pvModel.PointCode1, &headerModel.StartDate, &headerModel.EndDate, &pvModel.HolidaySchedule,
+			&headerModel.CreatedBy, &headerModel.ModifiedBy, &headerModel.CreatedAt, &headerModel.ModifiedAt,
+			&executionDate, &executionTime,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return err
+		}
+
+		if totalQuantity.Valid {
+			headerModel.TotalQuantity = totalQuantity.Float64
+		}
+
+		if interaffiliateFlag.Valid {
+			headerModel.InteraffiliateFlag = interaffiliateFlag.String
+		}
+
+		if contract.Valid {
+			headerModel.Contract = contract.String
+		}
+
+		if confirmFormat.Valid {
+			headerModel.ConfirmFormat = confirmFormat.String
+		}
+
+		if hsHedgeKey.Valid {
+			headerModel.HsHedgeKey = hsHedgeKey.String
+		}
+
+		if hasBroker.Valid {
+			headerModel.HasBroker = hasBroker.String
+		}
+
+		if broker.Valid {
+			headerModel.Broker = broker.String
+		}
+
+		if charge.Valid {
+			pvModel.FixedPrice = charge.Float64
+		}
+
+		pvModel.BegDate = headerModel.StartDate
+		pvModel.EndDate = headerModel.EndDate
+
+		headerModel.ExoticFlag = "NA"
+
+		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
+			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
+			if err != nil {
+				logger.Debugln("error parsing executionDate and executionTime: ", err)
+				return err
+			}
+			headerModel.ExecutionTime = execTime
+		}
+
+		if energyFormula.Valid {
+			pvModel.Formula1 = energyFormula.String
+			formulaDealKeys = append(formulaDealKeys, headerModel.DealKey)
+		}
+
+		if nonstdFlag == "N" {
+			pvModel.VolSeq = 0
+			headerModel.Terms = append(headerModel.Terms, &pvModel)
+		} else {
+			initialTermModelsMap[headerModel.DealKey] = &pvModel
+			headerModelsMap[headerModel.DealKey] = &headerModel
+			dealKeys = append(dealKeys, headerModel.DealKey)
+		}
+
+		headerModels = append(headerModels, &headerModel)
+
+		if len(headerModels) == dealListStreamBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return err
+	}
+
+	return flush()
+}
+
+func (repo *NucleusTradeRepository) getNucCapacityDealTermModel(ctx context.Context, dealKeys []int) (map[int][]*nucleus.NucleusTradeTermModel, error) {
+	return getTermModelsInChunks(ctx, dealKeys, repo.getNucCapacityDealTermModelChunk)
+}
+
+func (repo *NucleusTradeRepository) getNucCapacityDealTermModelChunk(ctx context.Context, dealKeys []int) (map[int][]*nucleus.NucleusTradeTermModel, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "getNucCapacityDealTermModel")
+
+	if len(dealKeys) == 0 {
+		return nil, nil
+	}
+
+	termModelMap := make(map[int][]*nucleus.NucleusTradeTermModel)
+
+	insNumQuery, params, err := oracle.CreateInQueryInt(dealKeys, []interface{}{}, "cpd_capacity_key")
+	if err != nil {
+		logger.Debugln("error in CreateInQueryInt: ", err)
+		return nil, err
+	}
+
+	query := getNucCapacityDealTermModelQuery(insNumQuery)
+
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucCapacityDealTermModelQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var termModel nucleus.NucleusTradeTermModel
+
+		var dealKey int
+
+		if err := rows.Scan(
+			&dealKey, &termModel.BegDate, &termModel.EndDate,
+		); err != nil {
+			logger.Debugln("error when scanning rows: ", err)
+			return nil, err
+		}
+
+		termModel.VolSeq = len(termModelMap[dealKey])
+		termModelMap[dealKey] = append(termModelMap[dealKey], &termModel)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	return termModelMap, nil
+}
+
+func (repo *NucleusTradeRepository) getNucCapacityDealIndexModel(ctx context.Context, dealKeys []int) (map[int][]*nucleus.NucleusTradeIndexModel, error) {
+	return getIndexModelsInChunks(ctx, dealKeys, repo.getNucCapacityDealIndexModelChunk)
+}
+
+func (repo *NucleusTradeRepository) getNucCapacityDealIndexModelChunk(ctx context.Context, dealKeys []int) (map[int][]*nucleus.NucleusTradeIndexModel, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "getNucCapacityDealIndexModel")
+
+	if len(dealKeys) == 0 {
+		return nil, nil
+	}
+
+	indexModelMap := make(map[int][]*nucleus.NucleusTradeIndexModel)
+
+	insNumQuery, params, err := oracle.CreateInQueryInt(dealKeys, []interface{}{}, "cpd_capacity_key")
+	if err != nil {
+		logger.Debugln("error in CreateInQueryInt: ", err)
+		return nil, err
+	}
+
+	query := getNucCapacityDealIndexModelQuery(insNumQuery)
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucCapacityDealIndexModelQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var indexModel nucleus.NucleusTradeIndexModel
+
+		var dealKey int
+
+		if err := rows.Scan(
+			&dealKey, &indexModel.Publication, &indexModel.PubIndex, &indexModel.Frequency,
+		); err != nil {
+			logger.Debugln("error when scanning rows: ", err)
+			return nil, err
+		}
+
+		indexModel.VolSeq = len(indexModelMap[dealKey])
+		indexModelMap[dealKey] = append(indexModelMap[dealKey], &indexModel)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	return indexModelMap, nil
+}
+
+func (repo *NucleusTradeRepository) GetNucPTPDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return repo.getNucPTPDealList(ctx, "GetNucPTPDealList", getNucPTPDealListQuery, sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime))
+}
+
+func (repo *NucleusTradeRepository) StreamNucPTPDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	return repo.streamNucPTPDealList(ctx, "StreamNucPTPDealList", getNucPTPDealListQuery, []interface{}{sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime)}, yield)
+}
+
+func (repo *NucleusTradeRepository) GetNucPTPDealListByTradeDateRange(ctx context.Context, lastRunTime time.Time, fromTradeDate time.Time, toTradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return repo.getNucPTPDealList(ctx, "GetNucPTPDealListByTradeDateRange", getNucDealListByTradeDateRangeQuery(getNucPTPDealListQuery), sql.Named("fromTradeDate", fromTradeDate), sql.Named("toTradeDate", toTradeDate), sql.Named("lastRunTime", lastRunTime))
+}
+
+func (repo *NucleusTradeRepository) getNucPTPDealList(ctx context.Context, method string, query string, args ...interface{}) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return collectHeaderModels(func(yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+		return repo.streamNucPTPDealList(ctx, method, query, args, yield)
+	})
+}
+
+func (repo *NucleusTradeRepository) streamNucPTPDealList(ctx context.Context, method string, query string, args []interface{}, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", method)
+
+	release, err := repo.acquireDealListStream(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucPTPDealListQuery: ", err)
+		return err
+	}
+	defer rows.Close()
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+
+	flush := func() error {
+		for _, headerModel := range headerModels {
+			if err := yield(headerModel); err != nil {
+				return err
+			}
+		}
+
+		headerModels = nil
+
+		return nil
+	}
+
+	for rows.Next() {
+		var headerModel nucleus.NucleusTradeHeaderModel
+		var pvModel nucleus.NucleusTradeTermModel
+		var idxModel, idxModel2 nucleus.NucleusTradeIndexModel
+
+		var contract, confirmFormat, hsHedgeKey, hasBroker,
+			broker sql.NullString
+
+		if err := rows.Scan(
+			&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection, &headerModel.TransactionDate,
+			&headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName, &headerModel.CompanyCode,
+			&headerModel.LegalEntity, &headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey,
+			&contract, &confirmFormat, &headerModel.Region, &hsHedgeKey,
+			&headerModel.PrtPortfolio, &headerModel.Portfolio, &headerModel.UrTrader, &headerModel.TzTimeZone,
+			&hasBroker, &broker, &pvModel.BegDate, &pvModel.Pool1, &pvModel.Product1,
+			&idxModel.Publication, &idxModel.PubIndex, &idxModel2.Publication, &idxModel2.PubIndex,
+			&headerModel.CreatedBy, &headerModel.ModifiedBy, &headerModel.CreatedAt, &headerModel.ModifiedAt,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return err
+		}
+
+		if contract.Valid {
+			headerModel.Contract = contract.String
+		}
+
+		if confirmFormat.Valid {
+			headerModel.ConfirmFormat = confirmFormat.String
+		}
+
+		if hsHedgeKey.Valid {
+			headerModel.HsHedgeKey = hsHedgeKey.String
+		}
+
+		if hasBroker.Valid {
+			headerModel.HasBroker = hasBroker.String
+		}
+
+		if broker.Valid {
+			headerModel.Broker = broker.String
+		}
+
+		pvModel.EndDate = pvModel.BegDate
+
+		idxModel.Frequency = "HOURLY"
+		idxModel2.Frequency = "HOURLY"
+
+		headerModel.InteraffiliateFlag = "N"
+
+		pvModel.VolSeq = 0
+		pvModel.Indexes1 = append(pvModel.Indexes1, &idxModel)
+		pvModel.Indexes2 = append(pvModel.Indexes2, &idxModel2)
+
+		headerModel.Terms = append(headerModel.Terms, &pvModel)
+
+		headerModels = append(headerModels, &headerModel)
+
+		if len(headerModels) == dealListStreamBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return err
+	}
+
+	return flush()
+}
+
+func (repo *NucleusTradeRepository) GetNucEmissionDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return repo.getNucEmissionDealList(ctx, "GetNucEmissionDealList", getNucEmissionDealListQuery, sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime))
+}
+
+func (repo *NucleusTradeRepository) StreamNucEmissionDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	return repo.streamNucEmissionDealList(ctx, "StreamNucEmissionDealList", getNucEmissionDealListQuery, []interface{}{sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime)}, yield)
+}
+
+func (repo *NucleusTradeRepository) GetNucEmissionDealListByTradeDateRange(ctx context.Context, lastRunTime time.Time, fromTradeDate time.Time, toTradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return repo.getNucEmissionDealList(ctx, "GetNucEmissionDealListByTradeDateRange", getNucDealListByTradeDateRangeQuery(getNucEmissionDealListQuery), sql.Named("fromTradeDate", fromTradeDate), sql.Named("toTradeDate", toTradeDate), sql.Named("lastRunTime", lastRunTime))
+}
+
+func (repo *NucleusTradeRepository) getNucEmissionDealList(ctx context.Context, method string, query string, args ...interface{}) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return collectHeaderModels(func(yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+		return repo.streamNucEmissionDealList(ctx, method, query, args, yield)
+	})
+}
+
+func (repo *NucleusTradeRepository) streamNucEmissionDealList(ctx context.Context, method string, query string, args []interface{}, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", method)
+
+	release, err := repo.acquireDealListStream(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucEmissionDealListQuery: ", err)
+		return err
+	}
+	defer rows.Close()
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+	headerModelsMap := make(map[int]*nucleus.NucleusTradeHeaderModel)
+
+	var dealKeys []int
+
+	flush := func() error {
+		if len(dealKeys) > 0 {
+			termModelsMap, err := repo.getNucEmissionDealListTermModel(ctx, dealKeys)
+			if err != nil {
+				logger.Debugln("error getting getNucEmissionDealListTermModel: ", err)
+				return err
+			}
+
+			for dealKey, termModels := range termModelsMap {
+				headerModelsMap[dealKey].Terms = termModels
+			}
+		}
+
+		for _, headerModel := range headerModels {
+			if err := yield(headerModel); err != nil {
+				return err
+			}
+		}
+
+		headerModels = nil
+		headerModelsMap = make(map[int]*nucleus.NucleusTradeHeaderModel)
+		dealKeys = nil
+
+		return nil
+	}
+
+	for rows.Next() {
+		var headerModel nucleus.NucleusTradeHeaderModel
+
+		var contract, confirmFormat, hsHedgeKey, hasBroker,
+			broker, executionDate, executionTime, tzTimeZone sql.NullString
+
+		if err := rows.Scan(
+			&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection, &headerModel.TransactionDate,
+			&headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName, &headerModel.CompanyCode,
+			&headerModel.LegalEntity, &headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey,
+			&contract, &confirmFormat, &headerModel.Region, &hsHedgeKey,
+			&headerModel.PrtPortfolio, &headerModel.Portfolio, &headerModel.UrTrader, &tzTimeZone,
+			&hasBroker, &broker, &headerModel.CreatedBy, &headerModel.ModifiedBy,
+			&headerModel.CreatedAt, &headerModel.ModifiedAt, &executionDate, &executionTime,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return err
+		}
+
+		if contract.Valid {
+			headerModel.Contract = contract.String
+		}
+
+		if confirmFormat.Valid {
+			headerModel.ConfirmFormat = confirmFormat.String
+		}
+
+		if hsHedgeKey.Valid {
+			headerModel.HsHedgeKey = hsHedgeKey.String
+		}
+
+		if tzTimeZone.Valid {
+			headerModel.TzTimeZone = tzTimeZone.String
+		}
+
+		if hasBroker.Valid {
+			headerModel.HasBroker = hasBroker.String
+		}
+
+		if broker.Valid {
+			headerModel.Broker = broker.String
+		}
+
+		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
+			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
+			if err != nil {
+				logger.Debugln("error parsing executionDate and executionTime: ", err)
+				return err
+			}
+			headerModel.ExecutionTime = execTime
+		}
+
+		headerModel.ExoticFlag = "NA"
+		headerModel.InteraffiliateFlag = "N"
+
+		dealKeys = append(dealKeys, headerModel.DealKey)
+		headerModels = append(headerModels, &headerModel)
+		headerModelsMap[headerModel.DealKey] = &headerModel
+
+		if len(headerModels) == dealListStreamBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return err
+	}
+
+	return flush()
+}
+
+func (repo *NucleusTradeRepository) getNucEmissionDealListTermModel(ctx context.Context, dealKeys []int) (map[int][]*nucleus.NucleusTradeTermModel, error) {
+	return getTermModelsInChunks(ctx, dealKeys, repo.getNucEmissionDealListTermModelChunk)
+}
+
+func (repo *NucleusTradeRepository) getNucEmissionDealListTermModelChunk(ctx context.Context, dealKeys []int) (map[int][]*nucleus.NucleusTradeTermModel, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "getNucEmissionDealListTermModel")
+
+	if len(dealKeys) == 0 {
+		return nil, nil
+	}
+
+	termModelMap := make(map[int][]*nucleus.NucleusTradeTermModel)
+
+	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeys, []interface{}{}, "pv.ed_emission_key")
+	if err != nil {
+		logger.Debugln("error in CreateInQueryInt: ", err)
+		return nil, err
+	}
+
+	query := getNucEmissionDealListTermModelQuery(dealKeysQuery)
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucEmissionDealListTermModelQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	formulaRegex, err := regexp.Compile(formulaPattern)
+	if err != nil {
+		logger.Debugln("error when compiling regex: ", err)
+		return nil, err
+	}
+
+	for rows.Next() {
+		var termModel nucleus.NucleusTradeTermModel
+		var dealKey int
+
+		var ctpPointCode, formula sql.NullString
+
+		if err := rows.Scan(
+			&dealKey, &termModel.VolSeq, &termModel.BegDate, &termModel.EndDate,
+			&termModel.PriceType, &termModel.FixedPrice, &termModel.Volume, &termModel.Product1,
+			&ctpPointCode, &formula,
+		); err != nil {
+			logger.Debugln("error when scanning rows: ", err)
+			return nil, err
+		}
+
+		if ctpPointCode.Valid {
+			termModel.PointCode1 = ctpPointCode.String
+		}
+
+		if formula.Valid && formula.String != "" {
+			termModel.Formula1 = formula.String
+
+			formula := formulaRegex.FindString(formula.String)
+			formula = strings.ReplaceAll(formula, "[", "")
+			formula = strings.ReplaceAll(formula, "]", "")
+			formulaSections := strings.Split(formula, "|")
+
+			if len(formulaSections) == 3 {
+				var idxModel nucleus.NucleusTradeIndexModel
+				idxModel.Publication = formulaSections[0]
+				idxModel.PubIndex = formulaSections[1]
+				idxModel.Frequency = formulaSections[2]
+				termModel.Indexes1 = append(termModel.Indexes1, &idxModel)
+			}
+		}
+
+		termModelMap[dealKey] = append(termModelMap[dealKey], &termModel)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	return termModelMap, nil
+}
+
+func (repo *NucleusTradeRepository) GetNucEmissionOptionDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return repo.getNucEmissionOptionDealList(ctx, "GetNucEmissionOptionDealList", getNucEmissionOptionDealListQuery, sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime))
+}
+
+func (repo *NucleusTradeRepository) StreamNucEmissionOptionDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	return repo.streamNucEmissionOptionDealList(ctx, "StreamNucEmissionOptionDealList", getNucEmissionOptionDealListQuery, []interface{}{sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime)}, yield)
+}
+
+func (repo *NucleusTradeRepository) GetNucEmissionOptionDealListByTradeDateRange(ctx context.Context, lastRunTime time.Time, fromTradeDate time.Time, toTradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return repo.getNucEmissionOptionDealList(ctx, "GetNucEmissionOptionDealListByTradeDateRange", getNucDealListByTradeDateRangeQuery(getNucEmissionOptionDealListQuery), sql.Named("fromTradeDate", fromTradeDate), sql.Named("toTradeDate", toTradeDate), sql.Named("lastRunTime", lastRunTime))
+}
+
+func (repo *NucleusTradeRepository) getNucEmissionOptionDealList(ctx context.Context, method string, query string, args ...interface{}) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return collectHeaderModels(func(yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+		return repo.streamNucEmissionOptionDealList(ctx, method, query, args, yield)
+	})
+}
+
+func (repo *NucleusTradeRepository) streamNucEmissionOptionDealList(ctx context.Context, method string, query string, args []interface{}, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", method)
+
+	release, err := repo.acquireDealListStream(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucEmissionOptionDealListQuery: ", err)
+		return err
+	}
+	defer rows.Close()
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+
+	var emissionKeys []int
+
+	flush := func() error {
+		if len(emissionKeys) > 0 {
+			termModelsMap, err := repo.getNucEmissionOptionDealTermList(ctx, emissionKeys)
+			if err != nil {
+				logger.Debugln("error getting getNucEmissionOptionDealTermList: ", err)
+				return err
+			}
+
+			for _, headerModel := range headerModels {
+				emissionKey := headerModel.Terms[0].VolSeq
+
+				var modelTerms []*nucleus.NucleusTradeTermModel
+				for _, termModel := range termModelsMap[emissionKey] {
+					termModel.FixedPrice = headerModel.Terms[0].FixedPrice
+					termModel.Volume = headerModel.Terms[0].Volume
+					modelTerms = append(modelTerms, termModel)
+				}
+				headerModel.Terms = modelTerms
+			}
+		}
+
+		for _, headerModel := range headerModels {
+			if err := yield(headerModel); err != nil {
+				return err
+			}
+		}
+
+		headerModels = nil
+		emissionKeys = nil
+
+		return nil
+	}
+
+	for rows.Next() {
+		var headerModel nucleus.NucleusTradeHeaderModel
+		var pvModel nucleus.NucleusTradeTermModel
+
+		var emissionKey int
+
+		var contract, confirmFormat, hsHedgeKey, hasBroker,
+			broker, executionDate, executionTime, tzTimeZone sql.NullString
+
+		if err := rows.Scan(
+			&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection, &headerModel.TransactionDate,
+			&headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName, &headerModel.CompanyCode,
+			&headerModel.LegalEntity, &headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey,
+			&contract, &confirmFormat, &headerModel.Region, &hsHedgeKey,
+			&headerModel.PrtPortfolio, &headerModel.Portfolio, &headerModel.UrTrader, &tzTimeZone,
+			&hasBroker, &broker, &emissionKey, &pvModel.FixedPrice,
+			&pvModel.Volume, &headerModel.CreatedBy, &headerModel.ModifiedBy, &headerModel.CreatedAt,
+			&headerModel.ModifiedAt, &executionDate, &executionTime,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return err
+		}
+
+		if contract.Valid {
+			headerModel.Contract = contract.String
+		}
+
+		if confirmFormat.Valid {
+			headerModel.ConfirmFormat = confirmFormat.String
+		}
+
+		if hsHedgeKey.Valid {
+			headerModel.HsHedgeKey = hsHedgeKey.String
+		}
+
+		if tzTimeZone.Valid {
+			headerModel.TzTimeZone = tzTimeZone.String
+		}
+
+		if hasBroker.Valid {
+			headerModel.HasBroker = hasBroker.String
+		}
+
+		if broker.Valid {
+			headerModel.Broker = broker.String
+		}
+
+		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
+			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
+			if err != nil {
+				logger.Debugln("error parsing executionDate and executionTime: ", err)
+				return err
+			}
+			headerModel.ExecutionTime = execTime
+		}
+
+		headerModel.ExoticFlag = "NA"
+		headerModel.InteraffiliateFlag = "N"
+
+		pvModel.VolSeq = emissionKey
+
+		headerModel.Terms = append(headerModel.Terms, &pvModel)
+		emissionKeys = append(emissionKeys, emissionKey)
+		headerModels = append(headerModels, &headerModel)
+
+		if len(headerModels) == dealListStreamBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return err
+	}
+
+	return flush()
+}
+
+func (repo *NucleusTradeRepository) getNucEmissionOptionDealTermList(ctx context.Context, emissionKeys []int) (map[int][]*nucleus.NucleusTradeTermModel, error) {
+	return getTermModelsInChunks(ctx, emissionKeys, repo.getNucEmissionOptionDealTermListChunk)
+}
+
+func (repo *NucleusTradeRepository) getNucEmissionOptionDealTermListChunk(ctx context.Context, emissionKeys []int) (map[int][]*nucleus.NucleusTradeTermModel, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "getNucEmissionOptionDealTermLists")
+
+	if len(emissionKeys) == 0 {
+		return nil, nil
+	}
+
+	termModelMap := make(map[int][]*nucleus.NucleusTradeTermModel)
+
+	emissionKeysQuery, params, err := oracle.CreateInQueryInt(emissionKeys, []interface{}{}, "pv.ed_emission_key")
+	if err != nil {
+		logger.Debugln("error in CreateInQueryInt: ", err)
+		return nil, err
+	}
+
+	query := getNucEmissionOptionDealTermListQuery(emissionKeysQuery)
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucEmissionOptionDealTermListQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var termModel nucleus.NucleusTradeTermModel
+		var emissionKey int
+
+		var ctpPointCode sql.NullString
+
+		if err := rows.Scan(
+			&emissionKey, &termModel.VolSeq, &termModel.BegDate, &termModel.EndDate,
+			&ctpPointCode, &termModel.Product1,
+		); err != nil {
+			logger.Debugln("error when scanning rows: ", err)
+			return nil, err
+		}
+
+		if ctpPointCode.Valid {
+			termModel.PointCode1 = ctpPointCode.String
+		}
+
+		termModelMap[emissionKey] = append(termModelMap[emissionKey], &termModel)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	return termModelMap, nil
+}
+
+func (repo *NucleusTradeRepository) GetNucSpreadOptionsDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return repo.getNucSpreadOptionsDealList(ctx, "GetNucSpreadOptionsDealList", getNucSpreadOptionsDealListQuery, sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime))
+}
+
+func (repo *NucleusTradeRepository) StreamNucSpreadOptionsDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	return repo.streamNucSpreadOptionsDealList(ctx, "StreamNucSpreadOptionsDealList", getNucSpreadOptionsDealListQuery, []interface{}{sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime)}, yield)
+}
+
+func (repo *NucleusTradeRepository) GetNucSpreadOptionsDealListByTradeDateRange(ctx context.Context, lastRunTime time.Time, fromTradeDate time.Time, toTradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return repo.getNucSpreadOptionsDealList(ctx, "GetNucSpreadOptionsDealListByTradeDateRange", getNucDealListByTradeDateRangeQuery(getNucSpreadOptionsDealListQuery), sql.Named("fromTradeDate", fromTradeDate), sql.Named("toTradeDate", toTradeDate), sql.Named("lastRunTime", lastRunTime))
+}
+
+func (repo *NucleusTradeRepository) getNucSpreadOptionsDealList(ctx context.Context, method string, query string, args ...interface{}) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return collectHeaderModels(func(yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+		return repo.streamNucSpreadOptionsDealList(ctx, method, query, args, yield)
+	})
+}
+
+func (repo *NucleusTradeRepository) streamNucSpreadOptionsDealList(ctx context.Context, method string, query string, args []interface{}, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", method)
+
+	release, err := repo.acquireDealListStream(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucSpreadOptionsDealListQuery: ", err)
+		return err
+	}
+	defer rows.Close()
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+
+	formulaRegex, err := regexp.Compile(formulaPattern)
+	if err != nil {
+		logger.Debugln("error when compiling regex: ", err)
+		return err
+	}
+
+	flush := func() error {
+		for _, headerModel := range headerModels {
+			if err := yield(headerModel); err != nil {
+				return err
+			}
+		}
+
+		headerModels = nil
+
+		return nil
+	}
+
+	for rows.Next() {
+		var headerModel nucleus.NucleusTradeHeaderModel
+		var pvModel nucleus.NucleusTradeTermModel
+
+		var contract, confirmFormat, hsHedgeKey, hasBroker, broker, executionDate,
+			executionTime, tzTimeZone, ibPortfolio, ibUrTrader, formula1, formula2,
+			pool1, pool2, product1, product2, exoticFlag, pointCode1 sql.NullString
+		var ibPrtPortfolio sql.NullInt32
+		var fixedPrice sql.NullFloat64
+
+		if err := rows.Scan(
+			&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection, &headerModel.TransactionDate,
+			&headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName, &headerModel.CompanyCode,
+			&headerModel.LegalEntity, &headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey,
+			&contract, &confirmFormat, &headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio,
+			&headerModel.Portfolio, &headerModel.UrTrader, &ibPrtPortfolio, &ibPortfolio,
+			&ibUrTrader, &tzTimeZone, &hasBroker, &broker, &headerModel.StartDate, &headerModel.EndDate,
+			&pvModel.HolidaySchedule, &formula1, &formula2, &pool1, &pool2, &product1, &product2,
+			&pvModel.Volume, &fixedPrice, &headerModel.CreatedBy, &headerModel.ModifiedBy, &headerModel.CreatedAt,
+			&headerModel.ModifiedAt, &executionDate, &executionTime, &exoticFlag, &pointCode1,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return err
+		}
+
+		if contract.Valid {
+			headerModel.Contract = contract.String
+		}
+
+		if confirmFormat.Valid {
+			headerModel.ConfirmFormat = confirmFormat.String
+		}
+
+		if hsHedgeKey.Valid {
+			headerModel.HsHedgeKey = hsHedgeKey.String
+		}
+
+		if ibPrtPortfolio.Valid {
+			headerModel.IbPrtPortfolio = int(ibPrtPortfolio.Int32)
+		}
+
+		if ibPortfolio.Valid {
+			headerModel.IbPortfolio = ibPortfolio.String
+		}
+
+		if ibUrTrader.Valid {
+			headerModel.IbUrTrader = ibUrTrader.String
+		}
+
+		if tzTimeZone.Valid {
+			headerModel.TzTimeZone = tzTimeZone.String
+		}
+
+		if hasBroker.Valid {
+			headerModel.HasBroker = hasBroker.String
+		}
+
+		if broker.Valid {
+			headerModel.Broker = broker.String
+		}
+
+		if pool1.Valid {
+			pvModel.Pool1 = pool1.String
+		}
+
+		if pool2.Valid {
+			pvModel.Pool2 = pool2.String
+		}
+
+		if product1.Valid {
+			pvModel.Product1 = product1.String
+		}
+
+		if product2.Valid {
+			pvModel.Product2 = product2.String
+		}
+
+		if fixedPrice.Valid {
+			pvModel.FixedPrice = fixedPrice.Float64
+		}
+
+		if exoticFlag.Valid {
+			headerModel.ExoticFlag = exoticFlag.String
+		}
+
+		if pointCode1.Valid {
+			pvModel.PointCode1 = pointCode1.String
+		}
+
+		headerModel.InteraffiliateFlag = "N"
+
+		pvModel.VolSeq = 0
+		pvModel.BegDate = headerModel.StartDate
+		pvModel.EndDate = headerModel.EndDate
+
+		if formula1.Valid && formula1.String != "" {
+			formula := formulaRegex.FindString(formula1.String)
+			formula = strings.ReplaceAll(formula, "[", "")
+			formula = strings.ReplaceAll(formula, "]", "")
+			formulaSections := strings.Split(formula, "|")
+
+			if len(formulaSections) == 3 {
+				var idxModel nucleus.NucleusTradeIndexModel
+				idxModel.Publication = formulaSections[0]
+				idxModel.PubIndex = formulaSections[1]
+				idxModel.Frequency = formulaSections[2]
+				pvModel.Indexes1 = append(pvModel.Indexes1, &idxModel)
+			}
+		}
+
+		if formula2.Valid && formula2.String != "" {
+			formula := formulaRegex.FindString(formula2.String)
+			formula = strings.ReplaceAll(formula, "[", "")
+			formula = strings.ReplaceAll(formula, "]", "")
+			formulaSections := strings.Split(formula, "|")
+
+			if len(formulaSections) == 3 {
+				var idxModel nucleus.NucleusTradeIndexModel
+				idxModel.Publication = formulaSections[0]
+				idxModel.PubIndex = formulaSections[1]
+				idxModel.Frequency = formulaSections[2]
+				pvModel.Indexes2 = append(pvModel.Indexes2, &idxModel)
+			}
+		}
+
+		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
+			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
+			if err != nil {
+				logger.Debugln("error parsing executionDate and executionTime: ", err)
+				return err
+			}
+			headerModel.ExecutionTime = execTime
+		}
+
+		headerModel.Terms = append(headerModel.Terms, &pvModel)
+		headerModels = append(headerModels, &headerModel)
+
+		if len(headerModels) == dealListStreamBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return err
+	}
+
+	return flush()
+}
+
+func (repo *NucleusTradeRepository) GetNucHeatRateSwapsDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return repo.getNucHeatRateSwapsDealList(ctx, "GetNucHeatRateSwapsDealList", getNucHeatRateSwapsDealListQuery, sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime))
+}
+
+func (repo *NucleusTradeRepository) StreamNucHeatRateSwapsDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	return repo.streamNucHeatRateSwapsDealList(ctx, "StreamNucHeatRateSwapsDealList", getNucHeatRateSwapsDealListQuery, []interface{}{sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime)}, yield)
+}
+
+func (repo *NucleusTradeRepository) GetNucHeatRateSwapsDealListByTradeDateRange(ctx context.Context, lastRunTime time.Time, fromTradeDate time.Time, toTradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return repo.getNucHeatRateSwapsDealList(ctx, "GetNucHeatRateSwapsDealListByTradeDateRange", getNucDealListByTradeDateRangeQuery(getNucHeatRateSwapsDealListQuery), sql.Named("fromTradeDate", fromTradeDate), sql.Named("toTradeDate", toTradeDate), sql.Named("lastRunTime", lastRunTime))
+}
+
+func (repo *NucleusTradeRepository) getNucHeatRateSwapsDealList(ctx context.Context, method string, query string, args ...interface{}) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return collectHeaderModels(func(yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+		return repo.streamNucHeatRateSwapsDealList(ctx, method, query, args, yield)
+	})
+}
+
+func (repo *NucleusTradeRepository) streamNucHeatRateSwapsDealList(ctx context.Context, method string, query string, args []interface{}, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", method)
+
+	release, err := repo.acquireDealListStream(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucHeatRateSwapsDealListQuery: ", err)
+		return err
+	}
+	defer rows.Close()
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+
+	flush := func() error {
+		for _, headerModel := range headerModels {
+			if err := yield(headerModel); err != nil {
+				return err
+			}
+		}
+
+		headerModels = nil
+
+		return nil
+	}
+
+	for rows.Next() {
+		var headerModel nucleus.NucleusTradeHeaderModel
+		var pvModel nucleus.NucleusTradeTermModel
+		var idxModel, idxModel2 nucleus.NucleusTradeIndexModel
+
+		var contract, confirmFormat, hsHedgeKey, hasBroker, broker, executionDate,
+			executionTime, tzTimeZone, interaffiliateFlag, publication1, pubIndex1,
+			publication2, pubIndex2, frequency2 sql.NullString
+		var exercisedOptionKey sql.NullInt32
+		var totalQuantity, volume sql.NullFloat64
+
+		if err := rows.Scan(
+			&headerModel.DealKey, &headerModel.DealType, &totalQuantity, &headerModel.DnDirection,
+			&headerModel.TransactionDate, &headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName,
+			&headerModel.CompanyCode, &headerModel.LegalEntity, &headerModel.LegalEntityLongName,
+			&headerModel.CyLegalEntityKey, &interaffiliateFlag, &contract, &confirmFormat,
+			&headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio, &headerModel.Portfolio,
+			&headerModel.UrTrader, &tzTimeZone, &hasBroker, &broker, &exercisedOptionKey,
+			&pvModel.Pool1, &pvModel.Product1, &publication1, &pubIndex1, &publication2,
+			&pubIndex2, &frequency2, &headerModel.StartDate, &headerModel.EndDate,
+			&pvModel.HolidaySchedule, &volume, &headerModel.CreatedBy, &headerModel.ModifiedBy,
+			&headerModel.CreatedAt, &headerModel.ModifiedAt, &executionDate, &executionTime,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return err
+		}
+
+		if totalQuantity.Valid {
+			headerModel.TotalQuantity = totalQuantity.Float64
+		}
+
+		if interaffiliateFlag.Valid {
+			headerModel.InteraffiliateFlag = interaffiliateFlag.String
+		}
+
+		if contract.Valid {
+			headerModel.Contract = contract.String
+		}
+
+		if confirmFormat.Valid {
+			headerModel.ConfirmFormat = confirmFormat.String
+		}
+
+		if hsHedgeKey.Valid {
+			headerModel.HsHedgeKey = hsHedgeKey.String
+		}
+
+		if tzTimeZone.Valid {
+			headerModel.TzTimeZone = tzTimeZone.String
+		}
+
+		if hasBroker.Valid {
+			headerModel.HasBroker = hasBroker.String
+		}
+
+		if broker.Valid {
+			headerModel.Broker = broker.String
+		}
+
+		if exercisedOptionKey.Valid {
+			headerModel.ExercisedOptionKey = int(exercisedOptionKey.Int32)
+		}
+
+		if publication1.Valid {
+			idxModel.Publication = publication1.String
+		}
+
+		if pubIndex1.Valid {
+			idxModel.PubIndex = pubIndex1.String
+		}
+
+		if publication2.Valid {
+			idxModel2.Publication = publication2.String
+		}
+
+		if pubIndex2.Valid {
+			idxModel2.PubIndex = pubIndex2.String
+		}
+
+		if frequency2.Valid {
+			idxModel2.Frequency = frequency2.String
+		}
+
+		pvModel.Indexes1 = append(pvModel.Indexes1, &idxModel)
+		pvModel.Indexes2 = append(pvModel.Indexes2, &idxModel2)
+		pvModel.Indexes2 = append(pvModel.Indexes2, &idxModel2)
+
+		if volume.Valid {
+			pvModel.Volume = volume.Float64
+		}
+
+		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
+			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
+			if err != nil {
+				logger.Debugln("error parsing executionDate and executionTime: ", err)
+				return err
+			}
+			headerModel.ExecutionTime = execTime
+		}
+
+		headerModel.ExoticFlag = "NA"
+		pvModel.VolSeq = 0
+
+		pvModel.BegDate = headerModel.StartDate
+		pvModel.EndDate = headerModel.EndDate
+
+		headerModel.Terms = append(headerModel.Terms, &pvModel)
+		headerModels = append(headerModels, &headerModel)
+
+		if len(headerModels) == dealListStreamBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return err
+	}
+
+	return flush()
+}
+
+func (repo *NucleusTradeRepository) GetNucTCCFTRSDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time, strDealType string) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return repo.getNucTCCFTRSDealList(ctx, "GetNucTCCFTRSDealList", getNucTCCFTRSDealListQuery, sql.Named("tradeDate", tradeDate), sql.Named("strDealType", strDealType), sql.Named("lastRunTime", lastRunTime))
+}
+
+func (repo *NucleusTradeRepository) StreamNucTCCFTRSDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time, strDealType string, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	return repo.streamNucTCCFTRSDealList(ctx, "StreamNucTCCFTRSDealList", getNucTCCFTRSDealListQuery, []interface{}{sql.Named("tradeDate", tradeDate), sql.Named("strDealType", strDealType), sql.Named("lastRunTime", lastRunTime)}, yield)
+}
+
+func (repo *NucleusTradeRepository) GetNucTCCFTRSDealListByTradeDateRange(ctx context.Context, lastRunTime time.Time, fromTradeDate time.Time, toTradeDate time.Time, strDealType string) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return repo.getNucTCCFTRSDealList(ctx, "GetNucTCCFTRSDealListByTradeDateRange", getNucDealListByTradeDateRangeQuery(getNucTCCFTRSDealListQuery), sql.Named("fromTradeDate", fromTradeDate), sql.Named("toTradeDate", toTradeDate), sql.Named("strDealType", strDealType), sql.Named("lastRunTime", lastRunTime))
+}
+
+func (repo *NucleusTradeRepository) getNucTCCFTRSDealList(ctx context.Context, method string, query string, args ...interface{}) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return collectHeaderModels(func(yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+		return repo.streamNucTCCFTRSDealList(ctx, method, query, args, yield)
+	})
+}
+
+func (repo *NucleusTradeRepository) streamNucTCCFTRSDealList(ctx context.Context, method string, query string, args []interface{}, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", method)
+
+	release, err := repo.acquireDealListStream(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucTCCFTRSDealListQuery: ", err)
+		return err
+	}
+	defer rows.Close()
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+
+	flush := func() error {
+		for _, headerModel := range headerModels {
+			if err := yield(headerModel); err != nil {
+				return err
+			}
+		}
+
+		headerModels = nil
+
+		return nil
+	}
+
+	for rows.Next() {
+		var headerModel nucleus.NucleusTradeHeaderModel
+		var pvModel nucleus.NucleusTradeTermModel
+		var idxModel, idxModel2 nucleus.NucleusTradeIndexModel
+
+		var contract, confirmFormat, hsHedgeKey, hasBroker, broker sql.NullString
+		var volume, fixedPrice sql.NullFloat64
+
+		if err := rows.Scan(
+			&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection, &headerModel.TransactionDate,
+			&headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName, &headerModel.CompanyCode,
+			&headerModel.LegalEntity, &headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey,
+			&contract, &confirmFormat, &headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio,
+			&headerModel.Portfolio, &headerModel.UrTrader, &headerModel.TzTimeZone, &hasBroker, &broker,
+			&pvModel.BegDate, &pvModel.EndDate, &pvModel.HolidaySchedule, &volume,
+			&fixedPrice, &pvModel.Pool1, &pvModel.Product1, &idxModel.Publication,
+			&idxModel.Frequency, &idxModel.PubIndex, &idxModel2.PubIndex, &headerModel.CreatedBy,
+			&headerModel.ModifiedBy, &headerModel.CreatedAt, &headerModel.ModifiedAt,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return err
+		}
+
+		if contract.Valid {
+			headerModel.Contract = contract.String
+		}
+
+		if confirmFormat.Valid {
+			headerModel.ConfirmFormat = confirmFormat.String
+		}
+
+		if hsHedgeKey.Valid {
+			headerModel.HsHedgeKey = hsHedgeKey.String
+		}
+
+		if hasBroker.Valid {
+			headerModel.HasBroker = hasBroker.String
+		}
+
+		if broker.Valid {
+			headerModel.Broker = broker.String
+		}
+
+		if volume.Valid {
+			pvModel.Volume = volume.Float64
+		}
+
+		if fixedPrice.Valid {
+			pvModel.FixedPrice = fixedPrice.Float64
+		}
+
+		idxModel2.Publication = idxModel.Publication
+		idxModel2.Frequency = idxModel.Frequency
+
+		headerModel.ExoticFlag = "NA"
+		headerModel.InteraffiliateFlag = "N"
+
+		pvModel.Indexes1 = append(pvModel.Indexes1, &idxModel)
+		pvModel.Indexes2 = append(pvModel.Indexes2, &idxModel2)
+		pvModel.VolSeq = 0
+
+		headerModel.Terms = append(headerModel.Terms, &pvModel)
+		headerModels = append(headerModels, &headerModel)
+
+		if len(headerModels) == dealListStreamBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return err
+	}
+
+	return flush()
+}
+
+func (repo *NucleusTradeRepository) GetNucTransmissionDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return repo.getNucTransmissionDealList(ctx, "GetNucTransmissionDealList", getNucTransmissionDealListQuery, sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime))
+}
+
+func (repo *NucleusTradeRepository) StreamNucTransmissionDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	return repo.streamNucTransmissionDealList(ctx, "StreamNucTransmissionDealList", getNucTransmissionDealListQuery, []interface{}{sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime)}, yield)
+}
+
+func (repo *NucleusTradeRepository) GetNucTransmissionDealListByTradeDateRange(ctx context.Context, lastRunTime time.Time, fromTradeDate time.Time, toTradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return repo.getNucTransmissionDealList(ctx, "GetNucTransmissionDealListByTradeDateRange", getNucDealListByTradeDateRangeQuery(getNucTransmissionDealListQuery), sql.Named("fromTradeDate", fromTradeDate), sql.Named("toTradeDate", toTradeDate), sql.Named("lastRunTime", lastRunTime))
+}
+
+func (repo *NucleusTradeRepository) getNucTransmissionDealList(ctx context.Context, method string, query string, args ...interface{}) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return collectHeaderModels(func(yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+		return repo.streamNucTransmissionDealList(ctx, method, query, args, yield)
+	})
+}
+
+func (repo *NucleusTradeRepository) streamNucTransmissionDealList(ctx context.Context, method string, query string, args []interface{}, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", method)
+
+	release, err := repo.acquireDealListStream(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucTransmissionDealListQuery: ", err)
+		return err
+	}
+	defer rows.Close()
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+	headerModelsMap := make(map[int]*nucleus.NucleusTradeHeaderModel)
+
+	var dealKeys []int
+
+	flush := func() error {
+		if len(dealKeys) > 0 {
+			termModelsMap, err := repo.getNucTransmissionDealTermModel(ctx, dealKeys)
+			if err != nil {
+				logger.Debugln("error getting getNucTransmissionDealTermModel: ", err)
+				return err
+			}
+
+			for dealKey, termModels := range termModelsMap {
+				headerModelsMap[dealKey].Terms = termModels
+			}
+		}
+
+		for _, headerModel := range headerModels {
+			if err := yield(headerModel); err != nil {
+				return err
+			}
+		}
+
+		headerModels = nil
+		headerModelsMap = make(map[int]*nucleus.NucleusTradeHeaderModel)
+		dealKeys = nil
+
+		return nil
+	}
+
+	for rows.Next() {
+		var headerModel nucleus.NucleusTradeHeaderModel
+		var contract, confirmFormat, hsHedgeKey, hasBroker, broker, executionTime sql.NullString
+
+		if err := rows.Scan(
+			&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection, &headerModel.TransactionDate,
+			&headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName, &headerModel.CompanyCode,
+			&headerModel.LegalEntity, &headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey,
+			&contract, &confirmFormat, &headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio,
+			&headerModel.Portfolio, &headerModel.UrTrader, &headerModel.TzTimeZone, &hasBroker,
+			&broker, &headerModel.CreatedBy, &headerModel.ModifiedBy, &headerModel.CreatedAt,
+			&headerModel.ModifiedAt, &executionTime,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return err
+		}
+
+		if contract.Valid {
+			headerModel.Contract = contract.String
+		}
+
+		if confirmFormat.Valid {
+			headerModel.ConfirmFormat = confirmFormat.String
+		}
+
+		if hsHedgeKey.Valid {
+			headerModel.HsHedgeKey = hsHedgeKey.String
+		}
+
+		if hasBroker.Valid {
+			headerModel.HasBroker = hasBroker.String
+		}
+
+		if broker.Valid {
+			headerModel.Broker = broker.String
+		}
+
+		if executionTime.Valid {
+			time, err := parseExecutionDate(executionTime.String)
+			if err != nil {
+				logger.Debugln("error parsing executionDate and executionTime: ", err)
+				return err
+			}
+
+			headerModel.ExecutionTime = time
+		}
+
+		headerModel.ExoticFlag = "NA"
+		headerModel.InteraffiliateFlag = "N"
+
+		dealKeys = append(dealKeys, headerModel.DealKey)
+		headerModelsMap[headerModel.DealKey] = &headerModel
+		headerModels = append(headerModels, &headerModel)
+
+		if len(headerModels) == dealListStreamBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return err
+	}
+
+	return flush()
+}
+
+func (repo *NucleusTradeRepository) getNucTransmissionDealTermModel(ctx context.Context, dealKeys []int) (map[int][]*nucleus.NucleusTradeTermModel, error) {
+	return getTermModelsInChunks(ctx, dealKeys, repo.getNucTransmissionDealTermModelChunk)
+}
+
+func (repo *NucleusTradeRepository) getNucTransmissionDealTermModelChunk(ctx context.Context, dealKeys []int) (map[int][]*nucleus.NucleusTradeTermModel, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "getNucTransmissionDealTermModel")
+
+	if len(dealKeys) == 0 {
+		return nil, nil
+	}
+
+	termModels := make(map[int][]*nucleus.NucleusTradeTermModel)
+
+	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeys, []interface{}{}, "pv.td_trans_key")
+	if err != nil {
+		logger.Debugln("error in CreateInQueryInt: ", err)
+		return nil, err
+	}
+
+	query := getNucTransmissionDealTermListQuery(dealKeysQuery)
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucTransmissionDealTermListQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var termModel nucleus.NucleusTradeTermModel
+		var dealKey int
+
+		if err := rows.Scan(
+			&dealKey, &termModel.VolSeq, &termModel.BegDate, &termModel.EndDate,
+			&termModel.Volume, &termModel.Product1, &termModel.Pool1, &termModel.PointCode1,
+			&termModel.Pool2, &termModel.PointCode2, &termModel.HolidaySchedule,
+		); err != nil {
+			logger.Debugln("error when scanning rows: ", err)
+			return nil, err
+		}
+
+		termModel.PriceType = "F"
+
+		termModels[dealKey] = append(termModels[dealKey], &termModel)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	return termModels, nil
+}
+
+func (repo *NucleusTradeRepository) GetNucMiscChargeDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return repo.getNucMiscChargeDealList(ctx, "GetNucMiscChargeDealList", getNucMiscChargeDealListQuery, sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime))
+}
+
+func (repo *NucleusTradeRepository) StreamNucMiscChargeDealList(ctx context.Context, lastRunTime time.Time, tradeDate time.Time, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	return repo.streamNucMiscChargeDealList(ctx, "StreamNucMiscChargeDealList", getNucMiscChargeDealListQuery, []interface{}{sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime)}, yield)
+}
+
+func (repo *NucleusTradeRepository) GetNucMiscChargeDealListByTradeDateRange(ctx context.Context, lastRunTime time.Time, fromTradeDate time.Time, toTradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return repo.getNucMiscChargeDealList(ctx, "GetNucMiscChargeDealListByTradeDateRange", getNucDealListByTradeDateRangeQuery(getNucMiscChargeDealListQuery), sql.Named("fromTradeDate", fromTradeDate), sql.Named("toTradeDate", toTradeDate), sql.Named("lastRunTime", lastRunTime))
+}
+
+func (repo *NucleusTradeRepository) getNucMiscChargeDealList(ctx context.Context, method string, query string, args ...interface{}) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return collectHeaderModels(func(yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+		return repo.streamNucMiscChargeDealList(ctx, method, query, args, yield)
+	})
+}
+
+func (repo *NucleusTradeRepository) streamNucMiscChargeDealList(ctx context.Context, method string, query string, args []interface{}, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", method)
+
+	release, err := repo.acquireDealListStream(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucMiscChargeDealListQuery: ", err)
+		return err
+	}
+	defer rows.Close()
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+	headerModelsMap := make(map[int]*nucleus.NucleusTradeHeaderModel)
+
+	var dealKeys []int
+
+	flush := func() error {
+		if len(dealKeys) > 0 {
+			termModelsMap, err := repo.getNucMiscChargeDealTermList(ctx, dealKeys)
+			if err != nil {
+				logger.Debugln("error getting getNucMiscChargeDealTermList: ", err)
+				return err
+			}
+
+			for dealKey, termModels := range termModelsMap {
+				headerModelsMap[dealKey].Terms = termModels
+			}
+		}
+
+		for _, headerModel := range headerModels {
+			if err := yield(headerModel); err != nil {
+				return err
+			}
+		}
+
+		headerModels = nil
+		headerModelsMap = make(map[int]*nucleus.NucleusTradeHeaderModel)
+		dealKeys = nil
+
+		return nil
+	}
+
+	for rows.Next() {
+		var headerModel nucleus.NucleusTradeHeaderModel
+		var contract, confirmFormat, hsHedgeKey, hasBroker, broker, region, tzTimeZone sql.NullString
+
+		if err := rows.Scan(
+			&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection, &headerModel.TransactionDate,
+			&headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName, &headerModel.CompanyCode,
+			&headerModel.LegalEntity, &headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey,
+			&contract, &confirmFormat, &region, &hsHedgeKey, &headerModel.PrtPortfolio,
+			&headerModel.Portfolio, &headerModel.UrTrader, &tzTimeZone, &hasBroker, &broker,
+			&headerModel.CreatedBy, &headerModel.ModifiedBy, &headerModel.CreatedAt, &headerModel.ModifiedAt,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return err
+		}
+
+		if contract.Valid {
+			headerModel.Contract = contract.String
+		}
+
+		if confirmFormat.Valid {
+			headerModel.ConfirmFormat = confirmFormat.String
+		}
+
+		if region.Valid {
+			headerModel.Region = region.String
+		}
+
+		if hsHedgeKey.Valid {
+			headerModel.HsHedgeKey = hsHedgeKey.String
+		}
+
+		if tzTimeZone.Valid {
+			headerModel.TzTimeZone = tzTimeZone.String
+		}
+
+		if hasBroker.Valid {
+			headerModel.HasBroker = hasBroker.String
+		}
+
+		if broker.Valid {
+			headerModel.Broker = broker.String
+		}
+
+		headerModel.InteraffiliateFlag = "N"
+
+		dealKeys = append(dealKeys, headerModel.DealKey)
+		headerModelsMap[headerModel.DealKey] = &headerModel
+		headerModels = append(headerModels, &headerModel)
+
+		if len(headerModels) == dealListStreamBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return err
+	}
+
+	return flush()
+}
+
+func (repo *NucleusTradeRepository) getNucMiscChargeDealTermList(ctx context.Context, dealKeys []int) (map[int][]*nucleus.NucleusTradeTermModel, error) {
+	return getTermModelsInChunks(ctx, dealKeys, repo.getNucMiscChargeDealTermListChunk)
+}
+
+func (repo *NucleusTradeRepository) getNucMiscChargeDealTermListChunk(ctx context.Context, dealKeys []int) (map[int][]*nucleus.NucleusTradeTermModel, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "getNucMiscChargeDealTermList")
+
+	if len(dealKeys) == 0 {
+		return nil, nil
+	}
+
+	termModels := make(map[int][]*nucleus.NucleusTradeTermModel)
+
+	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeys, []interface{}{}, "pv.mc_misc_charge_key")
+	if err != nil {
+		logger.Debugln("error in CreateInQueryInt: ", err)
+		return nil, err
+	}
+
+	query := getNucMiscChargeDealTermListQuery(dealKeysQuery)
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucMiscChargeDealTermListQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var termModel nucleus.NucleusTradeTermModel
+		var dealKey int
+
+		if err := rows.Scan(
+			&dealKey, &termModel.VolSeq, &termModel.BegDate, &termModel.EndDate,
+			&termModel.Volume,
+		); err != nil {
+			logger.Debugln("error when scanning rows: ", err)
+			return nil, err
+		}
+
+		termModels[dealKey] = append(termModels[dealKey], &termModel)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	return termModels, nil
+}
+
+func (repo *NucleusTradeRepository) GetNucPowerDealByKeys(ctx context.Context, lstPowerkeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return getHeaderModelsByKeysInChunks(ctx, lstPowerkeys, repo.getNucPowerDealByKeysChunk)
+}
+
+func (repo *NucleusTradeRepository) getNucPowerDealByKeysChunk(ctx context.Context, lstPowerkeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "GetNucPowerDealByKeys")
+
+	dealKeysQuery, params, err := oracle.CreateInQueryFloat64(lstPowerkeys, []interface{}{}, "pd.power_key")
+	if err != nil {
+		logger.Debugln("error in CreateInQueryFloat64: ", err)
+		return nil, err
+	}
+
+	query := getNucPowerDealByKeysQuery(dealKeysQuery)
+
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucPowerDealByKeys: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+	headerModelsMap := make(map[int]*nucleus.NucleusTradeHeaderModel)
+	var dealKeys []int
+
+	for rows.Next() {
+		var headerModel nucleus.NucleusTradeHeaderModel
+
+		var contract, confirmFormat, hsHedgeKey, hasBroker,
+			broker, executionDate, executionTime, exoticFlag sql.NullString
+		var optionKey sql.NullInt32
+
+		if err := rows.Scan(&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection,
+			&headerModel.TransactionDate, &headerModel.CyCompanyKey, &headerModel.Company,
+			&headerModel.CompanyLongName, &headerModel.CompanyCode, &headerModel.LegalEntity,
+			&headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey, &contract,
+			&confirmFormat, &headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio,
+			&headerModel.Portfolio, &headerModel.UrTrader, &headerModel.TzTimeZone,
+			&hasBroker, &broker, &optionKey, &headerModel.CreatedBy,
+			&headerModel.CreatedAt, &headerModel.ModifiedBy, &headerModel.ModifiedAt,
+			&executionDate, &executionTime, &exoticFlag,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return nil, err
+		}
+
+		if contract.Valid {
+			headerModel.Contract = contract.String
+		}
+
+		if confirmFormat.Valid {
+			headerModel.ConfirmFormat = confirmFormat.String
+		}
+
+		if hsHedgeKey.Valid {
+			headerModel.HsHedgeKey = hsHedgeKey.String
+		}
+
+		if hasBroker.Valid {
+			headerModel.HasBroker = hasBroker.String
+		}
+
+		if broker.Valid {
+			headerModel.Broker = broker.String
+		}
+
+		if optionKey.Valid {
+			headerModel.ExercisedOptionKey = int(optionKey.Int32)
+		}
+
+		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
+			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
+			if err != nil {
+				logger.Debugln("error parsing executionDate and executionTime: ", err)
+				return nil, err
+			}
+			headerModel.ExecutionTime = execTime
+		}
+
+		if exoticFlag.Valid {
+			headerModel.ExoticFlag = exoticFlag.String
+		}
+
+		headerModel.InteraffiliateFlag = "N"
+
+		dealKeys = append(dealKeys, headerModel.DealKey)
+		headerModelsMap[headerModel.DealKey] = &headerModel
+		headerModels = append(headerModels, &headerModel)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	if len(dealKeys) > 0 {
+		termModelsMap, err := repo.getNucPowerDealTradeTermModel(ctx, dealKeys)
+		if err != nil {
+			logger.Debugln("error getting getNucPowerDealTradeTermModel: ", err)
+			return nil, err
+		}
+
+		for index := range headerModelsMap {
+			headerModelsMap[index].Terms = termModelsMap[index]
+		}
+	}
+
+	return headerModels, nil
+}
+
+func (repo *NucleusTradeRepository) GetNucPowerSwapDealByKeys(ctx context.Context, lstPowerSwapkeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return getHeaderModelsByKeysInChunks(ctx, lstPowerSwapkeys, repo.getNucPowerSwapDealByKeysChunk)
+}
+
+func (repo *NucleusTradeRepository) getNucPowerSwapDealByKeysChunk(ctx context.Context, lstPowerSwapkeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "GetNucPowerSwapDealByKeys")
+
+	dealKeysQuery, params, err := oracle.CreateInQueryFloat64(lstPowerSwapkeys, []interface{}{}, "pd.pswap_key")
+	if err != nil {
+		logger.Debugln("error in CreateInQueryFloat64: ", err)
+		return nil, err
+	}
+
+	query := getNucPowerSwapDealByKeysQuery(dealKeysQuery)
+
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucPowerSwapDealByKeys: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+	headerModelsMap := make(map[int]*nucleus.NucleusTradeHeaderModel)
+	initialTermModelsMap := make(map[int]*nucleus.NucleusTradeTermModel)
+
+	var dealKeys []int
+
+	for rows.Next() {
+		var headerModel nucleus.NucleusTradeHeaderModel
+		var pvModel nucleus.NucleusTradeTermModel
+		var idxModel, idxModel2 nucleus.NucleusTradeIndexModel
+
+		var interaffiliateFlag, contract, confirmFormat,
+			hsHedgeKey, ibPortfolio, ibUrTrader, hasBroker,
+			broker, executionDate, executionTime, exoticFlag sql.NullString
+		var totalQuantity, fixedPrice sql.NullFloat64
+		var ibPrtPortfolio, optionKey sql.NullInt32
+
+		var nonstdFlag string
+
+		var fixPiPbPublication, fixPiPubIndex, fixFrqFrequency sql.NullString
+
+		if err := rows.Scan(
+			&headerModel.DealKey, &headerModel.DealType, &totalQuantity, &headerModel.DnDirection,
+			&headerModel.TransactionDate, &headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName,
+			&headerModel.CompanyCode, &headerModel.LegalEntity, &headerModel.LegalEntityLongName,
+			&headerModel.CyLegalEntityKey, &interaffiliateFlag, &contract,
+			&confirmFormat, &headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio,
+			&headerModel.Portfolio, &headerModel.UrTrader, &ibPrtPortfolio,
+			&ibPortfolio, &ibUrTrader, &headerModel.TzTimeZone, &hasBroker,
+			&broker, &optionKey, &pvModel.Pool1, &pvModel.Product1,
+			&pvModel.Volume, &fixedPrice, &nonstdFlag, &idxModel.Publication,
+			&idxModel.PubIndex, &idxModel.Frequency, &fixPiPbPublication,
+			&fixPiPubIndex, &fixFrqFrequency, &headerModel.StartDate, &headerModel.EndDate,
+			&pvModel.HolidaySchedule, &headerModel.CreatedBy, &headerModel.CreatedAt, &headerModel.ModifiedBy,
+			&headerModel.ModifiedAt, &executionDate, &executionTime, &exoticFlag,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return nil, err
+		}
+
+		if totalQuantity.Valid {
+			headerModel.TotalQuantity = totalQuantity.Float64
+		}
+
+		if interaffiliateFlag.Valid {
+			headerModel.InteraffiliateFlag = interaffiliateFlag.String
+		}
+
+		if contract.Valid {
+			headerModel.Contract = contract.String
+		}
+
+		if confirmFormat.Valid {
+			headerModel.ConfirmFormat = confirmFormat.String
+		}
+
+		if hsHedgeKey.Valid {
+			headerModel.HsHedgeKey = hsHedgeKey.String
+		}
+
+		if ibPrtPortfolio.Valid {
+			headerModel.IbPrtPortfolio = int(ibPrtPortfolio.Int32)
+		}
+
+		if ibPortfolio.Valid {
+			headerModel.IbPortfolio = ibPortfolio.String
+		}
+
+		if ibUrTrader.Valid {
+			headerModel.IbUrTrader = ibUrTrader.String
+		}
+
+		if hasBroker.Valid {
+			headerModel.HasBroker = hasBroker.String
+		}
+
+		if broker.Valid {
+			headerModel.Broker = broker.String
+		}
+
+		if optionKey.Valid {
+			headerModel.ExercisedOptionKey = int(optionKey.Int32)
+		}
+
+		if fixedPrice.Valid {
+			pvModel.FixedPrice = fixedPrice.Float64
+		}
+
+		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
+			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
+			if err != nil {
+				logger.Debugln("error parsing executionDate and executionTime: ", err)
+				return nil, err
+			}
+			headerModel.ExecutionTime = execTime
+		}
+
+		if exoticFlag.Valid {
+			headerModel.ExoticFlag = exoticFlag.String
+		}
+
+		if fixPiPbPublication.Valid && fixPiPubIndex.Valid && fixFrqFrequency.Valid {
+			idxModel2.Publication = fixPiPbPublication.String
+			idxModel2.PubIndex = fixPiPubIndex.String
+			idxModel2.Frequency = fixFrqFrequency.String
+			pvModel.Indexes2 = append(pvModel.Indexes2, &idxModel2)
+		}
+
+		pvModel.BegDate = headerModel.StartDate
+		pvModel.EndDate = headerModel.EndDate
+		pvModel.Indexes1 = append(pvModel.Indexes1, &idxModel)
+
+		if nonstdFlag == "N" {
+			pvModel.VolSeq = 0
+			headerModel.Terms = append(headerModel.Terms, &pvModel)
+		} else {
+			initialTermModelsMap[headerModel.DealKey] = &pvModel
+			headerModelsMap[headerModel.DealKey] = &headerModel
+			dealKeys = append(dealKeys, headerModel.DealKey)
+		}
+
+		headerModels = append(headerModels, &headerModel)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	if len(dealKeys) > 0 {
+		termModelsMap, err := repo.getNucPowerSwapDealTermModel(ctx, dealKeys)
+		if err != nil {
+			logger.Debugln("error getting getNucPowerSwapDealTermModel: ", err)
+			return nil, err
+		}
+
+		for dealKey, termModels := range termModelsMap {
+			for _, termModel := range termModels {
+				termModel.Pool1 = initialTermModelsMap[dealKey].Pool1
+				termModel.Product1 = initialTermModelsMap[dealKey].Product1
+				termModel.Indexes1 = initialTermModelsMap[dealKey].Indexes1
+				termModel.Indexes2 = initialTermModelsMap[dealKey].Indexes2
+				termModel.HolidaySchedule = initialTermModelsMap[dealKey].HolidaySchedule
+
+				headerModelsMap[dealKey].Terms = append(headerModelsMap[dealKey].Terms, termModel)
+			}
+		}
+	}
+
+	return headerModels, nil
+}
+
+func (repo *NucleusTradeRepository) GetNucPowerOptionsDealByKeys(ctx context.Context, lstPowerOptionkeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return getHeaderModelsByKeysInChunks(ctx, lstPowerOptionkeys, repo.getNucPowerOptionsDealByKeysChunk)
+}
+
+func (repo *NucleusTradeRepository) getNucPowerOptionsDealByKeysChunk(ctx context.Context, lstPowerOptionkeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "GetNucPowerOptionsDealByKeys")
+
+	dealKeysQuery, params, err := oracle.CreateInQueryFloat64(lstPowerOptionkeys, []interface{}{}, "pd.poption_key")
+	if err != nil {
+		logger.Debugln("error in CreateInQueryFloat64: ", err)
+		return nil, err
+	}
+
+	query := getNucPowerOptionsDealByKeysQuery(dealKeysQuery)
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucPowerOptionsDealByKeysQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+
+	formulaRegex, err := regexp.Compile(formulaPattern)
+	if err != nil {
+		logger.Debugln("error when compiling regex: ", err)
+		return nil, err
+	}
+
+	for rows.Next() {
+		var headerModel nucleus.NucleusTradeHeaderModel
+		var pvModel nucleus.NucleusTradeTermModel
+
+		var company, companyLongName, companyCode, legalEntity, legalEntityLongName,
+			contract, confirmFormat, hsHedgeKey, hasBroker, broker, executionDate,
+			executionTime, exoticFlag, interaffiliateFlag, region, portfolio, ibPortfolio,
+			ibUrTrader, settleFormula, strikeFormula sql.NullString
+		var ibPrtPortfolio sql.NullInt32
+		var totalQuantity, fixedPrice sql.NullFloat64
+
+		if err := rows.Scan(
+			&headerModel.DealKey, &headerModel.DealType, &totalQuantity, &headerModel.DnDirection,
+			&headerModel.TransactionDate, &headerModel.CyCompanyKey, &company, &companyLongName,
+			&companyCode, &legalEntity, &legalEntityLongName,
+			&headerModel.CyLegalEntityKey, &interaffiliateFlag, &contract,
+			&confirmFormat, &region, &hsHedgeKey, &headerModel.PrtPortfolio,
+			&portfolio, &headerModel.UrTrader, &ibPrtPortfolio, &ibPortfolio,
+			&ibUrTrader, &headerModel.TzTimeZone, &headerModel.TzExerciseZone, &hasBroker,
+			&broker, &pvModel.Pool1, &pvModel.Product1, &pvModel.PointCode1,
+			&settleFormula, &headerModel.StartDate, &headerModel.EndDate, &pvModel.HolidaySchedule,
+			&pvModel.Volume, &fixedPrice, &pvModel.PriceType, &strikeFormula,
+			&headerModel.CreatedBy, &headerModel.ModifiedBy, &headerModel.CreatedAt, &headerModel.ModifiedAt,
+			&executionDate, &executionTime, &exoticFlag,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return nil, err
+		}
+
+		if totalQuantity.Valid {
+			headerModel.TotalQuantity = totalQuantity.Float64
+		}
+
+		if company.Valid {
+			headerModel.Company = company.String
+		}
+
+		if companyLongName.Valid {
+			headerModel.CompanyLongName = companyLongName.String
+		}
+
+		if companyCode.Valid {
+			headerModel.CompanyCode = companyCode.String
+		}
+
+		if legalEntity.Valid {
+			headerModel.LegalEntity = legalEntity.String
+		}
+
+		if legalEntityLongName.Valid {
+			headerModel.LegalEntityLongName = legalEntityLongName.String
+		}
+
+		if interaffiliateFlag.Valid {
+			headerModel.InteraffiliateFlag = interaffiliateFlag.String
+		}
+
+		if contract.Valid {
+			headerModel.Contract = contract.String
+		}
+
+		if confirmFormat.Valid {
+			headerModel.ConfirmFormat = confirmFormat.String
+		}
+
+		if region.Valid {
+			headerModel.Region = region.String
+		}
+
+		if hsHedgeKey.Valid {
+			headerModel.HsHedgeKey = hsHedgeKey.String
+		}
+
+		if portfolio.Valid {
+			headerModel.Portfolio = portfolio.String
+		}
+
+		if ibPrtPortfolio.Valid {
+			headerModel.IbPrtPortfolio = int(ibPrtPortfolio.Int32)
+		}
+
+		if ibPortfolio.Valid {
+			headerModel.IbPortfolio = ibPortfolio.String
+		}
+
+		if ibUrTrader.Valid {
+			headerModel.IbUrTrader = ibUrTrader.String
+		}
+
+		if hasBroker.Valid {
+			headerModel.HasBroker = hasBroker.String
+		}
+
+		if broker.Valid {
+			headerModel.Broker = broker.String
+		}
+
+		pvModel.BegDate = headerModel.StartDate
+		pvModel.EndDate = headerModel.EndDate
+		pvModel.VolSeq = 0
+
+		if fixedPrice.Valid {
+			pvModel.FixedPrice = fixedPrice.Float64
+		}
+
+		if exoticFlag.Valid {
+			headerModel.ExoticFlag = exoticFlag.String
+		}
+
+		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
+			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
+			if err != nil {
+				logger.Debugln("error parsing executionDate and executionTime: ", err)
+				return nil, err
+			}
+			headerModel.ExecutionTime = execTime
+		}
+
+		if settleFormula.Valid && settleFormula.String != "" {
+			formula := formulaRegex.FindString(settleFormula.String)
+			formula = strings.ReplaceAll(formula, "[", "")
+			formula = strings.ReplaceAll(formula, "]", "")
+			formulaSections := strings.Split(formula, "|")
+
+			if len(formulaSections) == 3 {
+				var idxModel nucleus.NucleusTradeIndexModel
+				idxModel.Publication = formulaSections[0]
+				idxModel.PubIndex = formulaSections[1]
+				idxModel.Frequency = formulaSections[2]
+				pvModel.Indexes1 = append(pvModel.Indexes1, &idxModel)
+			}
+		}
+
+		if strikeFormula.Valid && strikeFormula.String != "" {
+			formula := formulaRegex.FindString(strikeFormula.String)
+			formula = strings.ReplaceAll(formula, "[", "")
+			formula = strings.ReplaceAll(formula, "]", "")
+			formulaSections := strings.Split(formula, "|")
+
+			if len(formulaSections) == 3 {
+				var idxModel nucleus.NucleusTradeIndexModel
+				idxModel.Publication = formulaSections[0]
+				idxModel.PubIndex = formulaSections[1]
+				idxModel.Frequency = formulaSections[2]
+				pvModel.Indexes2 = append(pvModel.Indexes2, &idxModel)
+			}
+		}
+
+		headerModel.Terms = append(headerModel.Terms, &pvModel)
+		headerModels = append(headerModels, &headerModel)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	return headerModels, nil
+}
+
+func (repo *NucleusTradeRepository) GetNucCapacityDealByKeys(ctx context.Context, lstCapacitykeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return getHeaderModelsByKeysInChunks(ctx, lstCapacitykeys, repo.getNucCapacityDealByKeysChunk)
+}
+
+func (repo *NucleusTradeRepository) getNucCapacityDealByKeysChunk(ctx context.Context, lstCapacitykeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "GetNucCapacityDealByKeys")
+
+	dealKeysQuery, params, err := oracle.CreateInQueryFloat64(lstCapacitykeys, []interface{}{}, "pd.capacity_key")
+	if err != nil {
+		logger.Debugln("error in CreateInQueryFloat64: ", err)
+		return nil, err
+	}
+
+	query := getNucCapacityDealByKeysQuery(dealKeysQuery)
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucCapacityDealByKeysQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+	headerModelsMap := make(map[int]*nucleus.NucleusTradeHeaderModel)
+	initialTermModelsMap := make(map[int]*nucleus.NucleusTradeTermModel)
+
+	var dealKeys []int
+	var formulaDealKeys []int
+
+	for rows.Next() {
+		var headerModel nucleus.NucleusTradeHeaderModel
+		var pvModel nucleus.NucleusTradeTermModel
+
+		var interaffiliateFlag, contract, confirmFormat,
+			hsHedgeKey, hasBroker, broker, executionDate,
+			executionTime, energyFormula sql.NullString
+		var totalQuantity, charge sql.NullFloat64
+
+		var nonstdFlag string
+
+		if err := rows.Scan(
+			&headerModel.DealKey, &headerModel.DealType, &totalQuantity, &headerModel.DnDirection,
+			&headerModel.TransactionDate, &headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName,
+			&headerModel.CompanyCode, &headerModel.LegalEntity, &headerModel.LegalEntityLongName,
+			&headerModel.CyLegalEntityKey, &interaffiliateFlag, &contract,
+			&confirmFormat, &headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio,
+			&headerModel.Portfolio, &headerModel.UrTrader, &headerModel.TzTimeZone,
+			&hasBroker, &broker, &nonstdFlag, &pvModel.PriceType,
+			&charge, &pvModel.Volume, &energyFormula, &pvModel.Pool1,
+			&pvModel.Product1, &pvModel.PointCode1, &headerModel.StartDate, &headerModel.EndDate, &pvModel.HolidaySchedule,
+			&headerModel.CreatedBy, &headerModel.ModifiedBy, &headerModel.CreatedAt, &headerModel.ModifiedAt,
+			&executionDate, &executionTime,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return nil, err
+		}
+
+		if totalQuantity.Valid {
+			headerModel.TotalQuantity = totalQuantity.Float64
+		}
+
+		if interaffiliateFlag.Valid {
+			headerModel.InteraffiliateFlag = interaffiliateFlag.String
+		}
+
+		if contract.Valid {
+			headerModel.Contract = contract.String
+		}
+
+		if confirmFormat.Valid {
+			headerModel.ConfirmFormat = confirmFormat.String
+		}
+
+		if hsHedgeKey.Valid {
+			headerModel.HsHedgeKey = hsHedgeKey.String
+		}
+
+		if hasBroker.Valid {
+			headerModel.HasBroker = hasBroker.String
+		}
+
+		if broker.Valid {
+			headerModel.Broker = broker.String
+		}
+
+		if charge.Valid {
+			pvModel.FixedPrice = charge.Float64
+		}
+
+		pvModel.BegDate = headerModel.StartDate
+		pvModel.EndDate = headerModel.EndDate
+
+		headerModel.ExoticFlag = "NA"
+
+		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
+			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
+			if err != nil {
+				logger.Debugln("error parsing executionDate and executionTime: ", err)
+				return nil, err
+			}
+			headerModel.ExecutionTime = execTime
+		}
+
+		if energyFormula.Valid {
+			pvModel.Formula1 = energyFormula.String
+			formulaDealKeys = append(formulaDealKeys, headerModel.DealKey)
+		}
+
+		if nonstdFlag == "N" {
+			pvModel.VolSeq = 0
+			headerModel.Terms = append(headerModel.Terms, &pvModel)
+		} else {
+			initialTermModelsMap[headerModel.DealKey] = &pvModel
+			dealKeys = append(dealKeys, headerModel.DealKey)
+		}
+
+		// every header is indexed so that standard deals with an energy
+		// formula can also receive their indexes below
+		headerModelsMap[headerModel.DealKey] = &headerModel
+		headerModels = append(headerModels, &headerModel)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	if len(dealKeys) > 0 {
+		termModelsMap, err := repo.getNucCapacityDealTermModel(ctx, dealKeys)
+		if err != nil {
+			logger.Debugln("error getting getNucCapacityDealTermModel: ", err)
+			return nil, err
+		}
+
+		for dealKey, termModels := range termModelsMap {
+			for _, termModel := range termModels {
+				termModel.PriceType = initialTermModelsMap[dealKey].PriceType
+				termModel.Pool1 = initialTermModelsMap[dealKey].Pool1
+				termModel.Product1 = initialTermModelsMap[dealKey].Product1
+				termModel.PointCode1 = initialTermModelsMap[dealKey].PointCode1
+				termModel.HolidaySchedule = initialTermModelsMap[dealKey].HolidaySchedule
+
+				headerModelsMap[dealKey].Terms = append(headerModelsMap[dealKey].Terms, termModel)
+			}
+		}
+	}
+
+	if len(formulaDealKeys) > 0 {
+		indexModelsMap, err := repo.getNucCapacityDealIndexModel(ctx, formulaDealKeys)
+		if err != nil {
+			logger.Debugln("error getting getNucCapacityDealIndexModel: ", err)
+			return nil, err
+		}
+
+		for dealKey, indexModels := range indexModelsMap {
+			if headerModel, ok := headerModelsMap[dealKey]; ok && len(headerModel.Terms) > 0 {
+				headerModel.Terms[0].Indexes1 = indexModels
+			}
+		}
+	}
+
+	return headerModels, nil
+}
+
+func (repo *NucleusTradeRepository) GetNucPTPDealByKeys(ctx context.Context, lstPTPkeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return getHeaderModelsByKeysInChunks(ctx, lstPTPkeys, repo.getNucPTPDealByKeysChunk)
+}
+
+func (repo *NucleusTradeRepository) getNucPTPDealByKeysChunk(ctx context.Context, lstPTPkeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "GetNucPTPDealByKeys")
+
+	dealKeysQuery, params, err := oracle.CreateInQueryFloat64(lstPTPkeys, []interface{}{}, "pd.ptp_key")
+	if err != nil {
+		logger.Debugln("error in CreateInQueryFloat64: ", err)
+		return nil, err
+	}
+
+	query := getNucPTPDealByKeysQuery(dealKeysQuery)
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucPTPDealByKeysQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+
+	for rows.Next() {
+		var headerModel nucleus.NucleusTradeHeaderModel
+		var pvModel nucleus.NucleusTradeTermModel
+		var idxModel, idxModel2 nucleus.NucleusTradeIndexModel
+
+		var contract, confirmFormat, hsHedgeKey, hasBroker,
+			broker sql.NullString
+
+		if err := rows.Scan(
+			&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection, &headerModel.TransactionDate,
+			&headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName, &headerModel.CompanyCode,
+			&headerModel.LegalEntity, &headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey,
+			&contract, &confirmFormat, &headerModel.Region, &hsHedgeKey,
+			&headerModel.PrtPortfolio, &headerModel.Portfolio, &headerModel.UrTrader, &headerModel.TzTimeZone,
+			&hasBroker, &broker, &pvModel.BegDate, &pvModel.Pool1, &pvModel.Product1,
+			&idxModel.Publication, &idxModel.PubIndex, &idxModel2.Publication, &idxModel2.PubIndex,
+			&headerModel.CreatedBy, &headerModel.ModifiedBy, &headerModel.CreatedAt, &headerModel.ModifiedAt,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return nil, err
+		}
+
+		if contract.Valid {
+			headerModel.Contract = contract.String
+		}
+
+		if confirmFormat.Valid {
+			headerModel.ConfirmFormat = confirmFormat.String
+		}
+
+		if hsHedgeKey.Valid {
+			headerModel.HsHedgeKey = hsHedgeKey.String
+		}
+
+		if hasBroker.Valid {
+			headerModel.HasBroker = hasBroker.String
+		}
+
+		if broker.Valid {
+			headerModel.Broker = broker.String
+		}
+
+		pvModel.EndDate = pvModel.BegDate
+
+		idxModel.Frequency = "HOURLY"
+		idxModel2.Frequency = "HOURLY"
+
+		headerModel.InteraffiliateFlag = "N"
+
+		pvModel.VolSeq = 0
+		pvModel.Indexes1 = append(pvModel.Indexes1, &idxModel)
+		pvModel.Indexes2 = append(pvModel.Indexes2, &idxModel2)
+
+		headerModel.Terms = append(headerModel.Terms, &pvModel)
+
+		headerModels = append(headerModels, &headerModel)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	return headerModels, nil
+}
+
+func (repo *NucleusTradeRepository) GetNucEmissionDealByKeys(ctx context.Context, lstEmissionkeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return getHeaderModelsByKeysInChunks(ctx, lstEmissionkeys, repo.getNucEmissionDealByKeysChunk)
+}
+
+func (repo *NucleusTradeRepository) getNucEmissionDealByKeysChunk(ctx context.Context, lstEmissionkeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "GetNucEmissionDealByKeys")
+
+	dealKeysQuery, params, err := oracle.CreateInQueryFloat64(lstEmissionkeys, []interface{}{}, "pd.emission_key")
+	if err != nil {
+		logger.Debugln("error in CreateInQueryFloat64: ", err)
+		return nil, err
+	}
+
+	query := getNucEmissionDealByKeysQuery(dealKeysQuery)
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucEmissionDealByKeysQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+	headerModelsMap := make(map[int]*nucleus.NucleusTradeHeaderModel)
+
+	var dealKeys []int
+
+	for rows.Next() {
+		var headerModel nucleus.NucleusTradeHeaderModel
+
+		var contract, confirmFormat, hsHedgeKey, hasBroker,
+			broker, executionDate, executionTime, tzTimeZone sql.NullString
+
+		if err := rows.Scan(
+			&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection, &headerModel.TransactionDate,
+			&headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName, &headerModel.CompanyCode,
+			&headerModel.LegalEntity, &headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey,
+			&contract, &confirmFormat, &headerModel.Region, &hsHedgeKey,
+			&headerModel.PrtPortfolio, &headerModel.Portfolio, &headerModel.UrTrader, &tzTimeZone,
+			&hasBroker, &broker, &headerModel.CreatedBy, &headerModel.ModifiedBy,
+			&headerModel.CreatedAt, &headerModel.ModifiedAt, &executionDate, &executionTime,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return nil, err
+		}
+
+		if contract.Valid {
+			headerModel.Contract = contract.String
+		}
+
+		if confirmFormat.Valid {
+			headerModel.ConfirmFormat = confirmFormat.String
+		}
+
+		if hsHedgeKey.Valid {
+			headerModel.HsHedgeKey = hsHedgeKey.String
+		}
+
+		if tzTimeZone.Valid {
+			headerModel.TzTimeZone = tzTimeZone.String
+		}
+
+		if hasBroker.Valid {
+			headerModel.HasBroker = hasBroker.String
+		}
+
+		if broker.Valid {
+			headerModel.Broker = broker.String
+		}
+
+		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
+			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
+			if err != nil {
+				logger.Debugln("error parsing executionDate and executionTime: ", err)
+				return nil, err
+			}
+			headerModel.ExecutionTime = execTime
+		}
+
+		headerModel.ExoticFlag = "NA"
+		headerModel.InteraffiliateFlag = "N"
+
+		dealKeys = append(dealKeys, headerModel.DealKey)
+		headerModels = append(headerModels, &headerModel)
+		headerModelsMap[headerModel.DealKey] = &headerModel
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	if len(dealKeys) > 0 {
+		termModelsMap, err := repo.getNucEmissionDealListTermModel(ctx, dealKeys)
+		if err != nil {
+			logger.Debugln("error getting getNucEmissionDealListTermModel: ", err)
+			return nil, err
+		}
+
+		for dealKey, termModels := range termModelsMap {
+			headerModelsMap[dealKey].Terms = termModels
+		}
+	}
+
+	return headerModels, nil
+}
+
+func (repo *NucleusTradeRepository) GetNucEmissionOptionDealByKeys(ctx context.Context, lstEmissionkeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return getHeaderModelsByKeysInChunks(ctx, lstEmissionkeys, repo.getNucEmissionOptionDealByKeysChunk)
+}
+
+func (repo *NucleusTradeRepository) getNucEmissionOptionDealByKeysChunk(ctx context.Context, lstEmissionkeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "GetNucEmissionOptionDealByKeys")
+
+	dealKeysQuery, params, err := oracle.CreateInQueryFloat64(lstEmissionkeys, []interface{}{}, "pd.eoption_key")
+	if err != nil {
+		logger.Debugln("error in CreateInQueryFloat64: ", err)
+		return nil, err
+	}
+
+	query := getNucEmissionOptionDealByKeysQuery(dealKeysQuery)
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucEmissionOptionDealByKeysQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+
+	var emissionKeys []int
+
+	for rows.Next() {
+		var headerModel nucleus.NucleusTradeHeaderModel
+		var pvModel nucleus.NucleusTradeTermModel
+
+		var emissionKey int
+
+		var contract, confirmFormat, hsHedgeKey, hasBroker,
+			broker, executionDate, executionTime, tzTimeZone sql.NullString
+
+		if err := rows.Scan(
+			&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection, &headerModel.TransactionDate,
+			&headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName, &headerModel.CompanyCode,
+			&headerModel.LegalEntity, &headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey,
+			&contract, &confirmFormat, &headerModel.Region, &hsHedgeKey,
+			&headerModel.PrtPortfolio, &headerModel.Portfolio, &headerModel.UrTrader, &tzTimeZone,
+			&hasBroker, &broker, &emissionKey, &pvModel.FixedPrice,
+			&pvModel.Volume, &headerModel.CreatedBy, &headerModel.ModifiedBy, &headerModel.CreatedAt,
+			&headerModel.ModifiedAt, &executionDate, &executionTime,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return nil, err
+		}
+
+		if contract.Valid {
+			headerModel.Contract = contract.String
+		}
+
+		if confirmFormat.Valid {
+			headerModel.ConfirmFormat = confirmFormat.String
+		}
+
+		if hsHedgeKey.Valid {
+			headerModel.HsHedgeKey = hsHedgeKey.String
+		}
+
+		if tzTimeZone.Valid {
+			headerModel.TzTimeZone = tzTimeZone.String
+		}
+
+		if hasBroker.Valid {
+			headerModel.HasBroker = hasBroker.String
+		}
+
+		if broker.Valid {
+			headerModel.Broker = broker.String
+		}
+
+		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
+			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
+			if err != nil {
+				logger.Debugln("error parsing executionDate and executionTime: ", err)
+				return nil, err
+			}
+			headerModel.ExecutionTime = execTime
+		}
+
+		headerModel.ExoticFlag = "NA"
+		headerModel.InteraffiliateFlag = "N"
+
+		pvModel.VolSeq = emissionKey
+
+		headerModel.Terms = append(headerModel.Terms, &pvModel)
+		emissionKeys = append(emissionKeys, emissionKey)
+		headerModels = append(headerModels, &headerModel)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	if len(emissionKeys) > 0 {
+		termModelsMap, err := repo.getNucEmissionOptionDealTermList(ctx, emissionKeys)
+		if err != nil {
+			logger.Debugln("error getting getNucEmissionOptionDealTermList: ", err)
+			return nil, err
+		}
+
+		for _, headerModel := range headerModels {
+			emissionKey := headerModel.Terms[0].VolSeq
+
+			var modelTerms []*nucleus.NucleusTradeTermModel
+			for _, termModel := range termModelsMap[emissionKey] {
+				termModel.FixedPrice = headerModel.Terms[0].FixedPrice
+				termModel.Volume = headerModel.Terms[0].Volume
+				modelTerms = append(modelTerms, termModel)
+			}
+			headerModel.Terms = modelTerms
+		}
+	}
+
+	return headerModels, nil
+}
+
+func (repo *NucleusTradeRepository) GetNucSpreadOptionsDealByKeys(ctx context.Context, lstSpreadOptionkeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return getHeaderModelsByKeysInChunks(ctx, lstSpreadOptionkeys, repo.getNucSpreadOptionsDealByKeysChunk)
+}
+
+func (repo *NucleusTradeRepository) getNucSpreadOptionsDealByKeysChunk(ctx context.Context, lstSpreadOptionkeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "GetNucSpreadOptionsDealByKeys")
+
+	dealKeysQuery, params, err := oracle.CreateInQueryFloat64(lstSpreadOptionkeys, []interface{}{}, "pd.spread_option_key")
+	if err != nil {
+		logger.Debugln("error in CreateInQueryFloat64: ", err)
+		return nil, err
+	}
+
+	query := getNucSpreadOptionsDealByKeysQuery(dealKeysQuery)
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucSpreadOptionsDealByKeysQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+
+	formulaRegex, err := regexp.Compile(formulaPattern)
+	if err != nil {
+		logger.Debugln("error when compiling regex: ", err)
+		return nil, err
+	}
+
+	for rows.Next() {
+		var headerModel nucleus.NucleusTradeHeaderModel
+		var pvModel nucleus.NucleusTradeTermModel
+
+		var contract, confirmFormat, hsHedgeKey, hasBroker, broker, executionDate,
+			executionTime, tzTimeZone, ibPortfolio, ibUrTrader, formula1, formula2,
+			pool1, pool2, product1, product2, exoticFlag, pointCode1 sql.NullString
+		var ibPrtPortfolio sql.NullInt32
+		var fixedPrice sql.NullFloat64
+
+		if err := rows.Scan(
+			&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection, &headerModel.TransactionDate,
+			&headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName, &headerModel.CompanyCode,
+			&headerModel.LegalEntity, &headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey,
+			&contract, &confirmFormat, &headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio,
+			&headerModel.Portfolio, &headerModel.UrTrader, &ibPrtPortfolio, &ibPortfolio,
+			&ibUrTrader, &tzTimeZone, &hasBroker, &broker, &headerModel.StartDate, &headerModel.EndDate,
+			&pvModel.HolidaySchedule, &formula1, &formula2, &pool1, &pool2, &product1, &product2,
+			&pvModel.Volume, &fixedPrice, &headerModel.CreatedBy, &headerModel.ModifiedBy, &headerModel.CreatedAt,
+			&headerModel.ModifiedAt, &executionDate, &executionTime, &exoticFlag, &pointCode1,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return nil, err
+		}
+
+		if contract.Valid {
+			headerModel.Contract = contract.String
+		}
+
+		if confirmFormat.Valid {
+			headerModel.ConfirmFormat = confirmFormat.String
+		}
+
+		if hsHedgeKey.Valid {
+			headerModel.HsHedgeKey = hsHedgeKey.String
+		}
+
+		if ibPrtPortfolio.Valid {
+			headerModel.IbPrtPortfolio = int(ibPrtPortfolio.Int32)
+		}
+
+		if ibPortfolio.Valid {
+			headerModel.IbPortfolio = ibPortfolio.String
+		}
+
+		if ibUrTrader.Valid {
+			headerModel.IbUrTrader = ibUrTrader.String
+		}
+
+		if tzTimeZone.Valid {
+			headerModel.TzTimeZone = tzTimeZone.String
+		}
+
+		if hasBroker.Valid {
+			headerModel.HasBroker = hasBroker.String
+		}
+
+		if broker.Valid {
+			headerModel.Broker = broker.String
+		}
+
+		if pool1.Valid {
+			pvModel.Pool1 = pool1.String
+		}
+
+		if pool2.Valid {
+			pvModel.Pool2 = pool2.String
+		}
+
+		if product1.Valid {
+			pvModel.Product1 = product1.String
+		}
+
+		if product2.Valid {
+			pvModel.Product2 = product2.String
+		}
+
+		if fixedPrice.Valid {
+			pvModel.FixedPrice = fixedPrice.Float64
+		}
+
+		if exoticFlag.Valid {
+			headerModel.ExoticFlag = exoticFlag.String
+		}
+
+		if pointCode1.Valid {
+			pvModel.PointCode1 = pointCode1.String
+		}
+
+		headerModel.InteraffiliateFlag = "N"
+
+		pvModel.VolSeq = 0
+		pvModel.BegDate = headerModel.StartDate
+		pvModel.EndDate = headerModel.EndDate
+
+		if formula1.Valid && formula1.String != "" {
+			formula := formulaRegex.FindString(formula1.String)
+			formula = strings.ReplaceAll(formula, "[", "")
+			formula = strings.ReplaceAll(formula, "]", "")
+			formulaSections := strings.Split(formula, "|")
+
+			if len(formulaSections) == 3 {
+				var idxModel nucleus.NucleusTradeIndexModel
+				idxModel.Publication = formulaSections[0]
+				idxModel.PubIndex = formulaSections[1]
+				idxModel.Frequency = formulaSections[2]
+				pvModel.Indexes1 = append(pvModel.Indexes1, &idxModel)
+			}
+		}
+
+		if formula2.Valid && formula2.String != "" {
+			formula := formulaRegex.FindString(formula2.String)
+			formula = strings.ReplaceAll(formula, "[", "")
+			formula = strings.ReplaceAll(formula, "]", "")
+			formulaSections := strings.Split(formula, "|")
+
+			if len(formulaSections) == 3 {
+				var idxModel nucleus.NucleusTradeIndexModel
+				idxModel.Publication = formulaSections[0]
+				idxModel.PubIndex = formulaSections[1]
+				idxModel.Frequency = formulaSections[2]
+				pvModel.Indexes2 = append(pvModel.Indexes2, &idxModel)
+			}
+		}
+
+		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
+			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
+			if err != nil {
+				logger.Debugln("error parsing executionDate and executionTime: ", err)
+				return nil, err
+			}
+			headerModel.ExecutionTime = execTime
+		}
+
+		headerModel.Terms = append(headerModel.Terms, &pvModel)
+		headerModels = append(headerModels, &headerModel)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	return headerModels, nil
+}
+
+func (repo *NucleusTradeRepository) GetNucHeatRateSwapsDealByKeys(ctx context.Context, lstHeatRateSwapskeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return getHeaderModelsByKeysInChunks(ctx, lstHeatRateSwapskeys, repo.getNucHeatRateSwapsDealByKeysChunk)
+}
+
+func (repo *NucleusTradeRepository) getNucHeatRateSwapsDealByKeysChunk(ctx context.Context, lstHeatRateSwapskeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "GetNucHeatRateSwapsDealByKeys")
+
+	dealKeysQuery, params, err := oracle.CreateInQueryFloat64(lstHeatRateSwapskeys, []interface{}{}, "pd.hrswps_key")
+	if err != nil {
+		logger.Debugln("error in CreateInQueryFloat64: ", err)
+		return nil, err
+	}
+
+	query := getNucHeatRateSwapsDealByKeysQuery(dealKeysQuery)
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucHeatRateSwapsDealByKeysQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+
+	for rows.Next() {
+		var headerModel nucleus.NucleusTradeHeaderModel
+		var pvModel nucleus.NucleusTradeTermModel
+		var idxModel, idxModel2 nucleus.NucleusTradeIndexModel
+
+		var contract, confirmFormat, hsHedgeKey, hasBroker, broker, executionDate,
+			executionTime, tzTimeZone, interaffiliateFlag, publication1, pubIndex1,
+			publication2, pubIndex2, frequency2 sql.NullString
+		var exercisedOptionKey sql.NullInt32
+		var totalQuantity, volume sql.NullFloat64
+
+		if err := rows.Scan(
+			&headerModel.DealKey, &headerModel.DealType, &totalQuantity, &headerModel.DnDirection,
+			&headerModel.TransactionDate, &headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName,
+			&headerModel.CompanyCode, &headerModel.LegalEntity, &headerModel.LegalEntityLongName,
+			&headerModel.CyLegalEntityKey, &interaffiliateFlag, &contract, &confirmFormat,
+			&headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio, &headerModel.Portfolio,
+			&headerModel.UrTrader, &tzTimeZone, &hasBroker, &broker, &exercisedOptionKey,
+			&pvModel.Pool1, &pvModel.Product1, &publication1, &pubIndex1, &publication2,
+			&pubIndex2, &frequency2, &headerModel.StartDate, &headerModel.EndDate,
+			&pvModel.HolidaySchedule, &volume, &headerModel.CreatedBy, &headerModel.ModifiedBy,
+			&headerModel.CreatedAt, &headerModel.ModifiedAt, &executionDate, &executionTime,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return nil, err
+		}
+
+		if totalQuantity.Valid {
+			headerModel.TotalQuantity = totalQuantity.Float64
+		}
+
+		if interaffiliateFlag.Valid {
+			headerModel.InteraffiliateFlag = interaffiliateFlag.String
+		}
+
+		if contract.Valid {
+			headerModel.Contract = contract.String
+		}
+
+		if confirmFormat.Valid {
+			headerModel.ConfirmFormat = confirmFormat.String
+		}
+
+		if hsHedgeKey.Valid {
+			headerModel.HsHedgeKey = hsHedgeKey.String
+		}
+
+		if tzTimeZone.Valid {
+			headerModel.TzTimeZone = tzTimeZone.String
+		}
+
+		if hasBroker.Valid {
+			headerModel.HasBroker = hasBroker.String
+		}
+
+		if broker.Valid {
+			headerModel.Broker = broker.String
+		}
+
+		if exercisedOptionKey.Valid {
+			headerModel.ExercisedOptionKey = int(exercisedOptionKey.Int32)
+		}
+
+		if publication1.Valid {
+			idxModel.Publication = publication1.String
+		}
+
+		if pubIndex1.Valid {
+			idxModel.PubIndex = pubIndex1.String
+		}
+
+		if publication2.Valid {
+			idxModel2.Publication = publication2.String
+		}
+
+		if pubIndex2.Valid {
+			idxModel2.PubIndex = pubIndex2.String
+		}
+
+		if frequency2.Valid {
+			idxModel2.Frequency = frequency2.String
+		}
+
+		pvModel.Indexes1 = append(pvModel.Indexes1, &idxModel)
+		pvModel.Indexes2 = append(pvModel.Indexes2, &idxModel2)
+		pvModel.Indexes2 = append(pvModel.Indexes2, &idxModel2)
+
+		if volume.Valid {
+			pvModel.Volume = volume.Float64
+		}
+
+		if executionDate.Valid && executionTime.Valid && executionDate.String != "" && executionTime.String != "" {
+			execTime, err := parseExecutionDateTime(executionDate.String, executionTime.String)
+			if err != nil {
+				logger.Debugln("error parsing executionDate and executionTime: ", err)
+				return nil, err
+			}
+			headerModel.ExecutionTime = execTime
+		}
+
+		headerModel.ExoticFlag = "NA"
+		pvModel.VolSeq = 0
+
+		pvModel.BegDate = headerModel.StartDate
+		pvModel.EndDate = headerModel.EndDate
+
+		headerModel.Terms = append(headerModel.Terms, &pvModel)
+		headerModels = append(headerModels, &headerModel)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	return headerModels, nil
+}
+
+func (repo *NucleusTradeRepository) GetNucTransmissionDealByKeys(ctx context.Context, lstTranskeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return getHeaderModelsByKeysInChunks(ctx, lstTranskeys, repo.getNucTransmissionDealByKeysChunk)
+}
+
+func (repo *NucleusTradeRepository) getNucTransmissionDealByKeysChunk(ctx context.Context, lstTranskeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "GetNucTransmissionDealByKeys")
+
+	dealKeysQuery, params, err := oracle.CreateInQueryFloat64(lstTranskeys, []interface{}{}, "pd.trans_key")
+	if err != nil {
+		logger.Debugln("error in CreateInQueryFloat64: ", err)
+		return nil, err
+	}
+
+	query := getNucTransmissionDealByKeysQuery(dealKeysQuery)
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucTransmissionDealByKeysQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+	headerModelsMap := make(map[int]*nucleus.NucleusTradeHeaderModel)
+
+	var dealKeys []int
+
+	for rows.Next() {
+		var headerModel nucleus.NucleusTradeHeaderModel
+		var contract, confirmFormat, hsHedgeKey, hasBroker, broker, executionTime sql.NullString
+
+		if err := rows.Scan(
+			&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection, &headerModel.TransactionDate,
+			&headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName, &headerModel.CompanyCode,
+			&headerModel.LegalEntity, &headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey,
+			&contract, &confirmFormat, &headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio,
+			&headerModel.Portfolio, &headerModel.UrTrader, &headerModel.TzTimeZone, &hasBroker,
+			&broker, &headerModel.CreatedBy, &headerModel.ModifiedBy, &headerModel.CreatedAt,
+			&headerModel.ModifiedAt, &executionTime,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return nil, err
+		}
+
+		if contract.Valid {
+			headerModel.Contract = contract.String
+		}
+
+		if confirmFormat.Valid {
+			headerModel.ConfirmFormat = confirmFormat.String
+		}
+
+		if hsHedgeKey.Valid {
+			headerModel.HsHedgeKey = hsHedgeKey.String
+		}
+
+		if hasBroker.Valid {
+			headerModel.HasBroker = hasBroker.String
+		}
+
+		if broker.Valid {
+			headerModel.Broker = broker.String
+		}
+
+		if executionTime.Valid {
+			time, err := parseExecutionDate(executionTime.String)
+			if err != nil {
+				logger.Debugln("error parsing executionDate and executionTime: ", err)
+				return nil, err
+			}
+
+			headerModel.ExecutionTime = time
+		}
+
+		headerModel.ExoticFlag = "NA"
+		headerModel.InteraffiliateFlag = "N"
+
+		dealKeys = append(dealKeys, headerModel.DealKey)
+		headerModelsMap[headerModel.DealKey] = &headerModel
+		headerModels = append(headerModels, &headerModel)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	if len(dealKeys) > 0 {
+		termModelsMap, err := repo.getNucTransmissionDealTermModel(ctx, dealKeys)
+		if err != nil {
+			logger.Debugln("error getting getNucTransmissionDealTermModel: ", err)
+			return nil, err
+		}
+
+		for dealKey, termModels := range termModelsMap {
+			headerModelsMap[dealKey].Terms = termModels
+		}
+	}
+
+	return headerModels, nil
+}
+
+func (repo *NucleusTradeRepository) GetNucTCCFTRSDealByKeys(ctx context.Context, lstTccFtrskeys []float64, strDealType string) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return getHeaderModelsByKeysInChunks(ctx, lstTccFtrskeys, func(ctx context.Context, keys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+		return repo.getNucTCCFTRSDealByKeysChunk(ctx, keys, strDealType)
+	})
+}
+
+func (repo *NucleusTradeRepository) getNucTCCFTRSDealByKeysChunk(ctx context.Context, lstTccFtrskeys []float64, strDealType string) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "GetNucTCCFTRSDealByKeys")
+
+	dealKeysQuery, params, err := oracle.CreateInQueryFloat64(lstTccFtrskeys, []interface{}{}, "pd.deal_key")
+	if err != nil {
+		logger.Debugln("error in CreateInQueryFloat64: ", err)
+		return nil, err
+	}
+
+	params = append(params, sql.Named("strDealType", strDealType))
+
+	query := getNucTCCFTRSDealByKeysQuery(dealKeysQuery)
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucTCCFTRSDealByKeysQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+
+	for rows.Next() {
+		var headerModel nucleus.NucleusTradeHeaderModel
+		var pvModel nucleus.NucleusTradeTermModel
+		var idxModel, idxModel2 nucleus.NucleusTradeIndexModel
+
+		var contract, confirmFormat, hsHedgeKey, hasBroker, broker sql.NullString
+		var volume, fixedPrice sql.NullFloat64
+
+		if err := rows.Scan(
+			&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection, &headerModel.TransactionDate,
+			&headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName, &headerModel.CompanyCode,
+			&headerModel.LegalEntity, &headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey,
+			&contract, &confirmFormat, &headerModel.Region, &hsHedgeKey, &headerModel.PrtPortfolio,
+			&headerModel.Portfolio, &headerModel.UrTrader, &headerModel.TzTimeZone, &hasBroker, &broker,
+			&pvModel.BegDate, &pvModel.EndDate, &pvModel.HolidaySchedule, &volume,
+			&fixedPrice, &pvModel.Pool1, &pvModel.Product1, &idxModel.Publication,
+			&idxModel.Frequency, &idxModel.PubIndex, &idxModel2.PubIndex, &headerModel.CreatedBy,
+			&headerModel.ModifiedBy, &headerModel.CreatedAt, &headerModel.ModifiedAt,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return nil, err
+		}
+
+		if contract.Valid {
+			headerModel.Contract = contract.String
+		}
+
+		if confirmFormat.Valid {
+			headerModel.ConfirmFormat = confirmFormat.String
+		}
+
+		if hsHedgeKey.Valid {
+			headerModel.HsHedgeKey = hsHedgeKey.String
+		}
+
+		if hasBroker.Valid {
+			headerModel.HasBroker = hasBroker.String
+		}
+
+		if broker.Valid {
+			headerModel.Broker = broker.String
+		}
+
+		if volume.Valid {
+			pvModel.Volume = volume.Float64
+		}
+
+		if fixedPrice.Valid {
+			pvModel.FixedPrice = fixedPrice.Float64
+		}
+
+		idxModel2.Publication = idxModel.Publication
+		idxModel2.Frequency = idxModel.Frequency
+
+		headerModel.ExoticFlag = "NA"
+		headerModel.InteraffiliateFlag = "N"
+
+		pvModel.Indexes1 = append(pvModel.Indexes1, &idxModel)
+		pvModel.Indexes2 = append(pvModel.Indexes2, &idxModel2)
+		pvModel.VolSeq = 0
+
+		headerModel.Terms = append(headerModel.Terms, &pvModel)
+		headerModels = append(headerModels, &headerModel)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	return headerModels, nil
+}
+
+func (repo *NucleusTradeRepository) GetNucMiscChargeDealByKeys(ctx context.Context, lstMiscChargekeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	return getHeaderModelsByKeysInChunks(ctx, lstMiscChargekeys, repo.getNucMiscChargeDealByKeysChunk)
+}
+
+func (repo *NucleusTradeRepository) getNucMiscChargeDealByKeysChunk(ctx context.Context, lstMiscChargekeys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "GetNucMiscChargeDealByKeys")
+
+	dealKeysQuery, params, err := oracle.CreateInQueryFloat64(lstMiscChargekeys, []interface{}{}, "pd.misc_charge_key")
+	if err != nil {
+		logger.Debugln("error in CreateInQueryFloat64: ", err)
+		return nil, err
+	}
+
+	query := getNucMiscChargeDealByKeysQuery(dealKeysQuery)
+	rows, err := repo.nucleusDb.QueryContext(ctx, query, params...)
+	if err != nil {
+		logger.Debugln("error got when executing getNucMiscChargeDealByKeysQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+	headerModelsMap := make(map[int]*nucleus.NucleusTradeHeaderModel)
+
+	var dealKeys []int
+
+	for rows.Next() {
+		var headerModel nucleus.NucleusTradeHeaderModel
+		var contract, confirmFormat, hsHedgeKey, hasBroker, broker, region, tzTimeZone sql.NullString
+
+		if err := rows.Scan(
+			&headerModel.DealKey, &headerModel.DealType, &headerModel.DnDirection, &headerModel.TransactionDate,
+			&headerModel.CyCompanyKey, &headerModel.Company, &headerModel.CompanyLongName, &headerModel.CompanyCode,
+			&headerModel.LegalEntity, &headerModel.LegalEntityLongName, &headerModel.CyLegalEntityKey,
+			&contract, &confirmFormat, &region, &hsHedgeKey, &headerModel.PrtPortfolio,
+			&headerModel.Portfolio, &headerModel.UrTrader, &tzTimeZone, &hasBroker, &broker,
+			&headerModel.CreatedBy, &headerModel.ModifiedBy, &headerModel.CreatedAt, &headerModel.ModifiedAt,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return nil, err
+		}
+
+		if contract.Valid {
+			headerModel.Contract = contract.String
+		}
+
+		if confirmFormat.Valid {
+			headerModel.ConfirmFormat = confirmFormat.String
+		}
+
+		if region.Valid {
+			headerModel.Region = region.String
+		}
+
+		if hsHedgeKey.Valid {
+			headerModel.HsHedgeKey = hsHedgeKey.String
+		}
+
+		if tzTimeZone.Valid {
+			headerModel.TzTimeZone = tzTimeZone.String
+		}
+
+		if hasBroker.Valid {
+			headerModel.HasBroker = hasBroker.String
+		}
+
+		if broker.Valid {
+			headerModel.Broker = broker.String
+		}
+
+		headerModel.InteraffiliateFlag = "N"
+
+		dealKeys = append(dealKeys, headerModel.DealKey)
+		headerModelsMap[headerModel.DealKey] = &headerModel
+		headerModels = append(headerModels, &headerModel)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	if len(dealKeys) > 0 {
+		termModelsMap, err := repo.getNucMiscChargeDealTermList(ctx, dealKeys)
+		if err != nil {
+			logger.Debugln("error getting getNucMiscChargeDealTermList: ", err)
+			return nil, err
+		}
+
+		for dealKey, termModels := range termModelsMap {
+			headerModelsMap[dealKey].Terms = termModels
+		}
+	}
+
+	return headerModels, nil
+}
+
+// NucleusDealLookupKey identifies one deal requested through GetNucDealsByTypeAndKeys
+type NucleusDealLookupKey struct {
+	DealType string `json:"dealType"`
+	DealKey  int    `json:"dealKey"`
+}
+
+// NucleusDealLookupMiss is a requested deal that could not be returned
+// together with the reason why
+type NucleusDealLookupMiss struct {
+	DealType string `json:"dealType"`
+	DealKey  int    `json:"dealKey"`
+	Reason   string `json:"reason"`
+}
+
+const (
+	dealLookupNotFoundReason            = "not found"
+	dealLookupUnsupportedDealTypeReason = "unsupported deal type"
+)
+
+// nucleusDealTypeMethods are the methods extracting the deals of a deal type
+type nucleusDealTypeMethods struct {
+	// listedWith is the deal type whose deal list holds the deals of this
+	// one, empty when it has its own deal list
+	listedWith string
+	byKeys     func(ctx context.Context, keys []float64) ([]*nucleus.NucleusTradeHeaderModel, error)
+	stream     func(ctx context.Context, lastRunTime time.Time, tradeDate time.Time, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error
+}
+
+// getNucDealTypeMethods is the single table of the supported nucleus deal
+// types, the ByKeys loaders, the deal list loaders and the streamers are all
+// derived from it
+func (repo *NucleusTradeRepository) getNucDealTypeMethods() map[string]*nucleusDealTypeMethods {
+	tccFtrs := func(dealType string) *nucleusDealTypeMethods {
+		return &nucleusDealTypeMethods{
+			byKeys: func(ctx context.Context, keys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+				return repo.GetNucTCCFTRSDealByKeys(ctx, keys, dealType)
+			},
+			stream: func(ctx context.Context, lastRunTime time.Time, tradeDate time.Time, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+				return repo.StreamNucTCCFTRSDealList(ctx, lastRunTime, tradeDate, dealType, yield)
+			},
+		}
+	}
+
+	return map[string]*nucleusDealTypeMethods{
+		"POWER":  {byKeys: repo.GetNucPowerDealByKeys, stream: repo.StreamNucPowerDealList},
+		"PWRNSD": {listedWith: "POWER", byKeys: repo.GetNucPowerDealByKeys},
+		"PSWPS":  {byKeys: repo.GetNucPowerSwapDealByKeys, stream: repo.StreamNucPowerSwapDealList},
+		"POPTS":  {byKeys: repo.GetNucPowerOptionsDealByKeys, stream: repo.StreamNucPowerOptionsDealList},
+		"CAPCTY": {byKeys: repo.GetNucCapacityDealByKeys, stream: repo.StreamNucCapacityDealList},
+		"PTP":    {byKeys: repo.GetNucPTPDealByKeys, stream: repo.StreamNucPTPDealList},
+		"EMSSN":  {byKeys: repo.GetNucEmissionDealByKeys, stream: repo.StreamNucEmissionDealList},
+		"EMOPTS": {byKeys: repo.GetNucEmissionOptionDealByKeys, stream: repo.StreamNucEmissionOptionDealList},
+		"SPDOPT": {byKeys: repo.GetNucSpreadOptionsDealByKeys, stream: repo.StreamNucSpreadOptionsDealList},
+		"HRSWPS": {byKeys: repo.GetNucHeatRateSwapsDealByKeys, stream: repo.StreamNucHeatRateSwapsDealList},
+		"FTROPT": tccFtrs("FTROPT"),
+		"FTRSWP": tccFtrs("FTRSWP"),
+		"TCCSWP": tccFtrs("TCCSWP"),
+		"TRANS":  {byKeys: repo.GetNucTransmissionDealByKeys, stream: repo.StreamNucTransmissionDealList},
+		"MISC":   {byKeys: repo.GetNucMiscChargeDealByKeys, stream: repo.StreamNucMiscChargeDealList},
+	}
+}
+
+// getNucDealByKeysLoaders maps every supported nucleus deal type to the
+// ByKeys method that loads it
+func (repo *NucleusTradeRepository) getNucDealByKeysLoaders() map[string]func(ctx context.Context, keys []float64) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	loaders := make(map[string]func(ctx context.Context, keys []float64) ([]*nucleus.NucleusTradeHeaderModel, error))
+	for dealType, methods := range repo.getNucDealTypeMethods() {
+		loaders[dealType] = methods.byKeys
+	}
+
+	return loaders
+}
+
+// GetNucDealsByTypeAndKeys groups the requested deals by deal type, loads each
+// group with its ByKeys method and merges the results. Deals that don't exist,
+// that are of another deal type than requested, as the POWER and PWRNSD deals
+// share their ByKeys method, or that have an unsupported deal type are returned
+// as misses instead of failing the whole lookup
+func (repo *NucleusTradeRepository) GetNucDealsByTypeAndKeys(ctx context.Context, lookupKeys []*NucleusDealLookupKey) ([]*nucleus.NucleusTradeHeaderModel, []*NucleusDealLookupMiss, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "GetNucDealsByTypeAndKeys")
+
+	loaders := repo.getNucDealByKeysLoaders()
+
+	var dealTypes []string
+	dealKeysByType := make(map[string][]int)
+	requestedKeys := make(map[string]map[int]bool)
+
+	var misses []*NucleusDealLookupMiss
+
+	for _, lookupKey := range lookupKeys {
+		if lookupKey == nil {
+			continue
+		}
+
+		dealType := strings.ToUpper(strings.TrimSpace(lookupKey.DealType))
+
+		if _, ok := loaders[dealType]; !ok {
+			misses = append(misses, &NucleusDealLookupMiss{
+				DealType: lookupKey.DealType,
+				DealKey:  lookupKey.DealKey,
+				Reason:   dealLookupUnsupportedDealTypeReason,
+			})
+			continue
+		}
+
+		if _, ok := requestedKeys[dealType]; !ok {
+			requestedKeys[dealType] = make(map[int]bool)
+			dealTypes = append(dealTypes, dealType)
+		}
+
+		if requestedKeys[dealType][lookupKey.DealKey] {
+			continue
+		}
+
+		requestedKeys[dealType][lookupKey.DealKey] = true
+		dealKeysByType[dealType] = append(dealKeysByType[dealType], lookupKey.DealKey)
+	}
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+
+	for _, dealType := range dealTypes {
+		var keys []float64
+		for _, dealKey := range dealKeysByType[dealType] {
+			keys = append(keys, float64(dealKey))
+		}
+
+		results, err := loaders[dealType](ctx, keys)
+		if err != nil {
+			logger.Debugln("error loading deals for deal type "+dealType+": ", err)
+			return nil, nil, err
+		}
+
+		foundKeys := make(map[int]bool)
+		for _, headerModel := range results {
+			if strings.ToUpper(strings.TrimSpace(headerModel.DealType)) != dealType {
+				continue
+			}
+			foundKeys[headerModel.DealKey] = true
+			headerModels = append(headerModels, headerModel)
+		}
+
+		for _, dealKey := range dealKeysByType[dealType] {
+			if !foundKeys[dealKey] {
+				misses = append(misses, &NucleusDealLookupMiss{
+					DealType: dealType,
+					DealKey:  dealKey,
+					Reason:   dealLookupNotFoundReason,
+				})
+			}
+		}
+	}
+
+	return headerModels, misses, nil
+}
+
+// getAllNucDealListsConcurrency is the maximum number of deal list queries
+// GetAllNucDealLists runs at the same time against nucleusDb
+const getAllNucDealListsConcurrency = 4
+
+// NucleusDealListResult is the outcome of extracting the deal list of a single
+// deal type in GetAllNucDealLists
+type NucleusDealListResult struct {
+	DealType   string                             `json:"dealType"`
+	Deals      []*nucleus.NucleusTradeHeaderModel `json:"deals"`
+	Error      string                             `json:"error,omitempty"`
+	DurationMs int64                              `json:"durationMs"`
+}
+
+// getNucDealListLoaders maps every nucleus deal type with its own deal list
+// to the method that extracts it
+func (repo *NucleusTradeRepository) getNucDealListLoaders() map[string]func(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	loaders := make(map[string]func(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error))
+	for dealType, stream := range repo.getNucDealListStreamers() {
+		stream := stream
+		loaders[dealType] = func(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+			return collectHeaderModels(func(yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+				return stream(ctx, lastRunTime, tradeDate, yield)
+			})
+		}
+	}
+
+	return loaders
+}
+
+// getNucDealListStreamers maps every deal type with its own deal list to the
+// method streaming it
+func (repo *NucleusTradeRepository) getNucDealListStreamers() map[string]func(ctx context.Context, lastRunTime time.Time, tradeDate time.Time, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+	streamers := make(map[string]func(ctx context.Context, lastRunTime time.Time, tradeDate time.Time, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error)
+	for dealType, methods := range repo.getNucDealTypeMethods() {
+		if methods.listedWith == "" {
+			streamers[dealType] = methods.stream
+		}
+	}
+
+	return streamers
+}
+
+// GetAllNucDealLists extracts the deal lists of every deal type concurrently,
+// running at most getAllNucDealListsConcurrency queries at once, and no more
+// than the deal lists streamed by the other callers leave room for in the
+// connection pool of nucleusDb. A failing deal type doesn't stop the others,
+// its error is reported in its own result
+func (repo *NucleusTradeRepository) GetAllNucDealLists(ctx context.Context, lastRunTime time.Time, tradeDate time.Time) map[string]*NucleusDealListResult {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "GetAllNucDealLists")
+
+	loaders := repo.getNucDealListLoaders()
+
+	var dealTypes []string
+	for dealType := range loaders {
+		dealTypes = append(dealTypes, dealType)
+	}
+	sort.Strings(dealTypes)
+
+	results := make(map[string]*NucleusDealListResult)
+	var resultsMutex sync.Mutex
+	var wg sync.WaitGroup
+
+	semaphore := make(chan struct{}, getAllNucDealListsConcurrency)
+
+	for _, dealType := range dealTypes {
+		wg.Add(1)
+		go func(dealType string) {
+			defer wg.Done()
+
+			result := &NucleusDealListResult{DealType: dealType}
+
+			select {
+			case semaphore <- struct{}{}:
+				start := time.Now()
+				deals, err := loaders[dealType](ctx, lastRunTime, tradeDate)
+				result.DurationMs = time.Since(start).Milliseconds()
+				<-semaphore
+
+				if err != nil {
+					logger.Debugln("error getting the deal list for deal type "+dealType+": ", err)
+					result.Error = err.Error()
+				} else {
+					result.Deals = deals
+				}
+			case <-ctx.Done():
+				result.Error = ctx.Err().Error()
+			}
+
+			resultsMutex.Lock()
+			results[dealType] = result
+			resultsMutex.Unlock()
+		}(dealType)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+type nucleusProcessedTradeType struct {
+	TradeId             int64
+	DealType            string
+	PortfolioId         int64
+	TransactionDate     time.Time
+	TradeDetail         string
+	AnomalyDetectedFlag bool
+	AnomalyTestResult   sql.NullString
+	ModelParameters     sql.NullString
+}
+
+// defaultProcessTradesChunkSize is the number of trades ProcessTrades sends
+// in a single TVP when the repository isn't configured otherwise
+const defaultProcessTradesChunkSize = 1000
+
+// ErrProcessTradesOutputMissing is returned when SP_PROCESS_NUCLEUSTRADES_UPSERT
+// doesn't output the TradeId, DealType, $action and AnomalyDetectedFlag of the
+// rows it merged
+var ErrProcessTradesOutputMissing = errors.New("SP_PROCESS_NUCLEUSTRADES_UPSERT returned no merge output")
+
+// the outcomes of a trade sent to ProcessTrades
+const (
+	ProcessedTradeInserted  = "inserted"
+	ProcessedTradeUpdated   = "updated"
+	ProcessedTradeUnchanged = "unchanged"
+	ProcessedTradeFailed    = "failed"
+)
+
+// NucleusProcessTradesSummary is the outcome of a committed ProcessTrades
+type NucleusProcessTradesSummary struct {
+	TradesCount        int                            `json:"tradesCount"`
+	ChunksCount        int                            `json:"chunksCount"`
+	UpsertedByDealType map[string]int                 `json:"upsertedByDealType"`
+	FailedCount        int                            `json:"failedCount"`
+	Results            []*NucleusProcessedTradeResult `json:"results"`
+}
+
+// NucleusProcessedTradeResult is the outcome of a single trade, with the
+// AnomalyDetectedFlag stored for it
+type NucleusProcessedTradeResult struct {
+	DealKey             int    `json:"dealKey"`
+	DealType            string `json:"dealType"`
+	Status              string `json:"status"`
+	AnomalyDetectedFlag bool   `json:"anomalyDetectedFlag"`
+	Error               string `json:"error,omitempty"`
+}
+
+// nucleusProcessedTradeModelResultType is a row of the
+// NucleusProcessedTradeModelResultType TVP, one per model result of a trade
+type nucleusProcessedTradeModelResultType struct {
+	TradeId         int64
+	DealType        string
+	ModelName       string
+	ScoredLabel     string
+	Message         sql.NullString
+	ModelParameters string
+}
+
+// NucleusProcessedTradeModelResult is a model result stored for a processed
+// trade by ProcessTrades
+type NucleusProcessedTradeModelResult struct {
+	DealKey         int             `json:"dealKey"`
+	DealType        string          `json:"dealType"`
+	ModelName       string          `json:"modelName"`
+	ScoredLabel     string          `json:"scoredLabel"`
+	Message         string          `json:"message"`
+	ModelParameters json.RawMessage `json:"modelParameters"`
+	CreatedAt       time.Time       `json:"createdAt"`
+}
+
+type nucleusProcessedTradeKey struct {
+	tradeId  int64
+	dealType string
+}
+
+// ProcessTrades upserts the trades with their anomaly results through
+// SP_PROCESS_NUCLEUSTRADES_UPSERT. The trades are sent in TVP chunks inside a
+// single transaction, so either every chunk is committed or none is. A trade
+// that can't be sent (null, no key, duplicated or not serialisable) is reported
+// as failed without stopping the others. The stored procedure outputs the rows
+// it merged, the trades missing from that output were left unchanged. Every
+// model result of a trade replaces the ones stored in
+// NucleusProcessedTradeModelResult in the same transaction, and a trade whose
+// detail changed since it was last sent is stored as a new NucleusTradeVersion
+func (repo *NucleusTradeRepository) ProcessTrades(ctx context.Context, trades []*nucleus.NucleusTradeHeaderModel, anomalyMessages map[int][]common.IModelBasePayload) (*NucleusProcessTradesSummary, error) {
+	return repo.processTrades(ctx, trades, anomalyMessages, nil)
+}
+
+// processTrades is ProcessTrades, beforeCommit runs in the transaction of the
+// upsert once every chunk is sent, with the summary it is about to commit. The
+// transaction is opened even without a trade to send when beforeCommit is set
+func (repo *NucleusTradeRepository) processTrades(ctx context.Context, trades []*nucleus.NucleusTradeHeaderModel, anomalyMessages map[int][]common.IModelBasePayload, beforeCommit func(ctx context.Context, tx *sql.Tx, summary *NucleusProcessTradesSummary) error) (*NucleusProcessTradesSummary, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "ProcessTrades")
+
+	summary := &NucleusProcessTradesSummary{
+		UpsertedByDealType: make(map[string]int),
+	}
+
+	if len(trades) == 0 && beforeCommit == nil {
+		return summary, nil
+	}
+
+	var nucleusProcessedTradeTypeData []nucleusProcessedTradeType
+	var nucleusProcessedTradeModelResultData [][]nucleusProcessedTradeModelResultType
+	pendingResults := make(map[nucleusProcessedTradeKey]*NucleusProcessedTradeResult)
+
+	for _, trade := range trades {
+		if trade == nil {
+			summary.Results = append(summary.Results, &NucleusProcessedTradeResult{
+				Status: ProcessedTradeFailed,
+				Error:  "trade is null",
+			})
+			continue
+		}
+
+		result := &NucleusProcessedTradeResult{
+			DealKey:  trade.DealKey,
+			DealType: trade.DealType,
+		}
+		summary.Results = append(summary.Results, result)
+
+		key := nucleusProcessedTradeKey{tradeId: int64(trade.DealKey), dealType: trade.DealType}
+		if trade.DealKey <= 0 || trade.DealType == "" {
+			result.Status = ProcessedTradeFailed
+			result.Error = "dealKey and dealType are required"
+			continue
+		}
+		if _, ok := pendingResults[key]; ok {
+			result.Status = ProcessedTradeFailed
+			result.Error = "trade is duplicated in the request"
+			continue
+		}
+
+		nucleusProcessedTrade, err := toNucleusProcessedTradeType(trade, anomalyMessages[trade.DealKey])
+		if err != nil {
+			logger.Debugln("error marshalling trade: ", err)
+			result.Status = ProcessedTradeFailed
+			result.Error = err.Error()
+			continue
+		}
+
+		modelResults, err := toNucleusProcessedTradeModelResultTypes(trade, anomalyMessages[trade.DealKey])
+		if err != nil {
+			logger.Debugln("error marshalling model results: ", err)
+			result.Status = ProcessedTradeFailed
+			result.Error = err.Error()
+			continue
+		}
+
+		result.AnomalyDetectedFlag = nucleusProcessedTrade.AnomalyDetectedFlag
+		pendingResults[key] = result
+		nucleusProcessedTradeTypeData = append(nucleusProcessedTradeTypeData, nucleusProcessedTrade)
+		nucleusProcessedTradeModelResultData = append(nucleusProcessedTradeModelResultData, modelResults)
+	}
+
+	var tx *sql.Tx
+	if len(nucleusProcessedTradeTypeData) > 0 || beforeCommit != nil {
+		chunkSize := repo.processTradesChunkSize
+		if chunkSize < 1 {
+			chunkSize = defaultProcessTradesChunkSize
+		}
+
+		var err error
+		tx, err = repo.machineLearningDb.BeginTx(ctx, nil)
+		if err != nil {
+			logger.Debugln("error beginning the transaction: ", err)
+			return nil, err
+		}
+		defer tx.Rollback()
+
+		mergedResults := make(map[nucleusProcessedTradeKey]*NucleusProcessedTradeResult)
+		for start := 0; start < len(nucleusProcessedTradeTypeData); start += chunkSize {
+			end := start + chunkSize
+			if end > len(nucleusProcessedTradeTypeData) {
+				end = len(nucleusProcessedTradeTypeData)
+			}
+
+			if err := repo.execProcessTradesChunk(ctx, tx, nucleusProcessedTradeTypeData[start:end], mergedResults); err != nil {
+				return nil, err
+			}
+
+			if err := repo.replaceProcessedTradeModelResults(ctx, tx, nucleusProcessedTradeTypeData[start:end], nucleusProcessedTradeModelResultData[start:end]); err != nil {
+				return nil, err
+			}
+
+			if err := repo.insertTradeVersions(ctx, tx, nucleusProcessedTradeTypeData[start:end]); err != nil {
+				return nil, err
+			}
+
+			summary.ChunksCount++
+		}
+
+		for key, result := range pendingResults {
+			result.Status = ProcessedTradeUnchanged
+			if mergedResult, ok := mergedResults[key]; ok {
+				result.Status = mergedResult.Status
+				result.AnomalyDetectedFlag = mergedResult.AnomalyDetectedFlag
+			}
+		}
+	}
+
+	for _, result := range summary.Results {
+		switch result.Status {
+		case ProcessedTradeInserted, ProcessedTradeUpdated:
+			summary.UpsertedByDealType[result.DealType]++
+		case ProcessedTradeFailed:
+			summary.FailedCount++
+		}
+	}
+	summary.TradesCount = len(summary.Results)
+
+	if tx != nil {
+		if beforeCommit != nil {
+			if err := beforeCommit(ctx, tx, summary); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			logger.Debugln("error committing the transaction: ", err)
+			return nil, err
+		}
+	}
+
+	return summary, nil
+}
+
+// execProcessTradesChunk sends a single TVP to SP_PROCESS_NUCLEUSTRADES_UPSERT
+// and collects the TradeId, DealType, action and AnomalyDetectedFlag of the
+// rows it merged. A procedure without that output fails the chunk, as every
+// trade would otherwise be reported unchanged
+func (repo *NucleusTradeRepository) execProcessTradesChunk(ctx context.Context, tx *sql.Tx, chunk []nucleusProcessedTradeType, mergedResults map[nucleusProcessedTradeKey]*NucleusProcessedTradeResult) error {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "ProcessTrades")
+
+	tvpType := mssql.TVP{
+		TypeName: "NucleusProcessedTradeType",
+		Value:    chunk,
+	}
+
+	rows, err := tx.QueryContext(ctx, execProcessTradesQuery, sql.Named("TVP", tvpType))
+	if err != nil {
+		logger.Debugln("error in execProcessTradesQuery: ", err)
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		logger.Debugln("error getting the columns: ", err)
+		return err
+	}
+	if len(columns) != 4 {
+		logger.Debugln("error in execProcessTradesQuery: ", ErrProcessTradesOutputMissing)
+		return ErrProcessTradesOutputMissing
+	}
+
+	for rows.Next() {
+		var tradeId int64
+		var dealType, action string
+		var anomalyDetectedFlag bool
+
+		if err := rows.Scan(&tradeId, &dealType, &action, &anomalyDetectedFlag); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return err
+		}
+
+		result := &NucleusProcessedTradeResult{
+			DealKey:             int(tradeId),
+			DealType:            dealType,
+			Status:              ProcessedTradeUnchanged,
+			AnomalyDetectedFlag: anomalyDetectedFlag,
+		}
+
+		switch strings.ToUpper(action) {
+		case "INSERT":
+			result.Status = ProcessedTradeInserted
+		case "UPDATE":
+			result.Status = ProcessedTradeUpdated
+		}
+
+		mergedResults[nucleusProcessedTradeKey{tradeId: tradeId, dealType: dealType}] = result
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return err
+	}
+
+	return nil
+}
+
+// replaceProcessedTradeModelResults deletes the model results stored for the
+// trades of the chunk and inserts the new ones
+func (repo *NucleusTradeRepository) replaceProcessedTradeModelResults(ctx context.Context, tx *sql.Tx, chunk []nucleusProcessedTradeType, chunkModelResults [][]nucleusProcessedTradeModelResultType) error {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "ProcessTrades")
+
+	tvpType := mssql.TVP{
+		TypeName: "NucleusProcessedTradeType",
+		Value:    chunk,
+	}
+
+	if _, err := tx.ExecContext(ctx, deleteProcessedTradeModelResultsQuery, sql.Named("TVP", tvpType)); err != nil {
+		logger.Debugln("error in deleteProcessedTradeModelResultsQuery: ", err)
+		return err
+	}
+
+	var modelResults []nucleusProcessedTradeModelResultType
+	for _, tradeModelResults := range chunkModelResults {
+		modelResults = append(modelResults, tradeModelResults...)
+	}
+
+	if len(modelResults) == 0 {
+		return nil
+	}
+
+	modelResultsTvpType := mssql.TVP{
+		TypeName: "NucleusProcessedTradeModelResultType",
+		Value:    modelResults,
+	}
+
+	if _, err := tx.ExecContext(ctx, insertProcessedTradeModelResultsQuery, sql.Named("TVP", modelResultsTvpType)); err != nil {
+		logger.Debugln("error in insertProcessedTradeModelResultsQuery: ", err)
+		return err
+	}
+
+	return nil
+}
+
+// insertTradeVersions stores a new version of every trade of the chunk whose
+// TradeDetail changed since its latest version
+func (repo *NucleusTradeRepository) insertTradeVersions(ctx context.Context, tx *sql.Tx, chunk []nucleusProcessedTradeType) error {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "ProcessTrades")
+
+	tvpType := mssql.TVP{
+		TypeName: "NucleusProcessedTradeType",
+		Value:    chunk,
+	}
+
+	if _, err := tx.ExecContext(ctx, insertTradeVersionsQuery, sql.Named("TVP", tvpType)); err != nil {
+		logger.Debugln("error in insertTradeVersionsQuery: ", err)
+		return err
+	}
+
+	return nil
+}
+
+// toNucleusProcessedTradeModelResultTypes builds a TVP row for every model
+// result of the trade, whatever its scored label
+func toNucleusProcessedTradeModelResultTypes(trade *nucleus.NucleusTradeHeaderModel, resultList []common.IModelBasePayload) ([]nucleusProcessedTradeModelResultType, error) {
+	var modelResults []nucleusProcessedTradeModelResultType
+
+	for _, result := range resultList {
+		vParams, err := json.Marshal(result)
+		if err != nil {
+			return nil, err
+		}
+
+		modelResults = append(modelResults, nucleusProcessedTradeModelResultType{
+			TradeId:     int64(trade.DealKey),
+			DealType:    trade.DealType,
+			ModelName:   result.GetModelName(),
+			ScoredLabel: result.GetScoredLabel(),
+			Message: sql.NullString{
+				String: result.GetMessage(),
+				Valid:  result.GetMessage() != "",
+			},
+			ModelParameters: string(vParams),
+		})
+	}
+
+	return modelResults, nil
+}
+
+// toNucleusProcessedTradeType builds the TVP row of the trade. The results
+// scored "NO" flag the trade as an anomaly
+func toNucleusProcessedTradeType(trade *nucleus.NucleusTradeHeaderModel, resultList []common.IModelBasePayload) (nucleusProcessedTradeType, error) {
+	var anomalyMsg, modelParams string
+
+	for _, result := range resultList {
+		if result.GetScoredLabel() == "NO" {
+			if anomalyMsg == "" {
+				anomalyMsg = result.GetModelName() + " " + result.GetMessage()
+			} else {
+				anomalyMsg = anomalyMsg + ";" + result.GetModelName() + " " + result.GetMessage()
+			}
+
+			vParams, err := json.Marshal(result)
+			if err != nil {
+				return nucleusProcessedTradeType{}, err
+			}
+
+			if modelParams == "" {
+				modelParams = string(vParams)
+			} else {
+				modelParams = modelParams + ";" + string(vParams)
+			}
+		}
+	}
+
+	marshalledTrade, err := json.Marshal(trade)
+	if err != nil {
+		return nucleusProcessedTradeType{}, err
+	}
+
+	nucleusProcessedTrade := nucleusProcessedTradeType{
+		TradeId:             int64(trade.DealKey),
+		DealType:            trade.DealType,
+		PortfolioId:         int64(trade.PrtPortfolio),
+		TransactionDate:     trade.TransactionDate,
+		TradeDetail:         string(marshalledTrade),
+		AnomalyDetectedFlag: false,
+		AnomalyTestResult: sql.NullString{
+			Valid: false,
+		},
+		ModelParameters: sql.NullString{
+			Valid: false,
+		},
+	}
+
+	if anomalyMsg != "" {
+		nucleusProcessedTrade.AnomalyDetectedFlag = true
+		nucleusProcessedTrade.AnomalyTestResult = sql.NullString{
+			String: anomalyMsg,
+			Valid:  true,
+		}
+	}
+
+	if modelParams != "" {
+		nucleusProcessedTrade.ModelParameters = sql.NullString{
+			String: modelParams,
+			Valid:  true,
+		}
+	}
+
+	return nucleusProcessedTrade, nil
+}
+
+// GetProcessedTradeModelResults returns the model results stored by
+// ProcessTrades for the trade, in the order they were sent
+func (repo *NucleusTradeRepository) GetProcessedTradeModelResults(ctx context.Context, dealKey int, dealType string) ([]*NucleusProcessedTradeModelResult, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "GetProcessedTradeModelResults")
+
+	rows, err := repo.machineLearningDb.QueryContext(ctx, getProcessedTradeModelResultsQuery,
+		sql.Named("tradeId", dealKey),
+		sql.Named("dealType", dealType),
+	)
+	if err != nil {
+		logger.Debugln("error got when executing getProcessedTradeModelResultsQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var modelResults []*NucleusProcessedTradeModelResult
+
+	for rows.Next() {
+		var modelResult NucleusProcessedTradeModelResult
+
+		var message, modelParameters sql.NullString
+
+		if err := rows.Scan(
+			&modelResult.DealKey, &modelResult.DealType, &modelResult.ModelName, &modelResult.ScoredLabel,
+			&message, &modelParameters, &modelResult.CreatedAt,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return nil, err
+		}
+
+		if message.Valid {
+			modelResult.Message = message.String
+		}
+
+		if modelParameters.Valid {
+			modelResult.ModelParameters = json.RawMessage(modelParameters.String)
+		}
+
+		modelResults = append(modelResults, &modelResult)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	return modelResults, nil
+}
+
+// the page size of GetProcessedTrades when the filter doesn't set one, and
+// the largest page it returns
+const (
+	defaultProcessedTradesPageSize = 100
+	maxProcessedTradesPageSize     = 1000
+)
+
+// NucleusProcessedTradeFilter selects the processed trades returned by
+// GetProcessedTrades, the filters left empty are not applied. Page starts at 1
+type NucleusProcessedTradeFilter struct {
+	FromTradeDate       *time.Time
+	ToTradeDate         *time.Time
+	DealType            string
+	PortfolioId         *int
+	AnomalyDetectedFlag *bool
+	ModelName           string
+	Page                int
+	PageSize            int
+}
+
+// NucleusProcessedTrade is a trade stored by ProcessTrades with its anomaly
+// verdict, TradeDetail is the trade as it was sent
+type NucleusProcessedTrade struct {
+	DealKey             int                              `json:"dealKey"`
+	DealType            string                           `json:"dealType"`
+	PortfolioId         int                              `json:"portfolioId"`
+	TransactionDate     time.Time                        `json:"transactionDate"`
+	AnomalyDetectedFlag bool                             `json:"anomalyDetectedFlag"`
+	AnomalyTestResult   string                           `json:"anomalyTestResult"`
+	ModelParameters     string                           `json:"modelParameters"`
+	TradeDetail         *nucleus.NucleusTradeHeaderModel `json:"tradeDetail"`
+}
+
+// NucleusProcessedTradePage is a page of the processed trades matching a
+// NucleusProcessedTradeFilter, TotalCount counts every page
+type NucleusProcessedTradePage struct {
+	Page       int                      `json:"page"`
+	PageSize   int                      `json:"pageSize"`
+	TotalCount int                      `json:"totalCount"`
+	Trades     []*NucleusProcessedTrade `json:"trades"`
+}
+
+// GetProcessedTrades returns a page of the trades stored by ProcessTrades,
+// ordered by trade date and deal key
+func (repo *NucleusTradeRepository) GetProcessedTrades(ctx context.Context, filter NucleusProcessedTradeFilter) (*NucleusProcessedTradePage, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "GetProcessedTrades")
+
+	page := filter.Page
+	if page <= 0 {
+		page = 1
+	}
+
+	pageSize := filter.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultProcessedTradesPageSize
+	}
+	if pageSize > maxProcessedTradesPageSize {
+		pageSize = maxProcessedTradesPageSize
+	}
+
+	filterArgs := processedTradesFilterArgs(filter)
+
+	processedTradePage := &NucleusProcessedTradePage{
+		Page:     page,
+		PageSize: pageSize,
+		Trades:   []*NucleusProcessedTrade{},
+	}
+
+	if err := repo.machineLearningDb.QueryRowContext(ctx, countProcessedTradesQuery, filterArgs...).
+		Scan(&processedTradePage.TotalCount); err != nil {
+		logger.Debugln("error got when executing countProcessedTradesQuery: ", err)
+		return nil, err
+	}
+
+	if processedTradePage.TotalCount <= (page-1)*pageSize {
+		return processedTradePage, nil
+	}
+
+	args := append(filterArgs,
+		sql.Named("offset", (page-1)*pageSize),
+		sql.Named("pageSize", pageSize),
+	)
+
+	rows, err := repo.machineLearningDb.QueryContext(ctx, getProcessedTradesQuery, args...)
+	if err != nil {
+		logger.Debugln("error got when executing getProcessedTradesQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var processedTrade NucleusProcessedTrade
+
+		var tradeDetail string
+		var anomalyTestResult, modelParameters sql.NullString
+
+		if err := rows.Scan(
+			&processedTrade.DealKey, &processedTrade.DealType, &processedTrade.PortfolioId, &processedTrade.TransactionDate,
+			&tradeDetail, &processedTrade.AnomalyDetectedFlag, &anomalyTestResult, &modelParameters,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return nil, err
+		}
+
+		if anomalyTestResult.Valid {
+			processedTrade.AnomalyTestResult = anomalyTestResult.String
+		}
+
+		if modelParameters.Valid {
+			processedTrade.ModelParameters = modelParameters.String
+		}
+
+		var headerModel nucleus.NucleusTradeHeaderModel
+		if err := json.Unmarshal([]byte(tradeDetail), &headerModel); err != nil {
+			logger.Debugln("error unmarshalling the trade detail of ", processedTrade.DealType, " ", processedTrade.DealKey, ": ", err)
+			return nil, err
+		}
+		processedTrade.TradeDetail = &headerModel
+
+		processedTradePage.Trades = append(processedTradePage.Trades, &processedTrade)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	return processedTradePage, nil
+}
+
+// processedTradesFilterArgs sends a NULL for every filter that isn't set, so
+// processedTradesFilter skips it
+func processedTradesFilterArgs(filter NucleusProcessedTradeFilter) []interface{} {
+	var fromTradeDate, toTradeDate sql.NullTime
+	if filter.FromTradeDate != nil {
+		fromTradeDate = sql.NullTime{Time: *filter.FromTradeDate, Valid: true}
+	}
+	if filter.ToTradeDate != nil {
+		toTradeDate = sql.NullTime{Time: *filter.ToTradeDate, Valid: true}
+	}
+
+	var portfolioId sql.NullInt64
+	if filter.PortfolioId != nil {
+		portfolioId = sql.NullInt64{Int64: int64(*filter.PortfolioId), Valid: true}
+	}
+
+	var anomalyDetectedFlag sql.NullBool
+	if filter.AnomalyDetectedFlag != nil {
+		anomalyDetectedFlag = sql.NullBool{Bool: *filter.AnomalyDetectedFlag, Valid: true}
+	}
+
+	return []interface{}{
+		sql.Named("fromTradeDate", fromTradeDate),
+		sql.Named("toTradeDate", toTradeDate),
+		sql.Named("dealType", sql.NullString{String: filter.DealType, Valid: filter.DealType != ""}),
+		sql.Named("portfolioId", portfolioId),
+		sql.Named("anomalyDetectedFlag", anomalyDetectedFlag),
+		sql.Named("modelName", sql.NullString{String: filter.ModelName, Valid: filter.ModelName != ""}),
+	}
+}
+
+func (repo *NucleusTradeRepository) GetLastExtractionRun(ctx context.Context, tradeDate time.Time, dealType string) (*nucleus.NucleusTradeExtractionRunModel, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "GetLastExtractionRun")
+
+	var extractionRun nucleus.NucleusTradeExtractionRunModel
+	if err := repo.machineLearningDb.QueryRowContext(ctx, getLastExtractionRunQuery, sql.Named("transactionDate", tradeDate), sql.Named("dealType", dealType)).
+		Scan(
+			&extractionRun.ExtractionRunId, &extractionRun.TransactionDate, &extractionRun.DealType,
+			&extractionRun.TimeParameter, &extractionRun.CreatedAt,
+		); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		logger.Debugln("error got when executing getLastExtractionRunQuery: ", err)
+		return nil, err
+	}
+
+	return &extractionRun, nil
+}
+
+func (repo *NucleusTradeRepository) InsertExtractionRun(ctx context.Context, tradeDate time.Time, lastRun time.Time, dealType string) error {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "InsertExtractionRun")
+
+	if _, err := repo.machineLearningDb.ExecContext(ctx, insertExtractionRunQuery,
+		sql.Named("transactionDate", tradeDate),
+		sql.Named("dealType", dealType),
+		sql.Named("timeParameter", lastRun),
+	); err != nil {
+		logger.Debugln("error got when executing insertExtractionRun: ", err)
+		return err
+	}
+
+	return nil
+}
+
+func (repo *NucleusTradeRepository) GetPortfolioRiskMappingList(ctx context.Context) ([]*nucleus.PortfolioRiskMappingModel, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "GetPortfolioRiskMappingList")
+
+	rows, err := repo.machineLearningDb.QueryContext(ctx, getPortfolioRiskMappingListQuery)
+	if err != nil {
+		logger.Debugln("error got when executing getPortfolioRiskMappingListQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var portfolioRiskModels []*nucleus.PortfolioRiskMappingModel
+
+	for rows.Next() {
+		var portfolioRiskModel nucleus.PortfolioRiskMappingModel
+
+		var legalEntity sql.NullString
+
+		if err := rows.Scan(
+			&portfolioRiskModel.SourceSystem, &portfolioRiskModel.Portfolio, &legalEntity,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return nil, err
+		}
+
+		if legalEntity.Valid {
+			portfolioRiskModel.LegalEntity = legalEntity.String
+		}
+
+		portfolioRiskModels = append(portfolioRiskModels, &portfolioRiskModel)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	return portfolioRiskModels, nil
+}
+
+func (repo *NucleusTradeRepository) GetLarBaselist(ctx context.Context) ([]*common.LarBaseModel, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "GetLarBaselist")
+
+	rows, err := repo.machineLearningDb.QueryContext(ctx, getLarBaselistQuery)
+	if err != nil {
+		logger.Debugln("error got when executing getLarBaselistQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var larBaseModels []*common.LarBaseModel
+
+	for rows.Next() {
+		var larBaseModel common.LarBaseModel
+
+		var shortName, counterpartyLongName, parentCompany, product, sourceSystem, dealType,
+			nettingAgreement, agreementTypePerCSA, buyTenor, sellTenor, limitAvailability,
+			sPRating, moodyRating, finalInternalRating, finalRating, equifax, amendedBy,
+			doddFrankClassification, tradingEntity, legalEntity sql.NullString
+		var grossExposure, collateral, netPosition sql.NullFloat64
+		var effectiveDate, reviewDate, reportCreatedDate, reportingDate, createdAt sql.NullTime
+
+		if err := rows.Scan(
+			&shortName, &counterpartyLongName, &parentCompany, &product, &sourceSystem,
+			&dealType, &nettingAgreement, &agreementTypePerCSA, &larBaseModel.OurThreshold,
+			&larBaseModel.CounterpartyThreshold, &buyTenor, &sellTenor, &grossExposure,
+			&collateral, &netPosition, &larBaseModel.LimitValue, &larBaseModel.LimitCurrency,
+			&limitAvailability, &larBaseModel.ExposureLimit, &larBaseModel.ExpirationDate,
+			&larBaseModel.MarketType, &larBaseModel.IndustryCode, &sPRating, &moodyRating,
+			&finalInternalRating, &finalRating, &equifax, &amendedBy, &effectiveDate,
+			&reviewDate, &doddFrankClassification, &reportCreatedDate, &larBaseModel.Boost,
+			&tradingEntity, &legalEntity, &larBaseModel.Agmt, &larBaseModel.CSA,
+			&larBaseModel.Tenor, &larBaseModel.Limit, &reportingDate, &createdAt,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return nil, err
+		}
+
+		if shortName.Valid {
+			larBaseModel.ShortName = shortName.String
+		}
+
+		if counterpartyLongName.Valid {
+			larBaseModel.CounterpartyLongName = counterpartyLongName.String
+		}
+
+		if parentCompany.Valid {
+			larBaseModel.ParentCompany = parentCompany.String
+		}
+
+		if product.Valid {
+			larBaseModel.Product = product.String
+		}
+
+		if sourceSystem.Valid {
+			larBaseModel.SourceSystem = sourceSystem.String
+		}
+
+		if dealType.Valid {
+			larBaseModel.DealType = dealType.String
+		}
+
+		if nettingAgreement.Valid {
+			larBaseModel.NettingAgreement = nettingAgreement.String
+		}
+
+		if agreementTypePerCSA.Valid {
+			larBaseModel.AgreementTypePerCSA = agreementTypePerCSA.String
+		}
+
+		if buyTenor.Valid {
+			larBaseModel.BuyTenor = buyTenor.String
+		}
+
+		if sellTenor.Valid {
+			larBaseModel.SellTenor = sellTenor.String
+		}
+
+		if grossExposure.Valid {
+			larBaseModel.GrossExposure = grossExposure.Float64
+		}
+
+		if collateral.Valid {
+			larBaseModel.Collateral = collateral.Float64
+		}
+
+		if netPosition.Valid {
+			larBaseModel.NetPosition = netPosition.Float64
+		}
+
+		if limitAvailability.Valid {
+			larBaseModel.LimitAvailability = limitAvailability.String
+		}
+
+		if sPRating.Valid {
+			larBaseModel.SPRating = sPRating.String
+		}
+
+		if moodyRating.Valid {
+			larBaseModel.MoodyRating = moodyRating.String
+		}
+
+		if finalInternalRating.Valid {
+			larBaseModel.FinalInternalRating = finalInternalRating.String
+		}
+
+		if finalRating.Valid {
+			larBaseModel.FinalRating = finalRating.String
+		}
+
+		if equifax.Valid {
+			larBaseModel.Equifax = equifax.String
+		}
+
+		if amendedBy.Valid {
+			larBaseModel.AmendedBy = amendedBy.String
+		}
+
+		if effectiveDate.Valid {
+			larBaseModel.EffectiveDate = effectiveDate.Time
+		}
+
+		if reviewDate.Valid {
+			larBaseModel.ReviewDate = reviewDate.Time
+		}
+
+		if doddFrankClassification.Valid {
+			larBaseModel.DoddFrankClassification = doddFrankClassification.String
+		}
+
+		if reportCreatedDate.Valid {
+			larBaseModel.ReportCreatedDate = reportCreatedDate.Time
+		}
+
+		if tradingEntity.Valid {
+			larBaseModel.TradingEntity = tradingEntity.String
+		}
+
+		if legalEntity.Valid {
+			larBaseModel.LegalEntity = legalEntity.String
+		}
+
+		if reportingDate.Valid {
+			larBaseModel.ReportingDate = reportingDate.Time
+		}
+
+		if createdAt.Valid {
+			larBaseModel.CreatedAt = createdAt.Time
+		}
+
+		larBaseModels = append(larBaseModels, &larBaseModel)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	return larBaseModels, nil
+}
@@ -0,0 +1,422 @@
+package power
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sede-x/RogerRogerAnomalyDetector/logger"
+)
+
+// the reasons a trade date and deal type need a backfill
+const (
+	ExtractionGapMissing = "missing"
+	ExtractionGapStale   = "stale"
+)
+
+// the statuses of a backfill and of its items
+const (
+	BackfillPending   = "pending"
+	BackfillRunning   = "running"
+	BackfillSucceeded = "succeeded"
+	BackfillFailed    = "failed"
+	BackfillCancelled = "cancelled"
+)
+
+const (
+	// finishedBackfillRetention is how long a finished backfill can be read
+	finishedBackfillRetention = 24 * time.Hour
+	// maxFinishedBackfills is the number of finished backfills kept, the
+	// oldest ones are evicted first
+	maxFinishedBackfills = 100
+)
+
+// ErrBackfillOverlap is returned when a gap of the plan is still to be run by
+// another backfill
+var ErrBackfillOverlap = errors.New("the backfill overlaps a running backfill")
+
+// ErrBackfillServiceStopped is returned when a backfill is started after the
+// service was stopped
+var ErrBackfillServiceStopped = errors.New("the backfill service is stopped")
+
+// extractionGapDateLayout keys the watermarks by trade date
+const extractionGapDateLayout = "2006-01-02"
+
+// NucleusExtractionGap is a trade date and deal type without a successful
+// extraction run, or whose last successful run is older than the stale limit
+type NucleusExtractionGap struct {
+	TradeDate         time.Time  `json:"tradeDate"`
+	DealType          string     `json:"dealType"`
+	Reason            string     `json:"reason"`
+	LastTimeParameter *time.Time `json:"lastTimeParameter"`
+	LastSucceededAt   *time.Time `json:"lastSucceededAt"`
+}
+
+// NucleusBackfillPlan lists the gaps of a trade date range, by trade date
+// then deal type
+type NucleusBackfillPlan struct {
+	FromTradeDate time.Time               `json:"fromTradeDate"`
+	ToTradeDate   time.Time               `json:"toTradeDate"`
+	DealTypes     []string                `json:"dealTypes"`
+	Gaps          []*NucleusExtractionGap `json:"gaps"`
+}
+
+// GetExtractionGaps scans the extraction runs of every trade date between
+// fromTradeDate and toTradeDate for the deal types, every deal type with a
+// deal list when empty. A trade date is missing when the deal type never
+// succeeded for it, and stale when its last successful run finished more than
+// staleAfter ago, which isn't checked when 0. The runs are dated by
+// SYSUTCDATETIME, unlike the watermarks which are in the time of NUCLEUS
+func (repo *NucleusTradeRepository) GetExtractionGaps(ctx context.Context, fromTradeDate time.Time, toTradeDate time.Time, dealTypes []string, staleAfter time.Duration) (*NucleusBackfillPlan, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "GetExtractionGaps")
+
+	loaders := repo.getNucDealListLoaders()
+	if len(dealTypes) == 0 {
+		dealTypes = repo.GetNucDealTypes()
+	}
+	for _, dealType := range dealTypes {
+		if _, ok := loaders[dealType]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrNucDealTypeNotSupported, dealType)
+		}
+	}
+
+	rows, err := repo.machineLearningDb.QueryContext(ctx, getLastTimeParametersQuery,
+		sql.Named("fromTradeDate", fromTradeDate),
+		sql.Named("toTradeDate", toTradeDate),
+	)
+	if err != nil {
+		logger.Debugln("error got when executing getLastTimeParametersQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	lastSucceededRuns := make(map[string]*NucleusExtractionRun)
+
+	for rows.Next() {
+		var lastSucceededRun NucleusExtractionRun
+		var finishedAt time.Time
+
+		if err := rows.Scan(&lastSucceededRun.TransactionDate, &lastSucceededRun.DealType, &lastSucceededRun.TimeParameter, &finishedAt); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return nil, err
+		}
+		lastSucceededRun.FinishedAt = &finishedAt
+
+		lastSucceededRuns[lastSucceededRun.TransactionDate.Format(extractionGapDateLayout)+"/"+lastSucceededRun.DealType] = &lastSucceededRun
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	plan := &NucleusBackfillPlan{
+		FromTradeDate: fromTradeDate,
+		ToTradeDate:   toTradeDate,
+		DealTypes:     dealTypes,
+		Gaps:          []*NucleusExtractionGap{},
+	}
+
+	for tradeDate := fromTradeDate; !tradeDate.After(toTradeDate); tradeDate = tradeDate.AddDate(0, 0, 1) {
+		for _, dealType := range dealTypes {
+			lastSucceededRun, ok := lastSucceededRuns[tradeDate.Format(extractionGapDateLayout)+"/"+dealType]
+			switch {
+			case !ok:
+				plan.Gaps = append(plan.Gaps, &NucleusExtractionGap{
+					TradeDate: tradeDate,
+					DealType:  dealType,
+					Reason:    ExtractionGapMissing,
+				})
+			case staleAfter > 0 && time.Since(*lastSucceededRun.FinishedAt) > staleAfter:
+				plan.Gaps = append(plan.Gaps, &NucleusExtractionGap{
+					TradeDate:         tradeDate,
+					DealType:          dealType,
+					Reason:            ExtractionGapStale,
+					LastTimeParameter: &lastSucceededRun.TimeParameter,
+					LastSucceededAt:   lastSucceededRun.FinishedAt,
+				})
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// NucleusBackfillItem is the progress of a gap of a backfill
+type NucleusBackfillItem struct {
+	NucleusExtractionGap
+	Status          string `json:"status"`
+	ExtractionRunId int    `json:"extractionRunId,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
+
+// NucleusBackfill is the progress of a backfill plan run by
+// NucleusBackfillService
+type NucleusBackfill struct {
+	BackfillId     string                 `json:"backfillId"`
+	Status         string                 `json:"status"`
+	TotalCount     int                    `json:"totalCount"`
+	CompletedCount int                    `json:"completedCount"`
+	FailedCount    int                    `json:"failedCount"`
+	StartedAt      time.Time              `json:"startedAt"`
+	FinishedAt     *time.Time             `json:"finishedAt"`
+	Items          []*NucleusBackfillItem `json:"items"`
+}
+
+// NucleusBackfillService runs backfill plans in the background, one gap after
+// the other through ExtractAndProcessTrades, and keeps their progress in
+// memory. The finished backfills are kept for finishedBackfillRetention, and
+// at most maxFinishedBackfills of them
+type NucleusBackfillService struct {
+	repository INucleusTradeRepository
+	logger     logger.Logger
+	ctx        context.Context
+	cancel     context.CancelFunc
+	running    sync.WaitGroup
+
+	mutex     sync.Mutex
+	lastId    int
+	backfills map[string]*NucleusBackfill
+	cancels   map[string]context.CancelFunc
+}
+
+// NewNucleusBackfillService creates the service, cancelling ctx or calling
+// Stop cancels the backfills it runs
+func NewNucleusBackfillService(ctx context.Context, repository INucleusTradeRepository, logger logger.Logger) *NucleusBackfillService {
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &NucleusBackfillService{
+		repository: repository,
+		logger:     logger,
+		ctx:        ctx,
+		cancel:     cancel,
+		backfills:  make(map[string]*NucleusBackfill),
+		cancels:    make(map[string]context.CancelFunc),
+	}
+}
+
+// StartBackfill runs the gaps of the plan in the background, holding the
+// extraction leases as ownerId, and returns the backfill to follow with
+// GetBackfill. A missing trade date is extracted from its first deal, a stale
+// one from its last watermark. A failing gap doesn't stop the others.
+// ErrBackfillOverlap is returned when a gap is still to be run by another
+// backfill, ErrBackfillServiceStopped once the service is stopped
+func (service *NucleusBackfillService) StartBackfill(plan *NucleusBackfillPlan, ownerId string) (*NucleusBackfill, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	if service.ctx.Err() != nil {
+		return nil, ErrBackfillServiceStopped
+	}
+
+	service.evictFinishedBackfills()
+
+	if err := service.checkBackfillOverlap(plan); err != nil {
+		return nil, err
+	}
+
+	service.lastId++
+	backfill := &NucleusBackfill{
+		BackfillId: strconv.Itoa(service.lastId),
+		Status:     BackfillRunning,
+		TotalCount: len(plan.Gaps),
+		StartedAt:  time.Now(),
+	}
+	for _, gap := range plan.Gaps {
+		backfill.Items = append(backfill.Items, &NucleusBackfillItem{
+			NucleusExtractionGap: *gap,
+			Status:               BackfillPending,
+		})
+	}
+	service.backfills[backfill.BackfillId] = backfill
+
+	ctx, cancel := context.WithCancel(service.ctx)
+	service.cancels[backfill.BackfillId] = cancel
+
+	service.running.Add(1)
+	go func() {
+		defer service.running.Done()
+		service.runBackfill(ctx, backfill, ownerId)
+	}()
+
+	return backfill.snapshot(), nil
+}
+
+// Stop cancels the running backfills and waits for them to finish, so their
+// extraction runs are recorded and their leases released. No backfill can be
+// started afterwards
+func (service *NucleusBackfillService) Stop() {
+	service.mutex.Lock()
+	service.cancel()
+	service.mutex.Unlock()
+
+	service.running.Wait()
+}
+
+// GetBackfill returns the progress of the backfill, nil when it is unknown
+func (service *NucleusBackfillService) GetBackfill(backfillId string) *NucleusBackfill {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	service.evictFinishedBackfills()
+
+	backfill, ok := service.backfills[backfillId]
+	if !ok {
+		return nil
+	}
+
+	return backfill.snapshot()
+}
+
+// CancelBackfill cancels the backfill and returns its progress, nil when it
+// is unknown. The gap being run is stopped and the pending ones are skipped,
+// the backfill is cancelled once its FinishedAt is set
+func (service *NucleusBackfillService) CancelBackfill(backfillId string) *NucleusBackfill {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	backfill, ok := service.backfills[backfillId]
+	if !ok {
+		return nil
+	}
+
+	if cancel, ok := service.cancels[backfillId]; ok {
+		cancel()
+	}
+
+	return backfill.snapshot()
+}
+
+// checkBackfillOverlap checks that no gap of the plan is pending or running
+// in an unfinished backfill
+func (service *NucleusBackfillService) checkBackfillOverlap(plan *NucleusBackfillPlan) error {
+	runningGaps := make(map[string]string)
+	for _, backfill := range service.backfills {
+		if backfill.FinishedAt != nil {
+			continue
+		}
+		for _, item := range backfill.Items {
+			if item.Status == BackfillPending || item.Status == BackfillRunning {
+				runningGaps[item.TradeDate.Format(extractionGapDateLayout)+"/"+item.DealType] = backfill.BackfillId
+			}
+		}
+	}
+
+	for _, gap := range plan.Gaps {
+		key := gap.TradeDate.Format(extractionGapDateLayout) + "/" + gap.DealType
+		if backfillId, ok := runningGaps[key]; ok {
+			return fmt.Errorf("%w: %s %s is run by backfill %s", ErrBackfillOverlap, gap.DealType, gap.TradeDate.Format(extractionGapDateLayout), backfillId)
+		}
+	}
+
+	return nil
+}
+
+// evictFinishedBackfills removes the backfills finished for longer than
+// finishedBackfillRetention, then the oldest finished ones over
+// maxFinishedBackfills
+func (service *NucleusBackfillService) evictFinishedBackfills() {
+	var finished []*NucleusBackfill
+	for backfillId, backfill := range service.backfills {
+		if backfill.FinishedAt == nil {
+			continue
+		}
+		if time.Since(*backfill.FinishedAt) > finishedBackfillRetention {
+			delete(service.backfills, backfillId)
+			continue
+		}
+		finished = append(finished, backfill)
+	}
+
+	if len(finished) <= maxFinishedBackfills {
+		return
+	}
+
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].FinishedAt.Before(*finished[j].FinishedAt)
+	})
+	for _, backfill := range finished[:len(finished)-maxFinishedBackfills] {
+		delete(service.backfills, backfill.BackfillId)
+	}
+}
+
+func (service *NucleusBackfillService) runBackfill(ctx context.Context, backfill *NucleusBackfill, ownerId string) {
+	logger := service.logger.GetLogger()
+	logger = logger.WithField("method", "runBackfill")
+
+	for _, item := range backfill.Items {
+		if ctx.Err() != nil {
+			break
+		}
+
+		service.mutex.Lock()
+		item.Status = BackfillRunning
+		service.mutex.Unlock()
+
+		summary, err := service.repository.ExtractAndProcessTrades(ctx, item.TradeDate, item.DealType, time.Time{}, ownerId)
+
+		service.mutex.Lock()
+		switch {
+		case err != nil && ctx.Err() != nil:
+			item.Status = BackfillCancelled
+			item.Error = err.Error()
+		case err != nil:
+			logger.Debugln("error backfilling ", item.DealType, " ", item.TradeDate.Format(extractionGapDateLayout), ": ", err)
+			item.Status = BackfillFailed
+			item.Error = err.Error()
+			backfill.FailedCount++
+			backfill.CompletedCount++
+		default:
+			item.Status = BackfillSucceeded
+			item.ExtractionRunId = summary.ExtractionRunId
+			backfill.CompletedCount++
+		}
+		service.mutex.Unlock()
+	}
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	finishedAt := time.Now()
+	backfill.FinishedAt = &finishedAt
+	backfill.Status = BackfillSucceeded
+	if backfill.FailedCount > 0 {
+		backfill.Status = BackfillFailed
+	}
+	for _, item := range backfill.Items {
+		if item.Status == BackfillPending || item.Status == BackfillCancelled {
+			item.Status = BackfillCancelled
+			backfill.Status = BackfillCancelled
+		}
+	}
+
+	service.cancels[backfill.BackfillId]()
+	delete(service.cancels, backfill.BackfillId)
+}
+
+// snapshot copies the backfill, so it can be read while it runs
+func (backfill *NucleusBackfill) snapshot() *NucleusBackfill {
+	snapshot := *backfill
+	snapshot.Items = make([]*NucleusBackfillItem, 0, len(backfill.Items))
+	for _, item := range backfill.Items {
+		itemSnapshot := *item
+		snapshot.Items = append(snapshot.Items, &itemSnapshot)
+	}
+	if backfill.FinishedAt != nil {
+		finishedAt := *backfill.FinishedAt
+		snapshot.FinishedAt = &finishedAt
+	}
+
+	return &snapshot
+}
@@ -0,0 +1,249 @@
+package power
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sede-x/RogerRogerAnomalyDetector/models/nucleus"
+)
+
+const (
+	// oracleMaxInListSize is the maximum number of expressions Oracle accepts
+	// in a single IN list (ORA-01795)
+	oracleMaxInListSize = 1000
+	// inListChunksConcurrency is the maximum number of deal chunk queries run
+	// at the same time for a single IN list. The term and index chunks loaded
+	// for a deal chunk run one after the other, so nested chunks don't
+	// multiply the number of concurrent Oracle queries
+	inListChunksConcurrency = 4
+)
+
+// chunkIntKeys removes the duplicated keys and splits the rest in chunks of
+// at most oracleMaxInListSize keys, keeping the original order
+func chunkIntKeys(keys []int) [][]int {
+	var chunks [][]int
+	var chunk []int
+
+	seenKeys := make(map[int]bool)
+	for _, key := range keys {
+		if seenKeys[key] {
+			continue
+		}
+		seenKeys[key] = true
+
+		chunk = append(chunk, key)
+		if len(chunk) == oracleMaxInListSize {
+			chunks = append(chunks, chunk)
+			chunk = nil
+		}
+	}
+
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks
+}
+
+// chunkFloat64Keys removes the duplicated keys and splits the rest in chunks
+// of at most oracleMaxInListSize keys, keeping the original order
+func chunkFloat64Keys(keys []float64) [][]float64 {
+	var chunks [][]float64
+	var chunk []float64
+
+	seenKeys := make(map[float64]bool)
+	for _, key := range keys {
+		if seenKeys[key] {
+			continue
+		}
+		seenKeys[key] = true
+
+		chunk = append(chunk, key)
+		if len(chunk) == oracleMaxInListSize {
+			chunks = append(chunks, chunk)
+			chunk = nil
+		}
+	}
+
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks
+}
+
+// chunkIntMap splits the map in maps of at most oracleMaxInListSize entries
+func chunkIntMap(values map[int]int) []map[int]int {
+	var chunks []map[int]int
+	chunk := make(map[int]int)
+
+	for key, value := range values {
+		chunk[key] = value
+		if len(chunk) == oracleMaxInListSize {
+			chunks = append(chunks, chunk)
+			chunk = make(map[int]int)
+		}
+	}
+
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks
+}
+
+// runInListChunks calls runChunk for every chunk index, at most concurrency
+// at the same time. The first error cancels the chunks that are still
+// pending and is returned
+func runInListChunks(ctx context.Context, chunksCount int, concurrency int, runChunk func(ctx context.Context, index int) error) error {
+	if chunksCount == 1 || concurrency <= 1 {
+		for index := 0; index < chunksCount; index++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := runChunk(ctx, index); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+
+	semaphore := make(chan struct{}, concurrency)
+
+	for index := 0; index < chunksCount; index++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				errOnce.Do(func() { firstErr = ctx.Err() })
+				return
+			}
+
+			if err := runChunk(ctx, index); err != nil {
+				errOnce.Do(func() { firstErr = err })
+				cancel()
+			}
+		}(index)
+	}
+
+	wg.Wait()
+
+	return firstErr
+}
+
+// getTermModelsInChunks loads the term models of the deal keys in chunks that
+// fit in an Oracle IN list, one after the other, and merges them in a single
+// map
+func getTermModelsInChunks(ctx context.Context, dealKeys []int, loadChunk func(ctx context.Context, dealKeys []int) (map[int][]*nucleus.NucleusTradeTermModel, error)) (map[int][]*nucleus.NucleusTradeTermModel, error) {
+	chunks := chunkIntKeys(dealKeys)
+	if len(chunks) == 0 {
+		return nil, nil
+	}
+
+	chunkResults := make([]map[int][]*nucleus.NucleusTradeTermModel, len(chunks))
+	if err := runInListChunks(ctx, len(chunks), 1, func(ctx context.Context, index int) error {
+		termModelMap, err := loadChunk(ctx, chunks[index])
+		chunkResults[index] = termModelMap
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	termModelMap := make(map[int][]*nucleus.NucleusTradeTermModel)
+	for _, chunkResult := range chunkResults {
+		for dealKey, termModels := range chunkResult {
+			termModelMap[dealKey] = append(termModelMap[dealKey], termModels...)
+		}
+	}
+
+	return termModelMap, nil
+}
+
+// getIndexModelsInChunks loads the index models of the deal keys in chunks
+// that fit in an Oracle IN list, one after the other, and merges them in a
+// single map
+func getIndexModelsInChunks(ctx context.Context, dealKeys []int, loadChunk func(ctx context.Context, dealKeys []int) (map[int][]*nucleus.NucleusTradeIndexModel, error)) (map[int][]*nucleus.NucleusTradeIndexModel, error) {
+	chunks := chunkIntKeys(dealKeys)
+	if len(chunks) == 0 {
+		return nil, nil
+	}
+
+	chunkResults := make([]map[int][]*nucleus.NucleusTradeIndexModel, len(chunks))
+	if err := runInListChunks(ctx, len(chunks), 1, func(ctx context.Context, index int) error {
+		indexModelMap, err := loadChunk(ctx, chunks[index])
+		chunkResults[index] = indexModelMap
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return mergeIndexModelMaps(chunkResults), nil
+}
+
+// getIndexModelsByFormulaMapInChunks loads the index models of the
+// deal key/volume sequence pairs in chunks of at most oracleMaxInListSize
+// pairs, one after the other, and merges them in a single map
+func getIndexModelsByFormulaMapInChunks(ctx context.Context, formulaMap map[int]int, loadChunk func(ctx context.Context, formulaMap map[int]int) (map[int][]*nucleus.NucleusTradeIndexModel, error)) (map[int][]*nucleus.NucleusTradeIndexModel, error) {
+	chunks := chunkIntMap(formulaMap)
+	if len(chunks) == 0 {
+		return nil, nil
+	}
+
+	chunkResults := make([]map[int][]*nucleus.NucleusTradeIndexModel, len(chunks))
+	if err := runInListChunks(ctx, len(chunks), 1, func(ctx context.Context, index int) error {
+		indexModelMap, err := loadChunk(ctx, chunks[index])
+		chunkResults[index] = indexModelMap
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return mergeIndexModelMaps(chunkResults), nil
+}
+
+func mergeIndexModelMaps(chunkResults []map[int][]*nucleus.NucleusTradeIndexModel) map[int][]*nucleus.NucleusTradeIndexModel {
+	indexModelMap := make(map[int][]*nucleus.NucleusTradeIndexModel)
+	for _, chunkResult := range chunkResults {
+		for dealKey, indexModels := range chunkResult {
+			indexModelMap[dealKey] = append(indexModelMap[dealKey], indexModels...)
+		}
+	}
+
+	return indexModelMap
+}
+
+// getHeaderModelsByKeysInChunks loads the deals of the keys in chunks that
+// fit in an Oracle IN list and concatenates them in the order of the chunks
+func getHeaderModelsByKeysInChunks(ctx context.Context, keys []float64, loadChunk func(ctx context.Context, keys []float64) ([]*nucleus.NucleusTradeHeaderModel, error)) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	chunks := chunkFloat64Keys(keys)
+	if len(chunks) == 0 {
+		return nil, nil
+	}
+
+	chunkResults := make([][]*nucleus.NucleusTradeHeaderModel, len(chunks))
+	if err := runInListChunks(ctx, len(chunks), inListChunksConcurrency, func(ctx context.Context, index int) error {
+		headerModels, err := loadChunk(ctx, chunks[index])
+		chunkResults[index] = headerModels
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+	for _, chunkResult := range chunkResults {
+		headerModels = append(headerModels, chunkResult...)
+	}
+
+	return headerModels, nil
+}
@@ -0,0 +1,249 @@
+package power
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sede-x/RogerRogerAnomalyDetector/models/common"
+	"github.com/sede-x/RogerRogerAnomalyDetector/models/nucleus"
+)
+
+// CreditLimitRuleName is the model name of the results of CreditLimitRule
+const CreditLimitRuleName = "CreditLimit"
+
+// the directions of a deal, they pick the buy or the sell tenor of a limit
+const (
+	dealDirectionPurchase = "PURCHASE"
+	dealDirectionSale     = "SALE"
+)
+
+// creditLimitSourceSystem is the SourceSystem of the LarBase limits of the
+// NUCLEUS deals
+const creditLimitSourceSystem = "NUCLEUS"
+
+// unavailableCreditLimits are the LimitAvailability values of a counterparty
+// without a limit
+var unavailableCreditLimits = map[string]bool{
+	"NOT AVAILABLE": true,
+	"N/A":           true,
+	"NA":            true,
+}
+
+// creditTenorPattern reads tenors like 18M, 5 Years or 30 days
+var creditTenorPattern = regexp.MustCompile(`^(\d+)\s*(D|DAY|DAYS|W|WK|WEEK|WEEKS|M|MO|MTH|MONTH|MONTHS|Y|YR|YRS|YEAR|YEARS)$`)
+
+// CreditLimitRule checks a trade against the LarBase credit limit of its
+// counterparty. The limit is found by matching the Company or the
+// CompanyLongName of the trade with the ShortName of the NUCLEUS limits of
+// LarBase, then a product of the terms or the deal type of the trade with the
+// Product or the DealType of the limit. The limit of the legal entity of the
+// trade comes first, then the one of a product without a legal entity, which
+// applies to every legal entity. The trade is flagged when:
+//   - the counterparty has no limit for the legal entity or its limit is not available
+//   - the limit expired before the trade date
+//   - the trade ends after the buy or sell tenor of the limit
+//   - the trade pushes NetPosition beyond ExposureLimit either way, LimitValue
+//     when ExposureLimit isn't a number. The exposure of the trade is the sum
+//     of the volume times the fixed price of its terms, negative for a sale
+//
+// The rule doesn't apply to the counterparties missing from LarBase
+type CreditLimitRule struct{}
+
+func (rule *CreditLimitRule) Name() string {
+	return CreditLimitRuleName
+}
+
+func (rule *CreditLimitRule) Evaluate(trade *nucleus.NucleusTradeHeaderModel, referenceData *NucleusReferenceData) (common.IModelBasePayload, error) {
+	larBases := referenceData.getLarBaseIndex()
+
+	counterpartyLimits := larBases[normalizeReferenceName(trade.Company)]
+	if len(counterpartyLimits) == 0 {
+		counterpartyLimits = larBases[normalizeReferenceName(trade.CompanyLongName)]
+	}
+	if len(counterpartyLimits) == 0 {
+		return nil, nil
+	}
+
+	limit := findCreditLimit(counterpartyLimits, trade)
+	if limit == nil {
+		return NewRuleResult(rule, trade, false, fmt.Sprintf("counterparty %s has no credit limit for legal entity %s", trade.Company, trade.LegalEntity)), nil
+	}
+
+	var problems []string
+
+	if unavailableCreditLimits[normalizeReferenceName(limit.LimitAvailability)] {
+		problems = append(problems, fmt.Sprintf("the credit limit of counterparty %s is not available", limit.ShortName))
+	}
+
+	if !limit.ExpirationDate.IsZero() && limit.ExpirationDate.Before(trade.TransactionDate) {
+		problems = append(problems, fmt.Sprintf("the credit limit of counterparty %s expired on %s", limit.ShortName, limit.ExpirationDate.Format(extractionGapDateLayout)))
+	}
+
+	var tenor string
+	switch trade.DnDirection {
+	case dealDirectionPurchase:
+		tenor = limit.BuyTenor
+	case dealDirectionSale:
+		tenor = limit.SellTenor
+	}
+	if tenorEnd, ok := addCreditTenor(trade.TransactionDate, tenor); ok {
+		if maturity := tradeMaturity(trade); maturity.After(tenorEnd) {
+			problems = append(problems, fmt.Sprintf("the trade ends on %s, beyond the %s tenor of counterparty %s", maturity.Format(extractionGapDateLayout), tenor, limit.ShortName))
+		}
+	}
+
+	if exposureLimit, ok := getExposureLimit(limit); ok {
+		netPosition := limit.NetPosition + tradeExposure(trade)
+		if math.Abs(netPosition) > exposureLimit && math.Abs(netPosition) > math.Abs(limit.NetPosition) {
+			problems = append(problems, fmt.Sprintf("the trade pushes the net position of counterparty %s to %.2f, over its exposure limit of %.2f", limit.ShortName, netPosition, exposureLimit))
+		}
+	}
+
+	return NewRuleResult(rule, trade, len(problems) == 0, strings.Join(problems, "; ")), nil
+}
+
+// CheckCreditLimits runs CreditLimitRule over every deal of the trade date of
+// the deal types, every deal type when empty
+func (repo *NucleusTradeRepository) CheckCreditLimits(ctx context.Context, tradeDate time.Time, dealTypes []string) (*NucleusRuleCheck, error) {
+	return repo.checkTradesWithRule(ctx, "CheckCreditLimits", tradeDate, dealTypes, &CreditLimitRule{})
+}
+
+// getLarBaseIndex indexes the LarBase limits by counterparty short name once
+func (referenceData *NucleusReferenceData) getLarBaseIndex() map[string][]*common.LarBaseModel {
+	referenceData.larBaseOnce.Do(func() {
+		referenceData.larBaseIndex = make(map[string][]*common.LarBaseModel)
+		for _, larBase := range referenceData.LarBases {
+			key := normalizeReferenceName(larBase.ShortName)
+			referenceData.larBaseIndex[key] = append(referenceData.larBaseIndex[key], larBase)
+		}
+	})
+
+	return referenceData.larBaseIndex
+}
+
+// findCreditLimit picks the NUCLEUS limit that matches the trade best, the
+// first one on a tie, nil when none matches a product of its terms or its deal
+// type. The legal entity of the trade matters most, then the products of its
+// terms, then its deal type. A limit without a legal entity, whose product
+// isn't mapped to one, matches every legal entity
+func findCreditLimit(limits []*common.LarBaseModel, trade *nucleus.NucleusTradeHeaderModel) *common.LarBaseModel {
+	products := make(map[string]bool)
+	for _, term := range trade.Terms {
+		if term == nil {
+			continue
+		}
+		for _, product := range []string{term.Product1, term.Product2} {
+			if product = normalizeReferenceName(product); product != "" {
+				products[product] = true
+			}
+		}
+	}
+
+	var found *common.LarBaseModel
+	var foundRank int
+
+	for _, limit := range limits {
+		if normalizeReferenceName(limit.SourceSystem) != creditLimitSourceSystem {
+			continue
+		}
+
+		var rank int
+		if products[normalizeReferenceName(limit.Product)] {
+			rank += 2
+		}
+		if limit.DealType == trade.DealType {
+			rank++
+		}
+		if rank == 0 {
+			continue
+		}
+
+		legalEntity := normalizeReferenceName(limit.LegalEntity)
+		if legalEntity != "" {
+			if legalEntity != normalizeReferenceName(trade.LegalEntity) && legalEntity != normalizeReferenceName(trade.LegalEntityLongName) {
+				continue
+			}
+			rank += 4
+		}
+
+		if found == nil || rank > foundRank {
+			found, foundRank = limit, rank
+		}
+	}
+
+	return found
+}
+
+// addCreditTenor adds the tenor to the trade date, false when the tenor isn't
+// a duration, e.g. CALL CREDIT
+func addCreditTenor(tradeDate time.Time, tenor string) (time.Time, bool) {
+	match := creditTenorPattern.FindStringSubmatch(normalizeReferenceName(tenor))
+	if match == nil {
+		return time.Time{}, false
+	}
+
+	count, err := strconv.Atoi(match[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	switch match[2][0] {
+	case 'D':
+		return tradeDate.AddDate(0, 0, count), true
+	case 'W':
+		return tradeDate.AddDate(0, 0, 7*count), true
+	case 'M':
+		return tradeDate.AddDate(0, count, 0), true
+	default:
+		return tradeDate.AddDate(count, 0, 0), true
+	}
+}
+
+// tradeMaturity is the last end date of the trade or of its terms
+func tradeMaturity(trade *nucleus.NucleusTradeHeaderModel) time.Time {
+	maturity := trade.EndDate
+	for _, term := range trade.Terms {
+		if term != nil && term.EndDate.After(maturity) {
+			maturity = term.EndDate
+		}
+	}
+
+	return maturity
+}
+
+// tradeExposure is the sum of the volume times the fixed price of the terms,
+// negative when the trade is a sale
+func tradeExposure(trade *nucleus.NucleusTradeHeaderModel) float64 {
+	var exposure float64
+	for _, term := range trade.Terms {
+		if term != nil {
+			exposure += math.Abs(term.Volume * term.FixedPrice)
+		}
+	}
+
+	if trade.DnDirection == dealDirectionSale {
+		return -exposure
+	}
+
+	return exposure
+}
+
+// getExposureLimit reads ExposureLimit when it is a number, LimitValue
+// otherwise. false when the limit has no positive amount
+func getExposureLimit(limit *common.LarBaseModel) (float64, bool) {
+	exposureLimit, err := strconv.ParseFloat(strings.NewReplacer(",", "", "$", "", " ", "").Replace(limit.ExposureLimit), 64)
+	if err == nil && exposureLimit > 0 {
+		return exposureLimit, true
+	}
+
+	if limit.LimitValue > 0 {
+		return limit.LimitValue, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,320 @@
+package power
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrExtractionRunConflict is returned when another extraction advanced the
+// watermark of the trade date and deal type while the trades were processed,
+// or when the extraction lease expired before the run could succeed
+var ErrExtractionRunConflict = errors.New("the extraction run was advanced by another extraction or its lease expired")
+
+// the statuses of an extraction run
+const (
+	ExtractionRunStarted   = "started"
+	ExtractionRunSucceeded = "succeeded"
+	ExtractionRunFailed    = "failed"
+)
+
+// defaultExtractionWatermarkOverlap is how long before the SYSDATE of NUCLEUS
+// the next watermark is. The deal lists filter on modify_date, a DATE with no
+// fraction of second, so the deals modified in the second the SYSDATE is read
+// and the ones committed late would be missed without it. The deals extracted
+// again are unchanged when upserted
+const defaultExtractionWatermarkOverlap = 5 * time.Minute
+
+// failExtractionRunTimeout bounds the update marking a run as failed, which
+// runs even when the context of the extraction is cancelled
+const failExtractionRunTimeout = 30 * time.Second
+
+// NucleusExtractionRun is an extraction run with its lifecycle. The counts,
+// DurationMs and FinishedAt are only set once the run succeeded or failed
+type NucleusExtractionRun struct {
+	ExtractionRunId int        `json:"extractionRunId"`
+	TransactionDate time.Time  `json:"transactionDate"`
+	DealType        string     `json:"dealType"`
+	TimeParameter   time.Time  `json:"timeParameter"`
+	Status          string     `json:"status"`
+	ExtractedCount  int        `json:"extractedCount"`
+	UpsertedCount   int        `json:"upsertedCount"`
+	FailedCount     int        `json:"failedCount"`
+	DurationMs      int64      `json:"durationMs"`
+	ErrorText       string     `json:"errorText"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	FinishedAt      *time.Time `json:"finishedAt"`
+}
+
+// NucleusExtractAndProcessSummary is the outcome of ExtractAndProcessTrades.
+// LastRunTime is the watermark the deals were extracted from and
+// TimeParameter the watermark stored for the next extraction
+type NucleusExtractAndProcessSummary struct {
+	ExtractionRunId int                          `json:"extractionRunId"`
+	TradeDate       time.Time                    `json:"tradeDate"`
+	DealType        string                       `json:"dealType"`
+	OwnerId         string                       `json:"ownerId"`
+	LastRunTime     time.Time                    `json:"lastRunTime"`
+	TimeParameter   time.Time                    `json:"timeParameter"`
+	ExtractedCount  int                          `json:"extractedCount"`
+	ProcessTrades   *NucleusProcessTradesSummary `json:"processTrades"`
+}
+
+// ExtractAndProcessTrades extracts the deals of the deal type modified since
+// the last successful extraction run of the trade date and upserts them like
+// ProcessTrades. The run is stored as started before the extraction, and is
+// marked as succeeded in the transaction of the upsert, so the watermark only
+// moves when the trades are committed. A run that fails is marked as failed
+// with its error. Without a previous run the deals are extracted from
+// initialLastRunTime. The SYSDATE of NUCLEUS read before the extraction, less
+// the watermark overlap, is the next watermark, so it doesn't depend on the
+// clock of this service and the deals modified while the extraction runs are
+// extracted again the next time. The
+// trades are scored by the rules of the repository rule engine, if any. The
+// extraction lease of the trade date and deal type is held by ownerId, a
+// generated one when empty, and renewed for the whole run. It is released at
+// the end unless the owner already held it. ErrExtractionLeaseHeld is returned
+// when another owner holds it
+func (repo *NucleusTradeRepository) ExtractAndProcessTrades(ctx context.Context, tradeDate time.Time, dealType string, initialLastRunTime time.Time, ownerId string) (*NucleusExtractAndProcessSummary, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "ExtractAndProcessTrades")
+
+	loader, ok := repo.getNucDealListLoaders()[dealType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNucDealTypeNotSupported, dealType)
+	}
+
+	if ownerId == "" {
+		ownerId = newExtractionLeaseOwnerId()
+	}
+
+	acquiredAt := time.Now()
+	lease, err := repo.AcquireExtractionLease(ctx, tradeDate, dealType, ownerId, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// a lease the owner held before is left for it to release
+	leaseKeeper := repo.keepExtractionLease(ctx, tradeDate, dealType, ownerId, acquiredAt, !lease.AlreadyHeld)
+	defer leaseKeeper.stop()
+	ctx = leaseKeeper.ctx
+
+	lastExtractionRun, err := repo.GetLastExtractionRun(ctx, tradeDate, dealType)
+	if err != nil {
+		return nil, leaseKeeper.cause(err)
+	}
+
+	start := time.Now()
+
+	sysdate, err := repo.getNucleusSysdate(ctx)
+	if err != nil {
+		return nil, leaseKeeper.cause(err)
+	}
+	timeParameter := sysdate.Add(-repo.getExtractionWatermarkOverlap())
+
+	summary := &NucleusExtractAndProcessSummary{
+		TradeDate:     tradeDate,
+		DealType:      dealType,
+		OwnerId:       ownerId,
+		LastRunTime:   initialLastRunTime,
+		TimeParameter: timeParameter,
+	}
+
+	var lastExtractionRunId int
+	if lastExtractionRun != nil {
+		lastExtractionRunId = lastExtractionRun.ExtractionRunId
+		summary.LastRunTime = lastExtractionRun.TimeParameter
+	}
+
+	if err := repo.machineLearningDb.QueryRowContext(ctx, insertStartedExtractionRunQuery,
+		sql.Named("transactionDate", tradeDate),
+		sql.Named("dealType", dealType),
+		sql.Named("timeParameter", summary.TimeParameter),
+	).Scan(&summary.ExtractionRunId); err != nil {
+		logger.Debugln("error got when executing insertStartedExtractionRunQuery: ", err)
+		return nil, leaseKeeper.cause(err)
+	}
+
+	headerModels, err := loader(ctx, summary.LastRunTime, tradeDate)
+	if err != nil {
+		logger.Debugln("error extracting the deal list: ", err)
+		err = leaseKeeper.cause(err)
+		repo.failExtractionRun(summary.ExtractionRunId, 0, start, err)
+		return nil, err
+	}
+	summary.ExtractedCount = len(headerModels)
+
+	anomalyMessages, err := repo.EvaluateTrades(ctx, headerModels)
+	if err != nil {
+		err = leaseKeeper.cause(err)
+		repo.failExtractionRun(summary.ExtractionRunId, summary.ExtractedCount, start, err)
+		return nil, err
+	}
+
+	summary.ProcessTrades, err = repo.processTrades(ctx, headerModels, anomalyMessages, func(ctx context.Context, tx *sql.Tx, processTradesSummary *NucleusProcessTradesSummary) error {
+		return repo.succeedExtractionRun(ctx, tx, summary, processTradesSummary, lastExtractionRunId, start)
+	})
+	if err != nil {
+		err = leaseKeeper.cause(err)
+		repo.failExtractionRun(summary.ExtractionRunId, summary.ExtractedCount, start, err)
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// getNucleusSysdate returns the current time of NUCLEUS
+func (repo *NucleusTradeRepository) getNucleusSysdate(ctx context.Context) (time.Time, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "ExtractAndProcessTrades")
+
+	var sysdate time.Time
+	if err := repo.nucleusDb.QueryRowContext(ctx, getNucleusSysdateQuery).Scan(&sysdate); err != nil {
+		logger.Debugln("error got when executing getNucleusSysdateQuery: ", err)
+		return time.Time{}, err
+	}
+
+	return sysdate, nil
+}
+
+func (repo *NucleusTradeRepository) getExtractionWatermarkOverlap() time.Duration {
+	if repo.watermarkOverlap >= time.Second {
+		return repo.watermarkOverlap
+	}
+	return defaultExtractionWatermarkOverlap
+}
+
+// succeedExtractionRun marks the run as succeeded, unless the lease was lost
+// or another run succeeded since lastExtractionRunId was read
+func (repo *NucleusTradeRepository) succeedExtractionRun(ctx context.Context, tx *sql.Tx, summary *NucleusExtractAndProcessSummary, processTradesSummary *NucleusProcessTradesSummary, lastExtractionRunId int, start time.Time) error {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "ExtractAndProcessTrades")
+
+	var upsertedCount int
+	for _, count := range processTradesSummary.UpsertedByDealType {
+		upsertedCount += count
+	}
+
+	result, err := tx.ExecContext(ctx, succeedExtractionRunIfLatestQuery,
+		sql.Named("extractedCount", summary.ExtractedCount),
+		sql.Named("upsertedCount", upsertedCount),
+		sql.Named("failedCount", processTradesSummary.FailedCount),
+		sql.Named("durationMs", time.Since(start).Milliseconds()),
+		sql.Named("extractionRunId", summary.ExtractionRunId),
+		sql.Named("transactionDate", summary.TradeDate),
+		sql.Named("dealType", summary.DealType),
+		sql.Named("ownerId", summary.OwnerId),
+		sql.Named("lastExtractionRunId", lastExtractionRunId),
+	)
+	if err != nil {
+		logger.Debugln("error got when executing succeedExtractionRunIfLatestQuery: ", err)
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return ErrExtractionRunConflict
+	}
+
+	return nil
+}
+
+// failExtractionRun marks the run as failed with the error that stopped it.
+// Failing to do so is only logged, the run stays started
+func (repo *NucleusTradeRepository) failExtractionRun(extractionRunId int, extractedCount int, start time.Time, runErr error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "ExtractAndProcessTrades")
+
+	ctx, cancel := context.WithTimeout(context.Background(), failExtractionRunTimeout)
+	defer cancel()
+
+	if _, err := repo.machineLearningDb.ExecContext(ctx, failExtractionRunQuery,
+		sql.Named("extractedCount", extractedCount),
+		sql.Named("durationMs", time.Since(start).Milliseconds()),
+		sql.Named("errorText", runErr.Error()),
+		sql.Named("extractionRunId", extractionRunId),
+	); err != nil {
+		logger.Debugln("error got when executing failExtractionRunQuery: ", err)
+	}
+}
+
+// GetExtractionRuns lists the extraction runs of the trade dates between
+// fromTradeDate and toTradeDate. The runs of every deal type and status are
+// listed when dealType or status is empty
+func (repo *NucleusTradeRepository) GetExtractionRuns(ctx context.Context, fromTradeDate time.Time, toTradeDate time.Time, dealType string, status string) ([]*NucleusExtractionRun, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "GetExtractionRuns")
+
+	rows, err := repo.machineLearningDb.QueryContext(ctx, getExtractionRunsQuery,
+		sql.Named("fromTradeDate", fromTradeDate),
+		sql.Named("toTradeDate", toTradeDate),
+		sql.Named("dealType", sql.NullString{String: dealType, Valid: dealType != ""}),
+		sql.Named("status", sql.NullString{String: status, Valid: status != ""}),
+	)
+	if err != nil {
+		logger.Debugln("error got when executing getExtractionRunsQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	extractionRuns := []*NucleusExtractionRun{}
+
+	for rows.Next() {
+		var extractionRun NucleusExtractionRun
+
+		var extractedCount, upsertedCount, failedCount, durationMs sql.NullInt64
+		var errorText sql.NullString
+		var finishedAt sql.NullTime
+
+		if err := rows.Scan(
+			&extractionRun.ExtractionRunId, &extractionRun.TransactionDate, &extractionRun.DealType, &extractionRun.TimeParameter, &extractionRun.Status,
+			&extractedCount, &upsertedCount, &failedCount, &durationMs, &errorText, &extractionRun.CreatedAt, &finishedAt,
+		); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return nil, err
+		}
+
+		if extractedCount.Valid {
+			extractionRun.ExtractedCount = int(extractedCount.Int64)
+		}
+
+		if upsertedCount.Valid {
+			extractionRun.UpsertedCount = int(upsertedCount.Int64)
+		}
+
+		if failedCount.Valid {
+			extractionRun.FailedCount = int(failedCount.Int64)
+		}
+
+		if durationMs.Valid {
+			extractionRun.DurationMs = durationMs.Int64
+		}
+
+		if errorText.Valid {
+			extractionRun.ErrorText = errorText.String
+		}
+
+		if finishedAt.Valid {
+			extractionRun.FinishedAt = &finishedAt.Time
+		}
+
+		extractionRuns = append(extractionRuns, &extractionRun)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	return extractionRuns, nil
+}
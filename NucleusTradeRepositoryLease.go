@@ -0,0 +1,265 @@
+package power
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// defaultExtractionLeaseTTL is how long an extraction lease lasts without
+// being renewed when the repository isn't configured otherwise
+const defaultExtractionLeaseTTL = 5 * time.Minute
+
+// releaseExtractionLeaseTimeout bounds the release of the lease taken by
+// ExtractAndProcessTrades, which runs even when its context is cancelled
+const releaseExtractionLeaseTimeout = 30 * time.Second
+
+// extractionLeaseRenewRetries is the number of times a renewal that failed on
+// a database error is retried in a third of the ttl, while the lease holds
+const extractionLeaseRenewRetries = 10
+
+// ErrExtractionLeaseHeld is returned when another owner holds the extraction
+// lease of the trade date and deal type
+var ErrExtractionLeaseHeld = errors.New("the extraction lease is held by another owner")
+
+// ErrExtractionLeaseLost is returned when the lease can't be renewed because
+// it expired or was taken by another owner
+var ErrExtractionLeaseLost = errors.New("the extraction lease was lost")
+
+// NucleusExtractionLease is the lease an owner holds on the extraction of a
+// trade date and deal type until ExpiresAt. AlreadyHeld is only set by
+// AcquireExtractionLease, when the owner held the lease before the call
+type NucleusExtractionLease struct {
+	TransactionDate time.Time `json:"transactionDate"`
+	DealType        string    `json:"dealType"`
+	OwnerId         string    `json:"ownerId"`
+	AcquiredAt      time.Time `json:"acquiredAt"`
+	ExpiresAt       time.Time `json:"expiresAt"`
+	AlreadyHeld     bool      `json:"alreadyHeld"`
+}
+
+// AcquireExtractionLease takes the extraction lease of the trade date and deal
+// type for ttl, the repository default when ttl is 0. The owner already
+// holding the lease extends it. ErrExtractionLeaseHeld is returned, with the
+// current owner, when somebody else holds a lease that hasn't expired
+func (repo *NucleusTradeRepository) AcquireExtractionLease(ctx context.Context, tradeDate time.Time, dealType string, ownerId string, ttl time.Duration) (*NucleusExtractionLease, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "AcquireExtractionLease")
+
+	var lease NucleusExtractionLease
+	err := repo.machineLearningDb.QueryRowContext(ctx, acquireExtractionLeaseQuery,
+		sql.Named("transactionDate", tradeDate),
+		sql.Named("dealType", dealType),
+		sql.Named("ownerId", ownerId),
+		sql.Named("ttlMs", repo.getExtractionLeaseTTL(ttl).Milliseconds()),
+	).Scan(&lease.TransactionDate, &lease.DealType, &lease.OwnerId, &lease.AcquiredAt, &lease.ExpiresAt, &lease.AlreadyHeld)
+	if err == nil {
+		return &lease, nil
+	}
+	if err != sql.ErrNoRows {
+		logger.Debugln("error got when executing acquireExtractionLeaseQuery: ", err)
+		return nil, err
+	}
+
+	var heldLease NucleusExtractionLease
+	if err := repo.machineLearningDb.QueryRowContext(ctx, getExtractionLeaseQuery,
+		sql.Named("transactionDate", tradeDate),
+		sql.Named("dealType", dealType),
+	).Scan(&heldLease.TransactionDate, &heldLease.DealType, &heldLease.OwnerId, &heldLease.AcquiredAt, &heldLease.ExpiresAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrExtractionLeaseHeld
+		}
+		logger.Debugln("error got when executing getExtractionLeaseQuery: ", err)
+		return nil, err
+	}
+
+	return nil, fmt.Errorf("%w: %s until %s", ErrExtractionLeaseHeld, heldLease.OwnerId, heldLease.ExpiresAt.Format(time.RFC3339))
+}
+
+// RenewExtractionLease extends the lease held by the owner for ttl, the
+// repository default when ttl is 0. ErrExtractionLeaseLost is returned when
+// the owner doesn't hold the lease anymore
+func (repo *NucleusTradeRepository) RenewExtractionLease(ctx context.Context, tradeDate time.Time, dealType string, ownerId string, ttl time.Duration) (*NucleusExtractionLease, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "RenewExtractionLease")
+
+	var lease NucleusExtractionLease
+	if err := repo.machineLearningDb.QueryRowContext(ctx, renewExtractionLeaseQuery,
+		sql.Named("ttlMs", repo.getExtractionLeaseTTL(ttl).Milliseconds()),
+		sql.Named("transactionDate", tradeDate),
+		sql.Named("dealType", dealType),
+		sql.Named("ownerId", ownerId),
+	).Scan(&lease.TransactionDate, &lease.DealType, &lease.OwnerId, &lease.AcquiredAt, &lease.ExpiresAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrExtractionLeaseLost
+		}
+		logger.Debugln("error got when executing renewExtractionLeaseQuery: ", err)
+		return nil, err
+	}
+
+	return &lease, nil
+}
+
+// ReleaseExtractionLease gives the lease back, it does nothing when the owner
+// doesn't hold it
+func (repo *NucleusTradeRepository) ReleaseExtractionLease(ctx context.Context, tradeDate time.Time, dealType string, ownerId string) error {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "ReleaseExtractionLease")
+
+	if _, err := repo.machineLearningDb.ExecContext(ctx, releaseExtractionLeaseQuery,
+		sql.Named("transactionDate", tradeDate),
+		sql.Named("dealType", dealType),
+		sql.Named("ownerId", ownerId),
+	); err != nil {
+		logger.Debugln("error got when executing releaseExtractionLeaseQuery: ", err)
+		return err
+	}
+
+	return nil
+}
+
+func (repo *NucleusTradeRepository) getExtractionLeaseTTL(ttl time.Duration) time.Duration {
+	if ttl >= time.Second {
+		return ttl
+	}
+	if repo.extractionLeaseTTL >= time.Second {
+		return repo.extractionLeaseTTL
+	}
+	return defaultExtractionLeaseTTL
+}
+
+// newExtractionLeaseOwnerId identifies the callers that don't name themselves
+func newExtractionLeaseOwnerId() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), time.Now().UnixNano())
+}
+
+// extractionLeaseKeeper renews an acquired lease every third of its ttl until
+// it is stopped. A renewal failing on a database error is retried until the
+// lease expires, the expiry being measured from the time the lease was last
+// extended. When the lease is lost its context is cancelled, so the work done
+// under the lease stops
+type extractionLeaseKeeper struct {
+	repo      *NucleusTradeRepository
+	ctx       context.Context
+	cancel    context.CancelFunc
+	done      chan struct{}
+	tradeDate time.Time
+	dealType  string
+	ownerId   string
+	release   bool
+
+	mutex   sync.Mutex
+	lostErr error
+}
+
+// keepExtractionLease renews the lease extended at extendedAt. The lease is
+// only released on stop when release is set, so a caller doesn't give back a
+// lease its owner held before it
+func (repo *NucleusTradeRepository) keepExtractionLease(ctx context.Context, tradeDate time.Time, dealType string, ownerId string, extendedAt time.Time, release bool) *extractionLeaseKeeper {
+	keeper := &extractionLeaseKeeper{
+		repo:      repo,
+		done:      make(chan struct{}),
+		tradeDate: tradeDate,
+		dealType:  dealType,
+		ownerId:   ownerId,
+		release:   release,
+	}
+	keeper.ctx, keeper.cancel = context.WithCancel(ctx)
+
+	ttl := repo.getExtractionLeaseTTL(0)
+	go keeper.renew(ttl, extendedAt.Add(ttl))
+
+	return keeper
+}
+
+func (keeper *extractionLeaseKeeper) renew(ttl time.Duration, expiresAt time.Time) {
+	logger := keeper.repo.logger.GetLogger()
+	logger = logger.WithField("method", "ExtractAndProcessTrades")
+
+	defer close(keeper.done)
+
+	renewInterval := ttl / 3
+	retryInterval := renewInterval / extractionLeaseRenewRetries
+
+	timer := time.NewTimer(renewInterval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-keeper.ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		renewedAt := time.Now()
+		_, err := keeper.repo.RenewExtractionLease(keeper.ctx, keeper.tradeDate, keeper.dealType, keeper.ownerId, ttl)
+		if err == nil {
+			expiresAt = renewedAt.Add(ttl)
+			timer.Reset(renewInterval)
+			continue
+		}
+		if keeper.ctx.Err() != nil {
+			return
+		}
+
+		// the lease still holds until it expires, a database error is
+		// retried until then
+		if !errors.Is(err, ErrExtractionLeaseLost) {
+			if time.Now().Add(retryInterval).Before(expiresAt) {
+				logger.Debugln("error renewing the extraction lease, retrying: ", err)
+				timer.Reset(retryInterval)
+				continue
+			}
+			err = fmt.Errorf("%w: %v", ErrExtractionLeaseLost, err)
+		}
+
+		keeper.mutex.Lock()
+		keeper.lostErr = err
+		keeper.mutex.Unlock()
+
+		keeper.cancel()
+		return
+	}
+}
+
+// cause returns the loss of the lease when it is what stopped the work,
+// runErr otherwise
+func (keeper *extractionLeaseKeeper) cause(runErr error) error {
+	keeper.mutex.Lock()
+	defer keeper.mutex.Unlock()
+
+	if keeper.lostErr != nil {
+		return keeper.lostErr
+	}
+
+	return runErr
+}
+
+// stop stops the renewals and releases the lease when the keeper was asked to
+func (keeper *extractionLeaseKeeper) stop() {
+	logger := keeper.repo.logger.GetLogger()
+	logger = logger.WithField("method", "ExtractAndProcessTrades")
+
+	keeper.cancel()
+	<-keeper.done
+
+	if !keeper.release {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), releaseExtractionLeaseTimeout)
+	defer cancel()
+
+	if err := keeper.repo.ReleaseExtractionLease(ctx, keeper.tradeDate, keeper.dealType, keeper.ownerId); err != nil {
+		logger.Debugln("error releasing the extraction lease: ", err)
+	}
+}
@@ -0,0 +1,413 @@
+package power
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/writer"
+
+	"github.com/sede-x/RogerRogerAnomalyDetector/models/nucleus"
+)
+
+const (
+	// nucleusParquetFileName is the name of the file written in every
+	// dealType=<dealType>/tradeDate=<yyyy-mm-dd> partition
+	nucleusParquetFileName = "deals.parquet"
+	// nucleusParquetTmpFileName is the name of the file being written in a
+	// partition. The leading dot hides it from the readers of the dataset
+	nucleusParquetTmpFileName = "." + nucleusParquetFileName + ".tmp"
+	// nucleusParquetTradeDateLayout is the layout of the tradeDate partition
+	nucleusParquetTradeDateLayout = "2006-01-02"
+	// nucleusParquetWriterParallelism is the number of goroutines marshalling
+	// the rows of a single parquet file
+	nucleusParquetWriterParallelism = 4
+)
+
+// ErrNucDealTypeNotSupported is returned when a deal type has no deal list
+var ErrNucDealTypeNotSupported = errors.New(dealLookupUnsupportedDealTypeReason)
+
+// nucleusParquetDeal is the parquet schema of an exported deal, one row per
+// deal with its terms and their indexes nested as lists. Columns are only
+// ever added to it, so files exported at different times can be read together
+type nucleusParquetDeal struct {
+	DealKey             int64                 `parquet:"name=deal_key, type=INT64"`
+	DealType            string                `parquet:"name=deal_type, type=BYTE_ARRAY, convertedtype=UTF8"`
+	TotalQuantity       float64               `parquet:"name=total_quantity, type=DOUBLE"`
+	DnDirection         string                `parquet:"name=dn_direction, type=BYTE_ARRAY, convertedtype=UTF8"`
+	TransactionDate     *int64                `parquet:"name=transaction_date, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	CyCompanyKey        int64                 `parquet:"name=cy_company_key, type=INT64"`
+	Company             string                `parquet:"name=company, type=BYTE_ARRAY, convertedtype=UTF8"`
+	CompanyLongName     string                `parquet:"name=company_long_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	CompanyCode         string                `parquet:"name=company_code, type=BYTE_ARRAY, convertedtype=UTF8"`
+	CyLegalEntityKey    int64                 `parquet:"name=cy_legal_entity_key, type=INT64"`
+	LegalEntity         string                `parquet:"name=legal_entity, type=BYTE_ARRAY, convertedtype=UTF8"`
+	LegalEntityLongName string                `parquet:"name=legal_entity_long_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	InteraffiliateFlag  string                `parquet:"name=interaffiliate_flag, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Contract            string                `parquet:"name=contract, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ConfirmFormat       string                `parquet:"name=confirm_format, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Region              string                `parquet:"name=region, type=BYTE_ARRAY, convertedtype=UTF8"`
+	HsHedgeKey          string                `parquet:"name=hs_hedge_key, type=BYTE_ARRAY, convertedtype=UTF8"`
+	PrtPortfolio        int64                 `parquet:"name=prt_portfolio, type=INT64"`
+	Portfolio           string                `parquet:"name=portfolio, type=BYTE_ARRAY, convertedtype=UTF8"`
+	UrTrader            string                `parquet:"name=ur_trader, type=BYTE_ARRAY, convertedtype=UTF8"`
+	IbPrtPortfolio      int64                 `parquet:"name=ib_prt_portfolio, type=INT64"`
+	IbPortfolio         string                `parquet:"name=ib_portfolio, type=BYTE_ARRAY, convertedtype=UTF8"`
+	IbUrTrader          string                `parquet:"name=ib_ur_trader, type=BYTE_ARRAY, convertedtype=UTF8"`
+	TzTimeZone          string                `parquet:"name=tz_time_zone, type=BYTE_ARRAY, convertedtype=UTF8"`
+	TzExerciseZone      string                `parquet:"name=tz_exercise_zone, type=BYTE_ARRAY, convertedtype=UTF8"`
+	HasBroker           string                `parquet:"name=has_broker, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Broker              string                `parquet:"name=broker, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ExercisedOptionKey  int64                 `parquet:"name=exercised_option_key, type=INT64"`
+	ExoticFlag          string                `parquet:"name=exotic_flag, type=BYTE_ARRAY, convertedtype=UTF8"`
+	StartDate           *int64                `parquet:"name=start_date, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	EndDate             *int64                `parquet:"name=end_date, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	ExecutionTime       *int64                `parquet:"name=execution_time, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	CreatedBy           string                `parquet:"name=created_by, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ModifiedBy          string                `parquet:"name=modified_by, type=BYTE_ARRAY, convertedtype=UTF8"`
+	CreatedAt           *int64                `parquet:"name=created_at, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	ModifiedAt          *int64                `parquet:"name=modified_at, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	Terms               []*nucleusParquetTerm `parquet:"name=terms, type=LIST"`
+}
+
+type nucleusParquetTerm struct {
+	VolSeq          int64                  `parquet:"name=vol_seq, type=INT64"`
+	BegDate         *int64                 `parquet:"name=beg_date, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	EndDate         *int64                 `parquet:"name=end_date, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	Volume          float64                `parquet:"name=volume, type=DOUBLE"`
+	FixedPrice      float64                `parquet:"name=fixed_price, type=DOUBLE"`
+	PriceType       string                 `parquet:"name=price_type, type=BYTE_ARRAY, convertedtype=UTF8"`
+	HolidaySchedule string                 `parquet:"name=holiday_schedule, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Pool1           string                 `parquet:"name=pool1, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Product1        string                 `parquet:"name=product1, type=BYTE_ARRAY, convertedtype=UTF8"`
+	PointCode1      string                 `parquet:"name=point_code1, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Formula1        string                 `parquet:"name=formula1, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Indexes1        []*nucleusParquetIndex `parquet:"name=indexes1, type=LIST"`
+	Pool2           string                 `parquet:"name=pool2, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Product2        string                 `parquet:"name=product2, type=BYTE_ARRAY, convertedtype=UTF8"`
+	PointCode2      string                 `parquet:"name=point_code2, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Formula2        string                 `parquet:"name=formula2, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Indexes2        []*nucleusParquetIndex `parquet:"name=indexes2, type=LIST"`
+}
+
+type nucleusParquetIndex struct {
+	VolSeq      int64  `parquet:"name=vol_seq, type=INT64"`
+	Publication string `parquet:"name=publication, type=BYTE_ARRAY, convertedtype=UTF8"`
+	PubIndex    string `parquet:"name=pub_index, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Frequency   string `parquet:"name=frequency, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+// NucleusParquetExportFile is a parquet file written by
+// ExportNucDealListsToParquet
+type NucleusParquetExportFile struct {
+	DealType  string    `json:"dealType"`
+	TradeDate time.Time `json:"tradeDate"`
+	Path      string    `json:"path"`
+	Rows      int64     `json:"rows"`
+}
+
+// nucleusParquetPartitionWriter writes the deals of a single
+// dealType/tradeDate partition to a temporary file, renamed once complete so
+// readers never see a half written file
+type nucleusParquetPartitionWriter struct {
+	file       source.ParquetFile
+	writer     *writer.ParquetWriter
+	tmpPath    string
+	exportFile *NucleusParquetExportFile
+}
+
+// ExportNucDealListsToParquet writes every deal of the trade dates in the
+// range to outputDir/dealType=<dealType>/tradeDate=<yyyy-mm-dd>/deals.parquet,
+// replacing the partitions exported before. A partition whose deal list is
+// now empty is removed. An empty dealTypes exports every deal type. The deals
+// are streamed, so a trade date is never fully in memory
+func (repo *NucleusTradeRepository) ExportNucDealListsToParquet(ctx context.Context, fromTradeDate time.Time, toTradeDate time.Time, dealTypes []string, outputDir string) ([]*NucleusParquetExportFile, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "ExportNucDealListsToParquet")
+
+	streamers := repo.getNucDealListStreamers()
+
+	// the deal types listed with another one are written in their own
+	// partitions, which are stale when none of their deals is listed anymore
+	partitionDealTypes := make(map[string][]string)
+	for dealType, methods := range repo.getNucDealTypeMethods() {
+		listDealType := dealType
+		if methods.listedWith != "" {
+			listDealType = methods.listedWith
+		}
+		partitionDealTypes[listDealType] = append(partitionDealTypes[listDealType], dealType)
+	}
+
+	if len(dealTypes) == 0 {
+		for dealType := range streamers {
+			dealTypes = append(dealTypes, dealType)
+		}
+	}
+
+	var exportDealTypes []string
+	for _, dealType := range dealTypes {
+		dealType = strings.ToUpper(strings.TrimSpace(dealType))
+		if _, ok := streamers[dealType]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrNucDealTypeNotSupported, dealType)
+		}
+		exportDealTypes = append(exportDealTypes, dealType)
+	}
+	sort.Strings(exportDealTypes)
+
+	var exportFiles []*NucleusParquetExportFile
+	for tradeDate := fromTradeDate; !tradeDate.After(toTradeDate); tradeDate = tradeDate.AddDate(0, 0, 1) {
+		for _, dealType := range exportDealTypes {
+			files, err := repo.exportNucDealListToParquet(ctx, tradeDate, dealType, partitionDealTypes[dealType], streamers[dealType], outputDir)
+			if err != nil {
+				logger.Debugln("error exporting "+dealType+" deal list to parquet: ", err)
+				return nil, err
+			}
+			exportFiles = append(exportFiles, files...)
+		}
+	}
+
+	return exportFiles, nil
+}
+
+// exportNucDealListToParquet streams the whole deal list of a single deal type
+// and trade date in its partitions. The partition comes from the DealType of
+// each deal, the deal type of the list is only used when a deal has none. The
+// partitions of staleDealTypes that got no deal are removed
+func (repo *NucleusTradeRepository) exportNucDealListToParquet(ctx context.Context, tradeDate time.Time, listDealType string, staleDealTypes []string, streamDealList func(ctx context.Context, lastRunTime time.Time, tradeDate time.Time, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error, outputDir string) ([]*NucleusParquetExportFile, error) {
+	partitionWriters := make(map[string]*nucleusParquetPartitionWriter)
+	var partitionDealTypes []string
+
+	err := streamDealList(ctx, time.Time{}, tradeDate, func(headerModel *nucleus.NucleusTradeHeaderModel) error {
+		dealType := headerModel.DealType
+		if dealType == "" {
+			dealType = listDealType
+		}
+
+		partitionWriter, ok := partitionWriters[dealType]
+		if !ok {
+			var err error
+			partitionWriter, err = newNucleusParquetPartitionWriter(outputDir, dealType, tradeDate)
+			if err != nil {
+				return err
+			}
+			partitionWriters[dealType] = partitionWriter
+			partitionDealTypes = append(partitionDealTypes, dealType)
+		}
+
+		if err := partitionWriter.writer.Write(toNucleusParquetDeal(headerModel)); err != nil {
+			return err
+		}
+		partitionWriter.exportFile.Rows++
+
+		return nil
+	})
+	if err != nil {
+		for _, partitionWriter := range partitionWriters {
+			partitionWriter.abort()
+		}
+		return nil, err
+	}
+
+	var exportFiles []*NucleusParquetExportFile
+	for index, dealType := range partitionDealTypes {
+		if err := partitionWriters[dealType].commit(); err != nil {
+			for _, pendingDealType := range partitionDealTypes[index+1:] {
+				partitionWriters[pendingDealType].abort()
+			}
+			return nil, err
+		}
+		exportFiles = append(exportFiles, partitionWriters[dealType].exportFile)
+	}
+
+	for _, dealType := range staleDealTypes {
+		if _, ok := partitionWriters[dealType]; ok {
+			continue
+		}
+		if err := removeNucleusParquetPartition(outputDir, dealType, tradeDate); err != nil {
+			return nil, err
+		}
+	}
+
+	return exportFiles, nil
+}
+
+func getNucleusParquetPartitionDir(outputDir string, dealType string, tradeDate time.Time) string {
+	return filepath.Join(outputDir, "dealType="+dealType, "tradeDate="+tradeDate.Format(nucleusParquetTradeDateLayout))
+}
+
+// removeNucleusParquetPartition removes the file of a partition exported
+// before, and the partition directory once it is empty
+func removeNucleusParquetPartition(outputDir string, dealType string, tradeDate time.Time) error {
+	partitionDir := getNucleusParquetPartitionDir(outputDir, dealType, tradeDate)
+
+	if err := os.Remove(filepath.Join(partitionDir, nucleusParquetFileName)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// the directory is kept when something else is still in it
+	os.Remove(partitionDir)
+
+	return nil
+}
+
+func newNucleusParquetPartitionWriter(outputDir string, dealType string, tradeDate time.Time) (*nucleusParquetPartitionWriter, error) {
+	if dealType == "." || dealType == ".." || strings.ContainsAny(dealType, `/\`) {
+		return nil, fmt.Errorf("deal type %q can't be used as a partition", dealType)
+	}
+
+	partitionDir := getNucleusParquetPartitionDir(outputDir, dealType, tradeDate)
+	if err := os.MkdirAll(partitionDir, 0755); err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(partitionDir, nucleusParquetFileName)
+	tmpPath := filepath.Join(partitionDir, nucleusParquetTmpFileName)
+
+	file, err := local.NewLocalFileWriter(tmpPath)
+	if err != nil {
+		return nil, err
+	}
+
+	parquetWriter, err := writer.NewParquetWriter(file, new(nucleusParquetDeal), nucleusParquetWriterParallelism)
+	if err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return nil, err
+	}
+
+	return &nucleusParquetPartitionWriter{
+		file:    file,
+		writer:  parquetWriter,
+		tmpPath: tmpPath,
+		exportFile: &NucleusParquetExportFile{
+			DealType:  dealType,
+			TradeDate: tradeDate,
+			Path:      path,
+		},
+	}, nil
+}
+
+func (w *nucleusParquetPartitionWriter) commit() error {
+	if err := w.writer.WriteStop(); err != nil {
+		w.file.Close()
+		os.Remove(w.tmpPath)
+		return err
+	}
+
+	if err := w.file.Close(); err != nil {
+		os.Remove(w.tmpPath)
+		return err
+	}
+
+	return os.Rename(w.tmpPath, w.exportFile.Path)
+}
+
+func (w *nucleusParquetPartitionWriter) abort() {
+	w.file.Close()
+	os.Remove(w.tmpPath)
+}
+
+func toNucleusParquetDeal(headerModel *nucleus.NucleusTradeHeaderModel) *nucleusParquetDeal {
+	deal := &nucleusParquetDeal{
+		DealKey:             int64(headerModel.DealKey),
+		DealType:            headerModel.DealType,
+		TotalQuantity:       float64(headerModel.TotalQuantity),
+		DnDirection:         headerModel.DnDirection,
+		TransactionDate:     toParquetTimestamp(headerModel.TransactionDate),
+		CyCompanyKey:        int64(headerModel.CyCompanyKey),
+		Company:             headerModel.Company,
+		CompanyLongName:     headerModel.CompanyLongName,
+		CompanyCode:         headerModel.CompanyCode,
+		CyLegalEntityKey:    int64(headerModel.CyLegalEntityKey),
+		LegalEntity:         headerModel.LegalEntity,
+		LegalEntityLongName: headerModel.LegalEntityLongName,
+		InteraffiliateFlag:  headerModel.InteraffiliateFlag,
+		Contract:            headerModel.Contract,
+		ConfirmFormat:       headerModel.ConfirmFormat,
+		Region:              headerModel.Region,
+		HsHedgeKey:          headerModel.HsHedgeKey,
+		PrtPortfolio:        int64(headerModel.PrtPortfolio),
+		Portfolio:           headerModel.Portfolio,
+		UrTrader:            headerModel.UrTrader,
+		IbPrtPortfolio:      int64(headerModel.IbPrtPortfolio),
+		IbPortfolio:         headerModel.IbPortfolio,
+		IbUrTrader:          headerModel.IbUrTrader,
+		TzTimeZone:          headerModel.TzTimeZone,
+		TzExerciseZone:      headerModel.TzExerciseZone,
+		HasBroker:           headerModel.HasBroker,
+		Broker:              headerModel.Broker,
+		ExercisedOptionKey:  int64(headerModel.ExercisedOptionKey),
+		ExoticFlag:          headerModel.ExoticFlag,
+		StartDate:           toParquetTimestamp(headerModel.StartDate),
+		EndDate:             toParquetTimestamp(headerModel.EndDate),
+		ExecutionTime:       toParquetTimestamp(headerModel.ExecutionTime),
+		CreatedBy:           headerModel.CreatedBy,
+		ModifiedBy:          headerModel.ModifiedBy,
+		CreatedAt:           toParquetTimestamp(headerModel.CreatedAt),
+		ModifiedAt:          toParquetTimestamp(headerModel.ModifiedAt),
+	}
+
+	for _, termModel := range headerModel.Terms {
+		if termModel == nil {
+			continue
+		}
+
+		deal.Terms = append(deal.Terms, &nucleusParquetTerm{
+			VolSeq:          int64(termModel.VolSeq),
+			BegDate:         toParquetTimestamp(termModel.BegDate),
+			EndDate:         toParquetTimestamp(termModel.EndDate),
+			Volume:          float64(termModel.Volume),
+			FixedPrice:      float64(termModel.FixedPrice),
+			PriceType:       termModel.PriceType,
+			HolidaySchedule: termModel.HolidaySchedule,
+			Pool1:           termModel.Pool1,
+			Product1:        termModel.Product1,
+			PointCode1:      termModel.PointCode1,
+			Formula1:        termModel.Formula1,
+			Indexes1:        toNucleusParquetIndexes(termModel.Indexes1),
+			Pool2:           termModel.Pool2,
+			Product2:        termModel.Product2,
+			PointCode2:      termModel.PointCode2,
+			Formula2:        termModel.Formula2,
+			Indexes2:        toNucleusParquetIndexes(termModel.Indexes2),
+		})
+	}
+
+	return deal
+}
+
+func toNucleusParquetIndexes(indexModels []*nucleus.NucleusTradeIndexModel) []*nucleusParquetIndex {
+	var indexes []*nucleusParquetIndex
+	for _, indexModel := range indexModels {
+		if indexModel == nil {
+			continue
+		}
+
+		indexes = append(indexes, &nucleusParquetIndex{
+			VolSeq:      int64(indexModel.VolSeq),
+			Publication: indexModel.Publication,
+			PubIndex:    indexModel.PubIndex,
+			Frequency:   indexModel.Frequency,
+		})
+	}
+
+	return indexes
+}
+
+// toParquetTimestamp returns the time in milliseconds since the epoch, or nil
+// for the zero time so it is stored as null
+func toParquetTimestamp(value time.Time) *int64 {
+	if value.IsZero() {
+		return nil
+	}
+
+	milliseconds := value.UnixNano() / int64(time.Millisecond)
+	return &milliseconds
+}
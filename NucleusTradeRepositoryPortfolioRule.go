@@ -0,0 +1,113 @@
+package power
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sede-x/RogerRogerAnomalyDetector/models/common"
+	"github.com/sede-x/RogerRogerAnomalyDetector/models/nucleus"
+)
+
+// PortfolioLegalEntityRuleName is the model name of the results of
+// PortfolioLegalEntityRule
+const PortfolioLegalEntityRuleName = "PortfolioLegalEntity"
+
+// PortfolioLegalEntityRule checks the portfolio of a trade against the
+// approved Portfolio to LegalEntity mapping of NUCLEUS. The trade is flagged
+// when its portfolio isn't mapped or is mapped to another legal entity. The
+// interbook portfolio of an interbook swap must be mapped too, to the legal
+// entity of the internal counterparty of the trade, by its company or
+// company long name. A mapping matches a portfolio by name or by key
+type PortfolioLegalEntityRule struct{}
+
+func (rule *PortfolioLegalEntityRule) Name() string {
+	return PortfolioLegalEntityRuleName
+}
+
+func (rule *PortfolioLegalEntityRule) Evaluate(trade *nucleus.NucleusTradeHeaderModel, referenceData *NucleusReferenceData) (common.IModelBasePayload, error) {
+	mappings := referenceData.getPortfolioRiskMappingIndex()
+
+	var problems []string
+
+	portfolioMappings := findPortfolioMappings(mappings, trade.Portfolio, trade.PrtPortfolio)
+	switch {
+	case len(portfolioMappings) == 0:
+		problems = append(problems, fmt.Sprintf("portfolio %s (%d) is missing from the portfolio risk mapping", trade.Portfolio, trade.PrtPortfolio))
+	case !hasMappedLegalEntity(portfolioMappings, trade.LegalEntity):
+		problems = append(problems, fmt.Sprintf("portfolio %s (%d) is mapped to legal entity %s, not %s", trade.Portfolio, trade.PrtPortfolio, joinMappedLegalEntities(portfolioMappings), trade.LegalEntity))
+	}
+
+	if trade.IbPrtPortfolio != 0 || trade.IbPortfolio != "" {
+		ibPortfolioMappings := findPortfolioMappings(mappings, trade.IbPortfolio, trade.IbPrtPortfolio)
+		switch {
+		case len(ibPortfolioMappings) == 0:
+			problems = append(problems, fmt.Sprintf("interbook portfolio %s (%d) is missing from the portfolio risk mapping", trade.IbPortfolio, trade.IbPrtPortfolio))
+		case !hasMappedLegalEntity(ibPortfolioMappings, trade.Company) && !hasMappedLegalEntity(ibPortfolioMappings, trade.CompanyLongName):
+			problems = append(problems, fmt.Sprintf("interbook portfolio %s (%d) is mapped to legal entity %s, not the internal counterparty %s", trade.IbPortfolio, trade.IbPrtPortfolio, joinMappedLegalEntities(ibPortfolioMappings), trade.Company))
+		}
+	}
+
+	return NewRuleResult(rule, trade, len(problems) == 0, strings.Join(problems, "; ")), nil
+}
+
+// CheckPortfolioLegalEntities runs PortfolioLegalEntityRule over every deal of
+// the trade date of the deal types, every deal type when empty
+func (repo *NucleusTradeRepository) CheckPortfolioLegalEntities(ctx context.Context, tradeDate time.Time, dealTypes []string) (*NucleusRuleCheck, error) {
+	return repo.checkTradesWithRule(ctx, "CheckPortfolioLegalEntities", tradeDate, dealTypes, &PortfolioLegalEntityRule{})
+}
+
+// getPortfolioRiskMappingIndex indexes the mappings by portfolio once, so the
+// trades of a bulk check don't scan them every time
+func (referenceData *NucleusReferenceData) getPortfolioRiskMappingIndex() map[string][]*nucleus.PortfolioRiskMappingModel {
+	referenceData.portfolioRiskMappingOnce.Do(func() {
+		referenceData.portfolioRiskMappingIndex = make(map[string][]*nucleus.PortfolioRiskMappingModel)
+		for _, mapping := range referenceData.PortfolioRiskMappings {
+			key := normalizeReferenceName(mapping.Portfolio)
+			referenceData.portfolioRiskMappingIndex[key] = append(referenceData.portfolioRiskMappingIndex[key], mapping)
+		}
+	})
+
+	return referenceData.portfolioRiskMappingIndex
+}
+
+func findPortfolioMappings(mappings map[string][]*nucleus.PortfolioRiskMappingModel, portfolio string, prtPortfolio int) []*nucleus.PortfolioRiskMappingModel {
+	if portfolio != "" {
+		if portfolioMappings, ok := mappings[normalizeReferenceName(portfolio)]; ok {
+			return portfolioMappings
+		}
+	}
+
+	if prtPortfolio != 0 {
+		return mappings[strconv.Itoa(prtPortfolio)]
+	}
+
+	return nil
+}
+
+func hasMappedLegalEntity(mappings []*nucleus.PortfolioRiskMappingModel, legalEntity string) bool {
+	for _, mapping := range mappings {
+		if mapping.LegalEntity != "" && normalizeReferenceName(mapping.LegalEntity) == normalizeReferenceName(legalEntity) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func joinMappedLegalEntities(mappings []*nucleus.PortfolioRiskMappingModel) string {
+	var legalEntities []string
+	for _, mapping := range mappings {
+		legalEntities = append(legalEntities, mapping.LegalEntity)
+	}
+
+	return strings.Join(legalEntities, "/")
+}
+
+// normalizeReferenceName compares the names of the reference data regardless
+// of case and surrounding spaces
+func normalizeReferenceName(name string) string {
+	return strings.ToUpper(strings.TrimSpace(name))
+}
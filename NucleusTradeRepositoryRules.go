@@ -0,0 +1,301 @@
+package power
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sede-x/RogerRogerAnomalyDetector/models/common"
+	"github.com/sede-x/RogerRogerAnomalyDetector/models/nucleus"
+)
+
+// anomalyRulesEnv is the environment variable holding the comma separated
+// names of the enabled anomaly rules
+const anomalyRulesEnv = "NUCLEUS_ANOMALY_RULES"
+
+// the scored labels of a rule result, a trade with a "NO" is an anomaly
+const (
+	RuleScoredLabelYes = "YES"
+	RuleScoredLabelNo  = "NO"
+)
+
+// NucleusReferenceData is the reference data the anomaly rules check the
+// trades against
+type NucleusReferenceData struct {
+	PortfolioRiskMappings []*nucleus.PortfolioRiskMappingModel
+	LarBases              []*common.LarBaseModel
+
+	portfolioRiskMappingOnce  sync.Once
+	portfolioRiskMappingIndex map[string][]*nucleus.PortfolioRiskMappingModel
+	larBaseOnce               sync.Once
+	larBaseIndex              map[string][]*common.LarBaseModel
+}
+
+// NucleusAnomalyRule checks a trade. Evaluate returns a result scored
+// RuleScoredLabelYes when the trade passes the rule and RuleScoredLabelNo
+// when it doesn't, or nil when the rule doesn't apply to the trade
+type NucleusAnomalyRule interface {
+	Name() string
+	Evaluate(trade *nucleus.NucleusTradeHeaderModel, referenceData *NucleusReferenceData) (common.IModelBasePayload, error)
+}
+
+// NewRuleResult builds the result of a rule for the trade
+func NewRuleResult(rule NucleusAnomalyRule, trade *nucleus.NucleusTradeHeaderModel, passed bool, message string) common.IModelBasePayload {
+	scoredLabel := RuleScoredLabelYes
+	if !passed {
+		scoredLabel = RuleScoredLabelNo
+	}
+
+	return &common.ResultModelBasePayload{
+		ModelName:   rule.Name(),
+		Message:     message,
+		DealKey:     trade.DealKey,
+		DealType:    trade.DealType,
+		ScoredLabel: scoredLabel,
+	}
+}
+
+// NucleusRuleEngine evaluates the enabled rules registered for the deal type
+// of every trade
+type NucleusRuleEngine struct {
+	mutex        sync.RWMutex
+	enabledRules map[string]bool
+	rules        map[string][]NucleusAnomalyRule
+	allDealTypes []NucleusAnomalyRule
+}
+
+// NewNucleusRuleEngine builds an engine that only evaluates the rules named
+// in enabledRules
+func NewNucleusRuleEngine(enabledRules []string) *NucleusRuleEngine {
+	engine := &NucleusRuleEngine{
+		enabledRules: make(map[string]bool),
+		rules:        make(map[string][]NucleusAnomalyRule),
+	}
+
+	for _, name := range enabledRules {
+		engine.enabledRules[name] = true
+	}
+
+	return engine
+}
+
+// NewDefaultNucleusRuleEngine builds an engine with the built-in rules
+// registered for every deal type, only the ones named in enabledRules are
+// evaluated
+func NewDefaultNucleusRuleEngine(enabledRules []string) *NucleusRuleEngine {
+	engine := NewNucleusRuleEngine(enabledRules)
+	engine.Register(&PortfolioLegalEntityRule{})
+	engine.Register(&CreditLimitRule{})
+
+	return engine
+}
+
+// LoadNucleusAnomalyRuleNames reads the names of the enabled anomaly rules
+// from anomalyRulesEnv
+func LoadNucleusAnomalyRuleNames() []string {
+	var names []string
+	for _, name := range strings.Split(os.Getenv(anomalyRulesEnv), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// Register adds the rule for the deal types, every deal type when none is
+// given. The rule is only evaluated when it is enabled
+func (engine *NucleusRuleEngine) Register(rule NucleusAnomalyRule, dealTypes ...string) {
+	engine.mutex.Lock()
+	defer engine.mutex.Unlock()
+
+	if len(dealTypes) == 0 {
+		engine.allDealTypes = append(engine.allDealTypes, rule)
+		return
+	}
+
+	for _, dealType := range dealTypes {
+		engine.rules[dealType] = append(engine.rules[dealType], rule)
+	}
+}
+
+// HasRules tells whether an enabled rule is registered for the deal type
+func (engine *NucleusRuleEngine) HasRules(dealType string) bool {
+	return len(engine.getRules(dealType)) > 0
+}
+
+// HasRulesForTrades tells whether an enabled rule is registered for the deal
+// type of one of the trades
+func (engine *NucleusRuleEngine) HasRulesForTrades(trades []*nucleus.NucleusTradeHeaderModel) bool {
+	checked := make(map[string]bool)
+	for _, trade := range trades {
+		if trade == nil || checked[trade.DealType] {
+			continue
+		}
+		if engine.HasRules(trade.DealType) {
+			return true
+		}
+		checked[trade.DealType] = true
+	}
+
+	return false
+}
+
+// Evaluate runs the rules of every trade and returns their results by deal
+// key, ready to be given to ProcessTrades
+func (engine *NucleusRuleEngine) Evaluate(trades []*nucleus.NucleusTradeHeaderModel, referenceData *NucleusReferenceData) (map[int][]common.IModelBasePayload, error) {
+	anomalyMessages := make(map[int][]common.IModelBasePayload)
+
+	for _, trade := range trades {
+		for _, rule := range engine.getRules(trade.DealType) {
+			result, err := rule.Evaluate(trade, referenceData)
+			if err != nil {
+				return nil, fmt.Errorf("rule %s failed for deal %d: %w", rule.Name(), trade.DealKey, err)
+			}
+			if result != nil {
+				anomalyMessages[trade.DealKey] = append(anomalyMessages[trade.DealKey], result)
+			}
+		}
+	}
+
+	return anomalyMessages, nil
+}
+
+func (engine *NucleusRuleEngine) getRules(dealType string) []NucleusAnomalyRule {
+	engine.mutex.RLock()
+	defer engine.mutex.RUnlock()
+
+	var rules []NucleusAnomalyRule
+	for _, registered := range [][]NucleusAnomalyRule{engine.allDealTypes, engine.rules[dealType]} {
+		for _, rule := range registered {
+			if engine.enabledRules[rule.Name()] {
+				rules = append(rules, rule)
+			}
+		}
+	}
+
+	return rules
+}
+
+// GetNucleusReferenceData loads the reference data the anomaly rules need
+func (repo *NucleusTradeRepository) GetNucleusReferenceData(ctx context.Context) (*NucleusReferenceData, error) {
+	portfolioRiskMappings, err := repo.GetPortfolioRiskMappingList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	larBases, err := repo.GetLarBaselist(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NucleusReferenceData{
+		PortfolioRiskMappings: portfolioRiskMappings,
+		LarBases:              larBases,
+	}, nil
+}
+
+// EvaluateTrades runs the rules of the repository rule engine over the
+// trades, nil results without an engine or rules for the deal types of the
+// trades. A deal list can hold several deal types, e.g. the POWER one holds
+// the PWRNSD deals
+func (repo *NucleusTradeRepository) EvaluateTrades(ctx context.Context, trades []*nucleus.NucleusTradeHeaderModel) (map[int][]common.IModelBasePayload, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "EvaluateTrades")
+
+	if repo.ruleEngine == nil || !repo.ruleEngine.HasRulesForTrades(trades) {
+		return nil, nil
+	}
+
+	referenceData, err := repo.GetNucleusReferenceData(ctx)
+	if err != nil {
+		logger.Debugln("error loading the reference data: ", err)
+		return nil, err
+	}
+
+	anomalyMessages, err := repo.ruleEngine.Evaluate(trades, referenceData)
+	if err != nil {
+		logger.Debugln("error evaluating the rules: ", err)
+		return nil, err
+	}
+
+	return anomalyMessages, nil
+}
+
+// NucleusRuleCheck is the outcome of a rule over the trades of a trade date.
+// Anomalies only holds the results scored RuleScoredLabelNo, and Errors the
+// deal types whose deal list couldn't be extracted
+type NucleusRuleCheck struct {
+	TradeDate    time.Time                  `json:"tradeDate"`
+	RuleName     string                     `json:"ruleName"`
+	DealTypes    []string                   `json:"dealTypes"`
+	CheckedCount int                        `json:"checkedCount"`
+	FlaggedCount int                        `json:"flaggedCount"`
+	Anomalies    []common.IModelBasePayload `json:"anomalies"`
+	Errors       map[string]string          `json:"errors"`
+}
+
+// checkTradesWithRule runs the rule over every deal of the trade date of the
+// deal types, every deal type with a deal list when empty. A deal type that
+// can't be extracted doesn't stop the others
+func (repo *NucleusTradeRepository) checkTradesWithRule(ctx context.Context, method string, tradeDate time.Time, dealTypes []string, rule NucleusAnomalyRule) (*NucleusRuleCheck, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", method)
+
+	loaders := repo.getNucDealListLoaders()
+	if len(dealTypes) == 0 {
+		dealTypes = repo.GetNucDealTypes()
+	}
+	for _, dealType := range dealTypes {
+		if _, ok := loaders[dealType]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrNucDealTypeNotSupported, dealType)
+		}
+	}
+
+	referenceData, err := repo.GetNucleusReferenceData(ctx)
+	if err != nil {
+		logger.Debugln("error loading the reference data: ", err)
+		return nil, err
+	}
+
+	check := &NucleusRuleCheck{
+		TradeDate: tradeDate,
+		RuleName:  rule.Name(),
+		DealTypes: dealTypes,
+		Anomalies: []common.IModelBasePayload{},
+		Errors:    make(map[string]string),
+	}
+
+	for _, dealType := range dealTypes {
+		trades, err := loaders[dealType](ctx, time.Time{}, tradeDate)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			logger.Debugln("error getting the deal list for deal type "+dealType+": ", err)
+			check.Errors[dealType] = err.Error()
+			continue
+		}
+
+		for _, trade := range trades {
+			result, err := rule.Evaluate(trade, referenceData)
+			if err != nil {
+				return nil, fmt.Errorf("rule %s failed for deal %d: %w", rule.Name(), trade.DealKey, err)
+			}
+			if result == nil {
+				continue
+			}
+
+			check.CheckedCount++
+			if result.GetScoredLabel() == RuleScoredLabelNo {
+				check.FlaggedCount++
+				check.Anomalies = append(check.Anomalies, result)
+			}
+		}
+	}
+
+	return check, nil
+}
@@ -0,0 +1,284 @@
+package power
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sede-x/RogerRogerAnomalyDetector/logger"
+)
+
+const (
+	// extractionIntervalEnv is the environment variable holding the default
+	// interval between two scheduled extractions of a deal type, e.g. 15m
+	extractionIntervalEnv = "NUCLEUS_EXTRACTION_INTERVAL"
+	// extractionJitterEnv is the environment variable holding the default
+	// maximum random delay added to every scheduled extraction
+	extractionJitterEnv = "NUCLEUS_EXTRACTION_JITTER"
+	// extractionDisabledDealTypesEnv is the environment variable holding the
+	// comma separated deal types that aren't scheduled
+	extractionDisabledDealTypesEnv = "NUCLEUS_EXTRACTION_DISABLED_DEAL_TYPES"
+	// extractionLookbackDaysEnv is the environment variable holding the
+	// default number of previous trade dates extracted on every run, so the
+	// late amendments of a trade are picked up
+	extractionLookbackDaysEnv = "NUCLEUS_EXTRACTION_LOOKBACK_DAYS"
+	// extractionSchedulerEnabledEnv is the environment variable that starts
+	// the scheduled extractions with the http handlers when true
+	extractionSchedulerEnabledEnv = "NUCLEUS_EXTRACTION_SCHEDULER_ENABLED"
+	// defaultExtractionInterval is used when extractionIntervalEnv isn't set
+	defaultExtractionInterval = 15 * time.Minute
+	// maxExtractionLookbackDays is the most previous trade dates a run extracts
+	maxExtractionLookbackDays = 31
+)
+
+// NucleusExtractionSchedule is the cadence of the scheduled extractions of a
+// deal type. Every run waits Interval plus a random delay up to Jitter, and
+// extracts the current trade date and the LookbackDays trade dates before it
+type NucleusExtractionSchedule struct {
+	Enabled      bool          `json:"enabled"`
+	Interval     time.Duration `json:"interval"`
+	Jitter       time.Duration `json:"jitter"`
+	LookbackDays int           `json:"lookbackDays"`
+}
+
+// GetNucDealTypes returns the deal types with a deal list, sorted
+func (repo *NucleusTradeRepository) GetNucDealTypes() []string {
+	var dealTypes []string
+	for dealType := range repo.getNucDealListLoaders() {
+		dealTypes = append(dealTypes, dealType)
+	}
+	sort.Strings(dealTypes)
+
+	return dealTypes
+}
+
+// LoadNucleusExtractionSchedules builds the schedules of the deal types from
+// the environment. extractionIntervalEnv, extractionJitterEnv and
+// extractionLookbackDaysEnv set the defaults, which a deal type overrides with
+// the same variable suffixed by its name, e.g. NUCLEUS_EXTRACTION_INTERVAL_POWER.
+// The deal types listed in extractionDisabledDealTypesEnv are disabled
+func LoadNucleusExtractionSchedules(dealTypes []string) (map[string]*NucleusExtractionSchedule, error) {
+	defaultInterval, err := getExtractionDurationEnv(extractionIntervalEnv, defaultExtractionInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultJitter, err := getExtractionDurationEnv(extractionJitterEnv, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultLookbackDays, err := getExtractionDaysEnv(extractionLookbackDaysEnv, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	disabled := make(map[string]bool)
+	for _, dealType := range strings.Split(os.Getenv(extractionDisabledDealTypesEnv), ",") {
+		if dealType = strings.TrimSpace(dealType); dealType != "" {
+			disabled[dealType] = true
+		}
+	}
+
+	schedules := make(map[string]*NucleusExtractionSchedule)
+	for _, dealType := range dealTypes {
+		interval, err := getExtractionDurationEnv(extractionIntervalEnv+"_"+dealType, defaultInterval)
+		if err != nil {
+			return nil, err
+		}
+
+		jitter, err := getExtractionDurationEnv(extractionJitterEnv+"_"+dealType, defaultJitter)
+		if err != nil {
+			return nil, err
+		}
+
+		lookbackDays, err := getExtractionDaysEnv(extractionLookbackDaysEnv+"_"+dealType, defaultLookbackDays)
+		if err != nil {
+			return nil, err
+		}
+
+		schedules[dealType] = &NucleusExtractionSchedule{
+			Enabled:      !disabled[dealType],
+			Interval:     interval,
+			Jitter:       jitter,
+			LookbackDays: lookbackDays,
+		}
+	}
+
+	return schedules, nil
+}
+
+func getExtractionDurationEnv(name string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid duration %q in %s", value, name)
+	}
+
+	return duration, nil
+}
+
+func getExtractionDaysEnv(name string, defaultValue int) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 || days > maxExtractionLookbackDays {
+		return 0, fmt.Errorf("invalid number of days %q in %s", value, name)
+	}
+
+	return days, nil
+}
+
+// StartNucleusExtractionScheduler runs the scheduled extractions of every deal
+// type in the background until ctx is cancelled or the returned stop func is
+// called, when extractionSchedulerEnabledEnv is true. stop cancels the
+// scheduler and waits for its running extractions to be recorded and their
+// leases released. It does nothing when the scheduler isn't enabled
+func StartNucleusExtractionScheduler(ctx context.Context, repository INucleusTradeRepository, logger logger.Logger) (stop func(), err error) {
+	enabled, _ := strconv.ParseBool(os.Getenv(extractionSchedulerEnabledEnv))
+	if !enabled {
+		return func() {}, nil
+	}
+
+	schedules, err := LoadNucleusExtractionSchedules(repository.GetNucDealTypes())
+	if err != nil {
+		return nil, err
+	}
+
+	scheduler := NewNucleusExtractionScheduler(repository, logger, schedules, newExtractionLeaseOwnerId())
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scheduler.Run(ctx)
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}, nil
+}
+
+// NucleusExtractionScheduler extracts and processes the trades of the current
+// and lookback trade dates of every enabled deal type on its own interval,
+// through ExtractAndProcessTrades, so the runs are recorded and leased like
+// the ones requested over http
+type NucleusExtractionScheduler struct {
+	repository INucleusTradeRepository
+	logger     logger.Logger
+	schedules  map[string]*NucleusExtractionSchedule
+	ownerId    string
+
+	randomMutex sync.Mutex
+	random      *rand.Rand
+
+	now func() time.Time
+}
+
+func NewNucleusExtractionScheduler(repository INucleusTradeRepository, logger logger.Logger, schedules map[string]*NucleusExtractionSchedule, ownerId string) *NucleusExtractionScheduler {
+	return &NucleusExtractionScheduler{
+		repository: repository,
+		logger:     logger,
+		schedules:  schedules,
+		ownerId:    ownerId,
+		random:     rand.New(rand.NewSource(time.Now().UnixNano())),
+		now:        time.Now,
+	}
+}
+
+// Run schedules the enabled deal types until ctx is cancelled. The running
+// extractions are cancelled with ctx, and Run returns once they are recorded
+// as failed and their leases released
+func (scheduler *NucleusExtractionScheduler) Run(ctx context.Context) {
+	logger := scheduler.logger.GetLogger()
+	logger = logger.WithField("method", "NucleusExtractionScheduler.Run")
+
+	var wg sync.WaitGroup
+
+	for dealType, schedule := range scheduler.schedules {
+		if schedule == nil || !schedule.Enabled {
+			logger.Infoln("extraction of deal type ", dealType, " is disabled")
+			continue
+		}
+		if schedule.Interval <= 0 {
+			logger.Errorln("extraction of deal type ", dealType, " has no interval")
+			continue
+		}
+
+		wg.Add(1)
+		go func(dealType string, schedule NucleusExtractionSchedule) {
+			defer wg.Done()
+			scheduler.runDealType(ctx, dealType, schedule)
+		}(dealType, *schedule)
+	}
+
+	wg.Wait()
+}
+
+// runDealType extracts the deal type right away, after its jitter, then every
+// interval. A run extracts the current trade date then the lookback ones, from
+// the most recent. A run never overlaps the previous one of the same deal type
+func (scheduler *NucleusExtractionScheduler) runDealType(ctx context.Context, dealType string, schedule NucleusExtractionSchedule) {
+	logger := scheduler.logger.GetLogger()
+	logger = logger.WithField("method", "NucleusExtractionScheduler.runDealType")
+
+	timer := time.NewTimer(scheduler.jitter(schedule))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		now := scheduler.now()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+		for day := 0; day <= schedule.LookbackDays; day++ {
+			tradeDate := today.AddDate(0, 0, -day)
+
+			summary, err := scheduler.repository.ExtractAndProcessTrades(ctx, tradeDate, dealType, time.Time{}, scheduler.ownerId)
+			switch {
+			case err == nil:
+				logger.Debugln("extraction run ", summary.ExtractionRunId, " of deal type ", dealType, " on ", tradeDate.Format(extractionGapDateLayout), " extracted ", summary.ExtractedCount, " trades")
+			case errors.Is(err, ErrExtractionLeaseHeld):
+				logger.Debugln("extraction of deal type ", dealType, " on ", tradeDate.Format(extractionGapDateLayout), " skipped: ", err)
+			case ctx.Err() != nil:
+				return
+			default:
+				logger.Errorln("error extracting deal type ", dealType, " on ", tradeDate.Format(extractionGapDateLayout), ": ", err)
+			}
+		}
+
+		timer.Reset(schedule.Interval + scheduler.jitter(schedule))
+	}
+}
+
+// jitter draws from the random source of the scheduler, shared by the deal
+// types
+func (scheduler *NucleusExtractionScheduler) jitter(schedule NucleusExtractionSchedule) time.Duration {
+	if schedule.Jitter <= 0 {
+		return 0
+	}
+
+	scheduler.randomMutex.Lock()
+	defer scheduler.randomMutex.Unlock()
+
+	return time.Duration(scheduler.random.Int63n(int64(schedule.Jitter)))
+}
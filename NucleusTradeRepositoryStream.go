@@ -0,0 +1,59 @@
+package power
+
+import (
+	"context"
+
+	"github.com/sede-x/RogerRogerAnomalyDetector/models/nucleus"
+)
+
+// dealListStreamBatchSize is the number of scanned deals whose terms and
+// indexes are loaded together before they are handed to the caller of a
+// StreamNuc*DealList method
+const dealListStreamBatchSize = 500
+
+// acquireDealListStream waits for a slot to stream a deal list and returns
+// the func freeing it. A stream keeps the connection of its deal rows while
+// its flushes query the terms and the indexes on a second one, so at most half
+// of the MaxOpenConns of nucleusDb stream at once. Otherwise every stream
+// could hold its rows while waiting for a second connection. nucleusDb needs
+// at least 2 open connections, there is no limit when it has none
+func (repo *NucleusTradeRepository) acquireDealListStream(ctx context.Context) (func(), error) {
+	repo.dealListStreamsOnce.Do(func() {
+		if repo.nucleusDb == nil {
+			return
+		}
+		if maxOpenConnections := repo.nucleusDb.Stats().MaxOpenConnections; maxOpenConnections > 0 {
+			slots := maxOpenConnections / 2
+			if slots < 1 {
+				slots = 1
+			}
+			repo.dealListStreams = make(chan struct{}, slots)
+		}
+	})
+
+	if repo.dealListStreams == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case repo.dealListStreams <- struct{}{}:
+		return func() { <-repo.dealListStreams }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// collectHeaderModels runs the streaming deal list and gathers every deal in
+// a slice, so the slice returning methods keep their previous behaviour
+func collectHeaderModels(stream func(yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error) ([]*nucleus.NucleusTradeHeaderModel, error) {
+	var headerModels []*nucleus.NucleusTradeHeaderModel
+
+	if err := stream(func(headerModel *nucleus.NucleusTradeHeaderModel) error {
+		headerModels = append(headerModels, headerModel)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return headerModels, nil
+}
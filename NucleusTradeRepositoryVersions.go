@@ -0,0 +1,300 @@
+package power
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/sede-x/RogerRogerAnomalyDetector/models/nucleus"
+)
+
+// the kinds of change between two versions of a trade
+const (
+	TradeFieldModified = "modified"
+	TradeFieldAdded    = "added"
+	TradeFieldRemoved  = "removed"
+)
+
+// the sections of the trade a change belongs to
+const (
+	TradeSectionHeader  = "header"
+	TradeSectionTerms   = "terms"
+	TradeSectionIndexes = "indexes"
+)
+
+var (
+	tradeTermModelsType  = reflect.TypeOf([]*nucleus.NucleusTradeTermModel{})
+	tradeIndexModelsType = reflect.TypeOf([]*nucleus.NucleusTradeIndexModel{})
+	tradeTimeType        = reflect.TypeOf(time.Time{})
+)
+
+// NucleusTradeVersion is a version of a trade stored by ProcessTrades
+type NucleusTradeVersion struct {
+	DealKey       int                              `json:"dealKey"`
+	DealType      string                           `json:"dealType"`
+	VersionNumber int                              `json:"versionNumber"`
+	CreatedAt     time.Time                        `json:"createdAt"`
+	TradeDetail   *nucleus.NucleusTradeHeaderModel `json:"tradeDetail"`
+}
+
+// NucleusTradeVersionDiff lists the fields that changed between two
+// consecutive versions of a trade
+type NucleusTradeVersionDiff struct {
+	DealKey       int                        `json:"dealKey"`
+	DealType      string                     `json:"dealType"`
+	FromVersion   int                        `json:"fromVersion"`
+	ToVersion     int                        `json:"toVersion"`
+	FromCreatedAt time.Time                  `json:"fromCreatedAt"`
+	ToCreatedAt   time.Time                  `json:"toCreatedAt"`
+	Changes       []*NucleusTradeFieldChange `json:"changes"`
+}
+
+// NucleusTradeFieldChange is a single change of a trade. Path uses the json
+// names of the model, the terms are identified by their VolSeq and the
+// indexes by their position, e.g. terms[volSeq=3].indexes1[0].pubIndex. An
+// added or removed term or index carries the whole term or index as value
+type NucleusTradeFieldChange struct {
+	Section  string      `json:"section"`
+	VolSeq   *int        `json:"volSeq,omitempty"`
+	Path     string      `json:"path"`
+	Change   string      `json:"change"`
+	OldValue interface{} `json:"oldValue"`
+	NewValue interface{} `json:"newValue"`
+}
+
+// GetTradeVersionDiffs returns the changes between every consecutive versions
+// of the trade, oldest first
+func (repo *NucleusTradeRepository) GetTradeVersionDiffs(ctx context.Context, dealKey int, dealType string) ([]*NucleusTradeVersionDiff, error) {
+	logger := repo.logger.GetLogger()
+	logger = logger.WithField("method", "GetTradeVersionDiffs")
+
+	rows, err := repo.machineLearningDb.QueryContext(ctx, getTradeVersionsQuery,
+		sql.Named("tradeId", dealKey),
+		sql.Named("dealType", dealType),
+	)
+	if err != nil {
+		logger.Debugln("error got when executing getTradeVersionsQuery: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []*NucleusTradeVersion
+
+	for rows.Next() {
+		var version NucleusTradeVersion
+		var tradeDetail string
+
+		if err := rows.Scan(&version.DealKey, &version.DealType, &version.VersionNumber, &tradeDetail, &version.CreatedAt); err != nil {
+			logger.Debugln("error scanning rows: ", err)
+			return nil, err
+		}
+
+		var headerModel nucleus.NucleusTradeHeaderModel
+		if err := json.Unmarshal([]byte(tradeDetail), &headerModel); err != nil {
+			logger.Debugln("error unmarshalling the trade detail of version ", version.VersionNumber, ": ", err)
+			return nil, err
+		}
+		version.TradeDetail = &headerModel
+
+		versions = append(versions, &version)
+	}
+
+	if closeErr := rows.Close(); closeErr != nil {
+		logger.Debugln("error closing the rows: ", closeErr)
+		return nil, closeErr
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Debugln("error in rows: ", err)
+		return nil, err
+	}
+
+	diffs := []*NucleusTradeVersionDiff{}
+	for i := 1; i < len(versions); i++ {
+		diffs = append(diffs, &NucleusTradeVersionDiff{
+			DealKey:       versions[i].DealKey,
+			DealType:      versions[i].DealType,
+			FromVersion:   versions[i-1].VersionNumber,
+			ToVersion:     versions[i].VersionNumber,
+			FromCreatedAt: versions[i-1].CreatedAt,
+			ToCreatedAt:   versions[i].CreatedAt,
+			Changes:       diffTradeVersions(versions[i-1].TradeDetail, versions[i].TradeDetail),
+		})
+	}
+
+	return diffs, nil
+}
+
+// diffTradeVersions compares the header fields, then the terms matched by
+// VolSeq, then the indexes of every term matched by position
+func diffTradeVersions(from, to *nucleus.NucleusTradeHeaderModel) []*NucleusTradeFieldChange {
+	changes := []*NucleusTradeFieldChange{}
+
+	changes = append(changes, diffTradeFields(TradeSectionHeader, nil, "", reflect.ValueOf(from).Elem(), reflect.ValueOf(to).Elem())...)
+
+	fromTerms := make(map[int]*nucleus.NucleusTradeTermModel)
+	for _, term := range from.Terms {
+		if term != nil {
+			fromTerms[term.VolSeq] = term
+		}
+	}
+
+	toTerms := make(map[int]*nucleus.NucleusTradeTermModel)
+	for _, term := range to.Terms {
+		if term != nil {
+			toTerms[term.VolSeq] = term
+		}
+	}
+
+	for _, fromTerm := range from.Terms {
+		if fromTerm == nil {
+			continue
+		}
+
+		volSeq := fromTerm.VolSeq
+		path := fmt.Sprintf("terms[volSeq=%d]", volSeq)
+
+		toTerm, ok := toTerms[volSeq]
+		if !ok {
+			changes = append(changes, &NucleusTradeFieldChange{
+				Section:  TradeSectionTerms,
+				VolSeq:   &volSeq,
+				Path:     path,
+				Change:   TradeFieldRemoved,
+				OldValue: fromTerm,
+			})
+			continue
+		}
+
+		fromTermValue, toTermValue := reflect.ValueOf(fromTerm).Elem(), reflect.ValueOf(toTerm).Elem()
+		changes = append(changes, diffTradeFields(TradeSectionTerms, &volSeq, path+".", fromTermValue, toTermValue)...)
+
+		for _, field := range tradeModelFields(fromTermValue.Type()) {
+			if field.Type != tradeIndexModelsType {
+				continue
+			}
+			indexesPath := path + "." + tradeFieldName(field)
+			changes = append(changes, diffTradeIndexes(&volSeq, indexesPath,
+				fromTermValue.FieldByIndex(field.Index).Interface().([]*nucleus.NucleusTradeIndexModel),
+				toTermValue.FieldByIndex(field.Index).Interface().([]*nucleus.NucleusTradeIndexModel),
+			)...)
+		}
+	}
+
+	for _, toTerm := range to.Terms {
+		if toTerm == nil {
+			continue
+		}
+
+		volSeq := toTerm.VolSeq
+		if _, ok := fromTerms[volSeq]; ok {
+			continue
+		}
+
+		changes = append(changes, &NucleusTradeFieldChange{
+			Section:  TradeSectionTerms,
+			VolSeq:   &volSeq,
+			Path:     fmt.Sprintf("terms[volSeq=%d]", volSeq),
+			Change:   TradeFieldAdded,
+			NewValue: toTerm,
+		})
+	}
+
+	return changes
+}
+
+// diffTradeIndexes compares the indexes of a term by position
+func diffTradeIndexes(volSeq *int, path string, from, to []*nucleus.NucleusTradeIndexModel) []*NucleusTradeFieldChange {
+	var changes []*NucleusTradeFieldChange
+
+	for i := 0; i < len(from) || i < len(to); i++ {
+		indexPath := fmt.Sprintf("%s[%d]", path, i)
+
+		switch {
+		case i >= len(to) || to[i] == nil:
+			if i < len(from) && from[i] != nil {
+				changes = append(changes, &NucleusTradeFieldChange{
+					Section:  TradeSectionIndexes,
+					VolSeq:   volSeq,
+					Path:     indexPath,
+					Change:   TradeFieldRemoved,
+					OldValue: from[i],
+				})
+			}
+		case i >= len(from) || from[i] == nil:
+			changes = append(changes, &NucleusTradeFieldChange{
+				Section:  TradeSectionIndexes,
+				VolSeq:   volSeq,
+				Path:     indexPath,
+				Change:   TradeFieldAdded,
+				NewValue: to[i],
+			})
+		default:
+			changes = append(changes, diffTradeFields(TradeSectionIndexes, volSeq, indexPath+".", reflect.ValueOf(from[i]).Elem(), reflect.ValueOf(to[i]).Elem())...)
+		}
+	}
+
+	return changes
+}
+
+// diffTradeFields compares the fields of two models of the same type, the
+// terms and the indexes are left to diffTradeVersions
+func diffTradeFields(section string, volSeq *int, prefix string, from, to reflect.Value) []*NucleusTradeFieldChange {
+	var changes []*NucleusTradeFieldChange
+
+	for _, field := range tradeModelFields(from.Type()) {
+		if field.Type == tradeTermModelsType || field.Type == tradeIndexModelsType {
+			continue
+		}
+
+		fromValue := from.FieldByIndex(field.Index).Interface()
+		toValue := to.FieldByIndex(field.Index).Interface()
+
+		if field.Type == tradeTimeType {
+			if fromValue.(time.Time).Equal(toValue.(time.Time)) {
+				continue
+			}
+		} else if reflect.DeepEqual(fromValue, toValue) {
+			continue
+		}
+
+		changes = append(changes, &NucleusTradeFieldChange{
+			Section:  section,
+			VolSeq:   volSeq,
+			Path:     prefix + tradeFieldName(field),
+			Change:   TradeFieldModified,
+			OldValue: fromValue,
+			NewValue: toValue,
+		})
+	}
+
+	return changes
+}
+
+// tradeModelFields returns the exported fields of a model that are serialised
+func tradeModelFields(modelType reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+
+	for i := 0; i < modelType.NumField(); i++ {
+		field := modelType.Field(i)
+		if field.PkgPath != "" || field.Tag.Get("json") == "-" {
+			continue
+		}
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// tradeFieldName is the json name of the field, or its Go name without one
+func tradeFieldName(field reflect.StructField) string {
+	if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "" {
+		return name
+	}
+
+	return field.Name
+}
@@ -1,5562 +1,11496 @@
-package power
-
-import (
-	"context"
-	"database/sql"
-	"database/sql/driver"
-	"encoding/json"
-	"fmt"
-	"log"
-	"reflect"
-	"testing"
-	"time"
-
-	"github.com/DATA-DOG/go-sqlmock"
-	mssql "github.com/denisenkom/go-mssqldb"
-
-	"github.com/sede-x/RogerRogerAnomalyDetector/db/oracle"
-	"github.com/sede-x/RogerRogerAnomalyDetector/logger"
-	"github.com/sede-x/RogerRogerAnomalyDetector/models/common"
-	"github.com/sede-x/RogerRogerAnomalyDetector/models/nucleus"
-)
-
-func parseTimeLayout(format string, date string) time.Time {
-	time, err := time.Parse(format, date)
-	if err != nil {
-		log.Println("parseTimeLayout err: ", err)
-	}
-	return time
-}
-
-func parseTime(date string) time.Time {
-	return parseTimeLayout("02-01-2006", date)
-}
-
-func parseDateTime(date string, timestamp string) time.Time {
-	if date == "" || timestamp == "" {
-		return time.Time{}
-	}
-	executionTimeString := date + " " + timestamp
-	execTime, err := time.Parse("2006-01-02 03:04:05 PM", executionTimeString)
-	if err != nil {
-		execTime, err = time.Parse("01/02/2006 03:04:05 PM", executionTimeString)
-		if err != nil {
-			log.Println("parseDateTime err: ", err)
-		}
-	}
-	return execTime
-}
-
-type AnyValueMatch struct{}
-
-func (e AnyValueMatch) Match(v driver.Value) bool {
-	return true
-}
-
-func TestNucleusTradeRepository_GetNucPowerDealList(t *testing.T) {
-	now := time.Now()
-	serverLogger := logger.GetServerLogger()
-	serverLogger.Enable(false)
-
-	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer nucleusDb.Close()
-
-	columns := []string{"POWER_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
-		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE",
-		"LEGALENTITY", "LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER",
-		"CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER",
-		"TZ_TIME_ZONE", "HAS_BROKER", "BROKER", "OPTION_KEY", "CREATEDBY", "CREATE_DATE",
-		"MODIFIEDBY", "MODIFY_DATE", "EXECUTION_DATE", "EXECUTION_TIME", "EXOTIC_FLAG"}
-
-	mock.ExpectQuery(getNucPowerDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			3996506, "PWRNSD", "PURCHASE", parseTime("05-05-2022"),
-			1601, "PJM", "PJM INTERCONNECTION LLC", "PJM",
-			"SENA", "Shell Energy North America (US), L.P.", 10430, "011-KW-BI-05701",
-			"HOURLY", "EAST", "", 288, "SD - TRANS", "SROSS",
-			"PPT", "NO", "NA", nil, "SROSS", parseTime("04-05-2022"),
-			"SROSS", parseTime("05-05-2022"), "", "", "NA",
-		).AddRow(
-			3996507, "PWRNSD", "SALE", parseTime("05-05-2022"),
-			10459, "MISO", "MIDWEST INDEPENDENT TRANSMISSION SYSTEM", "MIDWEST IN",
-			"SENA", "Shell Energy North America (US), L.P.", 10430, "011-KW-IS-10952",
-			"HOURLY", "EAST", "", 288, "SD - TRANS", "SROSS",
-			"PPT", "NO", "NA", nil, "SROSS", parseTime("04-05-2022"),
-			"SROSS", parseTime("05-05-2022"), "2022-05-05", "08:18:55 AM", "NA",
-		))
-
-	dealKeysArray := []int{3996506, 3996507}
-
-	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeysArray, []interface{}{}, "pv.pd_power_key")
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when creating the query", err)
-	}
-
-	getNucPowerTradeTermModelQ := getNucPowerTradeTermModelQuery(dealKeysQuery)
-
-	var namedParams []driver.Value
-	for _, value := range params {
-		valueNamed, _ := value.(sql.NamedArg)
-		namedParams = append(namedParams, valueNamed)
-	}
-
-	columns = []string{"PD_POWER_KEY", "VOLUME_SEQ", "DY_BEG_DAY", "DY_END_DAY", "PRICE_TYPE",
-		"PRICE", "VOLUME", "PPEP_PP_POOL", "PPEP_PEP_PRODUCT", "CTP_POINT_CODE", "FORMULA",
-		"SCH_SCHEDULE"}
-
-	mock.ExpectQuery(getNucPowerTradeTermModelQ).WithArgs(namedParams...).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			3996506, 0, parseTime("05-05-2022"), parseTime("05-05-2022"), "I",
-			0, 0, "PJM", "HOURLY", "MISO", "[PJM DA LMP|40523629|HOURLY]",
-			"NERC",
-		).AddRow(
-			3996507, 0, parseTime("05-05-2022"), parseTime("05-05-2022"), "I",
-			0, 0, "MISOE", "HOURLY", "MISO/PJM", "[MISO DALMP|PJMC|HOURLY]",
-			"NERC",
-		))
-
-	formulaMap := make(map[int]int)
-	formulaMap[3996506] = 0
-	formulaMap[3996507] = 0
-
-	insNumQuery, _, err := oracle.CreateMapIntWhereQuery(formulaMap, []interface{}{}, "pv_pd_power_key", "pv_volume_seq")
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when creating the query", err)
-	}
-
-	getNucPowerTradeIndexModelQ := getNucPowerTradeIndexModelQuery(insNumQuery)
-
-	columns = []string{"PV_PD_POWER_KEY", "PV_VOLUME_SEQ", "PUBLICATION", "PUB_INDEX", "FREQUENCY"}
-
-	// this is a small hack to allow any values to be in any order for the mock,
-	// the issue is that the parameters for the query never will be in the same
-	// order because it comes from iterating over the keys of a map and Go always
-	// randmize them
-	mock.ExpectQuery(getNucPowerTradeIndexModelQ).WithArgs(AnyValueMatch{}, AnyValueMatch{},
-		AnyValueMatch{}, AnyValueMatch{}).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			3996506, 0, "PJM DA LMP", "40523629", "HOURLY",
-		).AddRow(
-			3996507, 0, "MISO DALMP", "PJMC", "HOURLY",
-		))
-
-	type fields struct {
-		nucleusDb         *sql.DB
-		machineLearningDb *sql.DB
-		logger            logger.Logger
-	}
-	type args struct {
-		ctx         context.Context
-		lastRunTime time.Time
-		tradeDate   time.Time
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    []*nucleus.NucleusTradeHeaderModel
-		wantErr bool
-	}{
-		{
-			name: "regular run",
-			fields: fields{
-				nucleusDb:         nucleusDb,
-				machineLearningDb: nil,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx:         context.TODO(),
-				lastRunTime: now,
-				tradeDate:   now,
-			},
-			want: []*nucleus.NucleusTradeHeaderModel{
-				{
-					DealKey:             3996506,
-					DealType:            "PWRNSD",
-					TotalQuantity:       0,
-					TransactionDate:     parseTime("05-05-2022"),
-					DnDirection:         "PURCHASE",
-					CyCompanyKey:        1601,
-					CompanyCode:         "PJM",
-					Company:             "PJM",
-					CompanyLongName:     "PJM INTERCONNECTION LLC",
-					HsHedgeKey:          "",
-					PrtPortfolio:        288,
-					Portfolio:           "SD - TRANS",
-					UrTrader:            "SROSS",
-					CyBrokerKey:         0,
-					Broker:              "NA",
-					DaRtIndicator:       "",
-					TzTimeZone:          "PPT",
-					TzExerciseZone:      "",
-					HasBroker:           "NO",
-					IbPrtPortfolio:      0,
-					IbPortfolio:         "",
-					IbUrTrader:          "",
-					ExercisedOptionKey:  0,
-					Contract:            "011-KW-BI-05701",
-					ConfirmFormat:       "HOURLY",
-					CyLegalEntityKey:    10430,
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					Region:              "EAST",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:          0,
-							BegDate:         parseTime("05-05-2022"),
-							EndDate:         parseTime("05-05-2022"),
-							Pool1:           "PJM",
-							Product1:        "HOURLY",
-							PointCode1:      "MISO",
-							Pool2:           "",
-							Product2:        "",
-							HolidaySchedule: "NERC",
-							PointCode2:      "",
-							Formula1:        "[PJM DA LMP|40523629|HOURLY]",
-							Indexes1: []*nucleus.NucleusTradeIndexModel{
-								{
-									VolSeq:      0,
-									Publication: "PJM DA LMP",
-									PubIndex:    "40523629",
-									Frequency:   "HOURLY",
-								},
-							},
-							Formula2:   "",
-							Indexes2:   nil,
-							PriceType:  "I",
-							FixedPrice: 0,
-							Volume:     0,
-						},
-					},
-					AnomalyTestResult:  "",
-					CreatedAt:          parseTime("04-05-2022"),
-					ModifiedAt:         parseTime("05-05-2022"),
-					CreatedBy:          "SROSS",
-					ModifiedBy:         "SROSS",
-					OptionType:         "",
-					ExecutionTime:      parseTime("01-01-0001"),
-					ExoticFlag:         "NA",
-					InteraffiliateFlag: "N",
-					StartDate:          parseTime("01-01-0001"),
-					EndDate:            parseTime("01-01-0001"),
-				},
-				{
-					DealKey:             3996507,
-					DealType:            "PWRNSD",
-					TotalQuantity:       0,
-					TransactionDate:     parseTime("05-05-2022"),
-					DnDirection:         "SALE",
-					CyCompanyKey:        10459,
-					CompanyCode:         "MIDWEST IN",
-					Company:             "MISO",
-					CompanyLongName:     "MIDWEST INDEPENDENT TRANSMISSION SYSTEM",
-					HsHedgeKey:          "",
-					PrtPortfolio:        288,
-					Portfolio:           "SD - TRANS",
-					UrTrader:            "SROSS",
-					CyBrokerKey:         0,
-					Broker:              "NA",
-					DaRtIndicator:       "",
-					TzTimeZone:          "PPT",
-					TzExerciseZone:      "",
-					HasBroker:           "NO",
-					IbPrtPortfolio:      0,
-					IbPortfolio:         "",
-					IbUrTrader:          "",
-					ExercisedOptionKey:  0,
-					Contract:            "011-KW-IS-10952",
-					ConfirmFormat:       "HOURLY",
-					CyLegalEntityKey:    10430,
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					Region:              "EAST",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:          0,
-							BegDate:         parseTime("05-05-2022"),
-							EndDate:         parseTime("05-05-2022"),
-							Pool1:           "MISOE",
-							Product1:        "HOURLY",
-							PointCode1:      "MISO/PJM",
-							Pool2:           "",
-							Product2:        "",
-							HolidaySchedule: "NERC",
-							PointCode2:      "",
-							Formula1:        "[MISO DALMP|PJMC|HOURLY]",
-							Indexes1: []*nucleus.NucleusTradeIndexModel{
-								{
-									VolSeq:      0,
-									Publication: "MISO DALMP",
-									PubIndex:    "PJMC",
-									Frequency:   "HOURLY",
-								},
-							},
-							Formula2:   "",
-							Indexes2:   nil,
-							PriceType:  "I",
-							FixedPrice: 0,
-							Volume:     0,
-						},
-					},
-					AnomalyTestResult:  "",
-					CreatedAt:          parseTime("04-05-2022"),
-					ModifiedAt:         parseTime("05-05-2022"),
-					CreatedBy:          "SROSS",
-					ModifiedBy:         "SROSS",
-					OptionType:         "",
-					ExecutionTime:      parseDateTime("2022-05-05", "08:18:55 AM"),
-					ExoticFlag:         "NA",
-					InteraffiliateFlag: "N",
-					StartDate:          parseTime("01-01-0001"),
-					EndDate:            parseTime("01-01-0001"),
-				},
-			},
-			wantErr: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			repo := &NucleusTradeRepository{
-				nucleusDb:         tt.fields.nucleusDb,
-				machineLearningDb: tt.fields.machineLearningDb,
-				logger:            tt.fields.logger,
-			}
-			got, err := repo.GetNucPowerDealList(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("NucleusTradeRepository.GetNucPowerDealList() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-
-			if len(got) != len(tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucPowerDealList() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
-				return
-			}
-
-			for _, wantedValue := range tt.want {
-				exists := false
-				for _, value := range got {
-					if wantedValue.DealKey == value.DealKey {
-						exists = true
-						if len(value.Terms) != len(wantedValue.Terms) {
-							t.Errorf("NucleusTradeRepository.GetNucPowerDealList() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
-							return
-						}
-
-						for _, wantedTerm := range wantedValue.Terms {
-							termExists := false
-							for _, valueTerm := range value.Terms {
-								if wantedTerm.VolSeq == valueTerm.VolSeq {
-									termExists = true
-
-									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
-										t.Errorf("NucleusTradeRepository.GetNucPowerDealList() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes1 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes1 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucPowerDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
-										t.Errorf("NucleusTradeRepository.GetNucPowerDealList() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes2 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes2 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucPowerDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if !reflect.DeepEqual(wantedTerm, valueTerm) {
-										t.Errorf("NucleusTradeRepository.GetNucPowerDealList() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
-										return
-									}
-								}
-							}
-							if !termExists {
-								t.Errorf("NucleusTradeRepository.GetNucPowerDealList() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
-								return
-							}
-						}
-
-						if !reflect.DeepEqual(wantedValue, value) {
-							t.Errorf("NucleusTradeRepository.GetNucPowerDealList() wantedValue = %+v, value = %+v", wantedValue, value)
-							return
-						}
-					}
-				}
-				if !exists {
-					t.Errorf("NucleusTradeRepository.GetNucPowerDealList() wanted value does not exist %d", wantedValue.DealKey)
-					return
-				}
-			}
-
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucPowerDealList() got = %+v, want = %+v", got, tt.want)
-				return
-			}
-		})
-	}
-}
-
-func TestNucleusTradeRepository_GetNucPowerSwapDealList(t *testing.T) {
-	now := time.Now()
-	serverLogger := logger.GetServerLogger()
-	serverLogger.Enable(false)
-
-	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer nucleusDb.Close()
-
-	columns := []string{"PSWAP_KEY", "DEAL_TYPE", "TOTAL_QUANTITY", "DN_DIRECTION",
-		"TRANSACTION_DATE", "CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE",
-		"LEGALENTITY", "LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "INTERAFFILIATE_FLAG",
-		"CONTRACTNUMBER", "CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO",
-		"PORTFOLIO", "UR_TRADER", "IB_PRT_PORTFOLIO", "IB_PORTFOLIO", "IB_UR_TRADER",
-		"TZ_TIME_ZONE", "HAS_BROKER", "BROKER", "OPTION_KEY", "PPEP_PP_POOL", "PPEP_PEP_PRODUCT",
-		"VOLUME", "FIXED_PRICE", "NONSTD_FLAG", "PI_PB_PUBLICATION", "PI_PUB_INDEX",
-		"FRQ_FREQUENCY", "FIX_PI_PB_PUBLICATION", "FIX_PI_PUB_INDEX", "FIX_FRQ_FREQUENCY",
-		"DY_BEG_DAY", "DY_END_DAY", "SCH_SCHEDULE", "CREATEDBY", "CREATE_DATE", "MODIFIEDBY",
-		"MODIFY_DATE", "EXECUTION_DATE", "EXECUTION_TIME", "EXOTIC_FLAG"}
-	mock.ExpectQuery(getNucPowerSwapDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			1388598, "PSWPS", 800, "PURCHASE",
-			parseTime("05-05-2022"), 18098, "FIMAT USA", "NEWEDGE USA LLC", "FIMAT USA",
-			"SENA", "Shell Energy North America (US), L.P.", 10430, "N",
-			"", "POWER SWAP", "SOUTH", "", 235,
-			"ERCOT B E FINANCIAL", "CWATSON", nil, "", "",
-			"CPT", "YES", "INTERXCHG", nil, "ERFNH", "STD ON",
-			50, 111, "Y", "ER RT LMP", "NORTH_HUB_AVG",
-			"HOURLY", nil, nil, nil,
-			parseTime("05-05-2022"), parseTime("05-05-2022"), "NERC", "LOB2", parseTime("05-05-2022"), "LOB2",
-			parseTime("05-05-2022"), "2022-05-05", "09:08:26 AM", "No",
-		).AddRow(
-			1388692, "PSWPS", 21600, "PURCHASE",
-			parseTime("05-05-2022"), 18098, "FIMAT USA", "NEWEDGE USA LLC", "FIMAT USA",
-			"SENA", "Shell Energy North America (US), L.P.", 10430, "N",
-			"", "POWER SWAP", "SOUTH", "", 235,
-			"ERCOT B E FINANCIAL", "CWATSON", nil, "", "",
-			"CPT", "YES", "INTERXCHG", nil, "ERFNH", "STD 2x16",
-			135, 30, "N", "ER RT LMP", "NORTH_HUB_AVG",
-			"HOURLY", nil, nil, nil,
-			parseTime("01-05-2025"), parseTime("31-05-2025"), "NERC", "LOB2", parseTime("05-05-2022"), "JKING",
-			parseTime("05-05-2022"), "2022-05-05", "11:05:35 AM", "No",
-		))
-
-	dealKeysArray := []int{1388598}
-
-	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeysArray, []interface{}{}, "pswp_pswap_key")
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when creating the query", err)
-	}
-
-	getNucPowerSwapDealTermModelQ := getNucPowerSwapDealTermModelQuery(dealKeysQuery)
-
-	var namedParams []driver.Value
-	for _, value := range params {
-		valueNamed, _ := value.(sql.NamedArg)
-		namedParams = append(namedParams, valueNamed)
-	}
-
-	columns = []string{"PSWP_PSWAP_KEY", "VOLUME_SEQ", "DY_BEG_DAY", "DY_END_DAY"}
-	mock.ExpectQuery(getNucPowerSwapDealTermModelQ).WithArgs(namedParams...).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			1388598, 613, parseTime("05-03-2024"), parseTime("05-03-2024"),
-		).AddRow(
-			1388598, 614, parseTime("06-03-2024"), parseTime("06-03-2024"),
-		))
-
-	type fields struct {
-		nucleusDb         *sql.DB
-		machineLearningDb *sql.DB
-		logger            logger.Logger
-	}
-	type args struct {
-		ctx         context.Context
-		lastRunTime time.Time
-		tradeDate   time.Time
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    []*nucleus.NucleusTradeHeaderModel
-		wantErr bool
-	}{
-		{
-			name: "regular run",
-			fields: fields{
-				nucleusDb:         nucleusDb,
-				machineLearningDb: nil,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx:         context.TODO(),
-				lastRunTime: now,
-				tradeDate:   now,
-			},
-			want: []*nucleus.NucleusTradeHeaderModel{
-				{
-					DealKey:             1388598,
-					DealType:            "PSWPS",
-					TotalQuantity:       800,
-					DnDirection:         "PURCHASE",
-					TransactionDate:     parseTime("05-05-2022"),
-					CyCompanyKey:        18098,
-					Company:             "FIMAT USA",
-					CompanyLongName:     "NEWEDGE USA LLC",
-					CompanyCode:         "FIMAT USA",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					Contract:            "",
-					ConfirmFormat:       "POWER SWAP",
-					Region:              "SOUTH",
-					PrtPortfolio:        235,
-					Portfolio:           "ERCOT B E FINANCIAL",
-					UrTrader:            "CWATSON",
-					IbPrtPortfolio:      0,
-					IbPortfolio:         "",
-					IbUrTrader:          "",
-					TzTimeZone:          "CPT",
-					HasBroker:           "YES",
-					Broker:              "INTERXCHG",
-					ExercisedOptionKey:  0,
-					StartDate:           parseTime("05-05-2022"),
-					EndDate:             parseTime("05-05-2022"),
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:     613,
-							Pool1:      "ERFNH",
-							Product1:   "STD ON",
-							Volume:     0,
-							FixedPrice: 0,
-							Indexes1: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "ER RT LMP",
-									PubIndex:    "NORTH_HUB_AVG",
-									Frequency:   "HOURLY",
-								},
-							},
-							BegDate:         parseTime("05-03-2024"),
-							EndDate:         parseTime("05-03-2024"),
-							HolidaySchedule: "NERC",
-						},
-						{
-							VolSeq:     614,
-							Pool1:      "ERFNH",
-							Product1:   "STD ON",
-							Volume:     0,
-							FixedPrice: 0,
-							Indexes1: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "ER RT LMP",
-									PubIndex:    "NORTH_HUB_AVG",
-									Frequency:   "HOURLY",
-								},
-							},
-							BegDate:         parseTime("06-03-2024"),
-							EndDate:         parseTime("06-03-2024"),
-							HolidaySchedule: "NERC",
-						},
-					},
-					CreatedBy:          "LOB2",
-					CreatedAt:          parseTime("05-05-2022"),
-					ModifiedBy:         "LOB2",
-					ModifiedAt:         parseTime("05-05-2022"),
-					ExecutionTime:      parseDateTime("2022-05-05", "09:08:26 AM"),
-					ExoticFlag:         "No",
-					InteraffiliateFlag: "N",
-				},
-				{
-					DealKey:             1388692,
-					DealType:            "PSWPS",
-					TotalQuantity:       21600,
-					DnDirection:         "PURCHASE",
-					TransactionDate:     parseTime("05-05-2022"),
-					CyCompanyKey:        18098,
-					Company:             "FIMAT USA",
-					CompanyLongName:     "NEWEDGE USA LLC",
-					CompanyCode:         "FIMAT USA",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					Contract:            "",
-					ConfirmFormat:       "POWER SWAP",
-					Region:              "SOUTH",
-					PrtPortfolio:        235,
-					Portfolio:           "ERCOT B E FINANCIAL",
-					UrTrader:            "CWATSON",
-					IbPrtPortfolio:      0,
-					IbPortfolio:         "",
-					IbUrTrader:          "",
-					TzTimeZone:          "CPT",
-					HasBroker:           "YES",
-					Broker:              "INTERXCHG",
-					ExercisedOptionKey:  0,
-					StartDate:           parseTime("01-05-2025"),
-					EndDate:             parseTime("31-05-2025"),
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:     0,
-							Pool1:      "ERFNH",
-							Product1:   "STD 2x16",
-							Volume:     135,
-							FixedPrice: 30,
-							Indexes1: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "ER RT LMP",
-									PubIndex:    "NORTH_HUB_AVG",
-									Frequency:   "HOURLY",
-								},
-							},
-							BegDate:         parseTime("01-05-2025"),
-							EndDate:         parseTime("31-05-2025"),
-							HolidaySchedule: "NERC",
-						},
-					},
-					CreatedBy:          "LOB2",
-					CreatedAt:          parseTime("05-05-2022"),
-					ModifiedBy:         "JKING",
-					ModifiedAt:         parseTime("05-05-2022"),
-					ExecutionTime:      parseDateTime("2022-05-05", "11:05:35 AM"),
-					ExoticFlag:         "No",
-					InteraffiliateFlag: "N",
-				},
-			},
-			wantErr: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			repo := &NucleusTradeRepository{
-				nucleusDb:         tt.fields.nucleusDb,
-				machineLearningDb: tt.fields.machineLearningDb,
-				logger:            tt.fields.logger,
-			}
-			got, err := repo.GetNucPowerSwapDealList(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealList() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-
-			if len(got) != len(tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealList() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
-				return
-			}
-
-			for _, wantedValue := range tt.want {
-				exists := false
-				for _, value := range got {
-					if wantedValue.DealKey == value.DealKey {
-						exists = true
-						if len(value.Terms) != len(wantedValue.Terms) {
-							t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealList() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
-							return
-						}
-
-						for _, wantedTerm := range wantedValue.Terms {
-							termExists := false
-							for _, valueTerm := range value.Terms {
-								if wantedTerm.VolSeq == valueTerm.VolSeq {
-									termExists = true
-
-									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
-										t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealList() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes1 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes1 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
-										t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealList() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes2 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes2 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if !reflect.DeepEqual(wantedTerm, valueTerm) {
-										t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealList() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
-										return
-									}
-								}
-							}
-							if !termExists {
-								t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealList() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
-								return
-							}
-						}
-
-						if !reflect.DeepEqual(wantedValue, value) {
-							t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealList() wantedValue = %+v, value = %+v", wantedValue, value)
-							return
-						}
-					}
-				}
-				if !exists {
-					t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealList() wanted value does not exist %d", wantedValue.DealKey)
-					return
-				}
-			}
-
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealList() got = %+v, want = %+v", got, tt.want)
-				return
-			}
-		})
-	}
-}
-
-func TestNucleusTradeRepository_GetNucPowerOptionsDealList(t *testing.T) {
-	now := time.Now()
-	serverLogger := logger.GetServerLogger()
-	serverLogger.Enable(false)
-
-	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer nucleusDb.Close()
-
-	columns := []string{"POPTION_KEY", "DEAL_TYPE", "TOTAL_QUANTITY", "DN_DIRECTION",
-		"TRANSACTION_DATE", "CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE",
-		"LEGALENTITY", "LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "INTERAFFILIATE_FLAG",
-		"CONTRACTNUMBER", "CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO",
-		"PORTFOLIO", "UR_TRADER", "IB_PRT_PORTFOLIO", "IB_PORTFOLIO", "IB_UR_TRADER",
-		"TZ_TIME_ZONE", "TZ_EXERCISE_ZONE", "HAS_BROKER", "BROKER", "PPEP_PP_POOL",
-		"PPEP_PEP_PRODUCT", "CTP_POINT_CODE", "SETTLE_FORMULA", "DY_BEG_DAY", "DY_END_DAY",
-		"SCH_SCHEDULE", "VOLUME", "STRIKE_PRICE", "STRIKE_PRICE_TYPE", "STRIKE_FORMULA",
-		"CREATEDBY", "MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE", "EXECUTION_DATE",
-		"EXECUTION_TIME", "EXOTIC_FLAG",
-	}
-	mock.ExpectQuery(getNucPowerOptionsDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			43435, "POPTS", 17600, "SALE",
-			parseTime("23-05-2022"), 10322, "IBT", "INTERBOOK TRANSFER", "IBT",
-			"SENA", "Shell Energy North America (US), L.P.", 10430, "N",
-			"", "POWER OPT", "EAST", "", 99478,
-			"ERCOT_RETAIL", "GABDULLA", nil, "", "",
-			"CPT", "CPT", "NO", "NA", "ERCNZ",
-			"STD ON", "HB_NORTH", nil, parseTime("01-06-2022"), parseTime("30-06-2022"),
-			"NERC", -50, 100, "F", "",
-			"GABDULLA", "GABDULLA", parseTime("23-05-2022"), parseTime("23-05-2022"), "05/23/2022",
-			"12:00:00 AM", "No",
-		).AddRow(
-			43441, "POPTS", 35200, "PURCHASE",
-			parseTime("23-05-2022"), 10322, "IBT", "INTERBOOK TRANSFER", "IBT",
-			"SENA", "Shell Energy North America (US), L.P.", 10430, "N",
-			"", "POWER OPT", "EAST", "", 220,
-			"SOUTH OTHER HR", "GABDULLA", nil, "", "",
-			"CPT", "CPT", "NO", "NA", "ERCNZ",
-			"STD ON", "HB_NORTH", "[MISO RTLMP|CINERGY.HUB|HOURLY]", parseTime("01-06-2022"), parseTime("30-06-2022"),
-			"NERC", 100, 100, "F", "(10.5*[GD|HOU SHP CHNL|DAILY]) + 3.55",
-			"GABDULLA", "GABDULLA", parseTime("23-05-2022"), parseTime("23-05-2022"), "05/23/2022",
-			"12:00:00 AM", "No",
-		))
-
-	type fields struct {
-		nucleusDb         *sql.DB
-		machineLearningDb *sql.DB
-		logger            logger.Logger
-	}
-	type args struct {
-		ctx         context.Context
-		lastRunTime time.Time
-		tradeDate   time.Time
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    []*nucleus.NucleusTradeHeaderModel
-		wantErr bool
-	}{
-		{
-			name: "regular run",
-			fields: fields{
-				nucleusDb:         nucleusDb,
-				machineLearningDb: nil,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx:         context.TODO(),
-				lastRunTime: now,
-				tradeDate:   now,
-			},
-			want: []*nucleus.NucleusTradeHeaderModel{
-				{
-					DealKey:             43435,
-					DealType:            "POPTS",
-					TotalQuantity:       17600,
-					DnDirection:         "SALE",
-					TransactionDate:     parseTime("23-05-2022"),
-					CyCompanyKey:        10322,
-					Company:             "IBT",
-					CompanyLongName:     "INTERBOOK TRANSFER",
-					CompanyCode:         "IBT",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					InteraffiliateFlag:  "N",
-					Contract:            "",
-					ConfirmFormat:       "POWER OPT",
-					Region:              "EAST",
-					HsHedgeKey:          "",
-					PrtPortfolio:        99478,
-					Portfolio:           "ERCOT_RETAIL",
-					UrTrader:            "GABDULLA",
-					IbPrtPortfolio:      0,
-					IbPortfolio:         "",
-					IbUrTrader:          "",
-					TzTimeZone:          "CPT",
-					TzExerciseZone:      "CPT",
-					HasBroker:           "NO",
-					Broker:              "NA",
-					StartDate:           parseTime("01-06-2022"),
-					EndDate:             parseTime("30-06-2022"),
-					CreatedBy:           "GABDULLA",
-					ModifiedBy:          "GABDULLA",
-					CreatedAt:           parseTime("23-05-2022"),
-					ModifiedAt:          parseTime("23-05-2022"),
-					ExecutionTime:       parseDateTime("05/23/2022", "12:00:00 AM"),
-					ExoticFlag:          "No",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							Pool1:           "ERCNZ",
-							Product1:        "STD ON",
-							PointCode1:      "HB_NORTH",
-							BegDate:         parseTime("01-06-2022"),
-							EndDate:         parseTime("30-06-2022"),
-							HolidaySchedule: "NERC",
-							Volume:          -50,
-							FixedPrice:      100,
-							PriceType:       "F",
-						},
-					},
-				},
-				{
-					DealKey:             43441,
-					DealType:            "POPTS",
-					TotalQuantity:       35200,
-					DnDirection:         "PURCHASE",
-					TransactionDate:     parseTime("23-05-2022"),
-					CyCompanyKey:        10322,
-					Company:             "IBT",
-					CompanyLongName:     "INTERBOOK TRANSFER",
-					CompanyCode:         "IBT",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					InteraffiliateFlag:  "N",
-					Contract:            "",
-					ConfirmFormat:       "POWER OPT",
-					Region:              "EAST",
-					HsHedgeKey:          "",
-					PrtPortfolio:        220,
-					Portfolio:           "SOUTH OTHER HR",
-					UrTrader:            "GABDULLA",
-					IbPrtPortfolio:      0,
-					IbPortfolio:         "",
-					IbUrTrader:          "",
-					TzTimeZone:          "CPT",
-					TzExerciseZone:      "CPT",
-					HasBroker:           "NO",
-					Broker:              "NA",
-					StartDate:           parseTime("01-06-2022"),
-					EndDate:             parseTime("30-06-2022"),
-					CreatedBy:           "GABDULLA",
-					ModifiedBy:          "GABDULLA",
-					CreatedAt:           parseTime("23-05-2022"),
-					ModifiedAt:          parseTime("23-05-2022"),
-					ExecutionTime:       parseDateTime("05/23/2022", "12:00:00 AM"),
-					ExoticFlag:          "No",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							Pool1:           "ERCNZ",
-							Product1:        "STD ON",
-							PointCode1:      "HB_NORTH",
-							BegDate:         parseTime("01-06-2022"),
-							EndDate:         parseTime("30-06-2022"),
-							HolidaySchedule: "NERC",
-							Volume:          100,
-							FixedPrice:      100,
-							PriceType:       "F",
-							Indexes1: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "MISO RTLMP",
-									PubIndex:    "CINERGY.HUB",
-									Frequency:   "HOURLY",
-								},
-							},
-							Indexes2: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "GD",
-									PubIndex:    "HOU SHP CHNL",
-									Frequency:   "DAILY",
-								},
-							},
-						},
-					},
-				},
-			},
-			wantErr: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			repo := &NucleusTradeRepository{
-				nucleusDb:         tt.fields.nucleusDb,
-				machineLearningDb: tt.fields.machineLearningDb,
-				logger:            tt.fields.logger,
-			}
-			got, err := repo.GetNucPowerOptionsDealList(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealList() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-
-			if len(got) != len(tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealList() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
-				return
-			}
-
-			for _, wantedValue := range tt.want {
-				exists := false
-				for _, value := range got {
-					if wantedValue.DealKey == value.DealKey {
-						exists = true
-						if len(value.Terms) != len(wantedValue.Terms) {
-							t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealList() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
-							return
-						}
-
-						for _, wantedTerm := range wantedValue.Terms {
-							termExists := false
-							for _, valueTerm := range value.Terms {
-								if wantedTerm.VolSeq == valueTerm.VolSeq {
-									termExists = true
-
-									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
-										t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealList() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes1 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes1 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
-										t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealList() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes2 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes2 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if !reflect.DeepEqual(wantedTerm, valueTerm) {
-										t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealList() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
-										return
-									}
-								}
-							}
-							if !termExists {
-								t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealList() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
-								return
-							}
-						}
-
-						if !reflect.DeepEqual(wantedValue, value) {
-							t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealList() wantedValue = %+v, value = %+v", wantedValue, value)
-							return
-						}
-					}
-				}
-				if !exists {
-					t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealList() wanted value does not exist %d", wantedValue.DealKey)
-					return
-				}
-			}
-
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealList() got = %+v, want = %+v", got, tt.want)
-				return
-			}
-		})
-	}
-}
-
-func TestNucleusTradeRepository_GetNucCapacityDealList(t *testing.T) {
-	now := time.Now()
-	serverLogger := logger.GetServerLogger()
-	serverLogger.Enable(false)
-
-	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer nucleusDb.Close()
-
-	columns := []string{"CAPACITY_KEY", "DEAL_TYPE", "TOTAL_QUANTITY", "DN_DIRECTION",
-		"TRANSACTION_DATE", "CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME",
-		"COMPANYCODE", "LEGALENTITY", "LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "INTERAFFILIATE_FLAG",
-		"CONTRACTNUMBER", "CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO",
-		"PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE", "HAS_BROKER", "BROKER", "NON_STANDARD_FLAG",
-		"PRICE_TYPE", "CHARGE", "VOLUME", "ENERGY_FORMULA", "PPCP_PP_POOL", "PPCP_PCP_PRODUCT",
-		"CTP_POINT_CODE", "DY_BEG_DAY", "DY_END_DAY", "SCH_SCHEDULE", "CREATEDBY", "MODIFIEDBY",
-		"CREATE_DATE", "MODIFY_DATE", "EXECUTION_DATE", "EXECUTION_TIME"}
-	mock.ExpectQuery(getNucCapacityDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			1104940, "CAPCTY", 972.7, "SALE",
-			parseTime("05-05-2022"), 10322, "IBT", "INTERBOOK TRANSFER",
-			"IBT", "SENA", "Shell Energy North America (US), L.P.", 10430, "N",
-			"", "U-CAP", "SOUTH", "", 99690,
-			"Realtime 7", "COSULLIV", "CPT", "NO", "NA", "N",
-			"F", 0.1, 0, nil, "NSRS", "STD ON",
-			"ERCOT", parseTime("04-05-2022"), parseTime("04-05-2022"), "NONE", "COSULLIV", "COSULLIV",
-			parseTime("05-05-2022"), parseTime("05-05-2022"), "", "",
-		).AddRow(
-			1107318, "CAPCTY", 0.06, "PURCHASE",
-			parseTime("05-05-2022"), 10296, "CAISO", "CALIFORNIA INDEPENDENT SYSTEMS OPERATION CORP DBA CALIF",
-			"CALF IND S", "SENA", "Shell Energy North America (US), L.P.", 10430, "N",
-			"011-KW-IS-06534", "STL CAPCTY", "WEST", "", 24,
-			"POWER REAL-TIME SAN DIEGO", "PCI_ALLO", "PPT", "NO", "NA", "Y",
-			"F", 0, 0, nil, "WSCC", "HOURLY",
-			"CFE-IV-MX", parseTime("05-05-2022"), parseTime("05-05-2022"), "NERC", "PCI_GSMS", "PCI_GSMS",
-			parseTime("18-05-2022"), parseTime("18-05-2022"), "2022-05-05", "11:36:12 AM",
-		).AddRow(
-			1105338, "CAPCTY", 0.24, "PURCHASE",
-			parseTime("05-05-2022"), 10296, "CAISO", "CALIFORNIA INDEPENDENT SYSTEMS OPERATION CORP DBA CALIF",
-			"CALF IND S", "SENA", "Shell Energy North America (US), L.P.", 10430, "N",
-			"011-KW-IS-06534", "STL CAPCTY", "WEST", "", 301,
-			"SETTLEMENT BRIDGE CAISO - DA", "PCI_ALLO", "PPT", "NO", "NA", "Y",
-			"F", 0, 0, "([ER AS RRS|DA_RRS|HOURLY]*0.85)<CU>USD</CU><UT>MW</UT>", "WSCC", "HOURLY",
-			"PGAE-APND", parseTime("05-05-2022"), parseTime("05-05-2022"), "NERC", "PCI_GSMS", "PCI_GSMS",
-			parseTime("06-05-2022"), parseTime("06-05-2022"), "05/07/2022", "12:00:00 AM",
-		))
-
-	dealKeysArray := []int{1107318, 1105338}
-
-	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeysArray, []interface{}{}, "cpd_capacity_key")
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when creating the query", err)
-	}
-
-	getNucCapacityDealTermModelQ := getNucCapacityDealTermModelQuery(dealKeysQuery)
-
-	var namedParams []driver.Value
-	for _, value := range params {
-		valueNamed, _ := value.(sql.NamedArg)
-		namedParams = append(namedParams, valueNamed)
-	}
-
-	columns = []string{"CPD_CAPACITY_KEY", "DY_BEG_DAY", "DY_END_DAY"}
-	mock.ExpectQuery(getNucCapacityDealTermModelQ).WithArgs(namedParams...).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			1105338, parseTime("05-05-2022"), parseTime("05-05-2022"),
-		).AddRow(
-			1107318, parseTime("04-04-2022"), parseTime("04-04-2022"),
-		).AddRow(
-			1107318, parseTime("05-05-2022"), parseTime("05-05-2022"),
-		))
-
-	formulaDealKeys := []int{1105338}
-
-	formulaDealKeysQuery, params, err := oracle.CreateInQueryInt(formulaDealKeys, []interface{}{}, "cpd_capacity_key")
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when creating the query", err)
-	}
-
-	getNucCapacityDealIndexModelQ := getNucCapacityDealIndexModelQuery(formulaDealKeysQuery)
-
-	namedParams = nil
-	for _, value := range params {
-		valueNamed, _ := value.(sql.NamedArg)
-		namedParams = append(namedParams, valueNamed)
-	}
-
-	columns = []string{"CPD_CAPACITY_KEY", "PUBLICATION", "PUB_INDEX", "FREQUENCY"}
-	mock.ExpectQuery(getNucCapacityDealIndexModelQ).WithArgs(namedParams...).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			1105338, "ER AS RRS", "DA_RRS", "HOURLY",
-		))
-
-	type fields struct {
-		nucleusDb         *sql.DB
-		machineLearningDb *sql.DB
-		logger            logger.Logger
-	}
-	type args struct {
-		ctx         context.Context
-		lastRunTime time.Time
-		tradeDate   time.Time
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    []*nucleus.NucleusTradeHeaderModel
-		wantErr bool
-	}{
-		{
-			name: "regular run",
-			fields: fields{
-				nucleusDb:         nucleusDb,
-				machineLearningDb: nil,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx:         context.TODO(),
-				lastRunTime: now,
-				tradeDate:   now,
-			},
-			want: []*nucleus.NucleusTradeHeaderModel{
-				{
-					DealKey:             1104940,
-					DealType:            "CAPCTY",
-					TotalQuantity:       972.7,
-					DnDirection:         "SALE",
-					TransactionDate:     parseTime("05-05-2022"),
-					CyCompanyKey:        10322,
-					Company:             "IBT",
-					CompanyLongName:     "INTERBOOK TRANSFER",
-					CompanyCode:         "IBT",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					InteraffiliateFlag:  "N",
-					Contract:            "",
-					ConfirmFormat:       "U-CAP",
-					Region:              "SOUTH",
-					HsHedgeKey:          "",
-					PrtPortfolio:        99690,
-					Portfolio:           "Realtime 7",
-					UrTrader:            "COSULLIV",
-					TzTimeZone:          "CPT",
-					HasBroker:           "NO",
-					Broker:              "NA",
-					StartDate:           parseTime("04-05-2022"),
-					EndDate:             parseTime("04-05-2022"),
-					CreatedBy:           "COSULLIV",
-					ModifiedBy:          "COSULLIV",
-					CreatedAt:           parseTime("05-05-2022"),
-					ModifiedAt:          parseTime("05-05-2022"),
-					ExoticFlag:          "NA",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:          0,
-							PriceType:       "F",
-							FixedPrice:      0.1,
-							Volume:          0,
-							Pool1:           "NSRS",
-							Product1:        "STD ON",
-							PointCode1:      "ERCOT",
-							BegDate:         parseTime("04-05-2022"),
-							EndDate:         parseTime("04-05-2022"),
-							HolidaySchedule: "NONE",
-						},
-					},
-				},
-				{
-					DealKey:             1107318,
-					DealType:            "CAPCTY",
-					TotalQuantity:       0.06,
-					DnDirection:         "PURCHASE",
-					TransactionDate:     parseTime("05-05-2022"),
-					CyCompanyKey:        10296,
-					Company:             "CAISO",
-					CompanyLongName:     "CALIFORNIA INDEPENDENT SYSTEMS OPERATION CORP DBA CALIF",
-					CompanyCode:         "CALF IND S",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					InteraffiliateFlag:  "N",
-					Contract:            "011-KW-IS-06534",
-					ConfirmFormat:       "STL CAPCTY",
-					Region:              "WEST",
-					HsHedgeKey:          "",
-					PrtPortfolio:        24,
-					Portfolio:           "POWER REAL-TIME SAN DIEGO",
-					UrTrader:            "PCI_ALLO",
-					TzTimeZone:          "PPT",
-					HasBroker:           "NO",
-					Broker:              "NA",
-					StartDate:           parseTime("05-05-2022"),
-					EndDate:             parseTime("05-05-2022"),
-					CreatedBy:           "PCI_GSMS",
-					ModifiedBy:          "PCI_GSMS",
-					CreatedAt:           parseTime("18-05-2022"),
-					ModifiedAt:          parseTime("18-05-2022"),
-					ExecutionTime:       parseDateTime("2022-05-05", "11:36:12 AM"),
-					ExoticFlag:          "NA",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:          0,
-							PriceType:       "F",
-							Pool1:           "WSCC",
-							Product1:        "HOURLY",
-							PointCode1:      "CFE-IV-MX",
-							HolidaySchedule: "NERC",
-							BegDate:         parseTime("04-04-2022"),
-							EndDate:         parseTime("04-04-2022"),
-						},
-						{
-							VolSeq:          1,
-							PriceType:       "F",
-							Pool1:           "WSCC",
-							Product1:        "HOURLY",
-							PointCode1:      "CFE-IV-MX",
-							HolidaySchedule: "NERC",
-							BegDate:         parseTime("05-05-2022"),
-							EndDate:         parseTime("05-05-2022"),
-						},
-					},
-				},
-				{
-					DealKey:             1105338,
-					DealType:            "CAPCTY",
-					TotalQuantity:       0.24,
-					DnDirection:         "PURCHASE",
-					TransactionDate:     parseTime("05-05-2022"),
-					CyCompanyKey:        10296,
-					Company:             "CAISO",
-					CompanyLongName:     "CALIFORNIA INDEPENDENT SYSTEMS OPERATION CORP DBA CALIF",
-					CompanyCode:         "CALF IND S",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					InteraffiliateFlag:  "N",
-					Contract:            "011-KW-IS-06534",
-					ConfirmFormat:       "STL CAPCTY",
-					Region:              "WEST",
-					HsHedgeKey:          "",
-					PrtPortfolio:        301,
-					Portfolio:           "SETTLEMENT BRIDGE CAISO - DA",
-					UrTrader:            "PCI_ALLO",
-					TzTimeZone:          "PPT",
-					HasBroker:           "NO",
-					Broker:              "NA",
-					StartDate:           parseTime("05-05-2022"),
-					EndDate:             parseTime("05-05-2022"),
-					CreatedBy:           "PCI_GSMS",
-					ModifiedBy:          "PCI_GSMS",
-					CreatedAt:           parseTime("06-05-2022"),
-					ModifiedAt:          parseTime("06-05-2022"),
-					ExecutionTime:       parseDateTime("05/07/2022", "12:00:00 AM"),
-					ExoticFlag:          "NA",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:          0,
-							PriceType:       "F",
-							Pool1:           "WSCC",
-							Product1:        "HOURLY",
-							PointCode1:      "PGAE-APND",
-							HolidaySchedule: "NERC",
-							BegDate:         parseTime("05-05-2022"),
-							EndDate:         parseTime("05-05-2022"),
-							Indexes1: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "ER AS RRS",
-									PubIndex:    "DA_RRS",
-									Frequency:   "HOURLY",
-								},
-							},
-						},
-					},
-				},
-			},
-			wantErr: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			repo := &NucleusTradeRepository{
-				nucleusDb:         tt.fields.nucleusDb,
-				machineLearningDb: tt.fields.machineLearningDb,
-				logger:            tt.fields.logger,
-			}
-			got, err := repo.GetNucCapacityDealList(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("NucleusTradeRepository.GetNucCapacityDealList() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-
-			if len(got) != len(tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucCapacityDealList() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
-				return
-			}
-
-			for _, wantedValue := range tt.want {
-				exists := false
-				for _, value := range got {
-					if wantedValue.DealKey == value.DealKey {
-						exists = true
-						if len(value.Terms) != len(wantedValue.Terms) {
-							t.Errorf("NucleusTradeRepository.GetNucCapacityDealList() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
-							return
-						}
-
-						for _, wantedTerm := range wantedValue.Terms {
-							termExists := false
-							for _, valueTerm := range value.Terms {
-								if wantedTerm.VolSeq == valueTerm.VolSeq {
-									termExists = true
-
-									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
-										t.Errorf("NucleusTradeRepository.GetNucCapacityDealList() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes1 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes1 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucCapacityDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
-										t.Errorf("NucleusTradeRepository.GetNucCapacityDealList() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes2 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes2 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucCapacityDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if !reflect.DeepEqual(wantedTerm, valueTerm) {
-										t.Errorf("NucleusTradeRepository.GetNucCapacityDealList() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
-										return
-									}
-								}
-							}
-							if !termExists {
-								t.Errorf("NucleusTradeRepository.GetNucCapacityDealList() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
-								return
-							}
-						}
-
-						if !reflect.DeepEqual(wantedValue, value) {
-							t.Errorf("NucleusTradeRepository.GetNucCapacityDealList() wantedValue = %+v, value = %+v", wantedValue, value)
-							return
-						}
-					}
-				}
-				if !exists {
-					t.Errorf("NucleusTradeRepository.GetNucCapacityDealList() wanted value does not exist %d", wantedValue.DealKey)
-					return
-				}
-			}
-
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucCapacityDealList() got = %+v, want = %+v", got, tt.want)
-				return
-			}
-		})
-	}
-}
-
-func TestNucleusTradeRepository_GetNucPTPDealList(t *testing.T) {
-	now := time.Now()
-	serverLogger := logger.GetServerLogger()
-	serverLogger.Enable(false)
-
-	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer nucleusDb.Close()
-
-	columns := []string{"PTP_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
-		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY",
-		"LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT",
-		"REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE",
-		"HAS_BROKER", "BROKER", "DY_FLOW_DAY", "PPEP_PP_POOL", "PPEP_PEP_PRODUCT",
-		"PUBLICATION1", "PUB_INDEX1", "PUBLICATION2", "PUB_INDEX2", "CREATEDBY",
-		"MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE"}
-	mock.ExpectQuery(getNucPTPDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			273387, "PTP", "PURCHASE", parseTime("05-05-2022"),
-			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
-			"Shell Energy North America (US), L.P.", 10430, "", "",
-			"SOUTH", "", 99643, "ERCOT CASH NODAL", "JPROMU", "CPT",
-			"NO", "NA", parseTime("06-05-2022"), "ERCRR", "HOURLY",
-			"ER DA LMP", "NORTH_HUB", "ER RT LMP", "SOUTH_HUB", "PCI_GSMS",
-			"PCI_GSMS", parseTime("06-05-2022"), parseTime("06-05-2022"),
-		).AddRow(
-			273385, "PTP", "PURCHASE", parseTime("05-05-2022"),
-			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
-			"Shell Energy North America (US), L.P.", 10430, "", "",
-			"SOUTH", "", 99643, "ERCOT CASH NODAL", "JPROMU", "CPT",
-			"NO", "NA", parseTime("06-05-2022"), "ERCRR", "HOURLY",
-			"ER DA LMP", "NORTH_HUB", "ER RT LMP", "NORTH_ZONE", "PCI_GSMS",
-			"PCI_GSMS", parseTime("06-05-2022"), parseTime("06-05-2022"),
-		).AddRow(
-			273377, "PTP", "PURCHASE", parseTime("05-05-2022"),
-			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
-			"Shell Energy North America (US), L.P.", 10430, "", "",
-			"SOUTH", "", 99643, "ERCOT CASH NODAL", "JPROMU", "CPT",
-			"NO", "NA", parseTime("06-05-2022"), "ERCRR", "HOURLY",
-			"ER DA LMP", "HOUSTON_HUB", "ER RT LMP", "HOUSTON_ZONE", "PCI_GSMS",
-			"PCI_GSMS", parseTime("06-05-2022"), parseTime("06-05-2022"),
-		))
-
-	type fields struct {
-		nucleusDb         *sql.DB
-		machineLearningDb *sql.DB
-		logger            logger.Logger
-	}
-	type args struct {
-		ctx         context.Context
-		lastRunTime time.Time
-		tradeDate   time.Time
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    []*nucleus.NucleusTradeHeaderModel
-		wantErr bool
-	}{
-		{
-			name: "regular run",
-			fields: fields{
-				nucleusDb:         nucleusDb,
-				machineLearningDb: nil,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx:         context.TODO(),
-				lastRunTime: now,
-				tradeDate:   now,
-			},
-			want: []*nucleus.NucleusTradeHeaderModel{
-				{
-					DealKey:             273387,
-					DealType:            "PTP",
-					DnDirection:         "PURCHASE",
-					TransactionDate:     parseTime("05-05-2022"),
-					CyCompanyKey:        10171,
-					Company:             "ERCOT",
-					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
-					CompanyCode:         "ERCOT",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					Contract:            "",
-					ConfirmFormat:       "",
-					Region:              "SOUTH",
-					HsHedgeKey:          "",
-					PrtPortfolio:        99643,
-					Portfolio:           "ERCOT CASH NODAL",
-					UrTrader:            "JPROMU",
-					TzTimeZone:          "CPT",
-					HasBroker:           "NO",
-					Broker:              "NA",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:   0,
-							BegDate:  parseTime("06-05-2022"),
-							EndDate:  parseTime("06-05-2022"),
-							Pool1:    "ERCRR",
-							Product1: "HOURLY",
-							Indexes1: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "ER DA LMP",
-									PubIndex:    "NORTH_HUB",
-									Frequency:   "HOURLY",
-								},
-							},
-							Indexes2: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "ER RT LMP",
-									PubIndex:    "SOUTH_HUB",
-									Frequency:   "HOURLY",
-								},
-							},
-						},
-					},
-					CreatedBy:          "PCI_GSMS",
-					ModifiedBy:         "PCI_GSMS",
-					CreatedAt:          parseTime("06-05-2022"),
-					ModifiedAt:         parseTime("06-05-2022"),
-					InteraffiliateFlag: "N",
-				},
-				{
-					DealKey:             273385,
-					DealType:            "PTP",
-					DnDirection:         "PURCHASE",
-					TransactionDate:     parseTime("05-05-2022"),
-					CyCompanyKey:        10171,
-					Company:             "ERCOT",
-					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
-					CompanyCode:         "ERCOT",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					Contract:            "",
-					ConfirmFormat:       "",
-					Region:              "SOUTH",
-					HsHedgeKey:          "",
-					PrtPortfolio:        99643,
-					Portfolio:           "ERCOT CASH NODAL",
-					UrTrader:            "JPROMU",
-					TzTimeZone:          "CPT",
-					HasBroker:           "NO",
-					Broker:              "NA",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:   0,
-							BegDate:  parseTime("06-05-2022"),
-							EndDate:  parseTime("06-05-2022"),
-							Pool1:    "ERCRR",
-							Product1: "HOURLY",
-							Indexes1: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "ER DA LMP",
-									PubIndex:    "NORTH_HUB",
-									Frequency:   "HOURLY",
-								},
-							},
-							Indexes2: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "ER RT LMP",
-									PubIndex:    "NORTH_ZONE",
-									Frequency:   "HOURLY",
-								},
-							},
-						},
-					},
-					CreatedBy:          "PCI_GSMS",
-					ModifiedBy:         "PCI_GSMS",
-					CreatedAt:          parseTime("06-05-2022"),
-					ModifiedAt:         parseTime("06-05-2022"),
-					InteraffiliateFlag: "N",
-				},
-				{
-					DealKey:             273377,
-					DealType:            "PTP",
-					DnDirection:         "PURCHASE",
-					TransactionDate:     parseTime("05-05-2022"),
-					CyCompanyKey:        10171,
-					Company:             "ERCOT",
-					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
-					CompanyCode:         "ERCOT",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					Contract:            "",
-					ConfirmFormat:       "",
-					Region:              "SOUTH",
-					HsHedgeKey:          "",
-					PrtPortfolio:        99643,
-					Portfolio:           "ERCOT CASH NODAL",
-					UrTrader:            "JPROMU",
-					TzTimeZone:          "CPT",
-					HasBroker:           "NO",
-					Broker:              "NA",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:   0,
-							BegDate:  parseTime("06-05-2022"),
-							EndDate:  parseTime("06-05-2022"),
-							Pool1:    "ERCRR",
-							Product1: "HOURLY",
-							Indexes1: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "ER DA LMP",
-									PubIndex:    "HOUSTON_HUB",
-									Frequency:   "HOURLY",
-								},
-							},
-							Indexes2: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "ER RT LMP",
-									PubIndex:    "HOUSTON_ZONE",
-									Frequency:   "HOURLY",
-								},
-							},
-						},
-					},
-					CreatedBy:          "PCI_GSMS",
-					ModifiedBy:         "PCI_GSMS",
-					CreatedAt:          parseTime("06-05-2022"),
-					ModifiedAt:         parseTime("06-05-2022"),
-					InteraffiliateFlag: "N",
-				},
-			},
-			wantErr: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			repo := &NucleusTradeRepository{
-				nucleusDb:         tt.fields.nucleusDb,
-				machineLearningDb: tt.fields.machineLearningDb,
-				logger:            tt.fields.logger,
-			}
-			got, err := repo.GetNucPTPDealList(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("NucleusTradeRepository.GetNucPTPDealList() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-
-			if len(got) != len(tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucPTPDealList() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
-				return
-			}
-
-			for _, wantedValue := range tt.want {
-				exists := false
-				for _, value := range got {
-					if wantedValue.DealKey == value.DealKey {
-						exists = true
-						if len(value.Terms) != len(wantedValue.Terms) {
-							t.Errorf("NucleusTradeRepository.GetNucPTPDealList() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
-							return
-						}
-
-						for _, wantedTerm := range wantedValue.Terms {
-							termExists := false
-							for _, valueTerm := range value.Terms {
-								if wantedTerm.VolSeq == valueTerm.VolSeq {
-									termExists = true
-
-									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
-										t.Errorf("NucleusTradeRepository.GetNucPTPDealList() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes1 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes1 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucPTPDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
-										t.Errorf("NucleusTradeRepository.GetNucPTPDealList() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes2 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes2 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucPTPDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if !reflect.DeepEqual(wantedTerm, valueTerm) {
-										t.Errorf("NucleusTradeRepository.GetNucPTPDealList() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
-										return
-									}
-								}
-							}
-							if !termExists {
-								t.Errorf("NucleusTradeRepository.GetNucPTPDealList() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
-								return
-							}
-						}
-
-						if !reflect.DeepEqual(wantedValue, value) {
-							t.Errorf("NucleusTradeRepository.GetNucPTPDealList() wantedValue = %+v, value = %+v", wantedValue, value)
-							return
-						}
-					}
-				}
-				if !exists {
-					t.Errorf("NucleusTradeRepository.GetNucPTPDealList() wanted value does not exist %d", wantedValue.DealKey)
-					return
-				}
-			}
-
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucPTPDealList() got = %+v, want = %+v", got, tt.want)
-				return
-			}
-		})
-	}
-}
-
-func TestNucleusTradeRepository_GetNucEmissionDealList(t *testing.T) {
-	now := time.Now()
-	serverLogger := logger.GetServerLogger()
-	serverLogger.Enable(false)
-
-	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer nucleusDb.Close()
-
-	columns := []string{"EMISSION_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
-		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY",
-		"LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT",
-		"REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE",
-		"HAS_BROKER", "BROKER", "CREATEDBY", "MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE",
-		"EXECUTION_DATE", "EXECUTION_TIME"}
-	mock.ExpectQuery(getNucEmissionDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			61150, "EMSSN", "SALE", parseTime("05-05-2022"),
-			17038, "CSU", "THE BOARD OF TRUSTEES OF THE CALIFORNIA STATE UNIV", "CSU", "SENA",
-			"Shell Energy North America (US), L.P.", 10430, "013-KW-SU-21400", "EMISSION",
-			"WEST", "CandI CRED", 99297, "WEST ENVIRON - CA RETAIL", "SGAPPY", "",
-			"NO", "NA", "SGAPPY", "SGAPPY", parseTime("05-05-2022"), parseTime("05-05-2022"),
-			"", "",
-		).AddRow(
-			61151, "EMSSN", "SALE", parseTime("05-05-2022"),
-			17038, "CSU", "THE BOARD OF TRUSTEES OF THE CALIFORNIA STATE UNIV", "CSU", "SENA",
-			"Shell Energy North America (US), L.P.", 10430, "013-KW-SU-21400", "EMISSION",
-			"WEST", "CandI CRED", 99297, "WEST ENVIRON - CA RETAIL", "SGAPPY", "",
-			"NO", "NA", "SGAPPY", "SGAPPY", parseTime("05-05-2022"), parseTime("05-05-2022"),
-			"", "",
-		))
-
-	dealKeysArray := []int{61150, 61151}
-
-	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeysArray, []interface{}{}, "pv.ed_emission_key")
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when creating the query", err)
-	}
-
-	getNucCapacityDealTermModelQ := getNucEmissionDealListTermModelQuery(dealKeysQuery)
-
-	var namedParams []driver.Value
-	for _, value := range params {
-		valueNamed, _ := value.(sql.NamedArg)
-		namedParams = append(namedParams, valueNamed)
-	}
-
-	columns = []string{"ED_EMISSION_KEY", "VOLUME_SEQ", "DY_BEG_DAY", "DY_END_DAY",
-		"PRICE_TYPE", "PRICE", "VOLUME", "EPDT_EMISSION_PRODUCT", "CTP_POINT_CODE",
-		"FORMULA"}
-	mock.ExpectQuery(getNucCapacityDealTermModelQ).WithArgs(namedParams...).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			61150, 1, parseTime("01-08-2023"), parseTime("31-08-2023"),
-			"F", 16.85, 10662, "CA REC", "MID-C",
-			"[OPIS|CELRINC|DAILY] *0.95",
-		).AddRow(
-			61151, 1, parseTime("01-09-2023"), parseTime("30-09-2023"),
-			"F", 16.85, 11117, "CA REC", "MID-C",
-			"",
-		))
-
-	type fields struct {
-		nucleusDb         *sql.DB
-		machineLearningDb *sql.DB
-		logger            logger.Logger
-	}
-	type args struct {
-		ctx         context.Context
-		lastRunTime time.Time
-		tradeDate   time.Time
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    []*nucleus.NucleusTradeHeaderModel
-		wantErr bool
-	}{
-		{
-			name: "normal run",
-			fields: fields{
-				nucleusDb:         nucleusDb,
-				machineLearningDb: nil,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx:         context.TODO(),
-				lastRunTime: now,
-				tradeDate:   now,
-			},
-			want: []*nucleus.NucleusTradeHeaderModel{
-				{
-					DealKey:             61150,
-					DealType:            "EMSSN",
-					DnDirection:         "SALE",
-					TransactionDate:     parseTime("05-05-2022"),
-					CyCompanyKey:        17038,
-					Company:             "CSU",
-					CompanyLongName:     "THE BOARD OF TRUSTEES OF THE CALIFORNIA STATE UNIV",
-					CompanyCode:         "CSU",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					Contract:            "013-KW-SU-21400",
-					ConfirmFormat:       "EMISSION",
-					Region:              "WEST",
-					HsHedgeKey:          "CandI CRED",
-					PrtPortfolio:        99297,
-					Portfolio:           "WEST ENVIRON - CA RETAIL",
-					UrTrader:            "SGAPPY",
-					TzTimeZone:          "",
-					HasBroker:           "NO",
-					Broker:              "NA",
-					CreatedBy:           "SGAPPY",
-					ModifiedBy:          "SGAPPY",
-					CreatedAt:           parseTime("05-05-2022"),
-					ModifiedAt:          parseTime("05-05-2022"),
-					ExoticFlag:          "NA",
-					InteraffiliateFlag:  "N",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:     1,
-							BegDate:    parseTime("01-08-2023"),
-							EndDate:    parseTime("31-08-2023"),
-							PriceType:  "F",
-							FixedPrice: 16.85,
-							Volume:     10662,
-							Product1:   "CA REC",
-							PointCode1: "MID-C",
-							Formula1:   "[OPIS|CELRINC|DAILY] *0.95",
-							Indexes1: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "OPIS",
-									PubIndex:    "CELRINC",
-									Frequency:   "DAILY",
-								},
-							},
-						},
-					},
-				},
-				{
-					DealKey:             61151,
-					DealType:            "EMSSN",
-					DnDirection:         "SALE",
-					TransactionDate:     parseTime("05-05-2022"),
-					CyCompanyKey:        17038,
-					Company:             "CSU",
-					CompanyLongName:     "THE BOARD OF TRUSTEES OF THE CALIFORNIA STATE UNIV",
-					CompanyCode:         "CSU",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					Contract:            "013-KW-SU-21400",
-					ConfirmFormat:       "EMISSION",
-					Region:              "WEST",
-					HsHedgeKey:          "CandI CRED",
-					PrtPortfolio:        99297,
-					Portfolio:           "WEST ENVIRON - CA RETAIL",
-					UrTrader:            "SGAPPY",
-					TzTimeZone:          "",
-					HasBroker:           "NO",
-					Broker:              "NA",
-					CreatedBy:           "SGAPPY",
-					ModifiedBy:          "SGAPPY",
-					CreatedAt:           parseTime("05-05-2022"),
-					ModifiedAt:          parseTime("05-05-2022"),
-					ExoticFlag:          "NA",
-					InteraffiliateFlag:  "N",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:     1,
-							BegDate:    parseTime("01-09-2023"),
-							EndDate:    parseTime("30-09-2023"),
-							PriceType:  "F",
-							FixedPrice: 16.85,
-							Volume:     11117,
-							Product1:   "CA REC",
-							PointCode1: "MID-C",
-						},
-					},
-				},
-			},
-			wantErr: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			repo := &NucleusTradeRepository{
-				nucleusDb:         tt.fields.nucleusDb,
-				machineLearningDb: tt.fields.machineLearningDb,
-				logger:            tt.fields.logger,
-			}
-			got, err := repo.GetNucEmissionDealList(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("NucleusTradeRepository.GetNucEmissionDealList() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-
-			if len(got) != len(tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucEmissionDealList() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
-				return
-			}
-
-			for _, wantedValue := range tt.want {
-				exists := false
-				for _, value := range got {
-					if wantedValue.DealKey == value.DealKey {
-						exists = true
-						if len(value.Terms) != len(wantedValue.Terms) {
-							t.Errorf("NucleusTradeRepository.GetNucEmissionDealList() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
-							return
-						}
-
-						for _, wantedTerm := range wantedValue.Terms {
-							termExists := false
-							for _, valueTerm := range value.Terms {
-								if wantedTerm.VolSeq == valueTerm.VolSeq {
-									termExists = true
-
-									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
-										t.Errorf("NucleusTradeRepository.GetNucEmissionDealList() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes1 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes1 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucEmissionDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
-										t.Errorf("NucleusTradeRepository.GetNucEmissionDealList() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes2 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes2 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucEmissionDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if !reflect.DeepEqual(wantedTerm, valueTerm) {
-										t.Errorf("NucleusTradeRepository.GetNucEmissionDealList() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
-										return
-									}
-								}
-							}
-							if !termExists {
-								t.Errorf("NucleusTradeRepository.GetNucEmissionDealList() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
-								return
-							}
-						}
-
-						if !reflect.DeepEqual(wantedValue, value) {
-							t.Errorf("NucleusTradeRepository.GetNucEmissionDealList() wantedValue = %+v, value = %+v", wantedValue, value)
-							return
-						}
-					}
-				}
-				if !exists {
-					t.Errorf("NucleusTradeRepository.GetNucEmissionDealList() wanted value does not exist %d", wantedValue.DealKey)
-					return
-				}
-			}
-
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucEmissionDealList() got = %+v, want = %+v", got, tt.want)
-				return
-			}
-		})
-	}
-}
-
-func TestNucleusTradeRepository_GetNucEmissionOptionDealList(t *testing.T) {
-	now := time.Now()
-	serverLogger := logger.GetServerLogger()
-	serverLogger.Enable(false)
-
-	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer nucleusDb.Close()
-
-	columns := []string{"EOPTION_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
-		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY",
-		"LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT", "REGION",
-		"HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE",
-		"HAS_BROKER", "BROKER", "ED_EMISSION_KEY", "STRIKE_PRICE", "VOLUME", "CREATEDBY",
-		"MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE", "EXECUTION_DATE", "EXECUTION_TIME"}
-	mock.ExpectQuery(getNucEmissionOptionDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			12706, "EMOPTS", "SALE", parseTime("27-01-2016"),
-			16370, "NEWFIN", "NEWEDGE FINANCIAL INC", "NEWFIN", "SENA",
-			"Shell Energy North America (US), L.P.", 10430, "", "EMSSN OPT", "EAST",
-			"REALIZED", 99444, "HOUSTON EMISSIONS FIN US", "DEKING", "EST",
-			"YES", "TFS", 31321, 7.5, -250000, "DEKING",
-			"NXTGEN", parseTime("27-01-2016"), parseTime("14-12-2016"), "01/27/2016", "10:07:59 AM",
-		).AddRow(
-			12711, "EMOPTS", "PURCHASE", parseTime("27-01-2016"),
-			16370, "NEWFIN", "NEWEDGE FINANCIAL INC", "NEWFIN", "SENA",
-			"Shell Energy North America (US), L.P.", 10430, "", "EMSSN OPT", "EAST",
-			"REALIZED", 99444, "HOUSTON EMISSIONS FIN US", "DEKING", "EST",
-			"NO", "NA", 31326, 8, 700000, "DEKING",
-			"NXTGEN", parseTime("27-01-2016"), parseTime("14-12-2016"), "01/27/2016", "10:12:59 AM",
-		))
-
-	dealKeysArray := []int{31321, 31326}
-
-	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeysArray, []interface{}{}, "pv.ed_emission_key")
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when creating the query", err)
-	}
-
-	getNucEmissionOptionDealTermListQ := getNucEmissionOptionDealTermListQuery(dealKeysQuery)
-
-	var namedParams []driver.Value
-	for _, value := range params {
-		valueNamed, _ := value.(sql.NamedArg)
-		namedParams = append(namedParams, valueNamed)
-	}
-
-	columns = []string{"ED_EMISSION_KEY", "VOLUME_SEQ", "DY_BEG_DAY", "DY_END_DAY",
-		"CTP_POINT_CODE", "EPDT_EMISSION_PRODUCT"}
-	mock.ExpectQuery(getNucEmissionOptionDealTermListQ).WithArgs(namedParams...).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			31321, 1, parseTime("01-04-2010"), parseTime("30-04-2010"), "MID-C", "CA REC",
-		).AddRow(
-			31326, 1, parseTime("01-04-2010"), parseTime("30-04-2010"), "MID-C", "CA REC",
-		))
-
-	type fields struct {
-		nucleusDb         *sql.DB
-		machineLearningDb *sql.DB
-		logger            logger.Logger
-	}
-	type args struct {
-		ctx         context.Context
-		lastRunTime time.Time
-		tradeDate   time.Time
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    []*nucleus.NucleusTradeHeaderModel
-		wantErr bool
-	}{
-		{
-			name: "regular run",
-			fields: fields{
-				nucleusDb:         nucleusDb,
-				machineLearningDb: nil,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx:         context.TODO(),
-				lastRunTime: now,
-				tradeDate:   now,
-			},
-			want: []*nucleus.NucleusTradeHeaderModel{
-				{
-					DealKey:             12706,
-					DealType:            "EMOPTS",
-					DnDirection:         "SALE",
-					TransactionDate:     parseTime("27-01-2016"),
-					CyCompanyKey:        16370,
-					Company:             "NEWFIN",
-					CompanyLongName:     "NEWEDGE FINANCIAL INC",
-					CompanyCode:         "NEWFIN",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					Contract:            "",
-					ConfirmFormat:       "EMSSN OPT",
-					Region:              "EAST",
-					HsHedgeKey:          "REALIZED",
-					PrtPortfolio:        99444,
-					Portfolio:           "HOUSTON EMISSIONS FIN US",
-					UrTrader:            "DEKING",
-					TzTimeZone:          "EST",
-					HasBroker:           "YES",
-					Broker:              "TFS",
-					CreatedBy:           "DEKING",
-					ModifiedBy:          "NXTGEN",
-					CreatedAt:           parseTime("27-01-2016"),
-					ModifiedAt:          parseTime("14-12-2016"),
-					ExecutionTime:       parseDateTime("01/27/2016", "10:07:59 AM"),
-					ExoticFlag:          "NA",
-					InteraffiliateFlag:  "N",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:     1,
-							FixedPrice: 7.5,
-							Volume:     -250000,
-							BegDate:    parseTime("01-04-2010"),
-							EndDate:    parseTime("30-04-2010"),
-							PointCode1: "MID-C",
-							Product1:   "CA REC",
-						},
-					},
-				},
-				{
-					DealKey:             12711,
-					DealType:            "EMOPTS",
-					DnDirection:         "PURCHASE",
-					TransactionDate:     parseTime("27-01-2016"),
-					CyCompanyKey:        16370,
-					Company:             "NEWFIN",
-					CompanyLongName:     "NEWEDGE FINANCIAL INC",
-					CompanyCode:         "NEWFIN",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					Contract:            "",
-					ConfirmFormat:       "EMSSN OPT",
-					Region:              "EAST",
-					HsHedgeKey:          "REALIZED",
-					PrtPortfolio:        99444,
-					Portfolio:           "HOUSTON EMISSIONS FIN US",
-					UrTrader:            "DEKING",
-					TzTimeZone:          "EST",
-					HasBroker:           "NO",
-					Broker:              "NA",
-					CreatedBy:           "DEKING",
-					ModifiedBy:          "NXTGEN",
-					CreatedAt:           parseTime("27-01-2016"),
-					ModifiedAt:          parseTime("14-12-2016"),
-					ExecutionTime:       parseDateTime("01/27/2016", "10:12:59 AM"),
-					ExoticFlag:          "NA",
-					InteraffiliateFlag:  "N",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:     1,
-							FixedPrice: 8,
-							Volume:     700000,
-							BegDate:    parseTime("01-04-2010"),
-							EndDate:    parseTime("30-04-2010"),
-							PointCode1: "MID-C",
-							Product1:   "CA REC",
-						},
-					},
-				},
-			},
-			wantErr: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			repo := &NucleusTradeRepository{
-				nucleusDb:         tt.fields.nucleusDb,
-				machineLearningDb: tt.fields.machineLearningDb,
-				logger:            tt.fields.logger,
-			}
-			got, err := repo.GetNucEmissionOptionDealList(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealList() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-
-			if len(got) != len(tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealList() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
-				return
-			}
-
-			for _, wantedValue := range tt.want {
-				exists := false
-				for _, value := range got {
-					if wantedValue.DealKey == value.DealKey {
-						exists = true
-						if len(value.Terms) != len(wantedValue.Terms) {
-							t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealList() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
-							return
-						}
-
-						for _, wantedTerm := range wantedValue.Terms {
-							termExists := false
-							for _, valueTerm := range value.Terms {
-								if wantedTerm.VolSeq == valueTerm.VolSeq {
-									termExists = true
-
-									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
-										t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealList() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes1 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes1 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
-										t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealList() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes2 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes2 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if !reflect.DeepEqual(wantedTerm, valueTerm) {
-										t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealList() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
-										return
-									}
-								}
-							}
-							if !termExists {
-								t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealList() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
-								return
-							}
-						}
-
-						if !reflect.DeepEqual(wantedValue, value) {
-							t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealList() wantedValue = %+v, value = %+v", wantedValue, value)
-							return
-						}
-					}
-				}
-				if !exists {
-					t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealList() wanted value does not exist %d", wantedValue.DealKey)
-					return
-				}
-			}
-
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealList() got = %+v, want = %+v", got, tt.want)
-				return
-			}
-		})
-	}
-}
-
-func TestNucleusTradeRepository_GetNucSpreadOptionsDealList(t *testing.T) {
-	now := time.Now()
-	serverLogger := logger.GetServerLogger()
-	serverLogger.Enable(false)
-
-	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer nucleusDb.Close()
-
-	columns := []string{"SPREAD_OPTION_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
-		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY", "LEGALENTITYLONGNAME",
-		"CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO",
-		"PORTFOLIO", "UR_TRADER", "IB_PRT_PORTFOLIO", "IB_PORTFOLIO", "IB_UR_TRADER", "TZ_TIME_ZONE",
-		"HAS_BROKER", "BROKER", "DY_BEG_DAY1", "DY_END_DAY1", "SCH_SCHEDULE", "FORMULA1", "FORMULA2",
-		"PPEP_PP_POOL1", "PPEP_PP_POOL2", "PPEP_PEP_PRODUCT1", "PPEP_PEP_PRODUCT2", "VOLUME",
-		"STRIKE_PRICE", "CREATEDBY", "MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE", "EXECUTION_DATE",
-		"EXECUTION_TIME", "EXOTIC_FLAG", "POINT_CODE"}
-	mock.ExpectQuery(getNucSpreadOptionsDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			77283, "SPDOPT", "PURCHASE", parseTime("25-05-2022"),
-			21589, "MIDTOPO", "MIDDLETOWN POWER LLC", "MIDTOPO", "SENA", "Shell Energy North America (US), L.P.",
-			10430, "013-KW-BI-32897", "DF7EXPRESO", "EAST", "FOGECA", 99896,
-			"FOGECA", "GGULYASS", nil, "", "", "",
-			"NO", "NA", parseTime("01-06-2022"), parseTime("30-06-2022"), "NERC", "[NE DA LMP|4000|HOURLY]", "[GD|TENN Z6 SOUTH|DAILY]",
-			"XMIDX", "", "STD 7x24", "", 226,
-			11, "GGULYASS", "GGULYASS", parseTime("26-05-2022"), parseTime("26-05-2022"), "05/26/2022",
-			"", "Yes", "NOT APPLICABLE",
-		).AddRow(
-			77294, "SPDOPT", "PURCHASE", parseTime("25-05-2022"),
-			21589, "MIDTOPO", "MIDDLETOWN POWER LLC", "MIDTOPO", "SENA", "Shell Energy North America (US), L.P.",
-			10430, "013-KW-BI-32897", "DF7EXPRESO", "EAST", "FOGECA", 99896,
-			"FOGECA", "GGULYASS", nil, "", "", "",
-			"NO", "NA", parseTime("01-10-2022"), parseTime("31-10-2022"), "NERC", "([PJM DA LMP|34887979|HOURLY])", "([IF|MICHCON LEU|MONTHLY] *1.0163)",
-			"XMIDX", "", "STD 7x24", "", 117,
-			10.65, "GGULYASS", "GGULYASS", parseTime("26-05-2022"), parseTime("26-05-2022"), "05/26/2022",
-			"", "Yes", "NOT APPLICABLE",
-		))
-
-	type fields struct {
-		nucleusDb         *sql.DB
-		machineLearningDb *sql.DB
-		logger            logger.Logger
-	}
-	type args struct {
-		ctx         context.Context
-		lastRunTime time.Time
-		tradeDate   time.Time
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    []*nucleus.NucleusTradeHeaderModel
-		wantErr bool
-	}{
-		{
-			name: "regular run",
-			fields: fields{
-				nucleusDb:         nucleusDb,
-				machineLearningDb: nil,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx:         context.TODO(),
-				lastRunTime: now,
-				tradeDate:   now,
-			},
-			want: []*nucleus.NucleusTradeHeaderModel{
-				{
-					DealKey:             77283,
-					DealType:            "SPDOPT",
-					DnDirection:         "PURCHASE",
-					TransactionDate:     parseTime("25-05-2022"),
-					CyCompanyKey:        21589,
-					Company:             "MIDTOPO",
-					CompanyLongName:     "MIDDLETOWN POWER LLC",
-					CompanyCode:         "MIDTOPO",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					Contract:            "013-KW-BI-32897",
-					ConfirmFormat:       "DF7EXPRESO",
-					Region:              "EAST",
-					HsHedgeKey:          "FOGECA",
-					PrtPortfolio:        99896,
-					Portfolio:           "FOGECA",
-					UrTrader:            "GGULYASS",
-					IbPrtPortfolio:      0,
-					IbPortfolio:         "",
-					IbUrTrader:          "",
-					TzTimeZone:          "",
-					HasBroker:           "NO",
-					Broker:              "NA",
-					StartDate:           parseTime("01-06-2022"),
-					EndDate:             parseTime("30-06-2022"),
-					CreatedBy:           "GGULYASS",
-					ModifiedBy:          "GGULYASS",
-					CreatedAt:           parseTime("26-05-2022"),
-					ModifiedAt:          parseTime("26-05-2022"),
-					ExecutionTime:       parseDateTime("05/26/2022", ""),
-					ExoticFlag:          "Yes",
-					InteraffiliateFlag:  "N",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							HolidaySchedule: "NERC",
-							Pool1:           "XMIDX",
-							Pool2:           "",
-							Product1:        "STD 7x24",
-							Product2:        "",
-							Volume:          226,
-							FixedPrice:      11,
-							PointCode1:      "NOT APPLICABLE",
-							BegDate:         parseTime("01-06-2022"),
-							EndDate:         parseTime("30-06-2022"),
-							Indexes1: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "NE DA LMP",
-									PubIndex:    "4000",
-									Frequency:   "HOURLY",
-								},
-							},
-							Indexes2: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "GD",
-									PubIndex:    "TENN Z6 SOUTH",
-									Frequency:   "DAILY",
-								},
-							},
-						},
-					},
-				},
-				{
-					DealKey:             77294,
-					DealType:            "SPDOPT",
-					DnDirection:         "PURCHASE",
-					TransactionDate:     parseTime("25-05-2022"),
-					CyCompanyKey:        21589,
-					Company:             "MIDTOPO",
-					CompanyLongName:     "MIDDLETOWN POWER LLC",
-					CompanyCode:         "MIDTOPO",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					Contract:            "013-KW-BI-32897",
-					ConfirmFormat:       "DF7EXPRESO",
-					Region:              "EAST",
-					HsHedgeKey:          "FOGECA",
-					PrtPortfolio:        99896,
-					Portfolio:           "FOGECA",
-					UrTrader:            "GGULYASS",
-					IbPrtPortfolio:      0,
-					IbPortfolio:         "",
-					IbUrTrader:          "",
-					TzTimeZone:          "",
-					HasBroker:           "NO",
-					Broker:              "NA",
-					StartDate:           parseTime("01-10-2022"),
-					EndDate:             parseTime("31-10-2022"),
-					CreatedBy:           "GGULYASS",
-					ModifiedBy:          "GGULYASS",
-					CreatedAt:           parseTime("26-05-2022"),
-					ModifiedAt:          parseTime("26-05-2022"),
-					ExecutionTime:       parseDateTime("05/26/2022", ""),
-					ExoticFlag:          "Yes",
-					InteraffiliateFlag:  "N",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							HolidaySchedule: "NERC",
-							Pool1:           "XMIDX",
-							Pool2:           "",
-							Product1:        "STD 7x24",
-							Product2:        "",
-							Volume:          117,
-							FixedPrice:      10.65,
-							PointCode1:      "NOT APPLICABLE",
-							BegDate:         parseTime("01-10-2022"),
-							EndDate:         parseTime("31-10-2022"),
-							Indexes1: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "PJM DA LMP",
-									PubIndex:    "34887979",
-									Frequency:   "HOURLY",
-								},
-							},
-							Indexes2: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "IF",
-									PubIndex:    "MICHCON LEU",
-									Frequency:   "MONTHLY",
-								},
-							},
-						},
-					},
-				},
-			},
-			wantErr: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			repo := &NucleusTradeRepository{
-				nucleusDb:         tt.fields.nucleusDb,
-				machineLearningDb: tt.fields.machineLearningDb,
-				logger:            tt.fields.logger,
-			}
-			got, err := repo.GetNucSpreadOptionsDealList(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealList() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-
-			if len(got) != len(tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealList() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
-				return
-			}
-
-			for _, wantedValue := range tt.want {
-				exists := false
-				for _, value := range got {
-					if wantedValue.DealKey == value.DealKey {
-						exists = true
-						if len(value.Terms) != len(wantedValue.Terms) {
-							t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealList() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
-							return
-						}
-
-						for _, wantedTerm := range wantedValue.Terms {
-							termExists := false
-							for _, valueTerm := range value.Terms {
-								if wantedTerm.VolSeq == valueTerm.VolSeq {
-									termExists = true
-
-									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
-										t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealList() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes1 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes1 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
-										t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealList() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes2 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes2 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if !reflect.DeepEqual(wantedTerm, valueTerm) {
-										t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealList() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
-										return
-									}
-								}
-							}
-							if !termExists {
-								t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealList() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
-								return
-							}
-						}
-
-						if !reflect.DeepEqual(wantedValue, value) {
-							t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealList() wantedValue = %+v, value = %+v", wantedValue, value)
-							return
-						}
-					}
-				}
-				if !exists {
-					t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealList() wanted value does not exist %d", wantedValue.DealKey)
-					return
-				}
-			}
-
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealList() got = %+v, want = %+v", got, tt.want)
-				return
-			}
-		})
-	}
-}
-
-func TestNucleusTradeRepository_GetNucHeatRateSwapsDealList(t *testing.T) {
-	now := time.Now()
-	serverLogger := logger.GetServerLogger()
-	serverLogger.Enable(false)
-
-	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer nucleusDb.Close()
-
-	columns := []string{"HRSWPS_KEY", "DEAL_TYPE", "TOTAL_QUANTITY", "DN_DIRECTION",
-		"TRANSACTION_DATE", "CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE",
-		"LEGALENTITY", "LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "INTERAFFILIATE_FLAG",
-		"CONTRACTNUMBER", "CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO",
-		"PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE", "HAS_BROKER", "BROKER", "OPTION_KEY",
-		"PPEP_PP_POOL", "PPEP_PEP_PRODUCT", "PIF_PI_PB_PUBLICATION1", "PIF_PI_PUB_INDEX1",
-		"PIF_PI_PB_PUBLICATION2", "PIF_PI_PUB_INDEX2", "PIF_FRQ_FREQUENCY2", "DY_BEG_DAY",
-		"DY_END_DAY", "SCH_SCHEDULE", "VOLUME1", "CREATEDBY", "MODIFIEDBY", "CREATE_DATE",
-		"MODIFY_DATE", "EXECUTION_DATE", "EXECUTION_TIME"}
-	mock.ExpectQuery(getNucHeatRateSwapsDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			16495, "HRSWPS", 175200, "SALE",
-			parseTime("01-06-2022"), 10105, "O&R", "ORANGE AND ROCKLAND UTILITIES INC", "ORNGE&RCK",
-			"STRM", "SHELL TRADING RISK MANAGEMENT LLC", 18726, "N",
-			"033-RM-FI-10415", "HR SWAP", "EAST", "", 99482,
-			"POWER_RM_CONSUMERS", "DMOLIN", "", "NO", "NA", nil,
-			"NYCAG", "STD 7x24", "NY DA LMP", "61758",
-			"NYMEX", "NG", "MONTHLY", parseTime("23-01-2023"),
-			parseTime("31-12-2023"), "NERC", -20, "DMOLIN", "DMOLIN", parseTime("01-06-2022"),
-			parseTime("01-06-2022"), "06/01/2022", "09:10:00 AM",
-		).AddRow(
-			16496, "HRSWPS", 175200, "PURCHASE",
-			parseTime("01-06-2022"), 10430, "SENA", "Shell Energy North America (US), L.P.", "SENA",
-			"STRM", "SHELL TRADING RISK MANAGEMENT LLC", 18726, "Y",
-			"033-RM-FI-20570", "HR SWAP", "EAST", "", 99482,
-			"POWER_RM_CONSUMERS", "DMOLIN", "", "NO", "NA", nil,
-			"NYCAG", "STD 7x24", "NY DA LMP", "61758",
-			"NYMEX", "NG", "MONTHLY", parseTime("23-01-2023"),
-			parseTime("31-12-2023"), "NERC", 20, "DMOLIN", "DMOLIN", parseTime("01-06-2022"),
-			parseTime("01-06-2022"), "06/01/2022", "09:10:00 AM",
-		))
-
-	type fields struct {
-		nucleusDb         *sql.DB
-		machineLearningDb *sql.DB
-		logger            logger.Logger
-	}
-	type args struct {
-		ctx         context.Context
-		lastRunTime time.Time
-		tradeDate   time.Time
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    []*nucleus.NucleusTradeHeaderModel
-		wantErr bool
-	}{
-		{
-			name: "test regular run",
-			fields: fields{
-				nucleusDb:         nucleusDb,
-				machineLearningDb: nil,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx:         context.TODO(),
-				lastRunTime: now,
-				tradeDate:   now,
-			},
-			want: []*nucleus.NucleusTradeHeaderModel{
-				{
-					DealKey:             16495,
-					DealType:            "HRSWPS",
-					TotalQuantity:       175200,
-					DnDirection:         "SALE",
-					TransactionDate:     parseTime("01-06-2022"),
-					CyCompanyKey:        10105,
-					Company:             "O&R",
-					CompanyLongName:     "ORANGE AND ROCKLAND UTILITIES INC",
-					CompanyCode:         "ORNGE&RCK",
-					LegalEntity:         "STRM",
-					LegalEntityLongName: "SHELL TRADING RISK MANAGEMENT LLC",
-					CyLegalEntityKey:    18726,
-					InteraffiliateFlag:  "N",
-					Contract:            "033-RM-FI-10415",
-					ConfirmFormat:       "HR SWAP",
-					Region:              "EAST",
-					HsHedgeKey:          "",
-					PrtPortfolio:        99482,
-					Portfolio:           "POWER_RM_CONSUMERS",
-					UrTrader:            "DMOLIN",
-					TzTimeZone:          "",
-					HasBroker:           "NO",
-					Broker:              "NA",
-					ExercisedOptionKey:  0,
-					StartDate:           parseTime("23-01-2023"),
-					EndDate:             parseTime("31-12-2023"),
-					CreatedBy:           "DMOLIN",
-					ModifiedBy:          "DMOLIN",
-					CreatedAt:           parseTime("01-06-2022"),
-					ModifiedAt:          parseTime("01-06-2022"),
-					ExecutionTime:       parseDateTime("06/01/2022", "09:10:00 AM"),
-					ExoticFlag:          "NA",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:          0,
-							Pool1:           "NYCAG",
-							Product1:        "STD 7x24",
-							HolidaySchedule: "NERC",
-							Volume:          -20,
-							BegDate:         parseTime("23-01-2023"),
-							EndDate:         parseTime("31-12-2023"),
-							Indexes1: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "NY DA LMP",
-									PubIndex:    "61758",
-								},
-							},
-							Indexes2: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "NYMEX",
-									PubIndex:    "NG",
-									Frequency:   "MONTHLY",
-								},
-								{
-									Publication: "NYMEX",
-									PubIndex:    "NG",
-									Frequency:   "MONTHLY",
-								},
-							},
-						},
-					},
-				},
-				{
-					DealKey:             16496,
-					DealType:            "HRSWPS",
-					TotalQuantity:       175200,
-					DnDirection:         "PURCHASE",
-					TransactionDate:     parseTime("01-06-2022"),
-					CyCompanyKey:        10430,
-					Company:             "SENA",
-					CompanyLongName:     "Shell Energy North America (US), L.P.",
-					CompanyCode:         "SENA",
-					LegalEntity:         "STRM",
-					LegalEntityLongName: "SHELL TRADING RISK MANAGEMENT LLC",
-					CyLegalEntityKey:    18726,
-					InteraffiliateFlag:  "Y",
-					Contract:            "033-RM-FI-20570",
-					ConfirmFormat:       "HR SWAP",
-					Region:              "EAST",
-					HsHedgeKey:          "",
-					PrtPortfolio:        99482,
-					Portfolio:           "POWER_RM_CONSUMERS",
-					UrTrader:            "DMOLIN",
-					TzTimeZone:          "",
-					HasBroker:           "NO",
-					Broker:              "NA",
-					ExercisedOptionKey:  0,
-					StartDate:           parseTime("23-01-2023"),
-					EndDate:             parseTime("31-12-2023"),
-					CreatedBy:           "DMOLIN",
-					ModifiedBy:          "DMOLIN",
-					CreatedAt:           parseTime("01-06-2022"),
-					ModifiedAt:          parseTime("01-06-2022"),
-					ExecutionTime:       parseDateTime("06/01/2022", "09:10:00 AM"),
-					ExoticFlag:          "NA",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:          0,
-							Pool1:           "NYCAG",
-							Product1:        "STD 7x24",
-							HolidaySchedule: "NERC",
-							Volume:          20,
-							BegDate:         parseTime("23-01-2023"),
-							EndDate:         parseTime("31-12-2023"),
-							Indexes1: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "NY DA LMP",
-									PubIndex:    "61758",
-								},
-							},
-							Indexes2: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "NYMEX",
-									PubIndex:    "NG",
-									Frequency:   "MONTHLY",
-								},
-								{
-									Publication: "NYMEX",
-									PubIndex:    "NG",
-									Frequency:   "MONTHLY",
-								},
-							},
-						},
-					},
-				},
-			},
-			wantErr: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			repo := &NucleusTradeRepository{
-				nucleusDb:         tt.fields.nucleusDb,
-				machineLearningDb: tt.fields.machineLearningDb,
-				logger:            tt.fields.logger,
-			}
-			got, err := repo.GetNucHeatRateSwapsDealList(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealList() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-
-			if len(got) != len(tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealList() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
-				return
-			}
-
-			for _, wantedValue := range tt.want {
-				exists := false
-				for _, value := range got {
-					if wantedValue.DealKey == value.DealKey {
-						exists = true
-						if len(value.Terms) != len(wantedValue.Terms) {
-							t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealList() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
-							return
-						}
-
-						for _, wantedTerm := range wantedValue.Terms {
-							termExists := false
-							for _, valueTerm := range value.Terms {
-								if wantedTerm.VolSeq == valueTerm.VolSeq {
-									termExists = true
-
-									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
-										t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealList() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes1 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes1 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
-										t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealList() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes2 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes2 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if !reflect.DeepEqual(wantedTerm, valueTerm) {
-										t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealList() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
-										return
-									}
-								}
-							}
-							if !termExists {
-								t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealList() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
-								return
-							}
-						}
-
-						if !reflect.DeepEqual(wantedValue, value) {
-							t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealList() wantedValue = %+v, value = %+v", wantedValue, value)
-							return
-						}
-					}
-				}
-				if !exists {
-					t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealList() wanted value does not exist %d", wantedValue.DealKey)
-					return
-				}
-			}
-
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealList() got = %+v, want = %+v", got, tt.want)
-				return
-			}
-		})
-	}
-}
-
-func TestNucleusTradeRepository_GetNucTCCFTRSDealList(t *testing.T) {
-	now := time.Now()
-	serverLogger := logger.GetServerLogger()
-	serverLogger.Enable(false)
-
-	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer nucleusDb.Close()
-
-	columns := []string{"DEAL_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
-		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY",
-		"LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT",
-		"REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE",
-		"HAS_BROKER", "BROKER", "DY_BEG_DAY", "DY_END_DAY", "SCH_SCHEDULE", "VOLUME",
-		"FIXED_PRICE", "PPEP_PP_POOL", "PPEP_PEP_PRODUCT", "PI_PB_PUBLICATION", "FRQ_FREQUENCY",
-		"POI_PI_PUB_INDEX", "POW_PI_PUB_INDEX", "CREATEDBY", "MODIFIEDBY", "CREATE_DATE",
-		"MODIFY_DATE"}
-	mock.ExpectQuery(getNucTCCFTRSDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("strDealType", "FTRSWP"), sql.Named("lastRunTime", now)).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			262325, "FTRSWP", "PURCHASE", parseTime("02-06-2022"),
-			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
-			"Shell Energy North America (US), L.P.", 10430, "", "FTRSWAP",
-			"SOUTH", "", 99860, "SP ERCOT CRR", "SPARK", "CPT",
-			"NO", "NA", parseTime("01-03-2023"), parseTime("31-03-2023"), "NERC", 100,
-			3.0205, "ERCRR", "STD ON", "ER DA LMP", "HOURLY",
-			"SOUTH_HUB", "LZ_LCRA", "PCI_GSMS", "PCI_GSMS", parseTime("02-06-2022"),
-			parseTime("02-06-2022"),
-		).AddRow(
-			262147, "FTRSWP", "PURCHASE", parseTime("02-06-2022"),
-			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
-			"Shell Energy North America (US), L.P.", 10430, "", "FTRSWAP",
-			"SOUTH", "", 99860, "SP ERCOT CRR", "SPARK", "CPT",
-			"NO", "NA", parseTime("01-01-2023"), parseTime("31-01-2023"), "NERC", 25,
-			4.10714, "ERCRR", "STD ON", "ER DA LMP", "HOURLY",
-			"SOUTH_HUB", "NORTH_HUB", "PCI_GSMS", "PCI_GSMS", parseTime("02-06-2022"),
-			parseTime("02-06-2022"),
-		))
-
-	type fields struct {
-		nucleusDb         *sql.DB
-		machineLearningDb *sql.DB
-		logger            logger.Logger
-	}
-	type args struct {
-		ctx         context.Context
-		lastRunTime time.Time
-		tradeDate   time.Time
-		strDealType string
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    []*nucleus.NucleusTradeHeaderModel
-		wantErr bool
-	}{
-		{
-			name: "regular run",
-			fields: fields{
-				nucleusDb:         nucleusDb,
-				machineLearningDb: nil,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx:         context.TODO(),
-				lastRunTime: now,
-				tradeDate:   now,
-				strDealType: "FTRSWP",
-			},
-			want: []*nucleus.NucleusTradeHeaderModel{
-				{
-					DealKey:             262325,
-					DealType:            "FTRSWP",
-					DnDirection:         "PURCHASE",
-					TransactionDate:     parseTime("02-06-2022"),
-					CyCompanyKey:        10171,
-					Company:             "ERCOT",
-					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
-					CompanyCode:         "ERCOT",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					Contract:            "",
-					ConfirmFormat:       "FTRSWAP",
-					Region:              "SOUTH",
-					HsHedgeKey:          "",
-					PrtPortfolio:        99860,
-					Portfolio:           "SP ERCOT CRR",
-					UrTrader:            "SPARK",
-					TzTimeZone:          "CPT",
-					HasBroker:           "NO",
-					Broker:              "NA",
-					CreatedBy:           "PCI_GSMS",
-					ModifiedBy:          "PCI_GSMS",
-					CreatedAt:           parseTime("02-06-2022"),
-					ModifiedAt:          parseTime("02-06-2022"),
-					ExoticFlag:          "NA",
-					InteraffiliateFlag:  "N",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:          0,
-							BegDate:         parseTime("01-03-2023"),
-							EndDate:         parseTime("31-03-2023"),
-							HolidaySchedule: "NERC",
-							Volume:          100,
-							FixedPrice:      3.0205,
-							Pool1:           "ERCRR",
-							Product1:        "STD ON",
-							Indexes1: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "ER DA LMP",
-									PubIndex:    "SOUTH_HUB",
-									Frequency:   "HOURLY",
-								},
-							},
-							Indexes2: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "ER DA LMP",
-									PubIndex:    "LZ_LCRA",
-									Frequency:   "HOURLY",
-								},
-							},
-						},
-					},
-				},
-				{
-					DealKey:             262147,
-					DealType:            "FTRSWP",
-					DnDirection:         "PURCHASE",
-					TransactionDate:     parseTime("02-06-2022"),
-					CyCompanyKey:        10171,
-					Company:             "ERCOT",
-					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
-					CompanyCode:         "ERCOT",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					Contract:            "",
-					ConfirmFormat:       "FTRSWAP",
-					Region:              "SOUTH",
-					HsHedgeKey:          "",
-					PrtPortfolio:        99860,
-					Portfolio:           "SP ERCOT CRR",
-					UrTrader:            "SPARK",
-					TzTimeZone:          "CPT",
-					HasBroker:           "NO",
-					Broker:              "NA",
-					CreatedBy:           "PCI_GSMS",
-					ModifiedBy:          "PCI_GSMS",
-					CreatedAt:           parseTime("02-06-2022"),
-					ModifiedAt:          parseTime("02-06-2022"),
-					ExoticFlag:          "NA",
-					InteraffiliateFlag:  "N",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:          0,
-							BegDate:         parseTime("01-01-2023"),
-							EndDate:         parseTime("31-01-2023"),
-							HolidaySchedule: "NERC",
-							Volume:          25,
-							FixedPrice:      4.10714,
-							Pool1:           "ERCRR",
-							Product1:        "STD ON",
-							Indexes1: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "ER DA LMP",
-									PubIndex:    "SOUTH_HUB",
-									Frequency:   "HOURLY",
-								},
-							},
-							Indexes2: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "ER DA LMP",
-									PubIndex:    "NORTH_HUB",
-									Frequency:   "HOURLY",
-								},
-							},
-						},
-					},
-				},
-			},
-			wantErr: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			repo := &NucleusTradeRepository{
-				nucleusDb:         tt.fields.nucleusDb,
-				machineLearningDb: tt.fields.machineLearningDb,
-				logger:            tt.fields.logger,
-			}
-			got, err := repo.GetNucTCCFTRSDealList(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate, tt.args.strDealType)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealList() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-
-			if len(got) != len(tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealList() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
-				return
-			}
-
-			for _, wantedValue := range tt.want {
-				exists := false
-				for _, value := range got {
-					if wantedValue.DealKey == value.DealKey {
-						exists = true
-						if len(value.Terms) != len(wantedValue.Terms) {
-							t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealList() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
-							return
-						}
-
-						for _, wantedTerm := range wantedValue.Terms {
-							termExists := false
-							for _, valueTerm := range value.Terms {
-								if wantedTerm.VolSeq == valueTerm.VolSeq {
-									termExists = true
-
-									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
-										t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealList() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes1 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes1 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
-										t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealList() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes2 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes2 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if !reflect.DeepEqual(wantedTerm, valueTerm) {
-										t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealList() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
-										return
-									}
-								}
-							}
-							if !termExists {
-								t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealList() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
-								return
-							}
-						}
-
-						if !reflect.DeepEqual(wantedValue, value) {
-							t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealList() wantedValue = %+v, value = %+v", wantedValue, value)
-							return
-						}
-					}
-				}
-				if !exists {
-					t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealList() wanted value does not exist %d", wantedValue.DealKey)
-					return
-				}
-			}
-
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealList() got = %+v, want = %+v", got, tt.want)
-				return
-			}
-		})
-	}
-}
-
-func TestNucleusTradeRepository_GetNucTransmissionDealList(t *testing.T) {
-	now := time.Now()
-	serverLogger := logger.GetServerLogger()
-	serverLogger.Enable(false)
-
-	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer nucleusDb.Close()
-
-	columns := []string{"TRANS_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
-		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY",
-		"LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT",
-		"REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE",
-		"HAS_BROKER", "BROKER", "CREATEDBY", "MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE",
-		"EXECUTION_TIME"}
-
-	mock.ExpectQuery(getNucTransmissionDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			323931, "TRANS", "PURCHASE", parseTime("02-06-2022"),
-			10133, "SMUD", "SACRAMENTO MUNICIPAL UTILITY DIST", "SMUD", "SENA",
-			"Shell Energy North America (US), L.P.", 10430, "011-KW-BI-03761", "TRANS",
-			"WEST", "N", 288, "SD - TRANS", "LHILER", "PPT",
-			"NO", "NA", "LHILER", "LHILER", parseTime("02-06-2022"), parseTime("02-06-2022"),
-			"01/01/1900",
-		).AddRow(
-			323934, "TRANS", "SALE", parseTime("02-06-2022"),
-			10363, "PEXC", "POWEREX CORP", "POWEREX", "SENA",
-			"Shell Energy North America (US), L.P.", 10430, "011-KW-BI-03761", "TRANS",
-			"WEST", "N", 288, "SD - TRANS", "LHILER", "PPT",
-			"NO", "NA", "LHILER", "LHILER", parseTime("02-06-2022"), parseTime("02-06-2022"),
-			"01/01/1900",
-		))
-
-	dealKeysArray := []int{323931, 323934}
-
-	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeysArray, []interface{}{}, "pv.td_trans_key")
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when creating the query", err)
-	}
-
-	getNucTransmissionDealTermListQ := getNucTransmissionDealTermListQuery(dealKeysQuery)
-
-	var namedParams []driver.Value
-	for _, value := range params {
-		valueNamed, _ := value.(sql.NamedArg)
-		namedParams = append(namedParams, valueNamed)
-	}
-
-	columns = []string{"TD_TRANS_KEY", "VOLUME_SEQ", "DY_BEG_DAY", "DY_END_DAY", "VOLUME",
-		"PPEP_PEP_PRODUCT", "PPEP_PP_FM_POOL", "CTP_FM_POINT_CODE", "PPEP_PP_TO_POOL",
-		"CTP_TO_POINT_CODE", "SCH_SCHEDULE"}
-
-	mock.ExpectQuery(getNucTransmissionDealTermListQ).WithArgs(namedParams...).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			323931, 0, parseTime("01-06-2022"), parseTime("01-06-2022"), 500,
-			"HOURLY", "MID-C", "MID-C", "MID-C",
-			"MID-C", "NERC",
-		).AddRow(
-			323931, 1, parseTime("01-06-2022"), parseTime("01-06-2022"), 414,
-			"HOURLY", "MID-C", "MID-C", "MID-C",
-			"MID-C", "NERC",
-		).AddRow(
-			323934, 0, parseTime("01-06-2022"), parseTime("01-06-2022"), 151,
-			"HOURLY", "MID-C", "MID-C", "MID-C",
-			"MID-C", "NERC",
-		))
-
-	type fields struct {
-		nucleusDb         *sql.DB
-		machineLearningDb *sql.DB
-		logger            logger.Logger
-	}
-	type args struct {
-		ctx         context.Context
-		lastRunTime time.Time
-		tradeDate   time.Time
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    []*nucleus.NucleusTradeHeaderModel
-		wantErr bool
-	}{
-		{
-			name: "regular run",
-			fields: fields{
-				nucleusDb:         nucleusDb,
-				machineLearningDb: nil,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx:         context.TODO(),
-				lastRunTime: now,
-				tradeDate:   now,
-			},
-			want: []*nucleus.NucleusTradeHeaderModel{
-				{
-					DealKey:             323931,
-					DealType:            "TRANS",
-					DnDirection:         "PURCHASE",
-					TransactionDate:     parseTime("02-06-2022"),
-					CyCompanyKey:        10133,
-					Company:             "SMUD",
-					CompanyLongName:     "SACRAMENTO MUNICIPAL UTILITY DIST",
-					CompanyCode:         "SMUD",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					Contract:            "011-KW-BI-03761",
-					ConfirmFormat:       "TRANS",
-					Region:              "WEST",
-					HsHedgeKey:          "N",
-					PrtPortfolio:        288,
-					Portfolio:           "SD - TRANS",
-					UrTrader:            "LHILER",
-					TzTimeZone:          "PPT",
-					HasBroker:           "NO",
-					Broker:              "NA",
-					CreatedBy:           "LHILER",
-					ModifiedBy:          "LHILER",
-					CreatedAt:           parseTime("02-06-2022"),
-					ModifiedAt:          parseTime("02-06-2022"),
-					ExoticFlag:          "NA",
-					InteraffiliateFlag:  "N",
-					ExecutionTime:       parseTime("01-01-1900"),
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:          0,
-							BegDate:         parseTime("01-06-2022"),
-							EndDate:         parseTime("01-06-2022"),
-							Volume:          500,
-							Product1:        "HOURLY",
-							Pool1:           "MID-C",
-							PointCode1:      "MID-C",
-							Pool2:           "MID-C",
-							PointCode2:      "MID-C",
-							HolidaySchedule: "NERC",
-							PriceType:       "F",
-						},
-						{
-							VolSeq:          1,
-							BegDate:         parseTime("01-06-2022"),
-							EndDate:         parseTime("01-06-2022"),
-							Volume:          414,
-							Product1:        "HOURLY",
-							Pool1:           "MID-C",
-							PointCode1:      "MID-C",
-							Pool2:           "MID-C",
-							PointCode2:      "MID-C",
-							HolidaySchedule: "NERC",
-							PriceType:       "F",
-						},
-					},
-				},
-				{
-					DealKey:             323934,
-					DealType:            "TRANS",
-					DnDirection:         "SALE",
-					TransactionDate:     parseTime("02-06-2022"),
-					CyCompanyKey:        10363,
-					Company:             "PEXC",
-					CompanyLongName:     "POWEREX CORP",
-					CompanyCode:         "POWEREX",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					Contract:            "011-KW-BI-03761",
-					ConfirmFormat:       "TRANS",
-					Region:              "WEST",
-					HsHedgeKey:          "N",
-					PrtPortfolio:        288,
-					Portfolio:           "SD - TRANS",
-					UrTrader:            "LHILER",
-					TzTimeZone:          "PPT",
-					HasBroker:           "NO",
-					Broker:              "NA",
-					CreatedBy:           "LHILER",
-					ModifiedBy:          "LHILER",
-					CreatedAt:           parseTime("02-06-2022"),
-					ModifiedAt:          parseTime("02-06-2022"),
-					ExoticFlag:          "NA",
-					InteraffiliateFlag:  "N",
-					ExecutionTime:       parseTime("01-01-1900"),
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:          0,
-							BegDate:         parseTime("01-06-2022"),
-							EndDate:         parseTime("01-06-2022"),
-							Volume:          151,
-							Product1:        "HOURLY",
-							Pool1:           "MID-C",
-							PointCode1:      "MID-C",
-							Pool2:           "MID-C",
-							PointCode2:      "MID-C",
-							HolidaySchedule: "NERC",
-							PriceType:       "F",
-						},
-					},
-				},
-			},
-			wantErr: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			repo := &NucleusTradeRepository{
-				nucleusDb:         tt.fields.nucleusDb,
-				machineLearningDb: tt.fields.machineLearningDb,
-				logger:            tt.fields.logger,
-			}
-			got, err := repo.GetNucTransmissionDealList(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("NucleusTradeRepository.GetNucTransmissionDealList() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-
-			if len(got) != len(tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucTransmissionDealList() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
-				return
-			}
-
-			for _, wantedValue := range tt.want {
-				exists := false
-				for _, value := range got {
-					if wantedValue.DealKey == value.DealKey {
-						exists = true
-						if len(value.Terms) != len(wantedValue.Terms) {
-							t.Errorf("NucleusTradeRepository.GetNucTransmissionDealList() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
-							return
-						}
-
-						for _, wantedTerm := range wantedValue.Terms {
-							termExists := false
-							for _, valueTerm := range value.Terms {
-								if wantedTerm.VolSeq == valueTerm.VolSeq {
-									termExists = true
-
-									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
-										t.Errorf("NucleusTradeRepository.GetNucTransmissionDealList() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes1 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes1 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucTransmissionDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
-										t.Errorf("NucleusTradeRepository.GetNucTransmissionDealList() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes2 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes2 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucTransmissionDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if !reflect.DeepEqual(wantedTerm, valueTerm) {
-										t.Errorf("NucleusTradeRepository.GetNucTransmissionDealList() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
-										return
-									}
-								}
-							}
-							if !termExists {
-								t.Errorf("NucleusTradeRepository.GetNucTransmissionDealList() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
-								return
-							}
-						}
-
-						if !reflect.DeepEqual(wantedValue, value) {
-							t.Errorf("NucleusTradeRepository.GetNucTransmissionDealList() wantedValue = %+v, value = %+v", wantedValue, value)
-							return
-						}
-					}
-				}
-				if !exists {
-					t.Errorf("NucleusTradeRepository.GetNucTransmissionDealList() wanted value does not exist %d", wantedValue.DealKey)
-					return
-				}
-			}
-
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucTransmissionDealList() got = %+v, want = %+v", got, tt.want)
-				return
-			}
-		})
-	}
-}
-
-func TestNucleusTradeRepository_GetNucMiscChargeDealList(t *testing.T) {
-	now := time.Now()
-	serverLogger := logger.GetServerLogger()
-	serverLogger.Enable(false)
-
-	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer nucleusDb.Close()
-
-	columns := []string{"MISC_CHARGE_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
-		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY", "LEGALENTITYLONGNAME",
-		"CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY",
-		"PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE", "HAS_BROKER", "BROKER",
-		"CREATEDBY", "MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE"}
-
-	mock.ExpectQuery(getNucMiscChargeDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			2854193, "MISC", "Payable", parseTime("02-06-2022"),
-			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA", "Shell Energy North America (US), L.P.",
-			10430, "013-KW-SA-20161", "", "", "",
-			255, "CEM ODD LOT", "PCI_ALLO", "", "", "",
-			"PCI_GSMS", "PCI_GSMS", parseTime("02-06-2022"), parseTime("02-06-2022"),
-		).AddRow(
-			2854183, "MISC", "Receivable", parseTime("02-06-2022"),
-			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA", "Shell Energy North America (US), L.P.",
-			10430, "013-KW-SA-20161", "", "", "",
-			255, "CEM ODD LOT", "PCI_ALLO", "", "", "",
-			"PCI_GSMS", "PCI_GSMS", parseTime("02-06-2022"), parseTime("02-06-2022"),
-		))
-
-	dealKeysArray := []int{2854193, 2854183}
-
-	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeysArray, []interface{}{}, "pv.mc_misc_charge_key")
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when creating the query", err)
-	}
-
-	getNucMiscChargeDealTermListQ := getNucMiscChargeDealTermListQuery(dealKeysQuery)
-
-	var namedParams []driver.Value
-	for _, value := range params {
-		valueNamed, _ := value.(sql.NamedArg)
-		namedParams = append(namedParams, valueNamed)
-	}
-
-	columns = []string{"MC_MISC_CHARGE_KEY", "MISC_VOL_SEQ", "DY_BEG_DAY", "DY_END_DAY", "INT_VOLUME"}
-
-	mock.ExpectQuery(getNucMiscChargeDealTermListQ).WithArgs(namedParams...).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			2854183, 0, parseTime("04-04-2022"), parseTime("04-04-2022"), 0,
-		).AddRow(
-			2854193, 0, parseTime("25-05-2022"), parseTime("25-05-2022"), 0,
-		))
-
-	type fields struct {
-		nucleusDb         *sql.DB
-		machineLearningDb *sql.DB
-		logger            logger.Logger
-	}
-	type args struct {
-		ctx         context.Context
-		lastRunTime time.Time
-		tradeDate   time.Time
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    []*nucleus.NucleusTradeHeaderModel
-		wantErr bool
-	}{
-		{
-			name: "regular run",
-			fields: fields{
-				nucleusDb:         nucleusDb,
-				machineLearningDb: nil,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx:         context.TODO(),
-				lastRunTime: now,
-				tradeDate:   now,
-			},
-			want: []*nucleus.NucleusTradeHeaderModel{
-				{
-					DealKey:             2854193,
-					DealType:            "MISC",
-					DnDirection:         "Payable",
-					TransactionDate:     parseTime("02-06-2022"),
-					CyCompanyKey:        10171,
-					Company:             "ERCOT",
-					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
-					CompanyCode:         "ERCOT",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					Contract:            "013-KW-SA-20161",
-					ConfirmFormat:       "",
-					Region:              "",
-					HsHedgeKey:          "",
-					PrtPortfolio:        255,
-					Portfolio:           "CEM ODD LOT",
-					UrTrader:            "PCI_ALLO",
-					TzTimeZone:          "",
-					HasBroker:           "",
-					Broker:              "",
-					CreatedBy:           "PCI_GSMS",
-					ModifiedBy:          "PCI_GSMS",
-					CreatedAt:           parseTime("02-06-2022"),
-					ModifiedAt:          parseTime("02-06-2022"),
-					InteraffiliateFlag:  "N",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:  0,
-							BegDate: parseTime("25-05-2022"),
-							EndDate: parseTime("25-05-2022"),
-							Volume:  0,
-						},
-					},
-				},
-				{
-					DealKey:             2854183,
-					DealType:            "MISC",
-					DnDirection:         "Receivable",
-					TransactionDate:     parseTime("02-06-2022"),
-					CyCompanyKey:        10171,
-					Company:             "ERCOT",
-					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
-					CompanyCode:         "ERCOT",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					Contract:            "013-KW-SA-20161",
-					ConfirmFormat:       "",
-					Region:              "",
-					HsHedgeKey:          "",
-					PrtPortfolio:        255,
-					Portfolio:           "CEM ODD LOT",
-					UrTrader:            "PCI_ALLO",
-					TzTimeZone:          "",
-					HasBroker:           "",
-					Broker:              "",
-					CreatedBy:           "PCI_GSMS",
-					ModifiedBy:          "PCI_GSMS",
-					CreatedAt:           parseTime("02-06-2022"),
-					ModifiedAt:          parseTime("02-06-2022"),
-					InteraffiliateFlag:  "N",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:  0,
-							BegDate: parseTime("04-04-2022"),
-							EndDate: parseTime("04-04-2022"),
-							Volume:  0,
-						},
-					},
-				},
-			},
-			wantErr: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			repo := &NucleusTradeRepository{
-				nucleusDb:         tt.fields.nucleusDb,
-				machineLearningDb: tt.fields.machineLearningDb,
-				logger:            tt.fields.logger,
-			}
-			got, err := repo.GetNucMiscChargeDealList(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealList() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-
-			if len(got) != len(tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealList() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
-				return
-			}
-
-			for _, wantedValue := range tt.want {
-				exists := false
-				for _, value := range got {
-					if wantedValue.DealKey == value.DealKey {
-						exists = true
-						if len(value.Terms) != len(wantedValue.Terms) {
-							t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealList() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
-							return
-						}
-
-						for _, wantedTerm := range wantedValue.Terms {
-							termExists := false
-							for _, valueTerm := range value.Terms {
-								if wantedTerm.VolSeq == valueTerm.VolSeq {
-									termExists = true
-
-									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
-										t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealList() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes1 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes1 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
-										t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealList() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes2 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes2 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if !reflect.DeepEqual(wantedTerm, valueTerm) {
-										t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealList() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
-										return
-									}
-								}
-							}
-							if !termExists {
-								t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealList() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
-								return
-							}
-						}
-
-						if !reflect.DeepEqual(wantedValue, value) {
-							t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealList() wantedValue = %+v, value = %+v", wantedValue, value)
-							return
-						}
-					}
-				}
-				if !exists {
-					t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealList() wanted value does not exist %d", wantedValue.DealKey)
-					return
-				}
-			}
-
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealList() got = %+v, want = %+v", got, tt.want)
-				return
-			}
-		})
-	}
-}
-
-func TestNucleusTradeRepository_GetLastExtractionRun(t *testing.T) {
-	now := time.Now()
-	serverLogger := logger.GetServerLogger()
-	serverLogger.Enable(false)
-
-	machineLearningDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer machineLearningDb.Close()
-
-	columns := []string{"ExtractionRunId", "TransactionDate", "DealType", "TimeParameter", "CreatedAt"}
-	mock.ExpectQuery(getLastExtractionRunQuery).WithArgs(sql.Named("transactionDate", now), sql.Named("dealType", "POWER")).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			461,
-			parseTime("01-12-2022"),
-			"POWER",
-			parseTimeLayout("2006-01-02 15:04:05.000", "2022-01-12 00:00:00.000"),
-			parseTimeLayout("2006-01-02 15:04:05.000", "2022-01-13 17:57:14.850"),
-		))
-
-	mock.ExpectQuery(getLastExtractionRunQuery).WithArgs(sql.Named("transactionDate", parseTime("12-12-2030")), sql.Named("dealType", "ALL")).
-		WillReturnRows(sqlmock.NewRows(columns))
-
-	type fields struct {
-		nucleusDb         *sql.DB
-		machineLearningDb *sql.DB
-		logger            logger.Logger
-	}
-	type args struct {
-		ctx       context.Context
-		tradeDate time.Time
-		dealType  string
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    *nucleus.NucleusTradeExtractionRunModel
-		wantErr bool
-	}{
-		{
-			name: "regular run",
-			fields: fields{
-				nucleusDb:         nil,
-				machineLearningDb: machineLearningDb,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx:       context.TODO(),
-				tradeDate: now,
-				dealType:  "POWER",
-			},
-			want: &nucleus.NucleusTradeExtractionRunModel{
-				ExtractionRunId: 461,
-				TransactionDate: parseTime("01-12-2022"),
-				DealType:        "POWER",
-				TimeParameter:   parseTimeLayout("2006-01-02 15:04:05.000", "2022-01-12 00:00:00.000"),
-				CreatedAt:       parseTimeLayout("2006-01-02 15:04:05.000", "2022-01-13 17:57:14.850"),
-			},
-			wantErr: false,
-		},
-		{
-			name: "no rows in result set",
-			fields: fields{
-				nucleusDb:         nil,
-				machineLearningDb: machineLearningDb,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx:       context.TODO(),
-				tradeDate: parseTime("12-12-2030"),
-				dealType:  "ALL",
-			},
-			want:    nil,
-			wantErr: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			repo := &NucleusTradeRepository{
-				nucleusDb:         tt.fields.nucleusDb,
-				machineLearningDb: tt.fields.machineLearningDb,
-				logger:            tt.fields.logger,
-			}
-			got, err := repo.GetLastExtractionRun(tt.args.ctx, tt.args.tradeDate, tt.args.dealType)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("NucleusTradeRepository.GetLastExtractionRun() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("NucleusTradeRepository.GetLastExtractionRun() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-type processedTradeExample struct {
-	*common.ResultModelBasePayload
-	Company  string
-	Trader   string
-	Source   string
-	Location string
-	Broker   string
-}
-
-type mockNucleusProcessedTradeConverter struct{}
-
-func (converter *mockNucleusProcessedTradeConverter) ConvertValue(raw interface{}) (driver.Value, error) {
-	switch inner := raw.(type) {
-	case string:
-		return raw.(string), nil
-	case mssql.TVP:
-		if inner.TypeName != "NucleusProcessedTradeType" {
-			return nil, fmt.Errorf("invalid type")
-		}
-		return "PASSED", nil
-	}
-
-	return nil, fmt.Errorf("invalid type")
-}
-
-func TestNucleusTradeRepository_ProcessTrades(t *testing.T) {
-	serverLogger := logger.GetServerLogger()
-	serverLogger.Enable(false)
-
-	machineLearningDb, mock, err := sqlmock.New(sqlmock.ValueConverterOption(&mockNucleusProcessedTradeConverter{}))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer machineLearningDb.Close()
-
-	anomalyMessages := make(map[int][]common.IModelBasePayload)
-	anomalyMessages[14695753] = []common.IModelBasePayload{
-		processedTradeExample{
-			Company:  "NGX G_LGL",
-			Trader:   "sharper",
-			Source:   "ECOM_ICE|ICE_FUT",
-			Location: "47288|HUNTINGDON",
-			Broker:   "INTERXCHG_BKR",
-			ResultModelBasePayload: &common.ResultModelBasePayload{
-				DealKey:     14695753,
-				DealType:    "COMM-PHYS",
-				ModelName:   "ModelName",
-				Message:     "Message",
-				ScoredLabel: "NO",
-			},
-		},
-		processedTradeExample{
-			Company:  "NGX G_LGL",
-			Trader:   "sharper",
-			Source:   "ECOM_ICE|ICE_FUT",
-			Location: "47288|HUNTINGDON",
-			Broker:   "INTERXCHG_BKR",
-			ResultModelBasePayload: &common.ResultModelBasePayload{
-				DealKey:     14695753,
-				DealType:    "COMM-PHYS",
-				ModelName:   "ModelName",
-				Message:     "Message",
-				ScoredLabel: "YES",
-			},
-		},
-	}
-
-	var nucleusProcessedTradeTypeData []*nucleusProcessedTradeType
-
-	trades := []*nucleus.NucleusTradeHeaderModel{
-		{
-			DealKey:             3995652,
-			DealType:            "PWRNSD",
-			TotalQuantity:       0,
-			TransactionDate:     parseTime("02-05-2022"),
-			DnDirection:         "PURCHASE",
-			CyCompanyKey:        10171,
-			CompanyCode:         "ERCOT",
-			Company:             "ERCOT",
-			CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
-			PrtPortfolio:        99817,
-			Portfolio:           "TEJAS GEN FRIENDSWOOD",
-			UrTrader:            "BSILVA",
-			CyBrokerKey:         0,
-			Broker:              "NA",
-			TzTimeZone:          "CPT",
-			HasBroker:           "NO",
-			ConfirmFormat:       "STL PWRNSD",
-			CyLegalEntityKey:    21014,
-			LegalEntity:         "TEJ PWR GN",
-			LegalEntityLongName: "TEJAS POWER GENERATION LLC",
-			Region:              "SOUTH",
-			Terms: []*nucleus.NucleusTradeTermModel{
-				{
-					VolSeq:          0,
-					BegDate:         parseTime("03-05-2022"),
-					EndDate:         parseTime("03-05-2022"),
-					Pool1:           "ERHOU",
-					Product1:        "HOURLY",
-					PointCode1:      "HB_HOUSTON",
-					HolidaySchedule: "NONE",
-					Formula1:        "([ER RT LMP|HOUSTON_HUB_AVG|HOURLY])<CU>USD</CU><UT>MW</UT>",
-					Indexes1: []*nucleus.NucleusTradeIndexModel{
-						{
-							Publication: "ER RT LMP",
-							PubIndex:    "HOUSTON_HUB_AVG",
-							Frequency:   "HOURLY",
-						},
-					},
-					PriceType: "I",
-				},
-			},
-			AnomalyTestResult:  "",
-			CreatedAt:          parseTime("02-05-2022"),
-			ModifiedAt:         parseDateTime("2022-05-18", "07:53:32 AM"),
-			CreatedBy:          "CAGUILAR",
-			ModifiedBy:         "JCHOI",
-			ExoticFlag:         "NA",
-			InteraffiliateFlag: "N",
-		},
-	}
-
-	for _, trade := range trades {
-		var anomalyMsg, modelParams string
-
-		if resultList, ok := anomalyMessages[trade.DealKey]; ok {
-			for _, result := range resultList {
-				if result.GetScoredLabel() == "NO" {
-					if anomalyMsg == "" {
-						anomalyMsg = result.GetModelName() + " " + result.GetMessage()
-					} else {
-						anomalyMsg = anomalyMsg + ";" + result.GetModelName() + " " + result.GetMessage()
-					}
-
-					vParams, err := json.Marshal(result)
-					if err != nil {
-						t.Fatalf("an error '%v' was not expected when marshalling json", err)
-					}
-
-					if modelParams == "" {
-						modelParams = string(vParams)
-					} else {
-						modelParams = modelParams + ";" + string(vParams)
-					}
-				}
-			}
-		}
-
-		marshalledTrade, err := json.Marshal(trade)
-		if err != nil {
-			t.Fatalf("an error '%v' was not expected when marshalling json", err)
-		}
-
-		nucleusProcessedTrade := &nucleusProcessedTradeType{
-			TradeId:             int64(trade.DealKey),
-			DealType:            trade.DealType,
-			PortfolioId:         int64(trade.PrtPortfolio),
-			TransactionDate:     trade.TransactionDate,
-			TradeDetail:         string(marshalledTrade),
-			AnomalyDetectedFlag: false,
-			AnomalyTestResult: sql.NullString{
-				Valid: false,
-			},
-			ModelParameters: sql.NullString{
-				Valid: false,
-			},
-		}
-
-		if anomalyMsg != "" {
-			nucleusProcessedTrade.AnomalyDetectedFlag = true
-			nucleusProcessedTrade.AnomalyTestResult = sql.NullString{
-				String: anomalyMsg,
-				Valid:  true,
-			}
-		}
-
-		if modelParams != "" {
-			nucleusProcessedTrade.AnomalyTestResult = sql.NullString{
-				String: modelParams,
-				Valid:  true,
-			}
-		}
-
-		nucleusProcessedTradeTypeData = append(nucleusProcessedTradeTypeData, nucleusProcessedTrade)
-	}
-
-	tvpType := mssql.TVP{
-		TypeName: "NucleusProcessedTradeType",
-		Value:    nucleusProcessedTradeTypeData,
-	}
-
-	mock.ExpectExec(execProcessTradesQuery).WithArgs(sql.Named("TVP", tvpType)).WillReturnResult(driver.ResultNoRows)
-
-	type fields struct {
-		nucleusDb         *sql.DB
-		machineLearningDb *sql.DB
-		logger            logger.Logger
-	}
-	type args struct {
-		ctx             context.Context
-		trades          []*nucleus.NucleusTradeHeaderModel
-		anomalyMessages map[int][]common.IModelBasePayload
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		wantErr bool
-	}{
-		{
-			name: "normal run",
-			fields: fields{
-				nucleusDb:         nil,
-				machineLearningDb: machineLearningDb,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx:             context.TODO(),
-				trades:          trades,
-				anomalyMessages: anomalyMessages,
-			},
-			wantErr: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			repo := &NucleusTradeRepository{
-				nucleusDb:         tt.fields.nucleusDb,
-				machineLearningDb: tt.fields.machineLearningDb,
-				logger:            tt.fields.logger,
-			}
-			if err := repo.ProcessTrades(tt.args.ctx, tt.args.trades, tt.args.anomalyMessages); (err != nil) != tt.wantErr {
-				t.Errorf("NucleusTradeRepository.ProcessTrades() error = %v, wantErr %v", err, tt.wantErr)
-			}
-		})
-	}
-}
-
-func TestNucleusTradeRepository_InsertExtractionRun(t *testing.T) {
-	now := time.Now()
-	serverLogger := logger.GetServerLogger()
-	serverLogger.Enable(false)
-
-	machineLearningDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer machineLearningDb.Close()
-
-	mock.ExpectExec(insertExtractionRunQuery).WithArgs(sql.Named("transactionDate", now), sql.Named("dealType", "FTRSWP"), sql.Named("timeParameter", now)).
-		WillReturnResult(sqlmock.NewResult(1, 1))
-
-	type fields struct {
-		nucleusDb         *sql.DB
-		machineLearningDb *sql.DB
-		logger            logger.Logger
-	}
-	type args struct {
-		ctx       context.Context
-		tradeDate time.Time
-		lastRun   time.Time
-		dealType  string
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		wantErr bool
-	}{
-		{
-			name: "test regular run",
-			fields: fields{
-				nucleusDb:         nil,
-				machineLearningDb: machineLearningDb,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx:       context.TODO(),
-				tradeDate: now,
-				lastRun:   now,
-				dealType:  "FTRSWP",
-			},
-			wantErr: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			repo := &NucleusTradeRepository{
-				nucleusDb:         tt.fields.nucleusDb,
-				machineLearningDb: tt.fields.machineLearningDb,
-				logger:            tt.fields.logger,
-			}
-			if err := repo.InsertExtractionRun(tt.args.ctx, tt.args.tradeDate, tt.args.lastRun, tt.args.dealType); (err != nil) != tt.wantErr {
-				t.Errorf("NucleusTradeRepository.InsertExtractionRun() error = %v, wantErr %v", err, tt.wantErr)
-			}
-		})
-	}
-}
-
-func TestNucleusTradeRepository_GetPortfolioRiskMappingList(t *testing.T) {
-	serverLogger := logger.GetServerLogger()
-	serverLogger.Enable(false)
-
-	machineLearningDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer machineLearningDb.Close()
-
-	machineLearningDbEmpty, mockEmpty, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer machineLearningDb.Close()
-
-	machineLearningDbError, mockError, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer machineLearningDb.Close()
-
-	columns := []string{"SourceSystem", "Portfolio", "LegalEntity"}
-
-	mock.ExpectQuery(getPortfolioRiskMappingListQuery).WillReturnRows(sqlmock.NewRows(columns).AddRow(
-		"NUCLEUS", "LOCKPORT_TOLL", nil,
-	).AddRow(
-		"NUCLEUS", "COMMITMENT FEE INTEREST", "STRM",
-	))
-
-	mockEmpty.ExpectQuery(getPortfolioRiskMappingListQuery).WillReturnRows(sqlmock.NewRows(columns))
-
-	mockError.ExpectQuery(getPortfolioRiskMappingListQuery).WillReturnError(fmt.Errorf("error"))
-
-	type fields struct {
-		nucleusDb         *sql.DB
-		machineLearningDb *sql.DB
-		logger            logger.Logger
-	}
-	type args struct {
-		ctx context.Context
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    []*nucleus.PortfolioRiskMappingModel
-		wantErr bool
-	}{
-		{
-			name: "regular run",
-			fields: fields{
-				nucleusDb:         nil,
-				machineLearningDb: machineLearningDb,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx: context.TODO(),
-			},
-			want: []*nucleus.PortfolioRiskMappingModel{
-				{
-					SourceSystem: "NUCLEUS",
-					Portfolio:    "LOCKPORT_TOLL",
-					LegalEntity:  "",
-				},
-				{
-					SourceSystem: "NUCLEUS",
-					Portfolio:    "COMMITMENT FEE INTEREST",
-					LegalEntity:  "STRM",
-				},
-			},
-			wantErr: false,
-		},
-		{
-			name: "no rows in result set",
-			fields: fields{
-				nucleusDb:         nil,
-				machineLearningDb: machineLearningDbEmpty,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx: context.TODO(),
-			},
-			want:    nil,
-			wantErr: false,
-		},
-		{
-			name: "error in result set",
-			fields: fields{
-				nucleusDb:         nil,
-				machineLearningDb: machineLearningDbError,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx: context.TODO(),
-			},
-			want:    nil,
-			wantErr: true,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			repo := &NucleusTradeRepository{
-				nucleusDb:         tt.fields.nucleusDb,
-				machineLearningDb: tt.fields.machineLearningDb,
-				logger:            tt.fields.logger,
-			}
-			got, err := repo.GetPortfolioRiskMappingList(tt.args.ctx)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("NucleusTradeRepository.GetPortfolioRiskMappingList() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("NucleusTradeRepository.GetPortfolioRiskMappingList() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-func TestNucleusTradeRepository_GetLarBaselist(t *testing.T) {
-	serverLogger := logger.GetServerLogger()
-	serverLogger.Enable(false)
-
-	machineLearningDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer machineLearningDb.Close()
-
-	machineLearningDbEmpty, mockEmpty, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer machineLearningDb.Close()
-
-	machineLearningDbError, mockError, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer machineLearningDb.Close()
-
-	columns := []string{"ShortName", "CounterpartyLongName", "ParentCompany", "Product",
-		"SourceSystem", "DealType", "NettingAgreement", "AgreementTypePerCSA", "OurThreshold",
-		"CounterpartyThreshold", "BuyTenor", "SellTenor", "GrossExposure", "Collateral",
-		"NetPosition", "LimitValue", "LimitCurrency", "LimitAvailability", "ExposureLimit",
-		"ExpirationDate", "MarketType", "IndustryCode", "SPRating", "MoodyRating",
-		"FinalInternalRating", "FinalRating", "Equifax", "AmendedBy", "EffectiveDate",
-		"ReviewDate", "DoddFrankClassification", "ReportCreatedDate", "Boost", "tradingEntity",
-		"LegalEntity", "Agmt", "CSA", "Tenor", "Limit", "ReportingDate", "CreatedAt"}
-
-	mock.ExpectQuery(getLarBaselistQuery).WillReturnRows(sqlmock.NewRows(columns).AddRow(
-		"ONT POWER", "ONTARIO POWER GENERATION INC", "ONTARIO POWER GENERATION INC", "SENA-Power",
-		"NUCLEUS", "CAPCTY", "011-KW-BI-03761", "WSPP with Netting", 0,
-		10, "Halt Trading -KYC", "Halt Trading -KYC", 0.00, 0,
-		0, 0, "USD", "HALT TRADING - KYC", "",
-		parseTimeLayout("2006-01-02 15:04:05.000", "2099-12-31 00:00:00.000"), "Wholesale", "55101010 - Electric Utilities", "BBB+", "Not Rated",
-		"BBB+", "BBB+", " ", "Icko Moster", parseTimeLayout("2006-01-02 15:04:05.000", "2020-10-15 00:00:00.000"),
-		parseTimeLayout("2006-01-02 15:04:05.000", "2099-12-31 00:00:00.000"), "No Classification", parseTimeLayout("2006-01-02 15:04:05.000", "2021-10-20 13:30:15.143"), "FALSE", "SHELL ENERGY NORTH AMERICA (US) LP",
-		"Shell Energy North America (US), L.P.", "X", "X", "X", "X", parseTime("20-10-2021"), parseTimeLayout("2006-01-02 15:04:05.000", "2021-10-20 13:22:25.910"),
-	).AddRow(
-		"OPSI", "OCCIDENTAL POWER SERVICES INC", "OCCIDENTAL PETROLEUM CORPORATION", "SENA-Power",
-		"NUCLEUS", "POWER", "011-KW-BI-03761", "WSPP with Netting", 10,
-		10, "CALL CREDIT", "CALL CREDIT", 0.00, 0,
-		0, 0, "USD", "CALL CREDIT", "HIGH",
-		parseTimeLayout("2006-01-02 15:04:05.000", "2022-09-30 00:00:00.000"), "Wholesale", "40203020 - Investment Banking and Brokerage", "Not Rated", "Not Rated",
-		"CCC+", "CCC+", " ", "Sarah Marissa Jynnyl Vista", parseTimeLayout("2006-01-02 15:04:05.000", "2021-10-06 00:00:00.000"),
-		parseTimeLayout("2006-01-02 15:04:05.000", "2022-08-30 00:00:00.000"), "No Classification", parseTimeLayout("2006-01-02 15:04:05.000", "2021-10-20 13:30:15.143"), "FALSE", "SHELL ENERGY NORTH AMERICA (US) LP",
-		"Shell Energy North America (US), L.P.", "X", "X", "X", "X", parseTime("20-10-2021"), parseTimeLayout("2006-01-02 15:04:05.000", "2021-10-20 13:22:25.910"),
-	))
-
-	mockEmpty.ExpectQuery(getLarBaselistQuery).WillReturnRows(sqlmock.NewRows(columns))
-
-	mockError.ExpectQuery(getLarBaselistQuery).WillReturnError(fmt.Errorf("error"))
-
-	type fields struct {
-		nucleusDb         *sql.DB
-		machineLearningDb *sql.DB
-		logger            logger.Logger
-	}
-	type args struct {
-		ctx context.Context
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    []*common.LarBaseModel
-		wantErr bool
-	}{
-		{
-			name: "regular run",
-			fields: fields{
-				nucleusDb:         nil,
-				machineLearningDb: machineLearningDb,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx: context.TODO(),
-			},
-			want: []*common.LarBaseModel{
-				{
-					ShortName:               "ONT POWER",
-					CounterpartyLongName:    "ONTARIO POWER GENERATION INC",
-					ParentCompany:           "ONTARIO POWER GENERATION INC",
-					Product:                 "SENA-Power",
-					SourceSystem:            "NUCLEUS",
-					DealType:                "CAPCTY",
-					NettingAgreement:        "011-KW-BI-03761",
-					AgreementTypePerCSA:     "WSPP with Netting",
-					OurThreshold:            0,
-					CounterpartyThreshold:   10,
-					BuyTenor:                "Halt Trading -KYC",
-					SellTenor:               "Halt Trading -KYC",
-					GrossExposure:           0.00,
-					Collateral:              0,
-					NetPosition:             0,
-					LimitValue:              0,
-					LimitCurrency:           "USD",
-					LimitAvailability:       "HALT TRADING - KYC",
-					ExposureLimit:           "",
-					ExpirationDate:          parseTimeLayout("2006-01-02 15:04:05.000", "2099-12-31 00:00:00.000"),
-					MarketType:              "Wholesale",
-					IndustryCode:            "55101010 - Electric Utilities",
-					SPRating:                "BBB+",
-					MoodyRating:             "Not Rated",
-					FinalInternalRating:     "BBB+",
-					FinalRating:             "BBB+",
-					Equifax:                 " ",
-					AmendedBy:               "Icko Moster",
-					EffectiveDate:           parseTimeLayout("2006-01-02 15:04:05.000", "2020-10-15 00:00:00.000"),
-					ReviewDate:              parseTimeLayout("2006-01-02 15:04:05.000", "2099-12-31 00:00:00.000"),
-					DoddFrankClassification: "No Classification",
-					ReportCreatedDate:       parseTimeLayout("2006-01-02 15:04:05.000", "2021-10-20 13:30:15.143"),
-					Boost:                   "FALSE",
-					TradingEntity:           "SHELL ENERGY NORTH AMERICA (US) LP",
-					LegalEntity:             "Shell Energy North America (US), L.P.",
-					Agmt:                    "X",
-					CSA:                     "X",
-					Tenor:                   "X",
-					Limit:                   "X",
-					ReportingDate:           parseTime("20-10-2021"),
-					CreatedAt:               parseTimeLayout("2006-01-02 15:04:05.000", "2021-10-20 13:22:25.910"),
-				},
-				{
-					ShortName:               "OPSI",
-					CounterpartyLongName:    "OCCIDENTAL POWER SERVICES INC",
-					ParentCompany:           "OCCIDENTAL PETROLEUM CORPORATION",
-					Product:                 "SENA-Power",
-					SourceSystem:            "NUCLEUS",
-					DealType:                "POWER",
-					NettingAgreement:        "011-KW-BI-03761",
-					AgreementTypePerCSA:     "WSPP with Netting",
-					OurThreshold:            10,
-					CounterpartyThreshold:   10,
-					BuyTenor:                "CALL CREDIT",
-					SellTenor:               "CALL CREDIT",
-					GrossExposure:           0.00,
-					Collateral:              0,
-					NetPosition:             0,
-					LimitValue:              0,
-					LimitCurrency:           "USD",
-					LimitAvailability:       "CALL CREDIT",
-					ExposureLimit:           "HIGH",
-					ExpirationDate:          parseTimeLayout("2006-01-02 15:04:05.000", "2022-09-30 00:00:00.000"),
-					MarketType:              "Wholesale",
-					IndustryCode:            "40203020 - Investment Banking and Brokerage",
-					SPRating:                "Not Rated",
-					MoodyRating:             "Not Rated",
-					FinalInternalRating:     "CCC+",
-					FinalRating:             "CCC+",
-					Equifax:                 " ",
-					AmendedBy:               "Sarah Marissa Jynnyl Vista",
-					EffectiveDate:           parseTimeLayout("2006-01-02 15:04:05.000", "2021-10-06 00:00:00.000"),
-					ReviewDate:              parseTimeLayout("2006-01-02 15:04:05.000", "2022-08-30 00:00:00.000"),
-					DoddFrankClassification: "No Classification",
-					ReportCreatedDate:       parseTimeLayout("2006-01-02 15:04:05.000", "2021-10-20 13:30:15.143"),
-					Boost:                   "FALSE",
-					TradingEntity:           "SHELL ENERGY NORTH AMERICA (US) LP",
-					LegalEntity:             "Shell Energy North America (US), L.P.",
-					Agmt:                    "X",
-					CSA:                     "X",
-					Tenor:                   "X",
-					Limit:                   "X",
-					ReportingDate:           parseTime("20-10-2021"),
-					CreatedAt:               parseTimeLayout("2006-01-02 15:04:05.000", "2021-10-20 13:22:25.910"),
-				},
-			},
-			wantErr: false,
-		},
-		{
-			name: "no rows in result set",
-			fields: fields{
-				nucleusDb:         nil,
-				machineLearningDb: machineLearningDbEmpty,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx: context.TODO(),
-			},
-			want:    nil,
-			wantErr: false,
-		},
-		{
-			name: "error in result set",
-			fields: fields{
-				nucleusDb:         nil,
-				machineLearningDb: machineLearningDbError,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx: context.TODO(),
-			},
-			want:    nil,
-			wantErr: true,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			repo := &NucleusTradeRepository{
-				nucleusDb:         tt.fields.nucleusDb,
-				machineLearningDb: tt.fields.machineLearningDb,
-				logger:            tt.fields.logger,
-			}
-			got, err := repo.GetLarBaselist(tt.args.ctx)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("NucleusTradeRepository.GetLarBaselist() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("NucleusTradeRepository.GetLarBaselist() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-func TestNucleusTradeRepository_GetNucPowerDealByKeys(t *testing.T) {
-	serverLogger := logger.GetServerLogger()
-	serverLogger.Enable(false)
-
-	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer nucleusDb.Close()
-
-	lstPowerkeys := []float64{3996506, 3996507}
-
-	dealKeysQuery, paramsDeal, err := oracle.CreateInQueryFloat64(lstPowerkeys, []interface{}{}, "pd.power_key")
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when creating the query", err)
-	}
-
-	getNucPowerDealByKeysQuery := getNucPowerDealByKeysQuery(dealKeysQuery)
-
-	var namedParamsQuery []driver.Value
-	for _, value := range paramsDeal {
-		valueNamed, _ := value.(sql.NamedArg)
-		namedParamsQuery = append(namedParamsQuery, valueNamed)
-	}
-
-	columns := []string{"POWER_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
-		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE",
-		"LEGALENTITY", "LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER",
-		"CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER",
-		"TZ_TIME_ZONE", "HAS_BROKER", "BROKER", "OPTION_KEY", "CREATEDBY", "CREATE_DATE",
-		"MODIFIEDBY", "MODIFY_DATE", "EXECUTION_DATE", "EXECUTION_TIME", "EXOTIC_FLAG"}
-
-	mock.ExpectQuery(getNucPowerDealByKeysQuery).WithArgs(namedParamsQuery...).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			3996506, "PWRNSD", "PURCHASE", parseTime("05-05-2022"),
-			1601, "PJM", "PJM INTERCONNECTION LLC", "PJM",
-			"SENA", "Shell Energy North America (US), L.P.", 10430, "011-KW-BI-05701",
-			"HOURLY", "EAST", "", 288, "SD - TRANS", "SROSS",
-			"PPT", "NO", "NA", nil, "SROSS", parseTime("04-05-2022"),
-			"SROSS", parseTime("05-05-2022"), "", "", "NA",
-		).AddRow(
-			3996507, "PWRNSD", "SALE", parseTime("05-05-2022"),
-			10459, "MISO", "MIDWEST INDEPENDENT TRANSMISSION SYSTEM", "MIDWEST IN",
-			"SENA", "Shell Energy North America (US), L.P.", 10430, "011-KW-IS-10952",
-			"HOURLY", "EAST", "", 288, "SD - TRANS", "SROSS",
-			"PPT", "NO", "NA", nil, "SROSS", parseTime("04-05-2022"),
-			"SROSS", parseTime("05-05-2022"), "2022-05-05", "08:18:55 AM", "NA",
-		))
-
-	dealKeysArray := []int{3996506, 3996507}
-
-	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeysArray, []interface{}{}, "pv.pd_power_key")
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when creating the query", err)
-	}
-
-	getNucPowerTradeTermModelQ := getNucPowerTradeTermModelQuery(dealKeysQuery)
-
-	var namedParams []driver.Value
-	for _, value := range params {
-		valueNamed, _ := value.(sql.NamedArg)
-		namedParams = append(namedParams, valueNamed)
-	}
-
-	columns = []string{"PD_POWER_KEY", "VOLUME_SEQ", "DY_BEG_DAY", "DY_END_DAY", "PRICE_TYPE",
-		"PRICE", "VOLUME", "PPEP_PP_POOL", "PPEP_PEP_PRODUCT", "CTP_POINT_CODE", "FORMULA",
-		"SCH_SCHEDULE"}
-
-	mock.ExpectQuery(getNucPowerTradeTermModelQ).WithArgs(namedParams...).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			3996506, 0, parseTime("05-05-2022"), parseTime("05-05-2022"), "I",
-			0, 0, "PJM", "HOURLY", "MISO", "[PJM DA LMP|40523629|HOURLY]",
-			"NERC",
-		).AddRow(
-			3996507, 0, parseTime("05-05-2022"), parseTime("05-05-2022"), "I",
-			0, 0, "MISOE", "HOURLY", "MISO/PJM", "[MISO DALMP|PJMC|HOURLY]",
-			"NERC",
-		))
-
-	formulaMap := make(map[int]int)
-	formulaMap[3996506] = 0
-	formulaMap[3996507] = 0
-
-	insNumQuery, _, err := oracle.CreateMapIntWhereQuery(formulaMap, []interface{}{}, "pv_pd_power_key", "pv_volume_seq")
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when creating the query", err)
-	}
-
-	getNucPowerTradeIndexModelQ := getNucPowerTradeIndexModelQuery(insNumQuery)
-
-	columns = []string{"PV_PD_POWER_KEY", "PV_VOLUME_SEQ", "PUBLICATION", "PUB_INDEX", "FREQUENCY"}
-
-	// this is a small hack to allow any values to be in any order for the mock,
-	// the issue is that the parameters for the query never will be in the same
-	// order because it comes from iterating over the keys of a map and Go always
-	// randmize them
-	mock.ExpectQuery(getNucPowerTradeIndexModelQ).WithArgs(AnyValueMatch{}, AnyValueMatch{},
-		AnyValueMatch{}, AnyValueMatch{}).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			3996506, 0, "PJM DA LMP", "40523629", "HOURLY",
-		).AddRow(
-			3996507, 0, "MISO DALMP", "PJMC", "HOURLY",
-		))
-
-	type fields struct {
-		nucleusDb         *sql.DB
-		machineLearningDb *sql.DB
-		logger            logger.Logger
-	}
-	type args struct {
-		ctx          context.Context
-		lstPowerkeys []float64
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    []*nucleus.NucleusTradeHeaderModel
-		wantErr bool
-	}{
-		{
-			name: "regular run",
-			fields: fields{
-				nucleusDb:         nucleusDb,
-				machineLearningDb: nil,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx:          context.TODO(),
-				lstPowerkeys: lstPowerkeys,
-			},
-			want: []*nucleus.NucleusTradeHeaderModel{
-				{
-					DealKey:             3996506,
-					DealType:            "PWRNSD",
-					TotalQuantity:       0,
-					TransactionDate:     parseTime("05-05-2022"),
-					DnDirection:         "PURCHASE",
-					CyCompanyKey:        1601,
-					CompanyCode:         "PJM",
-					Company:             "PJM",
-					CompanyLongName:     "PJM INTERCONNECTION LLC",
-					HsHedgeKey:          "",
-					PrtPortfolio:        288,
-					Portfolio:           "SD - TRANS",
-					UrTrader:            "SROSS",
-					CyBrokerKey:         0,
-					Broker:              "NA",
-					DaRtIndicator:       "",
-					TzTimeZone:          "PPT",
-					TzExerciseZone:      "",
-					HasBroker:           "NO",
-					IbPrtPortfolio:      0,
-					IbPortfolio:         "",
-					IbUrTrader:          "",
-					ExercisedOptionKey:  0,
-					Contract:            "011-KW-BI-05701",
-					ConfirmFormat:       "HOURLY",
-					CyLegalEntityKey:    10430,
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					Region:              "EAST",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:          0,
-							BegDate:         parseTime("05-05-2022"),
-							EndDate:         parseTime("05-05-2022"),
-							Pool1:           "PJM",
-							Product1:        "HOURLY",
-							PointCode1:      "MISO",
-							Pool2:           "",
-							Product2:        "",
-							HolidaySchedule: "NERC",
-							PointCode2:      "",
-							Formula1:        "[PJM DA LMP|40523629|HOURLY]",
-							Indexes1: []*nucleus.NucleusTradeIndexModel{
-								{
-									VolSeq:      0,
-									Publication: "PJM DA LMP",
-									PubIndex:    "40523629",
-									Frequency:   "HOURLY",
-								},
-							},
-							Formula2:   "",
-							Indexes2:   nil,
-							PriceType:  "I",
-							FixedPrice: 0,
-							Volume:     0,
-						},
-					},
-					AnomalyTestResult:  "",
-					CreatedAt:          parseTime("04-05-2022"),
-					ModifiedAt:         parseTime("05-05-2022"),
-					CreatedBy:          "SROSS",
-					ModifiedBy:         "SROSS",
-					OptionType:         "",
-					ExecutionTime:      parseTime("01-01-0001"),
-					ExoticFlag:         "NA",
-					InteraffiliateFlag: "N",
-					StartDate:          parseTime("01-01-0001"),
-					EndDate:            parseTime("01-01-0001"),
-				},
-				{
-					DealKey:             3996507,
-					DealType:            "PWRNSD",
-					TotalQuantity:       0,
-					TransactionDate:     parseTime("05-05-2022"),
-					DnDirection:         "SALE",
-					CyCompanyKey:        10459,
-					CompanyCode:         "MIDWEST IN",
-					Company:             "MISO",
-					CompanyLongName:     "MIDWEST INDEPENDENT TRANSMISSION SYSTEM",
-					HsHedgeKey:          "",
-					PrtPortfolio:        288,
-					Portfolio:           "SD - TRANS",
-					UrTrader:            "SROSS",
-					CyBrokerKey:         0,
-					Broker:              "NA",
-					DaRtIndicator:       "",
-					TzTimeZone:          "PPT",
-					TzExerciseZone:      "",
-					HasBroker:           "NO",
-					IbPrtPortfolio:      0,
-					IbPortfolio:         "",
-					IbUrTrader:          "",
-					ExercisedOptionKey:  0,
-					Contract:            "011-KW-IS-10952",
-					ConfirmFormat:       "HOURLY",
-					CyLegalEntityKey:    10430,
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					Region:              "EAST",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:          0,
-							BegDate:         parseTime("05-05-2022"),
-							EndDate:         parseTime("05-05-2022"),
-							Pool1:           "MISOE",
-							Product1:        "HOURLY",
-							PointCode1:      "MISO/PJM",
-							Pool2:           "",
-							Product2:        "",
-							HolidaySchedule: "NERC",
-							PointCode2:      "",
-							Formula1:        "[MISO DALMP|PJMC|HOURLY]",
-							Indexes1: []*nucleus.NucleusTradeIndexModel{
-								{
-									VolSeq:      0,
-									Publication: "MISO DALMP",
-									PubIndex:    "PJMC",
-									Frequency:   "HOURLY",
-								},
-							},
-							Formula2:   "",
-							Indexes2:   nil,
-							PriceType:  "I",
-							FixedPrice: 0,
-							Volume:     0,
-						},
-					},
-					AnomalyTestResult:  "",
-					CreatedAt:          parseTime("04-05-2022"),
-					ModifiedAt:         parseTime("05-05-2022"),
-					CreatedBy:          "SROSS",
-					ModifiedBy:         "SROSS",
-					OptionType:         "",
-					ExecutionTime:      parseDateTime("2022-05-05", "08:18:55 AM"),
-					ExoticFlag:         "NA",
-					InteraffiliateFlag: "N",
-					StartDate:          parseTime("01-01-0001"),
-					EndDate:            parseTime("01-01-0001"),
-				},
-			},
-			wantErr: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			repo := &NucleusTradeRepository{
-				nucleusDb:         tt.fields.nucleusDb,
-				machineLearningDb: tt.fields.machineLearningDb,
-				logger:            tt.fields.logger,
-			}
-			got, err := repo.GetNucPowerDealByKeys(tt.args.ctx, tt.args.lstPowerkeys)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("NucleusTradeRepository.GetNucPowerDealByKeys() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-
-			if len(got) != len(tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucPowerDealByKeys() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
-				return
-			}
-
-			for _, wantedValue := range tt.want {
-				exists := false
-				for _, value := range got {
-					if wantedValue.DealKey == value.DealKey {
-						exists = true
-						if len(value.Terms) != len(wantedValue.Terms) {
-							t.Errorf("NucleusTradeRepository.GetNucPowerDealByKeys() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
-							return
-						}
-
-						for _, wantedTerm := range wantedValue.Terms {
-							termExists := false
-							for _, valueTerm := range value.Terms {
-								if wantedTerm.VolSeq == valueTerm.VolSeq {
-									termExists = true
-
-									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
-										t.Errorf("NucleusTradeRepository.GetNucPowerDealByKeys() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes1 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes1 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucPowerDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
-										t.Errorf("NucleusTradeRepository.GetNucPowerDealByKeys() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes2 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes2 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucPowerDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if !reflect.DeepEqual(wantedTerm, valueTerm) {
-										t.Errorf("NucleusTradeRepository.GetNucPowerDealByKeys() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
-										return
-									}
-								}
-							}
-							if !termExists {
-								t.Errorf("NucleusTradeRepository.GetNucPowerDealByKeys() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
-								return
-							}
-						}
-
-						if !reflect.DeepEqual(wantedValue, value) {
-							t.Errorf("NucleusTradeRepository.GetNucPowerDealByKeys() wantedValue = %+v, value = %+v", wantedValue, value)
-							return
-						}
-					}
-				}
-				if !exists {
-					t.Errorf("NucleusTradeRepository.GetNucPowerDealByKeys() wanted value does not exist %d", wantedValue.DealKey)
-					return
-				}
-			}
-
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucPowerDealByKeys() got = %+v, want = %+v", got, tt.want)
-				return
-			}
-		})
-	}
-}
-
-func TestNucleusTradeRepository_GetNucPowerSwapDealByKeys(t *testing.T) {
-	serverLogger := logger.GetServerLogger()
-	serverLogger.Enable(false)
-
-	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer nucleusDb.Close()
-
-	lstPowerSwapkeys := []float64{1388598, 1388692}
-
-	dealKeysQuery, paramsDeal, err := oracle.CreateInQueryFloat64(lstPowerSwapkeys, []interface{}{}, "pd.pswap_key")
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when creating the query", err)
-	}
-
-	getNucPowerSwapDealByKeysQuery := getNucPowerSwapDealByKeysQuery(dealKeysQuery)
-
-	var namedParamsQuery []driver.Value
-	for _, value := range paramsDeal {
-		valueNamed, _ := value.(sql.NamedArg)
-		namedParamsQuery = append(namedParamsQuery, valueNamed)
-	}
-
-	columns := []string{"PSWAP_KEY", "DEAL_TYPE", "TOTAL_QUANTITY", "DN_DIRECTION",
-		"TRANSACTION_DATE", "CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE",
-		"LEGALENTITY", "LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "INTERAFFILIATE_FLAG",
-		"CONTRACTNUMBER", "CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO",
-		"PORTFOLIO", "UR_TRADER", "IB_PRT_PORTFOLIO", "IB_PORTFOLIO", "IB_UR_TRADER",
-		"TZ_TIME_ZONE", "HAS_BROKER", "BROKER", "OPTION_KEY", "PPEP_PP_POOL", "PPEP_PEP_PRODUCT",
-		"VOLUME", "FIXED_PRICE", "NONSTD_FLAG", "PI_PB_PUBLICATION", "PI_PUB_INDEX",
-		"FRQ_FREQUENCY", "FIX_PI_PB_PUBLICATION", "FIX_PI_PUB_INDEX", "FIX_FRQ_FREQUENCY",
-		"DY_BEG_DAY", "DY_END_DAY", "SCH_SCHEDULE", "CREATEDBY", "CREATE_DATE", "MODIFIEDBY",
-		"MODIFY_DATE", "EXECUTION_DATE", "EXECUTION_TIME", "EXOTIC_FLAG"}
-
-	mock.ExpectQuery(getNucPowerSwapDealByKeysQuery).WithArgs(namedParamsQuery...).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			1388598, "PSWPS", 800, "PURCHASE",
-			parseTime("05-05-2022"), 18098, "FIMAT USA", "NEWEDGE USA LLC", "FIMAT USA",
-			"SENA", "Shell Energy North America (US), L.P.", 10430, "N",
-			"", "POWER SWAP", "SOUTH", "", 235,
-			"ERCOT B E FINANCIAL", "CWATSON", nil, "", "",
-			"CPT", "YES", "INTERXCHG", nil, "ERFNH", "STD ON",
-			50, 111, "Y", "ER RT LMP", "NORTH_HUB_AVG",
-			"HOURLY", nil, nil, nil,
-			parseTime("05-05-2022"), parseTime("05-05-2022"), "NERC", "LOB2", parseTime("05-05-2022"), "LOB2",
-			parseTime("05-05-2022"), "2022-05-05", "09:08:26 AM", "No",
-		).AddRow(
-			1388692, "PSWPS", 21600, "PURCHASE",
-			parseTime("05-05-2022"), 18098, "FIMAT USA", "NEWEDGE USA LLC", "FIMAT USA",
-			"SENA", "Shell Energy North America (US), L.P.", 10430, "N",
-			"", "POWER SWAP", "SOUTH", "", 235,
-			"ERCOT B E FINANCIAL", "CWATSON", nil, "", "",
-			"CPT", "YES", "INTERXCHG", nil, "ERFNH", "STD 2x16",
-			135, 30, "N", "ER RT LMP", "NORTH_HUB_AVG",
-			"HOURLY", nil, nil, nil,
-			parseTime("01-05-2025"), parseTime("31-05-2025"), "NERC", "LOB2", parseTime("05-05-2022"), "JKING",
-			parseTime("05-05-2022"), "2022-05-05", "11:05:35 AM", "No",
-		))
-
-	dealKeysArray := []int{1388598}
-
-	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeysArray, []interface{}{}, "pswp_pswap_key")
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when creating the query", err)
-	}
-
-	getNucPowerSwapDealTermModelQ := getNucPowerSwapDealTermModelQuery(dealKeysQuery)
-
-	var namedParams []driver.Value
-	for _, value := range params {
-		valueNamed, _ := value.(sql.NamedArg)
-		namedParams = append(namedParams, valueNamed)
-	}
-
-	columns = []string{"PSWP_PSWAP_KEY", "VOLUME_SEQ", "DY_BEG_DAY", "DY_END_DAY"}
-	mock.ExpectQuery(getNucPowerSwapDealTermModelQ).WithArgs(namedParams...).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			1388598, 613, parseTime("05-03-2024"), parseTime("05-03-2024"),
-		).AddRow(
-			1388598, 614, parseTime("06-03-2024"), parseTime("06-03-2024"),
-		))
-
-	type fields struct {
-		nucleusDb         *sql.DB
-		machineLearningDb *sql.DB
-		logger            logger.Logger
-	}
-	type args struct {
-		ctx              context.Context
-		lstPowerSwapkeys []float64
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    []*nucleus.NucleusTradeHeaderModel
-		wantErr bool
-	}{
-		{
-			name: "regular run",
-			fields: fields{
-				nucleusDb:         nucleusDb,
-				machineLearningDb: nil,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx:              context.TODO(),
-				lstPowerSwapkeys: lstPowerSwapkeys,
-			},
-			want: []*nucleus.NucleusTradeHeaderModel{
-				{
-					DealKey:             1388598,
-					DealType:            "PSWPS",
-					TotalQuantity:       800,
-					DnDirection:         "PURCHASE",
-					TransactionDate:     parseTime("05-05-2022"),
-					CyCompanyKey:        18098,
-					Company:             "FIMAT USA",
-					CompanyLongName:     "NEWEDGE USA LLC",
-					CompanyCode:         "FIMAT USA",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					Contract:            "",
-					ConfirmFormat:       "POWER SWAP",
-					Region:              "SOUTH",
-					PrtPortfolio:        235,
-					Portfolio:           "ERCOT B E FINANCIAL",
-					UrTrader:            "CWATSON",
-					IbPrtPortfolio:      0,
-					IbPortfolio:         "",
-					IbUrTrader:          "",
-					TzTimeZone:          "CPT",
-					HasBroker:           "YES",
-					Broker:              "INTERXCHG",
-					ExercisedOptionKey:  0,
-					StartDate:           parseTime("05-05-2022"),
-					EndDate:             parseTime("05-05-2022"),
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:     613,
-							Pool1:      "ERFNH",
-							Product1:   "STD ON",
-							Volume:     0,
-							FixedPrice: 0,
-							Indexes1: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "ER RT LMP",
-									PubIndex:    "NORTH_HUB_AVG",
-									Frequency:   "HOURLY",
-								},
-							},
-							BegDate:         parseTime("05-03-2024"),
-							EndDate:         parseTime("05-03-2024"),
-							HolidaySchedule: "NERC",
-						},
-						{
-							VolSeq:     614,
-							Pool1:      "ERFNH",
-							Product1:   "STD ON",
-							Volume:     0,
-							FixedPrice: 0,
-							Indexes1: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "ER RT LMP",
-									PubIndex:    "NORTH_HUB_AVG",
-									Frequency:   "HOURLY",
-								},
-							},
-							BegDate:         parseTime("06-03-2024"),
-							EndDate:         parseTime("06-03-2024"),
-							HolidaySchedule: "NERC",
-						},
-					},
-					CreatedBy:          "LOB2",
-					CreatedAt:          parseTime("05-05-2022"),
-					ModifiedBy:         "LOB2",
-					ModifiedAt:         parseTime("05-05-2022"),
-					ExecutionTime:      parseDateTime("2022-05-05", "09:08:26 AM"),
-					ExoticFlag:         "No",
-					InteraffiliateFlag: "N",
-				},
-				{
-					DealKey:             1388692,
-					DealType:            "PSWPS",
-					TotalQuantity:       21600,
-					DnDirection:         "PURCHASE",
-					TransactionDate:     parseTime("05-05-2022"),
-					CyCompanyKey:        18098,
-					Company:             "FIMAT USA",
-					CompanyLongName:     "NEWEDGE USA LLC",
-					CompanyCode:         "FIMAT USA",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					Contract:            "",
-					ConfirmFormat:       "POWER SWAP",
-					Region:              "SOUTH",
-					PrtPortfolio:        235,
-					Portfolio:           "ERCOT B E FINANCIAL",
-					UrTrader:            "CWATSON",
-					IbPrtPortfolio:      0,
-					IbPortfolio:         "",
-					IbUrTrader:          "",
-					TzTimeZone:          "CPT",
-					HasBroker:           "YES",
-					Broker:              "INTERXCHG",
-					ExercisedOptionKey:  0,
-					StartDate:           parseTime("01-05-2025"),
-					EndDate:             parseTime("31-05-2025"),
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							VolSeq:     0,
-							Pool1:      "ERFNH",
-							Product1:   "STD 2x16",
-							Volume:     135,
-							FixedPrice: 30,
-							Indexes1: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "ER RT LMP",
-									PubIndex:    "NORTH_HUB_AVG",
-									Frequency:   "HOURLY",
-								},
-							},
-							BegDate:         parseTime("01-05-2025"),
-							EndDate:         parseTime("31-05-2025"),
-							HolidaySchedule: "NERC",
-						},
-					},
-					CreatedBy:          "LOB2",
-					CreatedAt:          parseTime("05-05-2022"),
-					ModifiedBy:         "JKING",
-					ModifiedAt:         parseTime("05-05-2022"),
-					ExecutionTime:      parseDateTime("2022-05-05", "11:05:35 AM"),
-					ExoticFlag:         "No",
-					InteraffiliateFlag: "N",
-				},
-			},
-			wantErr: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			repo := &NucleusTradeRepository{
-				nucleusDb:         tt.fields.nucleusDb,
-				machineLearningDb: tt.fields.machineLearningDb,
-				logger:            tt.fields.logger,
-			}
-			got, err := repo.GetNucPowerSwapDealByKeys(tt.args.ctx, tt.args.lstPowerSwapkeys)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealByKeys() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-
-			if len(got) != len(tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealByKeys() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
-				return
-			}
-
-			for _, wantedValue := range tt.want {
-				exists := false
-				for _, value := range got {
-					if wantedValue.DealKey == value.DealKey {
-						exists = true
-						if len(value.Terms) != len(wantedValue.Terms) {
-							t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealByKeys() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
-							return
-						}
-
-						for _, wantedTerm := range wantedValue.Terms {
-							termExists := false
-							for _, valueTerm := range value.Terms {
-								if wantedTerm.VolSeq == valueTerm.VolSeq {
-									termExists = true
-
-									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
-										t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealByKeys() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes1 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes1 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
-										t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealByKeys() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes2 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes2 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if !reflect.DeepEqual(wantedTerm, valueTerm) {
-										t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealByKeys() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
-										return
-									}
-								}
-							}
-							if !termExists {
-								t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealByKeys() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
-								return
-							}
-						}
-
-						if !reflect.DeepEqual(wantedValue, value) {
-							t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealByKeys() wantedValue = %+v, value = %+v", wantedValue, value)
-							return
-						}
-					}
-				}
-				if !exists {
-					t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealByKeys() wanted value does not exist %d", wantedValue.DealKey)
-					return
-				}
-			}
-
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealByKeys() got = %+v, want = %+v", got, tt.want)
-				return
-			}
-		})
-	}
-}
-
-func TestNucleusTradeRepository_GetNucPowerOptionsDealByKeys(t *testing.T) {
-	serverLogger := logger.GetServerLogger()
-	serverLogger.Enable(false)
-
-	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer nucleusDb.Close()
-
-	lstPowerOptionkeys := []float64{43435, 43441}
-
-	dealKeysQuery, paramsDeal, err := oracle.CreateInQueryFloat64(lstPowerOptionkeys, []interface{}{}, "pd.poption_key")
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when creating the query", err)
-	}
-
-	getNucPowerOptionsDealByKeysQ := getNucPowerOptionsDealByKeysQuery(dealKeysQuery)
-
-	var namedParamsQuery []driver.Value
-	for _, value := range paramsDeal {
-		valueNamed, _ := value.(sql.NamedArg)
-		namedParamsQuery = append(namedParamsQuery, valueNamed)
-	}
-
-	columns := []string{"POPTION_KEY", "DEAL_TYPE", "TOTAL_QUANTITY", "DN_DIRECTION",
-		"TRANSACTION_DATE", "CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE",
-		"LEGALENTITY", "LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "INTERAFFILIATE_FLAG",
-		"CONTRACTNUMBER", "CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO",
-		"PORTFOLIO", "UR_TRADER", "IB_PRT_PORTFOLIO", "IB_PORTFOLIO", "IB_UR_TRADER",
-		"TZ_TIME_ZONE", "TZ_EXERCISE_ZONE", "HAS_BROKER", "BROKER", "PPEP_PP_POOL",
-		"PPEP_PEP_PRODUCT", "CTP_POINT_CODE", "SETTLE_FORMULA", "DY_BEG_DAY", "DY_END_DAY",
-		"SCH_SCHEDULE", "VOLUME", "STRIKE_PRICE", "STRIKE_PRICE_TYPE", "STRIKE_FORMULA",
-		"CREATEDBY", "MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE", "EXECUTION_DATE",
-		"EXECUTION_TIME", "EXOTIC_FLAG",
-	}
-	mock.ExpectQuery(getNucPowerOptionsDealByKeysQ).WithArgs(namedParamsQuery...).
-		WillReturnRows(sqlmock.NewRows(columns).AddRow(
-			43435, "POPTS", 17600, "SALE",
-			parseTime("23-05-2022"), 10322, "IBT", "INTERBOOK TRANSFER", "IBT",
-			"SENA", "Shell Energy North America (US), L.P.", 10430, "N",
-			"", "POWER OPT", "EAST", "", 99478,
-			"ERCOT_RETAIL", "GABDULLA", nil, "", "",
-			"CPT", "CPT", "NO", "NA", "ERCNZ",
-			"STD ON", "HB_NORTH", nil, parseTime("01-06-2022"), parseTime("30-06-2022"),
-			"NERC", -50, 100, "F", "",
-			"GABDULLA", "GABDULLA", parseTime("23-05-2022"), parseTime("23-05-2022"), "05/23/2022",
-			"12:00:00 AM", "No",
-		).AddRow(
-			43441, "POPTS", 35200, "PURCHASE",
-			parseTime("23-05-2022"), 10322, "IBT", "INTERBOOK TRANSFER", "IBT",
-			"SENA", "Shell Energy North America (US), L.P.", 10430, "N",
-			"", "POWER OPT", "EAST", "", 220,
-			"SOUTH OTHER HR", "GABDULLA", nil, "", "",
-			"CPT", "CPT", "NO", "NA", "ERCNZ",
-			"STD ON", "HB_NORTH", "[MISO RTLMP|CINERGY.HUB|HOURLY]", parseTime("01-06-2022"), parseTime("30-06-2022"),
-			"NERC", 100, 100, "F", "(10.5*[GD|HOU SHP CHNL|DAILY]) + 3.55",
-			"GABDULLA", "GABDULLA", parseTime("23-05-2022"), parseTime("23-05-2022"), "05/23/2022",
-			"12:00:00 AM", "No",
-		))
-
-	type fields struct {
-		nucleusDb         *sql.DB
-		machineLearningDb *sql.DB
-		logger            logger.Logger
-	}
-	type args struct {
-		ctx                context.Context
-		lstPowerOptionkeys []float64
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    []*nucleus.NucleusTradeHeaderModel
-		wantErr bool
-	}{
-		{
-			name: "regular run",
-			fields: fields{
-				nucleusDb:         nucleusDb,
-				machineLearningDb: nil,
-				logger:            serverLogger,
-			},
-			args: args{
-				ctx:                context.TODO(),
-				lstPowerOptionkeys: lstPowerOptionkeys,
-			},
-			want: []*nucleus.NucleusTradeHeaderModel{
-				{
-					DealKey:             43435,
-					DealType:            "POPTS",
-					TotalQuantity:       17600,
-					DnDirection:         "SALE",
-					TransactionDate:     parseTime("23-05-2022"),
-					CyCompanyKey:        10322,
-					Company:             "IBT",
-					CompanyLongName:     "INTERBOOK TRANSFER",
-					CompanyCode:         "IBT",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					InteraffiliateFlag:  "N",
-					Contract:            "",
-					ConfirmFormat:       "POWER OPT",
-					Region:              "EAST",
-					HsHedgeKey:          "",
-					PrtPortfolio:        99478,
-					Portfolio:           "ERCOT_RETAIL",
-					UrTrader:            "GABDULLA",
-					IbPrtPortfolio:      0,
-					IbPortfolio:         "",
-					IbUrTrader:          "",
-					TzTimeZone:          "CPT",
-					TzExerciseZone:      "CPT",
-					HasBroker:           "NO",
-					Broker:              "NA",
-					StartDate:           parseTime("01-06-2022"),
-					EndDate:             parseTime("30-06-2022"),
-					CreatedBy:           "GABDULLA",
-					ModifiedBy:          "GABDULLA",
-					CreatedAt:           parseTime("23-05-2022"),
-					ModifiedAt:          parseTime("23-05-2022"),
-					ExecutionTime:       parseDateTime("05/23/2022", "12:00:00 AM"),
-					ExoticFlag:          "No",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							Pool1:           "ERCNZ",
-							Product1:        "STD ON",
-							PointCode1:      "HB_NORTH",
-							BegDate:         parseTime("01-06-2022"),
-							EndDate:         parseTime("30-06-2022"),
-							HolidaySchedule: "NERC",
-							Volume:          -50,
-							FixedPrice:      100,
-							PriceType:       "F",
-						},
-					},
-				},
-				{
-					DealKey:             43441,
-					DealType:            "POPTS",
-					TotalQuantity:       35200,
-					DnDirection:         "PURCHASE",
-					TransactionDate:     parseTime("23-05-2022"),
-					CyCompanyKey:        10322,
-					Company:             "IBT",
-					CompanyLongName:     "INTERBOOK TRANSFER",
-					CompanyCode:         "IBT",
-					LegalEntity:         "SENA",
-					LegalEntityLongName: "Shell Energy North America (US), L.P.",
-					CyLegalEntityKey:    10430,
-					InteraffiliateFlag:  "N",
-					Contract:            "",
-					ConfirmFormat:       "POWER OPT",
-					Region:              "EAST",
-					HsHedgeKey:          "",
-					PrtPortfolio:        220,
-					Portfolio:           "SOUTH OTHER HR",
-					UrTrader:            "GABDULLA",
-					IbPrtPortfolio:      0,
-					IbPortfolio:         "",
-					IbUrTrader:          "",
-					TzTimeZone:          "CPT",
-					TzExerciseZone:      "CPT",
-					HasBroker:           "NO",
-					Broker:              "NA",
-					StartDate:           parseTime("01-06-2022"),
-					EndDate:             parseTime("30-06-2022"),
-					CreatedBy:           "GABDULLA",
-					ModifiedBy:          "GABDULLA",
-					CreatedAt:           parseTime("23-05-2022"),
-					ModifiedAt:          parseTime("23-05-2022"),
-					ExecutionTime:       parseDateTime("05/23/2022", "12:00:00 AM"),
-					ExoticFlag:          "No",
-					Terms: []*nucleus.NucleusTradeTermModel{
-						{
-							Pool1:           "ERCNZ",
-							Product1:        "STD ON",
-							PointCode1:      "HB_NORTH",
-							BegDate:         parseTime("01-06-2022"),
-							EndDate:         parseTime("30-06-2022"),
-							HolidaySchedule: "NERC",
-							Volume:          100,
-							FixedPrice:      100,
-							PriceType:       "F",
-							Indexes1: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "MISO RTLMP",
-									PubIndex:    "CINERGY.HUB",
-									Frequency:   "HOURLY",
-								},
-							},
-							Indexes2: []*nucleus.NucleusTradeIndexModel{
-								{
-									Publication: "GD",
-									PubIndex:    "HOU SHP CHNL",
-									Frequency:   "DAILY",
-								},
-							},
-						},
-					},
-				},
-			},
-			wantErr: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			repo := &NucleusTradeRepository{
-				nucleusDb:         tt.fields.nucleusDb,
-				machineLearningDb: tt.fields.machineLearningDb,
-				logger:            tt.fields.logger,
-			}
-			got, err := repo.GetNucPowerOptionsDealByKeys(tt.args.ctx, tt.args.lstPowerOptionkeys)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealByKeys() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-
-			if len(got) != len(tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealByKeys() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
-				return
-			}
-
-			for _, wantedValue := range tt.want {
-				exists := false
-				for _, value := range got {
-					if wantedValue.DealKey == value.DealKey {
-						exists = true
-						if len(value.Terms) != len(wantedValue.Terms) {
-							t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealByKeys() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
-							return
-						}
-
-						for _, wantedTerm := range wantedValue.Terms {
-							termExists := false
-							for _, valueTerm := range value.Terms {
-								if wantedTerm.VolSeq == valueTerm.VolSeq {
-									termExists = true
-
-									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
-										t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealByKeys() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes1 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes1 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
-										t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealByKeys() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
-										return
-									}
-
-									for _, wantedIndex := range wantedTerm.Indexes2 {
-										indexExists := false
-										for _, valueIndex := range valueTerm.Indexes2 {
-											if reflect.DeepEqual(wantedIndex, valueIndex) {
-												indexExists = true
-											}
-										}
-										if !indexExists {
-											t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
-											return
-										}
-									}
-
-									if !reflect.DeepEqual(wantedTerm, valueTerm) {
-										t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealByKeys() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
-										return
-									}
-								}
-							}
-							if !termExists {
-								t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealByKeys() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
-								return
-							}
-						}
-
-						if !reflect.DeepEqual(wantedValue, value) {
-							t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealByKeys() wantedValue = %+v, value = %+v", wantedValue, value)
-							return
-						}
-					}
-				}
-				if !exists {
-					t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealByKeys() wanted value does not exist %d", wantedValue.DealKey)
-					return
-				}
-			}
-
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealByKeys() got = %+v, want = %+v", got, tt.want)
-				return
-			}
-		})
-	}
-}
+package power
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	mssql "github.com/denisenkom/go-mssqldb"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+
+	"github.com/sede-x/RogerRogerAnomalyDetector/db/oracle"
+	"github.com/sede-x/RogerRogerAnomalyDetector/logger"
+	"github.com/sede-x/RogerRogerAnomalyDetector/models/common"
+	"github.com/sede-x/RogerRogerAnomalyDetector/models/nucleus"
+)
+
+func parseTimeLayout(format string, date string) time.Time {
+	time, err := time.Parse(format, date)
+	if err != nil {
+		log.Println("parseTimeLayout err: ", err)
+	}
+	return time
+}
+
+func parseTime(date string) time.Time {
+	return parseTimeLayout("02-01-2006", date)
+}
+
+func parseDateTime(date string, timestamp string) time.Time {
+	if date == "" || timestamp == "" {
+		return time.Time{}
+	}
+	executionTimeString := date + " " + timestamp
+	execTime, err := time.Parse("2006-01-02 03:04:05 PM", executionTimeString)
+	if err != nil {
+		execTime, err = time.Parse("01/02/2006 03:04:05 PM", executionTimeString)
+		if err != nil {
+			log.Println("parseDateTime err: ", err)
+		}
+	}
+	return execTime
+}
+
+type AnyValueMatch struct{}
+
+func (e AnyValueMatch) Match(v driver.Value) bool {
+	return true
+}
+
+func TestNucleusTradeRepository_GetNucPowerDealList(t *testing.T) {
+	now := time.Now()
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	columns := []string{"POWER_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
+		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE",
+		"LEGALENTITY", "LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER",
+		"CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER",
+		"TZ_TIME_ZONE", "HAS_BROKER", "BROKER", "OPTION_KEY", "CREATEDBY", "CREATE_DATE",
+		"MODIFIEDBY", "MODIFY_DATE", "EXECUTION_DATE", "EXECUTION_TIME", "EXOTIC_FLAG"}
+
+	mock.ExpectQuery(getNucPowerDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			3996506, "PWRNSD", "PURCHASE", parseTime("05-05-2022"),
+			1601, "PJM", "PJM INTERCONNECTION LLC", "PJM",
+			"SENA", "Shell Energy North America (US), L.P.", 10430, "011-KW-BI-05701",
+			"HOURLY", "EAST", "", 288, "SD - TRANS", "SROSS",
+			"PPT", "NO", "NA", nil, "SROSS", parseTime("04-05-2022"),
+			"SROSS", parseTime("05-05-2022"), "", "", "NA",
+		).AddRow(
+			3996507, "PWRNSD", "SALE", parseTime("05-05-2022"),
+			10459, "MISO", "MIDWEST INDEPENDENT TRANSMISSION SYSTEM", "MIDWEST IN",
+			"SENA", "Shell Energy North America (US), L.P.", 10430, "011-KW-IS-10952",
+			"HOURLY", "EAST", "", 288, "SD - TRANS", "SROSS",
+			"PPT", "NO", "NA", nil, "SROSS", parseTime("04-05-2022"),
+			"SROSS", parseTime("05-05-2022"), "2022-05-05", "08:18:55 AM", "NA",
+		))
+
+	dealKeysArray := []int{3996506, 3996507}
+
+	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeysArray, []interface{}{}, "pv.pd_power_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucPowerTradeTermModelQ := getNucPowerTradeTermModelQuery(dealKeysQuery)
+
+	var namedParams []driver.Value
+	for _, value := range params {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParams = append(namedParams, valueNamed)
+	}
+
+	columns = []string{"PD_POWER_KEY", "VOLUME_SEQ", "DY_BEG_DAY", "DY_END_DAY", "PRICE_TYPE",
+		"PRICE", "VOLUME", "PPEP_PP_POOL", "PPEP_PEP_PRODUCT", "CTP_POINT_CODE", "FORMULA",
+		"SCH_SCHEDULE"}
+
+	mock.ExpectQuery(getNucPowerTradeTermModelQ).WithArgs(namedParams...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			3996506, 0, parseTime("05-05-2022"), parseTime("05-05-2022"), "I",
+			0, 0, "PJM", "HOURLY", "MISO", "[PJM DA LMP|40523629|HOURLY]",
+			"NERC",
+		).AddRow(
+			3996507, 0, parseTime("05-05-2022"), parseTime("05-05-2022"), "I",
+			0, 0, "MISOE", "HOURLY", "MISO/PJM", "[MISO DALMP|PJMC|HOURLY]",
+			"NERC",
+		))
+
+	formulaMap := make(map[int]int)
+	formulaMap[3996506] = 0
+	formulaMap[3996507] = 0
+
+	insNumQuery, _, err := oracle.CreateMapIntWhereQuery(formulaMap, []interface{}{}, "pv_pd_power_key", "pv_volume_seq")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucPowerTradeIndexModelQ := getNucPowerTradeIndexModelQuery(insNumQuery)
+
+	columns = []string{"PV_PD_POWER_KEY", "PV_VOLUME_SEQ", "PUBLICATION", "PUB_INDEX", "FREQUENCY"}
+
+	// this is a small hack to allow any values to be in any order for the mock,
+	// the issue is that the parameters for the query never will be in the same
+	// order because it comes from iterating over the keys of a map and Go always
+	// randmize them
+	mock.ExpectQuery(getNucPowerTradeIndexModelQ).WithArgs(AnyValueMatch{}, AnyValueMatch{},
+		AnyValueMatch{}, AnyValueMatch{}).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			3996506, 0, "PJM DA LMP", "40523629", "HOURLY",
+		).AddRow(
+			3996507, 0, "MISO DALMP", "PJMC", "HOURLY",
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx         context.Context
+		lastRunTime time.Time
+		tradeDate   time.Time
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:         context.TODO(),
+				lastRunTime: now,
+				tradeDate:   now,
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             3996506,
+					DealType:            "PWRNSD",
+					TotalQuantity:       0,
+					TransactionDate:     parseTime("05-05-2022"),
+					DnDirection:         "PURCHASE",
+					CyCompanyKey:        1601,
+					CompanyCode:         "PJM",
+					Company:             "PJM",
+					CompanyLongName:     "PJM INTERCONNECTION LLC",
+					HsHedgeKey:          "",
+					PrtPortfolio:        288,
+					Portfolio:           "SD - TRANS",
+					UrTrader:            "SROSS",
+					CyBrokerKey:         0,
+					Broker:              "NA",
+					DaRtIndicator:       "",
+					TzTimeZone:          "PPT",
+					TzExerciseZone:      "",
+					HasBroker:           "NO",
+					IbPrtPortfolio:      0,
+					IbPortfolio:         "",
+					IbUrTrader:          "",
+					ExercisedOptionKey:  0,
+					Contract:            "011-KW-BI-05701",
+					ConfirmFormat:       "HOURLY",
+					CyLegalEntityKey:    10430,
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					Region:              "EAST",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:          0,
+							BegDate:         parseTime("05-05-2022"),
+							EndDate:         parseTime("05-05-2022"),
+							Pool1:           "PJM",
+							Product1:        "HOURLY",
+							PointCode1:      "MISO",
+							Pool2:           "",
+							Product2:        "",
+							HolidaySchedule: "NERC",
+							PointCode2:      "",
+							Formula1:        "[PJM DA LMP|40523629|HOURLY]",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									VolSeq:      0,
+									Publication: "PJM DA LMP",
+									PubIndex:    "40523629",
+									Frequency:   "HOURLY",
+								},
+							},
+							Formula2:   "",
+							Indexes2:   nil,
+							PriceType:  "I",
+							FixedPrice: 0,
+							Volume:     0,
+						},
+					},
+					AnomalyTestResult:  "",
+					CreatedAt:          parseTime("04-05-2022"),
+					ModifiedAt:         parseTime("05-05-2022"),
+					CreatedBy:          "SROSS",
+					ModifiedBy:         "SROSS",
+					OptionType:         "",
+					ExecutionTime:      parseTime("01-01-0001"),
+					ExoticFlag:         "NA",
+					InteraffiliateFlag: "N",
+					StartDate:          parseTime("01-01-0001"),
+					EndDate:            parseTime("01-01-0001"),
+				},
+				{
+					DealKey:             3996507,
+					DealType:            "PWRNSD",
+					TotalQuantity:       0,
+					TransactionDate:     parseTime("05-05-2022"),
+					DnDirection:         "SALE",
+					CyCompanyKey:        10459,
+					CompanyCode:         "MIDWEST IN",
+					Company:             "MISO",
+					CompanyLongName:     "MIDWEST INDEPENDENT TRANSMISSION SYSTEM",
+					HsHedgeKey:          "",
+					PrtPortfolio:        288,
+					Portfolio:           "SD - TRANS",
+					UrTrader:            "SROSS",
+					CyBrokerKey:         0,
+					Broker:              "NA",
+					DaRtIndicator:       "",
+					TzTimeZone:          "PPT",
+					TzExerciseZone:      "",
+					HasBroker:           "NO",
+					IbPrtPortfolio:      0,
+					IbPortfolio:         "",
+					IbUrTrader:          "",
+					ExercisedOptionKey:  0,
+					Contract:            "011-KW-IS-10952",
+					ConfirmFormat:       "HOURLY",
+					CyLegalEntityKey:    10430,
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					Region:              "EAST",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:          0,
+							BegDate:         parseTime("05-05-2022"),
+							EndDate:         parseTime("05-05-2022"),
+							Pool1:           "MISOE",
+							Product1:        "HOURLY",
+							PointCode1:      "MISO/PJM",
+							Pool2:           "",
+							Product2:        "",
+							HolidaySchedule: "NERC",
+							PointCode2:      "",
+							Formula1:        "[MISO DALMP|PJMC|HOURLY]",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									VolSeq:      0,
+									Publication: "MISO DALMP",
+									PubIndex:    "PJMC",
+									Frequency:   "HOURLY",
+								},
+							},
+							Formula2:   "",
+							Indexes2:   nil,
+							PriceType:  "I",
+							FixedPrice: 0,
+							Volume:     0,
+						},
+					},
+					AnomalyTestResult:  "",
+					CreatedAt:          parseTime("04-05-2022"),
+					ModifiedAt:         parseTime("05-05-2022"),
+					CreatedBy:          "SROSS",
+					ModifiedBy:         "SROSS",
+					OptionType:         "",
+					ExecutionTime:      parseDateTime("2022-05-05", "08:18:55 AM"),
+					ExoticFlag:         "NA",
+					InteraffiliateFlag: "N",
+					StartDate:          parseTime("01-01-0001"),
+					EndDate:            parseTime("01-01-0001"),
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucPowerDealList(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucPowerDealList() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucPowerDealList() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucPowerDealList() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucPowerDealList() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucPowerDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucPowerDealList() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucPowerDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucPowerDealList() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucPowerDealList() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucPowerDealList() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucPowerDealList() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucPowerDealList() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucPowerSwapDealList(t *testing.T) {
+	now := time.Now()
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	columns := []string{"PSWAP_KEY", "DEAL_TYPE", "TOTAL_QUANTITY", "DN_DIRECTION",
+		"TRANSACTION_DATE", "CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE",
+		"LEGALENTITY", "LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "INTERAFFILIATE_FLAG",
+		"CONTRACTNUMBER", "CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO",
+		"PORTFOLIO", "UR_TRADER", "IB_PRT_PORTFOLIO", "IB_PORTFOLIO", "IB_UR_TRADER",
+		"TZ_TIME_ZONE", "HAS_BROKER", "BROKER", "OPTION_KEY", "PPEP_PP_POOL", "PPEP_PEP_PRODUCT",
+		"VOLUME", "FIXED_PRICE", "NONSTD_FLAG", "PI_PB_PUBLICATION", "PI_PUB_INDEX",
+		"FRQ_FREQUENCY", "FIX_PI_PB_PUBLICATION", "FIX_PI_PUB_INDEX", "FIX_FRQ_FREQUENCY",
+		"DY_BEG_DAY", "DY_END_DAY", "SCH_SCHEDULE", "CREATEDBY", "CREATE_DATE", "MODIFIEDBY",
+		"MODIFY_DATE", "EXECUTION_DATE", "EXECUTION_TIME", "EXOTIC_FLAG"}
+	mock.ExpectQuery(getNucPowerSwapDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			1388598, "PSWPS", 800, "PURCHASE",
+			parseTime("05-05-2022"), 18098, "FIMAT USA", "NEWEDGE USA LLC", "FIMAT USA",
+			"SENA", "Shell Energy North America (US), L.P.", 10430, "N",
+			"", "POWER SWAP", "SOUTH", "", 235,
+			"ERCOT B E FINANCIAL", "CWATSON", nil, "", "",
+			"CPT", "YES", "INTERXCHG", nil, "ERFNH", "STD ON",
+			50, 111, "Y", "ER RT LMP", "NORTH_HUB_AVG",
+			"HOURLY", nil, nil, nil,
+			parseTime("05-05-2022"), parseTime("05-05-2022"), "NERC", "LOB2", parseTime("05-05-2022"), "LOB2",
+			parseTime("05-05-2022"), "2022-05-05", "09:08:26 AM", "No",
+		).AddRow(
+			1388692, "PSWPS", 21600, "PURCHASE",
+			parseTime("05-05-2022"), 18098, "FIMAT USA", "NEWEDGE USA LLC", "FIMAT USA",
+			"SENA", "Shell Energy North America (US), L.P.", 10430, "N",
+			"", "POWER SWAP", "SOUTH", "", 235,
+			"ERCOT B E FINANCIAL", "CWATSON", nil, "", "",
+			"CPT", "YES", "INTERXCHG", nil, "ERFNH", "STD 2x16",
+			135, 30, "N", "ER RT LMP", "NORTH_HUB_AVG",
+			"HOURLY", nil, nil, nil,
+			parseTime("01-05-2025"), parseTime("31-05-2025"), "NERC", "LOB2", parseTime("05-05-2022"), "JKING",
+			parseTime("05-05-2022"), "2022-05-05", "11:05:35 AM", "No",
+		))
+
+	dealKeysArray := []int{1388598}
+
+	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeysArray, []interface{}{}, "pswp_pswap_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucPowerSwapDealTermModelQ := getNucPowerSwapDealTermModelQuery(dealKeysQuery)
+
+	var namedParams []driver.Value
+	for _, value := range params {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParams = append(namedParams, valueNamed)
+	}
+
+	columns = []string{"PSWP_PSWAP_KEY", "VOLUME_SEQ", "DY_BEG_DAY", "DY_END_DAY"}
+	mock.ExpectQuery(getNucPowerSwapDealTermModelQ).WithArgs(namedParams...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			1388598, 613, parseTime("05-03-2024"), parseTime("05-03-2024"),
+		).AddRow(
+			1388598, 614, parseTime("06-03-2024"), parseTime("06-03-2024"),
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx         context.Context
+		lastRunTime time.Time
+		tradeDate   time.Time
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:         context.TODO(),
+				lastRunTime: now,
+				tradeDate:   now,
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             1388598,
+					DealType:            "PSWPS",
+					TotalQuantity:       800,
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("05-05-2022"),
+					CyCompanyKey:        18098,
+					Company:             "FIMAT USA",
+					CompanyLongName:     "NEWEDGE USA LLC",
+					CompanyCode:         "FIMAT USA",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "",
+					ConfirmFormat:       "POWER SWAP",
+					Region:              "SOUTH",
+					PrtPortfolio:        235,
+					Portfolio:           "ERCOT B E FINANCIAL",
+					UrTrader:            "CWATSON",
+					IbPrtPortfolio:      0,
+					IbPortfolio:         "",
+					IbUrTrader:          "",
+					TzTimeZone:          "CPT",
+					HasBroker:           "YES",
+					Broker:              "INTERXCHG",
+					ExercisedOptionKey:  0,
+					StartDate:           parseTime("05-05-2022"),
+					EndDate:             parseTime("05-05-2022"),
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:     613,
+							Pool1:      "ERFNH",
+							Product1:   "STD ON",
+							Volume:     0,
+							FixedPrice: 0,
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER RT LMP",
+									PubIndex:    "NORTH_HUB_AVG",
+									Frequency:   "HOURLY",
+								},
+							},
+							BegDate:         parseTime("05-03-2024"),
+							EndDate:         parseTime("05-03-2024"),
+							HolidaySchedule: "NERC",
+						},
+						{
+							VolSeq:     614,
+							Pool1:      "ERFNH",
+							Product1:   "STD ON",
+							Volume:     0,
+							FixedPrice: 0,
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER RT LMP",
+									PubIndex:    "NORTH_HUB_AVG",
+									Frequency:   "HOURLY",
+								},
+							},
+							BegDate:         parseTime("06-03-2024"),
+							EndDate:         parseTime("06-03-2024"),
+							HolidaySchedule: "NERC",
+						},
+					},
+					CreatedBy:          "LOB2",
+					CreatedAt:          parseTime("05-05-2022"),
+					ModifiedBy:         "LOB2",
+					ModifiedAt:         parseTime("05-05-2022"),
+					ExecutionTime:      parseDateTime("2022-05-05", "09:08:26 AM"),
+					ExoticFlag:         "No",
+					InteraffiliateFlag: "N",
+				},
+				{
+					DealKey:             1388692,
+					DealType:            "PSWPS",
+					TotalQuantity:       21600,
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("05-05-2022"),
+					CyCompanyKey:        18098,
+					Company:             "FIMAT USA",
+					CompanyLongName:     "NEWEDGE USA LLC",
+					CompanyCode:         "FIMAT USA",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "",
+					ConfirmFormat:       "POWER SWAP",
+					Region:              "SOUTH",
+					PrtPortfolio:        235,
+					Portfolio:           "ERCOT B E FINANCIAL",
+					UrTrader:            "CWATSON",
+					IbPrtPortfolio:      0,
+					IbPortfolio:         "",
+					IbUrTrader:          "",
+					TzTimeZone:          "CPT",
+					HasBroker:           "YES",
+					Broker:              "INTERXCHG",
+					ExercisedOptionKey:  0,
+					StartDate:           parseTime("01-05-2025"),
+					EndDate:             parseTime("31-05-2025"),
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:     0,
+							Pool1:      "ERFNH",
+							Product1:   "STD 2x16",
+							Volume:     135,
+							FixedPrice: 30,
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER RT LMP",
+									PubIndex:    "NORTH_HUB_AVG",
+									Frequency:   "HOURLY",
+								},
+							},
+							BegDate:         parseTime("01-05-2025"),
+							EndDate:         parseTime("31-05-2025"),
+							HolidaySchedule: "NERC",
+						},
+					},
+					CreatedBy:          "LOB2",
+					CreatedAt:          parseTime("05-05-2022"),
+					ModifiedBy:         "JKING",
+					ModifiedAt:         parseTime("05-05-2022"),
+					ExecutionTime:      parseDateTime("2022-05-05", "11:05:35 AM"),
+					ExoticFlag:         "No",
+					InteraffiliateFlag: "N",
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucPowerSwapDealList(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealList() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealList() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealList() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealList() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealList() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealList() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealList() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealList() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealList() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealList() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucPowerOptionsDealList(t *testing.T) {
+	now := time.Now()
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	columns := []string{"POPTION_KEY", "DEAL_TYPE", "TOTAL_QUANTITY", "DN_DIRECTION",
+		"TRANSACTION_DATE", "CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE",
+		"LEGALENTITY", "LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "INTERAFFILIATE_FLAG",
+		"CONTRACTNUMBER", "CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO",
+		"PORTFOLIO", "UR_TRADER", "IB_PRT_PORTFOLIO", "IB_PORTFOLIO", "IB_UR_TRADER",
+		"TZ_TIME_ZONE", "TZ_EXERCISE_ZONE", "HAS_BROKER", "BROKER", "PPEP_PP_POOL",
+		"PPEP_PEP_PRODUCT", "CTP_POINT_CODE", "SETTLE_FORMULA", "DY_BEG_DAY", "DY_END_DAY",
+		"SCH_SCHEDULE", "VOLUME", "STRIKE_PRICE", "STRIKE_PRICE_TYPE", "STRIKE_FORMULA",
+		"CREATEDBY", "MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE", "EXECUTION_DATE",
+		"EXECUTION_TIME", "EXOTIC_FLAG",
+	}
+	mock.ExpectQuery(getNucPowerOptionsDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			43435, "POPTS", 17600, "SALE",
+			parseTime("23-05-2022"), 10322, "IBT", "INTERBOOK TRANSFER", "IBT",
+			"SENA", "Shell Energy North America (US), L.P.", 10430, "N",
+			"", "POWER OPT", "EAST", "", 99478,
+			"ERCOT_RETAIL", "GABDULLA", nil, "", "",
+			"CPT", "CPT", "NO", "NA", "ERCNZ",
+			"STD ON", "HB_NORTH", nil, parseTime("01-06-2022"), parseTime("30-06-2022"),
+			"NERC", -50, 100, "F", "",
+			"GABDULLA", "GABDULLA", parseTime("23-05-2022"), parseTime("23-05-2022"), "05/23/2022",
+			"12:00:00 AM", "No",
+		).AddRow(
+			43441, "POPTS", 35200, "PURCHASE",
+			parseTime("23-05-2022"), 10322, "IBT", "INTERBOOK TRANSFER", "IBT",
+			"SENA", "Shell Energy North America (US), L.P.", 10430, "N",
+			"", "POWER OPT", "EAST", "", 220,
+			"SOUTH OTHER HR", "GABDULLA", nil, "", "",
+			"CPT", "CPT", "NO", "NA", "ERCNZ",
+			"STD ON", "HB_NORTH", "[MISO RTLMP|CINERGY.HUB|HOURLY]", parseTime("01-06-2022"), parseTime("30-06-2022"),
+			"NERC", 100, 100, "F", "(10.5*[GD|HOU SHP CHNL|DAILY]) + 3.55",
+			"GABDULLA", "GABDULLA", parseTime("23-05-2022"), parseTime("23-05-2022"), "05/23/2022",
+			"12:00:00 AM", "No",
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx         context.Context
+		lastRunTime time.Time
+		tradeDate   time.Time
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:         context.TODO(),
+				lastRunTime: now,
+				tradeDate:   now,
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             43435,
+					DealType:            "POPTS",
+					TotalQuantity:       17600,
+					DnDirection:         "SALE",
+					TransactionDate:     parseTime("23-05-2022"),
+					CyCompanyKey:        10322,
+					Company:             "IBT",
+					CompanyLongName:     "INTERBOOK TRANSFER",
+					CompanyCode:         "IBT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					InteraffiliateFlag:  "N",
+					Contract:            "",
+					ConfirmFormat:       "POWER OPT",
+					Region:              "EAST",
+					HsHedgeKey:          "",
+					PrtPortfolio:        99478,
+					Portfolio:           "ERCOT_RETAIL",
+					UrTrader:            "GABDULLA",
+					IbPrtPortfolio:      0,
+					IbPortfolio:         "",
+					IbUrTrader:          "",
+					TzTimeZone:          "CPT",
+					TzExerciseZone:      "CPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					StartDate:           parseTime("01-06-2022"),
+					EndDate:             parseTime("30-06-2022"),
+					CreatedBy:           "GABDULLA",
+					ModifiedBy:          "GABDULLA",
+					CreatedAt:           parseTime("23-05-2022"),
+					ModifiedAt:          parseTime("23-05-2022"),
+					ExecutionTime:       parseDateTime("05/23/2022", "12:00:00 AM"),
+					ExoticFlag:          "No",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							Pool1:           "ERCNZ",
+							Product1:        "STD ON",
+							PointCode1:      "HB_NORTH",
+							BegDate:         parseTime("01-06-2022"),
+							EndDate:         parseTime("30-06-2022"),
+							HolidaySchedule: "NERC",
+							Volume:          -50,
+							FixedPrice:      100,
+							PriceType:       "F",
+						},
+					},
+				},
+				{
+					DealKey:             43441,
+					DealType:            "POPTS",
+					TotalQuantity:       35200,
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("23-05-2022"),
+					CyCompanyKey:        10322,
+					Company:             "IBT",
+					CompanyLongName:     "INTERBOOK TRANSFER",
+					CompanyCode:         "IBT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					InteraffiliateFlag:  "N",
+					Contract:            "",
+					ConfirmFormat:       "POWER OPT",
+					Region:              "EAST",
+					HsHedgeKey:          "",
+					PrtPortfolio:        220,
+					Portfolio:           "SOUTH OTHER HR",
+					UrTrader:            "GABDULLA",
+					IbPrtPortfolio:      0,
+					IbPortfolio:         "",
+					IbUrTrader:          "",
+					TzTimeZone:          "CPT",
+					TzExerciseZone:      "CPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					StartDate:           parseTime("01-06-2022"),
+					EndDate:             parseTime("30-06-2022"),
+					CreatedBy:           "GABDULLA",
+					ModifiedBy:          "GABDULLA",
+					CreatedAt:           parseTime("23-05-2022"),
+					ModifiedAt:          parseTime("23-05-2022"),
+					ExecutionTime:       parseDateTime("05/23/2022", "12:00:00 AM"),
+					ExoticFlag:          "No",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							Pool1:           "ERCNZ",
+							Product1:        "STD ON",
+							PointCode1:      "HB_NORTH",
+							BegDate:         parseTime("01-06-2022"),
+							EndDate:         parseTime("30-06-2022"),
+							HolidaySchedule: "NERC",
+							Volume:          100,
+							FixedPrice:      100,
+							PriceType:       "F",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "MISO RTLMP",
+									PubIndex:    "CINERGY.HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "GD",
+									PubIndex:    "HOU SHP CHNL",
+									Frequency:   "DAILY",
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucPowerOptionsDealList(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealList() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealList() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealList() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealList() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealList() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealList() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealList() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealList() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealList() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealList() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucCapacityDealList(t *testing.T) {
+	now := time.Now()
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	columns := []string{"CAPACITY_KEY", "DEAL_TYPE", "TOTAL_QUANTITY", "DN_DIRECTION",
+		"TRANSACTION_DATE", "CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME",
+		"COMPANYCODE", "LEGALENTITY", "LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "INTERAFFILIATE_FLAG",
+		"CONTRACTNUMBER", "CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO",
+		"PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE", "HAS_BROKER", "BROKER", "NON_STANDARD_FLAG",
+		"PRICE_TYPE", "CHARGE", "VOLUME", "ENERGY_FORMULA", "PPCP_PP_POOL", "PPCP_PCP_PRODUCT",
+		"CTP_POINT_CODE", "DY_BEG_DAY", "DY_END_DAY", "SCH_SCHEDULE", "CREATEDBY", "MODIFIEDBY",
+		"CREATE_DATE", "MODIFY_DATE", "EXECUTION_DATE", "EXECUTION_TIME"}
+	mock.ExpectQuery(getNucCapacityDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			1104940, "CAPCTY", 972.7, "SALE",
+			parseTime("05-05-2022"), 10322, "IBT", "INTERBOOK TRANSFER",
+			"IBT", "SENA", "Shell Energy North America (US), L.P.", 10430, "N",
+			"", "U-CAP", "SOUTH", "", 99690,
+			"Realtime 7", "COSULLIV", "CPT", "NO", "NA", "N",
+			"F", 0.1, 0, "([ER AS RRS|DA_RRS|HOURLY]*0.5)<CU>USD</CU><UT>MW</UT>", "NSRS", "STD ON",
+			"ERCOT", parseTime("04-05-2022"), parseTime("04-05-2022"), "NONE", "COSULLIV", "COSULLIV",
+			parseTime("05-05-2022"), parseTime("05-05-2022"), "", "",
+		).AddRow(
+			1107318, "CAPCTY", 0.06, "PURCHASE",
+			parseTime("05-05-2022"), 10296, "CAISO", "CALIFORNIA INDEPENDENT SYSTEMS OPERATION CORP DBA CALIF",
+			"CALF IND S", "SENA", "Shell Energy North America (US), L.P.", 10430, "N",
+			"011-KW-IS-06534", "STL CAPCTY", "WEST", "", 24,
+			"POWER REAL-TIME SAN DIEGO", "PCI_ALLO", "PPT", "NO", "NA", "Y",
+			"F", 0, 0, nil, "WSCC", "HOURLY",
+			"CFE-IV-MX", parseTime("05-05-2022"), parseTime("05-05-2022"), "NERC", "PCI_GSMS", "PCI_GSMS",
+			parseTime("18-05-2022"), parseTime("18-05-2022"), "2022-05-05", "11:36:12 AM",
+		).AddRow(
+			1105338, "CAPCTY", 0.24, "PURCHASE",
+			parseTime("05-05-2022"), 10296, "CAISO", "CALIFORNIA INDEPENDENT SYSTEMS OPERATION CORP DBA CALIF",
+			"CALF IND S", "SENA", "Shell Energy North America (US), L.P.", 10430, "N",
+			"011-KW-IS-06534", "STL CAPCTY", "WEST", "", 301,
+			"SETTLEMENT BRIDGE CAISO - DA", "PCI_ALLO", "PPT", "NO", "NA", "Y",
+			"F", 0, 0, "([ER AS RRS|DA_RRS|HOURLY]*0.85)<CU>USD</CU><UT>MW</UT>", "WSCC", "HOURLY",
+			"PGAE-APND", parseTime("05-05-2022"), parseTime("05-05-2022"), "NERC", "PCI_GSMS", "PCI_GSMS",
+			parseTime("06-05-2022"), parseTime("06-05-2022"), "05/07/2022", "12:00:00 AM",
+		))
+
+	dealKeysArray := []int{1107318, 1105338}
+
+	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeysArray, []interface{}{}, "cpd_capacity_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucCapacityDealTermModelQ := getNucCapacityDealTermModelQuery(dealKeysQuery)
+
+	var namedParams []driver.Value
+	for _, value := range params {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParams = append(namedParams, valueNamed)
+	}
+
+	columns = []string{"CPD_CAPACITY_KEY", "DY_BEG_DAY", "DY_END_DAY"}
+	mock.ExpectQuery(getNucCapacityDealTermModelQ).WithArgs(namedParams...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			1105338, parseTime("05-05-2022"), parseTime("05-05-2022"),
+		).AddRow(
+			1107318, parseTime("04-04-2022"), parseTime("04-04-2022"),
+		).AddRow(
+			1107318, parseTime("05-05-2022"), parseTime("05-05-2022"),
+		))
+
+	// the standard deal 1104940 has a formula but no term to put its indexes in
+	formulaDealKeys := []int{1104940, 1105338}
+
+	formulaDealKeysQuery, params, err := oracle.CreateInQueryInt(formulaDealKeys, []interface{}{}, "cpd_capacity_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucCapacityDealIndexModelQ := getNucCapacityDealIndexModelQuery(formulaDealKeysQuery)
+
+	namedParams = nil
+	for _, value := range params {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParams = append(namedParams, valueNamed)
+	}
+
+	columns = []string{"CPD_CAPACITY_KEY", "PUBLICATION", "PUB_INDEX", "FREQUENCY"}
+	mock.ExpectQuery(getNucCapacityDealIndexModelQ).WithArgs(namedParams...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			1104940, "ER AS RRS", "DA_RRS", "HOURLY",
+		).AddRow(
+			1105338, "ER AS RRS", "DA_RRS", "HOURLY",
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx         context.Context
+		lastRunTime time.Time
+		tradeDate   time.Time
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:         context.TODO(),
+				lastRunTime: now,
+				tradeDate:   now,
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             1104940,
+					DealType:            "CAPCTY",
+					TotalQuantity:       972.7,
+					DnDirection:         "SALE",
+					TransactionDate:     parseTime("05-05-2022"),
+					CyCompanyKey:        10322,
+					Company:             "IBT",
+					CompanyLongName:     "INTERBOOK TRANSFER",
+					CompanyCode:         "IBT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					InteraffiliateFlag:  "N",
+					Contract:            "",
+					ConfirmFormat:       "U-CAP",
+					Region:              "SOUTH",
+					HsHedgeKey:          "",
+					PrtPortfolio:        99690,
+					Portfolio:           "Realtime 7",
+					UrTrader:            "COSULLIV",
+					TzTimeZone:          "CPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					StartDate:           parseTime("04-05-2022"),
+					EndDate:             parseTime("04-05-2022"),
+					CreatedBy:           "COSULLIV",
+					ModifiedBy:          "COSULLIV",
+					CreatedAt:           parseTime("05-05-2022"),
+					ModifiedAt:          parseTime("05-05-2022"),
+					ExoticFlag:          "NA",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:          0,
+							PriceType:       "F",
+							FixedPrice:      0.1,
+							Volume:          0,
+							Pool1:           "NSRS",
+							Product1:        "STD ON",
+							PointCode1:      "ERCOT",
+							BegDate:         parseTime("04-05-2022"),
+							EndDate:         parseTime("04-05-2022"),
+							HolidaySchedule: "NONE",
+							Formula1:        "([ER AS RRS|DA_RRS|HOURLY]*0.5)<CU>USD</CU><UT>MW</UT>",
+						},
+					},
+				},
+				{
+					DealKey:             1107318,
+					DealType:            "CAPCTY",
+					TotalQuantity:       0.06,
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("05-05-2022"),
+					CyCompanyKey:        10296,
+					Company:             "CAISO",
+					CompanyLongName:     "CALIFORNIA INDEPENDENT SYSTEMS OPERATION CORP DBA CALIF",
+					CompanyCode:         "CALF IND S",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					InteraffiliateFlag:  "N",
+					Contract:            "011-KW-IS-06534",
+					ConfirmFormat:       "STL CAPCTY",
+					Region:              "WEST",
+					HsHedgeKey:          "",
+					PrtPortfolio:        24,
+					Portfolio:           "POWER REAL-TIME SAN DIEGO",
+					UrTrader:            "PCI_ALLO",
+					TzTimeZone:          "PPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					StartDate:           parseTime("05-05-2022"),
+					EndDate:             parseTime("05-05-2022"),
+					CreatedBy:           "PCI_GSMS",
+					ModifiedBy:          "PCI_GSMS",
+					CreatedAt:           parseTime("18-05-2022"),
+					ModifiedAt:          parseTime("18-05-2022"),
+					ExecutionTime:       parseDateTime("2022-05-05", "11:36:12 AM"),
+					ExoticFlag:          "NA",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:          0,
+							PriceType:       "F",
+							Pool1:           "WSCC",
+							Product1:        "HOURLY",
+							PointCode1:      "CFE-IV-MX",
+							HolidaySchedule: "NERC",
+							BegDate:         parseTime("04-04-2022"),
+							EndDate:         parseTime("04-04-2022"),
+						},
+						{
+							VolSeq:          1,
+							PriceType:       "F",
+							Pool1:           "WSCC",
+							Product1:        "HOURLY",
+							PointCode1:      "CFE-IV-MX",
+							HolidaySchedule: "NERC",
+							BegDate:         parseTime("05-05-2022"),
+							EndDate:         parseTime("05-05-2022"),
+						},
+					},
+				},
+				{
+					DealKey:             1105338,
+					DealType:            "CAPCTY",
+					TotalQuantity:       0.24,
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("05-05-2022"),
+					CyCompanyKey:        10296,
+					Company:             "CAISO",
+					CompanyLongName:     "CALIFORNIA INDEPENDENT SYSTEMS OPERATION CORP DBA CALIF",
+					CompanyCode:         "CALF IND S",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					InteraffiliateFlag:  "N",
+					Contract:            "011-KW-IS-06534",
+					ConfirmFormat:       "STL CAPCTY",
+					Region:              "WEST",
+					HsHedgeKey:          "",
+					PrtPortfolio:        301,
+					Portfolio:           "SETTLEMENT BRIDGE CAISO - DA",
+					UrTrader:            "PCI_ALLO",
+					TzTimeZone:          "PPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					StartDate:           parseTime("05-05-2022"),
+					EndDate:             parseTime("05-05-2022"),
+					CreatedBy:           "PCI_GSMS",
+					ModifiedBy:          "PCI_GSMS",
+					CreatedAt:           parseTime("06-05-2022"),
+					ModifiedAt:          parseTime("06-05-2022"),
+					ExecutionTime:       parseDateTime("05/07/2022", "12:00:00 AM"),
+					ExoticFlag:          "NA",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:          0,
+							PriceType:       "F",
+							Pool1:           "WSCC",
+							Product1:        "HOURLY",
+							PointCode1:      "PGAE-APND",
+							HolidaySchedule: "NERC",
+							BegDate:         parseTime("05-05-2022"),
+							EndDate:         parseTime("05-05-2022"),
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER AS RRS",
+									PubIndex:    "DA_RRS",
+									Frequency:   "HOURLY",
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucCapacityDealList(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucCapacityDealList() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucCapacityDealList() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucCapacityDealList() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucCapacityDealList() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucCapacityDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucCapacityDealList() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucCapacityDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucCapacityDealList() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucCapacityDealList() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucCapacityDealList() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucCapacityDealList() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucCapacityDealList() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucPTPDealList(t *testing.T) {
+	now := time.Now()
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	columns := []string{"PTP_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
+		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY",
+		"LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT",
+		"REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE",
+		"HAS_BROKER", "BROKER", "DY_FLOW_DAY", "PPEP_PP_POOL", "PPEP_PEP_PRODUCT",
+		"PUBLICATION1", "PUB_INDEX1", "PUBLICATION2", "PUB_INDEX2", "CREATEDBY",
+		"MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE"}
+	mock.ExpectQuery(getNucPTPDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			273387, "PTP", "PURCHASE", parseTime("05-05-2022"),
+			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "", "",
+			"SOUTH", "", 99643, "ERCOT CASH NODAL", "JPROMU", "CPT",
+			"NO", "NA", parseTime("06-05-2022"), "ERCRR", "HOURLY",
+			"ER DA LMP", "NORTH_HUB", "ER RT LMP", "SOUTH_HUB", "PCI_GSMS",
+			"PCI_GSMS", parseTime("06-05-2022"), parseTime("06-05-2022"),
+		).AddRow(
+			273385, "PTP", "PURCHASE", parseTime("05-05-2022"),
+			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "", "",
+			"SOUTH", "", 99643, "ERCOT CASH NODAL", "JPROMU", "CPT",
+			"NO", "NA", parseTime("06-05-2022"), "ERCRR", "HOURLY",
+			"ER DA LMP", "NORTH_HUB", "ER RT LMP", "NORTH_ZONE", "PCI_GSMS",
+			"PCI_GSMS", parseTime("06-05-2022"), parseTime("06-05-2022"),
+		).AddRow(
+			273377, "PTP", "PURCHASE", parseTime("05-05-2022"),
+			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "", "",
+			"SOUTH", "", 99643, "ERCOT CASH NODAL", "JPROMU", "CPT",
+			"NO", "NA", parseTime("06-05-2022"), "ERCRR", "HOURLY",
+			"ER DA LMP", "HOUSTON_HUB", "ER RT LMP", "HOUSTON_ZONE", "PCI_GSMS",
+			"PCI_GSMS", parseTime("06-05-2022"), parseTime("06-05-2022"),
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx         context.Context
+		lastRunTime time.Time
+		tradeDate   time.Time
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:         context.TODO(),
+				lastRunTime: now,
+				tradeDate:   now,
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             273387,
+					DealType:            "PTP",
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("05-05-2022"),
+					CyCompanyKey:        10171,
+					Company:             "ERCOT",
+					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
+					CompanyCode:         "ERCOT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "",
+					ConfirmFormat:       "",
+					Region:              "SOUTH",
+					HsHedgeKey:          "",
+					PrtPortfolio:        99643,
+					Portfolio:           "ERCOT CASH NODAL",
+					UrTrader:            "JPROMU",
+					TzTimeZone:          "CPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:   0,
+							BegDate:  parseTime("06-05-2022"),
+							EndDate:  parseTime("06-05-2022"),
+							Pool1:    "ERCRR",
+							Product1: "HOURLY",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER DA LMP",
+									PubIndex:    "NORTH_HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER RT LMP",
+									PubIndex:    "SOUTH_HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+						},
+					},
+					CreatedBy:          "PCI_GSMS",
+					ModifiedBy:         "PCI_GSMS",
+					CreatedAt:          parseTime("06-05-2022"),
+					ModifiedAt:         parseTime("06-05-2022"),
+					InteraffiliateFlag: "N",
+				},
+				{
+					DealKey:             273385,
+					DealType:            "PTP",
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("05-05-2022"),
+					CyCompanyKey:        10171,
+					Company:             "ERCOT",
+					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
+					CompanyCode:         "ERCOT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "",
+					ConfirmFormat:       "",
+					Region:              "SOUTH",
+					HsHedgeKey:          "",
+					PrtPortfolio:        99643,
+					Portfolio:           "ERCOT CASH NODAL",
+					UrTrader:            "JPROMU",
+					TzTimeZone:          "CPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:   0,
+							BegDate:  parseTime("06-05-2022"),
+							EndDate:  parseTime("06-05-2022"),
+							Pool1:    "ERCRR",
+							Product1: "HOURLY",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER DA LMP",
+									PubIndex:    "NORTH_HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER RT LMP",
+									PubIndex:    "NORTH_ZONE",
+									Frequency:   "HOURLY",
+								},
+							},
+						},
+					},
+					CreatedBy:          "PCI_GSMS",
+					ModifiedBy:         "PCI_GSMS",
+					CreatedAt:          parseTime("06-05-2022"),
+					ModifiedAt:         parseTime("06-05-2022"),
+					InteraffiliateFlag: "N",
+				},
+				{
+					DealKey:             273377,
+					DealType:            "PTP",
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("05-05-2022"),
+					CyCompanyKey:        10171,
+					Company:             "ERCOT",
+					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
+					CompanyCode:         "ERCOT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "",
+					ConfirmFormat:       "",
+					Region:              "SOUTH",
+					HsHedgeKey:          "",
+					PrtPortfolio:        99643,
+					Portfolio:           "ERCOT CASH NODAL",
+					UrTrader:            "JPROMU",
+					TzTimeZone:          "CPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:   0,
+							BegDate:  parseTime("06-05-2022"),
+							EndDate:  parseTime("06-05-2022"),
+							Pool1:    "ERCRR",
+							Product1: "HOURLY",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER DA LMP",
+									PubIndex:    "HOUSTON_HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER RT LMP",
+									PubIndex:    "HOUSTON_ZONE",
+									Frequency:   "HOURLY",
+								},
+							},
+						},
+					},
+					CreatedBy:          "PCI_GSMS",
+					ModifiedBy:         "PCI_GSMS",
+					CreatedAt:          parseTime("06-05-2022"),
+					ModifiedAt:         parseTime("06-05-2022"),
+					InteraffiliateFlag: "N",
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucPTPDealList(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucPTPDealList() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucPTPDealList() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucPTPDealList() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucPTPDealList() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucPTPDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucPTPDealList() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucPTPDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucPTPDealList() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucPTPDealList() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucPTPDealList() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucPTPDealList() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucPTPDealList() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucEmissionDealList(t *testing.T) {
+	now := time.Now()
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	columns := []string{"EMISSION_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
+		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY",
+		"LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT",
+		"REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE",
+		"HAS_BROKER", "BROKER", "CREATEDBY", "MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE",
+		"EXECUTION_DATE", "EXECUTION_TIME"}
+	mock.ExpectQuery(getNucEmissionDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			61150, "EMSSN", "SALE", parseTime("05-05-2022"),
+			17038, "CSU", "THE BOARD OF TRUSTEES OF THE CALIFORNIA STATE UNIV", "CSU", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "013-KW-SU-21400", "EMISSION",
+			"WEST", "CandI CRED", 99297, "WEST ENVIRON - CA RETAIL", "SGAPPY", "",
+			"NO", "NA", "SGAPPY", "SGAPPY", parseTime("05-05-2022"), parseTime("05-05-2022"),
+			"", "",
+		).AddRow(
+			61151, "EMSSN", "SALE", parseTime("05-05-2022"),
+			17038, "CSU", "THE BOARD OF TRUSTEES OF THE CALIFORNIA STATE UNIV", "CSU", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "013-KW-SU-21400", "EMISSION",
+			"WEST", "CandI CRED", 99297, "WEST ENVIRON - CA RETAIL", "SGAPPY", "",
+			"NO", "NA", "SGAPPY", "SGAPPY", parseTime("05-05-2022"), parseTime("05-05-2022"),
+			"", "",
+		))
+
+	dealKeysArray := []int{61150, 61151}
+
+	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeysArray, []interface{}{}, "pv.ed_emission_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucCapacityDealTermModelQ := getNucEmissionDealListTermModelQuery(dealKeysQuery)
+
+	var namedParams []driver.Value
+	for _, value := range params {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParams = append(namedParams, valueNamed)
+	}
+
+	columns = []string{"ED_EMISSION_KEY", "VOLUME_SEQ", "DY_BEG_DAY", "DY_END_DAY",
+		"PRICE_TYPE", "PRICE", "VOLUME", "EPDT_EMISSION_PRODUCT", "CTP_POINT_CODE",
+		"FORMULA"}
+	mock.ExpectQuery(getNucCapacityDealTermModelQ).WithArgs(namedParams...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			61150, 1, parseTime("01-08-2023"), parseTime("31-08-2023"),
+			"F", 16.85, 10662, "CA REC", "MID-C",
+			"[OPIS|CELRINC|DAILY] *0.95",
+		).AddRow(
+			61151, 1, parseTime("01-09-2023"), parseTime("30-09-2023"),
+			"F", 16.85, 11117, "CA REC", "MID-C",
+			"",
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx         context.Context
+		lastRunTime time.Time
+		tradeDate   time.Time
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "normal run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:         context.TODO(),
+				lastRunTime: now,
+				tradeDate:   now,
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             61150,
+					DealType:            "EMSSN",
+					DnDirection:         "SALE",
+					TransactionDate:     parseTime("05-05-2022"),
+					CyCompanyKey:        17038,
+					Company:             "CSU",
+					CompanyLongName:     "THE BOARD OF TRUSTEES OF THE CALIFORNIA STATE UNIV",
+					CompanyCode:         "CSU",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "013-KW-SU-21400",
+					ConfirmFormat:       "EMISSION",
+					Region:              "WEST",
+					HsHedgeKey:          "CandI CRED",
+					PrtPortfolio:        99297,
+					Portfolio:           "WEST ENVIRON - CA RETAIL",
+					UrTrader:            "SGAPPY",
+					TzTimeZone:          "",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					CreatedBy:           "SGAPPY",
+					ModifiedBy:          "SGAPPY",
+					CreatedAt:           parseTime("05-05-2022"),
+					ModifiedAt:          parseTime("05-05-2022"),
+					ExoticFlag:          "NA",
+					InteraffiliateFlag:  "N",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:     1,
+							BegDate:    parseTime("01-08-2023"),
+							EndDate:    parseTime("31-08-2023"),
+							PriceType:  "F",
+							FixedPrice: 16.85,
+							Volume:     10662,
+							Product1:   "CA REC",
+							PointCode1: "MID-C",
+							Formula1:   "[OPIS|CELRINC|DAILY] *0.95",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "OPIS",
+									PubIndex:    "CELRINC",
+									Frequency:   "DAILY",
+								},
+							},
+						},
+					},
+				},
+				{
+					DealKey:             61151,
+					DealType:            "EMSSN",
+					DnDirection:         "SALE",
+					TransactionDate:     parseTime("05-05-2022"),
+					CyCompanyKey:        17038,
+					Company:             "CSU",
+					CompanyLongName:     "THE BOARD OF TRUSTEES OF THE CALIFORNIA STATE UNIV",
+					CompanyCode:         "CSU",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "013-KW-SU-21400",
+					ConfirmFormat:       "EMISSION",
+					Region:              "WEST",
+					HsHedgeKey:          "CandI CRED",
+					PrtPortfolio:        99297,
+					Portfolio:           "WEST ENVIRON - CA RETAIL",
+					UrTrader:            "SGAPPY",
+					TzTimeZone:          "",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					CreatedBy:           "SGAPPY",
+					ModifiedBy:          "SGAPPY",
+					CreatedAt:           parseTime("05-05-2022"),
+					ModifiedAt:          parseTime("05-05-2022"),
+					ExoticFlag:          "NA",
+					InteraffiliateFlag:  "N",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:     1,
+							BegDate:    parseTime("01-09-2023"),
+							EndDate:    parseTime("30-09-2023"),
+							PriceType:  "F",
+							FixedPrice: 16.85,
+							Volume:     11117,
+							Product1:   "CA REC",
+							PointCode1: "MID-C",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucEmissionDealList(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucEmissionDealList() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucEmissionDealList() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucEmissionDealList() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucEmissionDealList() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucEmissionDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucEmissionDealList() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucEmissionDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucEmissionDealList() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucEmissionDealList() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucEmissionDealList() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucEmissionDealList() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucEmissionDealList() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucEmissionOptionDealList(t *testing.T) {
+	now := time.Now()
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	columns := []string{"EOPTION_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
+		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY",
+		"LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT", "REGION",
+		"HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE",
+		"HAS_BROKER", "BROKER", "ED_EMISSION_KEY", "STRIKE_PRICE", "VOLUME", "CREATEDBY",
+		"MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE", "EXECUTION_DATE", "EXECUTION_TIME"}
+	mock.ExpectQuery(getNucEmissionOptionDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			12706, "EMOPTS", "SALE", parseTime("27-01-2016"),
+			16370, "NEWFIN", "NEWEDGE FINANCIAL INC", "NEWFIN", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "", "EMSSN OPT", "EAST",
+			"REALIZED", 99444, "HOUSTON EMISSIONS FIN US", "DEKING", "EST",
+			"YES", "TFS", 31321, 7.5, -250000, "DEKING",
+			"NXTGEN", parseTime("27-01-2016"), parseTime("14-12-2016"), "01/27/2016", "10:07:59 AM",
+		).AddRow(
+			12711, "EMOPTS", "PURCHASE", parseTime("27-01-2016"),
+			16370, "NEWFIN", "NEWEDGE FINANCIAL INC", "NEWFIN", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "", "EMSSN OPT", "EAST",
+			"REALIZED", 99444, "HOUSTON EMISSIONS FIN US", "DEKING", "EST",
+			"NO", "NA", 31326, 8, 700000, "DEKING",
+			"NXTGEN", parseTime("27-01-2016"), parseTime("14-12-2016"), "01/27/2016", "10:12:59 AM",
+		))
+
+	dealKeysArray := []int{31321, 31326}
+
+	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeysArray, []interface{}{}, "pv.ed_emission_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucEmissionOptionDealTermListQ := getNucEmissionOptionDealTermListQuery(dealKeysQuery)
+
+	var namedParams []driver.Value
+	for _, value := range params {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParams = append(namedParams, valueNamed)
+	}
+
+	columns = []string{"ED_EMISSION_KEY", "VOLUME_SEQ", "DY_BEG_DAY", "DY_END_DAY",
+		"CTP_POINT_CODE", "EPDT_EMISSION_PRODUCT"}
+	mock.ExpectQuery(getNucEmissionOptionDealTermListQ).WithArgs(namedParams...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			31321, 1, parseTime("01-04-2010"), parseTime("30-04-2010"), "MID-C", "CA REC",
+		).AddRow(
+			31326, 1, parseTime("01-04-2010"), parseTime("30-04-2010"), "MID-C", "CA REC",
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx         context.Context
+		lastRunTime time.Time
+		tradeDate   time.Time
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:         context.TODO(),
+				lastRunTime: now,
+				tradeDate:   now,
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             12706,
+					DealType:            "EMOPTS",
+					DnDirection:         "SALE",
+					TransactionDate:     parseTime("27-01-2016"),
+					CyCompanyKey:        16370,
+					Company:             "NEWFIN",
+					CompanyLongName:     "NEWEDGE FINANCIAL INC",
+					CompanyCode:         "NEWFIN",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "",
+					ConfirmFormat:       "EMSSN OPT",
+					Region:              "EAST",
+					HsHedgeKey:          "REALIZED",
+					PrtPortfolio:        99444,
+					Portfolio:           "HOUSTON EMISSIONS FIN US",
+					UrTrader:            "DEKING",
+					TzTimeZone:          "EST",
+					HasBroker:           "YES",
+					Broker:              "TFS",
+					CreatedBy:           "DEKING",
+					ModifiedBy:          "NXTGEN",
+					CreatedAt:           parseTime("27-01-2016"),
+					ModifiedAt:          parseTime("14-12-2016"),
+					ExecutionTime:       parseDateTime("01/27/2016", "10:07:59 AM"),
+					ExoticFlag:          "NA",
+					InteraffiliateFlag:  "N",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:     1,
+							FixedPrice: 7.5,
+							Volume:     -250000,
+							BegDate:    parseTime("01-04-2010"),
+							EndDate:    parseTime("30-04-2010"),
+							PointCode1: "MID-C",
+							Product1:   "CA REC",
+						},
+					},
+				},
+				{
+					DealKey:             12711,
+					DealType:            "EMOPTS",
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("27-01-2016"),
+					CyCompanyKey:        16370,
+					Company:             "NEWFIN",
+					CompanyLongName:     "NEWEDGE FINANCIAL INC",
+					CompanyCode:         "NEWFIN",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "",
+					ConfirmFormat:       "EMSSN OPT",
+					Region:              "EAST",
+					HsHedgeKey:          "REALIZED",
+					PrtPortfolio:        99444,
+					Portfolio:           "HOUSTON EMISSIONS FIN US",
+					UrTrader:            "DEKING",
+					TzTimeZone:          "EST",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					CreatedBy:           "DEKING",
+					ModifiedBy:          "NXTGEN",
+					CreatedAt:           parseTime("27-01-2016"),
+					ModifiedAt:          parseTime("14-12-2016"),
+					ExecutionTime:       parseDateTime("01/27/2016", "10:12:59 AM"),
+					ExoticFlag:          "NA",
+					InteraffiliateFlag:  "N",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:     1,
+							FixedPrice: 8,
+							Volume:     700000,
+							BegDate:    parseTime("01-04-2010"),
+							EndDate:    parseTime("30-04-2010"),
+							PointCode1: "MID-C",
+							Product1:   "CA REC",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucEmissionOptionDealList(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealList() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealList() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealList() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealList() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealList() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealList() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealList() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealList() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealList() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealList() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucSpreadOptionsDealList(t *testing.T) {
+	now := time.Now()
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	columns := []string{"SPREAD_OPTION_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
+		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY", "LEGALENTITYLONGNAME",
+		"CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO",
+		"PORTFOLIO", "UR_TRADER", "IB_PRT_PORTFOLIO", "IB_PORTFOLIO", "IB_UR_TRADER", "TZ_TIME_ZONE",
+		"HAS_BROKER", "BROKER", "DY_BEG_DAY1", "DY_END_DAY1", "SCH_SCHEDULE", "FORMULA1", "FORMULA2",
+		"PPEP_PP_POOL1", "PPEP_PP_POOL2", "PPEP_PEP_PRODUCT1", "PPEP_PEP_PRODUCT2", "VOLUME",
+		"STRIKE_PRICE", "CREATEDBY", "MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE", "EXECUTION_DATE",
+		"EXECUTION_TIME", "EXOTIC_FLAG", "POINT_CODE"}
+	mock.ExpectQuery(getNucSpreadOptionsDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			77283, "SPDOPT", "PURCHASE", parseTime("25-05-2022"),
+			21589, "MIDTOPO", "MIDDLETOWN POWER LLC", "MIDTOPO", "SENA", "Shell Energy North America (US), L.P.",
+			10430, "013-KW-BI-32897", "DF7EXPRESO", "EAST", "FOGECA", 99896,
+			"FOGECA", "GGULYASS", nil, "", "", "",
+			"NO", "NA", parseTime("01-06-2022"), parseTime("30-06-2022"), "NERC", "[NE DA LMP|4000|HOURLY]", "[GD|TENN Z6 SOUTH|DAILY]",
+			"XMIDX", "", "STD 7x24", "", 226,
+			11, "GGULYASS", "GGULYASS", parseTime("26-05-2022"), parseTime("26-05-2022"), "05/26/2022",
+			"", "Yes", "NOT APPLICABLE",
+		).AddRow(
+			77294, "SPDOPT", "PURCHASE", parseTime("25-05-2022"),
+			21589, "MIDTOPO", "MIDDLETOWN POWER LLC", "MIDTOPO", "SENA", "Shell Energy North America (US), L.P.",
+			10430, "013-KW-BI-32897", "DF7EXPRESO", "EAST", "FOGECA", 99896,
+			"FOGECA", "GGULYASS", nil, "", "", "",
+			"NO", "NA", parseTime("01-10-2022"), parseTime("31-10-2022"), "NERC", "([PJM DA LMP|34887979|HOURLY])", "([IF|MICHCON LEU|MONTHLY] *1.0163)",
+			"XMIDX", "", "STD 7x24", "", 117,
+			10.65, "GGULYASS", "GGULYASS", parseTime("26-05-2022"), parseTime("26-05-2022"), "05/26/2022",
+			"", "Yes", "NOT APPLICABLE",
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx         context.Context
+		lastRunTime time.Time
+		tradeDate   time.Time
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:         context.TODO(),
+				lastRunTime: now,
+				tradeDate:   now,
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             77283,
+					DealType:            "SPDOPT",
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("25-05-2022"),
+					CyCompanyKey:        21589,
+					Company:             "MIDTOPO",
+					CompanyLongName:     "MIDDLETOWN POWER LLC",
+					CompanyCode:         "MIDTOPO",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "013-KW-BI-32897",
+					ConfirmFormat:       "DF7EXPRESO",
+					Region:              "EAST",
+					HsHedgeKey:          "FOGECA",
+					PrtPortfolio:        99896,
+					Portfolio:           "FOGECA",
+					UrTrader:            "GGULYASS",
+					IbPrtPortfolio:      0,
+					IbPortfolio:         "",
+					IbUrTrader:          "",
+					TzTimeZone:          "",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					StartDate:           parseTime("01-06-2022"),
+					EndDate:             parseTime("30-06-2022"),
+					CreatedBy:           "GGULYASS",
+					ModifiedBy:          "GGULYASS",
+					CreatedAt:           parseTime("26-05-2022"),
+					ModifiedAt:          parseTime("26-05-2022"),
+					ExecutionTime:       parseDateTime("05/26/2022", ""),
+					ExoticFlag:          "Yes",
+					InteraffiliateFlag:  "N",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							HolidaySchedule: "NERC",
+							Pool1:           "XMIDX",
+							Pool2:           "",
+							Product1:        "STD 7x24",
+							Product2:        "",
+							Volume:          226,
+							FixedPrice:      11,
+							PointCode1:      "NOT APPLICABLE",
+							BegDate:         parseTime("01-06-2022"),
+							EndDate:         parseTime("30-06-2022"),
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "NE DA LMP",
+									PubIndex:    "4000",
+									Frequency:   "HOURLY",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "GD",
+									PubIndex:    "TENN Z6 SOUTH",
+									Frequency:   "DAILY",
+								},
+							},
+						},
+					},
+				},
+				{
+					DealKey:             77294,
+					DealType:            "SPDOPT",
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("25-05-2022"),
+					CyCompanyKey:        21589,
+					Company:             "MIDTOPO",
+					CompanyLongName:     "MIDDLETOWN POWER LLC",
+					CompanyCode:         "MIDTOPO",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "013-KW-BI-32897",
+					ConfirmFormat:       "DF7EXPRESO",
+					Region:              "EAST",
+					HsHedgeKey:          "FOGECA",
+					PrtPortfolio:        99896,
+					Portfolio:           "FOGECA",
+					UrTrader:            "GGULYASS",
+					IbPrtPortfolio:      0,
+					IbPortfolio:         "",
+					IbUrTrader:          "",
+					TzTimeZone:          "",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					StartDate:           parseTime("01-10-2022"),
+					EndDate:             parseTime("31-10-2022"),
+					CreatedBy:           "GGULYASS",
+					ModifiedBy:          "GGULYASS",
+					CreatedAt:           parseTime("26-05-2022"),
+					ModifiedAt:          parseTime("26-05-2022"),
+					ExecutionTime:       parseDateTime("05/26/2022", ""),
+					ExoticFlag:          "Yes",
+					InteraffiliateFlag:  "N",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							HolidaySchedule: "NERC",
+							Pool1:           "XMIDX",
+							Pool2:           "",
+							Product1:        "STD 7x24",
+							Product2:        "",
+							Volume:          117,
+							FixedPrice:      10.65,
+							PointCode1:      "NOT APPLICABLE",
+							BegDate:         parseTime("01-10-2022"),
+							EndDate:         parseTime("31-10-2022"),
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "PJM DA LMP",
+									PubIndex:    "34887979",
+									Frequency:   "HOURLY",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "IF",
+									PubIndex:    "MICHCON LEU",
+									Frequency:   "MONTHLY",
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucSpreadOptionsDealList(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealList() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealList() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealList() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealList() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealList() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealList() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealList() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealList() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealList() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealList() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucHeatRateSwapsDealList(t *testing.T) {
+	now := time.Now()
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	columns := []string{"HRSWPS_KEY", "DEAL_TYPE", "TOTAL_QUANTITY", "DN_DIRECTION",
+		"TRANSACTION_DATE", "CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE",
+		"LEGALENTITY", "LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "INTERAFFILIATE_FLAG",
+		"CONTRACTNUMBER", "CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO",
+		"PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE", "HAS_BROKER", "BROKER", "OPTION_KEY",
+		"PPEP_PP_POOL", "PPEP_PEP_PRODUCT", "PIF_PI_PB_PUBLICATION1", "PIF_PI_PUB_INDEX1",
+		"PIF_PI_PB_PUBLICATION2", "PIF_PI_PUB_INDEX2", "PIF_FRQ_FREQUENCY2", "DY_BEG_DAY",
+		"DY_END_DAY", "SCH_SCHEDULE", "VOLUME1", "CREATEDBY", "MODIFIEDBY", "CREATE_DATE",
+		"MODIFY_DATE", "EXECUTION_DATE", "EXECUTION_TIME"}
+	mock.ExpectQuery(getNucHeatRateSwapsDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			16495, "HRSWPS", 175200, "SALE",
+			parseTime("01-06-2022"), 10105, "O&R", "ORANGE AND ROCKLAND UTILITIES INC", "ORNGE&RCK",
+			"STRM", "SHELL TRADING RISK MANAGEMENT LLC", 18726, "N",
+			"033-RM-FI-10415", "HR SWAP", "EAST", "", 99482,
+			"POWER_RM_CONSUMERS", "DMOLIN", "", "NO", "NA", nil,
+			"NYCAG", "STD 7x24", "NY DA LMP", "61758",
+			"NYMEX", "NG", "MONTHLY", parseTime("23-01-2023"),
+			parseTime("31-12-2023"), "NERC", -20, "DMOLIN", "DMOLIN", parseTime("01-06-2022"),
+			parseTime("01-06-2022"), "06/01/2022", "09:10:00 AM",
+		).AddRow(
+			16496, "HRSWPS", 175200, "PURCHASE",
+			parseTime("01-06-2022"), 10430, "SENA", "Shell Energy North America (US), L.P.", "SENA",
+			"STRM", "SHELL TRADING RISK MANAGEMENT LLC", 18726, "Y",
+			"033-RM-FI-20570", "HR SWAP", "EAST", "", 99482,
+			"POWER_RM_CONSUMERS", "DMOLIN", "", "NO", "NA", nil,
+			"NYCAG", "STD 7x24", "NY DA LMP", "61758",
+			"NYMEX", "NG", "MONTHLY", parseTime("23-01-2023"),
+			parseTime("31-12-2023"), "NERC", 20, "DMOLIN", "DMOLIN", parseTime("01-06-2022"),
+			parseTime("01-06-2022"), "06/01/2022", "09:10:00 AM",
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx         context.Context
+		lastRunTime time.Time
+		tradeDate   time.Time
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "test regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:         context.TODO(),
+				lastRunTime: now,
+				tradeDate:   now,
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             16495,
+					DealType:            "HRSWPS",
+					TotalQuantity:       175200,
+					DnDirection:         "SALE",
+					TransactionDate:     parseTime("01-06-2022"),
+					CyCompanyKey:        10105,
+					Company:             "O&R",
+					CompanyLongName:     "ORANGE AND ROCKLAND UTILITIES INC",
+					CompanyCode:         "ORNGE&RCK",
+					LegalEntity:         "STRM",
+					LegalEntityLongName: "SHELL TRADING RISK MANAGEMENT LLC",
+					CyLegalEntityKey:    18726,
+					InteraffiliateFlag:  "N",
+					Contract:            "033-RM-FI-10415",
+					ConfirmFormat:       "HR SWAP",
+					Region:              "EAST",
+					HsHedgeKey:          "",
+					PrtPortfolio:        99482,
+					Portfolio:           "POWER_RM_CONSUMERS",
+					UrTrader:            "DMOLIN",
+					TzTimeZone:          "",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					ExercisedOptionKey:  0,
+					StartDate:           parseTime("23-01-2023"),
+					EndDate:             parseTime("31-12-2023"),
+					CreatedBy:           "DMOLIN",
+					ModifiedBy:          "DMOLIN",
+					CreatedAt:           parseTime("01-06-2022"),
+					ModifiedAt:          parseTime("01-06-2022"),
+					ExecutionTime:       parseDateTime("06/01/2022", "09:10:00 AM"),
+					ExoticFlag:          "NA",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:          0,
+							Pool1:           "NYCAG",
+							Product1:        "STD 7x24",
+							HolidaySchedule: "NERC",
+							Volume:          -20,
+							BegDate:         parseTime("23-01-2023"),
+							EndDate:         parseTime("31-12-2023"),
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "NY DA LMP",
+									PubIndex:    "61758",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "NYMEX",
+									PubIndex:    "NG",
+									Frequency:   "MONTHLY",
+								},
+								{
+									Publication: "NYMEX",
+									PubIndex:    "NG",
+									Frequency:   "MONTHLY",
+								},
+							},
+						},
+					},
+				},
+				{
+					DealKey:             16496,
+					DealType:            "HRSWPS",
+					TotalQuantity:       175200,
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("01-06-2022"),
+					CyCompanyKey:        10430,
+					Company:             "SENA",
+					CompanyLongName:     "Shell Energy North America (US), L.P.",
+					CompanyCode:         "SENA",
+					LegalEntity:         "STRM",
+					LegalEntityLongName: "SHELL TRADING RISK MANAGEMENT LLC",
+					CyLegalEntityKey:    18726,
+					InteraffiliateFlag:  "Y",
+					Contract:            "033-RM-FI-20570",
+					ConfirmFormat:       "HR SWAP",
+					Region:              "EAST",
+					HsHedgeKey:          "",
+					PrtPortfolio:        99482,
+					Portfolio:           "POWER_RM_CONSUMERS",
+					UrTrader:            "DMOLIN",
+					TzTimeZone:          "",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					ExercisedOptionKey:  0,
+					StartDate:           parseTime("23-01-2023"),
+					EndDate:             parseTime("31-12-2023"),
+					CreatedBy:           "DMOLIN",
+					ModifiedBy:          "DMOLIN",
+					CreatedAt:           parseTime("01-06-2022"),
+					ModifiedAt:          parseTime("01-06-2022"),
+					ExecutionTime:       parseDateTime("06/01/2022", "09:10:00 AM"),
+					ExoticFlag:          "NA",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:          0,
+							Pool1:           "NYCAG",
+							Product1:        "STD 7x24",
+							HolidaySchedule: "NERC",
+							Volume:          20,
+							BegDate:         parseTime("23-01-2023"),
+							EndDate:         parseTime("31-12-2023"),
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "NY DA LMP",
+									PubIndex:    "61758",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "NYMEX",
+									PubIndex:    "NG",
+									Frequency:   "MONTHLY",
+								},
+								{
+									Publication: "NYMEX",
+									PubIndex:    "NG",
+									Frequency:   "MONTHLY",
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucHeatRateSwapsDealList(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealList() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealList() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealList() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealList() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealList() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealList() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealList() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealList() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealList() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealList() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucTCCFTRSDealList(t *testing.T) {
+	now := time.Now()
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	columns := []string{"DEAL_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
+		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY",
+		"LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT",
+		"REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE",
+		"HAS_BROKER", "BROKER", "DY_BEG_DAY", "DY_END_DAY", "SCH_SCHEDULE", "VOLUME",
+		"FIXED_PRICE", "PPEP_PP_POOL", "PPEP_PEP_PRODUCT", "PI_PB_PUBLICATION", "FRQ_FREQUENCY",
+		"POI_PI_PUB_INDEX", "POW_PI_PUB_INDEX", "CREATEDBY", "MODIFIEDBY", "CREATE_DATE",
+		"MODIFY_DATE"}
+	mock.ExpectQuery(getNucTCCFTRSDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("strDealType", "FTRSWP"), sql.Named("lastRunTime", now)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			262325, "FTRSWP", "PURCHASE", parseTime("02-06-2022"),
+			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "", "FTRSWAP",
+			"SOUTH", "", 99860, "SP ERCOT CRR", "SPARK", "CPT",
+			"NO", "NA", parseTime("01-03-2023"), parseTime("31-03-2023"), "NERC", 100,
+			3.0205, "ERCRR", "STD ON", "ER DA LMP", "HOURLY",
+			"SOUTH_HUB", "LZ_LCRA", "PCI_GSMS", "PCI_GSMS", parseTime("02-06-2022"),
+			parseTime("02-06-2022"),
+		).AddRow(
+			262147, "FTRSWP", "PURCHASE", parseTime("02-06-2022"),
+			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "", "FTRSWAP",
+			"SOUTH", "", 99860, "SP ERCOT CRR", "SPARK", "CPT",
+			"NO", "NA", parseTime("01-01-2023"), parseTime("31-01-2023"), "NERC", 25,
+			4.10714, "ERCRR", "STD ON", "ER DA LMP", "HOURLY",
+			"SOUTH_HUB", "NORTH_HUB", "PCI_GSMS", "PCI_GSMS", parseTime("02-06-2022"),
+			parseTime("02-06-2022"),
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx         context.Context
+		lastRunTime time.Time
+		tradeDate   time.Time
+		strDealType string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:         context.TODO(),
+				lastRunTime: now,
+				tradeDate:   now,
+				strDealType: "FTRSWP",
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             262325,
+					DealType:            "FTRSWP",
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("02-06-2022"),
+					CyCompanyKey:        10171,
+					Company:             "ERCOT",
+					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
+					CompanyCode:         "ERCOT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "",
+					ConfirmFormat:       "FTRSWAP",
+					Region:              "SOUTH",
+					HsHedgeKey:          "",
+					PrtPortfolio:        99860,
+					Portfolio:           "SP ERCOT CRR",
+					UrTrader:            "SPARK",
+					TzTimeZone:          "CPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					CreatedBy:           "PCI_GSMS",
+					ModifiedBy:          "PCI_GSMS",
+					CreatedAt:           parseTime("02-06-2022"),
+					ModifiedAt:          parseTime("02-06-2022"),
+					ExoticFlag:          "NA",
+					InteraffiliateFlag:  "N",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:          0,
+							BegDate:         parseTime("01-03-2023"),
+							EndDate:         parseTime("31-03-2023"),
+							HolidaySchedule: "NERC",
+							Volume:          100,
+							FixedPrice:      3.0205,
+							Pool1:           "ERCRR",
+							Product1:        "STD ON",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER DA LMP",
+									PubIndex:    "SOUTH_HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER DA LMP",
+									PubIndex:    "LZ_LCRA",
+									Frequency:   "HOURLY",
+								},
+							},
+						},
+					},
+				},
+				{
+					DealKey:             262147,
+					DealType:            "FTRSWP",
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("02-06-2022"),
+					CyCompanyKey:        10171,
+					Company:             "ERCOT",
+					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
+					CompanyCode:         "ERCOT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "",
+					ConfirmFormat:       "FTRSWAP",
+					Region:              "SOUTH",
+					HsHedgeKey:          "",
+					PrtPortfolio:        99860,
+					Portfolio:           "SP ERCOT CRR",
+					UrTrader:            "SPARK",
+					TzTimeZone:          "CPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					CreatedBy:           "PCI_GSMS",
+					ModifiedBy:          "PCI_GSMS",
+					CreatedAt:           parseTime("02-06-2022"),
+					ModifiedAt:          parseTime("02-06-2022"),
+					ExoticFlag:          "NA",
+					InteraffiliateFlag:  "N",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:          0,
+							BegDate:         parseTime("01-01-2023"),
+							EndDate:         parseTime("31-01-2023"),
+							HolidaySchedule: "NERC",
+							Volume:          25,
+							FixedPrice:      4.10714,
+							Pool1:           "ERCRR",
+							Product1:        "STD ON",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER DA LMP",
+									PubIndex:    "SOUTH_HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER DA LMP",
+									PubIndex:    "NORTH_HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucTCCFTRSDealList(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate, tt.args.strDealType)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealList() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealList() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealList() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealList() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealList() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealList() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealList() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealList() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealList() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealList() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucTransmissionDealList(t *testing.T) {
+	now := time.Now()
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	columns := []string{"TRANS_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
+		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY",
+		"LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT",
+		"REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE",
+		"HAS_BROKER", "BROKER", "CREATEDBY", "MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE",
+		"EXECUTION_TIME"}
+
+	mock.ExpectQuery(getNucTransmissionDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			323931, "TRANS", "PURCHASE", parseTime("02-06-2022"),
+			10133, "SMUD", "SACRAMENTO MUNICIPAL UTILITY DIST", "SMUD", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "011-KW-BI-03761", "TRANS",
+			"WEST", "N", 288, "SD - TRANS", "LHILER", "PPT",
+			"NO", "NA", "LHILER", "LHILER", parseTime("02-06-2022"), parseTime("02-06-2022"),
+			"01/01/1900",
+		).AddRow(
+			323934, "TRANS", "SALE", parseTime("02-06-2022"),
+			10363, "PEXC", "POWEREX CORP", "POWEREX", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "011-KW-BI-03761", "TRANS",
+			"WEST", "N", 288, "SD - TRANS", "LHILER", "PPT",
+			"NO", "NA", "LHILER", "LHILER", parseTime("02-06-2022"), parseTime("02-06-2022"),
+			"01/01/1900",
+		))
+
+	dealKeysArray := []int{323931, 323934}
+
+	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeysArray, []interface{}{}, "pv.td_trans_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucTransmissionDealTermListQ := getNucTransmissionDealTermListQuery(dealKeysQuery)
+
+	var namedParams []driver.Value
+	for _, value := range params {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParams = append(namedParams, valueNamed)
+	}
+
+	columns = []string{"TD_TRANS_KEY", "VOLUME_SEQ", "DY_BEG_DAY", "DY_END_DAY", "VOLUME",
+		"PPEP_PEP_PRODUCT", "PPEP_PP_FM_POOL", "CTP_FM_POINT_CODE", "PPEP_PP_TO_POOL",
+		"CTP_TO_POINT_CODE", "SCH_SCHEDULE"}
+
+	mock.ExpectQuery(getNucTransmissionDealTermListQ).WithArgs(namedParams...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			323931, 0, parseTime("01-06-2022"), parseTime("01-06-2022"), 500,
+			"HOURLY", "MID-C", "MID-C", "MID-C",
+			"MID-C", "NERC",
+		).AddRow(
+			323931, 1, parseTime("01-06-2022"), parseTime("01-06-2022"), 414,
+			"HOURLY", "MID-C", "MID-C", "MID-C",
+			"MID-C", "NERC",
+		).AddRow(
+			323934, 0, parseTime("01-06-2022"), parseTime("01-06-2022"), 151,
+			"HOURLY", "MID-C", "MID-C", "MID-C",
+			"MID-C", "NERC",
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx         context.Context
+		lastRunTime time.Time
+		tradeDate   time.Time
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:         context.TODO(),
+				lastRunTime: now,
+				tradeDate:   now,
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             323931,
+					DealType:            "TRANS",
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("02-06-2022"),
+					CyCompanyKey:        10133,
+					Company:             "SMUD",
+					CompanyLongName:     "SACRAMENTO MUNICIPAL UTILITY DIST",
+					CompanyCode:         "SMUD",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "011-KW-BI-03761",
+					ConfirmFormat:       "TRANS",
+					Region:              "WEST",
+					HsHedgeKey:          "N",
+					PrtPortfolio:        288,
+					Portfolio:           "SD - TRANS",
+					UrTrader:            "LHILER",
+					TzTimeZone:          "PPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					CreatedBy:           "LHILER",
+					ModifiedBy:          "LHILER",
+					CreatedAt:           parseTime("02-06-2022"),
+					ModifiedAt:          parseTime("02-06-2022"),
+					ExoticFlag:          "NA",
+					InteraffiliateFlag:  "N",
+					ExecutionTime:       parseTime("01-01-1900"),
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:          0,
+							BegDate:         parseTime("01-06-2022"),
+							EndDate:         parseTime("01-06-2022"),
+							Volume:          500,
+							Product1:        "HOURLY",
+							Pool1:           "MID-C",
+							PointCode1:      "MID-C",
+							Pool2:           "MID-C",
+							PointCode2:      "MID-C",
+							HolidaySchedule: "NERC",
+							PriceType:       "F",
+						},
+						{
+							VolSeq:          1,
+							BegDate:         parseTime("01-06-2022"),
+							EndDate:         parseTime("01-06-2022"),
+							Volume:          414,
+							Product1:        "HOURLY",
+							Pool1:           "MID-C",
+							PointCode1:      "MID-C",
+							Pool2:           "MID-C",
+							PointCode2:      "MID-C",
+							HolidaySchedule: "NERC",
+							PriceType:       "F",
+						},
+					},
+				},
+				{
+					DealKey:             323934,
+					DealType:            "TRANS",
+					DnDirection:         "SALE",
+					TransactionDate:     parseTime("02-06-2022"),
+					CyCompanyKey:        10363,
+					Company:             "PEXC",
+					CompanyLongName:     "POWEREX CORP",
+					CompanyCode:         "POWEREX",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "011-KW-BI-03761",
+					ConfirmFormat:       "TRANS",
+					Region:              "WEST",
+					HsHedgeKey:          "N",
+					PrtPortfolio:        288,
+					Portfolio:           "SD - TRANS",
+					UrTrader:            "LHILER",
+					TzTimeZone:          "PPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					CreatedBy:           "LHILER",
+					ModifiedBy:          "LHILER",
+					CreatedAt:           parseTime("02-06-2022"),
+					ModifiedAt:          parseTime("02-06-2022"),
+					ExoticFlag:          "NA",
+					InteraffiliateFlag:  "N",
+					ExecutionTime:       parseTime("01-01-1900"),
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:          0,
+							BegDate:         parseTime("01-06-2022"),
+							EndDate:         parseTime("01-06-2022"),
+							Volume:          151,
+							Product1:        "HOURLY",
+							Pool1:           "MID-C",
+							PointCode1:      "MID-C",
+							Pool2:           "MID-C",
+							PointCode2:      "MID-C",
+							HolidaySchedule: "NERC",
+							PriceType:       "F",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucTransmissionDealList(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucTransmissionDealList() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucTransmissionDealList() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucTransmissionDealList() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucTransmissionDealList() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucTransmissionDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucTransmissionDealList() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucTransmissionDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucTransmissionDealList() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucTransmissionDealList() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucTransmissionDealList() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucTransmissionDealList() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucTransmissionDealList() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucMiscChargeDealList(t *testing.T) {
+	now := time.Now()
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	columns := []string{"MISC_CHARGE_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
+		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY", "LEGALENTITYLONGNAME",
+		"CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY",
+		"PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE", "HAS_BROKER", "BROKER",
+		"CREATEDBY", "MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE"}
+
+	mock.ExpectQuery(getNucMiscChargeDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			2854193, "MISC", "Payable", parseTime("02-06-2022"),
+			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA", "Shell Energy North America (US), L.P.",
+			10430, "013-KW-SA-20161", "", "", "",
+			255, "CEM ODD LOT", "PCI_ALLO", "", "", "",
+			"PCI_GSMS", "PCI_GSMS", parseTime("02-06-2022"), parseTime("02-06-2022"),
+		).AddRow(
+			2854183, "MISC", "Receivable", parseTime("02-06-2022"),
+			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA", "Shell Energy North America (US), L.P.",
+			10430, "013-KW-SA-20161", "", "", "",
+			255, "CEM ODD LOT", "PCI_ALLO", "", "", "",
+			"PCI_GSMS", "PCI_GSMS", parseTime("02-06-2022"), parseTime("02-06-2022"),
+		))
+
+	dealKeysArray := []int{2854193, 2854183}
+
+	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeysArray, []interface{}{}, "pv.mc_misc_charge_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucMiscChargeDealTermListQ := getNucMiscChargeDealTermListQuery(dealKeysQuery)
+
+	var namedParams []driver.Value
+	for _, value := range params {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParams = append(namedParams, valueNamed)
+	}
+
+	columns = []string{"MC_MISC_CHARGE_KEY", "MISC_VOL_SEQ", "DY_BEG_DAY", "DY_END_DAY", "INT_VOLUME"}
+
+	mock.ExpectQuery(getNucMiscChargeDealTermListQ).WithArgs(namedParams...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			2854183, 0, parseTime("04-04-2022"), parseTime("04-04-2022"), 0,
+		).AddRow(
+			2854193, 0, parseTime("25-05-2022"), parseTime("25-05-2022"), 0,
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx         context.Context
+		lastRunTime time.Time
+		tradeDate   time.Time
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:         context.TODO(),
+				lastRunTime: now,
+				tradeDate:   now,
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             2854193,
+					DealType:            "MISC",
+					DnDirection:         "Payable",
+					TransactionDate:     parseTime("02-06-2022"),
+					CyCompanyKey:        10171,
+					Company:             "ERCOT",
+					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
+					CompanyCode:         "ERCOT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "013-KW-SA-20161",
+					ConfirmFormat:       "",
+					Region:              "",
+					HsHedgeKey:          "",
+					PrtPortfolio:        255,
+					Portfolio:           "CEM ODD LOT",
+					UrTrader:            "PCI_ALLO",
+					TzTimeZone:          "",
+					HasBroker:           "",
+					Broker:              "",
+					CreatedBy:           "PCI_GSMS",
+					ModifiedBy:          "PCI_GSMS",
+					CreatedAt:           parseTime("02-06-2022"),
+					ModifiedAt:          parseTime("02-06-2022"),
+					InteraffiliateFlag:  "N",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:  0,
+							BegDate: parseTime("25-05-2022"),
+							EndDate: parseTime("25-05-2022"),
+							Volume:  0,
+						},
+					},
+				},
+				{
+					DealKey:             2854183,
+					DealType:            "MISC",
+					DnDirection:         "Receivable",
+					TransactionDate:     parseTime("02-06-2022"),
+					CyCompanyKey:        10171,
+					Company:             "ERCOT",
+					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
+					CompanyCode:         "ERCOT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "013-KW-SA-20161",
+					ConfirmFormat:       "",
+					Region:              "",
+					HsHedgeKey:          "",
+					PrtPortfolio:        255,
+					Portfolio:           "CEM ODD LOT",
+					UrTrader:            "PCI_ALLO",
+					TzTimeZone:          "",
+					HasBroker:           "",
+					Broker:              "",
+					CreatedBy:           "PCI_GSMS",
+					ModifiedBy:          "PCI_GSMS",
+					CreatedAt:           parseTime("02-06-2022"),
+					ModifiedAt:          parseTime("02-06-2022"),
+					InteraffiliateFlag:  "N",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:  0,
+							BegDate: parseTime("04-04-2022"),
+							EndDate: parseTime("04-04-2022"),
+							Volume:  0,
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucMiscChargeDealList(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealList() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealList() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealList() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealList() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealList() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealList() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealList() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealList() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealList() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealList() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealList() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetLastExtractionRun(t *testing.T) {
+	now := time.Now()
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	machineLearningDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer machineLearningDb.Close()
+
+	columns := []string{"ExtractionRunId", "TransactionDate", "DealType", "TimeParameter", "CreatedAt"}
+	mock.ExpectQuery(getLastExtractionRunQuery).WithArgs(sql.Named("transactionDate", now), sql.Named("dealType", "POWER")).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			461,
+			parseTime("01-12-2022"),
+			"POWER",
+			parseTimeLayout("2006-01-02 15:04:05.000", "2022-01-12 00:00:00.000"),
+			parseTimeLayout("2006-01-02 15:04:05.000", "2022-01-13 17:57:14.850"),
+		))
+
+	mock.ExpectQuery(getLastExtractionRunQuery).WithArgs(sql.Named("transactionDate", parseTime("12-12-2030")), sql.Named("dealType", "ALL")).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx       context.Context
+		tradeDate time.Time
+		dealType  string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    *nucleus.NucleusTradeExtractionRunModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nil,
+				machineLearningDb: machineLearningDb,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:       context.TODO(),
+				tradeDate: now,
+				dealType:  "POWER",
+			},
+			want: &nucleus.NucleusTradeExtractionRunModel{
+				ExtractionRunId: 461,
+				TransactionDate: parseTime("01-12-2022"),
+				DealType:        "POWER",
+				TimeParameter:   parseTimeLayout("2006-01-02 15:04:05.000", "2022-01-12 00:00:00.000"),
+				CreatedAt:       parseTimeLayout("2006-01-02 15:04:05.000", "2022-01-13 17:57:14.850"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "no rows in result set",
+			fields: fields{
+				nucleusDb:         nil,
+				machineLearningDb: machineLearningDb,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:       context.TODO(),
+				tradeDate: parseTime("12-12-2030"),
+				dealType:  "ALL",
+			},
+			want:    nil,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetLastExtractionRun(tt.args.ctx, tt.args.tradeDate, tt.args.dealType)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetLastExtractionRun() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetLastExtractionRun() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type processedTradeExample struct {
+	*common.ResultModelBasePayload
+	Company  string
+	Trader   string
+	Source   string
+	Location string
+	Broker   string
+}
+
+type mockNucleusProcessedTradeConverter struct{}
+
+func (converter *mockNucleusProcessedTradeConverter) ConvertValue(raw interface{}) (driver.Value, error) {
+	switch inner := raw.(type) {
+	case string:
+		return raw.(string), nil
+	case mssql.TVP:
+		if inner.TypeName != "NucleusProcessedTradeType" && inner.TypeName != "NucleusProcessedTradeModelResultType" {
+			return nil, fmt.Errorf("invalid type")
+		}
+		return "PASSED", nil
+	}
+
+	return driver.DefaultParameterConverter.ConvertValue(raw)
+}
+
+func TestNucleusTradeRepository_ProcessTrades(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	machineLearningDb, mock, err := sqlmock.New(sqlmock.ValueConverterOption(&mockNucleusProcessedTradeConverter{}))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer machineLearningDb.Close()
+
+	anomalyMessages := make(map[int][]common.IModelBasePayload)
+	anomalyMessages[14695753] = []common.IModelBasePayload{
+		processedTradeExample{
+			Company:  "NGX G_LGL",
+			Trader:   "sharper",
+			Source:   "ECOM_ICE|ICE_FUT",
+			Location: "47288|HUNTINGDON",
+			Broker:   "INTERXCHG_BKR",
+			ResultModelBasePayload: &common.ResultModelBasePayload{
+				DealKey:     14695753,
+				DealType:    "COMM-PHYS",
+				ModelName:   "ModelName",
+				Message:     "Message",
+				ScoredLabel: "NO",
+			},
+		},
+		processedTradeExample{
+			Company:  "NGX G_LGL",
+			Trader:   "sharper",
+			Source:   "ECOM_ICE|ICE_FUT",
+			Location: "47288|HUNTINGDON",
+			Broker:   "INTERXCHG_BKR",
+			ResultModelBasePayload: &common.ResultModelBasePayload{
+				DealKey:     14695753,
+				DealType:    "COMM-PHYS",
+				ModelName:   "ModelName",
+				Message:     "Message",
+				ScoredLabel: "YES",
+			},
+		},
+	}
+
+	var nucleusProcessedTradeTypeData []*nucleusProcessedTradeType
+
+	trades := []*nucleus.NucleusTradeHeaderModel{
+		{
+			DealKey:             3995652,
+			DealType:            "PWRNSD",
+			TotalQuantity:       0,
+			TransactionDate:     parseTime("02-05-2022"),
+			DnDirection:         "PURCHASE",
+			CyCompanyKey:        10171,
+			CompanyCode:         "ERCOT",
+			Company:             "ERCOT",
+			CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
+			PrtPortfolio:        99817,
+			Portfolio:           "TEJAS GEN FRIENDSWOOD",
+			UrTrader:            "BSILVA",
+			CyBrokerKey:         0,
+			Broker:              "NA",
+			TzTimeZone:          "CPT",
+			HasBroker:           "NO",
+			ConfirmFormat:       "STL PWRNSD",
+			CyLegalEntityKey:    21014,
+			LegalEntity:         "TEJ PWR GN",
+			LegalEntityLongName: "TEJAS POWER GENERATION LLC",
+			Region:              "SOUTH",
+			Terms: []*nucleus.NucleusTradeTermModel{
+				{
+					VolSeq:          0,
+					BegDate:         parseTime("03-05-2022"),
+					EndDate:         parseTime("03-05-2022"),
+					Pool1:           "ERHOU",
+					Product1:        "HOURLY",
+					PointCode1:      "HB_HOUSTON",
+					HolidaySchedule: "NONE",
+					Formula1:        "([ER RT LMP|HOUSTON_HUB_AVG|HOURLY])<CU>USD</CU><UT>MW</UT>",
+					Indexes1: []*nucleus.NucleusTradeIndexModel{
+						{
+							Publication: "ER RT LMP",
+							PubIndex:    "HOUSTON_HUB_AVG",
+							Frequency:   "HOURLY",
+						},
+					},
+					PriceType: "I",
+				},
+			},
+			AnomalyTestResult:  "",
+			CreatedAt:          parseTime("02-05-2022"),
+			ModifiedAt:         parseDateTime("2022-05-18", "07:53:32 AM"),
+			CreatedBy:          "CAGUILAR",
+			ModifiedBy:         "JCHOI",
+			ExoticFlag:         "NA",
+			InteraffiliateFlag: "N",
+		},
+	}
+
+	for _, trade := range trades {
+		var anomalyMsg, modelParams string
+
+		if resultList, ok := anomalyMessages[trade.DealKey]; ok {
+			for _, result := range resultList {
+				if result.GetScoredLabel() == "NO" {
+					if anomalyMsg == "" {
+						anomalyMsg = result.GetModelName() + " " + result.GetMessage()
+					} else {
+						anomalyMsg = anomalyMsg + ";" + result.GetModelName() + " " + result.GetMessage()
+					}
+
+					vParams, err := json.Marshal(result)
+					if err != nil {
+						t.Fatalf("an error '%v' was not expected when marshalling json", err)
+					}
+
+					if modelParams == "" {
+						modelParams = string(vParams)
+					} else {
+						modelParams = modelParams + ";" + string(vParams)
+					}
+				}
+			}
+		}
+
+		marshalledTrade, err := json.Marshal(trade)
+		if err != nil {
+			t.Fatalf("an error '%v' was not expected when marshalling json", err)
+		}
+
+		nucleusProcessedTrade := &nucleusProcessedTradeType{
+			TradeId:             int64(trade.DealKey),
+			DealType:            trade.DealType,
+			PortfolioId:         int64(trade.PrtPortfolio),
+			TransactionDate:     trade.TransactionDate,
+			TradeDetail:         string(marshalledTrade),
+			AnomalyDetectedFlag: false,
+			AnomalyTestResult: sql.NullString{
+				Valid: false,
+			},
+			ModelParameters: sql.NullString{
+				Valid: false,
+			},
+		}
+
+		if anomalyMsg != "" {
+			nucleusProcessedTrade.AnomalyDetectedFlag = true
+			nucleusProcessedTrade.AnomalyTestResult = sql.NullString{
+				String: anomalyMsg,
+				Valid:  true,
+			}
+		}
+
+		if modelParams != "" {
+			nucleusProcessedTrade.AnomalyTestResult = sql.NullString{
+				String: modelParams,
+				Valid:  true,
+			}
+		}
+
+		nucleusProcessedTradeTypeData = append(nucleusProcessedTradeTypeData, nucleusProcessedTrade)
+	}
+
+	tvpType := mssql.TVP{
+		TypeName: "NucleusProcessedTradeType",
+		Value:    nucleusProcessedTradeTypeData,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(execProcessTradesQuery).WithArgs(sql.Named("TVP", tvpType)).
+		WillReturnRows(sqlmock.NewRows([]string{"TradeId", "DealType", "Action", "AnomalyDetectedFlag"}).AddRow(
+			3995652, "PWRNSD", "INSERT", false,
+		))
+	mock.ExpectExec(deleteProcessedTradeModelResultsQuery).WithArgs(sql.Named("TVP", tvpType)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(insertTradeVersionsQuery)).WithArgs(sql.Named("TVP", tvpType)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx             context.Context
+		trades          []*nucleus.NucleusTradeHeaderModel
+		anomalyMessages map[int][]common.IModelBasePayload
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "normal run",
+			fields: fields{
+				nucleusDb:         nil,
+				machineLearningDb: machineLearningDb,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:             context.TODO(),
+				trades:          trades,
+				anomalyMessages: anomalyMessages,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			if _, err := repo.ProcessTrades(tt.args.ctx, tt.args.trades, tt.args.anomalyMessages); (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.ProcessTrades() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_InsertExtractionRun(t *testing.T) {
+	now := time.Now()
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	machineLearningDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer machineLearningDb.Close()
+
+	mock.ExpectExec(insertExtractionRunQuery).WithArgs(sql.Named("transactionDate", now), sql.Named("dealType", "FTRSWP"), sql.Named("timeParameter", now)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx       context.Context
+		tradeDate time.Time
+		lastRun   time.Time
+		dealType  string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "test regular run",
+			fields: fields{
+				nucleusDb:         nil,
+				machineLearningDb: machineLearningDb,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:       context.TODO(),
+				tradeDate: now,
+				lastRun:   now,
+				dealType:  "FTRSWP",
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			if err := repo.InsertExtractionRun(tt.args.ctx, tt.args.tradeDate, tt.args.lastRun, tt.args.dealType); (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.InsertExtractionRun() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetPortfolioRiskMappingList(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	machineLearningDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer machineLearningDb.Close()
+
+	machineLearningDbEmpty, mockEmpty, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer machineLearningDb.Close()
+
+	machineLearningDbError, mockError, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer machineLearningDb.Close()
+
+	columns := []string{"SourceSystem", "Portfolio", "LegalEntity"}
+
+	mock.ExpectQuery(getPortfolioRiskMappingListQuery).WillReturnRows(sqlmock.NewRows(columns).AddRow(
+		"NUCLEUS", "LOCKPORT_TOLL", nil,
+	).AddRow(
+		"NUCLEUS", "COMMITMENT FEE INTEREST", "STRM",
+	))
+
+	mockEmpty.ExpectQuery(getPortfolioRiskMappingListQuery).WillReturnRows(sqlmock.NewRows(columns))
+
+	mockError.ExpectQuery(getPortfolioRiskMappingListQuery).WillReturnError(fmt.Errorf("error"))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx context.Context
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.PortfolioRiskMappingModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nil,
+				machineLearningDb: machineLearningDb,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx: context.TODO(),
+			},
+			want: []*nucleus.PortfolioRiskMappingModel{
+				{
+					SourceSystem: "NUCLEUS",
+					Portfolio:    "LOCKPORT_TOLL",
+					LegalEntity:  "",
+				},
+				{
+					SourceSystem: "NUCLEUS",
+					Portfolio:    "COMMITMENT FEE INTEREST",
+					LegalEntity:  "STRM",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "no rows in result set",
+			fields: fields{
+				nucleusDb:         nil,
+				machineLearningDb: machineLearningDbEmpty,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx: context.TODO(),
+			},
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name: "error in result set",
+			fields: fields{
+				nucleusDb:         nil,
+				machineLearningDb: machineLearningDbError,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx: context.TODO(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetPortfolioRiskMappingList(tt.args.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetPortfolioRiskMappingList() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetPortfolioRiskMappingList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetLarBaselist(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	machineLearningDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer machineLearningDb.Close()
+
+	machineLearningDbEmpty, mockEmpty, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer machineLearningDb.Close()
+
+	machineLearningDbError, mockError, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer machineLearningDb.Close()
+
+	columns := []string{"ShortName", "CounterpartyLongName", "ParentCompany", "Product",
+		"SourceSystem", "DealType", "NettingAgreement", "AgreementTypePerCSA", "OurThreshold",
+		"CounterpartyThreshold", "BuyTenor", "SellTenor", "GrossExposure", "Collateral",
+		"NetPosition", "LimitValue", "LimitCurrency", "LimitAvailability", "ExposureLimit",
+		"ExpirationDate", "MarketType", "IndustryCode", "SPRating", "MoodyRating",
+		"FinalInternalRating", "FinalRating", "Equifax", "AmendedBy", "EffectiveDate",
+		"ReviewDate", "DoddFrankClassification", "ReportCreatedDate", "Boost", "tradingEntity",
+		"LegalEntity", "Agmt", "CSA", "Tenor", "Limit", "ReportingDate", "CreatedAt"}
+
+	mock.ExpectQuery(getLarBaselistQuery).WillReturnRows(sqlmock.NewRows(columns).AddRow(
+		"ONT POWER", "ONTARIO POWER GENERATION INC", "ONTARIO POWER GENERATION INC", "SENA-Power",
+		"NUCLEUS", "CAPCTY", "011-KW-BI-03761", "WSPP with Netting", 0,
+		10, "Halt Trading -KYC", "Halt Trading -KYC", 0.00, 0,
+		0, 0, "USD", "HALT TRADING - KYC", "",
+		parseTimeLayout("2006-01-02 15:04:05.000", "2099-12-31 00:00:00.000"), "Wholesale", "55101010 - Electric Utilities", "BBB+", "Not Rated",
+		"BBB+", "BBB+", " ", "Icko Moster", parseTimeLayout("2006-01-02 15:04:05.000", "2020-10-15 00:00:00.000"),
+		parseTimeLayout("2006-01-02 15:04:05.000", "2099-12-31 00:00:00.000"), "No Classification", parseTimeLayout("2006-01-02 15:04:05.000", "2021-10-20 13:30:15.143"), "FALSE", "SHELL ENERGY NORTH AMERICA (US) LP",
+		"Shell Energy North America (US), L.P.", "X", "X", "X", "X", parseTime("20-10-2021"), parseTimeLayout("2006-01-02 15:04:05.000", "2021-10-20 13:22:25.910"),
+	).AddRow(
+		"OPSI", "OCCIDENTAL POWER SERVICES INC", "OCCIDENTAL PETROLEUM CORPORATION", "SENA-Power",
+		"NUCLEUS", "POWER", "011-KW-BI-03761", "WSPP with Netting", 10,
+		10, "CALL CREDIT", "CALL CREDIT", 0.00, 0,
+		0, 0, "USD", "CALL CREDIT", "HIGH",
+		parseTimeLayout("2006-01-02 15:04:05.000", "2022-09-30 00:00:00.000"), "Wholesale", "40203020 - Investment Banking and Brokerage", "Not Rated", "Not Rated",
+		"CCC+", "CCC+", " ", "Sarah Marissa Jynnyl Vista", parseTimeLayout("2006-01-02 15:04:05.000", "2021-10-06 00:00:00.000"),
+		parseTimeLayout("2006-01-02 15:04:05.000", "2022-08-30 00:00:00.000"), "No Classification", parseTimeLayout("2006-01-02 15:04:05.000", "2021-10-20 13:30:15.143"), "FALSE", "SHELL ENERGY NORTH AMERICA (US) LP",
+		"Shell Energy North America (US), L.P.", "X", "X", "X", "X", parseTime("20-10-2021"), parseTimeLayout("2006-01-02 15:04:05.000", "2021-10-20 13:22:25.910"),
+	))
+
+	mockEmpty.ExpectQuery(getLarBaselistQuery).WillReturnRows(sqlmock.NewRows(columns))
+
+	mockError.ExpectQuery(getLarBaselistQuery).WillReturnError(fmt.Errorf("error"))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx context.Context
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*common.LarBaseModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nil,
+				machineLearningDb: machineLearningDb,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx: context.TODO(),
+			},
+			want: []*common.LarBaseModel{
+				{
+					ShortName:               "ONT POWER",
+					CounterpartyLongName:    "ONTARIO POWER GENERATION INC",
+					ParentCompany:           "ONTARIO POWER GENERATION INC",
+					Product:                 "SENA-Power",
+					SourceSystem:            "NUCLEUS",
+					DealType:                "CAPCTY",
+					NettingAgreement:        "011-KW-BI-03761",
+					AgreementTypePerCSA:     "WSPP with Netting",
+					OurThreshold:            0,
+					CounterpartyThreshold:   10,
+					BuyTenor:                "Halt Trading -KYC",
+					SellTenor:               "Halt Trading -KYC",
+					GrossExposure:           0.00,
+					Collateral:              0,
+					NetPosition:             0,
+					LimitValue:              0,
+					LimitCurrency:           "USD",
+					LimitAvailability:       "HALT TRADING - KYC",
+					ExposureLimit:           "",
+					ExpirationDate:          parseTimeLayout("2006-01-02 15:04:05.000", "2099-12-31 00:00:00.000"),
+					MarketType:              "Wholesale",
+					IndustryCode:            "55101010 - Electric Utilities",
+					SPRating:                "BBB+",
+					MoodyRating:             "Not Rated",
+					FinalInternalRating:     "BBB+",
+					FinalRating:             "BBB+",
+					Equifax:                 " ",
+					AmendedBy:               "Icko Moster",
+					EffectiveDate:           parseTimeLayout("2006-01-02 15:04:05.000", "2020-10-15 00:00:00.000"),
+					ReviewDate:              parseTimeLayout("2006-01-02 15:04:05.000", "2099-12-31 00:00:00.000"),
+					DoddFrankClassification: "No Classification",
+					ReportCreatedDate:       parseTimeLayout("2006-01-02 15:04:05.000", "2021-10-20 13:30:15.143"),
+					Boost:                   "FALSE",
+					TradingEntity:           "SHELL ENERGY NORTH AMERICA (US) LP",
+					LegalEntity:             "Shell Energy North America (US), L.P.",
+					Agmt:                    "X",
+					CSA:                     "X",
+					Tenor:                   "X",
+					Limit:                   "X",
+					ReportingDate:           parseTime("20-10-2021"),
+					CreatedAt:               parseTimeLayout("2006-01-02 15:04:05.000", "2021-10-20 13:22:25.910"),
+				},
+				{
+					ShortName:               "OPSI",
+					CounterpartyLongName:    "OCCIDENTAL POWER SERVICES INC",
+					ParentCompany:           "OCCIDENTAL PETROLEUM CORPORATION",
+					Product:                 "SENA-Power",
+					SourceSystem:            "NUCLEUS",
+					DealType:                "POWER",
+					NettingAgreement:        "011-KW-BI-03761",
+					AgreementTypePerCSA:     "WSPP with Netting",
+					OurThreshold:            10,
+					CounterpartyThreshold:   10,
+					BuyTenor:                "CALL CREDIT",
+					SellTenor:               "CALL CREDIT",
+					GrossExposure:           0.00,
+					Collateral:              0,
+					NetPosition:             0,
+					LimitValue:              0,
+					LimitCurrency:           "USD",
+					LimitAvailability:       "CALL CREDIT",
+					ExposureLimit:           "HIGH",
+					ExpirationDate:          parseTimeLayout("2006-01-02 15:04:05.000", "2022-09-30 00:00:00.000"),
+					MarketType:              "Wholesale",
+					IndustryCode:            "40203020 - Investment Banking and Brokerage",
+					SPRating:                "Not Rated",
+					MoodyRating:             "Not Rated",
+					FinalInternalRating:     "CCC+",
+					FinalRating:             "CCC+",
+					Equifax:                 " ",
+					AmendedBy:               "Sarah Marissa Jynnyl Vista",
+					EffectiveDate:           parseTimeLayout("2006-01-02 15:04:05.000", "2021-10-06 00:00:00.000"),
+					ReviewDate:              parseTimeLayout("2006-01-02 15:04:05.000", "2022-08-30 00:00:00.000"),
+					DoddFrankClassification: "No Classification",
+					ReportCreatedDate:       parseTimeLayout("2006-01-02 15:04:05.000", "2021-10-20 13:30:15.143"),
+					Boost:                   "FALSE",
+					TradingEntity:           "SHELL ENERGY NORTH AMERICA (US) LP",
+					LegalEntity:             "Shell Energy North America (US), L.P.",
+					Agmt:                    "X",
+					CSA:                     "X",
+					Tenor:                   "X",
+					Limit:                   "X",
+					ReportingDate:           parseTime("20-10-2021"),
+					CreatedAt:               parseTimeLayout("2006-01-02 15:04:05.000", "2021-10-20 13:22:25.910"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "no rows in result set",
+			fields: fields{
+				nucleusDb:         nil,
+				machineLearningDb: machineLearningDbEmpty,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx: context.TODO(),
+			},
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name: "error in result set",
+			fields: fields{
+				nucleusDb:         nil,
+				machineLearningDb: machineLearningDbError,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx: context.TODO(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetLarBaselist(tt.args.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetLarBaselist() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetLarBaselist() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucPowerDealByKeys(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	lstPowerkeys := []float64{3996506, 3996507}
+
+	dealKeysQuery, paramsDeal, err := oracle.CreateInQueryFloat64(lstPowerkeys, []interface{}{}, "pd.power_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucPowerDealByKeysQuery := getNucPowerDealByKeysQuery(dealKeysQuery)
+
+	var namedParamsQuery []driver.Value
+	for _, value := range paramsDeal {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParamsQuery = append(namedParamsQuery, valueNamed)
+	}
+
+	columns := []string{"POWER_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
+		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE",
+		"LEGALENTITY", "LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER",
+		"CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER",
+		"TZ_TIME_ZONE", "HAS_BROKER", "BROKER", "OPTION_KEY", "CREATEDBY", "CREATE_DATE",
+		"MODIFIEDBY", "MODIFY_DATE", "EXECUTION_DATE", "EXECUTION_TIME", "EXOTIC_FLAG"}
+
+	mock.ExpectQuery(getNucPowerDealByKeysQuery).WithArgs(namedParamsQuery...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			3996506, "PWRNSD", "PURCHASE", parseTime("05-05-2022"),
+			1601, "PJM", "PJM INTERCONNECTION LLC", "PJM",
+			"SENA", "Shell Energy North America (US), L.P.", 10430, "011-KW-BI-05701",
+			"HOURLY", "EAST", "", 288, "SD - TRANS", "SROSS",
+			"PPT", "NO", "NA", nil, "SROSS", parseTime("04-05-2022"),
+			"SROSS", parseTime("05-05-2022"), "", "", "NA",
+		).AddRow(
+			3996507, "PWRNSD", "SALE", parseTime("05-05-2022"),
+			10459, "MISO", "MIDWEST INDEPENDENT TRANSMISSION SYSTEM", "MIDWEST IN",
+			"SENA", "Shell Energy North America (US), L.P.", 10430, "011-KW-IS-10952",
+			"HOURLY", "EAST", "", 288, "SD - TRANS", "SROSS",
+			"PPT", "NO", "NA", nil, "SROSS", parseTime("04-05-2022"),
+			"SROSS", parseTime("05-05-2022"), "2022-05-05", "08:18:55 AM", "NA",
+		))
+
+	dealKeysArray := []int{3996506, 3996507}
+
+	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeysArray, []interface{}{}, "pv.pd_power_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucPowerTradeTermModelQ := getNucPowerTradeTermModelQuery(dealKeysQuery)
+
+	var namedParams []driver.Value
+	for _, value := range params {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParams = append(namedParams, valueNamed)
+	}
+
+	columns = []string{"PD_POWER_KEY", "VOLUME_SEQ", "DY_BEG_DAY", "DY_END_DAY", "PRICE_TYPE",
+		"PRICE", "VOLUME", "PPEP_PP_POOL", "PPEP_PEP_PRODUCT", "CTP_POINT_CODE", "FORMULA",
+		"SCH_SCHEDULE"}
+
+	mock.ExpectQuery(getNucPowerTradeTermModelQ).WithArgs(namedParams...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			3996506, 0, parseTime("05-05-2022"), parseTime("05-05-2022"), "I",
+			0, 0, "PJM", "HOURLY", "MISO", "[PJM DA LMP|40523629|HOURLY]",
+			"NERC",
+		).AddRow(
+			3996507, 0, parseTime("05-05-2022"), parseTime("05-05-2022"), "I",
+			0, 0, "MISOE", "HOURLY", "MISO/PJM", "[MISO DALMP|PJMC|HOURLY]",
+			"NERC",
+		))
+
+	formulaMap := make(map[int]int)
+	formulaMap[3996506] = 0
+	formulaMap[3996507] = 0
+
+	insNumQuery, _, err := oracle.CreateMapIntWhereQuery(formulaMap, []interface{}{}, "pv_pd_power_key", "pv_volume_seq")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucPowerTradeIndexModelQ := getNucPowerTradeIndexModelQuery(insNumQuery)
+
+	columns = []string{"PV_PD_POWER_KEY", "PV_VOLUME_SEQ", "PUBLICATION", "PUB_INDEX", "FREQUENCY"}
+
+	// this is a small hack to allow any values to be in any order for the mock,
+	// the issue is that the parameters for the query never will be in the same
+	// order because it comes from iterating over the keys of a map and Go always
+	// randmize them
+	mock.ExpectQuery(getNucPowerTradeIndexModelQ).WithArgs(AnyValueMatch{}, AnyValueMatch{},
+		AnyValueMatch{}, AnyValueMatch{}).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			3996506, 0, "PJM DA LMP", "40523629", "HOURLY",
+		).AddRow(
+			3996507, 0, "MISO DALMP", "PJMC", "HOURLY",
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx          context.Context
+		lstPowerkeys []float64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:          context.TODO(),
+				lstPowerkeys: lstPowerkeys,
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             3996506,
+					DealType:            "PWRNSD",
+					TotalQuantity:       0,
+					TransactionDate:     parseTime("05-05-2022"),
+					DnDirection:         "PURCHASE",
+					CyCompanyKey:        1601,
+					CompanyCode:         "PJM",
+					Company:             "PJM",
+					CompanyLongName:     "PJM INTERCONNECTION LLC",
+					HsHedgeKey:          "",
+					PrtPortfolio:        288,
+					Portfolio:           "SD - TRANS",
+					UrTrader:            "SROSS",
+					CyBrokerKey:         0,
+					Broker:              "NA",
+					DaRtIndicator:       "",
+					TzTimeZone:          "PPT",
+					TzExerciseZone:      "",
+					HasBroker:           "NO",
+					IbPrtPortfolio:      0,
+					IbPortfolio:         "",
+					IbUrTrader:          "",
+					ExercisedOptionKey:  0,
+					Contract:            "011-KW-BI-05701",
+					ConfirmFormat:       "HOURLY",
+					CyLegalEntityKey:    10430,
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					Region:              "EAST",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:          0,
+							BegDate:         parseTime("05-05-2022"),
+							EndDate:         parseTime("05-05-2022"),
+							Pool1:           "PJM",
+							Product1:        "HOURLY",
+							PointCode1:      "MISO",
+							Pool2:           "",
+							Product2:        "",
+							HolidaySchedule: "NERC",
+							PointCode2:      "",
+							Formula1:        "[PJM DA LMP|40523629|HOURLY]",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									VolSeq:      0,
+									Publication: "PJM DA LMP",
+									PubIndex:    "40523629",
+									Frequency:   "HOURLY",
+								},
+							},
+							Formula2:   "",
+							Indexes2:   nil,
+							PriceType:  "I",
+							FixedPrice: 0,
+							Volume:     0,
+						},
+					},
+					AnomalyTestResult:  "",
+					CreatedAt:          parseTime("04-05-2022"),
+					ModifiedAt:         parseTime("05-05-2022"),
+					CreatedBy:          "SROSS",
+					ModifiedBy:         "SROSS",
+					OptionType:         "",
+					ExecutionTime:      parseTime("01-01-0001"),
+					ExoticFlag:         "NA",
+					InteraffiliateFlag: "N",
+					StartDate:          parseTime("01-01-0001"),
+					EndDate:            parseTime("01-01-0001"),
+				},
+				{
+					DealKey:             3996507,
+					DealType:            "PWRNSD",
+					TotalQuantity:       0,
+					TransactionDate:     parseTime("05-05-2022"),
+					DnDirection:         "SALE",
+					CyCompanyKey:        10459,
+					CompanyCode:         "MIDWEST IN",
+					Company:             "MISO",
+					CompanyLongName:     "MIDWEST INDEPENDENT TRANSMISSION SYSTEM",
+					HsHedgeKey:          "",
+					PrtPortfolio:        288,
+					Portfolio:           "SD - TRANS",
+					UrTrader:            "SROSS",
+					CyBrokerKey:         0,
+					Broker:              "NA",
+					DaRtIndicator:       "",
+					TzTimeZone:          "PPT",
+					TzExerciseZone:      "",
+					HasBroker:           "NO",
+					IbPrtPortfolio:      0,
+					IbPortfolio:         "",
+					IbUrTrader:          "",
+					ExercisedOptionKey:  0,
+					Contract:            "011-KW-IS-10952",
+					ConfirmFormat:       "HOURLY",
+					CyLegalEntityKey:    10430,
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					Region:              "EAST",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:          0,
+							BegDate:         parseTime("05-05-2022"),
+							EndDate:         parseTime("05-05-2022"),
+							Pool1:           "MISOE",
+							Product1:        "HOURLY",
+							PointCode1:      "MISO/PJM",
+							Pool2:           "",
+							Product2:        "",
+							HolidaySchedule: "NERC",
+							PointCode2:      "",
+							Formula1:        "[MISO DALMP|PJMC|HOURLY]",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									VolSeq:      0,
+									Publication: "MISO DALMP",
+									PubIndex:    "PJMC",
+									Frequency:   "HOURLY",
+								},
+							},
+							Formula2:   "",
+							Indexes2:   nil,
+							PriceType:  "I",
+							FixedPrice: 0,
+							Volume:     0,
+						},
+					},
+					AnomalyTestResult:  "",
+					CreatedAt:          parseTime("04-05-2022"),
+					ModifiedAt:         parseTime("05-05-2022"),
+					CreatedBy:          "SROSS",
+					ModifiedBy:         "SROSS",
+					OptionType:         "",
+					ExecutionTime:      parseDateTime("2022-05-05", "08:18:55 AM"),
+					ExoticFlag:         "NA",
+					InteraffiliateFlag: "N",
+					StartDate:          parseTime("01-01-0001"),
+					EndDate:            parseTime("01-01-0001"),
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucPowerDealByKeys(tt.args.ctx, tt.args.lstPowerkeys)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucPowerDealByKeys() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucPowerDealByKeys() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucPowerDealByKeys() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucPowerDealByKeys() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucPowerDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucPowerDealByKeys() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucPowerDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucPowerDealByKeys() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucPowerDealByKeys() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucPowerDealByKeys() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucPowerDealByKeys() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucPowerDealByKeys() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucPowerSwapDealByKeys(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	lstPowerSwapkeys := []float64{1388598, 1388692}
+
+	dealKeysQuery, paramsDeal, err := oracle.CreateInQueryFloat64(lstPowerSwapkeys, []interface{}{}, "pd.pswap_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucPowerSwapDealByKeysQuery := getNucPowerSwapDealByKeysQuery(dealKeysQuery)
+
+	var namedParamsQuery []driver.Value
+	for _, value := range paramsDeal {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParamsQuery = append(namedParamsQuery, valueNamed)
+	}
+
+	columns := []string{"PSWAP_KEY", "DEAL_TYPE", "TOTAL_QUANTITY", "DN_DIRECTION",
+		"TRANSACTION_DATE", "CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE",
+		"LEGALENTITY", "LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "INTERAFFILIATE_FLAG",
+		"CONTRACTNUMBER", "CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO",
+		"PORTFOLIO", "UR_TRADER", "IB_PRT_PORTFOLIO", "IB_PORTFOLIO", "IB_UR_TRADER",
+		"TZ_TIME_ZONE", "HAS_BROKER", "BROKER", "OPTION_KEY", "PPEP_PP_POOL", "PPEP_PEP_PRODUCT",
+		"VOLUME", "FIXED_PRICE", "NONSTD_FLAG", "PI_PB_PUBLICATION", "PI_PUB_INDEX",
+		"FRQ_FREQUENCY", "FIX_PI_PB_PUBLICATION", "FIX_PI_PUB_INDEX", "FIX_FRQ_FREQUENCY",
+		"DY_BEG_DAY", "DY_END_DAY", "SCH_SCHEDULE", "CREATEDBY", "CREATE_DATE", "MODIFIEDBY",
+		"MODIFY_DATE", "EXECUTION_DATE", "EXECUTION_TIME", "EXOTIC_FLAG"}
+
+	mock.ExpectQuery(getNucPowerSwapDealByKeysQuery).WithArgs(namedParamsQuery...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			1388598, "PSWPS", 800, "PURCHASE",
+			parseTime("05-05-2022"), 18098, "FIMAT USA", "NEWEDGE USA LLC", "FIMAT USA",
+			"SENA", "Shell Energy North America (US), L.P.", 10430, "N",
+			"", "POWER SWAP", "SOUTH", "", 235,
+			"ERCOT B E FINANCIAL", "CWATSON", nil, "", "",
+			"CPT", "YES", "INTERXCHG", nil, "ERFNH", "STD ON",
+			50, 111, "Y", "ER RT LMP", "NORTH_HUB_AVG",
+			"HOURLY", nil, nil, nil,
+			parseTime("05-05-2022"), parseTime("05-05-2022"), "NERC", "LOB2", parseTime("05-05-2022"), "LOB2",
+			parseTime("05-05-2022"), "2022-05-05", "09:08:26 AM", "No",
+		).AddRow(
+			1388692, "PSWPS", 21600, "PURCHASE",
+			parseTime("05-05-2022"), 18098, "FIMAT USA", "NEWEDGE USA LLC", "FIMAT USA",
+			"SENA", "Shell Energy North America (US), L.P.", 10430, "N",
+			"", "POWER SWAP", "SOUTH", "", 235,
+			"ERCOT B E FINANCIAL", "CWATSON", nil, "", "",
+			"CPT", "YES", "INTERXCHG", nil, "ERFNH", "STD 2x16",
+			135, 30, "N", "ER RT LMP", "NORTH_HUB_AVG",
+			"HOURLY", nil, nil, nil,
+			parseTime("01-05-2025"), parseTime("31-05-2025"), "NERC", "LOB2", parseTime("05-05-2022"), "JKING",
+			parseTime("05-05-2022"), "2022-05-05", "11:05:35 AM", "No",
+		))
+
+	dealKeysArray := []int{1388598}
+
+	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeysArray, []interface{}{}, "pswp_pswap_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucPowerSwapDealTermModelQ := getNucPowerSwapDealTermModelQuery(dealKeysQuery)
+
+	var namedParams []driver.Value
+	for _, value := range params {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParams = append(namedParams, valueNamed)
+	}
+
+	columns = []string{"PSWP_PSWAP_KEY", "VOLUME_SEQ", "DY_BEG_DAY", "DY_END_DAY"}
+	mock.ExpectQuery(getNucPowerSwapDealTermModelQ).WithArgs(namedParams...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			1388598, 613, parseTime("05-03-2024"), parseTime("05-03-2024"),
+		).AddRow(
+			1388598, 614, parseTime("06-03-2024"), parseTime("06-03-2024"),
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx              context.Context
+		lstPowerSwapkeys []float64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:              context.TODO(),
+				lstPowerSwapkeys: lstPowerSwapkeys,
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             1388598,
+					DealType:            "PSWPS",
+					TotalQuantity:       800,
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("05-05-2022"),
+					CyCompanyKey:        18098,
+					Company:             "FIMAT USA",
+					CompanyLongName:     "NEWEDGE USA LLC",
+					CompanyCode:         "FIMAT USA",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "",
+					ConfirmFormat:       "POWER SWAP",
+					Region:              "SOUTH",
+					PrtPortfolio:        235,
+					Portfolio:           "ERCOT B E FINANCIAL",
+					UrTrader:            "CWATSON",
+					IbPrtPortfolio:      0,
+					IbPortfolio:         "",
+					IbUrTrader:          "",
+					TzTimeZone:          "CPT",
+					HasBroker:           "YES",
+					Broker:              "INTERXCHG",
+					ExercisedOptionKey:  0,
+					StartDate:           parseTime("05-05-2022"),
+					EndDate:             parseTime("05-05-2022"),
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:     613,
+							Pool1:      "ERFNH",
+							Product1:   "STD ON",
+							Volume:     0,
+							FixedPrice: 0,
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER RT LMP",
+									PubIndex:    "NORTH_HUB_AVG",
+									Frequency:   "HOURLY",
+								},
+							},
+							BegDate:         parseTime("05-03-2024"),
+							EndDate:         parseTime("05-03-2024"),
+							HolidaySchedule: "NERC",
+						},
+						{
+							VolSeq:     614,
+							Pool1:      "ERFNH",
+							Product1:   "STD ON",
+							Volume:     0,
+							FixedPrice: 0,
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER RT LMP",
+									PubIndex:    "NORTH_HUB_AVG",
+									Frequency:   "HOURLY",
+								},
+							},
+							BegDate:         parseTime("06-03-2024"),
+							EndDate:         parseTime("06-03-2024"),
+							HolidaySchedule: "NERC",
+						},
+					},
+					CreatedBy:          "LOB2",
+					CreatedAt:          parseTime("05-05-2022"),
+					ModifiedBy:         "LOB2",
+					ModifiedAt:         parseTime("05-05-2022"),
+					ExecutionTime:      parseDateTime("2022-05-05", "09:08:26 AM"),
+					ExoticFlag:         "No",
+					InteraffiliateFlag: "N",
+				},
+				{
+					DealKey:             1388692,
+					DealType:            "PSWPS",
+					TotalQuantity:       21600,
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("05-05-2022"),
+					CyCompanyKey:        18098,
+					Company:             "FIMAT USA",
+					CompanyLongName:     "NEWEDGE USA LLC",
+					CompanyCode:         "FIMAT USA",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "",
+					ConfirmFormat:       "POWER SWAP",
+					Region:              "SOUTH",
+					PrtPortfolio:        235,
+					Portfolio:           "ERCOT B E FINANCIAL",
+					UrTrader:            "CWATSON",
+					IbPrtPortfolio:      0,
+					IbPortfolio:         "",
+					IbUrTrader:          "",
+					TzTimeZone:          "CPT",
+					HasBroker:           "YES",
+					Broker:              "INTERXCHG",
+					ExercisedOptionKey:  0,
+					StartDate:           parseTime("01-05-2025"),
+					EndDate:             parseTime("31-05-2025"),
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:     0,
+							Pool1:      "ERFNH",
+							Product1:   "STD 2x16",
+							Volume:     135,
+							FixedPrice: 30,
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER RT LMP",
+									PubIndex:    "NORTH_HUB_AVG",
+									Frequency:   "HOURLY",
+								},
+							},
+							BegDate:         parseTime("01-05-2025"),
+							EndDate:         parseTime("31-05-2025"),
+							HolidaySchedule: "NERC",
+						},
+					},
+					CreatedBy:          "LOB2",
+					CreatedAt:          parseTime("05-05-2022"),
+					ModifiedBy:         "JKING",
+					ModifiedAt:         parseTime("05-05-2022"),
+					ExecutionTime:      parseDateTime("2022-05-05", "11:05:35 AM"),
+					ExoticFlag:         "No",
+					InteraffiliateFlag: "N",
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucPowerSwapDealByKeys(tt.args.ctx, tt.args.lstPowerSwapkeys)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealByKeys() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealByKeys() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealByKeys() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealByKeys() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealByKeys() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealByKeys() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealByKeys() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealByKeys() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealByKeys() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucPowerSwapDealByKeys() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucPowerOptionsDealByKeys(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	lstPowerOptionkeys := []float64{43435, 43441}
+
+	dealKeysQuery, paramsDeal, err := oracle.CreateInQueryFloat64(lstPowerOptionkeys, []interface{}{}, "pd.poption_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucPowerOptionsDealByKeysQ := getNucPowerOptionsDealByKeysQuery(dealKeysQuery)
+
+	var namedParamsQuery []driver.Value
+	for _, value := range paramsDeal {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParamsQuery = append(namedParamsQuery, valueNamed)
+	}
+
+	columns := []string{"POPTION_KEY", "DEAL_TYPE", "TOTAL_QUANTITY", "DN_DIRECTION",
+		"TRANSACTION_DATE", "CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE",
+		"LEGALENTITY", "LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "INTERAFFILIATE_FLAG",
+		"CONTRACTNUMBER", "CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO",
+		"PORTFOLIO", "UR_TRADER", "IB_PRT_PORTFOLIO", "IB_PORTFOLIO", "IB_UR_TRADER",
+		"TZ_TIME_ZONE", "TZ_EXERCISE_ZONE", "HAS_BROKER", "BROKER", "PPEP_PP_POOL",
+		"PPEP_PEP_PRODUCT", "CTP_POINT_CODE", "SETTLE_FORMULA", "DY_BEG_DAY", "DY_END_DAY",
+		"SCH_SCHEDULE", "VOLUME", "STRIKE_PRICE", "STRIKE_PRICE_TYPE", "STRIKE_FORMULA",
+		"CREATEDBY", "MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE", "EXECUTION_DATE",
+		"EXECUTION_TIME", "EXOTIC_FLAG",
+	}
+	mock.ExpectQuery(getNucPowerOptionsDealByKeysQ).WithArgs(namedParamsQuery...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			43435, "POPTS", 17600, "SALE",
+			parseTime("23-05-2022"), 10322, "IBT", "INTERBOOK TRANSFER", "IBT",
+			"SENA", "Shell Energy North America (US), L.P.", 10430, "N",
+			"", "POWER OPT", "EAST", "", 99478,
+			"ERCOT_RETAIL", "GABDULLA", nil, "", "",
+			"CPT", "CPT", "NO", "NA", "ERCNZ",
+			"STD ON", "HB_NORTH", nil, parseTime("01-06-2022"), parseTime("30-06-2022"),
+			"NERC", -50, 100, "F", "",
+			"GABDULLA", "GABDULLA", parseTime("23-05-2022"), parseTime("23-05-2022"), "05/23/2022",
+			"12:00:00 AM", "No",
+		).AddRow(
+			43441, "POPTS", 35200, "PURCHASE",
+			parseTime("23-05-2022"), 10322, "IBT", "INTERBOOK TRANSFER", "IBT",
+			"SENA", "Shell Energy North America (US), L.P.", 10430, "N",
+			"", "POWER OPT", "EAST", "", 220,
+			"SOUTH OTHER HR", "GABDULLA", nil, "", "",
+			"CPT", "CPT", "NO", "NA", "ERCNZ",
+			"STD ON", "HB_NORTH", "[MISO RTLMP|CINERGY.HUB|HOURLY]", parseTime("01-06-2022"), parseTime("30-06-2022"),
+			"NERC", 100, 100, "F", "(10.5*[GD|HOU SHP CHNL|DAILY]) + 3.55",
+			"GABDULLA", "GABDULLA", parseTime("23-05-2022"), parseTime("23-05-2022"), "05/23/2022",
+			"12:00:00 AM", "No",
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx                context.Context
+		lstPowerOptionkeys []float64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:                context.TODO(),
+				lstPowerOptionkeys: lstPowerOptionkeys,
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             43435,
+					DealType:            "POPTS",
+					TotalQuantity:       17600,
+					DnDirection:         "SALE",
+					TransactionDate:     parseTime("23-05-2022"),
+					CyCompanyKey:        10322,
+					Company:             "IBT",
+					CompanyLongName:     "INTERBOOK TRANSFER",
+					CompanyCode:         "IBT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					InteraffiliateFlag:  "N",
+					Contract:            "",
+					ConfirmFormat:       "POWER OPT",
+					Region:              "EAST",
+					HsHedgeKey:          "",
+					PrtPortfolio:        99478,
+					Portfolio:           "ERCOT_RETAIL",
+					UrTrader:            "GABDULLA",
+					IbPrtPortfolio:      0,
+					IbPortfolio:         "",
+					IbUrTrader:          "",
+					TzTimeZone:          "CPT",
+					TzExerciseZone:      "CPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					StartDate:           parseTime("01-06-2022"),
+					EndDate:             parseTime("30-06-2022"),
+					CreatedBy:           "GABDULLA",
+					ModifiedBy:          "GABDULLA",
+					CreatedAt:           parseTime("23-05-2022"),
+					ModifiedAt:          parseTime("23-05-2022"),
+					ExecutionTime:       parseDateTime("05/23/2022", "12:00:00 AM"),
+					ExoticFlag:          "No",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							Pool1:           "ERCNZ",
+							Product1:        "STD ON",
+							PointCode1:      "HB_NORTH",
+							BegDate:         parseTime("01-06-2022"),
+							EndDate:         parseTime("30-06-2022"),
+							HolidaySchedule: "NERC",
+							Volume:          -50,
+							FixedPrice:      100,
+							PriceType:       "F",
+						},
+					},
+				},
+				{
+					DealKey:             43441,
+					DealType:            "POPTS",
+					TotalQuantity:       35200,
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("23-05-2022"),
+					CyCompanyKey:        10322,
+					Company:             "IBT",
+					CompanyLongName:     "INTERBOOK TRANSFER",
+					CompanyCode:         "IBT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					InteraffiliateFlag:  "N",
+					Contract:            "",
+					ConfirmFormat:       "POWER OPT",
+					Region:              "EAST",
+					HsHedgeKey:          "",
+					PrtPortfolio:        220,
+					Portfolio:           "SOUTH OTHER HR",
+					UrTrader:            "GABDULLA",
+					IbPrtPortfolio:      0,
+					IbPortfolio:         "",
+					IbUrTrader:          "",
+					TzTimeZone:          "CPT",
+					TzExerciseZone:      "CPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					StartDate:           parseTime("01-06-2022"),
+					EndDate:             parseTime("30-06-2022"),
+					CreatedBy:           "GABDULLA",
+					ModifiedBy:          "GABDULLA",
+					CreatedAt:           parseTime("23-05-2022"),
+					ModifiedAt:          parseTime("23-05-2022"),
+					ExecutionTime:       parseDateTime("05/23/2022", "12:00:00 AM"),
+					ExoticFlag:          "No",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							Pool1:           "ERCNZ",
+							Product1:        "STD ON",
+							PointCode1:      "HB_NORTH",
+							BegDate:         parseTime("01-06-2022"),
+							EndDate:         parseTime("30-06-2022"),
+							HolidaySchedule: "NERC",
+							Volume:          100,
+							FixedPrice:      100,
+							PriceType:       "F",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "MISO RTLMP",
+									PubIndex:    "CINERGY.HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "GD",
+									PubIndex:    "HOU SHP CHNL",
+									Frequency:   "DAILY",
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucPowerOptionsDealByKeys(tt.args.ctx, tt.args.lstPowerOptionkeys)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealByKeys() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealByKeys() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealByKeys() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealByKeys() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealByKeys() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealByKeys() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealByKeys() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealByKeys() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealByKeys() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucPowerOptionsDealByKeys() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucCapacityDealByKeys(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	lstCapacitykeys := []float64{1104940, 1105338, 1106012}
+
+	dealKeysQuery, paramsDeal, err := oracle.CreateInQueryFloat64(lstCapacitykeys, []interface{}{}, "pd.capacity_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucCapacityDealByKeysQ := getNucCapacityDealByKeysQuery(dealKeysQuery)
+
+	var namedParamsQuery []driver.Value
+	for _, value := range paramsDeal {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParamsQuery = append(namedParamsQuery, valueNamed)
+	}
+
+	columns := []string{"CAPACITY_KEY", "DEAL_TYPE", "TOTAL_QUANTITY", "DN_DIRECTION",
+		"TRANSACTION_DATE", "CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME",
+		"COMPANYCODE", "LEGALENTITY", "LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "INTERAFFILIATE_FLAG",
+		"CONTRACTNUMBER", "CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO",
+		"PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE", "HAS_BROKER", "BROKER", "NON_STANDARD_FLAG",
+		"PRICE_TYPE", "CHARGE", "VOLUME", "ENERGY_FORMULA", "PPCP_PP_POOL", "PPCP_PCP_PRODUCT",
+		"CTP_POINT_CODE", "DY_BEG_DAY", "DY_END_DAY", "SCH_SCHEDULE", "CREATEDBY", "MODIFIEDBY",
+		"CREATE_DATE", "MODIFY_DATE", "EXECUTION_DATE", "EXECUTION_TIME"}
+	mock.ExpectQuery(getNucCapacityDealByKeysQ).WithArgs(namedParamsQuery...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			1104940, "CAPCTY", 972.7, "SALE",
+			parseTime("05-05-2022"), 10322, "IBT", "INTERBOOK TRANSFER",
+			"IBT", "SENA", "Shell Energy North America (US), L.P.", 10430, "N",
+			"", "U-CAP", "SOUTH", "", 99690,
+			"Realtime 7", "COSULLIV", "CPT", "NO", "NA", "N",
+			"F", 0.1, 0, nil, "NSRS", "STD ON",
+			"ERCOT", parseTime("04-05-2022"), parseTime("04-05-2022"), "NONE", "COSULLIV", "COSULLIV",
+			parseTime("05-05-2022"), parseTime("05-05-2022"), "", "",
+		).AddRow(
+			1105338, "CAPCTY", 0.24, "PURCHASE",
+			parseTime("05-05-2022"), 10296, "CAISO", "CALIFORNIA INDEPENDENT SYSTEMS OPERATION CORP DBA CALIF",
+			"CALF IND S", "SENA", "Shell Energy North America (US), L.P.", 10430, "N",
+			"011-KW-IS-06534", "STL CAPCTY", "WEST", "", 301,
+			"SETTLEMENT BRIDGE CAISO - DA", "PCI_ALLO", "PPT", "NO", "NA", "Y",
+			"F", 0, 0, "([ER AS RRS|DA_RRS|HOURLY]*0.85)<CU>USD</CU><UT>MW</UT>", "WSCC", "HOURLY",
+			"PGAE-APND", parseTime("05-05-2022"), parseTime("05-05-2022"), "NERC", "PCI_GSMS", "PCI_GSMS",
+			parseTime("06-05-2022"), parseTime("06-05-2022"), "05/07/2022", "12:00:00 AM",
+		).AddRow(
+			1106012, "CAPCTY", 25, "PURCHASE",
+			parseTime("05-05-2022"), 10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
+			"ERCOT", "SENA", "Shell Energy North America (US), L.P.", 10430, "N",
+			"", "U-CAP", "SOUTH", "", 99690,
+			"Realtime 7", "COSULLIV", "CPT", "NO", "NA", "N",
+			"I", 0, 25, "([ER AS REGUP|DA_REGUP|HOURLY])<CU>USD</CU><UT>MW</UT>", "ERCOT", "HOURLY",
+			"HB_HOUSTON", parseTime("05-05-2022"), parseTime("05-05-2022"), "NONE", "COSULLIV", "COSULLIV",
+			parseTime("05-05-2022"), parseTime("05-05-2022"), "", "",
+		))
+
+	dealKeysArray := []int{1105338}
+
+	dealKeysTermQuery, params, err := oracle.CreateInQueryInt(dealKeysArray, []interface{}{}, "cpd_capacity_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucCapacityDealTermModelQ := getNucCapacityDealTermModelQuery(dealKeysTermQuery)
+
+	var namedParams []driver.Value
+	for _, value := range params {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParams = append(namedParams, valueNamed)
+	}
+
+	columns = []string{"CPD_CAPACITY_KEY", "DY_BEG_DAY", "DY_END_DAY"}
+	mock.ExpectQuery(getNucCapacityDealTermModelQ).WithArgs(namedParams...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			1105338, parseTime("05-05-2022"), parseTime("05-05-2022"),
+		))
+
+	formulaDealKeys := []int{1105338, 1106012}
+
+	formulaDealKeysQuery, params, err := oracle.CreateInQueryInt(formulaDealKeys, []interface{}{}, "cpd_capacity_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucCapacityDealIndexModelQ := getNucCapacityDealIndexModelQuery(formulaDealKeysQuery)
+
+	namedParams = nil
+	for _, value := range params {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParams = append(namedParams, valueNamed)
+	}
+
+	columns = []string{"CPD_CAPACITY_KEY", "PUBLICATION", "PUB_INDEX", "FREQUENCY"}
+	mock.ExpectQuery(getNucCapacityDealIndexModelQ).WithArgs(namedParams...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			1105338, "ER AS RRS", "DA_RRS", "HOURLY",
+		).AddRow(
+			1106012, "ER AS REGUP", "DA_REGUP", "HOURLY",
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx             context.Context
+		lstCapacitykeys []float64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:             context.TODO(),
+				lstCapacitykeys: lstCapacitykeys,
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             1104940,
+					DealType:            "CAPCTY",
+					TotalQuantity:       972.7,
+					DnDirection:         "SALE",
+					TransactionDate:     parseTime("05-05-2022"),
+					CyCompanyKey:        10322,
+					Company:             "IBT",
+					CompanyLongName:     "INTERBOOK TRANSFER",
+					CompanyCode:         "IBT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					InteraffiliateFlag:  "N",
+					Contract:            "",
+					ConfirmFormat:       "U-CAP",
+					Region:              "SOUTH",
+					HsHedgeKey:          "",
+					PrtPortfolio:        99690,
+					Portfolio:           "Realtime 7",
+					UrTrader:            "COSULLIV",
+					TzTimeZone:          "CPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					StartDate:           parseTime("04-05-2022"),
+					EndDate:             parseTime("04-05-2022"),
+					CreatedBy:           "COSULLIV",
+					ModifiedBy:          "COSULLIV",
+					CreatedAt:           parseTime("05-05-2022"),
+					ModifiedAt:          parseTime("05-05-2022"),
+					ExoticFlag:          "NA",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:          0,
+							PriceType:       "F",
+							FixedPrice:      0.1,
+							Volume:          0,
+							Pool1:           "NSRS",
+							Product1:        "STD ON",
+							PointCode1:      "ERCOT",
+							BegDate:         parseTime("04-05-2022"),
+							EndDate:         parseTime("04-05-2022"),
+							HolidaySchedule: "NONE",
+						},
+					},
+				},
+				{
+					DealKey:             1105338,
+					DealType:            "CAPCTY",
+					TotalQuantity:       0.24,
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("05-05-2022"),
+					CyCompanyKey:        10296,
+					Company:             "CAISO",
+					CompanyLongName:     "CALIFORNIA INDEPENDENT SYSTEMS OPERATION CORP DBA CALIF",
+					CompanyCode:         "CALF IND S",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					InteraffiliateFlag:  "N",
+					Contract:            "011-KW-IS-06534",
+					ConfirmFormat:       "STL CAPCTY",
+					Region:              "WEST",
+					HsHedgeKey:          "",
+					PrtPortfolio:        301,
+					Portfolio:           "SETTLEMENT BRIDGE CAISO - DA",
+					UrTrader:            "PCI_ALLO",
+					TzTimeZone:          "PPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					StartDate:           parseTime("05-05-2022"),
+					EndDate:             parseTime("05-05-2022"),
+					CreatedBy:           "PCI_GSMS",
+					ModifiedBy:          "PCI_GSMS",
+					CreatedAt:           parseTime("06-05-2022"),
+					ModifiedAt:          parseTime("06-05-2022"),
+					ExecutionTime:       parseDateTime("05/07/2022", "12:00:00 AM"),
+					ExoticFlag:          "NA",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:          0,
+							PriceType:       "F",
+							Pool1:           "WSCC",
+							Product1:        "HOURLY",
+							PointCode1:      "PGAE-APND",
+							HolidaySchedule: "NERC",
+							BegDate:         parseTime("05-05-2022"),
+							EndDate:         parseTime("05-05-2022"),
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER AS RRS",
+									PubIndex:    "DA_RRS",
+									Frequency:   "HOURLY",
+								},
+							},
+						},
+					},
+				},
+				{
+					DealKey:             1106012,
+					DealType:            "CAPCTY",
+					TotalQuantity:       25,
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("05-05-2022"),
+					CyCompanyKey:        10171,
+					Company:             "ERCOT",
+					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
+					CompanyCode:         "ERCOT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					InteraffiliateFlag:  "N",
+					Contract:            "",
+					ConfirmFormat:       "U-CAP",
+					Region:              "SOUTH",
+					HsHedgeKey:          "",
+					PrtPortfolio:        99690,
+					Portfolio:           "Realtime 7",
+					UrTrader:            "COSULLIV",
+					TzTimeZone:          "CPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					StartDate:           parseTime("05-05-2022"),
+					EndDate:             parseTime("05-05-2022"),
+					CreatedBy:           "COSULLIV",
+					ModifiedBy:          "COSULLIV",
+					CreatedAt:           parseTime("05-05-2022"),
+					ModifiedAt:          parseTime("05-05-2022"),
+					ExoticFlag:          "NA",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:          0,
+							PriceType:       "I",
+							Volume:          25,
+							Pool1:           "ERCOT",
+							Product1:        "HOURLY",
+							PointCode1:      "HB_HOUSTON",
+							BegDate:         parseTime("05-05-2022"),
+							EndDate:         parseTime("05-05-2022"),
+							HolidaySchedule: "NONE",
+							Formula1:        "([ER AS REGUP|DA_REGUP|HOURLY])<CU>USD</CU><UT>MW</UT>",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER AS REGUP",
+									PubIndex:    "DA_REGUP",
+									Frequency:   "HOURLY",
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucCapacityDealByKeys(tt.args.ctx, tt.args.lstCapacitykeys)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucCapacityDealByKeys() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucCapacityDealByKeys() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for index, wantedValue := range tt.want {
+				if !reflect.DeepEqual(wantedValue, got[index]) {
+					t.Errorf("NucleusTradeRepository.GetNucCapacityDealByKeys() wantedValue = %+v, value = %+v", wantedValue, got[index])
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucEmissionDealByKeys(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	lstEmissionkeys := []float64{61150, 61151}
+
+	dealKeysQuery, paramsDeal, err := oracle.CreateInQueryFloat64(lstEmissionkeys, []interface{}{}, "pd.emission_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucEmissionDealByKeysQ := getNucEmissionDealByKeysQuery(dealKeysQuery)
+
+	var namedParamsQuery []driver.Value
+	for _, value := range paramsDeal {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParamsQuery = append(namedParamsQuery, valueNamed)
+	}
+
+	columns := []string{"EMISSION_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
+		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY",
+		"LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT",
+		"REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE",
+		"HAS_BROKER", "BROKER", "CREATEDBY", "MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE",
+		"EXECUTION_DATE", "EXECUTION_TIME"}
+	mock.ExpectQuery(getNucEmissionDealByKeysQ).WithArgs(namedParamsQuery...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			61150, "EMSSN", "SALE", parseTime("05-05-2022"),
+			17038, "CSU", "THE BOARD OF TRUSTEES OF THE CALIFORNIA STATE UNIV", "CSU", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "013-KW-SU-21400", "EMISSION",
+			"WEST", "CandI CRED", 99297, "WEST ENVIRON - CA RETAIL", "SGAPPY", "",
+			"NO", "NA", "SGAPPY", "SGAPPY", parseTime("05-05-2022"), parseTime("05-05-2022"),
+			"", "",
+		).AddRow(
+			61151, "EMSSN", "SALE", parseTime("05-05-2022"),
+			17038, "CSU", "THE BOARD OF TRUSTEES OF THE CALIFORNIA STATE UNIV", "CSU", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "013-KW-SU-21400", "EMISSION",
+			"WEST", "CandI CRED", 99297, "WEST ENVIRON - CA RETAIL", "SGAPPY", "",
+			"NO", "NA", "SGAPPY", "SGAPPY", parseTime("05-05-2022"), parseTime("05-05-2022"),
+			"", "",
+		))
+
+	dealKeysArray := []int{61150, 61151}
+
+	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeysArray, []interface{}{}, "pv.ed_emission_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucCapacityDealTermModelQ := getNucEmissionDealListTermModelQuery(dealKeysQuery)
+
+	var namedParams []driver.Value
+	for _, value := range params {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParams = append(namedParams, valueNamed)
+	}
+
+	columns = []string{"ED_EMISSION_KEY", "VOLUME_SEQ", "DY_BEG_DAY", "DY_END_DAY",
+		"PRICE_TYPE", "PRICE", "VOLUME", "EPDT_EMISSION_PRODUCT", "CTP_POINT_CODE",
+		"FORMULA"}
+	mock.ExpectQuery(getNucCapacityDealTermModelQ).WithArgs(namedParams...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			61150, 1, parseTime("01-08-2023"), parseTime("31-08-2023"),
+			"F", 16.85, 10662, "CA REC", "MID-C",
+			"[OPIS|CELRINC|DAILY] *0.95",
+		).AddRow(
+			61151, 1, parseTime("01-09-2023"), parseTime("30-09-2023"),
+			"F", 16.85, 11117, "CA REC", "MID-C",
+			"",
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx             context.Context
+		lstEmissionkeys []float64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "normal run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:             context.TODO(),
+				lstEmissionkeys: lstEmissionkeys,
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             61150,
+					DealType:            "EMSSN",
+					DnDirection:         "SALE",
+					TransactionDate:     parseTime("05-05-2022"),
+					CyCompanyKey:        17038,
+					Company:             "CSU",
+					CompanyLongName:     "THE BOARD OF TRUSTEES OF THE CALIFORNIA STATE UNIV",
+					CompanyCode:         "CSU",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "013-KW-SU-21400",
+					ConfirmFormat:       "EMISSION",
+					Region:              "WEST",
+					HsHedgeKey:          "CandI CRED",
+					PrtPortfolio:        99297,
+					Portfolio:           "WEST ENVIRON - CA RETAIL",
+					UrTrader:            "SGAPPY",
+					TzTimeZone:          "",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					CreatedBy:           "SGAPPY",
+					ModifiedBy:          "SGAPPY",
+					CreatedAt:           parseTime("05-05-2022"),
+					ModifiedAt:          parseTime("05-05-2022"),
+					ExoticFlag:          "NA",
+					InteraffiliateFlag:  "N",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:     1,
+							BegDate:    parseTime("01-08-2023"),
+							EndDate:    parseTime("31-08-2023"),
+							PriceType:  "F",
+							FixedPrice: 16.85,
+							Volume:     10662,
+							Product1:   "CA REC",
+							PointCode1: "MID-C",
+							Formula1:   "[OPIS|CELRINC|DAILY] *0.95",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "OPIS",
+									PubIndex:    "CELRINC",
+									Frequency:   "DAILY",
+								},
+							},
+						},
+					},
+				},
+				{
+					DealKey:             61151,
+					DealType:            "EMSSN",
+					DnDirection:         "SALE",
+					TransactionDate:     parseTime("05-05-2022"),
+					CyCompanyKey:        17038,
+					Company:             "CSU",
+					CompanyLongName:     "THE BOARD OF TRUSTEES OF THE CALIFORNIA STATE UNIV",
+					CompanyCode:         "CSU",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "013-KW-SU-21400",
+					ConfirmFormat:       "EMISSION",
+					Region:              "WEST",
+					HsHedgeKey:          "CandI CRED",
+					PrtPortfolio:        99297,
+					Portfolio:           "WEST ENVIRON - CA RETAIL",
+					UrTrader:            "SGAPPY",
+					TzTimeZone:          "",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					CreatedBy:           "SGAPPY",
+					ModifiedBy:          "SGAPPY",
+					CreatedAt:           parseTime("05-05-2022"),
+					ModifiedAt:          parseTime("05-05-2022"),
+					ExoticFlag:          "NA",
+					InteraffiliateFlag:  "N",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:     1,
+							BegDate:    parseTime("01-09-2023"),
+							EndDate:    parseTime("30-09-2023"),
+							PriceType:  "F",
+							FixedPrice: 16.85,
+							Volume:     11117,
+							Product1:   "CA REC",
+							PointCode1: "MID-C",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucEmissionDealByKeys(tt.args.ctx, tt.args.lstEmissionkeys)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucEmissionDealByKeys() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucEmissionDealByKeys() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucEmissionDealByKeys() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucEmissionDealByKeys() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucEmissionDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucEmissionDealByKeys() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucEmissionDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucEmissionDealByKeys() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucEmissionDealByKeys() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucEmissionDealByKeys() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucEmissionDealByKeys() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucEmissionDealByKeys() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucEmissionOptionDealByKeys(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	lstEmissionkeys := []float64{12706, 12711}
+
+	dealKeysQuery, paramsDeal, err := oracle.CreateInQueryFloat64(lstEmissionkeys, []interface{}{}, "pd.eoption_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucEmissionOptionDealByKeysQ := getNucEmissionOptionDealByKeysQuery(dealKeysQuery)
+
+	var namedParamsQuery []driver.Value
+	for _, value := range paramsDeal {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParamsQuery = append(namedParamsQuery, valueNamed)
+	}
+
+	columns := []string{"EOPTION_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
+		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY",
+		"LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT", "REGION",
+		"HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE",
+		"HAS_BROKER", "BROKER", "ED_EMISSION_KEY", "STRIKE_PRICE", "VOLUME", "CREATEDBY",
+		"MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE", "EXECUTION_DATE", "EXECUTION_TIME"}
+	mock.ExpectQuery(getNucEmissionOptionDealByKeysQ).WithArgs(namedParamsQuery...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			12706, "EMOPTS", "SALE", parseTime("27-01-2016"),
+			16370, "NEWFIN", "NEWEDGE FINANCIAL INC", "NEWFIN", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "", "EMSSN OPT", "EAST",
+			"REALIZED", 99444, "HOUSTON EMISSIONS FIN US", "DEKING", "EST",
+			"YES", "TFS", 31321, 7.5, -250000, "DEKING",
+			"NXTGEN", parseTime("27-01-2016"), parseTime("14-12-2016"), "01/27/2016", "10:07:59 AM",
+		).AddRow(
+			12711, "EMOPTS", "PURCHASE", parseTime("27-01-2016"),
+			16370, "NEWFIN", "NEWEDGE FINANCIAL INC", "NEWFIN", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "", "EMSSN OPT", "EAST",
+			"REALIZED", 99444, "HOUSTON EMISSIONS FIN US", "DEKING", "EST",
+			"NO", "NA", 31326, 8, 700000, "DEKING",
+			"NXTGEN", parseTime("27-01-2016"), parseTime("14-12-2016"), "01/27/2016", "10:12:59 AM",
+		))
+
+	dealKeysArray := []int{31321, 31326}
+
+	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeysArray, []interface{}{}, "pv.ed_emission_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucEmissionOptionDealTermListQ := getNucEmissionOptionDealTermListQuery(dealKeysQuery)
+
+	var namedParams []driver.Value
+	for _, value := range params {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParams = append(namedParams, valueNamed)
+	}
+
+	columns = []string{"ED_EMISSION_KEY", "VOLUME_SEQ", "DY_BEG_DAY", "DY_END_DAY",
+		"CTP_POINT_CODE", "EPDT_EMISSION_PRODUCT"}
+	mock.ExpectQuery(getNucEmissionOptionDealTermListQ).WithArgs(namedParams...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			31321, 1, parseTime("01-04-2010"), parseTime("30-04-2010"), "MID-C", "CA REC",
+		).AddRow(
+			31326, 1, parseTime("01-04-2010"), parseTime("30-04-2010"), "MID-C", "CA REC",
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx             context.Context
+		lstEmissionkeys []float64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:             context.TODO(),
+				lstEmissionkeys: lstEmissionkeys,
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             12706,
+					DealType:            "EMOPTS",
+					DnDirection:         "SALE",
+					TransactionDate:     parseTime("27-01-2016"),
+					CyCompanyKey:        16370,
+					Company:             "NEWFIN",
+					CompanyLongName:     "NEWEDGE FINANCIAL INC",
+					CompanyCode:         "NEWFIN",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "",
+					ConfirmFormat:       "EMSSN OPT",
+					Region:              "EAST",
+					HsHedgeKey:          "REALIZED",
+					PrtPortfolio:        99444,
+					Portfolio:           "HOUSTON EMISSIONS FIN US",
+					UrTrader:            "DEKING",
+					TzTimeZone:          "EST",
+					HasBroker:           "YES",
+					Broker:              "TFS",
+					CreatedBy:           "DEKING",
+					ModifiedBy:          "NXTGEN",
+					CreatedAt:           parseTime("27-01-2016"),
+					ModifiedAt:          parseTime("14-12-2016"),
+					ExecutionTime:       parseDateTime("01/27/2016", "10:07:59 AM"),
+					ExoticFlag:          "NA",
+					InteraffiliateFlag:  "N",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:     1,
+							FixedPrice: 7.5,
+							Volume:     -250000,
+							BegDate:    parseTime("01-04-2010"),
+							EndDate:    parseTime("30-04-2010"),
+							PointCode1: "MID-C",
+							Product1:   "CA REC",
+						},
+					},
+				},
+				{
+					DealKey:             12711,
+					DealType:            "EMOPTS",
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("27-01-2016"),
+					CyCompanyKey:        16370,
+					Company:             "NEWFIN",
+					CompanyLongName:     "NEWEDGE FINANCIAL INC",
+					CompanyCode:         "NEWFIN",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "",
+					ConfirmFormat:       "EMSSN OPT",
+					Region:              "EAST",
+					HsHedgeKey:          "REALIZED",
+					PrtPortfolio:        99444,
+					Portfolio:           "HOUSTON EMISSIONS FIN US",
+					UrTrader:            "DEKING",
+					TzTimeZone:          "EST",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					CreatedBy:           "DEKING",
+					ModifiedBy:          "NXTGEN",
+					CreatedAt:           parseTime("27-01-2016"),
+					ModifiedAt:          parseTime("14-12-2016"),
+					ExecutionTime:       parseDateTime("01/27/2016", "10:12:59 AM"),
+					ExoticFlag:          "NA",
+					InteraffiliateFlag:  "N",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:     1,
+							FixedPrice: 8,
+							Volume:     700000,
+							BegDate:    parseTime("01-04-2010"),
+							EndDate:    parseTime("30-04-2010"),
+							PointCode1: "MID-C",
+							Product1:   "CA REC",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucEmissionOptionDealByKeys(tt.args.ctx, tt.args.lstEmissionkeys)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealByKeys() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealByKeys() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealByKeys() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealByKeys() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealByKeys() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealByKeys() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealByKeys() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealByKeys() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealByKeys() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucEmissionOptionDealByKeys() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucTCCFTRSDealByKeys(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	lstTccFtrskeys := []float64{262325, 262147}
+
+	dealKeysQuery, paramsDeal, err := oracle.CreateInQueryFloat64(lstTccFtrskeys, []interface{}{}, "pd.deal_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucTCCFTRSDealByKeysQ := getNucTCCFTRSDealByKeysQuery(dealKeysQuery)
+
+	var namedParamsQuery []driver.Value
+	for _, value := range paramsDeal {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParamsQuery = append(namedParamsQuery, valueNamed)
+	}
+	namedParamsQuery = append(namedParamsQuery, sql.Named("strDealType", "FTRSWP"))
+
+	columns := []string{"DEAL_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
+		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY",
+		"LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT",
+		"REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE",
+		"HAS_BROKER", "BROKER", "DY_BEG_DAY", "DY_END_DAY", "SCH_SCHEDULE", "VOLUME",
+		"FIXED_PRICE", "PPEP_PP_POOL", "PPEP_PEP_PRODUCT", "PI_PB_PUBLICATION", "FRQ_FREQUENCY",
+		"POI_PI_PUB_INDEX", "POW_PI_PUB_INDEX", "CREATEDBY", "MODIFIEDBY", "CREATE_DATE",
+		"MODIFY_DATE"}
+	mock.ExpectQuery(getNucTCCFTRSDealByKeysQ).WithArgs(namedParamsQuery...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			262325, "FTRSWP", "PURCHASE", parseTime("02-06-2022"),
+			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "", "FTRSWAP",
+			"SOUTH", "", 99860, "SP ERCOT CRR", "SPARK", "CPT",
+			"NO", "NA", parseTime("01-03-2023"), parseTime("31-03-2023"), "NERC", 100,
+			3.0205, "ERCRR", "STD ON", "ER DA LMP", "HOURLY",
+			"SOUTH_HUB", "LZ_LCRA", "PCI_GSMS", "PCI_GSMS", parseTime("02-06-2022"),
+			parseTime("02-06-2022"),
+		).AddRow(
+			262147, "FTRSWP", "PURCHASE", parseTime("02-06-2022"),
+			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "", "FTRSWAP",
+			"SOUTH", "", 99860, "SP ERCOT CRR", "SPARK", "CPT",
+			"NO", "NA", parseTime("01-01-2023"), parseTime("31-01-2023"), "NERC", 25,
+			4.10714, "ERCRR", "STD ON", "ER DA LMP", "HOURLY",
+			"SOUTH_HUB", "NORTH_HUB", "PCI_GSMS", "PCI_GSMS", parseTime("02-06-2022"),
+			parseTime("02-06-2022"),
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx            context.Context
+		lstTccFtrskeys []float64
+		strDealType    string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:            context.TODO(),
+				lstTccFtrskeys: lstTccFtrskeys,
+				strDealType:    "FTRSWP",
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             262325,
+					DealType:            "FTRSWP",
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("02-06-2022"),
+					CyCompanyKey:        10171,
+					Company:             "ERCOT",
+					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
+					CompanyCode:         "ERCOT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "",
+					ConfirmFormat:       "FTRSWAP",
+					Region:              "SOUTH",
+					HsHedgeKey:          "",
+					PrtPortfolio:        99860,
+					Portfolio:           "SP ERCOT CRR",
+					UrTrader:            "SPARK",
+					TzTimeZone:          "CPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					CreatedBy:           "PCI_GSMS",
+					ModifiedBy:          "PCI_GSMS",
+					CreatedAt:           parseTime("02-06-2022"),
+					ModifiedAt:          parseTime("02-06-2022"),
+					ExoticFlag:          "NA",
+					InteraffiliateFlag:  "N",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:          0,
+							BegDate:         parseTime("01-03-2023"),
+							EndDate:         parseTime("31-03-2023"),
+							HolidaySchedule: "NERC",
+							Volume:          100,
+							FixedPrice:      3.0205,
+							Pool1:           "ERCRR",
+							Product1:        "STD ON",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER DA LMP",
+									PubIndex:    "SOUTH_HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER DA LMP",
+									PubIndex:    "LZ_LCRA",
+									Frequency:   "HOURLY",
+								},
+							},
+						},
+					},
+				},
+				{
+					DealKey:             262147,
+					DealType:            "FTRSWP",
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("02-06-2022"),
+					CyCompanyKey:        10171,
+					Company:             "ERCOT",
+					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
+					CompanyCode:         "ERCOT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "",
+					ConfirmFormat:       "FTRSWAP",
+					Region:              "SOUTH",
+					HsHedgeKey:          "",
+					PrtPortfolio:        99860,
+					Portfolio:           "SP ERCOT CRR",
+					UrTrader:            "SPARK",
+					TzTimeZone:          "CPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					CreatedBy:           "PCI_GSMS",
+					ModifiedBy:          "PCI_GSMS",
+					CreatedAt:           parseTime("02-06-2022"),
+					ModifiedAt:          parseTime("02-06-2022"),
+					ExoticFlag:          "NA",
+					InteraffiliateFlag:  "N",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:          0,
+							BegDate:         parseTime("01-01-2023"),
+							EndDate:         parseTime("31-01-2023"),
+							HolidaySchedule: "NERC",
+							Volume:          25,
+							FixedPrice:      4.10714,
+							Pool1:           "ERCRR",
+							Product1:        "STD ON",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER DA LMP",
+									PubIndex:    "SOUTH_HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER DA LMP",
+									PubIndex:    "NORTH_HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucTCCFTRSDealByKeys(tt.args.ctx, tt.args.lstTccFtrskeys, tt.args.strDealType)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealByKeys() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealByKeys() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealByKeys() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealByKeys() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealByKeys() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealByKeys() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealByKeys() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealByKeys() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealByKeys() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealByKeys() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucSpreadOptionsDealByKeys(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	lstSpreadOptionkeys := []float64{77283, 77294}
+
+	dealKeysQuery, paramsDeal, err := oracle.CreateInQueryFloat64(lstSpreadOptionkeys, []interface{}{}, "pd.spread_option_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucSpreadOptionsDealByKeysQ := getNucSpreadOptionsDealByKeysQuery(dealKeysQuery)
+
+	var namedParamsQuery []driver.Value
+	for _, value := range paramsDeal {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParamsQuery = append(namedParamsQuery, valueNamed)
+	}
+
+	columns := []string{"SPREAD_OPTION_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
+		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY", "LEGALENTITYLONGNAME",
+		"CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO",
+		"PORTFOLIO", "UR_TRADER", "IB_PRT_PORTFOLIO", "IB_PORTFOLIO", "IB_UR_TRADER", "TZ_TIME_ZONE",
+		"HAS_BROKER", "BROKER", "DY_BEG_DAY1", "DY_END_DAY1", "SCH_SCHEDULE", "FORMULA1", "FORMULA2",
+		"PPEP_PP_POOL1", "PPEP_PP_POOL2", "PPEP_PEP_PRODUCT1", "PPEP_PEP_PRODUCT2", "VOLUME",
+		"STRIKE_PRICE", "CREATEDBY", "MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE", "EXECUTION_DATE",
+		"EXECUTION_TIME", "EXOTIC_FLAG", "POINT_CODE"}
+	mock.ExpectQuery(getNucSpreadOptionsDealByKeysQ).WithArgs(namedParamsQuery...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			77283, "SPDOPT", "PURCHASE", parseTime("25-05-2022"),
+			21589, "MIDTOPO", "MIDDLETOWN POWER LLC", "MIDTOPO", "SENA", "Shell Energy North America (US), L.P.",
+			10430, "013-KW-BI-32897", "DF7EXPRESO", "EAST", "FOGECA", 99896,
+			"FOGECA", "GGULYASS", nil, "", "", "",
+			"NO", "NA", parseTime("01-06-2022"), parseTime("30-06-2022"), "NERC", "[NE DA LMP|4000|HOURLY]", "[GD|TENN Z6 SOUTH|DAILY]",
+			"XMIDX", "", "STD 7x24", "", 226,
+			11, "GGULYASS", "GGULYASS", parseTime("26-05-2022"), parseTime("26-05-2022"), "05/26/2022",
+			"", "Yes", "NOT APPLICABLE",
+		).AddRow(
+			77294, "SPDOPT", "PURCHASE", parseTime("25-05-2022"),
+			21589, "MIDTOPO", "MIDDLETOWN POWER LLC", "MIDTOPO", "SENA", "Shell Energy North America (US), L.P.",
+			10430, "013-KW-BI-32897", "DF7EXPRESO", "EAST", "FOGECA", 99896,
+			"FOGECA", "GGULYASS", nil, "", "", "",
+			"NO", "NA", parseTime("01-10-2022"), parseTime("31-10-2022"), "NERC", "([PJM DA LMP|34887979|HOURLY])", "([IF|MICHCON LEU|MONTHLY] *1.0163)",
+			"XMIDX", "", "STD 7x24", "", 117,
+			10.65, "GGULYASS", "GGULYASS", parseTime("26-05-2022"), parseTime("26-05-2022"), "05/26/2022",
+			"", "Yes", "NOT APPLICABLE",
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx                 context.Context
+		lstSpreadOptionkeys []float64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:                 context.TODO(),
+				lstSpreadOptionkeys: lstSpreadOptionkeys,
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             77283,
+					DealType:            "SPDOPT",
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("25-05-2022"),
+					CyCompanyKey:        21589,
+					Company:             "MIDTOPO",
+					CompanyLongName:     "MIDDLETOWN POWER LLC",
+					CompanyCode:         "MIDTOPO",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "013-KW-BI-32897",
+					ConfirmFormat:       "DF7EXPRESO",
+					Region:              "EAST",
+					HsHedgeKey:          "FOGECA",
+					PrtPortfolio:        99896,
+					Portfolio:           "FOGECA",
+					UrTrader:            "GGULYASS",
+					IbPrtPortfolio:      0,
+					IbPortfolio:         "",
+					IbUrTrader:          "",
+					TzTimeZone:          "",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					StartDate:           parseTime("01-06-2022"),
+					EndDate:             parseTime("30-06-2022"),
+					CreatedBy:           "GGULYASS",
+					ModifiedBy:          "GGULYASS",
+					CreatedAt:           parseTime("26-05-2022"),
+					ModifiedAt:          parseTime("26-05-2022"),
+					ExecutionTime:       parseDateTime("05/26/2022", ""),
+					ExoticFlag:          "Yes",
+					InteraffiliateFlag:  "N",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							HolidaySchedule: "NERC",
+							Pool1:           "XMIDX",
+							Pool2:           "",
+							Product1:        "STD 7x24",
+							Product2:        "",
+							Volume:          226,
+							FixedPrice:      11,
+							PointCode1:      "NOT APPLICABLE",
+							BegDate:         parseTime("01-06-2022"),
+							EndDate:         parseTime("30-06-2022"),
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "NE DA LMP",
+									PubIndex:    "4000",
+									Frequency:   "HOURLY",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "GD",
+									PubIndex:    "TENN Z6 SOUTH",
+									Frequency:   "DAILY",
+								},
+							},
+						},
+					},
+				},
+				{
+					DealKey:             77294,
+					DealType:            "SPDOPT",
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("25-05-2022"),
+					CyCompanyKey:        21589,
+					Company:             "MIDTOPO",
+					CompanyLongName:     "MIDDLETOWN POWER LLC",
+					CompanyCode:         "MIDTOPO",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "013-KW-BI-32897",
+					ConfirmFormat:       "DF7EXPRESO",
+					Region:              "EAST",
+					HsHedgeKey:          "FOGECA",
+					PrtPortfolio:        99896,
+					Portfolio:           "FOGECA",
+					UrTrader:            "GGULYASS",
+					IbPrtPortfolio:      0,
+					IbPortfolio:         "",
+					IbUrTrader:          "",
+					TzTimeZone:          "",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					StartDate:           parseTime("01-10-2022"),
+					EndDate:             parseTime("31-10-2022"),
+					CreatedBy:           "GGULYASS",
+					ModifiedBy:          "GGULYASS",
+					CreatedAt:           parseTime("26-05-2022"),
+					ModifiedAt:          parseTime("26-05-2022"),
+					ExecutionTime:       parseDateTime("05/26/2022", ""),
+					ExoticFlag:          "Yes",
+					InteraffiliateFlag:  "N",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							HolidaySchedule: "NERC",
+							Pool1:           "XMIDX",
+							Pool2:           "",
+							Product1:        "STD 7x24",
+							Product2:        "",
+							Volume:          117,
+							FixedPrice:      10.65,
+							PointCode1:      "NOT APPLICABLE",
+							BegDate:         parseTime("01-10-2022"),
+							EndDate:         parseTime("31-10-2022"),
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "PJM DA LMP",
+									PubIndex:    "34887979",
+									Frequency:   "HOURLY",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "IF",
+									PubIndex:    "MICHCON LEU",
+									Frequency:   "MONTHLY",
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucSpreadOptionsDealByKeys(tt.args.ctx, tt.args.lstSpreadOptionkeys)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealByKeys() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealByKeys() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealByKeys() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealByKeys() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealByKeys() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealByKeys() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealByKeys() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealByKeys() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealByKeys() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucSpreadOptionsDealByKeys() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucHeatRateSwapsDealByKeys(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	lstHeatRateSwapskeys := []float64{16495, 16496}
+
+	dealKeysQuery, paramsDeal, err := oracle.CreateInQueryFloat64(lstHeatRateSwapskeys, []interface{}{}, "pd.hrswps_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucHeatRateSwapsDealByKeysQ := getNucHeatRateSwapsDealByKeysQuery(dealKeysQuery)
+
+	var namedParamsQuery []driver.Value
+	for _, value := range paramsDeal {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParamsQuery = append(namedParamsQuery, valueNamed)
+	}
+
+	columns := []string{"HRSWPS_KEY", "DEAL_TYPE", "TOTAL_QUANTITY", "DN_DIRECTION",
+		"TRANSACTION_DATE", "CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE",
+		"LEGALENTITY", "LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "INTERAFFILIATE_FLAG",
+		"CONTRACTNUMBER", "CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO",
+		"PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE", "HAS_BROKER", "BROKER", "OPTION_KEY",
+		"PPEP_PP_POOL", "PPEP_PEP_PRODUCT", "PIF_PI_PB_PUBLICATION1", "PIF_PI_PUB_INDEX1",
+		"PIF_PI_PB_PUBLICATION2", "PIF_PI_PUB_INDEX2", "PIF_FRQ_FREQUENCY2", "DY_BEG_DAY",
+		"DY_END_DAY", "SCH_SCHEDULE", "VOLUME1", "CREATEDBY", "MODIFIEDBY", "CREATE_DATE",
+		"MODIFY_DATE", "EXECUTION_DATE", "EXECUTION_TIME"}
+	mock.ExpectQuery(getNucHeatRateSwapsDealByKeysQ).WithArgs(namedParamsQuery...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			16495, "HRSWPS", 175200, "SALE",
+			parseTime("01-06-2022"), 10105, "O&R", "ORANGE AND ROCKLAND UTILITIES INC", "ORNGE&RCK",
+			"STRM", "SHELL TRADING RISK MANAGEMENT LLC", 18726, "N",
+			"033-RM-FI-10415", "HR SWAP", "EAST", "", 99482,
+			"POWER_RM_CONSUMERS", "DMOLIN", "", "NO", "NA", nil,
+			"NYCAG", "STD 7x24", "NY DA LMP", "61758",
+			"NYMEX", "NG", "MONTHLY", parseTime("23-01-2023"),
+			parseTime("31-12-2023"), "NERC", -20, "DMOLIN", "DMOLIN", parseTime("01-06-2022"),
+			parseTime("01-06-2022"), "06/01/2022", "09:10:00 AM",
+		).AddRow(
+			16496, "HRSWPS", 175200, "PURCHASE",
+			parseTime("01-06-2022"), 10430, "SENA", "Shell Energy North America (US), L.P.", "SENA",
+			"STRM", "SHELL TRADING RISK MANAGEMENT LLC", 18726, "Y",
+			"033-RM-FI-20570", "HR SWAP", "EAST", "", 99482,
+			"POWER_RM_CONSUMERS", "DMOLIN", "", "NO", "NA", nil,
+			"NYCAG", "STD 7x24", "NY DA LMP", "61758",
+			"NYMEX", "NG", "MONTHLY", parseTime("23-01-2023"),
+			parseTime("31-12-2023"), "NERC", 20, "DMOLIN", "DMOLIN", parseTime("01-06-2022"),
+			parseTime("01-06-2022"), "06/01/2022", "09:10:00 AM",
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx                  context.Context
+		lstHeatRateSwapskeys []float64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "test regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:                  context.TODO(),
+				lstHeatRateSwapskeys: lstHeatRateSwapskeys,
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             16495,
+					DealType:            "HRSWPS",
+					TotalQuantity:       175200,
+					DnDirection:         "SALE",
+					TransactionDate:     parseTime("01-06-2022"),
+					CyCompanyKey:        10105,
+					Company:             "O&R",
+					CompanyLongName:     "ORANGE AND ROCKLAND UTILITIES INC",
+					CompanyCode:         "ORNGE&RCK",
+					LegalEntity:         "STRM",
+					LegalEntityLongName: "SHELL TRADING RISK MANAGEMENT LLC",
+					CyLegalEntityKey:    18726,
+					InteraffiliateFlag:  "N",
+					Contract:            "033-RM-FI-10415",
+					ConfirmFormat:       "HR SWAP",
+					Region:              "EAST",
+					HsHedgeKey:          "",
+					PrtPortfolio:        99482,
+					Portfolio:           "POWER_RM_CONSUMERS",
+					UrTrader:            "DMOLIN",
+					TzTimeZone:          "",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					ExercisedOptionKey:  0,
+					StartDate:           parseTime("23-01-2023"),
+					EndDate:             parseTime("31-12-2023"),
+					CreatedBy:           "DMOLIN",
+					ModifiedBy:          "DMOLIN",
+					CreatedAt:           parseTime("01-06-2022"),
+					ModifiedAt:          parseTime("01-06-2022"),
+					ExecutionTime:       parseDateTime("06/01/2022", "09:10:00 AM"),
+					ExoticFlag:          "NA",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:          0,
+							Pool1:           "NYCAG",
+							Product1:        "STD 7x24",
+							HolidaySchedule: "NERC",
+							Volume:          -20,
+							BegDate:         parseTime("23-01-2023"),
+							EndDate:         parseTime("31-12-2023"),
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "NY DA LMP",
+									PubIndex:    "61758",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "NYMEX",
+									PubIndex:    "NG",
+									Frequency:   "MONTHLY",
+								},
+								{
+									Publication: "NYMEX",
+									PubIndex:    "NG",
+									Frequency:   "MONTHLY",
+								},
+							},
+						},
+					},
+				},
+				{
+					DealKey:             16496,
+					DealType:            "HRSWPS",
+					TotalQuantity:       175200,
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("01-06-2022"),
+					CyCompanyKey:        10430,
+					Company:             "SENA",
+					CompanyLongName:     "Shell Energy North America (US), L.P.",
+					CompanyCode:         "SENA",
+					LegalEntity:         "STRM",
+					LegalEntityLongName: "SHELL TRADING RISK MANAGEMENT LLC",
+					CyLegalEntityKey:    18726,
+					InteraffiliateFlag:  "Y",
+					Contract:            "033-RM-FI-20570",
+					ConfirmFormat:       "HR SWAP",
+					Region:              "EAST",
+					HsHedgeKey:          "",
+					PrtPortfolio:        99482,
+					Portfolio:           "POWER_RM_CONSUMERS",
+					UrTrader:            "DMOLIN",
+					TzTimeZone:          "",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					ExercisedOptionKey:  0,
+					StartDate:           parseTime("23-01-2023"),
+					EndDate:             parseTime("31-12-2023"),
+					CreatedBy:           "DMOLIN",
+					ModifiedBy:          "DMOLIN",
+					CreatedAt:           parseTime("01-06-2022"),
+					ModifiedAt:          parseTime("01-06-2022"),
+					ExecutionTime:       parseDateTime("06/01/2022", "09:10:00 AM"),
+					ExoticFlag:          "NA",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:          0,
+							Pool1:           "NYCAG",
+							Product1:        "STD 7x24",
+							HolidaySchedule: "NERC",
+							Volume:          20,
+							BegDate:         parseTime("23-01-2023"),
+							EndDate:         parseTime("31-12-2023"),
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "NY DA LMP",
+									PubIndex:    "61758",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "NYMEX",
+									PubIndex:    "NG",
+									Frequency:   "MONTHLY",
+								},
+								{
+									Publication: "NYMEX",
+									PubIndex:    "NG",
+									Frequency:   "MONTHLY",
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucHeatRateSwapsDealByKeys(tt.args.ctx, tt.args.lstHeatRateSwapskeys)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealByKeys() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealByKeys() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealByKeys() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealByKeys() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealByKeys() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealByKeys() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealByKeys() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealByKeys() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealByKeys() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucHeatRateSwapsDealByKeys() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucTransmissionDealByKeys(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	lstTranskeys := []float64{323931, 323934}
+
+	dealKeysQuery, paramsDeal, err := oracle.CreateInQueryFloat64(lstTranskeys, []interface{}{}, "pd.trans_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucTransmissionDealByKeysQ := getNucTransmissionDealByKeysQuery(dealKeysQuery)
+
+	var namedParamsQuery []driver.Value
+	for _, value := range paramsDeal {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParamsQuery = append(namedParamsQuery, valueNamed)
+	}
+
+	columns := []string{"TRANS_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
+		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY",
+		"LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT",
+		"REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE",
+		"HAS_BROKER", "BROKER", "CREATEDBY", "MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE",
+		"EXECUTION_TIME"}
+
+	mock.ExpectQuery(getNucTransmissionDealByKeysQ).WithArgs(namedParamsQuery...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			323931, "TRANS", "PURCHASE", parseTime("02-06-2022"),
+			10133, "SMUD", "SACRAMENTO MUNICIPAL UTILITY DIST", "SMUD", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "011-KW-BI-03761", "TRANS",
+			"WEST", "N", 288, "SD - TRANS", "LHILER", "PPT",
+			"NO", "NA", "LHILER", "LHILER", parseTime("02-06-2022"), parseTime("02-06-2022"),
+			"01/01/1900",
+		).AddRow(
+			323934, "TRANS", "SALE", parseTime("02-06-2022"),
+			10363, "PEXC", "POWEREX CORP", "POWEREX", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "011-KW-BI-03761", "TRANS",
+			"WEST", "N", 288, "SD - TRANS", "LHILER", "PPT",
+			"NO", "NA", "LHILER", "LHILER", parseTime("02-06-2022"), parseTime("02-06-2022"),
+			"01/01/1900",
+		))
+
+	dealKeysArray := []int{323931, 323934}
+
+	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeysArray, []interface{}{}, "pv.td_trans_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucTransmissionDealTermListQ := getNucTransmissionDealTermListQuery(dealKeysQuery)
+
+	var namedParams []driver.Value
+	for _, value := range params {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParams = append(namedParams, valueNamed)
+	}
+
+	columns = []string{"TD_TRANS_KEY", "VOLUME_SEQ", "DY_BEG_DAY", "DY_END_DAY", "VOLUME",
+		"PPEP_PEP_PRODUCT", "PPEP_PP_FM_POOL", "CTP_FM_POINT_CODE", "PPEP_PP_TO_POOL",
+		"CTP_TO_POINT_CODE", "SCH_SCHEDULE"}
+
+	mock.ExpectQuery(getNucTransmissionDealTermListQ).WithArgs(namedParams...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			323931, 0, parseTime("01-06-2022"), parseTime("01-06-2022"), 500,
+			"HOURLY", "MID-C", "MID-C", "MID-C",
+			"MID-C", "NERC",
+		).AddRow(
+			323931, 1, parseTime("01-06-2022"), parseTime("01-06-2022"), 414,
+			"HOURLY", "MID-C", "MID-C", "MID-C",
+			"MID-C", "NERC",
+		).AddRow(
+			323934, 0, parseTime("01-06-2022"), parseTime("01-06-2022"), 151,
+			"HOURLY", "MID-C", "MID-C", "MID-C",
+			"MID-C", "NERC",
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx          context.Context
+		lstTranskeys []float64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:          context.TODO(),
+				lstTranskeys: lstTranskeys,
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             323931,
+					DealType:            "TRANS",
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("02-06-2022"),
+					CyCompanyKey:        10133,
+					Company:             "SMUD",
+					CompanyLongName:     "SACRAMENTO MUNICIPAL UTILITY DIST",
+					CompanyCode:         "SMUD",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "011-KW-BI-03761",
+					ConfirmFormat:       "TRANS",
+					Region:              "WEST",
+					HsHedgeKey:          "N",
+					PrtPortfolio:        288,
+					Portfolio:           "SD - TRANS",
+					UrTrader:            "LHILER",
+					TzTimeZone:          "PPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					CreatedBy:           "LHILER",
+					ModifiedBy:          "LHILER",
+					CreatedAt:           parseTime("02-06-2022"),
+					ModifiedAt:          parseTime("02-06-2022"),
+					ExoticFlag:          "NA",
+					InteraffiliateFlag:  "N",
+					ExecutionTime:       parseTime("01-01-1900"),
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:          0,
+							BegDate:         parseTime("01-06-2022"),
+							EndDate:         parseTime("01-06-2022"),
+							Volume:          500,
+							Product1:        "HOURLY",
+							Pool1:           "MID-C",
+							PointCode1:      "MID-C",
+							Pool2:           "MID-C",
+							PointCode2:      "MID-C",
+							HolidaySchedule: "NERC",
+							PriceType:       "F",
+						},
+						{
+							VolSeq:          1,
+							BegDate:         parseTime("01-06-2022"),
+							EndDate:         parseTime("01-06-2022"),
+							Volume:          414,
+							Product1:        "HOURLY",
+							Pool1:           "MID-C",
+							PointCode1:      "MID-C",
+							Pool2:           "MID-C",
+							PointCode2:      "MID-C",
+							HolidaySchedule: "NERC",
+							PriceType:       "F",
+						},
+					},
+				},
+				{
+					DealKey:             323934,
+					DealType:            "TRANS",
+					DnDirection:         "SALE",
+					TransactionDate:     parseTime("02-06-2022"),
+					CyCompanyKey:        10363,
+					Company:             "PEXC",
+					CompanyLongName:     "POWEREX CORP",
+					CompanyCode:         "POWEREX",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "011-KW-BI-03761",
+					ConfirmFormat:       "TRANS",
+					Region:              "WEST",
+					HsHedgeKey:          "N",
+					PrtPortfolio:        288,
+					Portfolio:           "SD - TRANS",
+					UrTrader:            "LHILER",
+					TzTimeZone:          "PPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					CreatedBy:           "LHILER",
+					ModifiedBy:          "LHILER",
+					CreatedAt:           parseTime("02-06-2022"),
+					ModifiedAt:          parseTime("02-06-2022"),
+					ExoticFlag:          "NA",
+					InteraffiliateFlag:  "N",
+					ExecutionTime:       parseTime("01-01-1900"),
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:          0,
+							BegDate:         parseTime("01-06-2022"),
+							EndDate:         parseTime("01-06-2022"),
+							Volume:          151,
+							Product1:        "HOURLY",
+							Pool1:           "MID-C",
+							PointCode1:      "MID-C",
+							Pool2:           "MID-C",
+							PointCode2:      "MID-C",
+							HolidaySchedule: "NERC",
+							PriceType:       "F",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucTransmissionDealByKeys(tt.args.ctx, tt.args.lstTranskeys)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucTransmissionDealByKeys() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucTransmissionDealByKeys() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucTransmissionDealByKeys() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucTransmissionDealByKeys() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucTransmissionDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucTransmissionDealByKeys() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucTransmissionDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucTransmissionDealByKeys() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucTransmissionDealByKeys() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucTransmissionDealByKeys() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucTransmissionDealByKeys() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucTransmissionDealByKeys() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucPTPDealByKeys(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	lstPTPkeys := []float64{273387, 273385, 273377}
+
+	dealKeysQuery, paramsDeal, err := oracle.CreateInQueryFloat64(lstPTPkeys, []interface{}{}, "pd.ptp_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucPTPDealByKeysQ := getNucPTPDealByKeysQuery(dealKeysQuery)
+
+	var namedParamsQuery []driver.Value
+	for _, value := range paramsDeal {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParamsQuery = append(namedParamsQuery, valueNamed)
+	}
+
+	columns := []string{"PTP_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
+		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY",
+		"LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT",
+		"REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE",
+		"HAS_BROKER", "BROKER", "DY_FLOW_DAY", "PPEP_PP_POOL", "PPEP_PEP_PRODUCT",
+		"PUBLICATION1", "PUB_INDEX1", "PUBLICATION2", "PUB_INDEX2", "CREATEDBY",
+		"MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE"}
+	mock.ExpectQuery(getNucPTPDealByKeysQ).WithArgs(namedParamsQuery...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			273387, "PTP", "PURCHASE", parseTime("05-05-2022"),
+			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "", "",
+			"SOUTH", "", 99643, "ERCOT CASH NODAL", "JPROMU", "CPT",
+			"NO", "NA", parseTime("06-05-2022"), "ERCRR", "HOURLY",
+			"ER DA LMP", "NORTH_HUB", "ER RT LMP", "SOUTH_HUB", "PCI_GSMS",
+			"PCI_GSMS", parseTime("06-05-2022"), parseTime("06-05-2022"),
+		).AddRow(
+			273385, "PTP", "PURCHASE", parseTime("05-05-2022"),
+			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "", "",
+			"SOUTH", "", 99643, "ERCOT CASH NODAL", "JPROMU", "CPT",
+			"NO", "NA", parseTime("06-05-2022"), "ERCRR", "HOURLY",
+			"ER DA LMP", "NORTH_HUB", "ER RT LMP", "NORTH_ZONE", "PCI_GSMS",
+			"PCI_GSMS", parseTime("06-05-2022"), parseTime("06-05-2022"),
+		).AddRow(
+			273377, "PTP", "PURCHASE", parseTime("05-05-2022"),
+			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "", "",
+			"SOUTH", "", 99643, "ERCOT CASH NODAL", "JPROMU", "CPT",
+			"NO", "NA", parseTime("06-05-2022"), "ERCRR", "HOURLY",
+			"ER DA LMP", "HOUSTON_HUB", "ER RT LMP", "HOUSTON_ZONE", "PCI_GSMS",
+			"PCI_GSMS", parseTime("06-05-2022"), parseTime("06-05-2022"),
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx        context.Context
+		lstPTPkeys []float64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:        context.TODO(),
+				lstPTPkeys: lstPTPkeys,
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             273387,
+					DealType:            "PTP",
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("05-05-2022"),
+					CyCompanyKey:        10171,
+					Company:             "ERCOT",
+					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
+					CompanyCode:         "ERCOT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "",
+					ConfirmFormat:       "",
+					Region:              "SOUTH",
+					HsHedgeKey:          "",
+					PrtPortfolio:        99643,
+					Portfolio:           "ERCOT CASH NODAL",
+					UrTrader:            "JPROMU",
+					TzTimeZone:          "CPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:   0,
+							BegDate:  parseTime("06-05-2022"),
+							EndDate:  parseTime("06-05-2022"),
+							Pool1:    "ERCRR",
+							Product1: "HOURLY",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER DA LMP",
+									PubIndex:    "NORTH_HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER RT LMP",
+									PubIndex:    "SOUTH_HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+						},
+					},
+					CreatedBy:          "PCI_GSMS",
+					ModifiedBy:         "PCI_GSMS",
+					CreatedAt:          parseTime("06-05-2022"),
+					ModifiedAt:         parseTime("06-05-2022"),
+					InteraffiliateFlag: "N",
+				},
+				{
+					DealKey:             273385,
+					DealType:            "PTP",
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("05-05-2022"),
+					CyCompanyKey:        10171,
+					Company:             "ERCOT",
+					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
+					CompanyCode:         "ERCOT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "",
+					ConfirmFormat:       "",
+					Region:              "SOUTH",
+					HsHedgeKey:          "",
+					PrtPortfolio:        99643,
+					Portfolio:           "ERCOT CASH NODAL",
+					UrTrader:            "JPROMU",
+					TzTimeZone:          "CPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:   0,
+							BegDate:  parseTime("06-05-2022"),
+							EndDate:  parseTime("06-05-2022"),
+							Pool1:    "ERCRR",
+							Product1: "HOURLY",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER DA LMP",
+									PubIndex:    "NORTH_HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER RT LMP",
+									PubIndex:    "NORTH_ZONE",
+									Frequency:   "HOURLY",
+								},
+							},
+						},
+					},
+					CreatedBy:          "PCI_GSMS",
+					ModifiedBy:         "PCI_GSMS",
+					CreatedAt:          parseTime("06-05-2022"),
+					ModifiedAt:         parseTime("06-05-2022"),
+					InteraffiliateFlag: "N",
+				},
+				{
+					DealKey:             273377,
+					DealType:            "PTP",
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("05-05-2022"),
+					CyCompanyKey:        10171,
+					Company:             "ERCOT",
+					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
+					CompanyCode:         "ERCOT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "",
+					ConfirmFormat:       "",
+					Region:              "SOUTH",
+					HsHedgeKey:          "",
+					PrtPortfolio:        99643,
+					Portfolio:           "ERCOT CASH NODAL",
+					UrTrader:            "JPROMU",
+					TzTimeZone:          "CPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:   0,
+							BegDate:  parseTime("06-05-2022"),
+							EndDate:  parseTime("06-05-2022"),
+							Pool1:    "ERCRR",
+							Product1: "HOURLY",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER DA LMP",
+									PubIndex:    "HOUSTON_HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER RT LMP",
+									PubIndex:    "HOUSTON_ZONE",
+									Frequency:   "HOURLY",
+								},
+							},
+						},
+					},
+					CreatedBy:          "PCI_GSMS",
+					ModifiedBy:         "PCI_GSMS",
+					CreatedAt:          parseTime("06-05-2022"),
+					ModifiedAt:         parseTime("06-05-2022"),
+					InteraffiliateFlag: "N",
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucPTPDealByKeys(tt.args.ctx, tt.args.lstPTPkeys)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucPTPDealByKeys() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucPTPDealByKeys() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucPTPDealByKeys() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucPTPDealByKeys() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucPTPDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucPTPDealByKeys() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucPTPDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucPTPDealByKeys() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucPTPDealByKeys() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucPTPDealByKeys() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucPTPDealByKeys() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucPTPDealByKeys() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucMiscChargeDealByKeys(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	lstMiscChargekeys := []float64{2854193, 2854183}
+
+	dealKeysQuery, paramsDeal, err := oracle.CreateInQueryFloat64(lstMiscChargekeys, []interface{}{}, "pd.misc_charge_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucMiscChargeDealByKeysQ := getNucMiscChargeDealByKeysQuery(dealKeysQuery)
+
+	var namedParamsQuery []driver.Value
+	for _, value := range paramsDeal {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParamsQuery = append(namedParamsQuery, valueNamed)
+	}
+
+	columns := []string{"MISC_CHARGE_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
+		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY", "LEGALENTITYLONGNAME",
+		"CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY",
+		"PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE", "HAS_BROKER", "BROKER",
+		"CREATEDBY", "MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE"}
+
+	mock.ExpectQuery(getNucMiscChargeDealByKeysQ).WithArgs(namedParamsQuery...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			2854193, "MISC", "Payable", parseTime("02-06-2022"),
+			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA", "Shell Energy North America (US), L.P.",
+			10430, "013-KW-SA-20161", "", "", "",
+			255, "CEM ODD LOT", "PCI_ALLO", "", "", "",
+			"PCI_GSMS", "PCI_GSMS", parseTime("02-06-2022"), parseTime("02-06-2022"),
+		).AddRow(
+			2854183, "MISC", "Receivable", parseTime("02-06-2022"),
+			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA", "Shell Energy North America (US), L.P.",
+			10430, "013-KW-SA-20161", "", "", "",
+			255, "CEM ODD LOT", "PCI_ALLO", "", "", "",
+			"PCI_GSMS", "PCI_GSMS", parseTime("02-06-2022"), parseTime("02-06-2022"),
+		))
+
+	dealKeysArray := []int{2854193, 2854183}
+
+	dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeysArray, []interface{}{}, "pv.mc_misc_charge_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucMiscChargeDealTermListQ := getNucMiscChargeDealTermListQuery(dealKeysQuery)
+
+	var namedParams []driver.Value
+	for _, value := range params {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParams = append(namedParams, valueNamed)
+	}
+
+	columns = []string{"MC_MISC_CHARGE_KEY", "MISC_VOL_SEQ", "DY_BEG_DAY", "DY_END_DAY", "INT_VOLUME"}
+
+	mock.ExpectQuery(getNucMiscChargeDealTermListQ).WithArgs(namedParams...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			2854183, 0, parseTime("04-04-2022"), parseTime("04-04-2022"), 0,
+		).AddRow(
+			2854193, 0, parseTime("25-05-2022"), parseTime("25-05-2022"), 0,
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx               context.Context
+		lstMiscChargekeys []float64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:               context.TODO(),
+				lstMiscChargekeys: lstMiscChargekeys,
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             2854193,
+					DealType:            "MISC",
+					DnDirection:         "Payable",
+					TransactionDate:     parseTime("02-06-2022"),
+					CyCompanyKey:        10171,
+					Company:             "ERCOT",
+					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
+					CompanyCode:         "ERCOT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "013-KW-SA-20161",
+					ConfirmFormat:       "",
+					Region:              "",
+					HsHedgeKey:          "",
+					PrtPortfolio:        255,
+					Portfolio:           "CEM ODD LOT",
+					UrTrader:            "PCI_ALLO",
+					TzTimeZone:          "",
+					HasBroker:           "",
+					Broker:              "",
+					CreatedBy:           "PCI_GSMS",
+					ModifiedBy:          "PCI_GSMS",
+					CreatedAt:           parseTime("02-06-2022"),
+					ModifiedAt:          parseTime("02-06-2022"),
+					InteraffiliateFlag:  "N",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:  0,
+							BegDate: parseTime("25-05-2022"),
+							EndDate: parseTime("25-05-2022"),
+							Volume:  0,
+						},
+					},
+				},
+				{
+					DealKey:             2854183,
+					DealType:            "MISC",
+					DnDirection:         "Receivable",
+					TransactionDate:     parseTime("02-06-2022"),
+					CyCompanyKey:        10171,
+					Company:             "ERCOT",
+					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
+					CompanyCode:         "ERCOT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "013-KW-SA-20161",
+					ConfirmFormat:       "",
+					Region:              "",
+					HsHedgeKey:          "",
+					PrtPortfolio:        255,
+					Portfolio:           "CEM ODD LOT",
+					UrTrader:            "PCI_ALLO",
+					TzTimeZone:          "",
+					HasBroker:           "",
+					Broker:              "",
+					CreatedBy:           "PCI_GSMS",
+					ModifiedBy:          "PCI_GSMS",
+					CreatedAt:           parseTime("02-06-2022"),
+					ModifiedAt:          parseTime("02-06-2022"),
+					InteraffiliateFlag:  "N",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:  0,
+							BegDate: parseTime("04-04-2022"),
+							EndDate: parseTime("04-04-2022"),
+							Volume:  0,
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucMiscChargeDealByKeys(tt.args.ctx, tt.args.lstMiscChargekeys)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealByKeys() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealByKeys() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealByKeys() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealByKeys() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealByKeys() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealByKeys() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealByKeys() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealByKeys() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealByKeys() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealByKeys() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucMiscChargeDealByKeys() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucDealsByTypeAndKeys(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	dealKeysQuery, paramsDeal, err := oracle.CreateInQueryFloat64([]float64{273387, 273390}, []interface{}{}, "pd.ptp_key")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating the query", err)
+	}
+
+	getNucPTPDealByKeysQ := getNucPTPDealByKeysQuery(dealKeysQuery)
+
+	var namedParamsQuery []driver.Value
+	for _, value := range paramsDeal {
+		valueNamed, _ := value.(sql.NamedArg)
+		namedParamsQuery = append(namedParamsQuery, valueNamed)
+	}
+
+	columns := []string{"PTP_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
+		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY",
+		"LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT",
+		"REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE",
+		"HAS_BROKER", "BROKER", "DY_FLOW_DAY", "PPEP_PP_POOL", "PPEP_PEP_PRODUCT",
+		"PUBLICATION1", "PUB_INDEX1", "PUBLICATION2", "PUB_INDEX2", "CREATEDBY",
+		"MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE"}
+	mock.ExpectQuery(getNucPTPDealByKeysQ).WithArgs(namedParamsQuery...).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			273387, "PTP", "PURCHASE", parseTime("05-05-2022"),
+			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "", "",
+			"SOUTH", "", 99643, "ERCOT CASH NODAL", "JPROMU", "CPT",
+			"NO", "NA", parseTime("06-05-2022"), "ERCRR", "HOURLY",
+			"ER DA LMP", "NORTH_HUB", "ER RT LMP", "SOUTH_HUB", "PCI_GSMS",
+			"PCI_GSMS", parseTime("06-05-2022"), parseTime("06-05-2022"),
+		).AddRow(
+			// a deal of another deal type than requested is a miss
+			273390, "MISC", "PURCHASE", parseTime("05-05-2022"),
+			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "", "",
+			"SOUTH", "", 99643, "ERCOT CASH NODAL", "JPROMU", "CPT",
+			"NO", "NA", parseTime("06-05-2022"), "ERCRR", "HOURLY",
+			"ER DA LMP", "NORTH_HUB", "ER RT LMP", "SOUTH_HUB", "PCI_GSMS",
+			"PCI_GSMS", parseTime("06-05-2022"), parseTime("06-05-2022"),
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx        context.Context
+		lookupKeys []*NucleusDealLookupKey
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		want       []*nucleus.NucleusTradeHeaderModel
+		wantMisses []*NucleusDealLookupMiss
+		wantErr    bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx: context.TODO(),
+				lookupKeys: []*NucleusDealLookupKey{
+					{DealType: "PTP", DealKey: 273387},
+					{DealType: "UNKNOWN", DealKey: 1},
+					nil,
+					{DealType: "ptp", DealKey: 273390},
+					{DealType: "PTP", DealKey: 273387},
+				},
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             273387,
+					DealType:            "PTP",
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("05-05-2022"),
+					CyCompanyKey:        10171,
+					Company:             "ERCOT",
+					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
+					CompanyCode:         "ERCOT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Region:              "SOUTH",
+					PrtPortfolio:        99643,
+					Portfolio:           "ERCOT CASH NODAL",
+					UrTrader:            "JPROMU",
+					TzTimeZone:          "CPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:   0,
+							BegDate:  parseTime("06-05-2022"),
+							EndDate:  parseTime("06-05-2022"),
+							Pool1:    "ERCRR",
+							Product1: "HOURLY",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER DA LMP",
+									PubIndex:    "NORTH_HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER RT LMP",
+									PubIndex:    "SOUTH_HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+						},
+					},
+					CreatedBy:          "PCI_GSMS",
+					ModifiedBy:         "PCI_GSMS",
+					CreatedAt:          parseTime("06-05-2022"),
+					ModifiedAt:         parseTime("06-05-2022"),
+					InteraffiliateFlag: "N",
+				},
+			},
+			wantMisses: []*NucleusDealLookupMiss{
+				{DealType: "UNKNOWN", DealKey: 1, Reason: dealLookupUnsupportedDealTypeReason},
+				{DealType: "PTP", DealKey: 273390, Reason: dealLookupNotFoundReason},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, gotMisses, err := repo.GetNucDealsByTypeAndKeys(tt.args.ctx, tt.args.lookupKeys)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucDealsByTypeAndKeys() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucDealsByTypeAndKeys() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+
+			if !reflect.DeepEqual(gotMisses, tt.wantMisses) {
+				t.Errorf("NucleusTradeRepository.GetNucDealsByTypeAndKeys() gotMisses = %+v, wantMisses = %+v", gotMisses, tt.wantMisses)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucPTPDealListByTradeDateRange(t *testing.T) {
+	now := time.Now()
+	fromTradeDate := now.AddDate(0, 0, -30)
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	columns := []string{"PTP_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
+		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY",
+		"LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT",
+		"REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE",
+		"HAS_BROKER", "BROKER", "DY_FLOW_DAY", "PPEP_PP_POOL", "PPEP_PEP_PRODUCT",
+		"PUBLICATION1", "PUB_INDEX1", "PUBLICATION2", "PUB_INDEX2", "CREATEDBY",
+		"MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE"}
+	mock.ExpectQuery(getNucDealListByTradeDateRangeQuery(getNucPTPDealListQuery)).WithArgs(sql.Named("fromTradeDate", fromTradeDate), sql.Named("toTradeDate", now), sql.Named("lastRunTime", now)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			273387, "PTP", "PURCHASE", parseTime("05-05-2022"),
+			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "", "",
+			"SOUTH", "", 99643, "ERCOT CASH NODAL", "JPROMU", "CPT",
+			"NO", "NA", parseTime("06-05-2022"), "ERCRR", "HOURLY",
+			"ER DA LMP", "NORTH_HUB", "ER RT LMP", "SOUTH_HUB", "PCI_GSMS",
+			"PCI_GSMS", parseTime("06-05-2022"), parseTime("06-05-2022"),
+		).AddRow(
+			273385, "PTP", "PURCHASE", parseTime("05-05-2022"),
+			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "", "",
+			"SOUTH", "", 99643, "ERCOT CASH NODAL", "JPROMU", "CPT",
+			"NO", "NA", parseTime("06-05-2022"), "ERCRR", "HOURLY",
+			"ER DA LMP", "NORTH_HUB", "ER RT LMP", "NORTH_ZONE", "PCI_GSMS",
+			"PCI_GSMS", parseTime("06-05-2022"), parseTime("06-05-2022"),
+		).AddRow(
+			273377, "PTP", "PURCHASE", parseTime("05-05-2022"),
+			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "", "",
+			"SOUTH", "", 99643, "ERCOT CASH NODAL", "JPROMU", "CPT",
+			"NO", "NA", parseTime("06-05-2022"), "ERCRR", "HOURLY",
+			"ER DA LMP", "HOUSTON_HUB", "ER RT LMP", "HOUSTON_ZONE", "PCI_GSMS",
+			"PCI_GSMS", parseTime("06-05-2022"), parseTime("06-05-2022"),
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx           context.Context
+		lastRunTime   time.Time
+		fromTradeDate time.Time
+		toTradeDate   time.Time
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:           context.TODO(),
+				lastRunTime:   now,
+				fromTradeDate: fromTradeDate,
+				toTradeDate:   now,
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             273387,
+					DealType:            "PTP",
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("05-05-2022"),
+					CyCompanyKey:        10171,
+					Company:             "ERCOT",
+					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
+					CompanyCode:         "ERCOT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "",
+					ConfirmFormat:       "",
+					Region:              "SOUTH",
+					HsHedgeKey:          "",
+					PrtPortfolio:        99643,
+					Portfolio:           "ERCOT CASH NODAL",
+					UrTrader:            "JPROMU",
+					TzTimeZone:          "CPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:   0,
+							BegDate:  parseTime("06-05-2022"),
+							EndDate:  parseTime("06-05-2022"),
+							Pool1:    "ERCRR",
+							Product1: "HOURLY",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER DA LMP",
+									PubIndex:    "NORTH_HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER RT LMP",
+									PubIndex:    "SOUTH_HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+						},
+					},
+					CreatedBy:          "PCI_GSMS",
+					ModifiedBy:         "PCI_GSMS",
+					CreatedAt:          parseTime("06-05-2022"),
+					ModifiedAt:         parseTime("06-05-2022"),
+					InteraffiliateFlag: "N",
+				},
+				{
+					DealKey:             273385,
+					DealType:            "PTP",
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("05-05-2022"),
+					CyCompanyKey:        10171,
+					Company:             "ERCOT",
+					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
+					CompanyCode:         "ERCOT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "",
+					ConfirmFormat:       "",
+					Region:              "SOUTH",
+					HsHedgeKey:          "",
+					PrtPortfolio:        99643,
+					Portfolio:           "ERCOT CASH NODAL",
+					UrTrader:            "JPROMU",
+					TzTimeZone:          "CPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:   0,
+							BegDate:  parseTime("06-05-2022"),
+							EndDate:  parseTime("06-05-2022"),
+							Pool1:    "ERCRR",
+							Product1: "HOURLY",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER DA LMP",
+									PubIndex:    "NORTH_HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER RT LMP",
+									PubIndex:    "NORTH_ZONE",
+									Frequency:   "HOURLY",
+								},
+							},
+						},
+					},
+					CreatedBy:          "PCI_GSMS",
+					ModifiedBy:         "PCI_GSMS",
+					CreatedAt:          parseTime("06-05-2022"),
+					ModifiedAt:         parseTime("06-05-2022"),
+					InteraffiliateFlag: "N",
+				},
+				{
+					DealKey:             273377,
+					DealType:            "PTP",
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("05-05-2022"),
+					CyCompanyKey:        10171,
+					Company:             "ERCOT",
+					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
+					CompanyCode:         "ERCOT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "",
+					ConfirmFormat:       "",
+					Region:              "SOUTH",
+					HsHedgeKey:          "",
+					PrtPortfolio:        99643,
+					Portfolio:           "ERCOT CASH NODAL",
+					UrTrader:            "JPROMU",
+					TzTimeZone:          "CPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:   0,
+							BegDate:  parseTime("06-05-2022"),
+							EndDate:  parseTime("06-05-2022"),
+							Pool1:    "ERCRR",
+							Product1: "HOURLY",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER DA LMP",
+									PubIndex:    "HOUSTON_HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER RT LMP",
+									PubIndex:    "HOUSTON_ZONE",
+									Frequency:   "HOURLY",
+								},
+							},
+						},
+					},
+					CreatedBy:          "PCI_GSMS",
+					ModifiedBy:         "PCI_GSMS",
+					CreatedAt:          parseTime("06-05-2022"),
+					ModifiedAt:         parseTime("06-05-2022"),
+					InteraffiliateFlag: "N",
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucPTPDealListByTradeDateRange(tt.args.ctx, tt.args.lastRunTime, tt.args.fromTradeDate, tt.args.toTradeDate)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucPTPDealListByTradeDateRange() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucPTPDealListByTradeDateRange() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucPTPDealListByTradeDateRange() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucPTPDealListByTradeDateRange() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucPTPDealListByTradeDateRange() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucPTPDealListByTradeDateRange() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucPTPDealListByTradeDateRange() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucPTPDealListByTradeDateRange() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucPTPDealListByTradeDateRange() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucPTPDealListByTradeDateRange() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucPTPDealListByTradeDateRange() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucPTPDealListByTradeDateRange() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucTCCFTRSDealListByTradeDateRange(t *testing.T) {
+	now := time.Now()
+	fromTradeDate := now.AddDate(0, 0, -30)
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	columns := []string{"DEAL_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
+		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY",
+		"LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT",
+		"REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE",
+		"HAS_BROKER", "BROKER", "DY_BEG_DAY", "DY_END_DAY", "SCH_SCHEDULE", "VOLUME",
+		"FIXED_PRICE", "PPEP_PP_POOL", "PPEP_PEP_PRODUCT", "PI_PB_PUBLICATION", "FRQ_FREQUENCY",
+		"POI_PI_PUB_INDEX", "POW_PI_PUB_INDEX", "CREATEDBY", "MODIFIEDBY", "CREATE_DATE",
+		"MODIFY_DATE"}
+	mock.ExpectQuery(getNucDealListByTradeDateRangeQuery(getNucTCCFTRSDealListQuery)).WithArgs(sql.Named("fromTradeDate", fromTradeDate), sql.Named("toTradeDate", now), sql.Named("strDealType", "FTRSWP"), sql.Named("lastRunTime", now)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			262325, "FTRSWP", "PURCHASE", parseTime("02-06-2022"),
+			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "", "FTRSWAP",
+			"SOUTH", "", 99860, "SP ERCOT CRR", "SPARK", "CPT",
+			"NO", "NA", parseTime("01-03-2023"), parseTime("31-03-2023"), "NERC", 100,
+			3.0205, "ERCRR", "STD ON", "ER DA LMP", "HOURLY",
+			"SOUTH_HUB", "LZ_LCRA", "PCI_GSMS", "PCI_GSMS", parseTime("02-06-2022"),
+			parseTime("02-06-2022"),
+		).AddRow(
+			262147, "FTRSWP", "PURCHASE", parseTime("02-06-2022"),
+			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "", "FTRSWAP",
+			"SOUTH", "", 99860, "SP ERCOT CRR", "SPARK", "CPT",
+			"NO", "NA", parseTime("01-01-2023"), parseTime("31-01-2023"), "NERC", 25,
+			4.10714, "ERCRR", "STD ON", "ER DA LMP", "HOURLY",
+			"SOUTH_HUB", "NORTH_HUB", "PCI_GSMS", "PCI_GSMS", parseTime("02-06-2022"),
+			parseTime("02-06-2022"),
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx           context.Context
+		lastRunTime   time.Time
+		fromTradeDate time.Time
+		toTradeDate   time.Time
+		strDealType   string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []*nucleus.NucleusTradeHeaderModel
+		wantErr bool
+	}{
+		{
+			name: "regular run",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:           context.TODO(),
+				lastRunTime:   now,
+				fromTradeDate: fromTradeDate,
+				toTradeDate:   now,
+				strDealType:   "FTRSWP",
+			},
+			want: []*nucleus.NucleusTradeHeaderModel{
+				{
+					DealKey:             262325,
+					DealType:            "FTRSWP",
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("02-06-2022"),
+					CyCompanyKey:        10171,
+					Company:             "ERCOT",
+					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
+					CompanyCode:         "ERCOT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "",
+					ConfirmFormat:       "FTRSWAP",
+					Region:              "SOUTH",
+					HsHedgeKey:          "",
+					PrtPortfolio:        99860,
+					Portfolio:           "SP ERCOT CRR",
+					UrTrader:            "SPARK",
+					TzTimeZone:          "CPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					CreatedBy:           "PCI_GSMS",
+					ModifiedBy:          "PCI_GSMS",
+					CreatedAt:           parseTime("02-06-2022"),
+					ModifiedAt:          parseTime("02-06-2022"),
+					ExoticFlag:          "NA",
+					InteraffiliateFlag:  "N",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:          0,
+							BegDate:         parseTime("01-03-2023"),
+							EndDate:         parseTime("31-03-2023"),
+							HolidaySchedule: "NERC",
+							Volume:          100,
+							FixedPrice:      3.0205,
+							Pool1:           "ERCRR",
+							Product1:        "STD ON",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER DA LMP",
+									PubIndex:    "SOUTH_HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER DA LMP",
+									PubIndex:    "LZ_LCRA",
+									Frequency:   "HOURLY",
+								},
+							},
+						},
+					},
+				},
+				{
+					DealKey:             262147,
+					DealType:            "FTRSWP",
+					DnDirection:         "PURCHASE",
+					TransactionDate:     parseTime("02-06-2022"),
+					CyCompanyKey:        10171,
+					Company:             "ERCOT",
+					CompanyLongName:     "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC",
+					CompanyCode:         "ERCOT",
+					LegalEntity:         "SENA",
+					LegalEntityLongName: "Shell Energy North America (US), L.P.",
+					CyLegalEntityKey:    10430,
+					Contract:            "",
+					ConfirmFormat:       "FTRSWAP",
+					Region:              "SOUTH",
+					HsHedgeKey:          "",
+					PrtPortfolio:        99860,
+					Portfolio:           "SP ERCOT CRR",
+					UrTrader:            "SPARK",
+					TzTimeZone:          "CPT",
+					HasBroker:           "NO",
+					Broker:              "NA",
+					CreatedBy:           "PCI_GSMS",
+					ModifiedBy:          "PCI_GSMS",
+					CreatedAt:           parseTime("02-06-2022"),
+					ModifiedAt:          parseTime("02-06-2022"),
+					ExoticFlag:          "NA",
+					InteraffiliateFlag:  "N",
+					Terms: []*nucleus.NucleusTradeTermModel{
+						{
+							VolSeq:          0,
+							BegDate:         parseTime("01-01-2023"),
+							EndDate:         parseTime("31-01-2023"),
+							HolidaySchedule: "NERC",
+							Volume:          25,
+							FixedPrice:      4.10714,
+							Pool1:           "ERCRR",
+							Product1:        "STD ON",
+							Indexes1: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER DA LMP",
+									PubIndex:    "SOUTH_HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+							Indexes2: []*nucleus.NucleusTradeIndexModel{
+								{
+									Publication: "ER DA LMP",
+									PubIndex:    "NORTH_HUB",
+									Frequency:   "HOURLY",
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got, err := repo.GetNucTCCFTRSDealListByTradeDateRange(tt.args.ctx, tt.args.lastRunTime, tt.args.fromTradeDate, tt.args.toTradeDate, tt.args.strDealType)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealListByTradeDateRange() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealListByTradeDateRange() len(got) %d != len(tt.want) %d", len(got), len(tt.want))
+				return
+			}
+
+			for _, wantedValue := range tt.want {
+				exists := false
+				for _, value := range got {
+					if wantedValue.DealKey == value.DealKey {
+						exists = true
+						if len(value.Terms) != len(wantedValue.Terms) {
+							t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealListByTradeDateRange() len(value.Terms) %d != len(wantedValue.Terms) %d", len(value.Terms), len(wantedValue.Terms))
+							return
+						}
+
+						for _, wantedTerm := range wantedValue.Terms {
+							termExists := false
+							for _, valueTerm := range value.Terms {
+								if wantedTerm.VolSeq == valueTerm.VolSeq {
+									termExists = true
+
+									if len(wantedTerm.Indexes1) != len(valueTerm.Indexes1) {
+										t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealListByTradeDateRange() len(wantedTerm.Indexes1) %d != len(valueTerm.Indexes1) %d", len(wantedTerm.Indexes1), len(valueTerm.Indexes1))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes1 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes1 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealListByTradeDateRange() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if len(wantedTerm.Indexes2) != len(valueTerm.Indexes2) {
+										t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealListByTradeDateRange() len(wantedTerm.Indexes2) %d != len(valueTerm.Indexes2) %d", len(wantedTerm.Indexes2), len(valueTerm.Indexes2))
+										return
+									}
+
+									for _, wantedIndex := range wantedTerm.Indexes2 {
+										indexExists := false
+										for _, valueIndex := range valueTerm.Indexes2 {
+											if reflect.DeepEqual(wantedIndex, valueIndex) {
+												indexExists = true
+											}
+										}
+										if !indexExists {
+											t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealListByTradeDateRange() wanted index in term does not exist %s in wantedValue.DealKey %d, wantedTerm.VolSeq %d", wantedIndex.PubIndex, wantedValue.DealKey, wantedTerm.VolSeq)
+											return
+										}
+									}
+
+									if !reflect.DeepEqual(wantedTerm, valueTerm) {
+										t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealListByTradeDateRange() wantedTerm = %+v, valueTerm = %+v", wantedTerm, valueTerm)
+										return
+									}
+								}
+							}
+							if !termExists {
+								t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealListByTradeDateRange() wanted term with VolSeq %d value does not exist in wantedValue.DealKey %d", wantedTerm.VolSeq, wantedValue.DealKey)
+								return
+							}
+						}
+
+						if !reflect.DeepEqual(wantedValue, value) {
+							t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealListByTradeDateRange() wantedValue = %+v, value = %+v", wantedValue, value)
+							return
+						}
+					}
+				}
+				if !exists {
+					t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealListByTradeDateRange() wanted value does not exist %d", wantedValue.DealKey)
+					return
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NucleusTradeRepository.GetNucTCCFTRSDealListByTradeDateRange() got = %+v, want = %+v", got, tt.want)
+				return
+			}
+		})
+	}
+}
+
+func Test_getNucDealListByTradeDateRangeQuery(t *testing.T) {
+	dealListQueries := map[string]string{
+		"getNucPowerDealListQuery":          getNucPowerDealListQuery,
+		"getNucPowerSwapDealListQuery":      getNucPowerSwapDealListQuery,
+		"getNucPowerOptionsDealListQuery":   getNucPowerOptionsDealListQuery,
+		"getNucCapacityDealListQuery":       getNucCapacityDealListQuery,
+		"getNucPTPDealListQuery":            getNucPTPDealListQuery,
+		"getNucEmissionDealListQuery":       getNucEmissionDealListQuery,
+		"getNucEmissionOptionDealListQuery": getNucEmissionOptionDealListQuery,
+		"getNucSpreadOptionsDealListQuery":  getNucSpreadOptionsDealListQuery,
+		"getNucHeatRateSwapsDealListQuery":  getNucHeatRateSwapsDealListQuery,
+		"getNucTCCFTRSDealListQuery":        getNucTCCFTRSDealListQuery,
+		"getNucTransmissionDealListQuery":   getNucTransmissionDealListQuery,
+		"getNucMiscChargeDealListQuery":     getNucMiscChargeDealListQuery,
+	}
+	for name, dealListQuery := range dealListQueries {
+		t.Run(name, func(t *testing.T) {
+			got := getNucDealListByTradeDateRangeQuery(dealListQuery)
+			if strings.Contains(got, ":tradeDate") {
+				t.Errorf("getNucDealListByTradeDateRangeQuery() still filters on :tradeDate for %s", name)
+			}
+			if !strings.Contains(got, "pd.trade_date BETWEEN :fromTradeDate AND :toTradeDate") {
+				t.Errorf("getNucDealListByTradeDateRangeQuery() doesn't filter on the trade date range for %s", name)
+			}
+			if !strings.Contains(got, ":lastRunTime") {
+				t.Errorf("getNucDealListByTradeDateRangeQuery() lost the lastRunTime filter for %s", name)
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetAllNucDealLists(t *testing.T) {
+	now := time.Now()
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+
+	// the deal lists are extracted concurrently, so the queries arrive in any order
+	mock.MatchExpectationsInOrder(false)
+
+	failingQueries := []string{getNucPowerDealListQuery, getNucPowerSwapDealListQuery, getNucPowerOptionsDealListQuery,
+		getNucCapacityDealListQuery, getNucEmissionDealListQuery, getNucEmissionOptionDealListQuery,
+		getNucSpreadOptionsDealListQuery, getNucHeatRateSwapsDealListQuery, getNucTransmissionDealListQuery,
+		getNucMiscChargeDealListQuery}
+	for _, query := range failingQueries {
+		mock.ExpectQuery(query).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
+			WillReturnError(fmt.Errorf("ORA-01013: user requested cancel of current operation"))
+	}
+
+	for _, dealType := range []string{"FTROPT", "FTRSWP", "TCCSWP"} {
+		mock.ExpectQuery(getNucTCCFTRSDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("strDealType", dealType), sql.Named("lastRunTime", now)).
+			WillReturnError(fmt.Errorf("ORA-01013: user requested cancel of current operation"))
+	}
+
+	columns := []string{"PTP_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
+		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY",
+		"LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT",
+		"REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE",
+		"HAS_BROKER", "BROKER", "DY_FLOW_DAY", "PPEP_PP_POOL", "PPEP_PEP_PRODUCT",
+		"PUBLICATION1", "PUB_INDEX1", "PUBLICATION2", "PUB_INDEX2", "CREATEDBY",
+		"MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE"}
+	mock.ExpectQuery(getNucPTPDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			273387, "PTP", "PURCHASE", parseTime("05-05-2022"),
+			10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
+			"Shell Energy North America (US), L.P.", 10430, "", "",
+			"SOUTH", "", 99643, "ERCOT CASH NODAL", "JPROMU", "CPT",
+			"NO", "NA", parseTime("06-05-2022"), "ERCRR", "HOURLY",
+			"ER DA LMP", "NORTH_HUB", "ER RT LMP", "SOUTH_HUB", "PCI_GSMS",
+			"PCI_GSMS", parseTime("06-05-2022"), parseTime("06-05-2022"),
+		))
+
+	type fields struct {
+		nucleusDb         *sql.DB
+		machineLearningDb *sql.DB
+		logger            logger.Logger
+	}
+	type args struct {
+		ctx         context.Context
+		lastRunTime time.Time
+		tradeDate   time.Time
+	}
+	tests := []struct {
+		name          string
+		fields        fields
+		args          args
+		wantDealKeys  map[string][]int
+		wantErrorKeys []string
+	}{
+		{
+			name: "one deal type succeeds while the others fail",
+			fields: fields{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			},
+			args: args{
+				ctx:         context.TODO(),
+				lastRunTime: now,
+				tradeDate:   now,
+			},
+			wantDealKeys: map[string][]int{
+				"PTP": {273387},
+			},
+			wantErrorKeys: []string{"CAPCTY", "EMOPTS", "EMSSN", "FTROPT", "FTRSWP", "HRSWPS", "MISC",
+				"POPTS", "POWER", "PSWPS", "SPDOPT", "TCCSWP", "TRANS"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &NucleusTradeRepository{
+				nucleusDb:         tt.fields.nucleusDb,
+				machineLearningDb: tt.fields.machineLearningDb,
+				logger:            tt.fields.logger,
+			}
+			got := repo.GetAllNucDealLists(tt.args.ctx, tt.args.lastRunTime, tt.args.tradeDate)
+
+			if len(got) != len(tt.wantDealKeys)+len(tt.wantErrorKeys) {
+				t.Errorf("NucleusTradeRepository.GetAllNucDealLists() len(got) %d != %d", len(got), len(tt.wantDealKeys)+len(tt.wantErrorKeys))
+				return
+			}
+
+			for dealType, wantDealKeys := range tt.wantDealKeys {
+				result, ok := got[dealType]
+				if !ok || result.Error != "" {
+					t.Errorf("NucleusTradeRepository.GetAllNucDealLists() result for %s = %+v, want no error", dealType, result)
+					return
+				}
+
+				var gotDealKeys []int
+				for _, deal := range result.Deals {
+					gotDealKeys = append(gotDealKeys, deal.DealKey)
+				}
+				if !reflect.DeepEqual(gotDealKeys, wantDealKeys) {
+					t.Errorf("NucleusTradeRepository.GetAllNucDealLists() deal keys for %s = %v, want %v", dealType, gotDealKeys, wantDealKeys)
+					return
+				}
+			}
+
+			for _, dealType := range tt.wantErrorKeys {
+				result, ok := got[dealType]
+				if !ok || result.Error == "" || result.Deals != nil {
+					t.Errorf("NucleusTradeRepository.GetAllNucDealLists() result for %s = %+v, want an error", dealType, result)
+					return
+				}
+			}
+		})
+	}
+}
+
+func Test_chunkIntKeys(t *testing.T) {
+	makeKeys := func(count int) []int {
+		var keys []int
+		for index := 0; index < count; index++ {
+			keys = append(keys, index+1)
+		}
+		return keys
+	}
+
+	tests := []struct {
+		name           string
+		keys           []int
+		wantChunkSizes []int
+	}{
+		{name: "0 keys", keys: makeKeys(0), wantChunkSizes: nil},
+		{name: "1 key", keys: makeKeys(1), wantChunkSizes: []int{1}},
+		{name: "1000 keys", keys: makeKeys(1000), wantChunkSizes: []int{1000}},
+		{name: "5000 keys", keys: makeKeys(5000), wantChunkSizes: []int{1000, 1000, 1000, 1000, 1000}},
+		{name: "duplicated keys", keys: append(makeKeys(1000), makeKeys(10)...), wantChunkSizes: []int{1000}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := chunkIntKeys(tt.keys)
+
+			var gotChunkSizes []int
+			var gotKeys []int
+			for _, chunk := range got {
+				gotChunkSizes = append(gotChunkSizes, len(chunk))
+				gotKeys = append(gotKeys, chunk...)
+			}
+
+			if !reflect.DeepEqual(gotChunkSizes, tt.wantChunkSizes) {
+				t.Errorf("chunkIntKeys() chunk sizes = %v, want %v", gotChunkSizes, tt.wantChunkSizes)
+				return
+			}
+
+			if len(tt.keys) > 0 && gotKeys[0] != tt.keys[0] {
+				t.Errorf("chunkIntKeys() first key = %d, want %d", gotKeys[0], tt.keys[0])
+				return
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_getNucMiscChargeDealTermListInChunks(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	tests := []struct {
+		name      string
+		keysCount int
+	}{
+		{name: "0 keys", keysCount: 0},
+		{name: "1 key", keysCount: 1},
+		{name: "1000 keys", keysCount: 1000},
+		{name: "5000 keys", keysCount: 5000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer nucleusDb.Close()
+
+			var dealKeys []int
+			for index := 0; index < tt.keysCount; index++ {
+				dealKeys = append(dealKeys, 2854000+index)
+			}
+
+			for _, chunk := range chunkIntKeys(dealKeys) {
+				dealKeysQuery, params, err := oracle.CreateInQueryInt(chunk, []interface{}{}, "pv.mc_misc_charge_key")
+				if err != nil {
+					t.Fatalf("an error '%s' was not expected when creating the query", err)
+				}
+
+				var namedParams []driver.Value
+				for _, value := range params {
+					valueNamed, _ := value.(sql.NamedArg)
+					namedParams = append(namedParams, valueNamed)
+				}
+
+				rows := sqlmock.NewRows([]string{"MC_MISC_CHARGE_KEY", "MISC_VOL_SEQ", "DY_BEG_DAY", "DY_END_DAY", "INT_VOLUME"})
+				for _, dealKey := range chunk {
+					rows.AddRow(dealKey, 0, parseTime("25-05-2022"), parseTime("25-05-2022"), 10)
+				}
+
+				mock.ExpectQuery(getNucMiscChargeDealTermListQuery(dealKeysQuery)).WithArgs(namedParams...).WillReturnRows(rows)
+			}
+
+			repo := &NucleusTradeRepository{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			}
+			got, err := repo.getNucMiscChargeDealTermList(context.TODO(), dealKeys)
+			if err != nil {
+				t.Errorf("NucleusTradeRepository.getNucMiscChargeDealTermList() error = %v", err)
+				return
+			}
+
+			if len(got) != tt.keysCount {
+				t.Errorf("NucleusTradeRepository.getNucMiscChargeDealTermList() len(got) %d != keysCount %d", len(got), tt.keysCount)
+				return
+			}
+
+			for _, dealKey := range dealKeys {
+				if len(got[dealKey]) != 1 {
+					t.Errorf("NucleusTradeRepository.getNucMiscChargeDealTermList() len(got[%d]) %d != 1", dealKey, len(got[dealKey]))
+					return
+				}
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("NucleusTradeRepository.getNucMiscChargeDealTermList() unmet expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetNucPTPDealByKeysInChunks(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	columns := []string{"PTP_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
+		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY",
+		"LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT",
+		"REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE",
+		"HAS_BROKER", "BROKER", "DY_FLOW_DAY", "PPEP_PP_POOL", "PPEP_PEP_PRODUCT",
+		"PUBLICATION1", "PUB_INDEX1", "PUBLICATION2", "PUB_INDEX2", "CREATEDBY",
+		"MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE"}
+
+	tests := []struct {
+		name      string
+		keysCount int
+	}{
+		{name: "0 keys", keysCount: 0},
+		{name: "1 key", keysCount: 1},
+		{name: "1000 keys", keysCount: 1000},
+		{name: "5000 keys", keysCount: 5000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer nucleusDb.Close()
+
+			// the deal chunks of a lookup are queried inListChunksConcurrency at a
+			// time, so they arrive in any order
+			mock.MatchExpectationsInOrder(false)
+
+			var lstPTPkeys []float64
+			for index := 0; index < tt.keysCount; index++ {
+				lstPTPkeys = append(lstPTPkeys, float64(273000+index))
+			}
+
+			for _, chunk := range chunkFloat64Keys(lstPTPkeys) {
+				dealKeysQuery, paramsDeal, err := oracle.CreateInQueryFloat64(chunk, []interface{}{}, "pd.ptp_key")
+				if err != nil {
+					t.Fatalf("an error '%s' was not expected when creating the query", err)
+				}
+
+				var namedParamsQuery []driver.Value
+				for _, value := range paramsDeal {
+					valueNamed, _ := value.(sql.NamedArg)
+					namedParamsQuery = append(namedParamsQuery, valueNamed)
+				}
+
+				rows := sqlmock.NewRows(columns)
+				for _, dealKey := range chunk {
+					rows.AddRow(
+						int(dealKey), "PTP", "PURCHASE", parseTime("05-05-2022"),
+						10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
+						"Shell Energy North America (US), L.P.", 10430, "", "",
+						"SOUTH", "", 99643, "ERCOT CASH NODAL", "JPROMU", "CPT",
+						"NO", "NA", parseTime("06-05-2022"), "ERCRR", "HOURLY",
+						"ER DA LMP", "NORTH_HUB", "ER RT LMP", "SOUTH_HUB", "PCI_GSMS",
+						"PCI_GSMS", parseTime("06-05-2022"), parseTime("06-05-2022"),
+					)
+				}
+
+				mock.ExpectQuery(getNucPTPDealByKeysQuery(dealKeysQuery)).WithArgs(namedParamsQuery...).WillReturnRows(rows)
+			}
+
+			repo := &NucleusTradeRepository{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			}
+			got, err := repo.GetNucPTPDealByKeys(context.TODO(), lstPTPkeys)
+			if err != nil {
+				t.Errorf("NucleusTradeRepository.GetNucPTPDealByKeys() error = %v", err)
+				return
+			}
+
+			if len(got) != tt.keysCount {
+				t.Errorf("NucleusTradeRepository.GetNucPTPDealByKeys() len(got) %d != keysCount %d", len(got), tt.keysCount)
+				return
+			}
+
+			// the chunks are merged back in the order of the requested keys
+			for index, headerModel := range got {
+				if float64(headerModel.DealKey) != lstPTPkeys[index] {
+					t.Errorf("NucleusTradeRepository.GetNucPTPDealByKeys() got[%d].DealKey %d != %v", index, headerModel.DealKey, lstPTPkeys[index])
+					return
+				}
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("NucleusTradeRepository.GetNucPTPDealByKeys() unmet expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_StreamNucMiscChargeDealList(t *testing.T) {
+	now := time.Now()
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	columns := []string{"MISC_CHARGE_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
+		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY", "LEGALENTITYLONGNAME",
+		"CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY",
+		"PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE", "HAS_BROKER", "BROKER",
+		"CREATEDBY", "MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE"}
+
+	errStop := errors.New("client disconnected")
+
+	tests := []struct {
+		name      string
+		dealCount int
+		stopAfter int
+		wantErr   error
+	}{
+		{name: "no deals", dealCount: 0},
+		{name: "single batch", dealCount: dealListStreamBatchSize},
+		{name: "two batches", dealCount: dealListStreamBatchSize + 1},
+		{name: "yield error stops the stream", dealCount: dealListStreamBatchSize + 1, stopAfter: 10, wantErr: errStop},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer nucleusDb.Close()
+
+			var dealKeys []int
+			rows := sqlmock.NewRows(columns)
+			for index := 0; index < tt.dealCount; index++ {
+				dealKeys = append(dealKeys, 2854000+index)
+				rows.AddRow(
+					2854000+index, "MISC", "Payable", parseTime("02-06-2022"),
+					10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA", "Shell Energy North America (US), L.P.",
+					10430, "013-KW-SA-20161", "", "", "",
+					255, "CEM ODD LOT", "PCI_ALLO", "", "", "",
+					"PCI_GSMS", "PCI_GSMS", parseTime("02-06-2022"), parseTime("02-06-2022"),
+				)
+			}
+
+			mock.ExpectQuery(getNucMiscChargeDealListQuery).WithArgs(sql.Named("tradeDate", now), sql.Named("lastRunTime", now)).
+				WillReturnRows(rows)
+
+			// the terms are loaded once per batch of scanned deals
+			for start := 0; start < len(dealKeys); start += dealListStreamBatchSize {
+				end := start + dealListStreamBatchSize
+				if end > len(dealKeys) {
+					end = len(dealKeys)
+				}
+
+				dealKeysQuery, params, err := oracle.CreateInQueryInt(dealKeys[start:end], []interface{}{}, "pv.mc_misc_charge_key")
+				if err != nil {
+					t.Fatalf("an error '%s' was not expected when creating the query", err)
+				}
+
+				var namedParams []driver.Value
+				for _, value := range params {
+					valueNamed, _ := value.(sql.NamedArg)
+					namedParams = append(namedParams, valueNamed)
+				}
+
+				termRows := sqlmock.NewRows([]string{"MC_MISC_CHARGE_KEY", "MISC_VOL_SEQ", "DY_BEG_DAY", "DY_END_DAY", "INT_VOLUME"})
+				for _, dealKey := range dealKeys[start:end] {
+					termRows.AddRow(dealKey, 0, parseTime("25-05-2022"), parseTime("25-05-2022"), 10)
+				}
+
+				mock.ExpectQuery(getNucMiscChargeDealTermListQuery(dealKeysQuery)).WithArgs(namedParams...).WillReturnRows(termRows)
+
+				if tt.stopAfter > 0 {
+					break
+				}
+			}
+
+			repo := &NucleusTradeRepository{
+				nucleusDb:         nucleusDb,
+				machineLearningDb: nil,
+				logger:            serverLogger,
+			}
+
+			var got []*nucleus.NucleusTradeHeaderModel
+			err = repo.StreamNucMiscChargeDealList(context.TODO(), now, now, func(headerModel *nucleus.NucleusTradeHeaderModel) error {
+				if tt.stopAfter > 0 && len(got) == tt.stopAfter {
+					return errStop
+				}
+				got = append(got, headerModel)
+				return nil
+			})
+			if err != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.StreamNucMiscChargeDealList() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			wantCount := tt.dealCount
+			if tt.stopAfter > 0 {
+				wantCount = tt.stopAfter
+			}
+			if len(got) != wantCount {
+				t.Errorf("NucleusTradeRepository.StreamNucMiscChargeDealList() len(got) %d != %d", len(got), wantCount)
+				return
+			}
+
+			for index, headerModel := range got {
+				if headerModel.DealKey != dealKeys[index] {
+					t.Errorf("NucleusTradeRepository.StreamNucMiscChargeDealList() got[%d].DealKey %d != %d", index, headerModel.DealKey, dealKeys[index])
+					return
+				}
+				if len(headerModel.Terms) != 1 {
+					t.Errorf("NucleusTradeRepository.StreamNucMiscChargeDealList() len(got[%d].Terms) %d != 1", index, len(headerModel.Terms))
+					return
+				}
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("NucleusTradeRepository.StreamNucMiscChargeDealList() unmet expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_ExportNucDealListsToParquet(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	columns := []string{"PTP_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
+		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY",
+		"LEGALENTITYLONGNAME", "CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT",
+		"REGION", "HS_HEDGE_KEY", "PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE",
+		"HAS_BROKER", "BROKER", "DY_FLOW_DAY", "PPEP_PP_POOL", "PPEP_PEP_PRODUCT",
+		"PUBLICATION1", "PUB_INDEX1", "PUBLICATION2", "PUB_INDEX2", "CREATEDBY",
+		"MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE"}
+
+	fromTradeDate := parseTime("05-05-2022")
+	toTradeDate := parseTime("06-05-2022")
+
+	t.Run("partitions by deal type and trade date", func(t *testing.T) {
+		nucleusDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer nucleusDb.Close()
+
+		rows := sqlmock.NewRows(columns)
+		for _, dealKey := range []int{273201, 273202} {
+			rows.AddRow(
+				dealKey, "PTP", "PURCHASE", parseTime("05-05-2022"),
+				10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA",
+				"Shell Energy North America (US), L.P.", 10430, "", "",
+				"SOUTH", "", 99643, "ERCOT CASH NODAL", "JPROMU", "CPT",
+				"NO", "NA", parseTime("06-05-2022"), "ERCRR", "HOURLY",
+				"ER DA LMP", "NORTH_HUB", "ER RT LMP", "SOUTH_HUB", "PCI_GSMS",
+				"PCI_GSMS", parseTime("06-05-2022"), parseTime("06-05-2022"),
+			)
+		}
+
+		// the whole deal list is exported, whatever was modified last
+		mock.ExpectQuery(getNucPTPDealListQuery).WithArgs(sql.Named("tradeDate", fromTradeDate), sql.Named("lastRunTime", time.Time{})).
+			WillReturnRows(rows)
+		mock.ExpectQuery(getNucPTPDealListQuery).WithArgs(sql.Named("tradeDate", toTradeDate), sql.Named("lastRunTime", time.Time{})).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		repo := &NucleusTradeRepository{
+			nucleusDb:         nucleusDb,
+			machineLearningDb: nil,
+			logger:            serverLogger,
+		}
+
+		outputDir := t.TempDir()
+
+		// a partition exported before is stale once its deal list is empty
+		stalePartitionDir := filepath.Join(outputDir, "dealType=PTP", "tradeDate=2022-05-06")
+		if err := os.MkdirAll(stalePartitionDir, 0755); err != nil {
+			t.Fatalf("an error '%s' was not expected when creating the stale partition", err)
+		}
+		if err := os.WriteFile(filepath.Join(stalePartitionDir, nucleusParquetFileName), []byte("stale"), 0644); err != nil {
+			t.Fatalf("an error '%s' was not expected when writing the stale partition", err)
+		}
+
+		got, err := repo.ExportNucDealListsToParquet(context.TODO(), fromTradeDate, toTradeDate, []string{"ptp"}, outputDir)
+		if err != nil {
+			t.Errorf("NucleusTradeRepository.ExportNucDealListsToParquet() error = %v", err)
+			return
+		}
+
+		// the trade date without deals doesn't get a partition anymore
+		if _, err := os.Stat(stalePartitionDir); !os.IsNotExist(err) {
+			t.Errorf("NucleusTradeRepository.ExportNucDealListsToParquet() kept the stale partition, stat error = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(outputDir, "dealType=PTP", "tradeDate=2022-05-05", nucleusParquetTmpFileName)); !os.IsNotExist(err) {
+			t.Errorf("NucleusTradeRepository.ExportNucDealListsToParquet() left the temporary file, stat error = %v", err)
+		}
+
+		want := []*NucleusParquetExportFile{
+			{
+				DealType:  "PTP",
+				TradeDate: fromTradeDate,
+				Path:      filepath.Join(outputDir, "dealType=PTP", "tradeDate=2022-05-05", nucleusParquetFileName),
+				Rows:      2,
+			},
+		}
+		if !reflect.DeepEqual(got, want) {
+			gotJson, _ := json.Marshal(got)
+			wantJson, _ := json.Marshal(want)
+			t.Errorf("NucleusTradeRepository.ExportNucDealListsToParquet() got = %s, want %s", gotJson, wantJson)
+			return
+		}
+
+		fileReader, err := local.NewLocalFileReader(want[0].Path)
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening the parquet file", err)
+		}
+		defer fileReader.Close()
+
+		parquetReader, err := reader.NewParquetReader(fileReader, new(nucleusParquetDeal), 1)
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when reading the parquet file", err)
+		}
+		defer parquetReader.ReadStop()
+
+		deals := make([]nucleusParquetDeal, parquetReader.GetNumRows())
+		if err := parquetReader.Read(&deals); err != nil {
+			t.Fatalf("an error '%s' was not expected when reading the parquet rows", err)
+		}
+
+		if len(deals) != 2 || deals[0].DealKey != 273201 || deals[1].DealKey != 273202 {
+			t.Errorf("NucleusTradeRepository.ExportNucDealListsToParquet() unexpected rows %+v", deals)
+			return
+		}
+		if len(deals[0].Terms) != 1 || len(deals[0].Terms[0].Indexes1) != 1 || deals[0].Terms[0].Indexes1[0].PubIndex != "NORTH_HUB" {
+			t.Errorf("NucleusTradeRepository.ExportNucDealListsToParquet() unexpected terms %+v", deals[0].Terms)
+		}
+	})
+
+	t.Run("unsupported deal type", func(t *testing.T) {
+		repo := &NucleusTradeRepository{
+			nucleusDb:         nil,
+			machineLearningDb: nil,
+			logger:            serverLogger,
+		}
+
+		_, err := repo.ExportNucDealListsToParquet(context.TODO(), fromTradeDate, toTradeDate, []string{"GAS"}, t.TempDir())
+		if !errors.Is(err, ErrNucDealTypeNotSupported) {
+			t.Errorf("NucleusTradeRepository.ExportNucDealListsToParquet() error = %v, want %v", err, ErrNucDealTypeNotSupported)
+		}
+	})
+}
+
+func TestNucleusTradeRepository_ProcessTradesInChunks(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	trades := []*nucleus.NucleusTradeHeaderModel{
+		{DealKey: 1104940, DealType: "POWER", PrtPortfolio: 288, TransactionDate: parseTime("05-05-2022")},
+		{DealKey: 1105338, DealType: "POWER", PrtPortfolio: 288, TransactionDate: parseTime("05-05-2022")},
+		{DealKey: 273201, DealType: "PTP", PrtPortfolio: 99643, TransactionDate: parseTime("05-05-2022")},
+	}
+
+	tvpType := mssql.TVP{
+		TypeName: "NucleusProcessedTradeType",
+	}
+
+	columns := []string{"TradeId", "DealType", "Action", "AnomalyDetectedFlag"}
+
+	tests := []struct {
+		name            string
+		trades          []*nucleus.NucleusTradeHeaderModel
+		anomalyMessages map[int][]common.IModelBasePayload
+		expectMock      func(mock sqlmock.Sqlmock)
+		wantSummary     *NucleusProcessTradesSummary
+		wantErr         bool
+	}{
+		{
+			name:       "no trades",
+			trades:     nil,
+			expectMock: func(mock sqlmock.Sqlmock) {},
+			wantSummary: &NucleusProcessTradesSummary{
+				UpsertedByDealType: map[string]int{},
+			},
+		},
+		{
+			name:   "every chunk committed",
+			trades: trades,
+			expectMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				// 1105338 is left out of the output, it didn't change
+				mock.ExpectQuery(execProcessTradesQuery).WithArgs(sql.Named("TVP", tvpType)).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(1104940, "POWER", "UPDATE", false))
+				mock.ExpectExec(deleteProcessedTradeModelResultsQuery).WithArgs(sql.Named("TVP", tvpType)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(insertTradeVersionsQuery)).WithArgs(sql.Named("TVP", tvpType)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(execProcessTradesQuery).WithArgs(sql.Named("TVP", tvpType)).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(273201, "PTP", "INSERT", true))
+				mock.ExpectExec(deleteProcessedTradeModelResultsQuery).WithArgs(sql.Named("TVP", tvpType)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(insertTradeVersionsQuery)).WithArgs(sql.Named("TVP", tvpType)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantSummary: &NucleusProcessTradesSummary{
+				TradesCount:        3,
+				ChunksCount:        2,
+				UpsertedByDealType: map[string]int{"POWER": 1, "PTP": 1},
+				Results: []*NucleusProcessedTradeResult{
+					{DealKey: 1104940, DealType: "POWER", Status: ProcessedTradeUpdated},
+					{DealKey: 1105338, DealType: "POWER", Status: ProcessedTradeUnchanged},
+					{DealKey: 273201, DealType: "PTP", Status: ProcessedTradeInserted, AnomalyDetectedFlag: true},
+				},
+			},
+		},
+		{
+			name: "null, invalid and duplicated trades are reported as failed",
+			trades: []*nucleus.NucleusTradeHeaderModel{
+				trades[0],
+				nil,
+				{DealKey: 0, DealType: "POWER"},
+				trades[0],
+			},
+			expectMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(execProcessTradesQuery).WithArgs(sql.Named("TVP", tvpType)).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(1104940, "POWER", "INSERT", false))
+				mock.ExpectExec(deleteProcessedTradeModelResultsQuery).WithArgs(sql.Named("TVP", tvpType)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(insertTradeVersionsQuery)).WithArgs(sql.Named("TVP", tvpType)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantSummary: &NucleusProcessTradesSummary{
+				TradesCount:        4,
+				ChunksCount:        1,
+				UpsertedByDealType: map[string]int{"POWER": 1},
+				FailedCount:        3,
+				Results: []*NucleusProcessedTradeResult{
+					{DealKey: 1104940, DealType: "POWER", Status: ProcessedTradeInserted},
+					{Status: ProcessedTradeFailed, Error: "trade is null"},
+					{DealKey: 0, DealType: "POWER", Status: ProcessedTradeFailed, Error: "dealKey and dealType are required"},
+					{DealKey: 1104940, DealType: "POWER", Status: ProcessedTradeFailed, Error: "trade is duplicated in the request"},
+				},
+			},
+		},
+		{
+			name:   "model results replace the stored ones",
+			trades: trades[2:],
+			anomalyMessages: map[int][]common.IModelBasePayload{
+				273201: {
+					processedTradeExample{
+						ResultModelBasePayload: &common.ResultModelBasePayload{
+							DealKey:     273201,
+							DealType:    "PTP",
+							ModelName:   "PortfolioLegalEntity",
+							Message:     "legal entity doesn't match the portfolio",
+							ScoredLabel: "NO",
+						},
+					},
+					processedTradeExample{
+						ResultModelBasePayload: &common.ResultModelBasePayload{
+							DealKey:     273201,
+							DealType:    "PTP",
+							ModelName:   "CreditLimit",
+							ScoredLabel: "YES",
+						},
+					},
+				},
+			},
+			expectMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(execProcessTradesQuery).WithArgs(sql.Named("TVP", tvpType)).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(273201, "PTP", "UPDATE", true))
+				mock.ExpectExec(deleteProcessedTradeModelResultsQuery).WithArgs(sql.Named("TVP", tvpType)).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(regexp.QuoteMeta(insertProcessedTradeModelResultsQuery)).WithArgs(sql.Named("TVP", mssql.TVP{TypeName: "NucleusProcessedTradeModelResultType"})).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(regexp.QuoteMeta(insertTradeVersionsQuery)).WithArgs(sql.Named("TVP", tvpType)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantSummary: &NucleusProcessTradesSummary{
+				TradesCount:        1,
+				ChunksCount:        1,
+				UpsertedByDealType: map[string]int{"PTP": 1},
+				Results: []*NucleusProcessedTradeResult{
+					{DealKey: 273201, DealType: "PTP", Status: ProcessedTradeUpdated, AnomalyDetectedFlag: true},
+				},
+			},
+		},
+		{
+			name:   "failing chunk rolls every chunk back",
+			trades: trades,
+			expectMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(execProcessTradesQuery).WithArgs(sql.Named("TVP", tvpType)).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(1104940, "POWER", "INSERT", false))
+				mock.ExpectExec(deleteProcessedTradeModelResultsQuery).WithArgs(sql.Named("TVP", tvpType)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(insertTradeVersionsQuery)).WithArgs(sql.Named("TVP", tvpType)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(execProcessTradesQuery).WithArgs(sql.Named("TVP", tvpType)).WillReturnError(fmt.Errorf("timeout"))
+				mock.ExpectRollback()
+			},
+			wantSummary: nil,
+			wantErr:     true,
+		},
+		{
+			name:   "procedure without merge output",
+			trades: trades[2:],
+			expectMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(execProcessTradesQuery).WithArgs(sql.Named("TVP", tvpType)).
+					WillReturnRows(sqlmock.NewRows(nil))
+				mock.ExpectRollback()
+			},
+			wantSummary: nil,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machineLearningDb, mock, err := sqlmock.New(sqlmock.ValueConverterOption(&mockNucleusProcessedTradeConverter{}))
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer machineLearningDb.Close()
+
+			tt.expectMock(mock)
+
+			repo := NewNucleusTradeRepository(nil, machineLearningDb, serverLogger, WithProcessTradesChunkSize(2))
+			got, err := repo.ProcessTrades(context.TODO(), tt.trades, tt.anomalyMessages)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NucleusTradeRepository.ProcessTrades() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.wantSummary) {
+				gotJson, _ := json.Marshal(got)
+				wantJson, _ := json.Marshal(tt.wantSummary)
+				t.Errorf("NucleusTradeRepository.ProcessTrades() got = %s, want %s", gotJson, wantJson)
+				return
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("NucleusTradeRepository.ProcessTrades() unmet expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetProcessedTradeModelResults(t *testing.T) {
+	now := time.Now()
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	machineLearningDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer machineLearningDb.Close()
+
+	columns := []string{"TradeId", "DealType", "ModelName", "ScoredLabel", "Message", "ModelParameters", "CreatedAt"}
+
+	mock.ExpectQuery(getProcessedTradeModelResultsQuery).WithArgs(sql.Named("tradeId", 273201), sql.Named("dealType", "PTP")).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(
+			273201, "PTP", "PortfolioLegalEntity", "NO", "legal entity; doesn't match", `{"modelName":"PortfolioLegalEntity"}`, now,
+		).AddRow(
+			273201, "PTP", "CreditLimit", "YES", nil, nil, now,
+		))
+
+	want := []*NucleusProcessedTradeModelResult{
+		{
+			DealKey:         273201,
+			DealType:        "PTP",
+			ModelName:       "PortfolioLegalEntity",
+			ScoredLabel:     "NO",
+			Message:         "legal entity; doesn't match",
+			ModelParameters: json.RawMessage(`{"modelName":"PortfolioLegalEntity"}`),
+			CreatedAt:       now,
+		},
+		{
+			DealKey:     273201,
+			DealType:    "PTP",
+			ModelName:   "CreditLimit",
+			ScoredLabel: "YES",
+			CreatedAt:   now,
+		},
+	}
+
+	repo := &NucleusTradeRepository{
+		nucleusDb:         nil,
+		machineLearningDb: machineLearningDb,
+		logger:            serverLogger,
+	}
+	got, err := repo.GetProcessedTradeModelResults(context.TODO(), 273201, "PTP")
+	if err != nil {
+		t.Errorf("NucleusTradeRepository.GetProcessedTradeModelResults() error = %v", err)
+		return
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		gotJson, _ := json.Marshal(got)
+		wantJson, _ := json.Marshal(want)
+		t.Errorf("NucleusTradeRepository.GetProcessedTradeModelResults() got = %s, want %s", gotJson, wantJson)
+	}
+}
+
+func TestNucleusTradeRepository_GetProcessedTrades(t *testing.T) {
+	tradeDate := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	tradeDetail, err := json.Marshal(&nucleus.NucleusTradeHeaderModel{DealKey: 273201, DealType: "PTP", TransactionDate: tradeDate})
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when marshalling the trade detail", err)
+	}
+
+	anomalyDetected := true
+	filter := NucleusProcessedTradeFilter{
+		FromTradeDate:       &tradeDate,
+		DealType:            "PTP",
+		AnomalyDetectedFlag: &anomalyDetected,
+		ModelName:           "PortfolioLegalEntity",
+		Page:                2,
+		PageSize:            1,
+	}
+	filterArgs := []driver.Value{
+		sql.Named("fromTradeDate", sql.NullTime{Time: tradeDate, Valid: true}),
+		sql.Named("toTradeDate", sql.NullTime{}),
+		sql.Named("dealType", sql.NullString{String: "PTP", Valid: true}),
+		sql.Named("portfolioId", sql.NullInt64{}),
+		sql.Named("anomalyDetectedFlag", sql.NullBool{Bool: true, Valid: true}),
+		sql.Named("modelName", sql.NullString{String: "PortfolioLegalEntity", Valid: true}),
+	}
+
+	tests := []struct {
+		name     string
+		filter   NucleusProcessedTradeFilter
+		mockFunc func(mock sqlmock.Sqlmock)
+		want     *NucleusProcessedTradePage
+	}{
+		{
+			name:   "page of the matching trades",
+			filter: filter,
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(countProcessedTradesQuery).WithArgs(filterArgs...).
+					WillReturnRows(sqlmock.NewRows([]string{"Count"}).AddRow(3))
+				mock.ExpectQuery(getProcessedTradesQuery).WithArgs(append(filterArgs, sql.Named("offset", 1), sql.Named("pageSize", 1))...).
+					WillReturnRows(sqlmock.NewRows([]string{
+						"TradeId", "DealType", "PortfolioId", "TransactionDate", "TradeDetail",
+						"AnomalyDetectedFlag", "AnomalyTestResult", "ModelParameters",
+					}).AddRow(
+						273201, "PTP", 1150, tradeDate, string(tradeDetail),
+						true, "PortfolioLegalEntity legal entity doesn't match", nil,
+					))
+			},
+			want: &NucleusProcessedTradePage{
+				Page:       2,
+				PageSize:   1,
+				TotalCount: 3,
+				Trades: []*NucleusProcessedTrade{
+					{
+						DealKey:             273201,
+						DealType:            "PTP",
+						PortfolioId:         1150,
+						TransactionDate:     tradeDate,
+						AnomalyDetectedFlag: true,
+						AnomalyTestResult:   "PortfolioLegalEntity legal entity doesn't match",
+						TradeDetail:         &nucleus.NucleusTradeHeaderModel{DealKey: 273201, DealType: "PTP", TransactionDate: tradeDate},
+					},
+				},
+			},
+		},
+		{
+			name:   "page after the last one",
+			filter: NucleusProcessedTradeFilter{Page: 3},
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(countProcessedTradesQuery).WithArgs(
+					sql.Named("fromTradeDate", sql.NullTime{}),
+					sql.Named("toTradeDate", sql.NullTime{}),
+					sql.Named("dealType", sql.NullString{}),
+					sql.Named("portfolioId", sql.NullInt64{}),
+					sql.Named("anomalyDetectedFlag", sql.NullBool{}),
+					sql.Named("modelName", sql.NullString{}),
+				).WillReturnRows(sqlmock.NewRows([]string{"Count"}).AddRow(150))
+			},
+			want: &NucleusProcessedTradePage{
+				Page:       3,
+				PageSize:   defaultProcessedTradesPageSize,
+				TotalCount: 150,
+				Trades:     []*NucleusProcessedTrade{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machineLearningDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer machineLearningDb.Close()
+
+			tt.mockFunc(mock)
+
+			repo := &NucleusTradeRepository{
+				nucleusDb:         nil,
+				machineLearningDb: machineLearningDb,
+				logger:            serverLogger,
+			}
+			got, err := repo.GetProcessedTrades(context.TODO(), tt.filter)
+			if err != nil {
+				t.Errorf("NucleusTradeRepository.GetProcessedTrades() error = %v", err)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				gotJson, _ := json.Marshal(got)
+				wantJson, _ := json.Marshal(tt.want)
+				t.Errorf("NucleusTradeRepository.GetProcessedTrades() got = %s, want %s", gotJson, wantJson)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetTradeVersionDiffs(t *testing.T) {
+	now := time.Now()
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	machineLearningDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer machineLearningDb.Close()
+
+	version1 := &nucleus.NucleusTradeHeaderModel{
+		DealKey:   273201,
+		DealType:  "PTP",
+		Portfolio: "PWR EAST",
+		Terms: []*nucleus.NucleusTradeTermModel{
+			{VolSeq: 1, FixedPrice: 41.5, Indexes1: []*nucleus.NucleusTradeIndexModel{{VolSeq: 1, PubIndex: "PJM WH"}}},
+			{VolSeq: 3, FixedPrice: 43},
+		},
+	}
+	version2 := &nucleus.NucleusTradeHeaderModel{
+		DealKey:   273201,
+		DealType:  "PTP",
+		Portfolio: "PWR WEST",
+		Terms: []*nucleus.NucleusTradeTermModel{
+			{VolSeq: 1, FixedPrice: 41.5, Indexes1: []*nucleus.NucleusTradeIndexModel{{VolSeq: 1, PubIndex: "PJM WH"}}},
+			{VolSeq: 3, FixedPrice: 44.25},
+		},
+	}
+	version3 := &nucleus.NucleusTradeHeaderModel{
+		DealKey:   273201,
+		DealType:  "PTP",
+		Portfolio: "PWR WEST",
+		Terms: []*nucleus.NucleusTradeTermModel{
+			{VolSeq: 1, FixedPrice: 41.5, Indexes1: []*nucleus.NucleusTradeIndexModel{{VolSeq: 1, PubIndex: "PJM AD"}}},
+			{VolSeq: 3, FixedPrice: 44.25},
+			{VolSeq: 4, FixedPrice: 45},
+		},
+	}
+
+	rows := sqlmock.NewRows([]string{"TradeId", "DealType", "VersionNumber", "TradeDetail", "CreatedAt"})
+	for i, version := range []*nucleus.NucleusTradeHeaderModel{version1, version2, version3} {
+		tradeDetail, err := json.Marshal(version)
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when marshalling the trade detail", err)
+		}
+		rows.AddRow(273201, "PTP", i+1, string(tradeDetail), now)
+	}
+
+	mock.ExpectQuery(getTradeVersionsQuery).WithArgs(sql.Named("tradeId", 273201), sql.Named("dealType", "PTP")).WillReturnRows(rows)
+
+	volSeq1, volSeq3, volSeq4 := 1, 3, 4
+	want := []*NucleusTradeVersionDiff{
+		{
+			DealKey:       273201,
+			DealType:      "PTP",
+			FromVersion:   1,
+			ToVersion:     2,
+			FromCreatedAt: now,
+			ToCreatedAt:   now,
+			Changes: []*NucleusTradeFieldChange{
+				{Section: TradeSectionHeader, Path: "portfolio", Change: TradeFieldModified, OldValue: "PWR EAST", NewValue: "PWR WEST"},
+				{Section: TradeSectionTerms, VolSeq: &volSeq3, Path: "terms[volSeq=3].fixedPrice", Change: TradeFieldModified, OldValue: float64(43), NewValue: 44.25},
+			},
+		},
+		{
+			DealKey:       273201,
+			DealType:      "PTP",
+			FromVersion:   2,
+			ToVersion:     3,
+			FromCreatedAt: now,
+			ToCreatedAt:   now,
+			Changes: []*NucleusTradeFieldChange{
+				{Section: TradeSectionIndexes, VolSeq: &volSeq1, Path: "terms[volSeq=1].indexes1[0].pubIndex", Change: TradeFieldModified, OldValue: "PJM WH", NewValue: "PJM AD"},
+				{Section: TradeSectionTerms, VolSeq: &volSeq4, Path: "terms[volSeq=4]", Change: TradeFieldAdded, NewValue: version3.Terms[2]},
+			},
+		},
+	}
+
+	repo := &NucleusTradeRepository{
+		nucleusDb:         nil,
+		machineLearningDb: machineLearningDb,
+		logger:            serverLogger,
+	}
+	got, err := repo.GetTradeVersionDiffs(context.TODO(), 273201, "PTP")
+	if err != nil {
+		t.Errorf("NucleusTradeRepository.GetTradeVersionDiffs() error = %v", err)
+		return
+	}
+
+	gotJson, _ := json.Marshal(got)
+	wantJson, _ := json.Marshal(want)
+	if string(gotJson) != string(wantJson) {
+		t.Errorf("NucleusTradeRepository.GetTradeVersionDiffs() got = %s, want %s", gotJson, wantJson)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestNucleusTradeRepository_ExtractAndProcessTrades(t *testing.T) {
+	tradeDate := parseTime("02-06-2022")
+	initialLastRunTime := parseTime("01-06-2022")
+	lastRunTime := parseTime("02-06-2022").Add(6 * time.Hour)
+	nucleusSysdate := parseTime("02-06-2022").Add(10 * time.Hour)
+	timeParameter := nucleusSysdate.Add(-time.Minute)
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	columns := []string{"MISC_CHARGE_KEY", "DEAL_TYPE", "DN_DIRECTION", "TRANSACTION_DATE",
+		"CY_COMPANY_KEY", "COMPANY", "COMPANYLONGNAME", "COMPANYCODE", "LEGALENTITY", "LEGALENTITYLONGNAME",
+		"CYLEGALENTITYKEY", "CONTRACTNUMBER", "CONFIRMFORMAT", "REGION", "HS_HEDGE_KEY",
+		"PRTPORTFOLIO", "PORTFOLIO", "UR_TRADER", "TZ_TIME_ZONE", "HAS_BROKER", "BROKER",
+		"CREATEDBY", "MODIFIEDBY", "CREATE_DATE", "MODIFY_DATE"}
+	extractionRunColumns := []string{"ExtractionRunId", "TransactionDate", "DealType", "TimeParameter", "CreatedAt"}
+	leaseColumns := []string{"TransactionDate", "DealType", "OwnerId", "AcquiredAt", "ExpiresAt"}
+	acquiredLeaseColumns := append(leaseColumns, "AlreadyHeld")
+
+	tvpType := mssql.TVP{
+		TypeName: "NucleusProcessedTradeType",
+	}
+
+	errNucleusUnavailable := errors.New("ORA-12541: TNS:no listener")
+
+	tests := []struct {
+		name                string
+		dealType            string
+		expectNucleusMock   func(mock sqlmock.Sqlmock)
+		expectMlMock        func(mock sqlmock.Sqlmock)
+		wantLastRunTime     time.Time
+		wantExtractedCount  int
+		wantUpsertedByTypes map[string]int
+		wantErr             error
+	}{
+		{
+			name:     "first extraction advances the watermark without deals",
+			dealType: "MISC",
+			expectNucleusMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getNucleusSysdateQuery).WillReturnRows(sqlmock.NewRows([]string{"SYSDATE"}).AddRow(nucleusSysdate))
+				mock.ExpectQuery(getNucMiscChargeDealListQuery).WithArgs(sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", initialLastRunTime)).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			expectMlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(acquireExtractionLeaseQuery)).WithArgs(
+					sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC"), sql.Named("ownerId", "scheduler-1"), sql.Named("ttlMs", int64(300000)),
+				).WillReturnRows(sqlmock.NewRows(acquiredLeaseColumns).AddRow(tradeDate, "MISC", "scheduler-1", lastRunTime, lastRunTime.Add(5*time.Minute), false))
+				mock.ExpectQuery(regexp.QuoteMeta(getLastExtractionRunQuery)).WithArgs(sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC")).
+					WillReturnRows(sqlmock.NewRows(extractionRunColumns))
+				mock.ExpectQuery(regexp.QuoteMeta(insertStartedExtractionRunQuery)).WithArgs(
+					sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC"), sql.Named("timeParameter", timeParameter),
+				).WillReturnRows(sqlmock.NewRows([]string{"ExtractionRunId"}).AddRow(11))
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(succeedExtractionRunIfLatestQuery)).WithArgs(
+					sql.Named("extractedCount", 0), sql.Named("upsertedCount", 0), sql.Named("failedCount", 0), sqlmock.AnyArg(),
+					sql.Named("extractionRunId", 11), sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC"), sql.Named("ownerId", "scheduler-1"), sql.Named("lastExtractionRunId", 0),
+				).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				mock.ExpectExec(regexp.QuoteMeta(releaseExtractionLeaseQuery)).WithArgs(
+					sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC"), sql.Named("ownerId", "scheduler-1"),
+				).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantLastRunTime:     initialLastRunTime,
+			wantUpsertedByTypes: map[string]int{},
+		},
+		{
+			name:     "lease the owner already held isn't released",
+			dealType: "MISC",
+			expectNucleusMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getNucleusSysdateQuery).WillReturnRows(sqlmock.NewRows([]string{"SYSDATE"}).AddRow(nucleusSysdate))
+				mock.ExpectQuery(getNucMiscChargeDealListQuery).WithArgs(sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", initialLastRunTime)).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			expectMlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(acquireExtractionLeaseQuery)).WithArgs(
+					sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC"), sql.Named("ownerId", "scheduler-1"), sql.Named("ttlMs", int64(300000)),
+				).WillReturnRows(sqlmock.NewRows(acquiredLeaseColumns).AddRow(tradeDate, "MISC", "scheduler-1", lastRunTime, lastRunTime.Add(5*time.Minute), true))
+				mock.ExpectQuery(regexp.QuoteMeta(getLastExtractionRunQuery)).WithArgs(sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC")).
+					WillReturnRows(sqlmock.NewRows(extractionRunColumns))
+				mock.ExpectQuery(regexp.QuoteMeta(insertStartedExtractionRunQuery)).WithArgs(
+					sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC"), sql.Named("timeParameter", timeParameter),
+				).WillReturnRows(sqlmock.NewRows([]string{"ExtractionRunId"}).AddRow(11))
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(succeedExtractionRunIfLatestQuery)).WithArgs(
+					sql.Named("extractedCount", 0), sql.Named("upsertedCount", 0), sql.Named("failedCount", 0), sqlmock.AnyArg(),
+					sql.Named("extractionRunId", 11), sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC"), sql.Named("ownerId", "scheduler-1"), sql.Named("lastExtractionRunId", 0),
+				).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantLastRunTime:     initialLastRunTime,
+			wantUpsertedByTypes: map[string]int{},
+		},
+		{
+			name:     "deals are upserted with the watermark",
+			dealType: "MISC",
+			expectNucleusMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getNucleusSysdateQuery).WillReturnRows(sqlmock.NewRows([]string{"SYSDATE"}).AddRow(nucleusSysdate))
+				mock.ExpectQuery(getNucMiscChargeDealListQuery).WithArgs(sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime)).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(
+						2854044, "MISC", "Payable", tradeDate,
+						10171, "ERCOT", "ELECTRIC RELIABILITY COUNCIL OF TEXAS INC", "ERCOT", "SENA", "Shell Energy North America (US), L.P.",
+						10430, "013-KW-SA-20161", "", "", "",
+						255, "CEM ODD LOT", "PCI_ALLO", "", "", "",
+						"PCI_GSMS", "PCI_GSMS", tradeDate, tradeDate,
+					))
+
+				dealKeysQuery, params, err := oracle.CreateInQueryInt([]int{2854044}, []interface{}{}, "pv.mc_misc_charge_key")
+				if err != nil {
+					t.Fatalf("an error '%s' was not expected when creating the query", err)
+				}
+				var namedParams []driver.Value
+				for _, value := range params {
+					valueNamed, _ := value.(sql.NamedArg)
+					namedParams = append(namedParams, valueNamed)
+				}
+				mock.ExpectQuery(getNucMiscChargeDealTermListQuery(dealKeysQuery)).WithArgs(namedParams...).
+					WillReturnRows(sqlmock.NewRows([]string{"MC_MISC_CHARGE_KEY", "MISC_VOL_SEQ", "DY_BEG_DAY", "DY_END_DAY", "INT_VOLUME"}).
+						AddRow(2854044, 0, parseTime("25-05-2022"), parseTime("25-05-2022"), 10))
+			},
+			expectMlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(acquireExtractionLeaseQuery)).WithArgs(
+					sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC"), sql.Named("ownerId", "scheduler-1"), sql.Named("ttlMs", int64(300000)),
+				).WillReturnRows(sqlmock.NewRows(acquiredLeaseColumns).AddRow(tradeDate, "MISC", "scheduler-1", lastRunTime, lastRunTime.Add(5*time.Minute), false))
+				mock.ExpectQuery(regexp.QuoteMeta(getLastExtractionRunQuery)).WithArgs(sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC")).
+					WillReturnRows(sqlmock.NewRows(extractionRunColumns).AddRow(7, tradeDate, "MISC", lastRunTime, lastRunTime))
+				mock.ExpectQuery(regexp.QuoteMeta(insertStartedExtractionRunQuery)).WithArgs(
+					sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC"), sql.Named("timeParameter", timeParameter),
+				).WillReturnRows(sqlmock.NewRows([]string{"ExtractionRunId"}).AddRow(11))
+				mock.ExpectBegin()
+				mock.ExpectQuery(execProcessTradesQuery).WithArgs(sql.Named("TVP", tvpType)).
+					WillReturnRows(sqlmock.NewRows([]string{"TradeId", "DealType", "Action", "AnomalyDetectedFlag"}).AddRow(2854044, "MISC", "INSERT", false))
+				mock.ExpectExec(deleteProcessedTradeModelResultsQuery).WithArgs(sql.Named("TVP", tvpType)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(insertTradeVersionsQuery)).WithArgs(sql.Named("TVP", tvpType)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(succeedExtractionRunIfLatestQuery)).WithArgs(
+					sql.Named("extractedCount", 1), sql.Named("upsertedCount", 1), sql.Named("failedCount", 0), sqlmock.AnyArg(),
+					sql.Named("extractionRunId", 11), sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC"), sql.Named("ownerId", "scheduler-1"), sql.Named("lastExtractionRunId", 7),
+				).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				mock.ExpectExec(regexp.QuoteMeta(releaseExtractionLeaseQuery)).WithArgs(
+					sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC"), sql.Named("ownerId", "scheduler-1"),
+				).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantLastRunTime:     lastRunTime,
+			wantExtractedCount:  1,
+			wantUpsertedByTypes: map[string]int{"MISC": 1},
+		},
+		{
+			name:     "watermark advanced by another extraction rolls back",
+			dealType: "MISC",
+			expectNucleusMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getNucleusSysdateQuery).WillReturnRows(sqlmock.NewRows([]string{"SYSDATE"}).AddRow(nucleusSysdate))
+				mock.ExpectQuery(getNucMiscChargeDealListQuery).WithArgs(sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", lastRunTime)).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			expectMlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(acquireExtractionLeaseQuery)).WithArgs(
+					sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC"), sql.Named("ownerId", "scheduler-1"), sql.Named("ttlMs", int64(300000)),
+				).WillReturnRows(sqlmock.NewRows(acquiredLeaseColumns).AddRow(tradeDate, "MISC", "scheduler-1", lastRunTime, lastRunTime.Add(5*time.Minute), false))
+				mock.ExpectQuery(regexp.QuoteMeta(getLastExtractionRunQuery)).WithArgs(sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC")).
+					WillReturnRows(sqlmock.NewRows(extractionRunColumns).AddRow(7, tradeDate, "MISC", lastRunTime, lastRunTime))
+				mock.ExpectQuery(regexp.QuoteMeta(insertStartedExtractionRunQuery)).WithArgs(
+					sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC"), sql.Named("timeParameter", timeParameter),
+				).WillReturnRows(sqlmock.NewRows([]string{"ExtractionRunId"}).AddRow(11))
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(succeedExtractionRunIfLatestQuery)).WithArgs(
+					sql.Named("extractedCount", 0), sql.Named("upsertedCount", 0), sql.Named("failedCount", 0), sqlmock.AnyArg(),
+					sql.Named("extractionRunId", 11), sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC"), sql.Named("ownerId", "scheduler-1"), sql.Named("lastExtractionRunId", 7),
+				).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+				mock.ExpectExec(regexp.QuoteMeta(failExtractionRunQuery)).WithArgs(
+					sql.Named("extractedCount", 0), sqlmock.AnyArg(), sql.Named("errorText", ErrExtractionRunConflict.Error()), sql.Named("extractionRunId", 11),
+				).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(releaseExtractionLeaseQuery)).WithArgs(
+					sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC"), sql.Named("ownerId", "scheduler-1"),
+				).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantErr: ErrExtractionRunConflict,
+		},
+		{
+			name:     "failing deal list marks the run as failed",
+			dealType: "MISC",
+			expectNucleusMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getNucleusSysdateQuery).WillReturnRows(sqlmock.NewRows([]string{"SYSDATE"}).AddRow(nucleusSysdate))
+				mock.ExpectQuery(getNucMiscChargeDealListQuery).WithArgs(sql.Named("tradeDate", tradeDate), sql.Named("lastRunTime", initialLastRunTime)).
+					WillReturnError(errNucleusUnavailable)
+			},
+			expectMlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(acquireExtractionLeaseQuery)).WithArgs(
+					sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC"), sql.Named("ownerId", "scheduler-1"), sql.Named("ttlMs", int64(300000)),
+				).WillReturnRows(sqlmock.NewRows(acquiredLeaseColumns).AddRow(tradeDate, "MISC", "scheduler-1", lastRunTime, lastRunTime.Add(5*time.Minute), false))
+				mock.ExpectQuery(regexp.QuoteMeta(getLastExtractionRunQuery)).WithArgs(sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC")).
+					WillReturnRows(sqlmock.NewRows(extractionRunColumns))
+				mock.ExpectQuery(regexp.QuoteMeta(insertStartedExtractionRunQuery)).WithArgs(
+					sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC"), sql.Named("timeParameter", timeParameter),
+				).WillReturnRows(sqlmock.NewRows([]string{"ExtractionRunId"}).AddRow(11))
+				mock.ExpectExec(regexp.QuoteMeta(failExtractionRunQuery)).WithArgs(
+					sql.Named("extractedCount", 0), sqlmock.AnyArg(), sql.Named("errorText", errNucleusUnavailable.Error()), sql.Named("extractionRunId", 11),
+				).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(releaseExtractionLeaseQuery)).WithArgs(
+					sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC"), sql.Named("ownerId", "scheduler-1"),
+				).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantErr: errNucleusUnavailable,
+		},
+		{
+			name:              "lease held by another owner",
+			dealType:          "MISC",
+			expectNucleusMock: func(mock sqlmock.Sqlmock) {},
+			expectMlMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(acquireExtractionLeaseQuery)).WithArgs(
+					sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC"), sql.Named("ownerId", "scheduler-1"), sql.Named("ttlMs", int64(300000)),
+				).WillReturnRows(sqlmock.NewRows(acquiredLeaseColumns))
+				mock.ExpectQuery(regexp.QuoteMeta(getExtractionLeaseQuery)).WithArgs(sql.Named("transactionDate", tradeDate), sql.Named("dealType", "MISC")).
+					WillReturnRows(sqlmock.NewRows(leaseColumns).AddRow(tradeDate, "MISC", "scheduler-2", lastRunTime, lastRunTime.Add(5*time.Minute)))
+			},
+			wantErr: ErrExtractionLeaseHeld,
+		},
+		{
+			name:              "deal type without deal list",
+			dealType:          "GAS",
+			expectNucleusMock: func(mock sqlmock.Sqlmock) {},
+			expectMlMock:      func(mock sqlmock.Sqlmock) {},
+			wantErr:           ErrNucDealTypeNotSupported,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nucleusDb, nucleusMock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer nucleusDb.Close()
+
+			machineLearningDb, mlMock, err := sqlmock.New(sqlmock.ValueConverterOption(&mockNucleusProcessedTradeConverter{}))
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer machineLearningDb.Close()
+
+			tt.expectNucleusMock(nucleusMock)
+			tt.expectMlMock(mlMock)
+
+			repo := NewNucleusTradeRepository(nucleusDb, machineLearningDb, serverLogger, WithExtractionWatermarkOverlap(time.Minute))
+			got, err := repo.ExtractAndProcessTrades(context.TODO(), tradeDate, tt.dealType, initialLastRunTime, "scheduler-1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("NucleusTradeRepository.ExtractAndProcessTrades() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr == nil {
+				if !got.LastRunTime.Equal(tt.wantLastRunTime) {
+					t.Errorf("NucleusTradeRepository.ExtractAndProcessTrades() lastRunTime = %v, want %v", got.LastRunTime, tt.wantLastRunTime)
+				}
+				if !got.TimeParameter.Equal(timeParameter) {
+					t.Errorf("NucleusTradeRepository.ExtractAndProcessTrades() timeParameter = %v, want %v", got.TimeParameter, timeParameter)
+				}
+				if got.ExtractionRunId != 11 {
+					t.Errorf("NucleusTradeRepository.ExtractAndProcessTrades() extractionRunId = %d, want 11", got.ExtractionRunId)
+				}
+				if got.ExtractedCount != tt.wantExtractedCount {
+					t.Errorf("NucleusTradeRepository.ExtractAndProcessTrades() extractedCount = %d, want %d", got.ExtractedCount, tt.wantExtractedCount)
+				}
+				if !reflect.DeepEqual(got.ProcessTrades.UpsertedByDealType, tt.wantUpsertedByTypes) {
+					t.Errorf("NucleusTradeRepository.ExtractAndProcessTrades() upsertedByDealType = %v, want %v", got.ProcessTrades.UpsertedByDealType, tt.wantUpsertedByTypes)
+				}
+			}
+
+			if err := nucleusMock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled nucleus expectations: %s", err)
+			}
+			if err := mlMock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled machine learning expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_GetExtractionRuns(t *testing.T) {
+	fromTradeDate := parseTime("01-06-2022")
+	toTradeDate := parseTime("02-06-2022")
+	finishedAt := parseTime("02-06-2022").Add(time.Hour)
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	machineLearningDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer machineLearningDb.Close()
+
+	mock.ExpectQuery(getExtractionRunsQuery).WithArgs(
+		sql.Named("fromTradeDate", fromTradeDate),
+		sql.Named("toTradeDate", toTradeDate),
+		sql.Named("dealType", sql.NullString{String: "MISC", Valid: true}),
+		sql.Named("status", sql.NullString{}),
+	).WillReturnRows(sqlmock.NewRows([]string{"ExtractionRunId", "TransactionDate", "DealType", "TimeParameter", "Status",
+		"ExtractedCount", "UpsertedCount", "FailedCount", "DurationMs", "ErrorText", "CreatedAt", "FinishedAt"}).
+		AddRow(11, fromTradeDate, "MISC", fromTradeDate, ExtractionRunSucceeded, 120, 118, 2, 5400, nil, fromTradeDate, finishedAt).
+		AddRow(12, toTradeDate, "MISC", toTradeDate, ExtractionRunFailed, 0, nil, nil, 30000, "ORA-12541: TNS:no listener", toTradeDate, finishedAt).
+		AddRow(13, toTradeDate, "MISC", toTradeDate, ExtractionRunStarted, nil, nil, nil, nil, nil, toTradeDate, nil))
+
+	want := []*NucleusExtractionRun{
+		{
+			ExtractionRunId: 11, TransactionDate: fromTradeDate, DealType: "MISC", TimeParameter: fromTradeDate, Status: ExtractionRunSucceeded,
+			ExtractedCount: 120, UpsertedCount: 118, FailedCount: 2, DurationMs: 5400, CreatedAt: fromTradeDate, FinishedAt: &finishedAt,
+		},
+		{
+			ExtractionRunId: 12, TransactionDate: toTradeDate, DealType: "MISC", TimeParameter: toTradeDate, Status: ExtractionRunFailed,
+			DurationMs: 30000, ErrorText: "ORA-12541: TNS:no listener", CreatedAt: toTradeDate, FinishedAt: &finishedAt,
+		},
+		{
+			ExtractionRunId: 13, TransactionDate: toTradeDate, DealType: "MISC", TimeParameter: toTradeDate, Status: ExtractionRunStarted,
+			CreatedAt: toTradeDate,
+		},
+	}
+
+	repo := &NucleusTradeRepository{
+		nucleusDb:         nil,
+		machineLearningDb: machineLearningDb,
+		logger:            serverLogger,
+	}
+	got, err := repo.GetExtractionRuns(context.TODO(), fromTradeDate, toTradeDate, "MISC", "")
+	if err != nil {
+		t.Errorf("NucleusTradeRepository.GetExtractionRuns() error = %v", err)
+		return
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		gotJson, _ := json.Marshal(got)
+		wantJson, _ := json.Marshal(want)
+		t.Errorf("NucleusTradeRepository.GetExtractionRuns() got = %s, want %s", gotJson, wantJson)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestNucleusTradeRepository_RenewExtractionLease(t *testing.T) {
+	tradeDate := parseTime("02-06-2022")
+	expiresAt := tradeDate.Add(time.Hour)
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	leaseColumns := []string{"TransactionDate", "DealType", "OwnerId", "AcquiredAt", "ExpiresAt"}
+
+	tests := []struct {
+		name      string
+		ownerId   string
+		mockFunc  func(mock sqlmock.Sqlmock)
+		wantLease *NucleusExtractionLease
+		wantErr   error
+	}{
+		{
+			name:    "lease renewed",
+			ownerId: "scheduler-1",
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(renewExtractionLeaseQuery).WithArgs(
+					sql.Named("ttlMs", int64(60000)), sql.Named("transactionDate", tradeDate), sql.Named("dealType", "PTP"), sql.Named("ownerId", "scheduler-1"),
+				).WillReturnRows(sqlmock.NewRows(leaseColumns).AddRow(tradeDate, "PTP", "scheduler-1", tradeDate, expiresAt))
+			},
+			wantLease: &NucleusExtractionLease{TransactionDate: tradeDate, DealType: "PTP", OwnerId: "scheduler-1", AcquiredAt: tradeDate, ExpiresAt: expiresAt},
+		},
+		{
+			name:    "lease expired or taken",
+			ownerId: "scheduler-2",
+			mockFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(renewExtractionLeaseQuery).WithArgs(
+					sql.Named("ttlMs", int64(60000)), sql.Named("transactionDate", tradeDate), sql.Named("dealType", "PTP"), sql.Named("ownerId", "scheduler-2"),
+				).WillReturnRows(sqlmock.NewRows(leaseColumns))
+			},
+			wantErr: ErrExtractionLeaseLost,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machineLearningDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer machineLearningDb.Close()
+
+			tt.mockFunc(mock)
+
+			repo := NewNucleusTradeRepository(nil, machineLearningDb, serverLogger, WithExtractionLeaseTTL(time.Minute))
+			got, err := repo.RenewExtractionLease(context.TODO(), tradeDate, "PTP", tt.ownerId, 0)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("NucleusTradeRepository.RenewExtractionLease() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.wantLease) {
+				t.Errorf("NucleusTradeRepository.RenewExtractionLease() got = %v, want %v", got, tt.wantLease)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_extractionLeaseKeeper_renew(t *testing.T) {
+	tradeDate := parseTime("02-06-2022")
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	leaseColumns := []string{"TransactionDate", "DealType", "OwnerId", "AcquiredAt", "ExpiresAt"}
+	renewArgs := []driver.Value{
+		sql.Named("ttlMs", int64(3000)), sql.Named("transactionDate", tradeDate), sql.Named("dealType", "PTP"), sql.Named("ownerId", "scheduler-1"),
+	}
+
+	t.Run("database errors are retried while the lease holds", func(t *testing.T) {
+		machineLearningDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer machineLearningDb.Close()
+
+		mock.ExpectQuery(renewExtractionLeaseQuery).WithArgs(renewArgs...).WillReturnError(errors.New("connection reset"))
+		mock.ExpectQuery(renewExtractionLeaseQuery).WithArgs(renewArgs...).WillReturnError(errors.New("connection reset"))
+		mock.ExpectQuery(renewExtractionLeaseQuery).WithArgs(renewArgs...).
+			WillReturnRows(sqlmock.NewRows(leaseColumns).AddRow(tradeDate, "PTP", "scheduler-1", tradeDate, tradeDate))
+
+		repo := NewNucleusTradeRepository(nil, machineLearningDb, serverLogger, WithExtractionLeaseTTL(3*time.Second))
+		keeper := repo.keepExtractionLease(context.TODO(), tradeDate, "PTP", "scheduler-1", time.Now(), false)
+
+		deadline := time.Now().Add(3 * time.Second)
+		for mock.ExpectationsWereMet() != nil && keeper.ctx.Err() == nil && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+		keeper.stop()
+
+		if err := keeper.cause(nil); err != nil {
+			t.Errorf("extractionLeaseKeeper.renew() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("lease lost once expired", func(t *testing.T) {
+		machineLearningDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer machineLearningDb.Close()
+
+		mock.ExpectQuery(renewExtractionLeaseQuery).WithArgs(renewArgs...).WillReturnError(errors.New("connection reset"))
+
+		// the lease was extended 2 seconds ago, it expires in 1 second
+		repo := NewNucleusTradeRepository(nil, machineLearningDb, serverLogger, WithExtractionLeaseTTL(3*time.Second))
+		keeper := repo.keepExtractionLease(context.TODO(), tradeDate, "PTP", "scheduler-1", time.Now().Add(-2*time.Second), false)
+
+		select {
+		case <-keeper.ctx.Done():
+		case <-time.After(3 * time.Second):
+			t.Errorf("extractionLeaseKeeper.renew() kept a lease past its expiry")
+		}
+		keeper.stop()
+
+		if err := keeper.cause(nil); !errors.Is(err, ErrExtractionLeaseLost) {
+			t.Errorf("extractionLeaseKeeper.renew() error = %v, want %v", err, ErrExtractionLeaseLost)
+		}
+	})
+}
+
+func TestNucleusTradeRepository_GetExtractionGaps(t *testing.T) {
+	fromTradeDate := parseTime("01-06-2022")
+	toTradeDate := parseTime("03-06-2022")
+	recent := time.Now().Add(-time.Hour)
+	old := time.Now().Add(-72 * time.Hour)
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	machineLearningDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer machineLearningDb.Close()
+
+	// the watermark of MISC on fromTradeDate is old but its run recent, the
+	// staleness only depends on the runs
+	mock.ExpectQuery(getLastTimeParametersQuery).WithArgs(sql.Named("fromTradeDate", fromTradeDate), sql.Named("toTradeDate", toTradeDate)).
+		WillReturnRows(sqlmock.NewRows([]string{"TransactionDate", "DealType", "TimeParameter", "FinishedAt"}).
+			AddRow(fromTradeDate, "MISC", old, recent).
+			AddRow(fromTradeDate, "PTP", old, old).
+			AddRow(parseTime("02-06-2022"), "MISC", recent, recent))
+
+	want := &NucleusBackfillPlan{
+		FromTradeDate: fromTradeDate,
+		ToTradeDate:   toTradeDate,
+		DealTypes:     []string{"MISC", "PTP"},
+		Gaps: []*NucleusExtractionGap{
+			{TradeDate: fromTradeDate, DealType: "PTP", Reason: ExtractionGapStale, LastTimeParameter: &old, LastSucceededAt: &old},
+			{TradeDate: parseTime("02-06-2022"), DealType: "PTP", Reason: ExtractionGapMissing},
+			{TradeDate: toTradeDate, DealType: "MISC", Reason: ExtractionGapMissing},
+			{TradeDate: toTradeDate, DealType: "PTP", Reason: ExtractionGapMissing},
+		},
+	}
+
+	repo := &NucleusTradeRepository{
+		nucleusDb:         nil,
+		machineLearningDb: machineLearningDb,
+		logger:            serverLogger,
+	}
+	got, err := repo.GetExtractionGaps(context.TODO(), fromTradeDate, toTradeDate, []string{"MISC", "PTP"}, 24*time.Hour)
+	if err != nil {
+		t.Errorf("NucleusTradeRepository.GetExtractionGaps() error = %v", err)
+		return
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		gotJson, _ := json.Marshal(got)
+		wantJson, _ := json.Marshal(want)
+		t.Errorf("NucleusTradeRepository.GetExtractionGaps() got = %s, want %s", gotJson, wantJson)
+	}
+
+	if _, err := repo.GetExtractionGaps(context.TODO(), fromTradeDate, toTradeDate, []string{"GAS"}, 0); !errors.Is(err, ErrNucDealTypeNotSupported) {
+		t.Errorf("NucleusTradeRepository.GetExtractionGaps() error = %v, wantErr %v", err, ErrNucDealTypeNotSupported)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+// backfillRepositoryStub only implements the ExtractAndProcessTrades used by
+// NucleusBackfillService
+type backfillRepositoryStub struct {
+	INucleusTradeRepository
+	failingDealType  string
+	blockingDealType string
+}
+
+func (stub *backfillRepositoryStub) ExtractAndProcessTrades(ctx context.Context, tradeDate time.Time, dealType string, initialLastRunTime time.Time, ownerId string) (*NucleusExtractAndProcessSummary, error) {
+	if dealType == stub.failingDealType {
+		return nil, ErrExtractionLeaseHeld
+	}
+	if dealType == stub.blockingDealType {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return &NucleusExtractAndProcessSummary{ExtractionRunId: 42, TradeDate: tradeDate, DealType: dealType, OwnerId: ownerId}, nil
+}
+
+func TestNucleusBackfillService_StartBackfill(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	tradeDate := parseTime("01-06-2022")
+	plan := &NucleusBackfillPlan{
+		Gaps: []*NucleusExtractionGap{
+			{TradeDate: tradeDate, DealType: "MISC", Reason: ExtractionGapMissing},
+			{TradeDate: tradeDate, DealType: "PTP", Reason: ExtractionGapMissing},
+		},
+	}
+
+	service := NewNucleusBackfillService(context.TODO(), &backfillRepositoryStub{failingDealType: "PTP"}, serverLogger)
+	started, err := service.StartBackfill(plan, "backfill-1")
+	if err != nil {
+		t.Fatalf("NucleusBackfillService.StartBackfill() error = %v", err)
+	}
+	if started.TotalCount != 2 {
+		t.Fatalf("NucleusBackfillService.StartBackfill() totalCount = %d, want 2", started.TotalCount)
+	}
+
+	var got *NucleusBackfill
+	for attempt := 0; attempt < 100; attempt++ {
+		got = service.GetBackfill(started.BackfillId)
+		if got.FinishedAt != nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if got.Status != BackfillFailed || got.CompletedCount != 2 || got.FailedCount != 1 {
+		t.Errorf("NucleusBackfillService.GetBackfill() status = %s, completed = %d, failed = %d", got.Status, got.CompletedCount, got.FailedCount)
+	}
+	if got.Items[0].Status != BackfillSucceeded || got.Items[0].ExtractionRunId != 42 {
+		t.Errorf("NucleusBackfillService.GetBackfill() first item = %+v", got.Items[0])
+	}
+	if got.Items[1].Status != BackfillFailed || got.Items[1].Error != ErrExtractionLeaseHeld.Error() {
+		t.Errorf("NucleusBackfillService.GetBackfill() second item = %+v", got.Items[1])
+	}
+
+	if service.GetBackfill("unknown") != nil {
+		t.Errorf("NucleusBackfillService.GetBackfill() of an unknown backfill should be nil")
+	}
+}
+
+func TestNucleusBackfillService_CancelBackfill(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	tradeDate := parseTime("01-06-2022")
+	plan := &NucleusBackfillPlan{
+		Gaps: []*NucleusExtractionGap{
+			{TradeDate: tradeDate, DealType: "MISC", Reason: ExtractionGapMissing},
+			{TradeDate: tradeDate, DealType: "PTP", Reason: ExtractionGapMissing},
+		},
+	}
+
+	service := NewNucleusBackfillService(context.TODO(), &backfillRepositoryStub{blockingDealType: "MISC"}, serverLogger)
+	started, err := service.StartBackfill(plan, "backfill-1")
+	if err != nil {
+		t.Fatalf("NucleusBackfillService.StartBackfill() error = %v", err)
+	}
+
+	overlapping := &NucleusBackfillPlan{
+		Gaps: []*NucleusExtractionGap{{TradeDate: tradeDate, DealType: "PTP", Reason: ExtractionGapMissing}},
+	}
+	if _, err := service.StartBackfill(overlapping, "backfill-2"); !errors.Is(err, ErrBackfillOverlap) {
+		t.Errorf("NucleusBackfillService.StartBackfill() of an overlapping plan error = %v, want %v", err, ErrBackfillOverlap)
+	}
+
+	if service.CancelBackfill(started.BackfillId) == nil {
+		t.Fatalf("NucleusBackfillService.CancelBackfill() of a running backfill should not be nil")
+	}
+
+	var got *NucleusBackfill
+	for attempt := 0; attempt < 100; attempt++ {
+		got = service.GetBackfill(started.BackfillId)
+		if got.FinishedAt != nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if got.Status != BackfillCancelled || got.CompletedCount != 0 || got.FailedCount != 0 {
+		t.Errorf("NucleusBackfillService.GetBackfill() status = %s, completed = %d, failed = %d", got.Status, got.CompletedCount, got.FailedCount)
+	}
+	for _, item := range got.Items {
+		if item.Status != BackfillCancelled {
+			t.Errorf("NucleusBackfillService.GetBackfill() item = %+v, want it cancelled", item)
+		}
+	}
+
+	if _, err := service.StartBackfill(overlapping, "backfill-2"); err != nil {
+		t.Errorf("NucleusBackfillService.StartBackfill() after the cancellation error = %v", err)
+	}
+
+	if service.CancelBackfill("unknown") != nil {
+		t.Errorf("NucleusBackfillService.CancelBackfill() of an unknown backfill should be nil")
+	}
+}
+
+func TestNucleusBackfillService_Stop(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	plan := &NucleusBackfillPlan{
+		Gaps: []*NucleusExtractionGap{{TradeDate: parseTime("01-06-2022"), DealType: "MISC", Reason: ExtractionGapMissing}},
+	}
+
+	service := NewNucleusBackfillService(context.TODO(), &backfillRepositoryStub{blockingDealType: "MISC"}, serverLogger)
+	started, err := service.StartBackfill(plan, "backfill-1")
+	if err != nil {
+		t.Fatalf("NucleusBackfillService.StartBackfill() error = %v", err)
+	}
+
+	service.Stop()
+
+	if got := service.GetBackfill(started.BackfillId); got.FinishedAt == nil || got.Status != BackfillCancelled {
+		t.Errorf("NucleusBackfillService.GetBackfill() after Stop() = %+v, want it cancelled", got)
+	}
+
+	if _, err := service.StartBackfill(plan, "backfill-2"); !errors.Is(err, ErrBackfillServiceStopped) {
+		t.Errorf("NucleusBackfillService.StartBackfill() after Stop() error = %v, want %v", err, ErrBackfillServiceStopped)
+	}
+}
+
+func TestNucleusBackfillService_evictFinishedBackfills(t *testing.T) {
+	service := NewNucleusBackfillService(context.TODO(), &backfillRepositoryStub{}, logger.GetServerLogger())
+
+	now := time.Now()
+	expiredAt := now.Add(-finishedBackfillRetention - time.Minute)
+	service.backfills["expired"] = &NucleusBackfill{BackfillId: "expired", Status: BackfillSucceeded, FinishedAt: &expiredAt}
+	service.backfills["running"] = &NucleusBackfill{BackfillId: "running", Status: BackfillRunning}
+	for index := 0; index <= maxFinishedBackfills; index++ {
+		finishedAt := now.Add(time.Duration(index-maxFinishedBackfills) * time.Second)
+		backfillId := fmt.Sprintf("finished-%d", index)
+		service.backfills[backfillId] = &NucleusBackfill{BackfillId: backfillId, Status: BackfillSucceeded, FinishedAt: &finishedAt}
+	}
+
+	service.evictFinishedBackfills()
+
+	if len(service.backfills) != maxFinishedBackfills+1 {
+		t.Errorf("evictFinishedBackfills() kept %d backfills, want %d", len(service.backfills), maxFinishedBackfills+1)
+	}
+	for _, backfillId := range []string{"expired", "finished-0"} {
+		if _, ok := service.backfills[backfillId]; ok {
+			t.Errorf("evictFinishedBackfills() kept backfill %s", backfillId)
+		}
+	}
+	for _, backfillId := range []string{"running", "finished-1", fmt.Sprintf("finished-%d", maxFinishedBackfills)} {
+		if _, ok := service.backfills[backfillId]; !ok {
+			t.Errorf("evictFinishedBackfills() evicted backfill %s", backfillId)
+		}
+	}
+}
+
+// schedulerRepositoryStub only implements the ExtractAndProcessTrades and
+// GetNucDealTypes used by NucleusExtractionScheduler
+type schedulerRepositoryStub struct {
+	INucleusTradeRepository
+	runs chan string
+}
+
+func (stub *schedulerRepositoryStub) GetNucDealTypes() []string {
+	return []string{"MISC"}
+}
+
+func (stub *schedulerRepositoryStub) ExtractAndProcessTrades(ctx context.Context, tradeDate time.Time, dealType string, initialLastRunTime time.Time, ownerId string) (*NucleusExtractAndProcessSummary, error) {
+	select {
+	case stub.runs <- dealType + "/" + tradeDate.Format(extractionGapDateLayout):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &NucleusExtractAndProcessSummary{TradeDate: tradeDate, DealType: dealType, OwnerId: ownerId}, nil
+}
+
+func TestNucleusExtractionScheduler_Run(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	stub := &schedulerRepositoryStub{runs: make(chan string)}
+	scheduler := NewNucleusExtractionScheduler(stub, serverLogger, map[string]*NucleusExtractionSchedule{
+		"MISC": {Enabled: true, Interval: time.Millisecond, Jitter: time.Millisecond, LookbackDays: 2},
+		"PTP":  {Enabled: false, Interval: time.Millisecond},
+	}, "scheduler-1")
+	scheduler.now = func() time.Time { return time.Date(2022, 6, 1, 17, 30, 0, 0, time.Local) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		scheduler.Run(ctx)
+		close(stopped)
+	}()
+
+	// every run extracts the trade date then the lookback ones
+	wantRuns := []string{"MISC/2022-06-01", "MISC/2022-05-31", "MISC/2022-05-30"}
+	for i := 0; i < 2*len(wantRuns); i++ {
+		select {
+		case run := <-stub.runs:
+			if want := wantRuns[i%len(wantRuns)]; run != want {
+				t.Errorf("NucleusExtractionScheduler.Run() ran %s, want %s", run, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("NucleusExtractionScheduler.Run() didn't run MISC")
+		}
+	}
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatalf("NucleusExtractionScheduler.Run() didn't stop when its context was cancelled")
+	}
+}
+
+func TestStartNucleusExtractionScheduler(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	stub := &schedulerRepositoryStub{runs: make(chan string)}
+
+	stop, err := StartNucleusExtractionScheduler(context.Background(), stub, serverLogger)
+	if err != nil {
+		t.Fatalf("StartNucleusExtractionScheduler() error = %v", err)
+	}
+	stop()
+
+	t.Setenv(extractionSchedulerEnabledEnv, "true")
+	t.Setenv(extractionIntervalEnv, "1ms")
+
+	stop, err = StartNucleusExtractionScheduler(context.Background(), stub, serverLogger)
+	if err != nil {
+		t.Fatalf("StartNucleusExtractionScheduler() error = %v", err)
+	}
+
+	select {
+	case <-stub.runs:
+	case <-time.After(time.Second):
+		t.Fatalf("StartNucleusExtractionScheduler() didn't run MISC")
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatalf("the stop func of StartNucleusExtractionScheduler() didn't return")
+	}
+}
+
+func TestLoadNucleusExtractionSchedules(t *testing.T) {
+	t.Setenv(extractionIntervalEnv, "10m")
+	t.Setenv(extractionIntervalEnv+"_PTP", "1h")
+	t.Setenv(extractionJitterEnv, "30s")
+	t.Setenv(extractionDisabledDealTypesEnv, "TRANS, MISC")
+	t.Setenv(extractionLookbackDaysEnv, "1")
+	t.Setenv(extractionLookbackDaysEnv+"_POWER", "3")
+
+	got, err := LoadNucleusExtractionSchedules([]string{"MISC", "PTP", "POWER"})
+	if err != nil {
+		t.Fatalf("LoadNucleusExtractionSchedules() error = %v", err)
+	}
+
+	want := map[string]*NucleusExtractionSchedule{
+		"MISC":  {Enabled: false, Interval: 10 * time.Minute, Jitter: 30 * time.Second, LookbackDays: 1},
+		"PTP":   {Enabled: true, Interval: time.Hour, Jitter: 30 * time.Second, LookbackDays: 1},
+		"POWER": {Enabled: true, Interval: 10 * time.Minute, Jitter: 30 * time.Second, LookbackDays: 3},
+	}
+	if !reflect.DeepEqual(got, want) {
+		gotJson, _ := json.Marshal(got)
+		wantJson, _ := json.Marshal(want)
+		t.Errorf("LoadNucleusExtractionSchedules() got = %s, want %s", gotJson, wantJson)
+	}
+
+	t.Setenv(extractionLookbackDaysEnv+"_PTP", "-1")
+	if _, err := LoadNucleusExtractionSchedules([]string{"PTP"}); err == nil {
+		t.Errorf("LoadNucleusExtractionSchedules() should fail on a negative number of days")
+	}
+
+	t.Setenv(extractionJitterEnv+"_POWER", "soon")
+	if _, err := LoadNucleusExtractionSchedules([]string{"POWER"}); err == nil {
+		t.Errorf("LoadNucleusExtractionSchedules() should fail on an invalid duration")
+	}
+}
+
+// minVolumeRule flags the trades with terms below a volume
+type minVolumeRule struct {
+	name      string
+	minVolume float64
+}
+
+func (rule *minVolumeRule) Name() string {
+	return rule.name
+}
+
+func (rule *minVolumeRule) Evaluate(trade *nucleus.NucleusTradeHeaderModel, referenceData *NucleusReferenceData) (common.IModelBasePayload, error) {
+	if len(trade.Terms) == 0 {
+		return nil, nil
+	}
+	for _, term := range trade.Terms {
+		if term.Volume < rule.minVolume {
+			return NewRuleResult(rule, trade, false, "volume below the minimum"), nil
+		}
+	}
+	return NewRuleResult(rule, trade, true, ""), nil
+}
+
+func TestNucleusRuleEngine_Evaluate(t *testing.T) {
+	engine := NewNucleusRuleEngine([]string{"MinVolume", "PowerMinVolume"})
+	engine.Register(&minVolumeRule{name: "MinVolume", minVolume: 1})
+	engine.Register(&minVolumeRule{name: "PowerMinVolume", minVolume: 100}, "POWER")
+	engine.Register(&minVolumeRule{name: "Disabled", minVolume: 1000})
+
+	trades := []*nucleus.NucleusTradeHeaderModel{
+		{DealKey: 1, DealType: "POWER", Terms: []*nucleus.NucleusTradeTermModel{{VolSeq: 1, Volume: 50}}},
+		{DealKey: 2, DealType: "PTP", Terms: []*nucleus.NucleusTradeTermModel{{VolSeq: 1, Volume: 50}}},
+		{DealKey: 3, DealType: "PTP"},
+	}
+
+	got, err := engine.Evaluate(trades, &NucleusReferenceData{})
+	if err != nil {
+		t.Fatalf("NucleusRuleEngine.Evaluate() error = %v", err)
+	}
+
+	want := map[int][]common.IModelBasePayload{
+		1: {
+			&common.ResultModelBasePayload{ModelName: "MinVolume", DealKey: 1, DealType: "POWER", ScoredLabel: RuleScoredLabelYes},
+			&common.ResultModelBasePayload{ModelName: "PowerMinVolume", Message: "volume below the minimum", DealKey: 1, DealType: "POWER", ScoredLabel: RuleScoredLabelNo},
+		},
+		2: {
+			&common.ResultModelBasePayload{ModelName: "MinVolume", DealKey: 2, DealType: "PTP", ScoredLabel: RuleScoredLabelYes},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		gotJson, _ := json.Marshal(got)
+		wantJson, _ := json.Marshal(want)
+		t.Errorf("NucleusRuleEngine.Evaluate() got = %s, want %s", gotJson, wantJson)
+	}
+
+	if !engine.HasRules("PTP") || NewNucleusRuleEngine(nil).HasRules("PTP") {
+		t.Errorf("NucleusRuleEngine.HasRules() should only report the enabled rules")
+	}
+}
+
+func TestNucleusTradeRepository_EvaluateTrades(t *testing.T) {
+	serverLogger := logger.GetServerLogger()
+	serverLogger.Enable(false)
+
+	machineLearningDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer machineLearningDb.Close()
+
+	engine := NewNucleusRuleEngine([]string{"PwrnsdMinVolume"})
+	engine.Register(&minVolumeRule{name: "PwrnsdMinVolume", minVolume: 100}, "PWRNSD")
+
+	repo := NewNucleusTradeRepository(nil, machineLearningDb, serverLogger, WithRuleEngine(engine))
+
+	// the POWER deal list holds the PWRNSD deals
+	powerTrades := []*nucleus.NucleusTradeHeaderModel{
+		{DealKey: 1, DealType: "POWER", Terms: []*nucleus.NucleusTradeTermModel{{VolSeq: 1, Volume: 50}}},
+	}
+	got, err := repo.EvaluateTrades(context.TODO(), powerTrades)
+	if err != nil || got != nil {
+		t.Errorf("NucleusTradeRepository.EvaluateTrades() without rules got = %v, error = %v, want nil", got, err)
+	}
+
+	mock.ExpectQuery(getPortfolioRiskMappingListQuery).WillReturnRows(sqlmock.NewRows([]string{"SourceSystem", "Portfolio", "LegalEntity"}))
+	mock.ExpectQuery(getLarBaselistQuery).WillReturnRows(sqlmock.NewRows([]string{"ShortName"}))
+
+	trades := append(powerTrades, &nucleus.NucleusTradeHeaderModel{DealKey: 2, DealType: "PWRNSD", Terms: []*nucleus.NucleusTradeTermModel{{VolSeq: 1, Volume: 50}}})
+	got, err = repo.EvaluateTrades(context.TODO(), trades)
+	if err != nil {
+		t.Fatalf("NucleusTradeRepository.EvaluateTrades() error = %v", err)
+	}
+
+	want := map[int][]common.IModelBasePayload{
+		2: {&common.ResultModelBasePayload{ModelName: "PwrnsdMinVolume", Message: "volume below the minimum", DealKey: 2, DealType: "PWRNSD", ScoredLabel: RuleScoredLabelNo}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		gotJson, _ := json.Marshal(got)
+		wantJson, _ := json.Marshal(want)
+		t.Errorf("NucleusTradeRepository.EvaluateTrades() got = %s, want %s", gotJson, wantJson)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestNewDefaultNucleusRuleEngine(t *testing.T) {
+	t.Setenv(anomalyRulesEnv, PortfolioLegalEntityRuleName+", "+CreditLimitRuleName)
+
+	repo := NewNucleusTradeRepository(nil, nil, nil)
+	for _, dealType := range repo.GetNucDealTypes() {
+		var names []string
+		for _, rule := range repo.ruleEngine.getRules(dealType) {
+			names = append(names, rule.Name())
+		}
+
+		if want := []string{PortfolioLegalEntityRuleName, CreditLimitRuleName}; !reflect.DeepEqual(names, want) {
+			t.Errorf("NewNucleusTradeRepository() rules of %s = %v, want %v", dealType, names, want)
+		}
+	}
+
+	if NewDefaultNucleusRuleEngine(nil).HasRules("POWER") {
+		t.Errorf("NewDefaultNucleusRuleEngine() should only evaluate the enabled rules")
+	}
+}
+
+func TestPortfolioLegalEntityRule_Evaluate(t *testing.T) {
+	referenceData := &NucleusReferenceData{
+		PortfolioRiskMappings: []*nucleus.PortfolioRiskMappingModel{
+			{SourceSystem: "NUCLEUS", Portfolio: "WEST POWER", LegalEntity: "SENA"},
+			{SourceSystem: "NUCLEUS", Portfolio: "1234", LegalEntity: "SEUK"},
+			{SourceSystem: "NUCLEUS", Portfolio: "EAST POWER", LegalEntity: "SENA"},
+			{SourceSystem: "NUCLEUS", Portfolio: "EUROPE POWER", LegalEntity: "Shell Energy Europe Limited"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		trade       *nucleus.NucleusTradeHeaderModel
+		scoredLabel string
+		message     string
+	}{
+		{
+			name:        "mapped by name",
+			trade:       &nucleus.NucleusTradeHeaderModel{DealKey: 1, DealType: "POWER", Portfolio: "west power ", PrtPortfolio: 10, LegalEntity: "SENA"},
+			scoredLabel: RuleScoredLabelYes,
+		},
+		{
+			name:        "mapped by key",
+			trade:       &nucleus.NucleusTradeHeaderModel{DealKey: 2, DealType: "POWER", Portfolio: "UK POWER", PrtPortfolio: 1234, LegalEntity: "SEUK"},
+			scoredLabel: RuleScoredLabelYes,
+		},
+		{
+			name:        "missing portfolio",
+			trade:       &nucleus.NucleusTradeHeaderModel{DealKey: 3, DealType: "POWER", Portfolio: "GAS", PrtPortfolio: 11, LegalEntity: "SENA"},
+			scoredLabel: RuleScoredLabelNo,
+			message:     "portfolio GAS (11) is missing from the portfolio risk mapping",
+		},
+		{
+			name:        "other legal entity",
+			trade:       &nucleus.NucleusTradeHeaderModel{DealKey: 4, DealType: "POWER", Portfolio: "WEST POWER", PrtPortfolio: 10, LegalEntity: "SEUK"},
+			scoredLabel: RuleScoredLabelNo,
+			message:     "portfolio WEST POWER (10) is mapped to legal entity SENA, not SEUK",
+		},
+		{
+			name:        "missing interbook portfolio",
+			trade:       &nucleus.NucleusTradeHeaderModel{DealKey: 5, DealType: "PSWPS", Portfolio: "WEST POWER", PrtPortfolio: 10, LegalEntity: "SENA", IbPortfolio: "NORTH POWER", IbPrtPortfolio: 12},
+			scoredLabel: RuleScoredLabelNo,
+			message:     "interbook portfolio NORTH POWER (12) is missing from the portfolio risk mapping",
+		},
+		{
+			name:        "mapped interbook portfolio",
+			trade:       &nucleus.NucleusTradeHeaderModel{DealKey: 6, DealType: "PSWPS", Portfolio: "WEST POWER", PrtPortfolio: 10, LegalEntity: "SENA", IbPortfolio: "EAST POWER", IbPrtPortfolio: 13, Company: "SENA"},
+			scoredLabel: RuleScoredLabelYes,
+		},
+		{
+			name:        "interbook portfolio mapped by company long name",
+			trade:       &nucleus.NucleusTradeHeaderModel{DealKey: 7, DealType: "PSWPS", Portfolio: "WEST POWER", PrtPortfolio: 10, LegalEntity: "SENA", IbPortfolio: "EUROPE POWER", IbPrtPortfolio: 14, Company: "SEEL", CompanyLongName: "SHELL ENERGY EUROPE LIMITED"},
+			scoredLabel: RuleScoredLabelYes,
+		},
+		{
+			name:        "interbook portfolio of another legal entity",
+			trade:       &nucleus.NucleusTradeHeaderModel{DealKey: 8, DealType: "PSWPS", Portfolio: "WEST POWER", PrtPortfolio: 10, LegalEntity: "SENA", IbPortfolio: "EAST POWER", IbPrtPortfolio: 13, Company: "SEEL", CompanyLongName: "Shell Energy Europe Limited"},
+			scoredLabel: RuleScoredLabelNo,
+			message:     "interbook portfolio EAST POWER (13) is mapped to legal entity SENA, not the internal counterparty SEEL",
+		},
+	}
+
+	rule := &PortfolioLegalEntityRule{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rule.Evaluate(tt.trade, referenceData)
+			if err != nil {
+				t.Fatalf("PortfolioLegalEntityRule.Evaluate() error = %v", err)
+			}
+
+			if got.GetModelName() != PortfolioLegalEntityRuleName || got.GetScoredLabel() != tt.scoredLabel || got.GetMessage() != tt.message {
+				t.Errorf("PortfolioLegalEntityRule.Evaluate() got = %s %s %q, want %s %q", got.GetModelName(), got.GetScoredLabel(), got.GetMessage(), tt.scoredLabel, tt.message)
+			}
+		})
+	}
+}
+
+func TestCreditLimitRule_Evaluate(t *testing.T) {
+	tradeDate := parseTime("01-06-2022")
+	referenceData := &NucleusReferenceData{
+		LarBases: []*common.LarBaseModel{
+			{ShortName: "OPSI", SourceSystem: "NUCLEUS", DealType: "POWER", LegalEntity: "Shell Energy North America (US), L.P.", BuyTenor: "24M", SellTenor: "1 Year",
+				NetPosition: 900, LimitValue: 1000, ExposureLimit: "HIGH", ExpirationDate: parseTime("30-09-2022")},
+			{ShortName: "ONT POWER", SourceSystem: "NUCLEUS", DealType: "CAPCTY", LegalEntity: "Shell Energy North America (US), L.P.", BuyTenor: "CALL CREDIT", SellTenor: "CALL CREDIT",
+				LimitAvailability: "Not Available", ExpirationDate: parseTime("31-05-2022")},
+			{ShortName: "BETA", SourceSystem: "NUCLEUS", Product: "Unmapped Product", DealType: "POWER", NetPosition: -900, LimitValue: 1000},
+			{ShortName: "BETA", SourceSystem: "NUCLEUS", Product: "Natural Gas", DealType: "POWER", LegalEntity: "Shell Energy Europe Limited", LimitValue: 1000, LimitAvailability: "N/A"},
+			{ShortName: "BETA", SourceSystem: "NUCLEUS", Product: "Peak", LegalEntity: "Shell Energy Europe Limited", LimitValue: 5000},
+			{ShortName: "GAMMA", SourceSystem: "NUCLEUS", Product: "Natural Gas", DealType: "GAS", LimitValue: 1000, LimitAvailability: "N/A"},
+			{ShortName: "GAMMA", SourceSystem: "ENDUR", DealType: "POWER", LegalEntity: "Shell Energy North America (US), L.P.", LimitValue: 1000, LimitAvailability: "N/A"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		trade       *nucleus.NucleusTradeHeaderModel
+		wantNil     bool
+		scoredLabel string
+		message     string
+	}{
+		{
+			name: "within the limit",
+			trade: &nucleus.NucleusTradeHeaderModel{DealKey: 1, DealType: "POWER", Company: "opsi", LegalEntity: "SENA", LegalEntityLongName: "Shell Energy North America (US), L.P.",
+				DnDirection: "PURCHASE", TransactionDate: tradeDate, EndDate: parseTime("31-12-2023"),
+				Terms: []*nucleus.NucleusTradeTermModel{{VolSeq: 1, Volume: -10, FixedPrice: 5}}},
+			scoredLabel: RuleScoredLabelYes,
+		},
+		{
+			name: "sale beyond the tenor reducing the net position",
+			trade: &nucleus.NucleusTradeHeaderModel{DealKey: 2, DealType: "POWER", Company: "OPSI", LegalEntity: "SENA", LegalEntityLongName: "Shell Energy North America (US), L.P.",
+				DnDirection: "SALE", TransactionDate: tradeDate, EndDate: parseTime("31-12-2022"),
+				Terms: []*nucleus.NucleusTradeTermModel{{VolSeq: 1, EndDate: parseTime("30-06-2023"), Volume: 20, FixedPrice: 10}}},
+			scoredLabel: RuleScoredLabelNo,
+			message:     "the trade ends on 2023-06-30, beyond the 1 Year tenor of counterparty OPSI",
+		},
+		{
+			name: "purchase over the exposure limit",
+			trade: &nucleus.NucleusTradeHeaderModel{DealKey: 6, DealType: "POWER", Company: "OPSI", LegalEntity: "SENA", LegalEntityLongName: "Shell Energy North America (US), L.P.",
+				DnDirection: "PURCHASE", TransactionDate: tradeDate, EndDate: parseTime("31-12-2022"),
+				Terms: []*nucleus.NucleusTradeTermModel{{VolSeq: 1, Volume: 20, FixedPrice: 10}}},
+			scoredLabel: RuleScoredLabelNo,
+			message:     "the trade pushes the net position of counterparty OPSI to 1100.00, over its exposure limit of 1000.00",
+		},
+		{
+			name: "limit of the product of the terms",
+			trade: &nucleus.NucleusTradeHeaderModel{DealKey: 7, DealType: "POWER", Company: "BETA", LegalEntity: "SEUK", LegalEntityLongName: "Shell Energy Europe Limited",
+				DnDirection: "PURCHASE", TransactionDate: tradeDate, EndDate: parseTime("31-12-2022"),
+				Terms: []*nucleus.NucleusTradeTermModel{{VolSeq: 1, Volume: 20, FixedPrice: 10, Product1: "PEAK"}}},
+			scoredLabel: RuleScoredLabelYes,
+		},
+		{
+			name: "limit without a legal entity and sale over the exposure limit",
+			trade: &nucleus.NucleusTradeHeaderModel{DealKey: 8, DealType: "POWER", Company: "BETA", LegalEntity: "SENA", LegalEntityLongName: "Shell Energy North America (US), L.P.",
+				DnDirection: "SALE", TransactionDate: tradeDate, EndDate: parseTime("31-12-2022"),
+				Terms: []*nucleus.NucleusTradeTermModel{{VolSeq: 1, Volume: 20, FixedPrice: 10, Product1: "PEAK"}}},
+			scoredLabel: RuleScoredLabelNo,
+			message:     "the trade pushes the net position of counterparty BETA to -1100.00, over its exposure limit of 1000.00",
+		},
+		{
+			name: "expired and not available",
+			trade: &nucleus.NucleusTradeHeaderModel{DealKey: 3, DealType: "CAPCTY", Company: "ONT", CompanyLongName: "ONT POWER", LegalEntityLongName: "Shell Energy North America (US), L.P.",
+				DnDirection: "PURCHASE", TransactionDate: tradeDate, EndDate: parseTime("31-12-2022")},
+			scoredLabel: RuleScoredLabelNo,
+			message:     "the credit limit of counterparty ONT POWER is not available; the credit limit of counterparty ONT POWER expired on 2022-05-31",
+		},
+		{
+			name:        "no limit for the legal entity",
+			trade:       &nucleus.NucleusTradeHeaderModel{DealKey: 4, DealType: "POWER", Company: "OPSI", LegalEntity: "SEUK", LegalEntityLongName: "Shell Energy Europe Limited", TransactionDate: tradeDate},
+			scoredLabel: RuleScoredLabelNo,
+			message:     "counterparty OPSI has no credit limit for legal entity SEUK",
+		},
+		{
+			name: "limits of unrelated products or other source systems",
+			trade: &nucleus.NucleusTradeHeaderModel{DealKey: 9, DealType: "POWER", Company: "GAMMA", LegalEntity: "SENA", LegalEntityLongName: "Shell Energy North America (US), L.P.",
+				DnDirection: "PURCHASE", TransactionDate: tradeDate, EndDate: parseTime("31-12-2022"),
+				Terms: []*nucleus.NucleusTradeTermModel{{VolSeq: 1, Volume: 20, FixedPrice: 10, Product1: "PEAK"}}},
+			scoredLabel: RuleScoredLabelNo,
+			message:     "counterparty GAMMA has no credit limit for legal entity SENA",
+		},
+		{
+			name:    "counterparty missing from LarBase",
+			trade:   &nucleus.NucleusTradeHeaderModel{DealKey: 5, DealType: "POWER", Company: "ACME", TransactionDate: tradeDate},
+			wantNil: true,
+		},
+	}
+
+	rule := &CreditLimitRule{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rule.Evaluate(tt.trade, referenceData)
+			if err != nil {
+				t.Fatalf("CreditLimitRule.Evaluate() error = %v", err)
+			}
+
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("CreditLimitRule.Evaluate() got = %+v, want nil", got)
+				}
+				return
+			}
+
+			if got.GetModelName() != CreditLimitRuleName || got.GetScoredLabel() != tt.scoredLabel || got.GetMessage() != tt.message {
+				t.Errorf("CreditLimitRule.Evaluate() got = %s %s %q, want %s %q", got.GetModelName(), got.GetScoredLabel(), got.GetMessage(), tt.scoredLabel, tt.message)
+			}
+		})
+	}
+}
+
+func TestNucleusTradeRepository_getNucDealTypeMethods(t *testing.T) {
+	repo := &NucleusTradeRepository{}
+
+	byKeysLoaders := repo.getNucDealByKeysLoaders()
+	streamers := repo.getNucDealListStreamers()
+	listLoaders := repo.getNucDealListLoaders()
+
+	for dealType, methods := range repo.getNucDealTypeMethods() {
+		if byKeysLoaders[dealType] == nil {
+			t.Errorf("deal type %s has no ByKeys loader", dealType)
+		}
+
+		listDealType := dealType
+		if methods.listedWith != "" {
+			listDealType = methods.listedWith
+			if _, ok := streamers[dealType]; ok {
+				t.Errorf("deal type %s is listed with %s but has its own streamer", dealType, methods.listedWith)
+			}
+		}
+		if streamers[listDealType] == nil || listLoaders[listDealType] == nil {
+			t.Errorf("deal type %s has no deal list", dealType)
+		}
+	}
+
+	if len(streamers) != len(listLoaders) {
+		t.Errorf("got %d streamers and %d deal list loaders", len(streamers), len(listLoaders))
+	}
+}
+
+func TestNucleusTradeRepository_acquireDealListStream(t *testing.T) {
+	nucleusDb, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer nucleusDb.Close()
+	nucleusDb.SetMaxOpenConns(4)
+
+	repo := &NucleusTradeRepository{nucleusDb: nucleusDb}
+
+	// every stream needs 2 connections, so 2 of the 4 stream at once
+	var releases []func()
+	for i := 0; i < 2; i++ {
+		release, err := repo.acquireDealListStream(context.TODO())
+		if err != nil {
+			t.Fatalf("acquireDealListStream() error = %v", err)
+		}
+		releases = append(releases, release)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := repo.acquireDealListStream(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("acquireDealListStream() over the limit error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	releases[0]()
+	if _, err := repo.acquireDealListStream(context.TODO()); err != nil {
+		t.Errorf("acquireDealListStream() after a release error = %v", err)
+	}
+}
+
+func Test_runInListChunks(t *testing.T) {
+	for _, concurrency := range []int{1, inListChunksConcurrency} {
+		var mutex sync.Mutex
+		running, maxRunning, ran := 0, 0, 0
+
+		err := runInListChunks(context.TODO(), 10, concurrency, func(ctx context.Context, index int) error {
+			mutex.Lock()
+			running++
+			ran++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mutex.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("runInListChunks() error = %v", err)
+		}
+
+		if ran != 10 || maxRunning > concurrency {
+			t.Errorf("runInListChunks() with concurrency %d ran %d chunks, %d at the same time", concurrency, ran, maxRunning)
+		}
+	}
+
+	wantErr := errors.New("chunk failed")
+	ran := 0
+	if err := runInListChunks(context.TODO(), 3, 1, func(ctx context.Context, index int) error {
+		ran++
+		return wantErr
+	}); err != wantErr || ran != 1 {
+		t.Errorf("runInListChunks() error = %v after %d chunks, want %v after 1", err, ran, wantErr)
+	}
+}
@@ -1,1167 +1,3468 @@
-package handlers
-
-import (
-	"encoding/json"
-	"net/http"
-	"strconv"
-	"time"
-
-	"github.com/gorilla/mux"
-
-	"github.com/sede-x/RogerRogerAnomalyDetector/handlers"
-	models "github.com/sede-x/RogerRogerAnomalyDetector/handlers/models"
-	"github.com/sede-x/RogerRogerAnomalyDetector/logger"
-	"github.com/sede-x/RogerRogerAnomalyDetector/models/common"
-	"github.com/sede-x/RogerRogerAnomalyDetector/models/nucleus"
-	"github.com/sede-x/RogerRogerAnomalyDetector/repository/nucleus/power"
-)
-
-
-const (
-	// NucleusTradeRepoErrorCode is the error code for
-	// fetching data from the database
-	NucleusTradeRepoErrorCode = 1000
-	// GetNucPowerDealListTradeDateRequiredErrorCode is the error code for
-	// when the date time is not present
-	GetNucPowerDealListTradeDateRequiredErrorCode = 1001
-	// GetNucPowerDealListDateTimeFormatErrorCode is the error code for
-	// when the specified date time is not in RFC3339 format
-	GetNucPowerDealListDateTimeFormatErrorCode = 1002
-	// GetNucPowerSwapDealListTradeDateRequiredErrorCode is the error code for
-	// when the date time is not present
-	GetNucPowerSwapDealListTradeDateRequiredErrorCode = 1003
-	// GetNucPowerSwapDealListDateTimeFormatErrorCode is the error code for
-	// when the specified date time is not in RFC3339 format
-	GetNucPowerSwapDealListDateTimeFormatErrorCode = 1004
-	// GetNucPowerOptionsDealListTradeDateRequiredErrorCode is the error code for
-	// when the date time is not present
-	GetNucPowerOptionsDealListTradeDateRequiredErrorCode = 1005
-	// GetNucPowerOptionsDealListDateTimeFormatErrorCode is the error code for
-	// when the specified date time is not in RFC3339 format
-	GetNucPowerOptionsDealListDateTimeFormatErrorCode = 1006
-	// GetNucCapacityDealListTradeDateRequiredErrorCode is the error code for
-	// when the date time is not present
-	GetNucCapacityDealListTradeDateRequiredErrorCode = 1007
-	// GetNucCapacityDealListDateTimeFormatErrorCode is the error code for
-	// when the specified date time is not in RFC3339 format
-	GetNucCapacityDealListDateTimeFormatErrorCode = 1008
-	// GetNucPTPDealListTradeDateRequiredErrorCode is the error code for
-	// when the date time is not present
-	GetNucPTPDealListTradeDateRequiredErrorCode = 1009
-	// GetNucPTPDealListDateTimeFormatErrorCode is the error code for
-	// when the specified date time is not in RFC3339 format
-	GetNucPTPDealListDateTimeFormatErrorCode = 1010
-	// GetNucEmissionDealListTradeDateRequiredErrorCode is the error code for
-	// when the date time is not present
-	GetNucEmissionDealListTradeDateRequiredErrorCode = 1011
-	// GetNucEmissionDealListDateTimeFormatErrorCode is the error code for
-	// when the specified date time is not in RFC3339 format
-	GetNucEmissionDealListDateTimeFormatErrorCode = 1012
-	// GetNucEmissionOptionDealListTradeDateRequiredErrorCode is the error code for
-	// when the date time is not present
-	GetNucEmissionOptionDealListTradeDateRequiredErrorCode = 1013
-	// GetNucEmissionOptionDealListDateTimeFormatErrorCode is the error code for
-	// when the specified date time is not in RFC3339 format
-	GetNucEmissionOptionDealListDateTimeFormatErrorCode = 1014
-	// GetNucSpreadOptionsDealListTradeDateRequiredErrorCode is the error code for
-	// when the date time is not present
-	GetNucSpreadOptionsDealListTradeDateRequiredErrorCode = 1015
-	// GetNucSpreadOptionsDealListDateTimeFormatErrorCode is the error code for
-	// when the specified date time is not in RFC3339 format
-	GetNucSpreadOptionsDealListDateTimeFormatErrorCode = 1016
-	// GetNucHeatRateSwapsDealListTradeDateRequiredErrorCode is the error code for
-	// when the date time is not present
-	GetNucHeatRateSwapsDealListTradeDateRequiredErrorCode = 1017
-	// GetNucHeatRateSwapsDealListDateTimeFormatErrorCode is the error code for
-	// when the specified date time is not in RFC3339 format
-	GetNucHeatRateSwapsDealListDateTimeFormatErrorCode = 1018
-	// GetNucTCCFTRSDealListTradeDateRequiredErrorCode is the error code for
-	// when the date time is not present
-	GetNucTCCFTRSDealListTradeDateRequiredErrorCode = 1019
-	// GetNucTCCFTRSDealListDateTimeFormatErrorCode is the error code for
-	// when the specified date time is not in RFC3339 format
-	GetNucTCCFTRSDealListDateTimeFormatErrorCode = 1020
-	// GetNucTCCFTRSDealListDealTypeFormatErrorCode is the error code for
-	// when the specified deal type is not valid
-	GetNucTCCFTRSDealListDealTypeFormatErrorCode = 1021
-	// GetNucTransmissionDealListTradeDateRequiredErrorCode is the error code for
-	// when the date time is not present
-	GetNucTransmissionDealListTradeDateRequiredErrorCode = 1022
-	// GetNucTransmissionDealListDateTimeFormatErrorCode is the error code for
-	// when the specified date time is not in RFC3339 format
-	GetNucTransmissionDealListDateTimeFormatErrorCode = 1023
-	// GetNucMiscChargeDealListTradeDateRequiredErrorCode is the error code for
-	// when the date time is not present
-	GetNucMiscChargeDealListTradeDateRequiredErrorCode = 1024
-	// GetNucMiscChargeDealListDateTimeFormatErrorCode is the error code for
-	// when the specified date time is not in RFC3339 format
-	GetNucMiscChargeDealListDateTimeFormatErrorCode = 1025
-	// GetNucPowerDealByKeysRequiredErrorCode is the error code for
-	// when the keys aren't present
-	GetNucPowerDealByKeysRequiredErrorCode = 1026
-	// GetNucPowerDealByKeysFormatErrorCode is the error code for
-	// when the keys aren't float64
-	GetNucPowerDealByKeysFormatErrorCode = 1027
-	// GetNucPowerSwapDealByKeysRequiredErrorCode is the error code for
-	// when the keys aren't present
-	GetNucPowerSwapDealByKeysRequiredErrorCode = 1028
-	// GetNucPowerSwapDealByKeysFormatErrorCode is the error code for
-	// when the keys aren't float64
-	GetNucPowerSwapDealByKeysFormatErrorCode = 1029
-	// GetNucPowerOptionsDealByKeysRequiredErrorCode is the error code for
-	// when the keys aren't present
-	GetNucPowerOptionsDealByKeysRequiredErrorCode = 1030
-	// GetNucPowerOptionsDealByKeysFormatErrorCode is the error code for
-	// when the keys aren't float64
-	GetNucPowerOptionsDealByKeysFormatErrorCode = 1031
-)
-
-func AddNucleusHandlers(router *mux.Router, middleware func(handler http.HandlerFunc) http.Handler, logger logger.Logger, repository power.INucleusTradeRepository) {
-	getNucPowerDealListHandler := http.HandlerFunc(makeGetNucPowerDealListHandler(logger, repository))
-	router.Handle("/nucleus/power/GetNucPowerDealList/{lastRunTime}/{tradeDate}", middleware(getNucPowerDealListHandler)).Methods("GET")
-
-	getNucPowerSwapDealListHandler := http.HandlerFunc(makeGetNucPowerSwapDealListHandler(logger, repository))
-	router.Handle("/nucleus/power/GetNucPowerSwapDealList/{lastRunTime}/{tradeDate}", middleware(getNucPowerSwapDealListHandler)).Methods("GET")
-
-	getNucPowerOptionsDealListHandler := http.HandlerFunc(makeGetNucPowerOptionsDealListHandler(logger, repository))
-	router.Handle("/nucleus/power/GetNucPowerOptionsDealList/{lastRunTime}/{tradeDate}", middleware(getNucPowerOptionsDealListHandler)).Methods("GET")
-
-	getNucCapacityDealListHandler := http.HandlerFunc(makeGetNucCapacityDealListHandler(logger, repository))
-	router.Handle("/nucleus/power/GetNucCapacityDealList/{lastRunTime}/{tradeDate}", middleware(getNucCapacityDealListHandler)).Methods("GET")
-
-	getNucPTPDealListHandler := http.HandlerFunc(makeGetNucPTPDealListHandler(logger, repository))
-	router.Handle("/nucleus/power/GetNucPTPDealList/{lastRunTime}/{tradeDate}", middleware(getNucPTPDealListHandler)).Methods("GET")
-
-	getNucEmissionDealListHandler := http.HandlerFunc(makeGetNucEmissionDealListHandler(logger, repository))
-	router.Handle("/nucleus/power/GetNucEmissionDealList/{lastRunTime}/{tradeDate}", middleware(getNucEmissionDealListHandler)).Methods("GET")
-
-	getNucEmissionOptionDealListHandler := http.HandlerFunc(makeGetNucEmissionOptionDealListHandler(logger, repository))
-	router.Handle("/nucleus/power/GetNucEmissionOptionDealList/{lastRunTime}/{tradeDate}", middleware(getNucEmissionOptionDealListHandler)).Methods("GET")
-
-	getNucSpreadOptionsDealListHandler := http.HandlerFunc(makeGetNucSpreadOptionsDealListHandler(logger, repository))
-	router.Handle("/nucleus/power/GetNucSpreadOptionsDealList/{lastRunTime}/{tradeDate}", middleware(getNucSpreadOptionsDealListHandler)).Methods("GET")
-
-	getNucHeatRateSwapsDealListHandler := http.HandlerFunc(makeGetNucHeatRateSwapsDealListHandler(logger, repository))
-	router.Handle("/nucleus/power/GetNucHeatRateSwapsDealList/{lastRunTime}/{tradeDate}", middleware(getNucHeatRateSwapsDealListHandler)).Methods("GET")
-
-	// dealType: FTROPT, FTRSWP, TCCSWP
-	getNucTCCFTRSDealListHandler := http.HandlerFunc(makeGetNucTCCFTRSDealListHandler(logger, repository))
-	router.Handle("/nucleus/power/GetNucTCCFTRSDealList/{lastRunTime}/{tradeDate}/{dealType}", middleware(getNucTCCFTRSDealListHandler)).Methods("GET")
-
-	getNucTransmissionDealListHandler := http.HandlerFunc(makeGetNucTransmissionDealListHandler(logger, repository))
-	router.Handle("/nucleus/power/GetNucTransmissionDealList/{lastRunTime}/{tradeDate}", middleware(getNucTransmissionDealListHandler)).Methods("GET")
-
-	getNucMiscChargeDealListHandler := http.HandlerFunc(makeGetNucMiscChargeDealListHandler(logger, repository))
-	router.Handle("/nucleus/power/GetNucMiscChargeDealList/{lastRunTime}/{tradeDate}", middleware(getNucMiscChargeDealListHandler)).Methods("GET")
-
-	processTradesHandler := http.HandlerFunc(makeProcessTradesHandlerNucleus(logger, repository))
-	router.Handle("/nucleus/power/ProcessTrades", middleware(processTradesHandler)).Methods("POST")
-
-	getLastExtractionRunHandler := http.HandlerFunc(makeGetLastExtractionRunHandlerNucleus(logger, repository))
-	router.Handle("/nucleus/power/GetLastExtractionRun/{tradeDate}/{dealType}", middleware(getLastExtractionRunHandler)).Methods("GET")
-
-	insertExtractionRunHandler := http.HandlerFunc(makeInsertExtractionRunHandlerNucleus(logger, repository))
-	router.Handle("/nucleus/power/InsertExtractionRun", middleware(insertExtractionRunHandler)).Methods("POST")
-
-	getPortfolioRiskMappingListHandler := http.HandlerFunc(makeGetPortfolioRiskMappingListHandler(logger, repository))
-	router.Handle("/nucleus/power/GetPortfolioRiskMappingList", middleware(getPortfolioRiskMappingListHandler)).Methods("GET")
-
-	getLarBaselistHandler := http.HandlerFunc(makeGetLarBaselistHandlerNucleus(logger, repository))
-	router.Handle("/nucleus/power/GetLarBaselist", middleware(getLarBaselistHandler)).Methods("GET")
-
-	getNucPowerDealByKeysHandler := http.HandlerFunc(makeGetNucPowerDealByKeysHandler(logger, repository))
-	router.Handle("/nucleus/power/GetNucPowerDealByKeys", middleware(getNucPowerDealByKeysHandler)).Methods("GET")
-
-	getNucPowerSwapDealByKeysHandler := http.HandlerFunc(makeGetNucPowerSwapDealByKeysHandler(logger, repository))
-	router.Handle("/nucleus/power/GetNucPowerSwapDealByKeys", middleware(getNucPowerSwapDealByKeysHandler)).Methods("GET")
-
-	getNucPowerOptionsDealByKeysHandler := http.HandlerFunc(makeGetNucPowerOptionsDealByKeysHandler(logger, repository))
-	router.Handle("/nucleus/power/GetNucPowerOptionsDealByKeys", middleware(getNucPowerOptionsDealByKeysHandler)).Methods("GET")
-}
-
-func makeGetNucPowerDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
-	gLogger := logger.GetLogger()
-	return func(w http.ResponseWriter, r *http.Request) {
-		params := mux.Vars(r)
-
-		lastRunTimeParam, ok := params["lastRunTime"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
-				models.NewServerError(GetNucPowerDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
-				models.NewServerError(GetNucPowerDealListDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		tradeDateParam, ok := params["tradeDate"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
-				models.NewServerError(GetNucPowerDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
-				models.NewServerError(GetNucPowerDealListDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		results, err := repository.GetNucPowerDealList(r.Context(), lastRunTime, tradeDate)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendInternalServerError(w, r, "unable to get Nucleus Power Deal List",
-				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		if err := handlers.SendOk(w, results); err != nil {
-			gLogger.Errorln(err)
-		}
-	}
-}
-
-func makeGetNucPowerSwapDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
-	gLogger := logger.GetLogger()
-	return func(w http.ResponseWriter, r *http.Request) {
-		params := mux.Vars(r)
-
-		lastRunTimeParam, ok := params["lastRunTime"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
-				models.NewServerError(GetNucPowerSwapDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
-				models.NewServerError(GetNucPowerSwapDealListDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		tradeDateParam, ok := params["tradeDate"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
-				models.NewServerError(GetNucPowerSwapDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
-				models.NewServerError(GetNucPowerSwapDealListDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		results, err := repository.GetNucPowerSwapDealList(r.Context(), lastRunTime, tradeDate)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendInternalServerError(w, r, "unable to get Nucleus Power Swap Deal List",
-				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		if err := handlers.SendOk(w, results); err != nil {
-			gLogger.Errorln(err)
-		}
-	}
-}
-
-func makeGetNucPowerOptionsDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
-	gLogger := logger.GetLogger()
-	return func(w http.ResponseWriter, r *http.Request) {
-		params := mux.Vars(r)
-
-		lastRunTimeParam, ok := params["lastRunTime"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
-				models.NewServerError(GetNucPowerOptionsDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
-				models.NewServerError(GetNucPowerOptionsDealListDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		tradeDateParam, ok := params["tradeDate"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
-				models.NewServerError(GetNucPowerOptionsDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
-				models.NewServerError(GetNucPowerOptionsDealListDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		results, err := repository.GetNucPowerOptionsDealList(r.Context(), lastRunTime, tradeDate)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendInternalServerError(w, r, "unable to Get Nucleus Power Options Deal List",
-				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		if err := handlers.SendOk(w, results); err != nil {
-			gLogger.Errorln(err)
-		}
-	}
-}
-
-func makeGetNucCapacityDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
-	gLogger := logger.GetLogger()
-	return func(w http.ResponseWriter, r *http.Request) {
-		params := mux.Vars(r)
-
-		lastRunTimeParam, ok := params["lastRunTime"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
-				models.NewServerError(GetNucCapacityDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
-				models.NewServerError(GetNucCapacityDealListDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		tradeDateParam, ok := params["tradeDate"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
-				models.NewServerError(GetNucCapacityDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
-				models.NewServerError(GetNucCapacityDealListDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		results, err := repository.GetNucCapacityDealList(r.Context(), lastRunTime, tradeDate)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendInternalServerError(w, r, "unable to get Nucleus Capacity Deal List",
-				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		if err := handlers.SendOk(w, results); err != nil {
-			gLogger.Errorln(err)
-		}
-	}
-}
-
-func makeGetNucPTPDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
-	gLogger := logger.GetLogger()
-	return func(w http.ResponseWriter, r *http.Request) {
-		params := mux.Vars(r)
-
-		lastRunTimeParam, ok := params["lastRunTime"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
-				models.NewServerError(GetNucPTPDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
-				models.NewServerError(GetNucPTPDealListDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		tradeDateParam, ok := params["tradeDate"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
-				models.NewServerError(GetNucPTPDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
-				models.NewServerError(GetNucPTPDealListDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		results, err := repository.GetNucPTPDealList(r.Context(), lastRunTime, tradeDate)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendInternalServerError(w, r, "unable to Get Nucleus PTP Deal List",
-				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		if err := handlers.SendOk(w, results); err != nil {
-			gLogger.Errorln(err)
-		}
-	}
-}
-
-func makeGetNucEmissionDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
-	gLogger := logger.GetLogger()
-	return func(w http.ResponseWriter, r *http.Request) {
-		params := mux.Vars(r)
-
-		lastRunTimeParam, ok := params["lastRunTime"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
-				models.NewServerError(GetNucEmissionDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
-				models.NewServerError(GetNucEmissionDealListDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		tradeDateParam, ok := params["tradeDate"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
-				models.NewServerError(GetNucEmissionDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
-				models.NewServerError(GetNucEmissionDealListDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		results, err := repository.GetNucEmissionDealList(r.Context(), lastRunTime, tradeDate)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendInternalServerError(w, r, "unable to Get Nucleus Emission Deal List",
-				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		if err := handlers.SendOk(w, results); err != nil {
-			gLogger.Errorln(err)
-		}
-	}
-}
-
-func makeGetNucEmissionOptionDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
-	gLogger := logger.GetLogger()
-	return func(w http.ResponseWriter, r *http.Request) {
-		params := mux.Vars(r)
-
-		lastRunTimeParam, ok := params["lastRunTime"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
-				models.NewServerError(GetNucEmissionOptionDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
-				models.NewServerError(GetNucEmissionOptionDealListDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		tradeDateParam, ok := params["tradeDate"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
-				models.NewServerError(GetNucEmissionOptionDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
-				models.NewServerError(GetNucEmissionOptionDealListDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		results, err := repository.GetNucEmissionOptionDealList(r.Context(), lastRunTime, tradeDate)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendInternalServerError(w, r, "unable to Get Nucleus Emission Option Deal List",
-				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		if err := handlers.SendOk(w, results); err != nil {
-			gLogger.Errorln(err)
-		}
-	}
-}
-
-func makeGetNucSpreadOptionsDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
-	gLogger := logger.GetLogger()
-	return func(w http.ResponseWriter, r *http.Request) {
-		params := mux.Vars(r)
-
-		lastRunTimeParam, ok := params["lastRunTime"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
-				models.NewServerError(GetNucSpreadOptionsDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
-				models.NewServerError(GetNucSpreadOptionsDealListDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		tradeDateParam, ok := params["tradeDate"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
-				models.NewServerError(GetNucSpreadOptionsDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
-				models.NewServerError(GetNucSpreadOptionsDealListDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		results, err := repository.GetNucSpreadOptionsDealList(r.Context(), lastRunTime, tradeDate)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendInternalServerError(w, r, "unable to Get Nucleus Spread Options DealList",
-				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		if err := handlers.SendOk(w, results); err != nil {
-			gLogger.Errorln(err)
-		}
-	}
-}
-
-func makeGetNucHeatRateSwapsDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
-	gLogger := logger.GetLogger()
-	return func(w http.ResponseWriter, r *http.Request) {
-		params := mux.Vars(r)
-
-		lastRunTimeParam, ok := params["lastRunTime"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
-				models.NewServerError(GetNucHeatRateSwapsDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
-				models.NewServerError(GetNucHeatRateSwapsDealListDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		tradeDateParam, ok := params["tradeDate"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
-				models.NewServerError(GetNucHeatRateSwapsDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
-				models.NewServerError(GetNucHeatRateSwapsDealListDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		results, err := repository.GetNucHeatRateSwapsDealList(r.Context(), lastRunTime, tradeDate)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendInternalServerError(w, r, "unable to Get Nucleus Heat Rate Swaps DealList",
-				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		if err := handlers.SendOk(w, results); err != nil {
-			gLogger.Errorln(err)
-		}
-	}
-}
-
-func makeGetNucTCCFTRSDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
-	gLogger := logger.GetLogger()
-	return func(w http.ResponseWriter, r *http.Request) {
-		params := mux.Vars(r)
-
-		lastRunTimeParam, ok := params["lastRunTime"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
-				models.NewServerError(GetNucTCCFTRSDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
-				models.NewServerError(GetNucTCCFTRSDealListDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		tradeDateParam, ok := params["tradeDate"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
-				models.NewServerError(GetNucTCCFTRSDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
-				models.NewServerError(GetNucTCCFTRSDealListDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		dealType, ok := params["dealType"]
-		if !ok || dealType == "" {
-			if err := handlers.SendBadRequest(w, r, "dealType is required",
-				models.NewServerError(GetNucTCCFTRSDealListDealTypeFormatErrorCode, "dealType is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		results, err := repository.GetNucTCCFTRSDealList(r.Context(), lastRunTime, tradeDate, dealType)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendInternalServerError(w, r, "unable to Get Nucleus TCCFTRS DealList",
-				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		if err := handlers.SendOk(w, results); err != nil {
-			gLogger.Errorln(err)
-		}
-	}
-}
-
-func makeGetNucTransmissionDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
-	gLogger := logger.GetLogger()
-	return func(w http.ResponseWriter, r *http.Request) {
-		params := mux.Vars(r)
-
-		lastRunTimeParam, ok := params["lastRunTime"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
-				models.NewServerError(GetNucTransmissionDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
-				models.NewServerError(GetNucTransmissionDealListDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		tradeDateParam, ok := params["tradeDate"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
-				models.NewServerError(GetNucTransmissionDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
-				models.NewServerError(GetNucTransmissionDealListDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		results, err := repository.GetNucTransmissionDealList(r.Context(), lastRunTime, tradeDate)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendInternalServerError(w, r, "unable to Get Nucleus Heat Rate Swaps DealList",
-				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		if err := handlers.SendOk(w, results); err != nil {
-			gLogger.Errorln(err)
-		}
-	}
-}
-
-func makeGetNucMiscChargeDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
-	gLogger := logger.GetLogger()
-	return func(w http.ResponseWriter, r *http.Request) {
-		params := mux.Vars(r)
-
-		lastRunTimeParam, ok := params["lastRunTime"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
-				models.NewServerError(GetNucMiscChargeDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
-				models.NewServerError(GetNucMiscChargeDealListDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		tradeDateParam, ok := params["tradeDate"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
-				models.NewServerError(GetNucMiscChargeDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
-				models.NewServerError(GetNucMiscChargeDealListDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		results, err := repository.GetNucMiscChargeDealList(r.Context(), lastRunTime, tradeDate)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendInternalServerError(w, r, "unable to Get Nucleus Heat Rate Swaps DealList",
-				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		if err := handlers.SendOk(w, results); err != nil {
-			gLogger.Errorln(err)
-		}
-	}
-}
-
-type makeProcessTradesHandlerNucleusBody struct {
-	Trades          []*nucleus.NucleusTradeHeaderModel `json:"trades"`
-	AnomalyMessages map[int][]processedTradeExample    `json:"anomalyMessages"`
-}
-
-func makeProcessTradesHandlerNucleus(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
-	gLogger := logger.GetLogger()
-	return func(w http.ResponseWriter, r *http.Request) {
-		var data makeProcessTradesHandlerNucleusBody
-		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse body",
-				models.NewServerError(ProcessTradesBodyFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		anomalyMessages := make(map[int][]common.IModelBasePayload)
-
-		for index := range data.AnomalyMessages {
-			for _, value := range data.AnomalyMessages[index] {
-				// we need to convert the coming value from ModelBasePayload to
-				// ResultModelBasePayload, that way we can read all the properties
-				// from the incoming json and we can ingnore the necessary values
-				// when we save the data to the database
-				newValue := processedTradeExampleResult{
-					Company:  value.Company,
-					Trader:   value.Trader,
-					Source:   value.Source,
-					Location: value.Location,
-					Broker:   value.Broker,
-					ResultModelBasePayload: &common.ResultModelBasePayload{
-						ModelName:   value.ModelName,
-						Message:     value.Message,
-						DealKey:     value.DealKey,
-						DealType:    value.DealType,
-						ScoredLabel: value.ScoredLabel,
-					},
-				}
-
-				anomalyMessages[index] = append(anomalyMessages[index], newValue)
-			}
-		}
-
-		if err := repository.ProcessTrades(r.Context(), data.Trades, anomalyMessages); err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendInternalServerError(w, r, "unable to Process Trades",
-				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		if err := handlers.SendOk(w, nil); err != nil {
-			gLogger.Errorln(err)
-		}
-	}
-}
-
-func makeGetLastExtractionRunHandlerNucleus(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
-	gLogger := logger.GetLogger()
-	return func(w http.ResponseWriter, r *http.Request) {
-		params := mux.Vars(r)
-
-		tradeDateParam, ok := params["tradeDate"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
-				models.NewServerError(GetLastExtractionRunDateRequiredErrorCode, "tradeDate is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
-				models.NewServerError(GetLastExtractionRunDateTimeFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		dealTypeParam, ok := params["dealType"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "dealType is required",
-				models.NewServerError(GetLastExtractionRunDateRequiredErrorCode, "dealType is required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		results, err := repository.GetLastExtractionRun(r.Context(), tradeDate, dealTypeParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendInternalServerError(w, r, "unable to Get Last Extraction Run",
-				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		if err := handlers.SendOk(w, results); err != nil {
-			gLogger.Errorln(err)
-		}
-	}
-}
-
-type insertExtractionRunHandlerNucleusBody struct {
-	TradeDate time.Time `json:"tradeDate"`
-	DealType  string    `json:"dealType"`
-	LastRun   time.Time `json:"lastRun"`
-}
-
-func makeInsertExtractionRunHandlerNucleus(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
-	gLogger := logger.GetLogger()
-	return func(w http.ResponseWriter, r *http.Request) {
-		var data insertExtractionRunHandlerNucleusBody
-		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendBadRequest(w, r, "failed to parse body",
-				models.NewServerError(InsertExtractionRunBodyFormatErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-		if err := repository.InsertExtractionRun(r.Context(), data.TradeDate, data.LastRun, data.DealType); err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendInternalServerError(w, r, "unable to Insert Extraction Run",
-				models.NewServerError(EndureGasTradeRepoErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		if err := handlers.SendOk(w, nil); err != nil {
-			gLogger.Errorln(err)
-		}
-	}
-}
-
-func makeGetPortfolioRiskMappingListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
-	gLogger := logger.GetLogger()
-	return func(w http.ResponseWriter, r *http.Request) {
-		results, err := repository.GetPortfolioRiskMappingList(r.Context())
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendInternalServerError(w, r, "unable to Get Portfolio Risk Mapping List",
-				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		if err := handlers.SendOk(w, results); err != nil {
-			gLogger.Errorln(err)
-		}
-	}
-}
-
-func makeGetLarBaselistHandlerNucleus(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
-	gLogger := logger.GetLogger()
-	return func(w http.ResponseWriter, r *http.Request) {
-		results, err := repository.GetLarBaselist(r.Context())
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendInternalServerError(w, r, "unable to Get Lar Baselist",
-				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		if err := handlers.SendOk(w, results); err != nil {
-			gLogger.Errorln(err)
-		}
-	}
-}
-
-func makeGetNucPowerDealByKeysHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
-	gLogger := logger.GetLogger()
-	return func(w http.ResponseWriter, r *http.Request) {
-		query := r.URL.Query()
-
-		keysParamQuery, ok := query["keys"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "keys are required",
-				models.NewServerError(GetNucPowerDealByKeysRequiredErrorCode, "Keys are required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		var keysParam []float64
-		for _, key := range keysParamQuery {
-			keyFloat64, err := strconv.ParseFloat(key, 64)
-			if err != nil {
-				if err := handlers.SendBadRequest(w, r, "keys must be float64",
-					models.NewServerError(GetNucPowerDealByKeysFormatErrorCode, "keys must be float64")); err != nil {
-					gLogger.Errorln(err)
-				}
-			}
-			keysParam = append(keysParam, keyFloat64)
-		}
-
-		results, err := repository.GetNucPowerDealByKeys(r.Context(), keysParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendInternalServerError(w, r, "unable to Get Nuc Power Deal By Keys",
-				models.NewServerError(EndureGasTradeRepoErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		if err := handlers.SendOk(w, results); err != nil {
-			gLogger.Errorln(err)
-		}
-	}
-}
-
-func makeGetNucPowerSwapDealByKeysHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
-	gLogger := logger.GetLogger()
-	return func(w http.ResponseWriter, r *http.Request) {
-		query := r.URL.Query()
-
-		keysParamQuery, ok := query["keys"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "keys are required",
-				models.NewServerError(GetNucPowerSwapDealByKeysRequiredErrorCode, "Keys are required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		var keysParam []float64
-		for _, key := range keysParamQuery {
-			keyFloat64, err := strconv.ParseFloat(key, 64)
-			if err != nil {
-				if err := handlers.SendBadRequest(w, r, "keys must be float64",
-					models.NewServerError(GetNucPowerSwapDealByKeysFormatErrorCode, "keys must be float64")); err != nil {
-					gLogger.Errorln(err)
-				}
-			}
-			keysParam = append(keysParam, keyFloat64)
-		}
-
-		results, err := repository.GetNucPowerSwapDealByKeys(r.Context(), keysParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendInternalServerError(w, r, "unable to Get Nuc Power Swap Deal By Keys",
-				models.NewServerError(EndureGasTradeRepoErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		if err := handlers.SendOk(w, results); err != nil {
-			gLogger.Errorln(err)
-		}
-	}
-}
-
-func makeGetNucPowerOptionsDealByKeysHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
-	gLogger := logger.GetLogger()
-	return func(w http.ResponseWriter, r *http.Request) {
-		query := r.URL.Query()
-
-		keysParamQuery, ok := query["keys"]
-		if !ok {
-			if err := handlers.SendBadRequest(w, r, "keys are required",
-				models.NewServerError(GetNucPowerOptionsDealByKeysRequiredErrorCode, "Keys are required")); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		var keysParam []float64
-		for _, key := range keysParamQuery {
-			keyFloat64, err := strconv.ParseFloat(key, 64)
-			if err != nil {
-				if err := handlers.SendBadRequest(w, r, "keys must be float64",
-					models.NewServerError(GetNucPowerOptionsDealByKeysFormatErrorCode, "keys must be float64")); err != nil {
-					gLogger.Errorln(err)
-				}
-			}
-			keysParam = append(keysParam, keyFloat64)
-		}
-
-		results, err := repository.GetNucPowerOptionsDealByKeys(r.Context(), keysParam)
-		if err != nil {
-			gLogger.Errorln(err)
-			if err := handlers.SendInternalServerError(w, r, "unable to Get Nuc Power Options Deal By Keys",
-				models.NewServerError(EndureGasTradeRepoErrorCode, err.Error())); err != nil {
-				gLogger.Errorln(err)
-			}
-			return
-		}
-
-		if err := handlers.SendOk(w, results); err != nil {
-			gLogger.Errorln(err)
-		}
-	}
-}
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sede-x/RogerRogerAnomalyDetector/handlers"
+	models "github.com/sede-x/RogerRogerAnomalyDetector/handlers/models"
+	"github.com/sede-x/RogerRogerAnomalyDetector/logger"
+	"github.com/sede-x/RogerRogerAnomalyDetector/models/common"
+	"github.com/sede-x/RogerRogerAnomalyDetector/models/nucleus"
+	"github.com/sede-x/RogerRogerAnomalyDetector/repository/nucleus/power"
+)
+
+
+const (
+	// NucleusTradeRepoErrorCode is the error code for
+	// fetching data from the database
+	NucleusTradeRepoErrorCode = 1000
+	// GetNucPowerDealListTradeDateRequiredErrorCode is the error code for
+	// when the date time is not present
+	GetNucPowerDealListTradeDateRequiredErrorCode = 1001
+	// GetNucPowerDealListDateTimeFormatErrorCode is the error code for
+	// when the specified date time is not in RFC3339 format
+	GetNucPowerDealListDateTimeFormatErrorCode = 1002
+	// GetNucPowerSwapDealListTradeDateRequiredErrorCode is the error code for
+	// when the date time is not present
+	GetNucPowerSwapDealListTradeDateRequiredErrorCode = 1003
+	// GetNucPowerSwapDealListDateTimeFormatErrorCode is the error code for
+	// when the specified date time is not in RFC3339 format
+	GetNucPowerSwapDealListDateTimeFormatErrorCode = 1004
+	// GetNucPowerOptionsDealListTradeDateRequiredErrorCode is the error code for
+	// when the date time is not present
+	GetNucPowerOptionsDealListTradeDateRequiredErrorCode = 1005
+	// GetNucPowerOptionsDealListDateTimeFormatErrorCode is the error code for
+	// when the specified date time is not in RFC3339 format
+	GetNucPowerOptionsDealListDateTimeFormatErrorCode = 1006
+	// GetNucCapacityDealListTradeDateRequiredErrorCode is the error code for
+	// when the date time is not present
+	GetNucCapacityDealListTradeDateRequiredErrorCode = 1007
+	// GetNucCapacityDealListDateTimeFormatErrorCode is the error code for
+	// when the specified date time is not in RFC3339 format
+	GetNucCapacityDealListDateTimeFormatErrorCode = 1008
+	// GetNucPTPDealListTradeDateRequiredErrorCode is the error code for
+	// when the date time is not present
+	GetNucPTPDealListTradeDateRequiredErrorCode = 1009
+	// GetNucPTPDealListDateTimeFormatErrorCode is the error code for
+	// when the specified date time is not in RFC3339 format
+	GetNucPTPDealListDateTimeFormatErrorCode = 1010
+	// GetNucEmissionDealListTradeDateRequiredErrorCode is the error code for
+	// when the date time is not present
+	GetNucEmissionDealListTradeDateRequiredErrorCode = 1011
+	// GetNucEmissionDealListDateTimeFormatErrorCode is the error code for
+	// when the specified date time is not in RFC3339 format
+	GetNucEmissionDealListDateTimeFormatErrorCode = 1012
+	// GetNucEmissionOptionDealListTradeDateRequiredErrorCode is the error code for
+	// when the date time is not present
+	GetNucEmissionOptionDealListTradeDateRequiredErrorCode = 1013
+	// GetNucEmissionOptionDealListDateTimeFormatErrorCode is the error code for
+	// when the specified date time is not in RFC3339 format
+	GetNucEmissionOptionDealListDateTimeFormatErrorCode = 1014
+	// GetNucSpreadOptionsDealListTradeDateRequiredErrorCode is the error code for
+	// when the date time is not present
+	GetNucSpreadOptionsDealListTradeDateRequiredErrorCode = 1015
+	// GetNucSpreadOptionsDealListDateTimeFormatErrorCode is the error code for
+	// when the specified date time is not in RFC3339 format
+	GetNucSpreadOptionsDealListDateTimeFormatErrorCode = 1016
+	// GetNucHeatRateSwapsDealListTradeDateRequiredErrorCode is the error code for
+	// when the date time is not present
+	GetNucHeatRateSwapsDealListTradeDateRequiredErrorCode = 1017
+	// GetNucHeatRateSwapsDealListDateTimeFormatErrorCode is the error code for
+	// when the specified date time is not in RFC3339 format
+	GetNucHeatRateSwapsDealListDateTimeFormatErrorCode = 1018
+	// GetNucTCCFTRSDealListTradeDateRequiredErrorCode is the error code for
+	// when the date time is not present
+	GetNucTCCFTRSDealListTradeDateRequiredErrorCode = 1019
+	// GetNucTCCFTRSDealListDateTimeFormatErrorCode is the error code for
+	// when the specified date time is not in RFC3339 format
+	GetNucTCCFTRSDealListDateTimeFormatErrorCode = 1020
+	// GetNucTCCFTRSDealListDealTypeFormatErrorCode is the error code for
+	// when the specified deal type is not valid
+	GetNucTCCFTRSDealListDealTypeFormatErrorCode = 1021
+	// GetNucTransmissionDealListTradeDateRequiredErrorCode is the error code for
+	// when the date time is not present
+	GetNucTransmissionDealListTradeDateRequiredErrorCode = 1022
+	// GetNucTransmissionDealListDateTimeFormatErrorCode is the error code for
+	// when the specified date time is not in RFC3339 format
+	GetNucTransmissionDealListDateTimeFormatErrorCode = 1023
+	// GetNucMiscChargeDealListTradeDateRequiredErrorCode is the error code for
+	// when the date time is not present
+	GetNucMiscChargeDealListTradeDateRequiredErrorCode = 1024
+	// GetNucMiscChargeDealListDateTimeFormatErrorCode is the error code for
+	// when the specified date time is not in RFC3339 format
+	GetNucMiscChargeDealListDateTimeFormatErrorCode = 1025
+	// GetNucPowerDealByKeysRequiredErrorCode is the error code for
+	// when the keys aren't present
+	GetNucPowerDealByKeysRequiredErrorCode = 1026
+	// GetNucPowerDealByKeysFormatErrorCode is the error code for
+	// when the keys aren't float64
+	GetNucPowerDealByKeysFormatErrorCode = 1027
+	// GetNucPowerSwapDealByKeysRequiredErrorCode is the error code for
+	// when the keys aren't present
+	GetNucPowerSwapDealByKeysRequiredErrorCode = 1028
+	// GetNucPowerSwapDealByKeysFormatErrorCode is the error code for
+	// when the keys aren't float64
+	GetNucPowerSwapDealByKeysFormatErrorCode = 1029
+	// GetNucPowerOptionsDealByKeysRequiredErrorCode is the error code for
+	// when the keys aren't present
+	GetNucPowerOptionsDealByKeysRequiredErrorCode = 1030
+	// GetNucPowerOptionsDealByKeysFormatErrorCode is the error code for
+	// when the keys aren't float64
+	GetNucPowerOptionsDealByKeysFormatErrorCode = 1031
+	// GetNucCapacityDealByKeysRequiredErrorCode is the error code for
+	// when the keys aren't present
+	GetNucCapacityDealByKeysRequiredErrorCode = 1032
+	// GetNucCapacityDealByKeysFormatErrorCode is the error code for
+	// when the keys aren't float64
+	GetNucCapacityDealByKeysFormatErrorCode = 1033
+	// GetNucEmissionDealByKeysRequiredErrorCode is the error code for
+	// when the keys aren't present
+	GetNucEmissionDealByKeysRequiredErrorCode = 1034
+	// GetNucEmissionDealByKeysFormatErrorCode is the error code for
+	// when the keys aren't float64
+	GetNucEmissionDealByKeysFormatErrorCode = 1035
+	// GetNucEmissionOptionDealByKeysRequiredErrorCode is the error code for
+	// when the keys aren't present
+	GetNucEmissionOptionDealByKeysRequiredErrorCode = 1036
+	// GetNucEmissionOptionDealByKeysFormatErrorCode is the error code for
+	// when the keys aren't float64
+	GetNucEmissionOptionDealByKeysFormatErrorCode = 1037
+	// GetNucTCCFTRSDealByKeysRequiredErrorCode is the error code for
+	// when the keys aren't present
+	GetNucTCCFTRSDealByKeysRequiredErrorCode = 1038
+	// GetNucTCCFTRSDealByKeysFormatErrorCode is the error code for
+	// when the keys aren't float64
+	GetNucTCCFTRSDealByKeysFormatErrorCode = 1039
+	// GetNucTCCFTRSDealByKeysDealTypeFormatErrorCode is the error code for
+	// when the dealType isn't one of FTROPT, FTRSWP or TCCSWP
+	GetNucTCCFTRSDealByKeysDealTypeFormatErrorCode = 1040
+	// GetNucSpreadOptionsDealByKeysRequiredErrorCode is the error code for
+	// when the keys aren't present
+	GetNucSpreadOptionsDealByKeysRequiredErrorCode = 1041
+	// GetNucSpreadOptionsDealByKeysFormatErrorCode is the error code for
+	// when the keys aren't float64
+	GetNucSpreadOptionsDealByKeysFormatErrorCode = 1042
+	// GetNucHeatRateSwapsDealByKeysRequiredErrorCode is the error code for
+	// when the keys aren't present
+	GetNucHeatRateSwapsDealByKeysRequiredErrorCode = 1043
+	// GetNucHeatRateSwapsDealByKeysFormatErrorCode is the error code for
+	// when the keys aren't float64
+	GetNucHeatRateSwapsDealByKeysFormatErrorCode = 1044
+	// GetNucTransmissionDealByKeysRequiredErrorCode is the error code for
+	// when the keys aren't present
+	GetNucTransmissionDealByKeysRequiredErrorCode = 1045
+	// GetNucTransmissionDealByKeysFormatErrorCode is the error code for
+	// when the keys aren't float64
+	GetNucTransmissionDealByKeysFormatErrorCode = 1046
+	// GetNucPTPDealByKeysRequiredErrorCode is the error code for
+	// when the keys aren't present
+	GetNucPTPDealByKeysRequiredErrorCode = 1047
+	// GetNucPTPDealByKeysFormatErrorCode is the error code for
+	// when the keys aren't float64
+	GetNucPTPDealByKeysFormatErrorCode = 1048
+	// GetNucMiscChargeDealByKeysRequiredErrorCode is the error code for
+	// when the keys aren't present
+	GetNucMiscChargeDealByKeysRequiredErrorCode = 1049
+	// GetNucMiscChargeDealByKeysFormatErrorCode is the error code for
+	// when the keys aren't float64
+	GetNucMiscChargeDealByKeysFormatErrorCode = 1050
+	// GetNucDealsByTypeAndKeysBodyFormatErrorCode is the error code for
+	// when the lookup body can't be parsed
+	GetNucDealsByTypeAndKeysBodyFormatErrorCode = 1051
+	// GetNucDealsByTypeAndKeysRequiredErrorCode is the error code for
+	// when the lookup body doesn't contain any deal
+	GetNucDealsByTypeAndKeysRequiredErrorCode = 1052
+	// GetNucDealListByTradeDateRangeTradeDateRequiredErrorCode is the error code for
+	// when the lastRunTime, fromTradeDate or toTradeDate is not present
+	GetNucDealListByTradeDateRangeTradeDateRequiredErrorCode = 1053
+	// GetNucDealListByTradeDateRangeDateTimeFormatErrorCode is the error code for
+	// when the specified date time is not in RFC3339 format
+	GetNucDealListByTradeDateRangeDateTimeFormatErrorCode = 1054
+	// GetNucDealListByTradeDateRangeInvalidRangeErrorCode is the error code for
+	// when fromTradeDate is after toTradeDate
+	GetNucDealListByTradeDateRangeInvalidRangeErrorCode = 1055
+	// GetNucTCCFTRSDealListByTradeDateRangeDealTypeFormatErrorCode is the error code for
+	// when the dealType isn't one of FTROPT, FTRSWP or TCCSWP
+	GetNucTCCFTRSDealListByTradeDateRangeDealTypeFormatErrorCode = 1056
+	// GetAllDealListsTradeDateRequiredErrorCode is the error code for
+	// when the date time is not present
+	GetAllDealListsTradeDateRequiredErrorCode = 1057
+	// GetAllDealListsDateTimeFormatErrorCode is the error code for
+	// when the specified date time is not in RFC3339 format
+	GetAllDealListsDateTimeFormatErrorCode = 1058
+	// StreamNucDealListTradeDateRequiredErrorCode is the error code for
+	// when the date time is not present
+	StreamNucDealListTradeDateRequiredErrorCode = 1059
+	// StreamNucDealListDateTimeFormatErrorCode is the error code for
+	// when the specified date time is not in RFC3339 format
+	StreamNucDealListDateTimeFormatErrorCode = 1060
+	// StreamNucTCCFTRSDealListDealTypeFormatErrorCode is the error code for
+	// when the dealType isn't one of FTROPT, FTRSWP or TCCSWP
+	StreamNucTCCFTRSDealListDealTypeFormatErrorCode = 1061
+	// ExportDealListsParquetDealTypeFormatErrorCode is the error code for
+	// when one of the dealTypes has no deal list
+	ExportDealListsParquetDealTypeFormatErrorCode = 1062
+	// GetProcessedTradeModelResultsRequiredErrorCode is the error code for
+	// when the dealType or the dealKey is not present
+	GetProcessedTradeModelResultsRequiredErrorCode = 1063
+	// GetProcessedTradeModelResultsDealKeyFormatErrorCode is the error code for
+	// when the dealKey isn't an int
+	GetProcessedTradeModelResultsDealKeyFormatErrorCode = 1064
+	// GetProcessedTradesDateTimeFormatErrorCode is the error code for
+	// when the fromTradeDate or the toTradeDate is not in RFC3339 format
+	GetProcessedTradesDateTimeFormatErrorCode = 1065
+	// GetProcessedTradesFilterFormatErrorCode is the error code for
+	// when the portfolioId, anomalyDetected, page or pageSize can't be parsed
+	GetProcessedTradesFilterFormatErrorCode = 1066
+	// GetProcessedTradesInvalidRangeErrorCode is the error code for
+	// when the fromTradeDate is after the toTradeDate
+	GetProcessedTradesInvalidRangeErrorCode = 1067
+	// GetTradeVersionDiffsRequiredErrorCode is the error code for
+	// when the dealType or the dealKey is not present
+	GetTradeVersionDiffsRequiredErrorCode = 1068
+	// GetTradeVersionDiffsDealKeyFormatErrorCode is the error code for
+	// when the dealKey isn't an int
+	GetTradeVersionDiffsDealKeyFormatErrorCode = 1069
+	// ExtractAndProcessTradesRequiredErrorCode is the error code for
+	// when the tradeDate or the dealType is not present
+	ExtractAndProcessTradesRequiredErrorCode = 1070
+	// ExtractAndProcessTradesDateTimeFormatErrorCode is the error code for
+	// when the tradeDate or the lastRunTime is not in RFC3339 format
+	ExtractAndProcessTradesDateTimeFormatErrorCode = 1071
+	// ExtractAndProcessTradesDealTypeFormatErrorCode is the error code for
+	// when the dealType has no deal list
+	ExtractAndProcessTradesDealTypeFormatErrorCode = 1072
+	// ExtractAndProcessTradesConflictErrorCode is the error code for
+	// when another extraction advanced the watermark in the meantime
+	ExtractAndProcessTradesConflictErrorCode = 1073
+	// GetExtractionRunsTradeDateRequiredErrorCode is the error code for
+	// when the fromTradeDate or the toTradeDate is not present
+	GetExtractionRunsTradeDateRequiredErrorCode = 1074
+	// GetExtractionRunsDateTimeFormatErrorCode is the error code for
+	// when the fromTradeDate or the toTradeDate is not in RFC3339 format
+	GetExtractionRunsDateTimeFormatErrorCode = 1075
+	// GetExtractionRunsInvalidFilterErrorCode is the error code for
+	// when the fromTradeDate is after the toTradeDate or the status is unknown
+	GetExtractionRunsInvalidFilterErrorCode = 1076
+	// ExtractionLeaseHeldErrorCode is the error code for
+	// when another owner holds the extraction lease
+	ExtractionLeaseHeldErrorCode = 1077
+	// ExtractionLeaseLostErrorCode is the error code for
+	// when the owner doesn't hold the extraction lease anymore
+	ExtractionLeaseLostErrorCode = 1078
+	// ExtractionLeaseRequiredErrorCode is the error code for
+	// when the tradeDate, the dealType or the ownerId is not present
+	ExtractionLeaseRequiredErrorCode = 1079
+	// ExtractionLeaseFormatErrorCode is the error code for
+	// when the tradeDate isn't in RFC3339 format or the ttlSeconds isn't a positive int
+	ExtractionLeaseFormatErrorCode = 1080
+	// ExtractionGapsRequiredErrorCode is the error code for
+	// when the fromTradeDate or the toTradeDate is not present
+	ExtractionGapsRequiredErrorCode = 1081
+	// ExtractionGapsFormatErrorCode is the error code for
+	// when a trade date isn't in RFC3339 format or the staleAfterMinutes isn't a positive int
+	ExtractionGapsFormatErrorCode = 1082
+	// ExtractionGapsInvalidRangeErrorCode is the error code for
+	// when the fromTradeDate is after the toTradeDate or the range is too long
+	ExtractionGapsInvalidRangeErrorCode = 1083
+	// ExtractionGapsDealTypeFormatErrorCode is the error code for
+	// when one of the dealTypes has no deal list
+	ExtractionGapsDealTypeFormatErrorCode = 1084
+	// GetExtractionBackfillNotFoundErrorCode is the error code for
+	// when the backfillId isn't a known backfill
+	GetExtractionBackfillNotFoundErrorCode = 1085
+	// CheckPortfolioLegalEntitiesRequiredErrorCode is the error code for
+	// when the tradeDate is not present
+	CheckPortfolioLegalEntitiesRequiredErrorCode = 1086
+	// CheckPortfolioLegalEntitiesFormatErrorCode is the error code for
+	// when the tradeDate isn't in RFC3339 format
+	CheckPortfolioLegalEntitiesFormatErrorCode = 1087
+	// CheckPortfolioLegalEntitiesDealTypeFormatErrorCode is the error code for
+	// when one of the dealTypes has no deal list
+	CheckPortfolioLegalEntitiesDealTypeFormatErrorCode = 1088
+	// CheckCreditLimitsRequiredErrorCode is the error code for
+	// when the tradeDate is not present
+	CheckCreditLimitsRequiredErrorCode = 1089
+	// CheckCreditLimitsFormatErrorCode is the error code for
+	// when the tradeDate isn't in RFC3339 format
+	CheckCreditLimitsFormatErrorCode = 1090
+	// CheckCreditLimitsDealTypeFormatErrorCode is the error code for
+	// when one of the dealTypes has no deal list
+	CheckCreditLimitsDealTypeFormatErrorCode = 1091
+	// StartExtractionBackfillOverlapErrorCode is the error code for
+	// when a gap is still to be run by another backfill
+	StartExtractionBackfillOverlapErrorCode = 1092
+	// CancelExtractionBackfillNotFoundErrorCode is the error code for
+	// when the backfillId isn't a known backfill
+	CancelExtractionBackfillNotFoundErrorCode = 1093
+	// ExportDealListsParquetInvalidRangeErrorCode is the error code for
+	// when the trade date range is longer than maxExportDealListsDays
+	ExportDealListsParquetInvalidRangeErrorCode = 1094
+)
+
+// maxExtractionGapsDays is the longest trade date range scanned for gaps
+const maxExtractionGapsDays = 366
+
+// maxExportDealListsDays is the longest trade date range exported to parquet
+// in one request, the export runs within the request
+const maxExportDealListsDays = 31
+
+const (
+	// parquetExportDirEnv is the environment variable holding the directory
+	// the parquet exports are written to
+	parquetExportDirEnv = "NUCLEUS_PARQUET_EXPORT_DIR"
+	// defaultParquetExportDir is used when parquetExportDirEnv isn't set
+	defaultParquetExportDir = "nucleus-parquet-export"
+)
+
+// dealListStreamFlushSize is the number of deals written to the response
+// between two flushes
+const dealListStreamFlushSize = 100
+
+// AddNucleusHandlers registers the nucleus routes and starts the scheduled
+// extractions. The returned func stops the scheduled extractions and the
+// backfills, and waits for their runs to be recorded and their leases
+// released. It is meant to be called on shutdown
+func AddNucleusHandlers(router *mux.Router, middleware func(handler http.HandlerFunc) http.Handler, logger logger.Logger, repository power.INucleusTradeRepository) (stop func()) {
+	getNucPowerDealListHandler := http.HandlerFunc(makeGetNucPowerDealListHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucPowerDealList/{lastRunTime}/{tradeDate}", middleware(getNucPowerDealListHandler)).Methods("GET")
+
+	getNucPowerSwapDealListHandler := http.HandlerFunc(makeGetNucPowerSwapDealListHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucPowerSwapDealList/{lastRunTime}/{tradeDate}", middleware(getNucPowerSwapDealListHandler)).Methods("GET")
+
+	getNucPowerOptionsDealListHandler := http.HandlerFunc(makeGetNucPowerOptionsDealListHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucPowerOptionsDealList/{lastRunTime}/{tradeDate}", middleware(getNucPowerOptionsDealListHandler)).Methods("GET")
+
+	getNucCapacityDealListHandler := http.HandlerFunc(makeGetNucCapacityDealListHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucCapacityDealList/{lastRunTime}/{tradeDate}", middleware(getNucCapacityDealListHandler)).Methods("GET")
+
+	getNucPTPDealListHandler := http.HandlerFunc(makeGetNucPTPDealListHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucPTPDealList/{lastRunTime}/{tradeDate}", middleware(getNucPTPDealListHandler)).Methods("GET")
+
+	getNucEmissionDealListHandler := http.HandlerFunc(makeGetNucEmissionDealListHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucEmissionDealList/{lastRunTime}/{tradeDate}", middleware(getNucEmissionDealListHandler)).Methods("GET")
+
+	getNucEmissionOptionDealListHandler := http.HandlerFunc(makeGetNucEmissionOptionDealListHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucEmissionOptionDealList/{lastRunTime}/{tradeDate}", middleware(getNucEmissionOptionDealListHandler)).Methods("GET")
+
+	getNucSpreadOptionsDealListHandler := http.HandlerFunc(makeGetNucSpreadOptionsDealListHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucSpreadOptionsDealList/{lastRunTime}/{tradeDate}", middleware(getNucSpreadOptionsDealListHandler)).Methods("GET")
+
+	getNucHeatRateSwapsDealListHandler := http.HandlerFunc(makeGetNucHeatRateSwapsDealListHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucHeatRateSwapsDealList/{lastRunTime}/{tradeDate}", middleware(getNucHeatRateSwapsDealListHandler)).Methods("GET")
+
+	// dealType: FTROPT, FTRSWP, TCCSWP
+	getNucTCCFTRSDealListHandler := http.HandlerFunc(makeGetNucTCCFTRSDealListHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucTCCFTRSDealList/{lastRunTime}/{tradeDate}/{dealType}", middleware(getNucTCCFTRSDealListHandler)).Methods("GET")
+
+	getNucTransmissionDealListHandler := http.HandlerFunc(makeGetNucTransmissionDealListHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucTransmissionDealList/{lastRunTime}/{tradeDate}", middleware(getNucTransmissionDealListHandler)).Methods("GET")
+
+	getNucMiscChargeDealListHandler := http.HandlerFunc(makeGetNucMiscChargeDealListHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucMiscChargeDealList/{lastRunTime}/{tradeDate}", middleware(getNucMiscChargeDealListHandler)).Methods("GET")
+
+	processTradesHandler := http.HandlerFunc(makeProcessTradesHandlerNucleus(logger, repository))
+	router.Handle("/nucleus/power/ProcessTrades", middleware(processTradesHandler)).Methods("POST")
+
+	getProcessedTradeModelResultsHandler := http.HandlerFunc(makeGetProcessedTradeModelResultsHandler(logger, repository))
+	router.Handle("/nucleus/power/GetProcessedTradeModelResults/{dealType}/{dealKey}", middleware(getProcessedTradeModelResultsHandler)).Methods("GET")
+
+	getProcessedTradesHandler := http.HandlerFunc(makeGetProcessedTradesHandler(logger, repository))
+	router.Handle("/nucleus/power/GetProcessedTrades", middleware(getProcessedTradesHandler)).Methods("GET")
+
+	getTradeVersionDiffsHandler := http.HandlerFunc(makeGetTradeVersionDiffsHandler(logger, repository))
+	router.Handle("/nucleus/power/GetTradeVersionDiffs/{dealType}/{dealKey}", middleware(getTradeVersionDiffsHandler)).Methods("GET")
+
+	extractAndProcessTradesHandler := http.HandlerFunc(makeExtractAndProcessTradesHandler(logger, repository))
+	router.Handle("/nucleus/power/ExtractAndProcessTrades/{tradeDate}/{dealType}", middleware(extractAndProcessTradesHandler)).Methods("POST")
+
+	getExtractionRunsHandler := http.HandlerFunc(makeGetExtractionRunsHandler(logger, repository))
+	router.Handle("/nucleus/power/GetExtractionRuns", middleware(getExtractionRunsHandler)).Methods("GET")
+
+	acquireExtractionLeaseHandler := http.HandlerFunc(makeAcquireExtractionLeaseHandler(logger, repository))
+	router.Handle("/nucleus/power/AcquireExtractionLease/{tradeDate}/{dealType}", middleware(acquireExtractionLeaseHandler)).Methods("POST")
+
+	renewExtractionLeaseHandler := http.HandlerFunc(makeRenewExtractionLeaseHandler(logger, repository))
+	router.Handle("/nucleus/power/RenewExtractionLease/{tradeDate}/{dealType}", middleware(renewExtractionLeaseHandler)).Methods("POST")
+
+	releaseExtractionLeaseHandler := http.HandlerFunc(makeReleaseExtractionLeaseHandler(logger, repository))
+	router.Handle("/nucleus/power/ReleaseExtractionLease/{tradeDate}/{dealType}", middleware(releaseExtractionLeaseHandler)).Methods("POST")
+
+	// the backfills and the scheduled extractions run until stop is called,
+	// the scheduler is only started when NUCLEUS_EXTRACTION_SCHEDULER_ENABLED
+	// is true
+	backfillService := power.NewNucleusBackfillService(context.Background(), repository, logger)
+
+	stopScheduler, err := power.StartNucleusExtractionScheduler(context.Background(), repository, logger)
+	if err != nil {
+		logger.GetLogger().Errorln("unable to start the Nucleus extraction scheduler: ", err)
+		stopScheduler = func() {}
+	}
+
+	getExtractionGapsHandler := http.HandlerFunc(makeGetExtractionGapsHandler(logger, repository))
+	router.Handle("/nucleus/power/GetExtractionGaps", middleware(getExtractionGapsHandler)).Methods("GET")
+
+	startExtractionBackfillHandler := http.HandlerFunc(makeStartExtractionBackfillHandler(logger, repository, backfillService))
+	router.Handle("/nucleus/power/StartExtractionBackfill", middleware(startExtractionBackfillHandler)).Methods("POST")
+
+	getExtractionBackfillHandler := http.HandlerFunc(makeGetExtractionBackfillHandler(logger, backfillService))
+	router.Handle("/nucleus/power/GetExtractionBackfill/{backfillId}", middleware(getExtractionBackfillHandler)).Methods("GET")
+
+	cancelExtractionBackfillHandler := http.HandlerFunc(makeCancelExtractionBackfillHandler(logger, backfillService))
+	router.Handle("/nucleus/power/CancelExtractionBackfill/{backfillId}", middleware(cancelExtractionBackfillHandler)).Methods("POST")
+
+	checkPortfolioLegalEntitiesHandler := http.HandlerFunc(makeCheckPortfolioLegalEntitiesHandler(logger, repository))
+	router.Handle("/nucleus/power/CheckPortfolioLegalEntities/{tradeDate}", middleware(checkPortfolioLegalEntitiesHandler)).Methods("GET")
+
+	checkCreditLimitsHandler := http.HandlerFunc(makeCheckCreditLimitsHandler(logger, repository))
+	router.Handle("/nucleus/power/CheckCreditLimits/{tradeDate}", middleware(checkCreditLimitsHandler)).Methods("GET")
+
+	getLastExtractionRunHandler := http.HandlerFunc(makeGetLastExtractionRunHandlerNucleus(logger, repository))
+	router.Handle("/nucleus/power/GetLastExtractionRun/{tradeDate}/{dealType}", middleware(getLastExtractionRunHandler)).Methods("GET")
+
+	insertExtractionRunHandler := http.HandlerFunc(makeInsertExtractionRunHandlerNucleus(logger, repository))
+	router.Handle("/nucleus/power/InsertExtractionRun", middleware(insertExtractionRunHandler)).Methods("POST")
+
+	getPortfolioRiskMappingListHandler := http.HandlerFunc(makeGetPortfolioRiskMappingListHandler(logger, repository))
+	router.Handle("/nucleus/power/GetPortfolioRiskMappingList", middleware(getPortfolioRiskMappingListHandler)).Methods("GET")
+
+	getLarBaselistHandler := http.HandlerFunc(makeGetLarBaselistHandlerNucleus(logger, repository))
+	router.Handle("/nucleus/power/GetLarBaselist", middleware(getLarBaselistHandler)).Methods("GET")
+
+	getNucPowerDealByKeysHandler := http.HandlerFunc(makeGetNucPowerDealByKeysHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucPowerDealByKeys", middleware(getNucPowerDealByKeysHandler)).Methods("GET")
+
+	getNucPowerSwapDealByKeysHandler := http.HandlerFunc(makeGetNucPowerSwapDealByKeysHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucPowerSwapDealByKeys", middleware(getNucPowerSwapDealByKeysHandler)).Methods("GET")
+
+	getNucPowerOptionsDealByKeysHandler := http.HandlerFunc(makeGetNucPowerOptionsDealByKeysHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucPowerOptionsDealByKeys", middleware(getNucPowerOptionsDealByKeysHandler)).Methods("GET")
+
+	getNucCapacityDealByKeysHandler := http.HandlerFunc(makeGetNucCapacityDealByKeysHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucCapacityDealByKeys", middleware(getNucCapacityDealByKeysHandler)).Methods("GET")
+
+	getNucEmissionDealByKeysHandler := http.HandlerFunc(makeGetNucEmissionDealByKeysHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucEmissionDealByKeys", middleware(getNucEmissionDealByKeysHandler)).Methods("GET")
+
+	getNucEmissionOptionDealByKeysHandler := http.HandlerFunc(makeGetNucEmissionOptionDealByKeysHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucEmissionOptionDealByKeys", middleware(getNucEmissionOptionDealByKeysHandler)).Methods("GET")
+
+	// dealType: FTROPT, FTRSWP, TCCSWP
+	getNucTCCFTRSDealByKeysHandler := http.HandlerFunc(makeGetNucTCCFTRSDealByKeysHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucTCCFTRSDealByKeys", middleware(getNucTCCFTRSDealByKeysHandler)).Methods("GET")
+
+	getNucSpreadOptionsDealByKeysHandler := http.HandlerFunc(makeGetNucSpreadOptionsDealByKeysHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucSpreadOptionsDealByKeys", middleware(getNucSpreadOptionsDealByKeysHandler)).Methods("GET")
+
+	getNucHeatRateSwapsDealByKeysHandler := http.HandlerFunc(makeGetNucHeatRateSwapsDealByKeysHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucHeatRateSwapsDealByKeys", middleware(getNucHeatRateSwapsDealByKeysHandler)).Methods("GET")
+
+	getNucTransmissionDealByKeysHandler := http.HandlerFunc(makeGetNucTransmissionDealByKeysHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucTransmissionDealByKeys", middleware(getNucTransmissionDealByKeysHandler)).Methods("GET")
+
+	getNucPTPDealByKeysHandler := http.HandlerFunc(makeGetNucPTPDealByKeysHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucPTPDealByKeys", middleware(getNucPTPDealByKeysHandler)).Methods("GET")
+
+	getNucMiscChargeDealByKeysHandler := http.HandlerFunc(makeGetNucMiscChargeDealByKeysHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucMiscChargeDealByKeys", middleware(getNucMiscChargeDealByKeysHandler)).Methods("GET")
+
+	getNucDealsByTypeAndKeysHandler := http.HandlerFunc(makeGetNucDealsByTypeAndKeysHandler(logger, repository))
+	router.Handle("/nucleus/power/deals/lookup", middleware(getNucDealsByTypeAndKeysHandler)).Methods("POST")
+
+	getNucPowerDealListByTradeDateRangeHandler := http.HandlerFunc(makeGetNucDealListByTradeDateRangeHandler(logger, "unable to Get Nucleus Power DealList By Trade Date Range", repository.GetNucPowerDealListByTradeDateRange))
+	router.Handle("/nucleus/power/GetNucPowerDealListByTradeDateRange/{lastRunTime}", middleware(getNucPowerDealListByTradeDateRangeHandler)).Methods("GET")
+
+	getNucPowerSwapDealListByTradeDateRangeHandler := http.HandlerFunc(makeGetNucDealListByTradeDateRangeHandler(logger, "unable to Get Nucleus Power Swap DealList By Trade Date Range", repository.GetNucPowerSwapDealListByTradeDateRange))
+	router.Handle("/nucleus/power/GetNucPowerSwapDealListByTradeDateRange/{lastRunTime}", middleware(getNucPowerSwapDealListByTradeDateRangeHandler)).Methods("GET")
+
+	getNucPowerOptionsDealListByTradeDateRangeHandler := http.HandlerFunc(makeGetNucDealListByTradeDateRangeHandler(logger, "unable to Get Nucleus Power Options DealList By Trade Date Range", repository.GetNucPowerOptionsDealListByTradeDateRange))
+	router.Handle("/nucleus/power/GetNucPowerOptionsDealListByTradeDateRange/{lastRunTime}", middleware(getNucPowerOptionsDealListByTradeDateRangeHandler)).Methods("GET")
+
+	getNucCapacityDealListByTradeDateRangeHandler := http.HandlerFunc(makeGetNucDealListByTradeDateRangeHandler(logger, "unable to Get Nucleus Capacity DealList By Trade Date Range", repository.GetNucCapacityDealListByTradeDateRange))
+	router.Handle("/nucleus/power/GetNucCapacityDealListByTradeDateRange/{lastRunTime}", middleware(getNucCapacityDealListByTradeDateRangeHandler)).Methods("GET")
+
+	getNucPTPDealListByTradeDateRangeHandler := http.HandlerFunc(makeGetNucDealListByTradeDateRangeHandler(logger, "unable to Get Nucleus PTP DealList By Trade Date Range", repository.GetNucPTPDealListByTradeDateRange))
+	router.Handle("/nucleus/power/GetNucPTPDealListByTradeDateRange/{lastRunTime}", middleware(getNucPTPDealListByTradeDateRangeHandler)).Methods("GET")
+
+	getNucEmissionDealListByTradeDateRangeHandler := http.HandlerFunc(makeGetNucDealListByTradeDateRangeHandler(logger, "unable to Get Nucleus Emission DealList By Trade Date Range", repository.GetNucEmissionDealListByTradeDateRange))
+	router.Handle("/nucleus/power/GetNucEmissionDealListByTradeDateRange/{lastRunTime}", middleware(getNucEmissionDealListByTradeDateRangeHandler)).Methods("GET")
+
+	getNucEmissionOptionDealListByTradeDateRangeHandler := http.HandlerFunc(makeGetNucDealListByTradeDateRangeHandler(logger, "unable to Get Nucleus Emission Option DealList By Trade Date Range", repository.GetNucEmissionOptionDealListByTradeDateRange))
+	router.Handle("/nucleus/power/GetNucEmissionOptionDealListByTradeDateRange/{lastRunTime}", middleware(getNucEmissionOptionDealListByTradeDateRangeHandler)).Methods("GET")
+
+	getNucSpreadOptionsDealListByTradeDateRangeHandler := http.HandlerFunc(makeGetNucDealListByTradeDateRangeHandler(logger, "unable to Get Nucleus Spread Options DealList By Trade Date Range", repository.GetNucSpreadOptionsDealListByTradeDateRange))
+	router.Handle("/nucleus/power/GetNucSpreadOptionsDealListByTradeDateRange/{lastRunTime}", middleware(getNucSpreadOptionsDealListByTradeDateRangeHandler)).Methods("GET")
+
+	getNucHeatRateSwapsDealListByTradeDateRangeHandler := http.HandlerFunc(makeGetNucDealListByTradeDateRangeHandler(logger, "unable to Get Nucleus Heat Rate Swaps DealList By Trade Date Range", repository.GetNucHeatRateSwapsDealListByTradeDateRange))
+	router.Handle("/nucleus/power/GetNucHeatRateSwapsDealListByTradeDateRange/{lastRunTime}", middleware(getNucHeatRateSwapsDealListByTradeDateRangeHandler)).Methods("GET")
+
+	// dealType: FTROPT, FTRSWP, TCCSWP
+	getNucTCCFTRSDealListByTradeDateRangeHandler := http.HandlerFunc(makeGetNucTCCFTRSDealListByTradeDateRangeHandler(logger, repository))
+	router.Handle("/nucleus/power/GetNucTCCFTRSDealListByTradeDateRange/{lastRunTime}/{dealType}", middleware(getNucTCCFTRSDealListByTradeDateRangeHandler)).Methods("GET")
+
+	getNucTransmissionDealListByTradeDateRangeHandler := http.HandlerFunc(makeGetNucDealListByTradeDateRangeHandler(logger, "unable to Get Nucleus Transmission DealList By Trade Date Range", repository.GetNucTransmissionDealListByTradeDateRange))
+	router.Handle("/nucleus/power/GetNucTransmissionDealListByTradeDateRange/{lastRunTime}", middleware(getNucTransmissionDealListByTradeDateRangeHandler)).Methods("GET")
+
+	getNucMiscChargeDealListByTradeDateRangeHandler := http.HandlerFunc(makeGetNucDealListByTradeDateRangeHandler(logger, "unable to Get Nucleus Misc Charge DealList By Trade Date Range", repository.GetNucMiscChargeDealListByTradeDateRange))
+	router.Handle("/nucleus/power/GetNucMiscChargeDealListByTradeDateRange/{lastRunTime}", middleware(getNucMiscChargeDealListByTradeDateRangeHandler)).Methods("GET")
+
+	getAllDealListsHandler := http.HandlerFunc(makeGetAllDealListsHandler(logger, repository))
+	router.Handle("/nucleus/power/GetAllDealLists/{lastRunTime}/{tradeDate}", middleware(getAllDealListsHandler)).Methods("GET")
+
+	streamNucPowerDealListHandler := http.HandlerFunc(makeStreamNucDealListHandler(logger, "unable to Stream Nucleus Power DealList", repository.StreamNucPowerDealList))
+	router.Handle("/nucleus/power/StreamNucPowerDealList/{lastRunTime}/{tradeDate}", middleware(streamNucPowerDealListHandler)).Methods("GET")
+
+	streamNucPowerSwapDealListHandler := http.HandlerFunc(makeStreamNucDealListHandler(logger, "unable to Stream Nucleus Power Swap DealList", repository.StreamNucPowerSwapDealList))
+	router.Handle("/nucleus/power/StreamNucPowerSwapDealList/{lastRunTime}/{tradeDate}", middleware(streamNucPowerSwapDealListHandler)).Methods("GET")
+
+	streamNucPowerOptionsDealListHandler := http.HandlerFunc(makeStreamNucDealListHandler(logger, "unable to Stream Nucleus Power Options DealList", repository.StreamNucPowerOptionsDealList))
+	router.Handle("/nucleus/power/StreamNucPowerOptionsDealList/{lastRunTime}/{tradeDate}", middleware(streamNucPowerOptionsDealListHandler)).Methods("GET")
+
+	streamNucCapacityDealListHandler := http.HandlerFunc(makeStreamNucDealListHandler(logger, "unable to Stream Nucleus Capacity DealList", repository.StreamNucCapacityDealList))
+	router.Handle("/nucleus/power/StreamNucCapacityDealList/{lastRunTime}/{tradeDate}", middleware(streamNucCapacityDealListHandler)).Methods("GET")
+
+	streamNucPTPDealListHandler := http.HandlerFunc(makeStreamNucDealListHandler(logger, "unable to Stream Nucleus PTP DealList", repository.StreamNucPTPDealList))
+	router.Handle("/nucleus/power/StreamNucPTPDealList/{lastRunTime}/{tradeDate}", middleware(streamNucPTPDealListHandler)).Methods("GET")
+
+	streamNucEmissionDealListHandler := http.HandlerFunc(makeStreamNucDealListHandler(logger, "unable to Stream Nucleus Emission DealList", repository.StreamNucEmissionDealList))
+	router.Handle("/nucleus/power/StreamNucEmissionDealList/{lastRunTime}/{tradeDate}", middleware(streamNucEmissionDealListHandler)).Methods("GET")
+
+	streamNucEmissionOptionDealListHandler := http.HandlerFunc(makeStreamNucDealListHandler(logger, "unable to Stream Nucleus Emission Option DealList", repository.StreamNucEmissionOptionDealList))
+	router.Handle("/nucleus/power/StreamNucEmissionOptionDealList/{lastRunTime}/{tradeDate}", middleware(streamNucEmissionOptionDealListHandler)).Methods("GET")
+
+	streamNucSpreadOptionsDealListHandler := http.HandlerFunc(makeStreamNucDealListHandler(logger, "unable to Stream Nucleus Spread Options DealList", repository.StreamNucSpreadOptionsDealList))
+	router.Handle("/nucleus/power/StreamNucSpreadOptionsDealList/{lastRunTime}/{tradeDate}", middleware(streamNucSpreadOptionsDealListHandler)).Methods("GET")
+
+	streamNucHeatRateSwapsDealListHandler := http.HandlerFunc(makeStreamNucDealListHandler(logger, "unable to Stream Nucleus Heat Rate Swaps DealList", repository.StreamNucHeatRateSwapsDealList))
+	router.Handle("/nucleus/power/StreamNucHeatRateSwapsDealList/{lastRunTime}/{tradeDate}", middleware(streamNucHeatRateSwapsDealListHandler)).Methods("GET")
+
+	// dealType: FTROPT, FTRSWP, TCCSWP
+	streamNucTCCFTRSDealListHandler := http.HandlerFunc(makeStreamNucTCCFTRSDealListHandler(logger, repository))
+	router.Handle("/nucleus/power/StreamNucTCCFTRSDealList/{lastRunTime}/{tradeDate}/{dealType}", middleware(streamNucTCCFTRSDealListHandler)).Methods("GET")
+
+	streamNucTransmissionDealListHandler := http.HandlerFunc(makeStreamNucDealListHandler(logger, "unable to Stream Nucleus Transmission DealList", repository.StreamNucTransmissionDealList))
+	router.Handle("/nucleus/power/StreamNucTransmissionDealList/{lastRunTime}/{tradeDate}", middleware(streamNucTransmissionDealListHandler)).Methods("GET")
+
+	streamNucMiscChargeDealListHandler := http.HandlerFunc(makeStreamNucDealListHandler(logger, "unable to Stream Nucleus Misc Charge DealList", repository.StreamNucMiscChargeDealList))
+	router.Handle("/nucleus/power/StreamNucMiscChargeDealList/{lastRunTime}/{tradeDate}", middleware(streamNucMiscChargeDealListHandler)).Methods("GET")
+
+	exportDealListsParquetHandler := http.HandlerFunc(makeExportDealListsParquetHandler(logger, repository))
+	router.Handle("/nucleus/power/ExportDealListsParquet", middleware(exportDealListsParquetHandler)).Methods("POST")
+
+	return func() {
+		stopScheduler()
+		backfillService.Stop()
+	}
+}
+
+func makeGetNucPowerDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+
+		lastRunTimeParam, ok := params["lastRunTime"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
+				models.NewServerError(GetNucPowerDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
+				models.NewServerError(GetNucPowerDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		tradeDateParam, ok := params["tradeDate"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
+				models.NewServerError(GetNucPowerDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
+				models.NewServerError(GetNucPowerDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		results, err := repository.GetNucPowerDealList(r.Context(), lastRunTime, tradeDate)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to get Nucleus Power Deal List",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetNucPowerSwapDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+
+		lastRunTimeParam, ok := params["lastRunTime"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
+				models.NewServerError(GetNucPowerSwapDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
+				models.NewServerError(GetNucPowerSwapDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		tradeDateParam, ok := params["tradeDate"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
+				models.NewServerError(GetNucPowerSwapDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
+				models.NewServerError(GetNucPowerSwapDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		results, err := repository.GetNucPowerSwapDealList(r.Context(), lastRunTime, tradeDate)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to get Nucleus Power Swap Deal List",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetNucPowerOptionsDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+
+		lastRunTimeParam, ok := params["lastRunTime"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
+				models.NewServerError(GetNucPowerOptionsDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
+				models.NewServerError(GetNucPowerOptionsDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		tradeDateParam, ok := params["tradeDate"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
+				models.NewServerError(GetNucPowerOptionsDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
+				models.NewServerError(GetNucPowerOptionsDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		results, err := repository.GetNucPowerOptionsDealList(r.Context(), lastRunTime, tradeDate)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Nucleus Power Options Deal List",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetNucCapacityDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+
+		lastRunTimeParam, ok := params["lastRunTime"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
+				models.NewServerError(GetNucCapacityDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
+				models.NewServerError(GetNucCapacityDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		tradeDateParam, ok := params["tradeDate"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
+				models.NewServerError(GetNucCapacityDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
+				models.NewServerError(GetNucCapacityDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		results, err := repository.GetNucCapacityDealList(r.Context(), lastRunTime, tradeDate)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to get Nucleus Capacity Deal List",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetNucPTPDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+
+		lastRunTimeParam, ok := params["lastRunTime"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
+				models.NewServerError(GetNucPTPDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
+				models.NewServerError(GetNucPTPDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		tradeDateParam, ok := params["tradeDate"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
+				models.NewServerError(GetNucPTPDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
+				models.NewServerError(GetNucPTPDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		results, err := repository.GetNucPTPDealList(r.Context(), lastRunTime, tradeDate)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Nucleus PTP Deal List",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetNucEmissionDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+
+		lastRunTimeParam, ok := params["lastRunTime"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
+				models.NewServerError(GetNucEmissionDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
+				models.NewServerError(GetNucEmissionDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		tradeDateParam, ok := params["tradeDate"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
+				models.NewServerError(GetNucEmissionDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
+				models.NewServerError(GetNucEmissionDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		results, err := repository.GetNucEmissionDealList(r.Context(), lastRunTime, tradeDate)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Nucleus Emission Deal List",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetNucEmissionOptionDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+
+		lastRunTimeParam, ok := params["lastRunTime"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
+				models.NewServerError(GetNucEmissionOptionDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
+				models.NewServerError(GetNucEmissionOptionDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		tradeDateParam, ok := params["tradeDate"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
+				models.NewServerError(GetNucEmissionOptionDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
+				models.NewServerError(GetNucEmissionOptionDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		results, err := repository.GetNucEmissionOptionDealList(r.Context(), lastRunTime, tradeDate)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Nucleus Emission Option Deal List",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetNucSpreadOptionsDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+
+		lastRunTimeParam, ok := params["lastRunTime"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
+				models.NewServerError(GetNucSpreadOptionsDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
+				models.NewServerError(GetNucSpreadOptionsDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		tradeDateParam, ok := params["tradeDate"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
+				models.NewServerError(GetNucSpreadOptionsDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
+				models.NewServerError(GetNucSpreadOptionsDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		results, err := repository.GetNucSpreadOptionsDealList(r.Context(), lastRunTime, tradeDate)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Nucleus Spread Options DealList",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetNucHeatRateSwapsDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+
+		lastRunTimeParam, ok := params["lastRunTime"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
+				models.NewServerError(GetNucHeatRateSwapsDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
+				models.NewServerError(GetNucHeatRateSwapsDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		tradeDateParam, ok := params["tradeDate"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
+				models.NewServerError(GetNucHeatRateSwapsDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
+				models.NewServerError(GetNucHeatRateSwapsDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		results, err := repository.GetNucHeatRateSwapsDealList(r.Context(), lastRunTime, tradeDate)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Nucleus Heat Rate Swaps DealList",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetNucTCCFTRSDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+
+		lastRunTimeParam, ok := params["lastRunTime"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
+				models.NewServerError(GetNucTCCFTRSDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
+				models.NewServerError(GetNucTCCFTRSDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		tradeDateParam, ok := params["tradeDate"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
+				models.NewServerError(GetNucTCCFTRSDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
+				models.NewServerError(GetNucTCCFTRSDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		dealType, ok := params["dealType"]
+		if !ok || dealType == "" {
+			if err := handlers.SendBadRequest(w, r, "dealType is required",
+				models.NewServerError(GetNucTCCFTRSDealListDealTypeFormatErrorCode, "dealType is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		results, err := repository.GetNucTCCFTRSDealList(r.Context(), lastRunTime, tradeDate, dealType)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Nucleus TCCFTRS DealList",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetNucTransmissionDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+
+		lastRunTimeParam, ok := params["lastRunTime"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
+				models.NewServerError(GetNucTransmissionDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
+				models.NewServerError(GetNucTransmissionDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		tradeDateParam, ok := params["tradeDate"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
+				models.NewServerError(GetNucTransmissionDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
+				models.NewServerError(GetNucTransmissionDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		results, err := repository.GetNucTransmissionDealList(r.Context(), lastRunTime, tradeDate)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Nucleus Heat Rate Swaps DealList",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetNucMiscChargeDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+
+		lastRunTimeParam, ok := params["lastRunTime"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
+				models.NewServerError(GetNucMiscChargeDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
+				models.NewServerError(GetNucMiscChargeDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		tradeDateParam, ok := params["tradeDate"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
+				models.NewServerError(GetNucMiscChargeDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
+				models.NewServerError(GetNucMiscChargeDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		results, err := repository.GetNucMiscChargeDealList(r.Context(), lastRunTime, tradeDate)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Nucleus Heat Rate Swaps DealList",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+type makeProcessTradesHandlerNucleusBody struct {
+	Trades          []*nucleus.NucleusTradeHeaderModel `json:"trades"`
+	AnomalyMessages map[int][]processedTradeExample    `json:"anomalyMessages"`
+}
+
+func makeProcessTradesHandlerNucleus(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		var data makeProcessTradesHandlerNucleusBody
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse body",
+				models.NewServerError(ProcessTradesBodyFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		anomalyMessages := make(map[int][]common.IModelBasePayload)
+
+		for index := range data.AnomalyMessages {
+			for _, value := range data.AnomalyMessages[index] {
+				// we need to convert the coming value from ModelBasePayload to
+				// ResultModelBasePayload, that way we can read all the properties
+				// from the incoming json and we can ingnore the necessary values
+				// when we save the data to the database
+				newValue := processedTradeExampleResult{
+					Company:  value.Company,
+					Trader:   value.Trader,
+					Source:   value.Source,
+					Location: value.Location,
+					Broker:   value.Broker,
+					ResultModelBasePayload: &common.ResultModelBasePayload{
+						ModelName:   value.ModelName,
+						Message:     value.Message,
+						DealKey:     value.DealKey,
+						DealType:    value.DealType,
+						ScoredLabel: value.ScoredLabel,
+					},
+				}
+
+				anomalyMessages[index] = append(anomalyMessages[index], newValue)
+			}
+		}
+
+		results, err := repository.ProcessTrades(r.Context(), data.Trades, anomalyMessages)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Process Trades",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := handlers.SendOk(w, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetProcessedTradeModelResultsHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+
+		dealTypeParam, ok := params["dealType"]
+		if !ok || dealTypeParam == "" {
+			if err := handlers.SendBadRequest(w, r, "dealType is required",
+				models.NewServerError(GetProcessedTradeModelResultsRequiredErrorCode, "dealType is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		dealKeyParam, ok := params["dealKey"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "dealKey is required",
+				models.NewServerError(GetProcessedTradeModelResultsRequiredErrorCode, "dealKey is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		dealKey, err := strconv.Atoi(dealKeyParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse dealKey as int",
+				models.NewServerError(GetProcessedTradeModelResultsDealKeyFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		results, err := repository.GetProcessedTradeModelResults(r.Context(), dealKey, dealTypeParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Processed Trade Model Results",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := handlers.SendOk(w, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+// makeExtractAndProcessTradesHandler reads the optional lastRunTime query
+// parameter, only used when the trade date and deal type were never extracted,
+// and the optional ownerId the extraction lease is held by
+func makeExtractAndProcessTradesHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+
+		tradeDateParam, ok := params["tradeDate"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
+				models.NewServerError(ExtractAndProcessTradesRequiredErrorCode, "tradeDate is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
+				models.NewServerError(ExtractAndProcessTradesDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		dealTypeParam, ok := params["dealType"]
+		if !ok || dealTypeParam == "" {
+			if err := handlers.SendBadRequest(w, r, "dealType is required",
+				models.NewServerError(ExtractAndProcessTradesRequiredErrorCode, "dealType is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		var initialLastRunTime time.Time
+		if lastRunTimeParam := r.URL.Query().Get("lastRunTime"); lastRunTimeParam != "" {
+			initialLastRunTime, err = time.Parse(time.RFC3339, lastRunTimeParam)
+			if err != nil {
+				gLogger.Errorln(err)
+				if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
+					models.NewServerError(ExtractAndProcessTradesDateTimeFormatErrorCode, err.Error())); err != nil {
+					gLogger.Errorln(err)
+				}
+				return
+			}
+		}
+
+		summary, err := repository.ExtractAndProcessTrades(r.Context(), tradeDate, dealTypeParam, initialLastRunTime, r.URL.Query().Get("ownerId"))
+		if err != nil {
+			gLogger.Errorln(err)
+			switch {
+			case errors.Is(err, power.ErrNucDealTypeNotSupported):
+				if err := handlers.SendBadRequest(w, r, "dealType has no deal list",
+					models.NewServerError(ExtractAndProcessTradesDealTypeFormatErrorCode, err.Error())); err != nil {
+					gLogger.Errorln(err)
+				}
+			case errors.Is(err, power.ErrExtractionRunConflict):
+				if err := sendConflict(w, models.NewServerError(ExtractAndProcessTradesConflictErrorCode, err.Error())); err != nil {
+					gLogger.Errorln(err)
+				}
+			default:
+				sendExtractionLeaseError(logger, w, r, err, "unable to Extract And Process Trades")
+			}
+			return
+		}
+
+		if err := handlers.SendOk(w, summary); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+// makeGetExtractionRunsHandler reads the fromTradeDate and toTradeDate
+// (RFC3339) query parameters, and the optional dealType and status
+func makeGetExtractionRunsHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		var tradeDates [2]time.Time
+		for index, name := range []string{"fromTradeDate", "toTradeDate"} {
+			tradeDateParam := query.Get(name)
+			if tradeDateParam == "" {
+				if err := handlers.SendBadRequest(w, r, name+" is required",
+					models.NewServerError(GetExtractionRunsTradeDateRequiredErrorCode, name+" is required")); err != nil {
+					gLogger.Errorln(err)
+				}
+				return
+			}
+			tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
+			if err != nil {
+				gLogger.Errorln(err)
+				if err := handlers.SendBadRequest(w, r, "failed to parse "+name+" as time in RFC3339",
+					models.NewServerError(GetExtractionRunsDateTimeFormatErrorCode, err.Error())); err != nil {
+					gLogger.Errorln(err)
+				}
+				return
+			}
+			tradeDates[index] = tradeDate
+		}
+		fromTradeDate, toTradeDate := tradeDates[0], tradeDates[1]
+
+		if fromTradeDate.After(toTradeDate) {
+			if err := handlers.SendBadRequest(w, r, "fromTradeDate must not be after toTradeDate",
+				models.NewServerError(GetExtractionRunsInvalidFilterErrorCode, "fromTradeDate must not be after toTradeDate")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		status := query.Get("status")
+		switch status {
+		case "", power.ExtractionRunStarted, power.ExtractionRunSucceeded, power.ExtractionRunFailed:
+		default:
+			if err := handlers.SendBadRequest(w, r, "status must be started, succeeded or failed",
+				models.NewServerError(GetExtractionRunsInvalidFilterErrorCode, "unknown status "+status)); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		extractionRuns, err := repository.GetExtractionRuns(r.Context(), fromTradeDate, toTradeDate, query.Get("dealType"), status)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Extraction Runs",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := handlers.SendOk(w, extractionRuns); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeAcquireExtractionLeaseHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		tradeDate, dealType, ownerId, ttl, ok := parseExtractionLeaseRequest(logger, w, r)
+		if !ok {
+			return
+		}
+
+		lease, err := repository.AcquireExtractionLease(r.Context(), tradeDate, dealType, ownerId, ttl)
+		if err != nil {
+			gLogger.Errorln(err)
+			sendExtractionLeaseError(logger, w, r, err, "unable to Acquire Extraction Lease")
+			return
+		}
+
+		if err := handlers.SendOk(w, lease); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeRenewExtractionLeaseHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		tradeDate, dealType, ownerId, ttl, ok := parseExtractionLeaseRequest(logger, w, r)
+		if !ok {
+			return
+		}
+
+		lease, err := repository.RenewExtractionLease(r.Context(), tradeDate, dealType, ownerId, ttl)
+		if err != nil {
+			gLogger.Errorln(err)
+			sendExtractionLeaseError(logger, w, r, err, "unable to Renew Extraction Lease")
+			return
+		}
+
+		if err := handlers.SendOk(w, lease); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeReleaseExtractionLeaseHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		tradeDate, dealType, ownerId, _, ok := parseExtractionLeaseRequest(logger, w, r)
+		if !ok {
+			return
+		}
+
+		if err := repository.ReleaseExtractionLease(r.Context(), tradeDate, dealType, ownerId); err != nil {
+			gLogger.Errorln(err)
+			sendExtractionLeaseError(logger, w, r, err, "unable to Release Extraction Lease")
+			return
+		}
+
+		if err := handlers.SendOk(w, "lease released"); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+// parseExtractionLeaseRequest reads the tradeDate (RFC3339) and dealType path
+// variables, the ownerId query parameter and the optional ttlSeconds. When it
+// returns false the bad request has already been sent
+func parseExtractionLeaseRequest(logger logger.Logger, w http.ResponseWriter, r *http.Request) (tradeDate time.Time, dealType string, ownerId string, ttl time.Duration, ok bool) {
+	gLogger := logger.GetLogger()
+	params := mux.Vars(r)
+	query := r.URL.Query()
+
+	tradeDateParam, ok := params["tradeDate"]
+	if !ok {
+		if err := handlers.SendBadRequest(w, r, "tradeDate is required",
+			models.NewServerError(ExtractionLeaseRequiredErrorCode, "tradeDate is required")); err != nil {
+			gLogger.Errorln(err)
+		}
+		return tradeDate, dealType, ownerId, ttl, false
+	}
+	tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
+	if err != nil {
+		gLogger.Errorln(err)
+		if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
+			models.NewServerError(ExtractionLeaseFormatErrorCode, err.Error())); err != nil {
+			gLogger.Errorln(err)
+		}
+		return tradeDate, dealType, ownerId, ttl, false
+	}
+
+	dealType = params["dealType"]
+	if dealType == "" {
+		if err := handlers.SendBadRequest(w, r, "dealType is required",
+			models.NewServerError(ExtractionLeaseRequiredErrorCode, "dealType is required")); err != nil {
+			gLogger.Errorln(err)
+		}
+		return tradeDate, dealType, ownerId, ttl, false
+	}
+
+	ownerId = query.Get("ownerId")
+	if ownerId == "" {
+		if err := handlers.SendBadRequest(w, r, "ownerId is required",
+			models.NewServerError(ExtractionLeaseRequiredErrorCode, "ownerId is required")); err != nil {
+			gLogger.Errorln(err)
+		}
+		return tradeDate, dealType, ownerId, ttl, false
+	}
+
+	if ttlParam := query.Get("ttlSeconds"); ttlParam != "" {
+		ttlSeconds, err := strconv.Atoi(ttlParam)
+		if err == nil && ttlSeconds <= 0 {
+			err = errors.New("ttlSeconds must be positive")
+		}
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse ttlSeconds as a positive int",
+				models.NewServerError(ExtractionLeaseFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return tradeDate, dealType, ownerId, ttl, false
+		}
+		ttl = time.Duration(ttlSeconds) * time.Second
+	}
+
+	return tradeDate, dealType, ownerId, ttl, true
+}
+
+// sendExtractionLeaseError answers with a 409 when the lease is held by
+// another owner or was lost, and a 500 otherwise
+func sendExtractionLeaseError(logger logger.Logger, w http.ResponseWriter, r *http.Request, err error, msg string) {
+	gLogger := logger.GetLogger()
+
+	switch {
+	case errors.Is(err, power.ErrExtractionLeaseHeld):
+		if err := sendConflict(w, models.NewServerError(ExtractionLeaseHeldErrorCode, err.Error())); err != nil {
+			gLogger.Errorln(err)
+		}
+	case errors.Is(err, power.ErrExtractionLeaseLost):
+		if err := sendConflict(w, models.NewServerError(ExtractionLeaseLostErrorCode, err.Error())); err != nil {
+			gLogger.Errorln(err)
+		}
+	default:
+		if err := handlers.SendInternalServerError(w, r, msg,
+			models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetExtractionGapsHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		plan, ok := getExtractionGaps(logger, w, r, repository)
+		if !ok {
+			return
+		}
+
+		if err := handlers.SendOk(w, plan); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+// makeStartExtractionBackfillHandler starts the backfill of the gaps found
+// like GetExtractionGaps, the optional ownerId holds the extraction leases.
+// It is a conflict when a gap is still to be run by another backfill
+func makeStartExtractionBackfillHandler(logger logger.Logger, repository power.INucleusTradeRepository, backfillService *power.NucleusBackfillService) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		plan, ok := getExtractionGaps(logger, w, r, repository)
+		if !ok {
+			return
+		}
+
+		backfill, err := backfillService.StartBackfill(plan, r.URL.Query().Get("ownerId"))
+		if err != nil {
+			gLogger.Errorln(err)
+			if errors.Is(err, power.ErrBackfillOverlap) {
+				if err := sendConflict(w, models.NewServerError(StartExtractionBackfillOverlapErrorCode, err.Error())); err != nil {
+					gLogger.Errorln(err)
+				}
+				return
+			}
+			if err := handlers.SendInternalServerError(w, r, "unable to Start Extraction Backfill",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := handlers.SendOk(w, backfill); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetExtractionBackfillHandler(logger logger.Logger, backfillService *power.NucleusBackfillService) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		backfillId := mux.Vars(r)["backfillId"]
+
+		backfill := backfillService.GetBackfill(backfillId)
+		if backfill == nil {
+			if err := sendServerError(w, http.StatusNotFound,
+				models.NewServerError(GetExtractionBackfillNotFoundErrorCode, "unknown backfill "+backfillId)); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := handlers.SendOk(w, backfill); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+// makeCancelExtractionBackfillHandler cancels the backfill and sends its
+// progress, it is cancelled once its finishedAt is set
+func makeCancelExtractionBackfillHandler(logger logger.Logger, backfillService *power.NucleusBackfillService) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		backfillId := mux.Vars(r)["backfillId"]
+
+		backfill := backfillService.CancelBackfill(backfillId)
+		if backfill == nil {
+			if err := sendServerError(w, http.StatusNotFound,
+				models.NewServerError(CancelExtractionBackfillNotFoundErrorCode, "unknown backfill "+backfillId)); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := handlers.SendOk(w, backfill); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeCheckPortfolioLegalEntitiesHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	return makeRuleCheckHandler(logger, "Check Portfolio Legal Entities", repository.CheckPortfolioLegalEntities,
+		CheckPortfolioLegalEntitiesRequiredErrorCode, CheckPortfolioLegalEntitiesFormatErrorCode, CheckPortfolioLegalEntitiesDealTypeFormatErrorCode)
+}
+
+func makeCheckCreditLimitsHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	return makeRuleCheckHandler(logger, "Check Credit Limits", repository.CheckCreditLimits,
+		CheckCreditLimitsRequiredErrorCode, CheckCreditLimitsFormatErrorCode, CheckCreditLimitsDealTypeFormatErrorCode)
+}
+
+// makeRuleCheckHandler checks the deals of the {tradeDate} of the dealTypes
+// query param, a comma separated list defaulting to every deal type
+func makeRuleCheckHandler(logger logger.Logger, name string, check func(ctx context.Context, tradeDate time.Time, dealTypes []string) (*power.NucleusRuleCheck, error), requiredErrorCode int, formatErrorCode int, dealTypeFormatErrorCode int) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+
+		tradeDateParam, ok := params["tradeDate"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
+				models.NewServerError(requiredErrorCode, "tradeDate is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
+				models.NewServerError(formatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		var dealTypes []string
+		if dealTypesParam := r.URL.Query().Get("dealTypes"); dealTypesParam != "" {
+			dealTypes = strings.Split(dealTypesParam, ",")
+		}
+
+		result, err := check(r.Context(), tradeDate, dealTypes)
+		if err != nil {
+			gLogger.Errorln(err)
+			if errors.Is(err, power.ErrNucDealTypeNotSupported) {
+				if err := handlers.SendBadRequest(w, r, "dealTypes contains an unsupported deal type",
+					models.NewServerError(dealTypeFormatErrorCode, err.Error())); err != nil {
+					gLogger.Errorln(err)
+				}
+				return
+			}
+			if err := handlers.SendInternalServerError(w, r, "unable to "+name,
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := handlers.SendOk(w, result); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+// getExtractionGaps reads the fromTradeDate and toTradeDate (RFC3339) query
+// parameters, the optional comma separated dealTypes and staleAfterMinutes,
+// and scans the extraction runs for gaps. When it returns false the error has
+// already been sent
+func getExtractionGaps(logger logger.Logger, w http.ResponseWriter, r *http.Request, repository power.INucleusTradeRepository) (*power.NucleusBackfillPlan, bool) {
+	gLogger := logger.GetLogger()
+	query := r.URL.Query()
+
+	var tradeDates [2]time.Time
+	for index, name := range []string{"fromTradeDate", "toTradeDate"} {
+		tradeDateParam := query.Get(name)
+		if tradeDateParam == "" {
+			if err := handlers.SendBadRequest(w, r, name+" is required",
+				models.NewServerError(ExtractionGapsRequiredErrorCode, name+" is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return nil, false
+		}
+		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse "+name+" as time in RFC3339",
+				models.NewServerError(ExtractionGapsFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return nil, false
+		}
+		tradeDates[index] = tradeDate
+	}
+	fromTradeDate, toTradeDate := tradeDates[0], tradeDates[1]
+
+	if fromTradeDate.After(toTradeDate) || toTradeDate.Sub(fromTradeDate) > maxExtractionGapsDays*24*time.Hour {
+		if err := handlers.SendBadRequest(w, r, "fromTradeDate must not be after toTradeDate nor more than a year before",
+			models.NewServerError(ExtractionGapsInvalidRangeErrorCode, "invalid trade date range")); err != nil {
+			gLogger.Errorln(err)
+		}
+		return nil, false
+	}
+
+	var dealTypes []string
+	if dealTypesParam := query.Get("dealTypes"); dealTypesParam != "" {
+		dealTypes = strings.Split(dealTypesParam, ",")
+	}
+
+	var staleAfter time.Duration
+	if staleAfterParam := query.Get("staleAfterMinutes"); staleAfterParam != "" {
+		staleAfterMinutes, err := strconv.Atoi(staleAfterParam)
+		if err == nil && staleAfterMinutes <= 0 {
+			err = errors.New("staleAfterMinutes must be positive")
+		}
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse staleAfterMinutes as a positive int",
+				models.NewServerError(ExtractionGapsFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return nil, false
+		}
+		staleAfter = time.Duration(staleAfterMinutes) * time.Minute
+	}
+
+	plan, err := repository.GetExtractionGaps(r.Context(), fromTradeDate, toTradeDate, dealTypes, staleAfter)
+	if err != nil {
+		gLogger.Errorln(err)
+		if errors.Is(err, power.ErrNucDealTypeNotSupported) {
+			if err := handlers.SendBadRequest(w, r, "dealTypes contains an unsupported deal type",
+				models.NewServerError(ExtractionGapsDealTypeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return nil, false
+		}
+		if err := handlers.SendInternalServerError(w, r, "unable to Get Extraction Gaps",
+			models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+			gLogger.Errorln(err)
+		}
+		return nil, false
+	}
+
+	return plan, true
+}
+
+// sendConflict answers with a 409, for the requests that lost a race with
+// another caller
+func sendConflict(w http.ResponseWriter, serverError *models.ServerError) error {
+	return sendServerError(w, http.StatusConflict, serverError)
+}
+
+// sendServerError answers with the statuses handlers has no helper for
+func sendServerError(w http.ResponseWriter, status int, serverError *models.ServerError) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(serverError)
+}
+
+func makeGetTradeVersionDiffsHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+
+		dealTypeParam, ok := params["dealType"]
+		if !ok || dealTypeParam == "" {
+			if err := handlers.SendBadRequest(w, r, "dealType is required",
+				models.NewServerError(GetTradeVersionDiffsRequiredErrorCode, "dealType is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		dealKeyParam, ok := params["dealKey"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "dealKey is required",
+				models.NewServerError(GetTradeVersionDiffsRequiredErrorCode, "dealKey is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		dealKey, err := strconv.Atoi(dealKeyParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse dealKey as int",
+				models.NewServerError(GetTradeVersionDiffsDealKeyFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		diffs, err := repository.GetTradeVersionDiffs(r.Context(), dealKey, dealTypeParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Trade Version Diffs",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := handlers.SendOk(w, diffs); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+// makeGetProcessedTradesHandler reads the optional fromTradeDate, toTradeDate
+// (RFC3339), dealType, portfolioId, anomalyDetected, modelName, page and
+// pageSize query parameters
+func makeGetProcessedTradesHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		filter := power.NucleusProcessedTradeFilter{
+			DealType:  query.Get("dealType"),
+			ModelName: query.Get("modelName"),
+		}
+
+		for _, tradeDateParam := range []struct {
+			name   string
+			target **time.Time
+		}{
+			{name: "fromTradeDate", target: &filter.FromTradeDate},
+			{name: "toTradeDate", target: &filter.ToTradeDate},
+		} {
+			value := query.Get(tradeDateParam.name)
+			if value == "" {
+				continue
+			}
+			tradeDate, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				gLogger.Errorln(err)
+				if err := handlers.SendBadRequest(w, r, "failed to parse "+tradeDateParam.name+" as time in RFC3339",
+					models.NewServerError(GetProcessedTradesDateTimeFormatErrorCode, err.Error())); err != nil {
+					gLogger.Errorln(err)
+				}
+				return
+			}
+			*tradeDateParam.target = &tradeDate
+		}
+
+		if filter.FromTradeDate != nil && filter.ToTradeDate != nil && filter.FromTradeDate.After(*filter.ToTradeDate) {
+			if err := handlers.SendBadRequest(w, r, "fromTradeDate must not be after toTradeDate",
+				models.NewServerError(GetProcessedTradesInvalidRangeErrorCode, "fromTradeDate must not be after toTradeDate")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if portfolioIdParam := query.Get("portfolioId"); portfolioIdParam != "" {
+			portfolioId, err := strconv.Atoi(portfolioIdParam)
+			if err != nil {
+				gLogger.Errorln(err)
+				if err := handlers.SendBadRequest(w, r, "failed to parse portfolioId as int",
+					models.NewServerError(GetProcessedTradesFilterFormatErrorCode, err.Error())); err != nil {
+					gLogger.Errorln(err)
+				}
+				return
+			}
+			filter.PortfolioId = &portfolioId
+		}
+
+		if anomalyDetectedParam := query.Get("anomalyDetected"); anomalyDetectedParam != "" {
+			anomalyDetected, err := strconv.ParseBool(anomalyDetectedParam)
+			if err != nil {
+				gLogger.Errorln(err)
+				if err := handlers.SendBadRequest(w, r, "failed to parse anomalyDetected as bool",
+					models.NewServerError(GetProcessedTradesFilterFormatErrorCode, err.Error())); err != nil {
+					gLogger.Errorln(err)
+				}
+				return
+			}
+			filter.AnomalyDetectedFlag = &anomalyDetected
+		}
+
+		for _, pageParam := range []struct {
+			name   string
+			target *int
+		}{
+			{name: "page", target: &filter.Page},
+			{name: "pageSize", target: &filter.PageSize},
+		} {
+			value := query.Get(pageParam.name)
+			if value == "" {
+				continue
+			}
+			number, err := strconv.Atoi(value)
+			if err == nil && number <= 0 {
+				err = errors.New(pageParam.name + " must be positive")
+			}
+			if err != nil {
+				gLogger.Errorln(err)
+				if err := handlers.SendBadRequest(w, r, "failed to parse "+pageParam.name+" as a positive int",
+					models.NewServerError(GetProcessedTradesFilterFormatErrorCode, err.Error())); err != nil {
+					gLogger.Errorln(err)
+				}
+				return
+			}
+			*pageParam.target = number
+		}
+
+		processedTrades, err := repository.GetProcessedTrades(r.Context(), filter)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Processed Trades",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := handlers.SendOk(w, processedTrades); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetLastExtractionRunHandlerNucleus(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+
+		tradeDateParam, ok := params["tradeDate"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
+				models.NewServerError(GetLastExtractionRunDateRequiredErrorCode, "tradeDate is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
+				models.NewServerError(GetLastExtractionRunDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		dealTypeParam, ok := params["dealType"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "dealType is required",
+				models.NewServerError(GetLastExtractionRunDateRequiredErrorCode, "dealType is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		results, err := repository.GetLastExtractionRun(r.Context(), tradeDate, dealTypeParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Last Extraction Run",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := handlers.SendOk(w, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+type insertExtractionRunHandlerNucleusBody struct {
+	TradeDate time.Time `json:"tradeDate"`
+	DealType  string    `json:"dealType"`
+	LastRun   time.Time `json:"lastRun"`
+}
+
+func makeInsertExtractionRunHandlerNucleus(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		var data insertExtractionRunHandlerNucleusBody
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse body",
+				models.NewServerError(InsertExtractionRunBodyFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		if err := repository.InsertExtractionRun(r.Context(), data.TradeDate, data.LastRun, data.DealType); err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Insert Extraction Run",
+				models.NewServerError(EndureGasTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := handlers.SendOk(w, nil); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetPortfolioRiskMappingListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		results, err := repository.GetPortfolioRiskMappingList(r.Context())
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Portfolio Risk Mapping List",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := handlers.SendOk(w, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetLarBaselistHandlerNucleus(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		results, err := repository.GetLarBaselist(r.Context())
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Lar Baselist",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := handlers.SendOk(w, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetNucPowerDealByKeysHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		keysParamQuery, ok := query["keys"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "keys are required",
+				models.NewServerError(GetNucPowerDealByKeysRequiredErrorCode, "Keys are required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		var keysParam []float64
+		for _, key := range keysParamQuery {
+			keyFloat64, err := strconv.ParseFloat(key, 64)
+			if err != nil {
+				if err := handlers.SendBadRequest(w, r, "keys must be float64",
+					models.NewServerError(GetNucPowerDealByKeysFormatErrorCode, "keys must be float64")); err != nil {
+					gLogger.Errorln(err)
+				}
+			}
+			keysParam = append(keysParam, keyFloat64)
+		}
+
+		results, err := repository.GetNucPowerDealByKeys(r.Context(), keysParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Nuc Power Deal By Keys",
+				models.NewServerError(EndureGasTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetNucPowerSwapDealByKeysHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		keysParamQuery, ok := query["keys"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "keys are required",
+				models.NewServerError(GetNucPowerSwapDealByKeysRequiredErrorCode, "Keys are required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		var keysParam []float64
+		for _, key := range keysParamQuery {
+			keyFloat64, err := strconv.ParseFloat(key, 64)
+			if err != nil {
+				if err := handlers.SendBadRequest(w, r, "keys must be float64",
+					models.NewServerError(GetNucPowerSwapDealByKeysFormatErrorCode, "keys must be float64")); err != nil {
+					gLogger.Errorln(err)
+				}
+			}
+			keysParam = append(keysParam, keyFloat64)
+		}
+
+		results, err := repository.GetNucPowerSwapDealByKeys(r.Context(), keysParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Nuc Power Swap Deal By Keys",
+				models.NewServerError(EndureGasTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetNucPowerOptionsDealByKeysHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		keysParamQuery, ok := query["keys"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "keys are required",
+				models.NewServerError(GetNucPowerOptionsDealByKeysRequiredErrorCode, "Keys are required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		var keysParam []float64
+		for _, key := range keysParamQuery {
+			keyFloat64, err := strconv.ParseFloat(key, 64)
+			if err != nil {
+				if err := handlers.SendBadRequest(w, r, "keys must be float64",
+					models.NewServerError(GetNucPowerOptionsDealByKeysFormatErrorCode, "keys must be float64")); err != nil {
+					gLogger.Errorln(err)
+				}
+			}
+			keysParam = append(keysParam, keyFloat64)
+		}
+
+		results, err := repository.GetNucPowerOptionsDealByKeys(r.Context(), keysParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Nuc Power Options Deal By Keys",
+				models.NewServerError(EndureGasTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetNucCapacityDealByKeysHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		keysParamQuery, ok := query["keys"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "keys are required",
+				models.NewServerError(GetNucCapacityDealByKeysRequiredErrorCode, "Keys are required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		var keysParam []float64
+		for _, key := range keysParamQuery {
+			keyFloat64, err := strconv.ParseFloat(key, 64)
+			if err != nil {
+				if err := handlers.SendBadRequest(w, r, "keys must be float64",
+					models.NewServerError(GetNucCapacityDealByKeysFormatErrorCode, "keys must be float64")); err != nil {
+					gLogger.Errorln(err)
+				}
+				return
+			}
+			keysParam = append(keysParam, keyFloat64)
+		}
+
+		results, err := repository.GetNucCapacityDealByKeys(r.Context(), keysParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Nuc Capacity Deal By Keys",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetNucEmissionDealByKeysHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		keysParamQuery, ok := query["keys"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "keys are required",
+				models.NewServerError(GetNucEmissionDealByKeysRequiredErrorCode, "Keys are required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		var keysParam []float64
+		for _, key := range keysParamQuery {
+			keyFloat64, err := strconv.ParseFloat(key, 64)
+			if err != nil {
+				if err := handlers.SendBadRequest(w, r, "keys must be float64",
+					models.NewServerError(GetNucEmissionDealByKeysFormatErrorCode, "keys must be float64")); err != nil {
+					gLogger.Errorln(err)
+				}
+				return
+			}
+			keysParam = append(keysParam, keyFloat64)
+		}
+
+		results, err := repository.GetNucEmissionDealByKeys(r.Context(), keysParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Nuc Emission Deal By Keys",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetNucEmissionOptionDealByKeysHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		keysParamQuery, ok := query["keys"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "keys are required",
+				models.NewServerError(GetNucEmissionOptionDealByKeysRequiredErrorCode, "Keys are required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		var keysParam []float64
+		for _, key := range keysParamQuery {
+			keyFloat64, err := strconv.ParseFloat(key, 64)
+			if err != nil {
+				if err := handlers.SendBadRequest(w, r, "keys must be float64",
+					models.NewServerError(GetNucEmissionOptionDealByKeysFormatErrorCode, "keys must be float64")); err != nil {
+					gLogger.Errorln(err)
+				}
+				return
+			}
+			keysParam = append(keysParam, keyFloat64)
+		}
+
+		results, err := repository.GetNucEmissionOptionDealByKeys(r.Context(), keysParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Nuc Emission Option Deal By Keys",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetNucTCCFTRSDealByKeysHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		keysParamQuery, ok := query["keys"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "keys are required",
+				models.NewServerError(GetNucTCCFTRSDealByKeysRequiredErrorCode, "Keys are required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		var keysParam []float64
+		for _, key := range keysParamQuery {
+			keyFloat64, err := strconv.ParseFloat(key, 64)
+			if err != nil {
+				if err := handlers.SendBadRequest(w, r, "keys must be float64",
+					models.NewServerError(GetNucTCCFTRSDealByKeysFormatErrorCode, "keys must be float64")); err != nil {
+					gLogger.Errorln(err)
+				}
+				return
+			}
+			keysParam = append(keysParam, keyFloat64)
+		}
+
+		dealType := query.Get("dealType")
+		if dealType != "FTROPT" && dealType != "FTRSWP" && dealType != "TCCSWP" {
+			if err := handlers.SendBadRequest(w, r, "dealType must be one of FTROPT, FTRSWP or TCCSWP",
+				models.NewServerError(GetNucTCCFTRSDealByKeysDealTypeFormatErrorCode, "dealType must be one of FTROPT, FTRSWP or TCCSWP")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		results, err := repository.GetNucTCCFTRSDealByKeys(r.Context(), keysParam, dealType)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Nuc TCCFTRS Deal By Keys",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetNucSpreadOptionsDealByKeysHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		keysParamQuery, ok := query["keys"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "keys are required",
+				models.NewServerError(GetNucSpreadOptionsDealByKeysRequiredErrorCode, "Keys are required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		var keysParam []float64
+		for _, key := range keysParamQuery {
+			keyFloat64, err := strconv.ParseFloat(key, 64)
+			if err != nil {
+				if err := handlers.SendBadRequest(w, r, "keys must be float64",
+					models.NewServerError(GetNucSpreadOptionsDealByKeysFormatErrorCode, "keys must be float64")); err != nil {
+					gLogger.Errorln(err)
+				}
+				return
+			}
+			keysParam = append(keysParam, keyFloat64)
+		}
+
+		results, err := repository.GetNucSpreadOptionsDealByKeys(r.Context(), keysParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Nuc Spread Options Deal By Keys",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetNucHeatRateSwapsDealByKeysHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		keysParamQuery, ok := query["keys"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "keys are required",
+				models.NewServerError(GetNucHeatRateSwapsDealByKeysRequiredErrorCode, "Keys are required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		var keysParam []float64
+		for _, key := range keysParamQuery {
+			keyFloat64, err := strconv.ParseFloat(key, 64)
+			if err != nil {
+				if err := handlers.SendBadRequest(w, r, "keys must be float64",
+					models.NewServerError(GetNucHeatRateSwapsDealByKeysFormatErrorCode, "keys must be float64")); err != nil {
+					gLogger.Errorln(err)
+				}
+				return
+			}
+			keysParam = append(keysParam, keyFloat64)
+		}
+
+		results, err := repository.GetNucHeatRateSwapsDealByKeys(r.Context(), keysParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Nuc Heat Rate Swaps Deal By Keys",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetNucTransmissionDealByKeysHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		keysParamQuery, ok := query["keys"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "keys are required",
+				models.NewServerError(GetNucTransmissionDealByKeysRequiredErrorCode, "Keys are required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		var keysParam []float64
+		for _, key := range keysParamQuery {
+			keyFloat64, err := strconv.ParseFloat(key, 64)
+			if err != nil {
+				if err := handlers.SendBadRequest(w, r, "keys must be float64",
+					models.NewServerError(GetNucTransmissionDealByKeysFormatErrorCode, "keys must be float64")); err != nil {
+					gLogger.Errorln(err)
+				}
+				return
+			}
+			keysParam = append(keysParam, keyFloat64)
+		}
+
+		results, err := repository.GetNucTransmissionDealByKeys(r.Context(), keysParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Nuc Transmission Deal By Keys",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetNucPTPDealByKeysHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		keysParamQuery, ok := query["keys"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "keys are required",
+				models.NewServerError(GetNucPTPDealByKeysRequiredErrorCode, "Keys are required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		var keysParam []float64
+		for _, key := range keysParamQuery {
+			keyFloat64, err := strconv.ParseFloat(key, 64)
+			if err != nil {
+				if err := handlers.SendBadRequest(w, r, "keys must be float64",
+					models.NewServerError(GetNucPTPDealByKeysFormatErrorCode, "keys must be float64")); err != nil {
+					gLogger.Errorln(err)
+				}
+				return
+			}
+			keysParam = append(keysParam, keyFloat64)
+		}
+
+		results, err := repository.GetNucPTPDealByKeys(r.Context(), keysParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Nuc PTP Deal By Keys",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetNucMiscChargeDealByKeysHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		keysParamQuery, ok := query["keys"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "keys are required",
+				models.NewServerError(GetNucMiscChargeDealByKeysRequiredErrorCode, "Keys are required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		var keysParam []float64
+		for _, key := range keysParamQuery {
+			keyFloat64, err := strconv.ParseFloat(key, 64)
+			if err != nil {
+				if err := handlers.SendBadRequest(w, r, "keys must be float64",
+					models.NewServerError(GetNucMiscChargeDealByKeysFormatErrorCode, "keys must be float64")); err != nil {
+					gLogger.Errorln(err)
+				}
+				return
+			}
+			keysParam = append(keysParam, keyFloat64)
+		}
+
+		results, err := repository.GetNucMiscChargeDealByKeys(r.Context(), keysParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Nuc Misc Charge Deal By Keys",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+type getNucDealsByTypeAndKeysHandlerBody struct {
+	Deals []*power.NucleusDealLookupKey `json:"deals"`
+}
+
+type getNucDealsByTypeAndKeysHandlerResult struct {
+	Deals    []*nucleus.NucleusTradeHeaderModel `json:"deals"`
+	NotFound []*power.NucleusDealLookupMiss     `json:"notFound"`
+}
+
+func makeGetNucDealsByTypeAndKeysHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		var data getNucDealsByTypeAndKeysHandlerBody
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse body",
+				models.NewServerError(GetNucDealsByTypeAndKeysBodyFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if len(data.Deals) == 0 {
+			if err := handlers.SendBadRequest(w, r, "deals are required",
+				models.NewServerError(GetNucDealsByTypeAndKeysRequiredErrorCode, "deals are required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		for index, deal := range data.Deals {
+			if deal == nil {
+				if err := handlers.SendBadRequest(w, r, "deals can't contain null",
+					models.NewServerError(GetNucDealsByTypeAndKeysBodyFormatErrorCode, "deal "+strconv.Itoa(index)+" is null")); err != nil {
+					gLogger.Errorln(err)
+				}
+				return
+			}
+		}
+
+		results, misses, err := repository.GetNucDealsByTypeAndKeys(r.Context(), data.Deals)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Get Nuc Deals By Type And Keys",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := handlers.SendOk(w, getNucDealsByTypeAndKeysHandlerResult{
+			Deals:    results,
+			NotFound: misses,
+		}); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+// parseTradeDateRangeRequest reads the lastRunTime path variable and the
+// fromTradeDate/toTradeDate query parameters, all in RFC3339. When it returns
+// false the bad request has already been sent
+func parseTradeDateRangeRequest(logger logger.Logger, w http.ResponseWriter, r *http.Request) (lastRunTime time.Time, fromTradeDate time.Time, toTradeDate time.Time, ok bool) {
+	gLogger := logger.GetLogger()
+
+	lastRunTimeParam, ok := mux.Vars(r)["lastRunTime"]
+	if !ok {
+		if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
+			models.NewServerError(GetNucDealListByTradeDateRangeTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
+			gLogger.Errorln(err)
+		}
+		return lastRunTime, fromTradeDate, toTradeDate, false
+	}
+	lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
+	if err != nil {
+		gLogger.Errorln(err)
+		if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
+			models.NewServerError(GetNucDealListByTradeDateRangeDateTimeFormatErrorCode, err.Error())); err != nil {
+			gLogger.Errorln(err)
+		}
+		return lastRunTime, fromTradeDate, toTradeDate, false
+	}
+
+	fromTradeDate, toTradeDate, ok = parseTradeDateRangeQuery(logger, w, r)
+	return lastRunTime, fromTradeDate, toTradeDate, ok
+}
+
+// parseTradeDateRangeQuery reads the fromTradeDate/toTradeDate query
+// parameters in RFC3339. When it returns false the bad request has already
+// been sent
+func parseTradeDateRangeQuery(logger logger.Logger, w http.ResponseWriter, r *http.Request) (fromTradeDate time.Time, toTradeDate time.Time, ok bool) {
+	gLogger := logger.GetLogger()
+
+	query := r.URL.Query()
+
+	fromTradeDateParam := query.Get("fromTradeDate")
+	if fromTradeDateParam == "" {
+		if err := handlers.SendBadRequest(w, r, "fromTradeDate is required",
+			models.NewServerError(GetNucDealListByTradeDateRangeTradeDateRequiredErrorCode, "fromTradeDate is required")); err != nil {
+			gLogger.Errorln(err)
+		}
+		return fromTradeDate, toTradeDate, false
+	}
+	fromTradeDate, err := time.Parse(time.RFC3339, fromTradeDateParam)
+	if err != nil {
+		gLogger.Errorln(err)
+		if err := handlers.SendBadRequest(w, r, "failed to parse fromTradeDate as time in RFC3339",
+			models.NewServerError(GetNucDealListByTradeDateRangeDateTimeFormatErrorCode, err.Error())); err != nil {
+			gLogger.Errorln(err)
+		}
+		return fromTradeDate, toTradeDate, false
+	}
+
+	toTradeDateParam := query.Get("toTradeDate")
+	if toTradeDateParam == "" {
+		if err := handlers.SendBadRequest(w, r, "toTradeDate is required",
+			models.NewServerError(GetNucDealListByTradeDateRangeTradeDateRequiredErrorCode, "toTradeDate is required")); err != nil {
+			gLogger.Errorln(err)
+		}
+		return fromTradeDate, toTradeDate, false
+	}
+	toTradeDate, err = time.Parse(time.RFC3339, toTradeDateParam)
+	if err != nil {
+		gLogger.Errorln(err)
+		if err := handlers.SendBadRequest(w, r, "failed to parse toTradeDate as time in RFC3339",
+			models.NewServerError(GetNucDealListByTradeDateRangeDateTimeFormatErrorCode, err.Error())); err != nil {
+			gLogger.Errorln(err)
+		}
+		return fromTradeDate, toTradeDate, false
+	}
+
+	if fromTradeDate.After(toTradeDate) {
+		if err := handlers.SendBadRequest(w, r, "fromTradeDate must not be after toTradeDate",
+			models.NewServerError(GetNucDealListByTradeDateRangeInvalidRangeErrorCode, "fromTradeDate must not be after toTradeDate")); err != nil {
+			gLogger.Errorln(err)
+		}
+		return fromTradeDate, toTradeDate, false
+	}
+
+	return fromTradeDate, toTradeDate, true
+}
+
+func makeGetNucDealListByTradeDateRangeHandler(logger logger.Logger, errorMessage string, getDealList func(ctx context.Context, lastRunTime time.Time, fromTradeDate time.Time, toTradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error)) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		lastRunTime, fromTradeDate, toTradeDate, ok := parseTradeDateRangeRequest(logger, w, r)
+		if !ok {
+			return
+		}
+
+		results, err := getDealList(r.Context(), lastRunTime, fromTradeDate, toTradeDate)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, errorMessage,
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := sendDealList(w, r, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeGetNucTCCFTRSDealListByTradeDateRangeHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		dealType := mux.Vars(r)["dealType"]
+		if dealType != "FTROPT" && dealType != "FTRSWP" && dealType != "TCCSWP" {
+			if err := handlers.SendBadRequest(w, r, "dealType must be one of FTROPT, FTRSWP or TCCSWP",
+				models.NewServerError(GetNucTCCFTRSDealListByTradeDateRangeDealTypeFormatErrorCode, "dealType must be one of FTROPT, FTRSWP or TCCSWP")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		getDealList := func(ctx context.Context, lastRunTime time.Time, fromTradeDate time.Time, toTradeDate time.Time) ([]*nucleus.NucleusTradeHeaderModel, error) {
+			return repository.GetNucTCCFTRSDealListByTradeDateRange(ctx, lastRunTime, fromTradeDate, toTradeDate, dealType)
+		}
+
+		makeGetNucDealListByTradeDateRangeHandler(logger, "unable to Get Nucleus TCCFTRS DealList By Trade Date Range", getDealList)(w, r)
+	}
+}
+
+func makeGetAllDealListsHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+
+		lastRunTimeParam, ok := params["lastRunTime"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
+				models.NewServerError(GetAllDealListsTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
+				models.NewServerError(GetAllDealListsDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		tradeDateParam, ok := params["tradeDate"]
+		if !ok {
+			if err := handlers.SendBadRequest(w, r, "tradeDate is required",
+				models.NewServerError(GetAllDealListsTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		tradeDate, err := time.Parse(time.RFC3339, tradeDateParam)
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
+				models.NewServerError(GetAllDealListsDateTimeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		// a failing deal type is reported in its own result, so the
+		// response is sent even when some of the extractions failed
+		results := repository.GetAllNucDealLists(r.Context(), lastRunTime, tradeDate)
+
+		if err := handlers.SendOk(w, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+// dealListFormat is the representation of a deal list response, picked from
+// the Accept header of the request
+type dealListFormat string
+
+const (
+	dealListFormatJSON   dealListFormat = "application/json"
+	dealListFormatNDJSON dealListFormat = "application/x-ndjson"
+	dealListFormatCSV    dealListFormat = "text/csv"
+)
+
+// negotiateDealListFormat returns the supported deal list format with the
+// highest quality value in the Accept header, the first one listed on a tie.
+// JSON stays the default when nothing else is asked for
+func negotiateDealListFormat(r *http.Request) dealListFormat {
+	format := dealListFormatJSON
+	bestQuality := 0.0
+
+	for _, mediaRange := range strings.Split(r.Header.Get("Accept"), ",") {
+		params := strings.Split(mediaRange, ";")
+
+		var mediaFormat dealListFormat
+		switch strings.ToLower(strings.TrimSpace(params[0])) {
+		case string(dealListFormatNDJSON), "application/ndjson":
+			mediaFormat = dealListFormatNDJSON
+		case string(dealListFormatCSV):
+			mediaFormat = dealListFormatCSV
+		case string(dealListFormatJSON), "application/*", "*/*":
+			mediaFormat = dealListFormatJSON
+		default:
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range params[1:] {
+			nameValue := strings.SplitN(param, "=", 2)
+			if len(nameValue) != 2 || strings.ToLower(strings.TrimSpace(nameValue[0])) != "q" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(nameValue[1]), 64)
+			if err != nil {
+				parsed = 0
+			}
+			quality = parsed
+		}
+
+		if quality > bestQuality {
+			format = mediaFormat
+			bestQuality = quality
+		}
+	}
+
+	return format
+}
+
+// sendDealList sends the deals in the format negotiated with the client
+func sendDealList(w http.ResponseWriter, r *http.Request, headerModels []*nucleus.NucleusTradeHeaderModel) error {
+	format := negotiateDealListFormat(r)
+	if format == dealListFormatJSON {
+		return handlers.SendOk(w, headerModels)
+	}
+
+	streamWriter := newDealListStreamWriter(w, format)
+	for _, headerModel := range headerModels {
+		if err := streamWriter.write(headerModel); err != nil {
+			return err
+		}
+	}
+
+	return streamWriter.close()
+}
+
+// dealListStreamWriter writes the deals to the response while they are
+// scanned, as a JSON array, NDJSON or CSV. The status and headers are only
+// sent with the first deal, so a failure before that can still be answered
+// with an error response
+type dealListStreamWriter struct {
+	w         http.ResponseWriter
+	flusher   http.Flusher
+	format    dealListFormat
+	csvWriter *csv.Writer
+	count     int
+}
+
+func newDealListStreamWriter(w http.ResponseWriter, format dealListFormat) *dealListStreamWriter {
+	flusher, _ := w.(http.Flusher)
+
+	streamWriter := &dealListStreamWriter{
+		w:       w,
+		flusher: flusher,
+		format:  format,
+	}
+	if format == dealListFormatCSV {
+		streamWriter.csvWriter = csv.NewWriter(w)
+	}
+
+	return streamWriter
+}
+
+func (s *dealListStreamWriter) started() bool {
+	return s.count > 0
+}
+
+func (s *dealListStreamWriter) writeHeader() error {
+	s.w.Header().Set("Content-Type", string(s.format))
+	s.w.WriteHeader(http.StatusOK)
+
+	switch s.format {
+	case dealListFormatJSON:
+		_, err := io.WriteString(s.w, "[")
+		return err
+	case dealListFormatCSV:
+		return s.csvWriter.Write(dealListCSVColumns.names)
+	}
+
+	return nil
+}
+
+func (s *dealListStreamWriter) write(headerModel *nucleus.NucleusTradeHeaderModel) error {
+	if !s.started() {
+		if err := s.writeHeader(); err != nil {
+			return err
+		}
+	}
+
+	switch s.format {
+	case dealListFormatCSV:
+		if err := s.csvWriter.WriteAll(dealListCSVColumns.records(headerModel)); err != nil {
+			return err
+		}
+	default:
+		body, err := json.Marshal(headerModel)
+		if err != nil {
+			return err
+		}
+
+		var separator string
+		if s.format == dealListFormatJSON && s.started() {
+			separator = ","
+		}
+		if s.format == dealListFormatNDJSON {
+			body = append(body, '\n')
+		}
+
+		if _, err := io.WriteString(s.w, separator); err != nil {
+			return err
+		}
+		if _, err := s.w.Write(body); err != nil {
+			return err
+		}
+	}
+
+	s.count++
+	if s.flusher != nil && s.count%dealListStreamFlushSize == 0 {
+		// the csv writer buffers its records, they have to reach the
+		// response before it is flushed
+		if s.csvWriter != nil {
+			s.csvWriter.Flush()
+			if err := s.csvWriter.Error(); err != nil {
+				return err
+			}
+		}
+		s.flusher.Flush()
+	}
+
+	return nil
+}
+
+func (s *dealListStreamWriter) close() error {
+	if !s.started() {
+		if err := s.writeHeader(); err != nil {
+			return err
+		}
+	}
+
+	if s.format == dealListFormatJSON {
+		if _, err := io.WriteString(s.w, "]"); err != nil {
+			return err
+		}
+	}
+
+	if s.csvWriter != nil {
+		s.csvWriter.Flush()
+		if err := s.csvWriter.Error(); err != nil {
+			return err
+		}
+	}
+
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+
+	return nil
+}
+
+// dealListCSVColumns flattens the deals in one CSV row per term. The columns
+// come from the json names of the model types, so every product shares them:
+// the header fields, then the term fields prefixed with "terms." and the
+// index fields prefixed with "terms.indexes1." and "terms.indexes2.". The
+// values of the indexes of a term are joined with "|"
+var dealListCSVColumns = newDealListCSVLayout()
+
+var (
+	termModelsType  = reflect.TypeOf([]*nucleus.NucleusTradeTermModel{})
+	indexModelsType = reflect.TypeOf([]*nucleus.NucleusTradeIndexModel{})
+	timeType        = reflect.TypeOf(time.Time{})
+)
+
+// dealListCSVIndexMultiValueSeparator joins the values of the indexes of a term
+const dealListCSVIndexMultiValueSeparator = "|"
+
+type dealListCSVLayout struct {
+	names        []string
+	headerFields [][]int
+	termsField   []int
+	termFields   [][]int
+	indexFields  []dealListCSVIndexFields
+}
+
+type dealListCSVIndexFields struct {
+	indexesField []int
+	fields       [][]int
+}
+
+func newDealListCSVLayout() *dealListCSVLayout {
+	layout := &dealListCSVLayout{}
+
+	headerType := reflect.TypeOf(nucleus.NucleusTradeHeaderModel{})
+	for _, field := range csvFields(headerType) {
+		if field.Type == termModelsType {
+			layout.termsField = field.Index
+			continue
+		}
+		if isCSVScalar(field.Type) {
+			layout.names = append(layout.names, csvColumnName(field))
+			layout.headerFields = append(layout.headerFields, field.Index)
+		}
+	}
+
+	termType := reflect.TypeOf(nucleus.NucleusTradeTermModel{})
+	var indexesFields []reflect.StructField
+	for _, field := range csvFields(termType) {
+		if field.Type == indexModelsType {
+			indexesFields = append(indexesFields, field)
+			continue
+		}
+		if isCSVScalar(field.Type) {
+			layout.names = append(layout.names, "terms."+csvColumnName(field))
+			layout.termFields = append(layout.termFields, field.Index)
+		}
+	}
+
+	indexType := reflect.TypeOf(nucleus.NucleusTradeIndexModel{})
+	for _, indexesField := range indexesFields {
+		indexFields := dealListCSVIndexFields{indexesField: indexesField.Index}
+		for _, field := range csvFields(indexType) {
+			if isCSVScalar(field.Type) {
+				layout.names = append(layout.names, "terms."+csvColumnName(indexesField)+"."+csvColumnName(field))
+				indexFields.fields = append(indexFields.fields, field.Index)
+			}
+		}
+		layout.indexFields = append(layout.indexFields, indexFields)
+	}
+
+	return layout
+}
+
+// records returns one record per term of the deal, or a single record with
+// empty term columns when the deal has no terms
+func (l *dealListCSVLayout) records(headerModel *nucleus.NucleusTradeHeaderModel) [][]string {
+	header := reflect.ValueOf(headerModel).Elem()
+
+	headerValues := make([]string, 0, len(l.headerFields))
+	for _, index := range l.headerFields {
+		headerValues = append(headerValues, csvValue(header.FieldByIndex(index)))
+	}
+
+	var terms reflect.Value
+	if l.termsField != nil {
+		terms = header.FieldByIndex(l.termsField)
+	}
+
+	if !terms.IsValid() || terms.Len() == 0 {
+		record := make([]string, len(l.names))
+		copy(record, headerValues)
+		return [][]string{record}
+	}
+
+	records := make([][]string, 0, terms.Len())
+	for termIndex := 0; termIndex < terms.Len(); termIndex++ {
+		record := make([]string, 0, len(l.names))
+		record = append(record, headerValues...)
+
+		term := terms.Index(termIndex)
+		if term.IsNil() {
+			records = append(records, append(record, make([]string, len(l.names)-len(record))...))
+			continue
+		}
+		term = term.Elem()
+
+		for _, index := range l.termFields {
+			record = append(record, csvValue(term.FieldByIndex(index)))
+		}
+
+		for _, indexFields := range l.indexFields {
+			indexes := term.FieldByIndex(indexFields.indexesField)
+			for _, index := range indexFields.fields {
+				var values []string
+				for position := 0; position < indexes.Len(); position++ {
+					indexModel := indexes.Index(position)
+					if indexModel.IsNil() {
+						values = append(values, "")
+						continue
+					}
+					values = append(values, csvValue(indexModel.Elem().FieldByIndex(index)))
+				}
+				record = append(record, strings.Join(values, dealListCSVIndexMultiValueSeparator))
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// csvFields returns the exported fields of the struct in declaration order,
+// leaving out the ones hidden from json
+func csvFields(structType reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+	for index := 0; index < structType.NumField(); index++ {
+		field := structType.Field(index)
+		if field.PkgPath != "" || field.Tag.Get("json") == "-" {
+			continue
+		}
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+func csvColumnName(field reflect.StructField) string {
+	if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "" {
+		return name
+	}
+
+	return field.Name
+}
+
+func isCSVScalar(fieldType reflect.Type) bool {
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+	if fieldType == timeType {
+		return true
+	}
+
+	switch fieldType.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+func csvValue(value reflect.Value) string {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return ""
+		}
+		value = value.Elem()
+	}
+
+	if value.Type() == timeType {
+		return value.Interface().(time.Time).Format(time.RFC3339)
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return value.String()
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64)
+	}
+
+	return ""
+}
+
+// parseDealListRequest reads the lastRunTime and tradeDate path variables in
+// RFC3339. When it returns false the bad request has already been sent
+func parseDealListRequest(logger logger.Logger, w http.ResponseWriter, r *http.Request) (lastRunTime time.Time, tradeDate time.Time, ok bool) {
+	gLogger := logger.GetLogger()
+	params := mux.Vars(r)
+
+	lastRunTimeParam, ok := params["lastRunTime"]
+	if !ok {
+		if err := handlers.SendBadRequest(w, r, "lastRunTime is required",
+			models.NewServerError(StreamNucDealListTradeDateRequiredErrorCode, "lastRunTime is required")); err != nil {
+			gLogger.Errorln(err)
+		}
+		return lastRunTime, tradeDate, false
+	}
+	lastRunTime, err := time.Parse(time.RFC3339, lastRunTimeParam)
+	if err != nil {
+		gLogger.Errorln(err)
+		if err := handlers.SendBadRequest(w, r, "failed to parse lastRunTime as time in RFC3339",
+			models.NewServerError(StreamNucDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+			gLogger.Errorln(err)
+		}
+		return lastRunTime, tradeDate, false
+	}
+
+	tradeDateParam, ok := params["tradeDate"]
+	if !ok {
+		if err := handlers.SendBadRequest(w, r, "tradeDate is required",
+			models.NewServerError(StreamNucDealListTradeDateRequiredErrorCode, "tradeDate is required")); err != nil {
+			gLogger.Errorln(err)
+		}
+		return lastRunTime, tradeDate, false
+	}
+	tradeDate, err = time.Parse(time.RFC3339, tradeDateParam)
+	if err != nil {
+		gLogger.Errorln(err)
+		if err := handlers.SendBadRequest(w, r, "failed to parse tradeDate as time in RFC3339",
+			models.NewServerError(StreamNucDealListDateTimeFormatErrorCode, err.Error())); err != nil {
+			gLogger.Errorln(err)
+		}
+		return lastRunTime, tradeDate, false
+	}
+
+	return lastRunTime, tradeDate, true
+}
+
+func makeStreamNucDealListHandler(logger logger.Logger, errorMessage string, streamDealList func(ctx context.Context, lastRunTime time.Time, tradeDate time.Time, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		lastRunTime, tradeDate, ok := parseDealListRequest(logger, w, r)
+		if !ok {
+			return
+		}
+
+		streamWriter := newDealListStreamWriter(w, negotiateDealListFormat(r))
+
+		if err := streamDealList(r.Context(), lastRunTime, tradeDate, streamWriter.write); err != nil {
+			gLogger.Errorln(err)
+			// once the first deal is written the status can't change anymore,
+			// the connection is aborted so the client sees the response failed
+			// instead of a truncated body that may look complete
+			if streamWriter.started() {
+				panic(http.ErrAbortHandler)
+			}
+			if err := handlers.SendInternalServerError(w, r, errorMessage,
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := streamWriter.close(); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
+
+func makeStreamNucTCCFTRSDealListHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		dealType := mux.Vars(r)["dealType"]
+		if dealType != "FTROPT" && dealType != "FTRSWP" && dealType != "TCCSWP" {
+			if err := handlers.SendBadRequest(w, r, "dealType must be one of FTROPT, FTRSWP or TCCSWP",
+				models.NewServerError(StreamNucTCCFTRSDealListDealTypeFormatErrorCode, "dealType must be one of FTROPT, FTRSWP or TCCSWP")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		streamDealList := func(ctx context.Context, lastRunTime time.Time, tradeDate time.Time, yield func(headerModel *nucleus.NucleusTradeHeaderModel) error) error {
+			return repository.StreamNucTCCFTRSDealList(ctx, lastRunTime, tradeDate, dealType, yield)
+		}
+
+		makeStreamNucDealListHandler(logger, "unable to Stream Nucleus TCCFTRS DealList", streamDealList)(w, r)
+	}
+}
+
+func makeExportDealListsParquetHandler(logger logger.Logger, repository power.INucleusTradeRepository) func(http.ResponseWriter, *http.Request) {
+	gLogger := logger.GetLogger()
+	return func(w http.ResponseWriter, r *http.Request) {
+		fromTradeDate, toTradeDate, ok := parseTradeDateRangeQuery(logger, w, r)
+		if !ok {
+			return
+		}
+		if toTradeDate.Sub(fromTradeDate) > maxExportDealListsDays*24*time.Hour {
+			if err := handlers.SendBadRequest(w, r, "toTradeDate must not be more than a month after fromTradeDate",
+				models.NewServerError(ExportDealListsParquetInvalidRangeErrorCode, "invalid trade date range")); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		// dealTypes is optional, every deal type is exported without it
+		var dealTypes []string
+		if dealTypesParam := r.URL.Query().Get("dealTypes"); dealTypesParam != "" {
+			dealTypes = strings.Split(dealTypesParam, ",")
+		}
+
+		outputDir := os.Getenv(parquetExportDirEnv)
+		if outputDir == "" {
+			outputDir = defaultParquetExportDir
+		}
+
+		results, err := repository.ExportNucDealListsToParquet(r.Context(), fromTradeDate, toTradeDate, dealTypes, outputDir)
+		if errors.Is(err, power.ErrNucDealTypeNotSupported) {
+			if err := handlers.SendBadRequest(w, r, "dealTypes contains an unsupported deal type",
+				models.NewServerError(ExportDealListsParquetDealTypeFormatErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+		if err != nil {
+			gLogger.Errorln(err)
+			if err := handlers.SendInternalServerError(w, r, "unable to Export Nucleus DealLists to Parquet",
+				models.NewServerError(NucleusTradeRepoErrorCode, err.Error())); err != nil {
+				gLogger.Errorln(err)
+			}
+			return
+		}
+
+		if err := handlers.SendOk(w, results); err != nil {
+			gLogger.Errorln(err)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/sede-x/RogerRogerAnomalyDetector/models/nucleus"
+)
+
+func Test_negotiateDealListFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		want   dealListFormat
+	}{
+		{name: "no Accept header", accept: "", want: dealListFormatJSON},
+		{name: "json", accept: "application/json", want: dealListFormatJSON},
+		{name: "ndjson", accept: "application/x-ndjson", want: dealListFormatNDJSON},
+		{name: "csv", accept: "text/csv", want: dealListFormatCSV},
+		{name: "first supported type", accept: "text/html, text/csv, application/json", want: dealListFormatCSV},
+		{name: "highest quality", accept: "application/json;q=0.5, text/csv;q=0.9", want: dealListFormatCSV},
+		{name: "wildcard with lower quality", accept: "*/*;q=0.1, application/x-ndjson", want: dealListFormatNDJSON},
+		{name: "refused type", accept: "text/csv;q=0, application/x-ndjson;q=0.2", want: dealListFormatNDJSON},
+		{name: "unsupported types only", accept: "text/html, application/xml", want: dealListFormatJSON},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set("Accept", tt.accept)
+
+			if got := negotiateDealListFormat(r); got != tt.want {
+				t.Errorf("negotiateDealListFormat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_dealListCSVLayout(t *testing.T) {
+	columns := make(map[string]int)
+	for position, name := range dealListCSVColumns.names {
+		if _, ok := columns[name]; ok {
+			t.Fatalf("column %s is duplicated", name)
+		}
+		columns[name] = position
+	}
+
+	for _, name := range []string{"terms", "terms.indexes1", "terms.indexes2"} {
+		if _, ok := columns[name]; ok {
+			t.Errorf("column %s isn't flattened", name)
+		}
+	}
+
+	// the header columns come first, then the term and the index columns
+	ordered := []string{"dealKey", "dealType", "terms.volSeq", "terms.product1", "terms.indexes1.pubIndex", "terms.indexes2.pubIndex"}
+	for index, name := range ordered {
+		position, ok := columns[name]
+		if !ok {
+			t.Fatalf("column %s is missing", name)
+		}
+		if index > 0 && position <= columns[ordered[index-1]] {
+			t.Errorf("column %s is at %d, before %s at %d", name, position, ordered[index-1], columns[ordered[index-1]])
+		}
+	}
+
+	headerModel := &nucleus.NucleusTradeHeaderModel{
+		DealKey:  42,
+		DealType: "POWER",
+		Terms: []*nucleus.NucleusTradeTermModel{
+			{
+				VolSeq:   1,
+				Product1: "PEAK",
+				Indexes1: []*nucleus.NucleusTradeIndexModel{
+					{PubIndex: "PJM-W"},
+					{PubIndex: "PJM-E"},
+				},
+			},
+			{
+				VolSeq:   2,
+				Product1: "OFFPEAK",
+			},
+		},
+	}
+
+	records := dealListCSVColumns.records(headerModel)
+	if len(records) != 2 {
+		t.Fatalf("records() returned %d records, want one per term", len(records))
+	}
+
+	want := []map[string]string{
+		{"dealKey": "42", "dealType": "POWER", "terms.volSeq": "1", "terms.product1": "PEAK", "terms.indexes1.pubIndex": "PJM-W|PJM-E", "terms.indexes2.pubIndex": ""},
+		{"dealKey": "42", "dealType": "POWER", "terms.volSeq": "2", "terms.product1": "OFFPEAK", "terms.indexes1.pubIndex": "", "terms.indexes2.pubIndex": ""},
+	}
+	for index, record := range records {
+		if len(record) != len(dealListCSVColumns.names) {
+			t.Fatalf("record %d has %d values, want %d", index, len(record), len(dealListCSVColumns.names))
+		}
+
+		got := make(map[string]string)
+		for name := range want[index] {
+			got[name] = record[columns[name]]
+		}
+		if !reflect.DeepEqual(got, want[index]) {
+			t.Errorf("record %d = %v, want %v", index, got, want[index])
+		}
+	}
+
+	records = dealListCSVColumns.records(&nucleus.NucleusTradeHeaderModel{DealKey: 7})
+	if len(records) != 1 || records[0][columns["dealKey"]] != "7" || records[0][columns["terms.volSeq"]] != "" {
+		t.Errorf("records() of a deal without terms = %v, want one record with empty term columns", records)
+	}
+}
@@ -0,0 +1,34 @@
+-- SP_PROCESS_NUCLEUSTRADES_UPSERT outputs the TradeId, DealType, $action and
+-- AnomalyDetectedFlag of every row it merged. ProcessTrades reports the trades
+-- missing from that output as unchanged, and fails when there is no output.
+
+CREATE OR ALTER PROCEDURE dbo.SP_PROCESS_NUCLEUSTRADES_UPSERT
+	@TVP dbo.NucleusProcessedTradeType READONLY
+AS
+BEGIN
+	SET NOCOUNT ON;
+
+	MERGE dbo.NucleusProcessedTrade WITH (HOLDLOCK) AS pt
+	USING @TVP AS t
+		ON pt.TradeId = t.TradeId
+		AND pt.DealType = t.DealType
+	WHEN MATCHED AND (pt.PortfolioId <> t.PortfolioId
+			OR pt.TransactionDate <> t.TransactionDate
+			OR pt.TradeDetail <> t.TradeDetail
+			OR pt.AnomalyDetectedFlag <> t.AnomalyDetectedFlag
+			OR ISNULL(pt.AnomalyTestResult, N'') <> ISNULL(t.AnomalyTestResult, N'')
+			OR ISNULL(pt.ModelParameters, N'') <> ISNULL(t.ModelParameters, N'')) THEN
+		UPDATE SET PortfolioId = t.PortfolioId,
+			TransactionDate = t.TransactionDate,
+			TradeDetail = t.TradeDetail,
+			AnomalyDetectedFlag = t.AnomalyDetectedFlag,
+			AnomalyTestResult = t.AnomalyTestResult,
+			ModelParameters = t.ModelParameters
+	WHEN NOT MATCHED THEN
+		INSERT (TradeId, DealType, PortfolioId, TransactionDate, TradeDetail,
+			AnomalyDetectedFlag, AnomalyTestResult, ModelParameters)
+		VALUES (t.TradeId, t.DealType, t.PortfolioId, t.TransactionDate, t.TradeDetail,
+			t.AnomalyDetectedFlag, t.AnomalyTestResult, t.ModelParameters)
+	OUTPUT INSERTED.TradeId, INSERTED.DealType, $action, INSERTED.AnomalyDetectedFlag;
+END
+GO
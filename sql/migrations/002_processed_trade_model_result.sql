@@ -0,0 +1,33 @@
+-- NucleusProcessedTradeModelResult keeps every model result of a processed
+-- trade. ProcessTrades replaces the results of a trade in the transaction of
+-- its upsert, sending them in the NucleusProcessedTradeModelResultType TVP.
+
+CREATE TYPE dbo.NucleusProcessedTradeModelResultType AS TABLE
+(
+	TradeId         BIGINT         NOT NULL,
+	DealType        NVARCHAR(20)   NOT NULL,
+	ModelName       NVARCHAR(100)  NOT NULL,
+	ScoredLabel     NVARCHAR(20)   NOT NULL,
+	Message         NVARCHAR(MAX)  NULL,
+	ModelParameters NVARCHAR(MAX)  NOT NULL
+);
+GO
+
+CREATE TABLE dbo.NucleusProcessedTradeModelResult
+(
+	ModelResultId   BIGINT IDENTITY(1, 1) NOT NULL,
+	TradeId         BIGINT         NOT NULL,
+	DealType        NVARCHAR(20)   NOT NULL,
+	ModelName       NVARCHAR(100)  NOT NULL,
+	ScoredLabel     NVARCHAR(20)   NOT NULL,
+	Message         NVARCHAR(MAX)  NULL,
+	ModelParameters NVARCHAR(MAX)  NOT NULL,
+	CreatedAt       DATETIME2      NOT NULL CONSTRAINT DF_NucleusProcessedTradeModelResult_CreatedAt DEFAULT SYSUTCDATETIME(),
+	CONSTRAINT PK_NucleusProcessedTradeModelResult PRIMARY KEY (ModelResultId)
+);
+GO
+
+-- the results are deleted and read by trade, and filtered by model name
+CREATE INDEX IX_NucleusProcessedTradeModelResult_Trade
+	ON dbo.NucleusProcessedTradeModelResult (TradeId, DealType, ModelName);
+GO
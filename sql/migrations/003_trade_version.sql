@@ -0,0 +1,14 @@
+-- NucleusTradeVersion keeps every TradeDetail a trade was processed with.
+-- ProcessTrades adds a version, numbered from 1, whenever the detail of a
+-- trade differs from its latest version.
+
+CREATE TABLE dbo.NucleusTradeVersion
+(
+	TradeId       BIGINT         NOT NULL,
+	DealType      NVARCHAR(20)   NOT NULL,
+	VersionNumber INT            NOT NULL,
+	TradeDetail   NVARCHAR(MAX)  NOT NULL,
+	CreatedAt     DATETIME2      NOT NULL CONSTRAINT DF_NucleusTradeVersion_CreatedAt DEFAULT SYSUTCDATETIME(),
+	CONSTRAINT PK_NucleusTradeVersion PRIMARY KEY (TradeId, DealType, VersionNumber)
+);
+GO
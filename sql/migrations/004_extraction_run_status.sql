@@ -0,0 +1,23 @@
+-- NucleusTradeExtractionRun records the lifecycle of an extraction run. A run
+-- is inserted as started and completed as succeeded or failed, only the
+-- succeeded runs advance the watermark. The runs recorded before these
+-- columns existed were all successful, hence the default of Status.
+
+ALTER TABLE dbo.NucleusTradeExtractionRun
+	ADD Status         NVARCHAR(20)   NOT NULL CONSTRAINT DF_NucleusTradeExtractionRun_Status DEFAULT 'succeeded',
+		ExtractedCount INT            NULL,
+		UpsertedCount  INT            NULL,
+		FailedCount    INT            NULL,
+		DurationMs     BIGINT         NULL,
+		ErrorText      NVARCHAR(MAX)  NULL,
+		FinishedAt     DATETIME2      NULL;
+GO
+
+ALTER TABLE dbo.NucleusTradeExtractionRun
+	ADD CONSTRAINT CK_NucleusTradeExtractionRun_Status CHECK (Status IN ('started', 'succeeded', 'failed'));
+GO
+
+-- the watermark is the latest succeeded run of a trade date and deal type
+CREATE INDEX IX_NucleusTradeExtractionRun_Status
+	ON dbo.NucleusTradeExtractionRun (TransactionDate, DealType, Status, ExtractionRunId);
+GO
@@ -0,0 +1,14 @@
+-- NucleusTradeExtractionLease holds the owner allowed to extract a trade date
+-- and deal type until ExpiresAt. The owner renews it while extracting and
+-- deletes it once done, an expired lease can be taken by another owner.
+
+CREATE TABLE dbo.NucleusTradeExtractionLease
+(
+	TransactionDate DATE           NOT NULL,
+	DealType        NVARCHAR(20)   NOT NULL,
+	OwnerId         NVARCHAR(200)  NOT NULL,
+	AcquiredAt      DATETIME2      NOT NULL,
+	ExpiresAt       DATETIME2      NOT NULL,
+	CONSTRAINT PK_NucleusTradeExtractionLease PRIMARY KEY (TransactionDate, DealType)
+);
+GO